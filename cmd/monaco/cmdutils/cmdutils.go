@@ -28,3 +28,9 @@ func SilenceUsageCommand() func(cmd *cobra.Command, args []string) {
 		cmd.SilenceUsage = true
 	}
 }
+
+// AddVariablesFlag adds the '--var' flag to the command, which overrides the values of variables defined in the manifest
+func AddVariablesFlag(cmd *cobra.Command, variables *map[string]string) {
+	cmd.Flags().StringToStringVar(variables, "var", nil, "Override the value of a variable defined in the manifest, e.g. '--var tenant=abc123'. "+
+		"To set multiple variables either repeat this flag, or separate them using a comma (,).")
+}
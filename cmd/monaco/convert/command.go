@@ -22,52 +22,79 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/converter"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 	"os"
 	"path"
+	"slices"
 )
 
 func GetConvertCommand(fs afero.Fs) (convertCmd *cobra.Command) {
 
-	var outputFolder, manifestName string
+	var opts convertOptions
 
 	convertCmd = &cobra.Command{
-		Use:               "convert <environment.yaml> <config folder to convert>",
-		Short:             "Convert v1 monaco configuration into v2 format",
-		Example:           "monaco convert environment.yaml my-v1-project -o my-v2-project",
-		Args:              cobra.ExactArgs(2),
+		Use:   "convert <environment.yaml> <config folder to convert>",
+		Short: "Convert v1 monaco configuration into v2 format, or classic configurations into Settings 2.0",
+		Example: `monaco convert environment.yaml my-v1-project -o my-v2-project
+monaco convert --to-settings manifest.yaml -o my-settings-project`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.toSettings {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
 		ValidArgsFunction: completion.ConvertCompletion,
 		PreRun:            cmdutils.SilenceUsageCommand(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			environmentsFile := args[0]
-			workingDir := args[1]
 
 			if !files.IsYamlFileExtension(environmentsFile) {
-				err := fmt.Errorf("wrong format for environment file! expected a .yaml file, but got %s", environmentsFile)
+				fileKind := "environment"
+				if opts.toSettings {
+					fileKind = "manifest"
+				}
+				err := fmt.Errorf("wrong format for %s file! expected a .yaml file, but got %s", fileKind, environmentsFile)
 				return err
 			}
 
-			if !files.IsYamlFileExtension(manifestName) {
-				manifestName += ".yaml"
+			if !files.IsYamlFileExtension(opts.manifestName) {
+				opts.manifestName += ".yaml"
+			}
+
+			if !slices.Contains(converter.ReportFormats, opts.reportFormat) {
+				return fmt.Errorf("invalid '--report-format' %q, must be one of %q", opts.reportFormat, converter.ReportFormats)
 			}
 
-			if outputFolder == "" {
+			if !slices.Contains(converter.SecretProviders, opts.secretProvider) {
+				return fmt.Errorf("invalid '--secret-provider' %q, must be one of %q", opts.secretProvider, converter.SecretProviders)
+			}
+
+			if opts.outputFolder == "" {
 				folder, err := os.Getwd()
 				if err != nil {
 					return err
 				}
 
-				outputFolder = path.Base(folder) + "-v2"
+				opts.outputFolder = path.Base(folder) + "-v2"
 			}
 
-			return convert(fs, workingDir, environmentsFile, outputFolder, manifestName)
+			if opts.toSettings {
+				return convertToSettings(fs, args[0], opts)
+			}
+			return convert(fs, args[1], environmentsFile, opts)
 		},
 	}
 
-	convertCmd.Flags().StringVarP(&manifestName, "manifest", "m", "manifest.yaml", "Name of the manifest file to create")
-	convertCmd.Flags().StringVarP(&outputFolder, "output-folder", "o", "", "Folder where to write converted config to")
+	convertCmd.Flags().StringVarP(&opts.manifestName, "manifest", "m", "manifest.yaml", "Name of the manifest file to create")
+	convertCmd.Flags().StringVarP(&opts.outputFolder, "output-folder", "o", "", "Folder where to write converted config to")
+	convertCmd.Flags().StringVar((*string)(&opts.reportFormat), "report-format", string(converter.ReportFormatMarkdown), fmt.Sprintf("Format of the conversion report written next to the output folder. One of %q.", converter.ReportFormats))
+	convertCmd.Flags().StringVar((*string)(&opts.secretProvider), "secret-provider", string(converter.SecretProviderEnvironment), fmt.Sprintf("How the converted manifest references access tokens - from environment variables, or from files provided by a secret store. One of %q.", converter.SecretProviders))
+	convertCmd.Flags().StringVar(&opts.secretFolder, "secret-folder", "/run/secrets", "Folder containing one token file per 'env-token-name' when using '--secret-provider file'")
+	convertCmd.Flags().BoolVar(&opts.toSettings, "to-settings", false, fmt.Sprintf("Convert the classic configurations of the projects in the given manifest to their Settings 2.0 equivalents. Supported classic APIs: %q", supportedSettingsApis()))
 	err := convertCmd.MarkFlagDirname("output-folder")
 	if err != nil {
 		log.Fatal("failed to setup CLI %v", err)
@@ -78,3 +105,10 @@ func GetConvertCommand(fs afero.Fs) (convertCmd *cobra.Command) {
 	}
 	return convertCmd
 }
+
+// supportedSettingsApis returns the sorted classic APIs that can be converted to settings.
+func supportedSettingsApis() []string {
+	apis := maps.Keys(converter.SupportedSettingsConversions())
+	slices.Sort(apis)
+	return apis
+}
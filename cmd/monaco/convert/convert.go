@@ -34,12 +34,26 @@ import (
 	"github.com/spf13/afero"
 )
 
-func convert(fs afero.Fs, workingDir string, environmentsFile string, outputFolder string,
-	manifestName string) error {
+type convertOptions struct {
+	outputFolder string
+	manifestName string
+	// reportFormat is the format of the conversion report written next to the output folder
+	reportFormat converter.ReportFormat
+	// secretProvider defines how access tokens are referenced in the converted manifest
+	secretProvider converter.SecretProvider
+	// secretFolder is the folder token files are read from when using the file secret provider
+	secretFolder string
+	// toSettings converts the classic configs of a v2 manifest's projects to settings, instead of converting v1 projects
+	toSettings bool
+}
+
+func convert(fs afero.Fs, workingDir string, environmentsFile string, opts convertOptions) error {
 	apis := api.NewV1APIs()
+	outputFolder, manifestName := opts.outputFolder, opts.manifestName
 
 	log.Info("Converting configurations from '%s' ...", workingDir)
-	man, projs, configLoadErrors := convertConfigs(fs, workingDir, apis, environmentsFile)
+	report := &converter.Report{}
+	man, projs, configLoadErrors := convertConfigs(fs, workingDir, apis, environmentsFile, opts, report)
 
 	if len(configLoadErrors) > 0 {
 		errutils.PrintErrors(configLoadErrors)
@@ -76,12 +90,22 @@ func convert(fs afero.Fs, workingDir string, environmentsFile string, outputFold
 		return fmt.Errorf("failed to copy delete.yaml from %s to %s: %w", workingDir, outputFolder, err)
 	}
 
+	reportFile := reportFilePath(outputFolder, opts.reportFormat)
+	if err := report.Write(fs, reportFile, opts.reportFormat); err != nil {
+		return err
+	}
+	log.Info("Conversion report written to '%s'", reportFile)
+
+	if len(report.ManualSteps) > 0 {
+		log.Warn("%d conversion findings need manual attention, please review them in the conversion report", len(report.ManualSteps))
+	}
+
 	log.Info("Successfully converted configurations to v2 format, stored in '%s'", outputFolder)
 	return nil
 }
 
 func convertConfigs(fs afero.Fs, workingDir string, apis api.APIs,
-	environmentsFile string) (manifest.Manifest, []projectv2.Project, []error) {
+	environmentsFile string, opts convertOptions, report *converter.Report) (manifest.Manifest, []projectv2.Project, []error) {
 
 	environments, errors := v1environment.LoadEnvironmentsWithoutTemplating(environmentsFile, fs)
 
@@ -100,7 +124,7 @@ func convertConfigs(fs afero.Fs, workingDir string, apis api.APIs,
 
 	projects, err := projectv1.LoadProjectsToConvert(workingDirFs, apis, ".")
 
-	projects = removeEmptyProjects(projects)
+	projects = removeEmptyProjects(projects, report)
 
 	if err != nil {
 		return manifest.Manifest{}, nil, []error{err}
@@ -109,10 +133,13 @@ func convertConfigs(fs afero.Fs, workingDir string, apis api.APIs,
 	return converter.Convert(converter.ConverterContext{
 		Fs:             workingDirFs,
 		UnescapeValues: featureflags.UnescapeOnConvert().Enabled(),
+		SecretProvider: opts.secretProvider,
+		SecretFolder:   opts.secretFolder,
+		Report:         report,
 	}, environments, projects)
 }
 
-func removeEmptyProjects(projects []projectv1.Project) []projectv1.Project {
+func removeEmptyProjects(projects []projectv1.Project, report *converter.Report) []projectv1.Project {
 	filteredProjects := make([]projectv1.Project, 0, len(projects))
 
 	for _, project := range projects {
@@ -121,6 +148,7 @@ func removeEmptyProjects(projects []projectv1.Project) []projectv1.Project {
 
 		if numberConfigs == 0 {
 			log.Debug("Skipping project '%v' as it contains no configs.", project.GetId())
+			report.SkippedProjects = append(report.SkippedProjects, project.GetId())
 		} else {
 			filteredProjects = append(filteredProjects, project)
 		}
@@ -129,6 +157,11 @@ func removeEmptyProjects(projects []projectv1.Project) []projectv1.Project {
 	return filteredProjects
 }
 
+// reportFilePath returns the path of the conversion report, which is written next to the output folder
+func reportFilePath(outputFolder string, format converter.ReportFormat) string {
+	return filepath.Clean(outputFolder) + "-conversion-report" + format.FileExtension()
+}
+
 func copyDeleteFileIfPresent(fs afero.Fs, workingDir, outputFolder string) error {
 
 	currentDeleteFile := path.Join(workingDir, "delete.yaml")
@@ -25,10 +25,12 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/converter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/loader"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io/fs"
 	"os"
 	"strings"
@@ -42,7 +44,7 @@ func TestConvert_WorksOnFullConfiguration(t *testing.T) {
 	_ = afero.WriteFile(testFs, "environments.yaml", []byte("env:\n  - name: \"My_Environment\"\n  - env-url: \"{{ .Env.ENV_URL }}\"\n  - env-token-name: \"ENV_TOKEN\""), 0644)
 	_ = afero.WriteFile(testFs, "delete.yaml", []byte("delete:\n-\"some/config\""), 0644)
 
-	err := convert(testFs, ".", "environments.yaml", "converted", "manifest.yaml")
+	err := convert(testFs, ".", "environments.yaml", convertOptions{outputFolder: "converted", manifestName: "manifest.yaml", reportFormat: converter.ReportFormatJSON})
 	assert.NoError(t, err)
 
 	outputFolderExists, _ := afero.Exists(testFs, "converted/")
@@ -61,7 +63,7 @@ func TestConvert_WorksIfNoDeleteYamlExists(t *testing.T) {
 	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
 	_ = afero.WriteFile(testFs, "environments.yaml", []byte("env:\n  - name: \"My_Environment\"\n  - env-url: \"{{ .Env.ENV_URL }}\"\n  - env-token-name: \"ENV_TOKEN\""), 0644)
 
-	err := convert(testFs, ".", "environments.yaml", "converted", "manifest.yaml")
+	err := convert(testFs, ".", "environments.yaml", convertOptions{outputFolder: "converted", manifestName: "manifest.yaml", reportFormat: converter.ReportFormatJSON})
 	assert.NoError(t, err)
 
 	outputFolderExists, _ := afero.Exists(testFs, "converted/")
@@ -72,12 +74,201 @@ func TestConvert_WorksIfNoDeleteYamlExists(t *testing.T) {
 	assertExpectedManifestCreated(t, testFs)
 }
 
+func TestConvert_WritesConversionReportNextToOutputFolder(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte("config:\n  - profile: \"profile.json\"\n\nprofile:\n  - threshold: \"5\""), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
+	_ = testFs.MkdirAll("empty-project/", 0755)
+	_ = afero.WriteFile(testFs, "environments.yaml", []byte("env:\n  - name: \"My_Environment\"\n  - env-url: \"{{ .Env.ENV_URL }}\"\n  - env-token-name: \"ENV_TOKEN\""), 0644)
+
+	err := convert(testFs, ".", "environments.yaml", convertOptions{outputFolder: "out/converted", manifestName: "manifest.yaml", reportFormat: converter.ReportFormatJSON})
+	assert.NoError(t, err)
+
+	content, err := afero.ReadFile(testFs, "out/converted-conversion-report.json")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "configs": [
+    {
+      "coordinate": {"project": "project", "type": "alerting-profile", "configId": "profile"},
+      "sourceTemplate": "project/alerting-profile/profile.json"
+    }
+  ],
+  "deprecatedApis": null,
+  "skippedProjects": ["empty-project"],
+  "manualSteps": [
+    {
+      "coordinate": {"project": "project", "type": "alerting-profile", "configId": "profile"},
+      "parameter": "name",
+      "message": "name is missing, using generated name \"profile - monaco-conversion created name\""
+    }
+  ]
+}`, string(content))
+}
+
+func TestConvert_WritesFileSecretsToManifest(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte("config:\n  - profile: \"profile.json\"\n\nprofile:\n  - name: \"Star Trek Service\""), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "environments.yaml", []byte("env:\n  - name: \"My_Environment\"\n  - env-url: \"{{ .Env.ENV_URL }}\"\n  - env-token-name: \"ENV_TOKEN\""), 0644)
+
+	err := convert(testFs, ".", "environments.yaml", convertOptions{
+		outputFolder:   "converted",
+		manifestName:   "manifest.yaml",
+		reportFormat:   converter.ReportFormatJSON,
+		secretProvider: converter.SecretProviderFile,
+		secretFolder:   "/mnt/secrets-store",
+	})
+	assert.NoError(t, err)
+
+	manifestContent, err := afero.ReadFile(testFs, "converted/manifest.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, `manifestVersion: "1.1"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      type: environment
+      value: ENV_URL
+    auth:
+      token:
+        type: file
+        name: ENV_TOKEN
+        path: /mnt/secrets-store/ENV_TOKEN
+`, string(manifestContent))
+}
+
+func TestConvertToSettings_ConvertsSupportedClassicConfigs(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "in/manifest.yaml", []byte(`manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://example.com
+    auth:
+      token:
+        name: ENV_TOKEN
+`), 0644)
+	_ = afero.WriteFile(testFs, "in/project/tags/config.yaml", []byte(`configs:
+- id: tag
+  type:
+    api: auto-tag
+  config:
+    name: my tag
+    template: tag.json
+- id: dashboard
+  type:
+    api: dashboard
+  config:
+    name: my dashboard
+    template: dashboard.json
+    parameters:
+      tag:
+        type: reference
+        configType: auto-tag
+        configId: tag
+        property: name
+`), 0644)
+	_ = afero.WriteFile(testFs, "in/project/tags/tag.json", []byte(`{"name": "{{ .name }}", "entitySelectorBasedRules": [{"enabled": true, "entitySelector": "type(HOST)"}]}`), 0644)
+	_ = afero.WriteFile(testFs, "in/project/tags/dashboard.json", []byte(`{"dashboardMetadata": {"name": "{{ .name }}", "filter": "{{ .tag }}"}}`), 0644)
+
+	err := convertToSettings(testFs, "in/manifest.yaml", convertOptions{
+		outputFolder: "out",
+		manifestName: "manifest.yaml",
+		reportFormat: converter.ReportFormatMarkdown,
+	})
+	require.NoError(t, err)
+
+	template, err := afero.ReadFile(testFs, "out/project/builtintags.auto-tagging/tag.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "name": "{{ .name }}",
+  "rules": [
+    {
+      "enabled": true,
+      "entitySelector": "type(HOST)",
+      "type": "SELECTOR"
+    }
+  ]
+}
+`, string(template))
+
+	dashboard, err := afero.ReadFile(testFs, "out/project/tags/dashboard.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(dashboard), `"filter": "{{ .tag }}"`)
+
+	dashboardConfig, err := afero.ReadFile(testFs, "out/project/dashboard/config.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(dashboardConfig), "configType: builtin:tags.auto-tagging")
+
+	exists, err := afero.Exists(testFs, "out-conversion-report.md")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestConvert_KeepsCommentsAndAnchors(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte(`config:
+  # the profile of all services
+  - profile: "profile.json"
+  - other: "profile.json"
+
+profile:
+  # shown in the UI
+  - name: &profileName "Star Trek Service"
+
+other:
+  - name: *profileName
+  - skipDeployment: "true" # not used anymore
+`), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte(`{"name": "{{ .name }}"}`), 0644)
+	_ = afero.WriteFile(testFs, "environments.yaml", []byte("env:\n  - name: \"My_Environment\"\n  - env-url: \"{{ .Env.ENV_URL }}\"\n  - env-token-name: \"ENV_TOKEN\""), 0644)
+
+	err := convert(testFs, ".", "environments.yaml", convertOptions{
+		outputFolder:   "converted",
+		manifestName:   "manifest.yaml",
+		reportFormat:   converter.ReportFormatMarkdown,
+		secretProvider: converter.SecretProviderEnvironment,
+	})
+	require.NoError(t, err)
+
+	content, err := afero.ReadFile(testFs, "converted/project/alerting-profile/config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `configs:
+  - id: other
+    config:
+      name: &profileName Star Trek Service
+      template: profile.json
+      # not used anymore
+      skip:
+        type: value
+        value: true
+    type:
+      api: alerting-profile
+  # the profile of all services
+  - id: profile
+    config:
+      # shown in the UI
+      name: *profileName
+      template: profile.json
+      skip: false
+    type:
+      api: alerting-profile
+`, string(content))
+}
+
 func TestConvert_FailsIfThereIsJustEmptyProjects(t *testing.T) {
 	testFs := afero.NewMemMapFs()
 	_ = testFs.MkdirAll("project/", 0755)
 	_ = afero.WriteFile(testFs, "environments.yaml", []byte("env:\n  - name: \"My_Environment\"\n  - env-url: \"{{ .Env.ENV_URL }}\"\n  - env-token-name: \"ENV_TOKEN\""), 0644)
 
-	err := convert(testFs, ".", "environments.yaml", "converted", "manifest.yaml")
+	err := convert(testFs, ".", "environments.yaml", convertOptions{outputFolder: "converted", manifestName: "manifest.yaml", reportFormat: converter.ReportFormatJSON})
 	assert.ErrorContains(t, err, "no projects to convert")
 }
 
@@ -105,7 +296,7 @@ profile:
 
 	t.Setenv(featureflags.UnescapeOnConvert().EnvName(), "true") // ensure unescape feature is ON for this test
 
-	err := convert(testFs, ".", "environments.yaml", "converted", "manifest.yaml")
+	err := convert(testFs, ".", "environments.yaml", convertOptions{outputFolder: "converted", manifestName: "manifest.yaml", reportFormat: converter.ReportFormatJSON})
 	assert.NoError(t, err)
 
 	outputFolderExists, _ := afero.Exists(testFs, "converted/")
@@ -172,7 +363,7 @@ profile:
 
 	t.Setenv(featureflags.UnescapeOnConvert().EnvName(), "false") // ensure unescape feature is OFF for this test
 
-	err := convert(testFs, ".", "environments.yaml", "converted", "manifest.yaml")
+	err := convert(testFs, ".", "environments.yaml", convertOptions{outputFolder: "converted", manifestName: "manifest.yaml", reportFormat: converter.ReportFormatJSON})
 	assert.NoError(t, err)
 
 	outputFolderExists, _ := afero.Exists(testFs, "converted/")
@@ -312,7 +503,7 @@ func assertExpectedConfigurationCreated(t *testing.T, testFs afero.Fs) {
 
 func assertExpectedManifestCreated(t *testing.T, testFs afero.Fs) {
 	expectedManifest := fmt.Sprintf(
-		`manifestVersion: "1.0"
+		`manifestVersion: "1.1"
 projects:
 - name: project
 environmentGroups:
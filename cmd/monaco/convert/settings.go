@@ -0,0 +1,85 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/converter"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/writer"
+	"github.com/spf13/afero"
+	"path/filepath"
+)
+
+// convertToSettings converts the classic configs of all projects of the manifest to their Settings 2.0 equivalents
+// and writes the manifest and all projects to the output folder.
+func convertToSettings(fs afero.Fs, manifestPath string, opts convertOptions) error {
+	m, errs := manifestloader.Load(&manifestloader.Context{
+		Fs:           fs,
+		ManifestPath: manifestPath,
+		Opts: manifestloader.Options{
+			DoNotResolveEnvVars:      true,
+			RequireEnvironmentGroups: true,
+		},
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to load manifest %q", manifestPath)
+	}
+
+	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
+		KnownApis:       api.NewAPIs().GetApiNameLookup(),
+		WorkingDir:      filepath.Dir(manifestPath),
+		Manifest:        m,
+		ParametersSerde: config.DefaultParameterParsers,
+	}, nil)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to load projects of manifest %q", manifestPath)
+	}
+
+	report := &converter.Report{}
+	converted := converter.ConvertToSettings(converter.ConverterContext{Report: report}, projects)
+
+	errs = writer.WriteToDisk(&writer.WriterContext{
+		Fs:              fs,
+		OutputDir:       opts.outputFolder,
+		ManifestName:    opts.manifestName,
+		ParametersSerde: config.DefaultParameterParsers,
+	}, m, converted)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("encountered %d errors while writing converted configurations", len(errs))
+	}
+
+	reportFile := reportFilePath(opts.outputFolder, opts.reportFormat)
+	if err := report.Write(fs, reportFile, opts.reportFormat); err != nil {
+		return err
+	}
+	log.Info("Conversion report written to '%s'", reportFile)
+
+	log.Info("Successfully converted %d configurations to settings, stored in '%s'", len(report.Configs), opts.outputFolder)
+	if len(report.ManualSteps) > 0 {
+		log.Warn("%d conversion findings need manual attention, please review them in the conversion report", len(report.ManualSteps))
+	}
+	return nil
+}
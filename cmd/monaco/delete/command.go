@@ -23,11 +23,15 @@ import (
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
 	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
@@ -36,6 +40,8 @@ func GetDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
 	var environments, groups []string
 	var manifestName string
 	var deleteFile string
+	var archiveFolder string
+	var variables map[string]string
 
 	deleteCmd = &cobra.Command{
 		Use:     "delete --manifest <manifest.yaml> --file <delete.yaml>",
@@ -67,7 +73,10 @@ func GetDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
 				ManifestPath: absManifestFilePath,
 				Environments: environments,
 				Groups:       groups,
+				Variables:    variables,
 				Opts:         manifestloader.Options{RequireEnvironmentGroups: true},
+
+				EnvironmentDiscoverer: dynatrace.AccountEnvironmentDiscoverer{},
 			})
 			if len(errs) > 0 {
 				errutils.PrintErrors(errs)
@@ -80,13 +89,32 @@ func GetDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
 				return fmt.Errorf("encountered errors while parsing %s: %w", deleteFile, err)
 			}
 
-			return Delete(manifest.Environments, entriesToDelete)
+			// Projects are only needed to order deletion by the references between configurations - if they can't be
+			// loaded, configurations are still deleted, just without considering their dependencies.
+			var projects []project.Project
+			if len(manifest.Projects) > 0 {
+				apis := api.NewAPIs().Filter(api.RemoveDisabled)
+				projects, errs = project.LoadProjects(fs, project.ProjectLoaderContext{
+					KnownApis:       apis.GetApiNameLookup(),
+					WorkingDir:      filepath.Dir(absManifestFilePath),
+					Manifest:        manifest,
+					ParametersSerde: config.DefaultParameterParsers,
+				}, nil)
+				if len(errs) > 0 {
+					log.Warn("Failed to load projects of manifest %q - configurations will be deleted without considering references between them: %v", manifestName, errors.Join(errs...))
+					projects = nil
+				}
+			}
+
+			return Delete(fs, manifest.Environments, entriesToDelete, projects, archiveFolder)
 		},
 		ValidArgsFunction: completion.DeleteCompletion,
 	}
 
 	deleteCmd.Flags().StringVarP(&manifestName, "manifest", "m", "manifest.yaml", "The manifest defining the environments to delete from. (default: 'manifest.yaml' in the current folder)")
 	deleteCmd.Flags().StringVar(&deleteFile, "file", "delete.yaml", "The delete file defining which configurations to remove. (default: 'delete.yaml' in the current folder)")
+	cmdutils.AddVariablesFlag(deleteCmd, &variables)
+	deleteCmd.Flags().StringVar(&archiveFolder, "archive-folder", "", "Folder to archive configurations to before they are deleted. Archived configurations are written as a monaco project into a timestamped sub-folder per environment and can be restored by deploying it")
 
 	deleteCmd.Flags().StringSliceVarP(&groups, "group", "g", []string{},
 		"Specify one (or multiple) environmentGroup(s) that should be used for deletion. "+
@@ -19,10 +19,16 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/archive"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/spf13/afero"
+	"golang.org/x/exp/maps"
 	"strings"
 )
 
@@ -31,19 +37,30 @@ import (
 // Parameters:
 //   - environments: A list of Dynatrace environments to perform the deletion on.
 //   - entriesToDelete: Deletion entries specifying what configurations to remove.
+//   - fs: The file system configurations are archived to.
+//   - projects: The projects the configurations were deployed from. References between their configurations define
+//     the order of deletion - configurations are deleted before the ones they reference. May be empty.
+//   - archiveFolder: The folder configurations are archived to before they are deleted. If empty, nothing is archived.
 //
 // Returns:
 //   - error: If an error occurs during the deletion process, an error is returned, describing the issue.
 //     If no errors occur, nil is returned.
-func Delete(environments manifest.Environments, entriesToDelete delete.DeleteEntries) error {
+func Delete(fs afero.Fs, environments manifest.Environments, entriesToDelete delete.DeleteEntries, projects []project.Project, archiveFolder string) error {
+	graphs := graph.New(projects, maps.Keys(environments))
+
 	var envsWithDeleteErrs []string
 	for _, env := range environments {
 		ctx := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
 		if containsPlatformTypes(entriesToDelete) && env.Auth.OAuth == nil {
 			log.WithCtxFields(ctx).Warn("Delete file contains Dynatrace Platform specific types, but no oAuth credentials are defined for environment %q - Dynatrace Platform configurations won't be deleted.", env.Name)
 		}
+		envEntries := entriesToDelete
+		if containsClassicTypes(entriesToDelete) && !env.Auth.HasToken() {
+			log.WithCtxFields(ctx).Warn("Delete file contains classic Config API types, but no access token is defined for environment %q - configurations of classic Config APIs won't be deleted.", env.Name)
+			envEntries = withoutClassicTypes(entriesToDelete)
+		}
 
-		clientSet, err := dynatrace.CreateClients(env.URL.Value, env.Auth)
+		clientSet, err := dynatrace.CreateClientsForEnvironment(env)
 		if err != nil {
 			return fmt.Errorf("failed to create API client for environment %q due to the following error: %w", env.Name, err)
 		}
@@ -64,7 +81,21 @@ func Delete(environments manifest.Environments, entriesToDelete delete.DeleteEnt
 			Buckets:    clientSet.Bucket(),
 		}
 
-		if err := delete.Configs(ctx, deleteClients, classicAPIs, automationAPIs, entriesToDelete); err != nil {
+		var dependencies delete.TypeDependencies
+		if g, ok := graphs[env.Name]; ok {
+			dependencies = delete.DependenciesOf(g)
+		}
+
+		if archiveFolder != "" {
+			if _, err := archive.Archive(ctx, fs, archiveFolder, env, deleteClients, classicAPIs, maps.Keys(envEntries), archive.EntriesSelector(envEntries)); err != nil {
+				log.WithCtxFields(ctx).WithFields(field.Error(err)).Error("Failed to archive configurations of environment %q - nothing was deleted: %v", env.Name, err)
+				envsWithDeleteErrs = append(envsWithDeleteErrs, env.Name)
+				continue
+			}
+		}
+
+		// deletion consumes the entries, each environment needs its own copy
+		if err := delete.ConfigsInOrder(ctx, deleteClients, classicAPIs, automationAPIs, maps.Clone(envEntries), dependencies); err != nil {
 			log.Error("Failed to delete all configurations from environment %q - check log for details", env.Name)
 			envsWithDeleteErrs = append(envsWithDeleteErrs, env.Name)
 		}
@@ -76,6 +107,28 @@ func Delete(environments manifest.Environments, entriesToDelete delete.DeleteEnt
 	return nil
 }
 
+func containsClassicTypes(entriesToDelete delete.DeleteEntries) bool {
+	apis := api.NewAPIs()
+	for t := range entriesToDelete {
+		if _, contains := apis[t]; contains {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutClassicTypes returns the entries without the ones of classic Config APIs
+func withoutClassicTypes(entriesToDelete delete.DeleteEntries) delete.DeleteEntries {
+	apis := api.NewAPIs()
+	result := make(delete.DeleteEntries, len(entriesToDelete))
+	for t, entries := range entriesToDelete {
+		if _, classic := apis[t]; !classic {
+			result[t] = entries
+		}
+	}
+	return result
+}
+
 func containsPlatformTypes(entriesToDelete delete.DeleteEntries) bool {
 	for _, t := range []string{string(config.Workflow), string(config.SchedulingRule), string(config.BusinessCalendar), "bucket"} {
 		if _, contains := entriesToDelete[t]; contains {
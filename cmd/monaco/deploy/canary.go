@@ -0,0 +1,145 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy/internal/hooks"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"golang.org/x/exp/maps"
+)
+
+// canaryOptions configures a canary deployment, which deploys to the environments of one group first and only
+// continues with the remaining groups once that deployment succeeded and was verified
+type canaryOptions struct {
+	// group is the environment group deployed to first. Without a group, all environments are deployed to at once.
+	group string
+	// wait is the time to wait after the deployment to the canary group before it is verified
+	wait time.Duration
+	// verifyCommand is an optional command that must succeed before the remaining groups are deployed to
+	verifyCommand string
+}
+
+// deploymentStage is a set of environments that are deployed to together
+type deploymentStage struct {
+	// group of all environments of the stage. It is empty if the stage contains all environments.
+	group   string
+	clients dynatrace.EnvironmentClients
+}
+
+// planStages splits the environments to deploy to into stages. Without a canary group, all environments are deployed
+// to in a single stage. Otherwise, the environments of the canary group are deployed to first, followed by the
+// environments of every other group, ordered by group name.
+func planStages(clients dynatrace.EnvironmentClients, canaryGroup string) ([]deploymentStage, error) {
+	if canaryGroup == "" {
+		return []deploymentStage{{clients: clients}}, nil
+	}
+
+	clientsByGroup := make(map[string]dynatrace.EnvironmentClients)
+	for env, c := range clients {
+		if clientsByGroup[env.Group] == nil {
+			clientsByGroup[env.Group] = make(dynatrace.EnvironmentClients)
+		}
+		clientsByGroup[env.Group][env] = c
+	}
+
+	canaryClients, found := clientsByGroup[canaryGroup]
+	if !found {
+		return nil, fmt.Errorf("canary group %q does not contain any of the environments to deploy to", canaryGroup)
+	}
+	delete(clientsByGroup, canaryGroup)
+
+	stages := []deploymentStage{{group: canaryGroup, clients: canaryClients}}
+	groups := maps.Keys(clientsByGroup)
+	slices.Sort(groups)
+	for _, g := range groups {
+		stages = append(stages, deploymentStage{group: g, clients: clientsByGroup[g]})
+	}
+	return stages, nil
+}
+
+// deployStages deploys to the given stages one after the other. If the deployment of a stage fails, the remaining
+// stages are not deployed to. If verify is set, the first stage is verified according to the canaryOptions before
+// any further stage is deployed to. All stages share their results, so that configs can reference configs deployed to
+// environments of earlier stages.
+func deployStages(ctx context.Context, projects []project.Project, stages []deploymentStage, opts deploy.DeployConfigsOptions, canary canaryOptions, verify bool) error {
+	if opts.Results == nil {
+		opts.Results = deploy.NewResults()
+	}
+	for i, stage := range stages {
+		remaining := stages[i+1:]
+		if len(stages) > 1 {
+			log.Info("Deploying to environment group %q (stage %d of %d)...", stage.group, i+1, len(stages))
+		}
+
+		if err := deploy.Deploy(ctx, projects, stage.clients, opts); err != nil {
+			if len(remaining) > 0 {
+				log.Error("Deployment to environment group %q failed, environment groups %q are not deployed to", stage.group, stageGroups(remaining))
+			}
+			return err
+		}
+
+		if i == 0 && len(remaining) > 0 && verify {
+			if err := verifyCanary(ctx, canary, stage); err != nil {
+				log.Error("Verification of canary group %q failed, environment groups %q are not deployed to", stage.group, stageGroups(remaining))
+				return fmt.Errorf("verification of canary group %q failed: %w", stage.group, err)
+			}
+			log.Info("Verification of canary group %q succeeded", stage.group)
+		}
+	}
+	return nil
+}
+
+// verifyCanary waits for the configured verification period and executes the verification command, if any
+func verifyCanary(ctx context.Context, canary canaryOptions, stage deploymentStage) error {
+	if canary.wait > 0 {
+		log.Info("Waiting %s before verifying canary group %q...", canary.wait, stage.group)
+		select {
+		case <-time.After(canary.wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if canary.verifyCommand == "" {
+		return nil
+	}
+
+	environments := stage.clients.Names()
+	slices.Sort(environments)
+	log.Info("Executing canary verification command %q", canary.verifyCommand)
+	return hooks.RunCommand(ctx, canary.verifyCommand, []string{
+		"MONACO_CANARY_GROUP=" + stage.group,
+		"MONACO_ENVIRONMENTS=" + strings.Join(environments, ","),
+	})
+}
+
+func stageGroups(stages []deploymentStage) []string {
+	groups := make([]string, len(stages))
+	for i, s := range stages {
+		groups[i] = s.group
+	}
+	return groups
+}
@@ -0,0 +1,161 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/crossenvironment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	canaryEnv = dynatrace.EnvironmentInfo{Name: "canary-env", Group: "canary"}
+	devEnv    = dynatrace.EnvironmentInfo{Name: "dev-env", Group: "dev"}
+	prodEnv   = dynatrace.EnvironmentInfo{Name: "prod-env", Group: "prod"}
+)
+
+func newCanaryTestClients() dynatrace.EnvironmentClients {
+	return dynatrace.EnvironmentClients{
+		prodEnv:   &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+		canaryEnv: &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+		devEnv:    &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+	}
+}
+
+func newCanaryTestProjects() []project.Project {
+	configsPerEnv := project.ConfigsPerTypePerEnvironments{}
+	for _, env := range []dynatrace.EnvironmentInfo{canaryEnv, devEnv, prodEnv} {
+		configsPerEnv[env.Name] = project.ConfigsPerType{
+			"builtin:test": []config.Config{{
+				Type:        config.SettingsType{SchemaId: "builtin:test"},
+				Template:    template.NewInMemoryTemplate("setting", "{}"),
+				Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "setting"},
+				Environment: env.Name,
+				Parameters: config.Parameters{
+					config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+				},
+			}},
+		}
+	}
+	return []project.Project{{Id: "project", Configs: configsPerEnv}}
+}
+
+func TestPlanStages(t *testing.T) {
+	t.Run("without canary group all environments are deployed at once", func(t *testing.T) {
+		stages, err := planStages(newCanaryTestClients(), "")
+		require.NoError(t, err)
+		require.Len(t, stages, 1)
+		assert.Len(t, stages[0].clients, 3)
+	})
+
+	t.Run("canary group is deployed first, followed by the other groups ordered by name", func(t *testing.T) {
+		stages, err := planStages(newCanaryTestClients(), "prod")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod", "canary", "dev"}, stageGroups(stages))
+		assert.Equal(t, []string{"prod-env"}, stages[0].clients.Names())
+	})
+
+	t.Run("unknown canary group", func(t *testing.T) {
+		_, err := planStages(newCanaryTestClients(), "unknown")
+		assert.ErrorContains(t, err, `canary group "unknown"`)
+	})
+}
+
+func TestDeployStages(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell commands")
+	}
+
+	tests := []struct {
+		name             string
+		verifyCommand    string
+		verify           bool
+		wantErr          bool
+		wantEnvironments []string
+	}{
+		{
+			name:             "successful verification deploys all groups",
+			verifyCommand:    `test "$MONACO_CANARY_GROUP" = canary && test "$MONACO_ENVIRONMENTS" = canary-env`,
+			verify:           true,
+			wantEnvironments: []string{"canary-env", "dev-env", "prod-env"},
+		},
+		{
+			name:             "failed verification stops after the canary group",
+			verifyCommand:    "exit 1",
+			verify:           true,
+			wantErr:          true,
+			wantEnvironments: []string{"canary-env"},
+		},
+		{
+			name:             "verification is skipped if not requested",
+			verifyCommand:    "exit 1",
+			verify:           false,
+			wantEnvironments: []string{"canary-env", "dev-env", "prod-env"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := planStages(newCanaryTestClients(), "canary")
+			require.NoError(t, err)
+
+			summary := report.NewSummary()
+			err = deployStages(context.TODO(), newCanaryTestProjects(), stages, deploy.DeployConfigsOptions{Reporter: summary}, canaryOptions{group: "canary", verifyCommand: tt.verifyCommand}, tt.verify)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var environments []string
+			for _, r := range summary.Records() {
+				environments = append(environments, r.Environment)
+			}
+			assert.ElementsMatch(t, tt.wantEnvironments, environments)
+		})
+	}
+}
+
+func TestDeployStages_ResolvesReferencesToEarlierStages(t *testing.T) {
+	projects := newCanaryTestProjects()
+	prodConfig := &projects[0].Configs[prodEnv.Name]["builtin:test"][0]
+	prodConfig.Parameters["canaryId"] = crossenvironment.New(canaryEnv.Name, "project", "builtin:test", "setting", "id")
+
+	stages, err := planStages(newCanaryTestClients(), "canary")
+	require.NoError(t, err)
+
+	summary := report.NewSummary()
+	err = deployStages(context.TODO(), projects, stages, deploy.DeployConfigsOptions{Reporter: summary}, canaryOptions{group: "canary"}, false)
+	require.NoError(t, err)
+	assert.Len(t, summary.Records(), 3)
+}
@@ -17,17 +17,22 @@
 package deploy
 
 import (
+	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
-	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/lock"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/secrets"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"path"
+	"slices"
 )
 
 func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
-	var dryRun, continueOnError bool
+	var opts deployOptions
 	var manifestName string
 	var environment, project, groups []string
 
@@ -42,12 +47,58 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 
 			manifestName = args[0]
 
-			if !files.IsYamlFileExtension(manifestName) {
-				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+			if err := validateManifestName(manifestName); err != nil {
 				return err
 			}
 
-			return deployConfigs(fs, manifestName, groups, environment, project, continueOnError, dryRun)
+			for _, p := range opts.only {
+
+				if _, err := path.Match(p, ""); err != nil {
+					return fmt.Errorf("invalid '--only' pattern %q: %w", p, err)
+				}
+			}
+
+			if !slices.Contains(report.Formats, opts.reportFormat) {
+				return fmt.Errorf("invalid '--report-format' %q, must be one of %q", opts.reportFormat, report.Formats)
+			}
+
+			if !slices.Contains(secrets.Levels, opts.secretScan) {
+				return fmt.Errorf("invalid '--secret-scan' %q, must be one of %q", opts.secretScan, secrets.Levels)
+			}
+
+			if opts.parallelEnvironments < 1 {
+				return fmt.Errorf("'--parallel-environments' must be at least 1, but is %d", opts.parallelEnvironments)
+			}
+
+			if opts.canary.group == "" && (opts.canary.wait != 0 || opts.canary.verifyCommand != "") {
+				return errors.New("'--canary-wait' and '--canary-verify' can only be used together with '--canary-group'")
+			}
+
+			if opts.canary.wait < 0 {
+				return fmt.Errorf("'--canary-wait' must not be negative, but is %s", opts.canary.wait)
+			}
+
+			if opts.timeout < 0 || opts.configTimeout < 0 {
+				return errors.New("'--timeout' and '--config-timeout' must not be negative")
+			}
+
+			if !opts.lock.enabled && (opts.lock.wait != 0 || cmd.Flags().Changed("lock-ttl")) {
+				return errors.New("'--lock-wait' and '--lock-ttl' can only be used together with '--lock'")
+			}
+
+			if opts.lock.wait < 0 || opts.lock.ttl <= 0 {
+				return errors.New("'--lock-wait' must not be negative and '--lock-ttl' must be positive")
+			}
+
+			if opts.remoteValidation && !opts.dryRun {
+				return errors.New("'--remote-validation' can only be used together with '--dry-run'")
+			}
+
+			if opts.dumpParametersFile != "" && !opts.dryRun {
+				return errors.New("'--dump-parameters' can only be used together with '--dry-run'")
+			}
+
+			return deployConfigs(fs, manifestName, groups, environment, project, opts)
 		},
 	}
 
@@ -61,8 +112,57 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 			"If this flag is specified, all environments within this group will be used for deployment. "+
 			"This flag is mutually exclusive with '--environment'")
 	deployCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to deploy (also deploys any dependent configurations)")
-	deployCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Validate the structure of your manifest, projects and configurations. Dry-run will resolve all configuration parameters and render JSON templates, but can not validate the content of JSON payloads. After a successful dry-run, deployments may still fail with Dynatrace API errors if the content of JSONs is not valid.")
-	deployCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "c", false, "Proceed deployment even if individual configuration deployments fail.")
+	deployCmd.Flags().BoolVarP(&opts.dryRun, "dry-run", "d", false, "Validate the structure of your manifest, projects and configurations. Dry-run will resolve all configuration parameters and render JSON templates, but can not validate the content of JSON payloads. After a successful dry-run, deployments may still fail with Dynatrace API errors if the content of JSONs is not valid.")
+	deployCmd.Flags().BoolVarP(&opts.continueOnError, "continue-on-error", "c", false, "Proceed deployment even if individual configuration deployments fail.")
+	deployCmd.Flags().BoolVar(&opts.remoteValidation, "remote-validation", false, "In combination with '--dry-run', validate the rendered payloads of classic configs and Settings 2.0 objects against the validation endpoints of the target environments. No configuration is created or updated.")
+	deployCmd.Flags().StringVar(&opts.dumpParametersFile, "dump-parameters", "", "In combination with '--dry-run', write the resolved parameter values of every configuration to the given JSON file, grouped by environment and configuration. "+
+		"Values of secret parameters and other sensitive values are masked. The resolved values are also logged at debug level during every dry-run.")
+	deployCmd.Flags().BoolVar(&opts.plan, "plan", false, "Do not deploy, but compare the rendered configurations to the current state of the target environments and print a plan of which configurations would be created, updated, or left unchanged.")
+	deployCmd.Flags().BoolVar(&opts.rollbackOnError, "rollback-on-error", false, "If the deployment to an environment fails, revert all configurations deployed to it: updated configurations are restored to their previous state, and created configurations are deleted.")
+	deployCmd.Flags().StringVar(&opts.reportFile, "report", "", "Write a report listing the outcome (deployed, failed, skipped), duration and error of every configuration to the given file.")
+	deployCmd.Flags().StringVar((*string)(&opts.reportFormat), "report-format", string(report.FormatJSON), fmt.Sprintf("Format of the report written by '--report'. One of %q.", report.Formats))
+	deployCmd.Flags().StringVar(&opts.summaryFile, "summary-file", "", "Write a JSON summary listing the outcome (deployed, failed, skipped) and error of every configuration to the given file.")
+	deployCmd.Flags().StringSliceVar(&opts.only, "only", []string{}, "Only deploy configurations matching the given coordinate 'project:type:configId', together with all configurations they depend on. "+
+		"Supports wildcards, e.g. 'my-project:builtin:alerting.profile:*'. "+
+		"To select multiple configurations either repeat this flag, or separate them using a comma (,).")
+	deployCmd.Flags().BoolVar(&opts.stampOwnership, "stamp-ownership", false, "Stamp deployed configurations with tags identifying the monaco project, configuration and deployment time, where the API supports tags (dashboards and SLOs). Settings 2.0 objects and documents are always identifiable by their externalId.")
+	deployCmd.Flags().BoolVar(&opts.skipUnchanged, "skip-unchanged", false, "Before deploying a configuration, read its current state from the environment and skip the deployment if nothing changed. This reduces API calls and audit log entries of repeated deployments, at the cost of an additional read per configuration.")
+	deployCmd.Flags().IntVar(&opts.concurrency, "concurrency", 0, "Maximum number of independent configurations deployed in parallel to an environment. Configurations referencing each other are always deployed in order. Set to 0 for no limit.")
+	deployCmd.Flags().IntVar(&opts.parallelEnvironments, "parallel-environments", 1, "Maximum number of environments deployed to in parallel. Set to 1 to deploy to one environment after the other.")
+	deployCmd.Flags().StringVar(&opts.policyFile, "policy", "", "YAML file of policy rules that the rendered payloads of all configurations are checked against before the deployment starts, also during '--dry-run'. "+
+		"If any configuration violates a rule, nothing is deployed. With '--continue-on-error', only the configurations violating a rule fail to deploy.")
+	deployCmd.Flags().StringVar((*string)(&opts.secretScan), "secret-scan", string(secrets.LevelOff), fmt.Sprintf("Scan the rendered payload of every configuration for values that look like secrets, e.g. API tokens, keys, or random passwords, before it is deployed, also during '--dry-run'. "+
+		"One of %q: 'warn' logs a warning for each configuration containing secrets, 'fail' fails their deployment.", secrets.Levels))
+	deployCmd.Flags().StringVar(&opts.secretScanAllowList, "secret-scan-allow-list", "", "YAML file listing values of configurations that are not reported by '--secret-scan', e.g. because they are no secrets.")
+	deployCmd.Flags().StringVar(&opts.canary.group, "canary-group", "", "Deploy to all environments of the given environment group first. "+
+		"Only if that deployment succeeds, and it was verified using '--canary-wait' and '--canary-verify', the remaining environment groups are deployed to one after the other, ordered by name. "+
+		"The deployment stops at the first group that fails.")
+	deployCmd.Flags().DurationVar(&opts.canary.wait, "canary-wait", 0, "Time to wait after deploying to the canary group before verifying it and continuing with the remaining groups, e.g. '5m'.")
+	deployCmd.Flags().StringVar(&opts.canary.verifyCommand, "canary-verify", "", "Command verifying the deployment to the canary group, executed after '--canary-wait'. "+
+		"The remaining groups are only deployed to if it succeeds. The environment variables MONACO_CANARY_GROUP and MONACO_ENVIRONMENTS describe the canary deployment.")
+	deployCmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Maximum duration of the whole deployment, e.g. '30m'. Once it is exceeded, in-flight requests are cancelled and no further configurations are deployed. Set to 0 for no limit.")
+	deployCmd.Flags().DurationVar(&opts.configTimeout, "config-timeout", 0, "Maximum duration of the deployment of a single configuration including all retries, e.g. '2m'. Configurations exceeding it fail to deploy. Set to 0 for no limit.")
+	deployCmd.Flags().BoolVar(&opts.lock.enabled, "lock", false, "Lock every environment before deploying to it, so that concurrent deployments to the same environment detect each other instead of interleaving their updates. "+
+		"The lock is stored as a document in the environment, thus all environments require platform credentials. "+
+		"Locks are released after the deployment; locks of crashed deployments expire after '--lock-ttl'.")
+	deployCmd.Flags().DurationVar(&opts.lock.wait, "lock-wait", 0, "Maximum time to wait for an environment locked by another deployment, e.g. '10m'. Set to 0 to fail immediately.")
+	deployCmd.Flags().DurationVar(&opts.lock.ttl, "lock-ttl", lock.DefaultTTL, "Time after which the lock of an environment expires, unless the deployment holding it is still running and renews it.")
+	deployCmd.Flags().BoolVar(&opts.allowProtected, "allow-protected", false, "Deploy configurations of types listed in the 'protectedTypes' of an environment in the manifest. "+
+		"Without this flag, the deployment is refused if any such configuration would be deployed.")
+	deployCmd.Flags().BoolVar(&opts.prefetch, "prefetch", false, "Before deploying to an environment, list the existing objects of all classic APIs and settings schemas used by the deployment once, in parallel. "+
+		"Existing objects are then looked up in memory instead of listing the same API for every configuration, which reduces API calls for large projects.")
+	deployCmd.Flags().StringVar(&opts.eventsFile, "events-file", "", "File to write an event to for every deployed configuration, as newline-delimited JSON, so that external tools can follow the deployment by tailing it. "+
+		"Each event contains the outcome of the configuration, the number of completed and total configurations, and the estimated remaining time.")
+	deployCmd.Flags().BoolVar(&opts.skipDeprecated, "skip-deprecated", false, "Skip configurations of classic APIs that are deprecated in favor of another API, instead of deploying them. "+
+		"A warning is logged for each skipped configuration, and a table of all of them and the APIs replacing them is shown after the deployment.")
+	deployCmd.Flags().BoolVar(&opts.acceptNewerSchema, "accept-newer-schema", false, "Deploy settings configurations that declare an older 'schemaVersion' than the environment provides against the newer version of the schema. "+
+		"Without this flag, configurations declaring a version with a different major version than the environment fail to deploy with an error naming both versions.")
+	deployCmd.Flags().StringVar(&opts.diffBase, "diff-base", "", "Only deploy configurations that were added or changed compared to a previous version of the projects, together with all configurations depending on them. "+
+		"The previous version is either a folder containing a copy of the manifest's folder, or a git ref (e.g. 'main' or 'HEAD~1') of the repository containing the manifest. "+
+		"Removed configurations are only reported, use 'monaco delete' to remove them.")
+	cmdutils.AddVariablesFlag(deployCmd, &opts.variables)
+	deployCmd.Flags().BoolVar(&opts.deleteOrphaned, "delete-orphaned", false, "After a successful deployment, delete the Settings 2.0 objects monaco deployed for one of the deployed projects whose configurations were removed from the project. "+
+		"Objects are attributed to projects by their externalId, objects of other types and objects deployed by monaco versions without projects in their externalId are never deleted.")
 
 	err := deployCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag)
 	if err != nil {
@@ -75,6 +175,14 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 	}
 
 	deployCmd.MarkFlagsMutuallyExclusive("environment", "group")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "dry-run")
+	deployCmd.MarkFlagsMutuallyExclusive("rollback-on-error", "dry-run")
+	deployCmd.MarkFlagsMutuallyExclusive("rollback-on-error", "plan")
+	deployCmd.MarkFlagsMutuallyExclusive("diff-base", "only")
+	deployCmd.MarkFlagsMutuallyExclusive("lock", "dry-run")
+	deployCmd.MarkFlagsMutuallyExclusive("lock", "plan")
+	deployCmd.MarkFlagsMutuallyExclusive("delete-orphaned", "dry-run")
+	deployCmd.MarkFlagsMutuallyExclusive("delete-orphaned", "plan")
 
 	return deployCmd
 }
@@ -15,35 +15,124 @@
 package deploy
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy/internal/hooks"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy/internal/logging"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/policy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/secrets"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/gitsource"
 	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/spf13/afero"
+	"golang.org/x/exp/maps"
 )
 
-func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string, specificEnvironments []string, specificProjects []string, continueOnErr bool, dryRun bool) error {
+// deployOptions holds the flags of the deploy command that modify how configurations are deployed
+type deployOptions struct {
+	// continueOnError states that the deployment continues even if individual configurations fail to deploy
+	continueOnError bool
+	// dryRun states that configurations are only validated, but not deployed
+	dryRun bool
+	// skipUnchanged states that configurations already matching their current state are not deployed again
+	skipUnchanged bool
+	// concurrency limits the number of configurations deployed in parallel to an environment
+	concurrency int
+	// parallelEnvironments limits the number of environments deployed to in parallel
+	parallelEnvironments int
+	// dumpParametersFile is the path of an optional file the resolved parameter values of all configurations are
+	// written to during a dry-run
+	dumpParametersFile string
+	// remoteValidation states that payloads are validated against the target environments during a dry-run
+	remoteValidation bool
+	// plan states that instead of deploying, the changes a deployment would make are printed
+	plan bool
+	// rollbackOnError states that all modifications of an environment are reverted if its deployment fails
+	rollbackOnError bool
+	// summaryFile is the path of an optional JSON file listing the outcome of the deployment of every configuration
+	summaryFile string
+	// reportFile is the path of an optional report listing the outcome of the deployment of every configuration
+	reportFile string
+	// reportFormat is the format the report is written in
+	reportFormat report.Format
+	// only restricts the deployment to the configurations matching one of these coordinate patterns, and their dependencies
+	only []string
+	// stampOwnership states that deployed configs are stamped with metadata identifying them as deployed by monaco
+	stampOwnership bool
+	// canary configures deploying to one environment group before all others
+	canary canaryOptions
+	// policyFile is the path of an optional file of policy rules that all rendered configurations must comply with
+	policyFile string
+	// secretScan defines whether rendered payloads are scanned for secrets, and how found secrets are handled
+	secretScan secrets.Level
+	// secretScanAllowList is the path of an optional file of values that are not reported by the secret scan
+	secretScanAllowList string
+	// timeout limits the duration of the whole deployment, if > 0
+	timeout time.Duration
+	// configTimeout limits the duration of the deployment of a single configuration, if > 0
+	configTimeout time.Duration
+	// lock configures locking the environments deployed to, preventing concurrent deployments
+	lock lockOptions
+	// allowProtected states that configurations of types protected by an environment are deployed to it
+	allowProtected bool
+	// prefetch states that existing objects of all used APIs and settings schemas are listed once before deploying
+	prefetch bool
+	// eventsFile is the path of an optional file every deployed configuration is written to as JSON event, one per line
+	eventsFile string
+	// skipDeprecated states that configurations of deprecated classic APIs are skipped instead of deployed
+	skipDeprecated bool
+	// acceptNewerSchema states that settings configurations are deployed against newer schema versions of the environment
+	acceptNewerSchema bool
+	// diffBase is a folder or git ref holding a previous version of the projects. If set, only configurations that
+	// were added or changed since then, and configurations depending on them, are deployed.
+	diffBase string
+	// deleteOrphaned states that settings objects of removed configurations of the deployed projects are deleted after
+	// a successful deployment
+	deleteOrphaned bool
+	// variables override the values of variables defined in the manifest
+	variables map[string]string
+}
+
+func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string, specificEnvironments []string, specificProjects []string, opts deployOptions) error {
+	if gitsource.IsSource(manifestPath) {
+		localPath, err := fetchManifest(fs, manifestPath)
+		if err != nil {
+			return err
+		}
+		manifestPath = localPath
+	}
+
 	absManifestPath, err := absPath(manifestPath)
 	if err != nil {
 		return fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
 	}
-	loadedManifest, err := loadManifest(fs, absManifestPath, environmentGroups, specificEnvironments)
+	loadedManifest, err := loadManifest(fs, absManifestPath, environmentGroups, specificEnvironments, opts.variables)
 	if err != nil {
 		return err
 	}
 
-	ok := verifyEnvironmentGen(loadedManifest.Environments, dryRun)
+	ok := verifyEnvironmentGen(loadedManifest.Environments, opts.dryRun)
 	if !ok {
 		return fmt.Errorf("unable to verify Dynatrace environment generation")
 	}
@@ -57,6 +146,41 @@ func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string,
 		return err
 	}
 
+	if !opts.allowProtected {
+		if err := checkProtectedTypes(loadedProjects, loadedManifest.Environments); err != nil {
+			return err
+		}
+	}
+
+	if opts.diffBase != "" {
+		patterns, err := selectChangedConfigs(fs, absManifestPath, loadedManifest, loadedProjects, opts.diffBase)
+		if err != nil {
+			return err
+		}
+		if len(patterns) == 0 {
+			log.Info("No configurations were added or changed compared to %q, nothing to deploy", opts.diffBase)
+			return nil
+		}
+		opts.only = patterns
+	}
+
+	var evaluator policy.Evaluator
+	if opts.policyFile != "" {
+		rules, err := policy.LoadRules(fs, opts.policyFile)
+		if err != nil {
+			return err
+		}
+		log.Info("Loaded %d policy rules from %q", len(rules.Rules), opts.policyFile)
+		evaluator = rules
+	}
+
+	var allowList secrets.AllowList
+	if opts.secretScanAllowList != "" {
+		if allowList, err = secrets.LoadAllowList(fs, opts.secretScanAllowList); err != nil {
+			return err
+		}
+	}
+
 	logging.LogProjectsInfo(loadedProjects)
 	logging.LogEnvironmentsInfo(loadedManifest.Environments)
 
@@ -65,27 +189,229 @@ func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string,
 		return fmt.Errorf("failed to create API clients: %w", err)
 	}
 
-	err = deploy.Deploy(loadedProjects, clientSets, deploy.DeployConfigsOptions{ContinueOnErr: continueOnErr, DryRun: dryRun})
+	stages, err := planStages(clientSets, opts.canary.group)
+	if err != nil {
+		return err
+	}
+
+	// cancelling the context on interrupt signals stops in-flight requests and retries, instead of leaving the process
+	// hanging until they finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	if opts.lock.enabled {
+		var releaseLocks func(context.Context)
+		ctx, releaseLocks, err = acquireLocks(ctx, clientSets, opts.lock)
+		if err != nil {
+			return err
+		}
+		defer releaseLocks(context.WithoutCancel(ctx))
+	}
+
+	// hooks may have side effects like notifications, thus they are only executed on actual deployments
+	runHooks := !opts.dryRun && !opts.plan
+	hookRunner := hooks.NewRunner()
+	if runHooks {
+		if err := hookRunner.Run(ctx, manifest.HookStagePre, loadedProjects, loadedManifest.Projects, loadedManifest.Environments.Names(), ""); err != nil {
+			return fmt.Errorf("pre-deployment hooks failed, deployment aborted: %w", err)
+		}
+	}
+
+	var progressOpts []report.ProgressOption
+	if opts.eventsFile != "" {
+		eventsFile, err := fs.Create(opts.eventsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create events file %q: %w", opts.eventsFile, err)
+		}
+		defer eventsFile.Close()
+		progressOpts = append(progressOpts, report.WithEvents(eventsFile))
+	}
+	progress := report.NewProgress(progressOpts...)
+
+	var environmentHooks deploy.EnvironmentHooks
+	if runHooks {
+		environmentHooks = hookRunner.ConfigTypeHooks(loadedManifest.Projects)
+	}
+
+	summary := report.NewSummary()
+	var resolvedParameters *deploy.ResolvedParameters
+	if opts.dumpParametersFile != "" {
+		resolvedParameters = deploy.NewResolvedParameters()
+	}
+	err = deployStages(ctx, loadedProjects, stages, deploy.DeployConfigsOptions{
+		ContinueOnErr:            opts.continueOnError,
+		DryRun:                   opts.dryRun,
+		MaxConcurrentDeployments: opts.concurrency,
+		MaxParallelEnvironments:  opts.parallelEnvironments,
+		RemoteValidation:         opts.remoteValidation,
+		Plan:                     opts.plan,
+		RollbackOnError:          opts.rollbackOnError,
+		Only:                     opts.only,
+		StampOwnership:           opts.stampOwnership,
+		SkipUnchanged:            opts.skipUnchanged,
+		Reporter:                 report.Multi(summary, progress),
+		Policy:                   evaluator,
+		ConfigTimeout:            opts.configTimeout,
+		Prefetch:                 opts.prefetch,
+		SkipDeprecated:           opts.skipDeprecated,
+		AcceptNewerSchema:        opts.acceptNewerSchema,
+		SecretScan:               opts.secretScan,
+		SecretScanAllowList:      allowList,
+		ResolvedParameters:       resolvedParameters,
+		EnvironmentHooks:         environmentHooks,
+	}, opts.canary, runHooks)
+
+	if err == nil && opts.deleteOrphaned {
+		err = deleteOrphans(ctx, loadedProjects, clientSets)
+	}
+
+	if err := progress.Finish(); err != nil {
+		log.WithFields(field.Error(err)).Error("Events file %q is incomplete: %v", opts.eventsFile, err)
+	}
+
+	if runHooks {
+		status := hooks.StatusSucceeded
+		if err != nil {
+			status = hooks.StatusFailed
+		}
+		// post-deployment hooks report the outcome of a cancelled deployment as well
+		if hookErr := hookRunner.Run(context.WithoutCancel(ctx), manifest.HookStagePost, loadedProjects, loadedManifest.Projects, loadedManifest.Environments.Names(), status); hookErr != nil {
+			err = errors.Join(err, fmt.Errorf("post-deployment hooks failed: %w", hookErr))
+		}
+	}
+
+	if opts.continueOnError {
+		logFailedConfigs(summary)
+	}
+	if opts.skipDeprecated {
+		logDeprecatedSkips(summary)
+	}
+	if opts.summaryFile != "" {
+		if err := summary.Write(fs, opts.summaryFile, report.FormatJSON); err != nil {
+			log.WithFields(field.Error(err)).Error("Failed to write deployment summary: %v", err)
+		} else {
+			log.Info("Deployment summary written to %q", opts.summaryFile)
+		}
+	}
+	if opts.reportFile != "" {
+		if err := summary.Write(fs, opts.reportFile, opts.reportFormat); err != nil {
+			log.WithFields(field.Error(err)).Error("Failed to write deployment report: %v", err)
+		} else {
+			log.Info("Deployment report written to %q", opts.reportFile)
+		}
+	}
+
+	if resolvedParameters != nil {
+		if err := writeResolvedParameters(fs, opts.dumpParametersFile, resolvedParameters); err != nil {
+			log.WithFields(field.Error(err)).Error("Failed to write resolved parameters: %v", err)
+		} else {
+			log.Info("Resolved parameters written to %q", opts.dumpParametersFile)
+		}
+	}
+
 	if err != nil {
-		return fmt.Errorf("%v failed - check logs for details: %w", logging.GetOperationNounForLogging(dryRun), err)
+		return fmt.Errorf("%v failed - check logs for details: %w", logging.GetOperationNounForLogging(opts.dryRun || opts.plan), err)
+	}
+
+	log.Info("%s finished without errors", logging.GetOperationNounForLogging(opts.dryRun || opts.plan))
+	return nil
+}
+
+// logFailedConfigs lists all configurations that failed to deploy, so that they do not need to be searched in the logs
+func logFailedConfigs(summary *report.Summary) {
+	failed := summary.Count(report.StateFailed)
+	if failed == 0 {
+		return
+	}
+
+	log.Error("%d configurations failed to deploy:", failed)
+	for _, r := range summary.Records() {
+		if r.State == report.StateFailed {
+			log.WithFields(field.Coordinate(r.Config), field.Environment(r.Environment, "")).Error("  - %s (environment %q): %s", r.Config, r.Environment, r.Error)
+		}
 	}
+}
 
-	log.Info("%s finished without errors", logging.GetOperationNounForLogging(dryRun))
+// logDeprecatedSkips lists all configurations that were skipped because their classic API is deprecated, together
+// with the API replacing it, as a table.
+func logDeprecatedSkips(summary *report.Summary) {
+	apis := api.NewAPIs()
+	var skipped []report.Record
+	for _, r := range summary.Records() {
+		if r.State == report.StateSkipped && r.Error != "" && apis[r.Config.Type].DeprecatedBy != "" {
+			skipped = append(skipped, r)
+		}
+	}
+	if len(skipped) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONFIG\tENVIRONMENT\tDEPRECATED API\tMIGRATE TO")
+	for _, r := range skipped {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Config, r.Environment, r.Config.Type, apis[r.Config.Type].DeprecatedBy)
+	}
+	_ = w.Flush()
+
+	log.Warn("%d configurations of deprecated APIs were skipped:\n%s", len(skipped), strings.TrimSuffix(b.String(), "\n"))
+}
+
+// validateManifestName checks that the manifest given on the command line is a yaml file. The manifest may be given as
+// Git source, e.g. 'git::https://github.com/example/repo.git//manifest.yaml?ref=v1.2.0'.
+func validateManifestName(manifestName string) error {
+	name := manifestName
+	if gitsource.IsSource(manifestName) {
+		src, err := gitsource.Parse(manifestName)
+		if err != nil {
+			return err
+		}
+		name = src.Subdir
+	}
+
+	if !files.IsYamlFileExtension(name) {
+		return fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+	}
 	return nil
 }
 
+// fetchManifest fetches the repository of a manifest given as Git source into the default Git cache, and returns
+// the local path of the manifest. The projects of the manifest are loaded from the fetched repository as well.
+func fetchManifest(fs afero.Fs, manifestPath string) (string, error) {
+	src, err := gitsource.Parse(manifestPath)
+	if err != nil {
+		return "", err
+	}
+
+	cacheDir, err := gitsource.DefaultCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	log.Info("Fetching manifest %q...", manifestPath)
+	return gitsource.Resolve(fs, gitsource.CLIFetcher{}, cacheDir, src)
+}
+
 func absPath(manifestPath string) (string, error) {
 	manifestPath = filepath.Clean(manifestPath)
 	return filepath.Abs(manifestPath)
 }
 
-func loadManifest(fs afero.Fs, manifestPath string, groups []string, environments []string) (*manifest.Manifest, error) {
+func loadManifest(fs afero.Fs, manifestPath string, groups []string, environments []string, variables map[string]string) (*manifest.Manifest, error) {
 	m, errs := manifestloader.Load(&manifestloader.Context{
 		Fs:           fs,
 		ManifestPath: manifestPath,
 		Groups:       groups,
 		Environments: environments,
+		Variables:    variables,
 		Opts:         manifestloader.Options{RequireEnvironmentGroups: true},
+
+		EnvironmentDiscoverer: dynatrace.AccountEnvironmentDiscoverer{},
 	})
 
 	if len(errs) > 0 {
@@ -96,6 +422,18 @@ func loadManifest(fs afero.Fs, manifestPath string, groups []string, environment
 	return &m, nil
 }
 
+// writeResolvedParameters writes the collected resolved parameter values as indented JSON to the given path
+func writeResolvedParameters(fs afero.Fs, path string, resolvedParameters *deploy.ResolvedParameters) error {
+	content, err := json.MarshalIndent(resolvedParameters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resolved parameters: %w", err)
+	}
+	if err := afero.WriteFile(fs, path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write resolved parameters to %q: %w", path, err)
+	}
+	return nil
+}
+
 func verifyEnvironmentGen(environments manifest.Environments, dryRun bool) bool {
 	if !dryRun {
 		return dynatrace.VerifyEnvironmentGeneration(environments)
@@ -139,23 +477,92 @@ func checkEnvironments(projects []project.Project, envs manifest.Environments) e
 	return nil
 }
 
+// checkConfigsForEnvironment returns an error if one of the configurations can not be deployed to the environment,
+// e.g. if the environment does not define the credentials required to deploy it: platform exclusive configurations
+// require OAuth credentials, configurations of classic Config APIs an access token.
 func checkConfigsForEnvironment(env manifest.EnvironmentDefinition, cfgs []config.Config) error {
 	for i := range cfgs {
-		if !cfgs[i].Skip && onlyAvailableOnPlatform(&cfgs[i]) && !platformEnvironment(env) {
-			return fmt.Errorf("enviroment %q is not specified as platform, but at least one of configurations (e.g. %q) is platform exclusive", env.Name, cfgs[i].Coordinate)
+		if cfgs[i].Skip {
+			continue
+		}
+		if notDeployableYet(&cfgs[i]) {
+			return fmt.Errorf("configurations of type %q (e.g. %q) can only be downloaded, but not deployed by monaco yet. Set 'skip: true' for them or remove them from the project", cfgs[i].Coordinate.Type, cfgs[i].Coordinate)
+		}
+		_, isClusterConfig := cfgs[i].Type.(config.ClusterType)
+		if env.IsCluster() && !isClusterConfig {
+			return fmt.Errorf("environment %q is a Managed cluster, but configurations of type %q (e.g. %q) can only be deployed to Dynatrace environments", env.Name, cfgs[i].Coordinate.Type, cfgs[i].Coordinate)
+		}
+		if !env.IsCluster() && isClusterConfig {
+			return fmt.Errorf("environment %q is no Managed cluster ('type: cluster' in the manifest), but it is required to deploy configurations of type %q (e.g. %q)", env.Name, cfgs[i].Coordinate.Type, cfgs[i].Coordinate)
+		}
+		if onlyAvailableOnPlatform(&cfgs[i]) && !env.Auth.HasOAuth() {
+			return fmt.Errorf("environment %q defines no OAuth credentials ('auth.oAuth' in the manifest), but they are required to deploy configurations of type %q (e.g. %q)", env.Name, cfgs[i].Coordinate.Type, cfgs[i].Coordinate)
+		}
+		if requiresAccessToken(&cfgs[i]) && !env.Auth.HasToken() {
+			return fmt.Errorf("environment %q defines no access token ('auth.token' in the manifest), but it is required to deploy configurations of type %q (e.g. %q)", env.Name, cfgs[i].Coordinate.Type, cfgs[i].Coordinate)
 		}
 	}
 	return nil
 }
 
-func platformEnvironment(e manifest.EnvironmentDefinition) bool {
-	return e.Auth.OAuth != nil
+// checkProtectedTypes returns an error listing all configurations to be deployed to an environment that protects their
+// config type
+func checkProtectedTypes(projects []project.Project, envs manifest.Environments) error {
+	envNames := maps.Keys(envs)
+	slices.Sort(envNames)
+
+	var errs []error
+	for _, envName := range envNames {
+		env := envs[envName]
+		if len(env.ProtectedTypes) == 0 {
+			continue
+		}
+
+		var protected []string
+		for _, p := range projects {
+			for cfgType, cfgs := range p.Configs[envName] {
+				if !env.IsProtectedType(cfgType) {
+					continue
+				}
+				for _, c := range cfgs {
+					if !c.Skip {
+						protected = append(protected, c.Coordinate.String())
+					}
+				}
+			}
+		}
+
+		if len(protected) > 0 {
+			slices.Sort(protected)
+			errs = append(errs, fmt.Errorf("environment %q protects the config types %q, but %d configurations of them would be deployed: %s", envName, env.ProtectedTypes, len(protected), strings.Join(protected, ", ")))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("refusing to deploy protected config types without '--allow-protected': %w", errors.Join(errs...))
+	}
+	return nil
 }
 
 func onlyAvailableOnPlatform(c *config.Config) bool {
-	if _, ok := c.Type.(config.AutomationType); ok {
+	switch c.Type.(type) {
+	case config.AutomationType, config.BucketType, config.DocumentType, config.SLOType, config.SegmentType, config.OpenPipelineType:
 		return true
 	}
-	_, ok := c.Type.(config.BucketType)
+	return false
+}
+
+// notDeployableYet returns whether the configuration is of a type monaco can download, but not deploy
+func notDeployableYet(c *config.Config) bool {
+	switch c.Type.(type) {
+	case config.SegmentType, config.OpenPipelineType:
+		return true
+	}
+	return false
+}
+
+// requiresAccessToken returns whether the configuration is deployed using a classic Config API, which requires an access token
+func requiresAccessToken(c *config.Config) bool {
+	_, ok := c.Type.(config.ClassicApiType)
 	return ok
 }
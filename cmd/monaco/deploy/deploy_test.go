@@ -17,9 +17,12 @@
 package deploy
 
 import (
+	"encoding/json"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -47,7 +50,7 @@ func Test_DoDeploy_InvalidManifest(t *testing.T) {
 	manifestPath, _ := filepath.Abs("manifest.yaml")
 	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
 
-	err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, true, true)
+	err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{continueOnError: true, dryRun: true})
 	assert.Error(t, err)
 }
 
@@ -88,27 +91,268 @@ environmentGroups:
 	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
 
 	t.Run("Wrong environment group", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"NOT_EXISTING_GROUP"}, []string{}, []string{}, true, true)
+		err := deployConfigs(testFs, manifestPath, []string{"NOT_EXISTING_GROUP"}, []string{}, []string{}, deployOptions{continueOnError: true, dryRun: true})
 		assert.Error(t, err)
 	})
 	t.Run("Wrong environment name", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"NOT_EXISTING_ENV"}, []string{}, true, true)
+		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"NOT_EXISTING_ENV"}, []string{}, deployOptions{continueOnError: true, dryRun: true})
 		assert.Error(t, err)
 	})
 
 	t.Run("Wrong project name", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"NON_EXISTING_PROJECT"}, true, true)
+		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"NON_EXISTING_PROJECT"}, deployOptions{continueOnError: true, dryRun: true})
 		assert.Error(t, err)
 	})
 
 	t.Run("no parameters", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, true, true)
+		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{continueOnError: true, dryRun: true})
 		assert.NoError(t, err)
 	})
 
 	t.Run("correct parameters", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"project"}, true, true)
+		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"project"}, deployOptions{continueOnError: true, dryRun: true})
 		assert.NoError(t, err)
 	})
 
+	t.Run("summary file", func(t *testing.T) {
+		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{continueOnError: true, dryRun: true, summaryFile: "summary.json"})
+		require.NoError(t, err)
+
+		content, err := afero.ReadFile(testFs, "summary.json")
+		require.NoError(t, err)
+
+		var summary struct {
+			Deployed int `json:"deployed"`
+			Configs  []struct {
+				Environment string `json:"environment"`
+				State       string `json:"state"`
+			} `json:"configs"`
+		}
+		require.NoError(t, json.Unmarshal(content, &summary))
+		assert.Equal(t, 1, summary.Deployed)
+		require.Len(t, summary.Configs, 1)
+		assert.Equal(t, "project", summary.Configs[0].Environment)
+		assert.Equal(t, "deployed", summary.Configs[0].State)
+	})
+}
+
+func Test_DoDeploy_ProtectedTypes(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: project
+    url:
+      value: https://abcde.dev.dynatracelabs.com
+    auth:
+      token:
+        name: ENV_TOKEN
+    protectedTypes: [alerting-profile]
+`
+	configYaml := `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+`
+	testFs := afero.NewMemMapFs()
+	configPath, _ := filepath.Abs("project/alerting-profile/profile.yaml")
+	_ = afero.WriteFile(testFs, configPath, []byte(configYaml), 0644)
+	templatePath, _ := filepath.Abs("project/alerting-profile/profile.json")
+	_ = afero.WriteFile(testFs, templatePath, []byte("{}"), 0644)
+	manifestPath, _ := filepath.Abs("manifest.yaml")
+	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
+
+	t.Run("protected types are refused", func(t *testing.T) {
+		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{dryRun: true})
+		assert.ErrorContains(t, err, `environment "project" protects the config types ["alerting-profile"], but 1 configurations of them would be deployed: project:alerting-profile:profile`)
+	})
+
+	t.Run("protected types are deployed with allowProtected", func(t *testing.T) {
+		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{dryRun: true, allowProtected: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("events are written to the events file", func(t *testing.T) {
+		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{dryRun: true, allowProtected: true, eventsFile: "events.ndjson"})
+		assert.NoError(t, err)
+
+		content, err := afero.ReadFile(testFs, "events.ndjson")
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[0], `"event":"planned"`)
+		assert.Contains(t, lines[1], `"event":"config"`)
+		assert.Contains(t, lines[1], `"configId":"profile"`)
+		assert.Contains(t, lines[2], `"event":"finished"`)
+	})
+
+	t.Run("resolved parameters are written to the dump file", func(t *testing.T) {
+		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{dryRun: true, allowProtected: true, dumpParametersFile: "parameters.json"})
+		assert.NoError(t, err)
+
+		content, err := afero.ReadFile(testFs, "parameters.json")
+		require.NoError(t, err)
+		var got map[string]map[string]map[string]any
+		require.NoError(t, json.Unmarshal(content, &got))
+		assert.Equal(t, "alerting-profile", got["project"]["project:alerting-profile:profile"]["name"])
+	})
+}
+
+func Test_DoDeploy_MissingCredentials(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+	t.Setenv("CLIENT_ID", "mock client id")
+	t.Setenv("CLIENT_SECRET", "mock client secret")
+
+	tests := []struct {
+		name          string
+		auth          string
+		envType       string
+		configYaml    string
+		wantErrorPart string
+	}{
+		{
+			name: "classic config without token",
+			auth: `{oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}}`,
+			configYaml: `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+`,
+			wantErrorPart: `environment "project" defines no access token ('auth.token' in the manifest), but it is required to deploy configurations of type "alerting-profile" (e.g. "project:alerting-profile:profile")`,
+		},
+		{
+			name: "platform config without OAuth credentials",
+			auth: `{token: {name: ENV_TOKEN}}`,
+			configYaml: `configs:
+- id: workflow
+  config:
+    name: workflow
+    template: profile.json
+  type:
+    automation:
+      resource: workflow
+`,
+			wantErrorPart: `environment "project" defines no OAuth credentials ('auth.oAuth' in the manifest), but they are required to deploy configurations of type "workflow" (e.g. "project:workflow:workflow")`,
+		},
+		{
+			name: "document without OAuth credentials",
+			auth: `{token: {name: ENV_TOKEN}}`,
+			configYaml: `configs:
+- id: dashboard
+  config:
+    name: dashboard
+    template: profile.json
+  type:
+    document:
+      type: dashboard-document
+`,
+			wantErrorPart: `environment "project" defines no OAuth credentials ('auth.oAuth' in the manifest), but they are required to deploy configurations of type "dashboard-document" (e.g. "project:dashboard-document:dashboard")`,
+		},
+		{
+			name: "segment",
+			auth: `{oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}}`,
+			configYaml: `configs:
+- id: segment
+  config:
+    name: segment
+    template: profile.json
+  type: segment
+`,
+			wantErrorPart: `configurations of type "segment" (e.g. "project:segment:segment") can only be downloaded, but not deployed by monaco yet`,
+		},
+		{
+			name: "OpenPipeline configuration",
+			auth: `{oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}}`,
+			configYaml: `configs:
+- id: logs
+  config:
+    name: logs
+    template: profile.json
+  type:
+    openpipeline:
+      kind: logs
+`,
+			wantErrorPart: `configurations of type "openpipeline" (e.g. "project:openpipeline:logs") can only be downloaded, but not deployed by monaco yet`,
+		},
+		{
+			name: "cluster config on Dynatrace environment",
+			auth: `{token: {name: ENV_TOKEN}}`,
+			configYaml: `configs:
+- id: group
+  config:
+    name: group
+    template: profile.json
+  type:
+    cluster: cluster-user-group
+`,
+			wantErrorPart: `environment "project" is no Managed cluster ('type: cluster' in the manifest), but it is required to deploy configurations of type "cluster-user-group" (e.g. "project:cluster-user-group:group")`,
+		},
+		{
+			name:    "classic config on Managed cluster",
+			auth:    `{token: {name: ENV_TOKEN}}`,
+			envType: "cluster",
+			configYaml: `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+`,
+			wantErrorPart: `environment "project" is a Managed cluster, but configurations of type "alerting-profile" (e.g. "project:alerting-profile:profile") can only be deployed to Dynatrace environments`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: project
+    url:
+      value: https://abcde.dev.dynatracelabs.com
+    auth: ` + tt.auth + `
+`
+			if tt.envType != "" {
+				manifestYaml += "    type: " + tt.envType + "\n"
+			}
+			testFs := afero.NewMemMapFs()
+			configPath, _ := filepath.Abs("project/config/config.yaml")
+			_ = afero.WriteFile(testFs, configPath, []byte(tt.configYaml), 0644)
+			templatePath, _ := filepath.Abs("project/config/profile.json")
+			_ = afero.WriteFile(testFs, templatePath, []byte("{}"), 0644)
+			manifestPath, _ := filepath.Abs("manifest.yaml")
+			_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
+
+			err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, deployOptions{dryRun: true})
+			assert.ErrorContains(t, err, tt.wantErrorPart)
+		})
+	}
+}
+
+func Test_validateManifestName(t *testing.T) {
+	for name, wantErr := range map[string]bool{
+		"manifest.yaml": false,
+		"manifest.json": true,
+		"git::https://github.com/example/repo.git//monaco/manifest.yaml?ref=v1.2.0": false,
+		"git::https://github.com/example/repo.git//monaco?ref=v1.2.0":               true,
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateManifestName(name)
+			assert.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+		})
+	}
 }
@@ -0,0 +1,157 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/exp/maps"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+// selectChangedConfigs compares the loaded projects to their version in diffBase, and returns '--only' patterns
+// selecting all added or changed configs, plus all configs depending on them. If nothing changed, no patterns are
+// returned.
+func selectChangedConfigs(fs afero.Fs, manifestPath string, m *manifest.Manifest, projects []project.Project, diffBase string) ([]string, error) {
+	baseProjects, err := loadDiffBase(fs, manifestPath, m, diffBase)
+	if err != nil {
+		return nil, err
+	}
+
+	changedPerEnvironment := make(map[string][]coordinate.Coordinate)
+	for _, c := range project.Compare(baseProjects, projects) {
+		log.Info("Configuration %s was %s in environment %q compared to %q", c.Coordinate, c.Kind, c.Environment, diffBase)
+		if c.Kind == project.ConfigRemoved {
+			continue
+		}
+		changedPerEnvironment[c.Environment] = append(changedPerEnvironment[c.Environment], c.Coordinate)
+	}
+
+	// the deployment selects the same configs in all environments, thus the changes of all environments are combined
+	patterns := make(map[string]struct{})
+	for env, changed := range changedPerEnvironment {
+		for _, c := range project.Dependents(projects, env, changed) {
+			patterns[escapePattern(c.String())] = struct{}{}
+		}
+	}
+	return maps.Keys(patterns), nil
+}
+
+// escapePattern escapes all characters of s that have a special meaning in path.Match patterns
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// loadDiffBase loads the projects of the manifest in their base version. The diffBase is either a folder containing a
+// copy of the manifest's folder, or a git ref of the repository containing the manifest. Projects that do not exist in
+// the base version are not loaded, so all their configs are considered added.
+func loadDiffBase(fs afero.Fs, manifestPath string, m *manifest.Manifest, diffBase string) ([]project.Project, error) {
+	baseFs, baseDir, err := openDiffBase(fs, filepath.Dir(manifestPath), diffBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open diff base %q: %w", diffBase, err)
+	}
+
+	baseManifest := *m
+	baseManifest.Projects = make(manifest.ProjectDefinitionByProjectID, len(m.Projects))
+	for id, p := range m.Projects {
+		if exists, _ := afero.DirExists(baseFs, filepath.Join(baseDir, p.Path)); exists {
+			baseManifest.Projects[id] = p
+		}
+	}
+	if len(baseManifest.Projects) == 0 {
+		return nil, nil
+	}
+
+	projects, err := loadProjects(baseFs, filepath.Join(baseDir, filepath.Base(manifestPath)), &baseManifest, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load diff base %q: %w", diffBase, err)
+	}
+	return projects, nil
+}
+
+// openDiffBase returns the file system and folder holding the base version of the manifest's folder
+func openDiffBase(fs afero.Fs, manifestDir string, diffBase string) (afero.Fs, string, error) {
+	if isDir, _ := afero.IsDir(fs, diffBase); isDir {
+		dir, err := filepath.Abs(diffBase)
+		return fs, dir, err
+	}
+	return gitArchive(manifestDir, diffBase)
+}
+
+// gitArchive extracts dir in the version of the given git ref into the root of an in-memory file system
+func gitArchive(dir string, ref string) (afero.Fs, string, error) {
+	// run within dir, git archive only contains the subtree of dir
+	archive, err := runGit(dir, "archive", "--format=tar", ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("%q is neither a folder nor a git ref: %w", ref, err)
+	}
+
+	memFs := afero.NewMemMapFs()
+	r := tar.NewReader(bytes.NewReader(archive))
+	for {
+		h, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read archive of git ref %q: %w", ref, err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read archive of git ref %q: %w", ref, err)
+		}
+		if err := afero.WriteFile(memFs, filepath.Join("/", h.Name), content, 0644); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return memFs, "/", nil
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,146 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"os/exec"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffTestManifest = `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://example.com
+    auth:
+      token:
+        name: ENV_TOKEN
+`
+
+const diffTestConfigs = `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+- id: other-profile
+  config:
+    name: other-alerting-profile
+    template: other.json
+  type:
+    api: alerting-profile
+`
+
+func writeDiffTestProject(t *testing.T, fs afero.Fs, dir string, profileTemplate string) string {
+	files := map[string]string{
+		"manifest.yaml":                         diffTestManifest,
+		"project/alerting-profile/cfg.yaml":     diffTestConfigs,
+		"project/alerting-profile/profile.json": profileTemplate,
+		"project/alerting-profile/other.json":   "{}",
+	}
+	for name, content := range files {
+		require.NoError(t, fs.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return filepath.Join(dir, "manifest.yaml")
+}
+
+func TestSelectChangedConfigs_FromFolder(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+	fs := afero.NewMemMapFs()
+	writeDiffTestProject(t, fs, "/base", "{}")
+	manifestPath := writeDiffTestProject(t, fs, "/current", `{"changed": true}`)
+
+	m, err := loadManifest(fs, manifestPath, nil, nil, nil)
+	require.NoError(t, err)
+	projects, err := loadProjects(fs, manifestPath, m, nil)
+	require.NoError(t, err)
+
+	t.Run("changed configs are selected", func(t *testing.T) {
+		patterns, err := selectChangedConfigs(fs, manifestPath, m, projects, "/base")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"project:alerting-profile:profile"}, patterns)
+	})
+
+	t.Run("nothing is selected if nothing changed", func(t *testing.T) {
+		patterns, err := selectChangedConfigs(fs, manifestPath, m, projects, "/current")
+		require.NoError(t, err)
+		assert.Empty(t, patterns)
+	})
+
+	t.Run("all configs are selected if the project does not exist in the base", func(t *testing.T) {
+		require.NoError(t, fs.MkdirAll("/empty", 0755))
+		patterns, err := selectChangedConfigs(fs, manifestPath, m, projects, "/empty")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"project:alerting-profile:profile", "project:alerting-profile:other-profile"}, patterns)
+	})
+}
+
+func TestSelectChangedConfigs_FromGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	fs := afero.NewOsFs()
+	git("init", "-q")
+	writeDiffTestProject(t, fs, filepath.Join(repo, "monaco"), "{}")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+	manifestPath := writeDiffTestProject(t, fs, filepath.Join(repo, "monaco"), `{"changed": true}`)
+
+	m, err := loadManifest(fs, manifestPath, nil, nil, nil)
+	require.NoError(t, err)
+	projects, err := loadProjects(fs, manifestPath, m, nil)
+	require.NoError(t, err)
+
+	patterns, err := selectChangedConfigs(fs, manifestPath, m, projects, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"project:alerting-profile:profile"}, patterns)
+
+	_, err = selectChangedConfigs(fs, manifestPath, m, projects, "does-not-exist")
+	assert.ErrorContains(t, err, `"does-not-exist" is neither a folder nor a git ref`)
+}
+
+func TestEscapePattern(t *testing.T) {
+	coordinate := `project:builtin:alerting.profile:id*with?[special]\chars`
+
+	matched, err := path.Match(escapePattern(coordinate), coordinate)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, _ = path.Match(escapePattern(coordinate), "project:builtin:alerting.profile:id-with-x[s]-chars")
+	assert.False(t, matched)
+}
@@ -0,0 +1,235 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package hooks executes the pre- and post-deployment hooks defined for projects in the manifest. Hooks of a project
+// are executed once around the whole deployment, hooks restricted to a config type around the deployment to each
+// environment.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+// Status is the outcome of a deployment passed to post-deployment hooks
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Event describes the deployment a hook is executed for. It is passed to commands as environment variables, and sent
+// as JSON body to URLs.
+type Event struct {
+	Stage        manifest.HookStage `json:"stage"`
+	Project      string             `json:"project"`
+	ConfigType   string             `json:"configType,omitempty"`
+	Environments []string           `json:"environments"`
+	// Status is only set for post-deployment hooks
+	Status Status `json:"status,omitempty"`
+}
+
+func (e Event) environ() []string {
+	return []string{
+		"MONACO_HOOK_STAGE=" + string(e.Stage),
+		"MONACO_PROJECT=" + e.Project,
+		"MONACO_CONFIG_TYPE=" + e.ConfigType,
+		"MONACO_ENVIRONMENTS=" + strings.Join(e.Environments, ","),
+		"MONACO_DEPLOYMENT_STATUS=" + string(e.Status),
+	}
+}
+
+// Runner executes hooks
+type Runner struct {
+	httpClient *http.Client
+}
+
+// NewRunner creates a Runner sending HTTP hooks with a timeout of 30 seconds
+func NewRunner() *Runner {
+	return &Runner{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Run executes all hooks of the given stage defined for the given projects, except for hooks restricted to a config
+// type, which are executed around the deployment to each environment by ConfigTypeHooks. Failing hooks with failure
+// mode manifest.HookFailureWarn are only logged, all other failures are returned joined together.
+func (r *Runner) Run(ctx context.Context, stage manifest.HookStage, projects []project.Project, definitions manifest.ProjectDefinitionByProjectID, environments []string, status Status) error {
+	environments = slices.Clone(environments)
+	slices.Sort(environments)
+
+	var errs []error
+	for _, p := range projects {
+		for _, h := range definitions[p.Id].Hooks {
+			if h.Stage != stage || h.ConfigType != "" {
+				continue
+			}
+
+			e := Event{Stage: stage, Project: p.Id, Environments: environments}
+			if stage == manifest.HookStagePost {
+				e.Status = status
+			}
+			errs = append(errs, r.run(ctx, h, e))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConfigTypeHooks executes the hooks restricted to a config type before and after the deployment to each environment
+// the project deploys configurations of that type to. It implements deploy.EnvironmentHooks.
+type ConfigTypeHooks struct {
+	runner      *Runner
+	definitions manifest.ProjectDefinitionByProjectID
+}
+
+// ConfigTypeHooks creates ConfigTypeHooks executing the hooks of the given project definitions
+func (r *Runner) ConfigTypeHooks(definitions manifest.ProjectDefinitionByProjectID) *ConfigTypeHooks {
+	return &ConfigTypeHooks{runner: r, definitions: definitions}
+}
+
+// Before executes the pre-deployment hooks of all config types of the given configs
+func (h *ConfigTypeHooks) Before(ctx context.Context, environment string, configs []*config.Config) error {
+	return h.run(ctx, manifest.HookStagePre, environment, configs, "")
+}
+
+// After executes the post-deployment hooks of all config types of the given configs
+func (h *ConfigTypeHooks) After(ctx context.Context, environment string, configs []*config.Config, deployErr error) error {
+	status := StatusSucceeded
+	if deployErr != nil {
+		status = StatusFailed
+	}
+	return h.run(ctx, manifest.HookStagePost, environment, configs, status)
+}
+
+func (h *ConfigTypeHooks) run(ctx context.Context, stage manifest.HookStage, environment string, configs []*config.Config, status Status) error {
+	typesPerProject := map[string]map[string]struct{}{}
+	var projectIDs []string
+	for _, c := range configs {
+		if c.Skip {
+			continue
+		}
+		if typesPerProject[c.Coordinate.Project] == nil {
+			typesPerProject[c.Coordinate.Project] = map[string]struct{}{}
+			projectIDs = append(projectIDs, c.Coordinate.Project)
+		}
+		typesPerProject[c.Coordinate.Project][c.Coordinate.Type] = struct{}{}
+	}
+	slices.Sort(projectIDs)
+
+	var errs []error
+	for _, id := range projectIDs {
+		for _, hook := range h.definitions[id].Hooks {
+			if _, found := typesPerProject[id][hook.ConfigType]; hook.Stage != stage || !found {
+				continue
+			}
+
+			e := Event{Stage: stage, Project: id, ConfigType: hook.ConfigType, Environments: []string{environment}}
+			if stage == manifest.HookStagePost {
+				e.Status = status
+			}
+			errs = append(errs, h.runner.run(ctx, hook, e))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run executes the given hook. If it fails, the error is returned, unless the hook only warns on failures.
+func (r *Runner) run(ctx context.Context, h manifest.Hook, e Event) error {
+	log.Info("Executing %s of project %q%s", h, e.Project, environmentSuffix(e))
+	if err := r.execute(ctx, h, e); err != nil {
+		if h.OnFailure == manifest.HookFailureWarn {
+			log.WithFields(field.Error(err)).Warn("Failed to execute %s of project %q%s: %v", h, e.Project, environmentSuffix(e), err)
+			return nil
+		}
+		return fmt.Errorf("failed to execute %s of project %q%s: %w", h, e.Project, environmentSuffix(e), err)
+	}
+	return nil
+}
+
+// environmentSuffix names the environment of config type hooks in log messages, which are executed per environment
+func environmentSuffix(e Event) string {
+	if e.ConfigType == "" {
+		return ""
+	}
+	return fmt.Sprintf(" for environment %q", e.Environments[0])
+}
+
+func (r *Runner) execute(ctx context.Context, h manifest.Hook, e Event) error {
+	if h.Command != "" {
+		return RunCommand(ctx, h.Command, e.environ())
+	}
+	return r.callURL(ctx, h.URL, e)
+}
+
+// RunCommand executes the given command using the shell of the operating system. The given variables of the form
+// "KEY=value" are added to the environment of the command.
+func RunCommand(ctx context.Context, command string, environ []string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), environ...)
+
+	out, err := cmd.CombinedOutput()
+	if len(out) > 0 {
+		log.Debug("Output of hook command %q:\n%s", command, out)
+	}
+	if err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
+
+func (r *Runner) callURL(ctx context.Context, url string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("request failed (HTTP %d)", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell commands")
+	}
+
+	projects := []project.Project{
+		{
+			Id: "p",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": {"dashboard": []config.Config{{}}},
+			},
+		},
+	}
+
+	t.Run("command receives deployment as environment variables", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out")
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePost, Command: `echo -n "$MONACO_PROJECT $MONACO_ENVIRONMENTS $MONACO_DEPLOYMENT_STATUS" > ` + out, OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		err := NewRunner().Run(context.TODO(), manifest.HookStagePost, projects, definitions, []string{"env2", "env"}, StatusSucceeded)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "p env,env2 succeeded", string(content))
+	})
+
+	t.Run("only hooks of the given stage are executed", func(t *testing.T) {
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePost, Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		err := NewRunner().Run(context.TODO(), manifest.HookStagePre, projects, definitions, []string{"env"}, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("hooks restricted to a config type are not executed for the project", func(t *testing.T) {
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, ConfigType: "dashboard", Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		err := NewRunner().Run(context.TODO(), manifest.HookStagePre, projects, definitions, []string{"env"}, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("failing hooks are returned unless they only warn", func(t *testing.T) {
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+				{Stage: manifest.HookStagePre, Command: "exit 2", OnFailure: manifest.HookFailureWarn},
+			}},
+		}
+
+		err := NewRunner().Run(context.TODO(), manifest.HookStagePre, projects, definitions, []string{"env"}, "")
+		assert.ErrorContains(t, err, `command "exit 1"`)
+		assert.NotContains(t, err.Error(), "exit 2")
+	})
+
+	t.Run("URL hooks receive the event", func(t *testing.T) {
+		var received Event
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		}))
+		defer server.Close()
+
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePost, URL: server.URL, OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		err := NewRunner().Run(context.TODO(), manifest.HookStagePost, projects, definitions, []string{"env"}, StatusFailed)
+		require.NoError(t, err)
+		assert.Equal(t, Event{Stage: manifest.HookStagePost, Project: "p", Environments: []string{"env"}, Status: StatusFailed}, received)
+	})
+
+	t.Run("URL hooks fail on unsuccessful responses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, URL: server.URL, OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		err := NewRunner().Run(context.TODO(), manifest.HookStagePre, projects, definitions, []string{"env"}, "")
+		assert.ErrorContains(t, err, "HTTP 500")
+	})
+}
+
+func TestConfigTypeHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell commands")
+	}
+
+	dashboard := &config.Config{Coordinate: coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "d"}}
+	profile := &config.Config{Coordinate: coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "a"}, Skip: true}
+
+	t.Run("hooks of deployed config types receive the environment", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out")
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, ConfigType: "dashboard", Command: `echo -n "$MONACO_HOOK_STAGE $MONACO_CONFIG_TYPE $MONACO_ENVIRONMENTS" >> ` + out, OnFailure: manifest.HookFailureAbort},
+				{Stage: manifest.HookStagePost, ConfigType: "dashboard", Command: `echo -n " $MONACO_HOOK_STAGE $MONACO_DEPLOYMENT_STATUS" >> ` + out, OnFailure: manifest.HookFailureAbort},
+				{Stage: manifest.HookStagePre, Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		h := NewRunner().ConfigTypeHooks(definitions)
+		require.NoError(t, h.Before(context.TODO(), "env", []*config.Config{dashboard}))
+		require.NoError(t, h.After(context.TODO(), "env", []*config.Config{dashboard}, errors.New("failed")))
+
+		content, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "pre dashboard env post failed", string(content))
+	})
+
+	t.Run("hooks of config types not deployed to the environment are skipped", func(t *testing.T) {
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, ConfigType: "alerting-profile", Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+				{Stage: manifest.HookStagePre, ConfigType: "slo", Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+			}},
+		}
+
+		err := NewRunner().ConfigTypeHooks(definitions).Before(context.TODO(), "env", []*config.Config{dashboard, profile})
+		assert.NoError(t, err)
+	})
+
+	t.Run("failing hooks are returned unless they only warn", func(t *testing.T) {
+		definitions := manifest.ProjectDefinitionByProjectID{
+			"p": {Name: "p", Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, ConfigType: "dashboard", Command: "exit 1", OnFailure: manifest.HookFailureAbort},
+				{Stage: manifest.HookStagePre, ConfigType: "dashboard", Command: "exit 2", OnFailure: manifest.HookFailureWarn},
+			}},
+		}
+
+		err := NewRunner().ConfigTypeHooks(definitions).Before(context.TODO(), "env", []*config.Config{dashboard})
+		assert.ErrorContains(t, err, `command "exit 1" of project "p" for environment "env"`)
+		assert.NotContains(t, err.Error(), "exit 2")
+	})
+}
@@ -0,0 +1,111 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/lock"
+)
+
+// lockDocumentName is the name of the document storing the deployment lock of an environment
+const lockDocumentName = "monaco deployment lock"
+
+// lockOptions configures locking the environments deployed to
+type lockOptions struct {
+	// enabled states that a lock is acquired for every environment before deploying to it
+	enabled bool
+	// wait is the maximum duration to wait for a lock held by another deployment. If 0, the deployment fails immediately.
+	wait time.Duration
+	// ttl is the duration after which a lock expires if it is not renewed, e.g. because monaco crashed
+	ttl time.Duration
+}
+
+// acquireLocks acquires the deployment lock of every environment. Locks are acquired ordered by environment name, so
+// that deployments to overlapping sets of environments can not wait for each other forever. If a lock can not be
+// acquired, all locks acquired before are released again. The returned context is cancelled if any lock is lost, e.g.
+// because it could not be renewed in time, and the returned function releases all locks.
+func acquireLocks(ctx context.Context, clientSets dynatrace.EnvironmentClients, opts lockOptions) (context.Context, func(context.Context), error) {
+	envs := make([]dynatrace.EnvironmentInfo, 0, len(clientSets))
+	for env := range clientSets {
+		envs = append(envs, env)
+	}
+	slices.SortFunc(envs, func(a, b dynatrace.EnvironmentInfo) int { return cmp.Compare(a.Name, b.Name) })
+
+	holder := lockHolder()
+	lockedCtx, cancel := context.WithCancelCause(ctx)
+	var locks []*lock.Lock
+	release := func(ctx context.Context) {
+		defer cancel(nil)
+		for i, l := range locks {
+			if err := l.Release(ctx); err != nil {
+				log.WithFields(field.Environment(envs[i].Name, envs[i].Group), field.Error(err)).Error("Failed to release deployment lock of environment %q: %v", envs[i].Name, err)
+			}
+		}
+	}
+
+	for _, env := range envs {
+		clients := clientSets[env]
+		if clients.DocumentClient == nil {
+			release(ctx)
+			return nil, nil, fmt.Errorf("failed to lock environment %q: deployment locks require platform credentials", env.Name)
+		}
+
+		l, err := lock.New(lock.NewDocumentStore(clients.DocumentClient, lockDocumentName), holder, lock.WithWait(opts.wait), lock.WithTTL(opts.ttl)).Acquire(ctx)
+		if err != nil {
+			release(ctx)
+			var heldErr lock.HeldError
+			if errors.As(err, &heldErr) {
+				return nil, nil, fmt.Errorf("environment %q is being deployed to by another deployment: %w", env.Name, err)
+			}
+			return nil, nil, fmt.Errorf("failed to lock environment %q: %w", env.Name, err)
+		}
+		log.WithFields(field.Environment(env.Name, env.Group)).Info("Acquired deployment lock of environment %q", env.Name)
+		locks = append(locks, l)
+
+		go func(env dynatrace.EnvironmentInfo) {
+			select {
+			case <-l.Lost():
+				log.WithFields(field.Environment(env.Name, env.Group)).Error("Deployment lock of environment %q was taken over by another deployment, cancelling deployment", env.Name)
+				cancel(fmt.Errorf("deployment lock of environment %q was taken over by another deployment", env.Name))
+			case <-lockedCtx.Done():
+			}
+		}(env)
+	}
+
+	return lockedCtx, release, nil
+}
+
+// lockHolder identifies this deployment in the locks it holds
+func lockHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown host"
+	}
+	return fmt.Sprintf("monaco on %s (pid %d, %s)", host, os.Getpid(), uuid.NewString())
+}
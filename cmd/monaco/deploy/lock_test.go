@@ -0,0 +1,163 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+)
+
+// lockDocumentClient stores at most one document, which is sufficient to store a lock
+type lockDocumentClient struct {
+	client.DocumentClient
+	mu  sync.Mutex
+	doc *documents.Response
+}
+
+func (c *lockDocumentClient) List(_ context.Context, _ string) (documents.ListResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.doc == nil {
+		return documents.ListResponse{}, nil
+	}
+	return documents.ListResponse{Responses: []documents.Response{*c.doc}}, nil
+}
+
+func (c *lockDocumentClient) Get(_ context.Context, _ string) (documents.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.doc, nil
+}
+
+func (c *lockDocumentClient) Create(_ context.Context, name string, externalId string, data []byte, _ documents.DocumentType) (documents.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doc = &documents.Response{Response: api.Response{Data: data}, ID: "lock", Name: name, ExternalID: externalId}
+	return *c.doc, nil
+}
+
+func (c *lockDocumentClient) Update(_ context.Context, _ string, _ string, data []byte, _ documents.DocumentType) (documents.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doc.Data = data
+	return *c.doc, nil
+}
+
+func (c *lockDocumentClient) Delete(_ context.Context, _ string) (documents.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doc = nil
+	return documents.Response{}, nil
+}
+
+func (c *lockDocumentClient) set(doc *documents.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doc = doc
+}
+
+func (c *lockDocumentClient) get() *documents.Response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.doc
+}
+
+func heldByOther() *documents.Response {
+	return &documents.Response{
+		ID:       "other-lock",
+		Response: api.Response{Data: []byte(fmt.Sprintf(`{"holder":"other","expires":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339)))},
+	}
+}
+
+func TestAcquireLocks(t *testing.T) {
+	opts := lockOptions{enabled: true, ttl: time.Minute}
+
+	t.Run("locks all environments until released", func(t *testing.T) {
+		dev, prod := &lockDocumentClient{}, &lockDocumentClient{}
+		clientSets := dynatrace.EnvironmentClients{
+			devEnv:  &client.ClientSet{DocumentClient: dev},
+			prodEnv: &client.ClientSet{DocumentClient: prod},
+		}
+
+		ctx, release, err := acquireLocks(context.TODO(), clientSets, opts)
+		require.NoError(t, err)
+		assert.NotNil(t, dev.get())
+		assert.NotNil(t, prod.get())
+		assert.NoError(t, ctx.Err())
+
+		release(context.TODO())
+		assert.Nil(t, dev.get())
+		assert.Nil(t, prod.get())
+	})
+
+	t.Run("cancels the deployment if a lock is taken over", func(t *testing.T) {
+		dev := &lockDocumentClient{}
+		clientSets := dynatrace.EnvironmentClients{
+			devEnv: &client.ClientSet{DocumentClient: dev},
+		}
+
+		ctx, release, err := acquireLocks(context.TODO(), clientSets, lockOptions{enabled: true, ttl: 20 * time.Millisecond})
+		require.NoError(t, err)
+		defer release(context.TODO())
+		dev.set(heldByOther())
+
+		select {
+		case <-ctx.Done():
+			assert.ErrorContains(t, context.Cause(ctx), `deployment lock of environment "dev-env" was taken over by another deployment`)
+		case <-time.After(time.Second):
+			assert.Fail(t, "deployment was not cancelled")
+		}
+		assert.Equal(t, "other-lock", dev.get().ID, "a lock taken over must not be released")
+	})
+
+	t.Run("fails if an environment is locked by another deployment and releases acquired locks", func(t *testing.T) {
+		dev := &lockDocumentClient{}
+		prod := &lockDocumentClient{doc: heldByOther()}
+		clientSets := dynatrace.EnvironmentClients{
+			devEnv:  &client.ClientSet{DocumentClient: dev},
+			prodEnv: &client.ClientSet{DocumentClient: prod},
+		}
+
+		_, _, err := acquireLocks(context.TODO(), clientSets, opts)
+		assert.ErrorContains(t, err, `environment "prod-env" is being deployed to by another deployment: locked by "other"`)
+		assert.Nil(t, dev.get())
+		assert.NotNil(t, prod.get())
+	})
+
+	t.Run("fails for environments without platform credentials", func(t *testing.T) {
+		clientSets := dynatrace.EnvironmentClients{
+			devEnv: &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+		}
+
+		_, _, err := acquireLocks(context.TODO(), clientSets, opts)
+		assert.ErrorContains(t, err, "deployment locks require platform credentials")
+	})
+}
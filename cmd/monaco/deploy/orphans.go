@@ -0,0 +1,74 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+// deleteOrphans deletes the settings objects monaco deployed for one of the given projects to the given environments,
+// whose configs are no longer part of the projects
+func deleteOrphans(ctx context.Context, projects []project.Project, clientSets dynatrace.EnvironmentClients) error {
+	projectNames := make([]string, len(projects))
+	for i, p := range projects {
+		projectNames[i] = p.Id
+	}
+
+	errs := 0
+	for env, clients := range clientSets {
+		ctx := context.WithValue(ctx, log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
+		logger := log.WithCtxFields(ctx)
+
+		var known []coordinate.Coordinate
+		for _, p := range projects {
+			p.ForEveryConfigInEnvironmentDo(env.Name, func(c config.Config) {
+				known = append(known, c.Coordinate)
+			})
+		}
+
+		deleteClients := delete.ClientSet{Settings: clients.Settings()}
+		logger.Info("Collecting orphaned Settings 2.0 objects of environment %s...", env.Name)
+		orphans, err := delete.CollectOrphans(ctx, deleteClients, projectNames, known)
+		if err != nil {
+			logger.Error("Failed to collect all orphaned Settings 2.0 objects of environment %s: %v", env.Name, err)
+			errs++
+		}
+		if len(orphans) == 0 {
+			continue
+		}
+
+		for _, o := range orphans {
+			logger.Info("Deleting orphaned settings object %s of schema %q", o.ID, o.Type)
+		}
+		if err := delete.Objects(ctx, deleteClients, nil, orphans); err != nil {
+			logger.Error("Failed to delete all orphaned Settings 2.0 objects of environment %s: %v", env.Name, err)
+			errs++
+		}
+	}
+
+	if errs > 0 {
+		return fmt.Errorf("failed to delete orphaned configurations of %d environment(s)", errs)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteOrphans(t *testing.T) {
+	externalID := func(c coordinate.Coordinate) string {
+		id, err := idutils.GenerateExternalIDForSettingsObject(c)
+		require.NoError(t, err)
+		return id
+	}
+
+	kept := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "kept"}
+	removed := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "removed"}
+	otherProject := coordinate.Coordinate{Project: "other", Type: "builtin:alerting.profile", ConfigId: "removed"}
+	legacy := coordinate.Coordinate{Type: "builtin:alerting.profile", ConfigId: "legacy"}
+
+	projects := []project.Project{{
+		Id: "project",
+		Configs: project.ConfigsPerTypePerEnvironments{
+			devEnv.Name: {"builtin:alerting.profile": {config.Config{Coordinate: kept}}},
+		},
+	}}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(dtclient.SchemaList{{SchemaId: "builtin:alerting.profile"}}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+		objects := []dtclient.DownloadSettingsObject{
+			{ObjectId: "kept", ExternalId: externalID(kept)},
+			{ObjectId: "removed", ExternalId: externalID(removed)},
+			{ObjectId: "other-project", ExternalId: externalID(otherProject)},
+			{ObjectId: "legacy", ExternalId: externalID(legacy)},
+			{ObjectId: "not-monaco", ExternalId: "terraform-123"},
+		}
+		var result []dtclient.DownloadSettingsObject
+		for _, o := range objects {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+	c.EXPECT().DeleteSettings("removed").Return(nil)
+
+	err := deleteOrphans(context.TODO(), projects, dynatrace.EnvironmentClients{devEnv: &client.ClientSet{DTClient: c}})
+	assert.NoError(t, err)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	configwriter "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/writer"
 	"net/url"
 	"path"
 
@@ -59,6 +60,8 @@ type downloadOptionsShared struct {
 	outputFolder           string
 	projectName            string
 	forceOverwriteManifest bool
+	// outputLayout is the name of the configwriter.Layout used to write the project. If empty, the flat layout is used.
+	outputLayout string
 }
 
 func writeConfigs(downloadedConfigs project.ConfigsPerType, opts downloadOptionsShared, fs afero.Fs) error {
@@ -70,6 +73,7 @@ func writeConfigs(downloadedConfigs project.ConfigsPerType, opts downloadOptions
 		Auth:           opts.auth,
 		OutputFolder:   opts.outputFolder,
 		ForceOverwrite: opts.forceOverwriteManifest,
+		Layout:         configwriter.Layouts[opts.outputLayout],
 	}
 	err := download.WriteToDisk(fs, downloadWriterContext)
 	if err != nil {
@@ -17,8 +17,10 @@ package download
 import (
 	"context"
 	"errors"
+	configwriter "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/writer"
 	"net/http"
 
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
@@ -67,6 +69,7 @@ func GetDownloadCommand(fs afero.Fs, command Command) (cmd *cobra.Command) {
 	// download via manifest
 	cmd.Flags().StringVarP(&f.manifestFile, "manifest", "m", "manifest.yaml", "Name (and the path) to the manifest file. Defaults to 'manifest.yaml'.")
 	cmd.Flags().StringVarP(&f.specificEnvironmentName, "environment", "e", "", "Specify an environment defined in the manifest to download the configurations.")
+	cmdutils.AddVariablesFlag(cmd, &f.variables)
 	// download without manifest
 	cmd.Flags().StringVar(&f.environmentURL, "url", "", "URL to the Dynatrace environment from which to download the configuration. "+
 		"To be able to connect to any Dynatrace environment, an API-Token needs to be provided using '--token'. "+
@@ -77,13 +80,52 @@ func GetDownloadCommand(fs afero.Fs, command Command) (cmd *cobra.Command) {
 	cmd.Flags().StringVar(&f.clientSecret, "oauth-client-secret", "", "OAuth client secret environment variable. Required when using the flag '--url' and connecting to a Dynatrace Platform.")
 
 	// download options
-	cmd.Flags().StringSliceVarP(&f.specificAPIs, "api", "a", nil, "Download one or more classic configuration APIs, including deprecated ones. (Repeat flag or use comma-separated values)")
-	cmd.Flags().StringSliceVarP(&f.specificSchemas, "settings-schema", "s", nil, "Download settings 2.0 objects of one or more settings 2.0 schemas. (Repeat flag or use comma-separated values)")
+	cmd.Flags().StringSliceVarP(&f.specificAPIs, "api", "a", nil, "Download one or more classic configuration APIs, including deprecated ones. Glob patterns like 'alerting-*' are supported. (Repeat flag or use comma-separated values)")
+	cmd.Flags().StringSliceVarP(&f.specificSchemas, "settings-schema", "s", nil, "Download settings 2.0 objects of one or more settings 2.0 schemas. Glob patterns like 'builtin:anomaly-detection.*' are supported. (Repeat flag or use comma-separated values)")
+	cmd.Flags().StringSliceVar(&f.excludeAPIs, "exclude-api", nil, "Do not download the classic configuration APIs matching the given names or glob patterns. (Repeat flag or use comma-separated values)")
+	cmd.Flags().StringSliceVar(&f.excludeSchemas, "exclude-schema", nil, "Do not download settings 2.0 objects of the schemas matching the given IDs or glob patterns, e.g. 'builtin:anomaly-detection.rum*'. (Repeat flag or use comma-separated values)")
 	cmd.Flags().BoolVar(&f.onlyAPIs, "only-apis", false, "Download only classic configuration APIs. Deprecated configuration APIs will not be included.")
 	cmd.Flags().BoolVar(&f.onlySettings, "only-settings", false, "Download only settings 2.0 objects")
 	cmd.Flags().BoolVar(&f.onlyAutomation, "only-automation", false, "Only download automation objects, skip all other configuration types")
+	cmd.Flags().BoolVar(&f.includeSegmentsAndOpenPipeline, "include-segments-and-openpipeline", false, "Also download Grail segments and OpenPipeline configurations. "+
+		"They can not be deployed by monaco yet, thus they are not downloaded by default, and deploying a project containing them fails before anything is deployed.")
+	cmd.Flags().StringSliceVar(&f.filterScopes, "filter-scope", nil, "Only download settings 2.0 objects and classic configurations of the given scopes, e.g. 'environment' or 'HOST-1234567890'. "+
+		"Scopes may contain wildcards, e.g. 'HOST-*'. Classic configurations of APIs with a parent, e.g. key user actions, are scoped to their parent entity, all others to 'environment'. "+
+		"Automation resources, buckets and documents are not filtered. (Repeat flag or use comma-separated values)")
+	cmd.Flags().StringSliceVar(&f.filterManagementZones, "filter-management-zone", nil, "Only download settings 2.0 objects and classic configurations belonging to the management zones with the given names or numeric IDs. "+
+		"A configuration belongs to a management zone if it is the management zone itself or references it, e.g. by its ID or in an entity selector. "+
+		"Automation resources, buckets and documents are not filtered. (Repeat flag or use comma-separated values)")
+	cmd.Flags().BoolVar(&f.incremental, "incremental", false, "Only fetch settings 2.0 objects that changed since the last incremental download into the same project folder, and update the existing project folder. "+
+		"Requires '--output-folder'. Classic configurations, automation resources, buckets and documents are always downloaded completely.")
+
+	cmd.Flags().StringVar(&f.mergeProject, "merge", "", "Merge the downloaded configurations into the given existing project folder instead of creating a new project. "+
+		"Existing configurations whose JSON did not change keep their parameters, drifted configurations are replaced by the downloaded ones. "+
+		"The project must have the folder structure created by download, environment and group overrides are not preserved.")
+
+	cmd.Flags().StringVar(&f.outputLayout, "output-layout", configwriter.FlatLayoutName, "Layout of the created project folder. "+
+		"'flat' creates a folder per API or settings schema, 'hierarchical' groups these folders by kind of configuration and settings schemas additionally by their category, e.g. 'settings/alerting/builtinalerting.profile'.")
+
+	cmd.Flags().BoolVar(&f.dryRun, "dry-run", false, "Only list the classic configuration APIs and settings schemas that would be downloaded, with the number of objects and their estimated size, without writing anything. "+
+		"Only the list endpoints are queried. Automation resources, buckets and documents are not included.")
+
+	cmd.Flags().BoolVar(&f.resume, "resume", false, "Continue a previous download into the same output folder that failed midway, without fetching the already downloaded classic configurations and settings schemas again. "+
+		"Requires '--output-folder' or '--merge'. The progress of every download into an output folder is recorded in a checkpoint file, which is removed once the download completed.")
+
+	cmd.Flags().BoolVar(&f.onlyMonacoManaged, "only-monaco-managed", false, "Only keep configurations that were deployed by monaco. "+
+		"Settings objects and documents are identified by the external ID monaco generates for them, dashboards and SLOs by the 'managed-by:monaco' ownership tag. "+
+		"Configurations of all other types are skipped.")
+
+	cmd.Flags().StringVar(&f.extractionRulesFile, "extraction-rules", "", "YAML file of rules defining which JSON fields of the downloaded templates to extract into parameters, e.g. "+
+		"'{field: displayName, parameter: name}' to extract the display name as 'name' parameter, or '{field: managementZoneId, as: reference}' to extract management zone IDs as references to the downloaded management zones.")
 
 	// combinations
+	cmd.MarkFlagsMutuallyExclusive("resume", "incremental")
+	cmd.MarkFlagsMutuallyExclusive("resume", "dry-run")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "merge")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "incremental")
+	cmd.MarkFlagsMutuallyExclusive("merge", "output-folder")
+	cmd.MarkFlagsMutuallyExclusive("merge", "project")
+	cmd.MarkFlagsMutuallyExclusive("merge", "incremental")
 	cmd.MarkFlagsMutuallyExclusive("settings-schema", "only-apis", "only-settings", "only-automation")
 	cmd.MarkFlagsMutuallyExclusive("api", "only-apis", "only-settings", "only-automation")
 
@@ -112,10 +154,16 @@ func GetDownloadCommand(fs afero.Fs, command Command) (cmd *cobra.Command) {
 
 func preRunChecks(f downloadCmdOptions) error {
 	switch {
+	case f.incremental && f.outputFolder == "":
+		return errors.New("'incremental' requires 'output-folder' to be set")
+	case f.resume && f.outputFolder == "" && f.mergeProject == "":
+		return errors.New("'resume' requires 'output-folder' or 'merge' to be set")
 	case f.environmentURL != "" && f.manifestFile != "manifest.yaml":
 		return errors.New("'url' and 'manifest' are mutually exclusive")
 	case f.environmentURL != "" && f.specificEnvironmentName != "":
 		return errors.New("'environment' is specific to manifest-based download and incompatible with direct download from 'url'")
+	case f.environmentURL != "" && len(f.variables) > 0:
+		return errors.New("'var' is specific to manifest-based download and incompatible with direct download from 'url'")
 	case f.environmentURL != "":
 		switch {
 		case f.token == "":
@@ -17,6 +17,7 @@
 package download
 
 import (
+	configwriter "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/writer"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -38,6 +39,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			manifestFile:             "path/to/my-manifest.yaml",
 			specificEnvironmentName:  "my-environment1",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 		}
 		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
 
@@ -53,6 +55,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			manifestFile:             "manifest.yaml",
 			specificEnvironmentName:  "my-environment",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 		}
 		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
 
@@ -73,6 +76,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			environmentURL:           "http://some.url",
 			auth:                     auth{token: "TOKEN"},
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 		}
 		m.EXPECT().DownloadConfigs(gomock.Any(), expected).Return(nil)
 
@@ -92,6 +96,7 @@ func TestGetDownloadCommand(t *testing.T) {
 				clientSecret: "CLIENT_SECRET",
 			},
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 		}
 		m.EXPECT().DownloadConfigs(gomock.Any(), expected).Return(nil)
 
@@ -125,10 +130,13 @@ func TestGetDownloadCommand(t *testing.T) {
 				outputFolder:   "path/to/my-folder",
 				forceOverwrite: true,
 			},
+			outputLayout:        configwriter.HierarchicalLayoutName,
+			onlyMonacoManaged:   true,
+			extractionRulesFile: "rules.yaml",
 		}
 		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
 
-		err := m.download("--manifest path/my-manifest.yaml --environment my-environment --project my-project --output-folder path/to/my-folder --force true")
+		err := m.download("--manifest path/my-manifest.yaml --environment my-environment --project my-project --output-folder path/to/my-folder --force true --output-layout hierarchical --only-monaco-managed --extraction-rules rules.yaml")
 
 		assert.NoError(t, err)
 	})
@@ -140,6 +148,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			manifestFile:             "manifest.yaml",
 			specificEnvironmentName:  "my_environment",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 		}
 		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
 
@@ -154,6 +163,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			manifestFile:             "manifest.yaml",
 			specificEnvironmentName:  "myEnvironment",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 			specificAPIs:             []string{"test", "test2", "test3", "test4"},
 		}
 		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
@@ -167,6 +177,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			environmentURL:           "test.url",
 			auth:                     auth{token: "token"},
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 			onlyAPIs:                 true,
 		}
 
@@ -196,6 +207,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			manifestFile:             "manifest.yaml",
 			specificEnvironmentName:  "myEnvironment",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 			specificSchemas:          []string{"settings:schema:1", "settings:schema:2", "settings:schema:3", "settings:schema:4"},
 		}
 		m := newMonaco(t)
@@ -210,6 +222,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			environmentURL:           "test.url",
 			auth:                     auth{token: "token"},
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
+			outputLayout:             configwriter.FlatLayoutName,
 			onlySettings:             true,
 		}
 
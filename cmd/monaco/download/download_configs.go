@@ -15,23 +15,40 @@
 package download
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	configwriter "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/writer"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/checkpoint"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/classic"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/dependency_resolution"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/document"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/estimate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/extraction"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/id_extraction"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/incremental"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/managed"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/openpipeline"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/secret_redaction"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/segment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/settings"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
@@ -44,14 +61,27 @@ type downloadCmdOptions struct {
 	sharedDownloadCmdOptions
 	environmentURL string
 	auth
-	manifestFile            string
-	specificEnvironmentName string
-	specificAPIs            []string
-	specificSchemas         []string
-	onlyAPIs                bool
-	onlySettings            bool
-	onlyAutomation          bool
-	onlyDocuments           bool
+	manifestFile                   string
+	variables                      map[string]string
+	specificEnvironmentName        string
+	specificAPIs                   []string
+	specificSchemas                []string
+	excludeAPIs                    []string
+	excludeSchemas                 []string
+	onlyAPIs                       bool
+	onlySettings                   bool
+	onlyAutomation                 bool
+	onlyDocuments                  bool
+	includeSegmentsAndOpenPipeline bool
+	filterScopes                   []string
+	filterManagementZones          []string
+	incremental                    bool
+	mergeProject                   string
+	outputLayout                   string
+	dryRun                         bool
+	resume                         bool
+	onlyMonacoManaged              bool
+	extractionRulesFile            string
 }
 
 type auth struct {
@@ -100,7 +130,10 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 		Fs:           fs,
 		ManifestPath: cmdOptions.manifestFile,
 		Environments: []string{cmdOptions.specificEnvironmentName},
+		Variables:    cmdOptions.variables,
 		Opts:         manifestloader.Options{RequireEnvironmentGroups: true},
+
+		EnvironmentDiscoverer: dynatrace.AccountEnvironmentDiscoverer{},
 	})
 	if len(errs) > 0 {
 		err := printAndFormatErrors(errs, "failed to load manifest '%v'", cmdOptions.manifestFile)
@@ -119,9 +152,10 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 
 	printUploadToSameEnvironmentWarning(env)
 
-	if !cmdOptions.forceOverwrite {
+	if !cmdOptions.forceOverwrite && cmdOptions.mergeProject == "" {
 		cmdOptions.projectName = fmt.Sprintf("%s_%s", cmdOptions.projectName, cmdOptions.specificEnvironmentName)
 	}
+	cmdOptions = withMergeTarget(cmdOptions)
 
 	options := downloadConfigsOptions{
 		downloadOptionsShared: downloadOptionsShared{
@@ -129,14 +163,26 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 			auth:                   env.Auth,
 			outputFolder:           cmdOptions.outputFolder,
 			projectName:            cmdOptions.projectName,
-			forceOverwriteManifest: cmdOptions.forceOverwrite,
+			forceOverwriteManifest: cmdOptions.forceOverwrite || cmdOptions.incremental,
+			outputLayout:           cmdOptions.outputLayout,
 		},
-		specificAPIs:    cmdOptions.specificAPIs,
-		specificSchemas: cmdOptions.specificSchemas,
-		onlyAPIs:        cmdOptions.onlyAPIs,
-		onlySettings:    cmdOptions.onlySettings,
-		onlyAutomation:  cmdOptions.onlyAutomation,
-		onlyDocuments:   cmdOptions.onlyDocuments,
+		specificAPIs:                   cmdOptions.specificAPIs,
+		specificSchemas:                cmdOptions.specificSchemas,
+		excludeAPIs:                    cmdOptions.excludeAPIs,
+		excludeSchemas:                 cmdOptions.excludeSchemas,
+		onlyAPIs:                       cmdOptions.onlyAPIs,
+		onlySettings:                   cmdOptions.onlySettings,
+		onlyAutomation:                 cmdOptions.onlyAutomation,
+		onlyDocuments:                  cmdOptions.onlyDocuments,
+		includeSegmentsAndOpenPipeline: cmdOptions.includeSegmentsAndOpenPipeline,
+		filterScopes:                   cmdOptions.filterScopes,
+		filterMZs:                      cmdOptions.filterManagementZones,
+		incremental:                    cmdOptions.incremental,
+		mergeProject:                   cmdOptions.mergeProject,
+		dryRun:                         cmdOptions.dryRun,
+		resume:                         cmdOptions.resume,
+		onlyMonacoManaged:              cmdOptions.onlyMonacoManaged,
+		extractionRulesFile:            cmdOptions.extractionRulesFile,
 	}
 
 	if errs := options.valid(); len(errs) != 0 {
@@ -144,7 +190,7 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 		return err
 	}
 
-	clientSet, err := dynatrace.CreateClients(options.environmentURL, options.auth)
+	clientSet, err := dynatrace.CreateClientsForEnvironment(env)
 	if err != nil {
 		return err
 	}
@@ -153,6 +199,7 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 }
 
 func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptions) error {
+	cmdOptions = withMergeTarget(cmdOptions)
 	a, errs := cmdOptions.auth.mapToAuth()
 	errs = append(errs, validateParameters(cmdOptions.environmentURL, cmdOptions.projectName)...)
 
@@ -166,14 +213,26 @@ func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptio
 			auth:                   *a,
 			outputFolder:           cmdOptions.outputFolder,
 			projectName:            cmdOptions.projectName,
-			forceOverwriteManifest: cmdOptions.forceOverwrite,
+			forceOverwriteManifest: cmdOptions.forceOverwrite || cmdOptions.incremental,
+			outputLayout:           cmdOptions.outputLayout,
 		},
-		specificAPIs:    cmdOptions.specificAPIs,
-		specificSchemas: cmdOptions.specificSchemas,
-		onlyAPIs:        cmdOptions.onlyAPIs,
-		onlySettings:    cmdOptions.onlySettings,
-		onlyAutomation:  cmdOptions.onlyAutomation,
-		onlyDocuments:   cmdOptions.onlyDocuments,
+		specificAPIs:                   cmdOptions.specificAPIs,
+		specificSchemas:                cmdOptions.specificSchemas,
+		excludeAPIs:                    cmdOptions.excludeAPIs,
+		excludeSchemas:                 cmdOptions.excludeSchemas,
+		onlyAPIs:                       cmdOptions.onlyAPIs,
+		onlySettings:                   cmdOptions.onlySettings,
+		onlyAutomation:                 cmdOptions.onlyAutomation,
+		onlyDocuments:                  cmdOptions.onlyDocuments,
+		includeSegmentsAndOpenPipeline: cmdOptions.includeSegmentsAndOpenPipeline,
+		filterScopes:                   cmdOptions.filterScopes,
+		filterMZs:                      cmdOptions.filterManagementZones,
+		incremental:                    cmdOptions.incremental,
+		mergeProject:                   cmdOptions.mergeProject,
+		dryRun:                         cmdOptions.dryRun,
+		resume:                         cmdOptions.resume,
+		onlyMonacoManaged:              cmdOptions.onlyMonacoManaged,
+		extractionRulesFile:            cmdOptions.extractionRulesFile,
 	}
 
 	if errs := options.valid(); len(errs) != 0 {
@@ -189,25 +248,121 @@ func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptio
 	return doDownloadConfigs(fs, clientSet, prepareAPIs(api.NewAPIs(), options), options)
 }
 
+// logRedactedSecrets lists the environment variables that need to be set to deploy the downloaded configs
+func logRedactedSecrets(secrets []secret_redaction.Secret) {
+	if len(secrets) == 0 {
+		return
+	}
+	log.Warn("%d secret values were replaced by environment variable parameters. Set the following environment variables before deploying:", len(secrets))
+	for _, s := range secrets {
+		log.WithFields(field.Coordinate(s.Coordinate), field.F("envVar", s.EnvVar)).Warn("\t%s (field %q of %s)", s.EnvVar, s.Field, s.Coordinate)
+	}
+}
+
+// withMergeTarget sets the output folder and project name to the ones of the project to merge into, if any
+func withMergeTarget(cmdOptions downloadCmdOptions) downloadCmdOptions {
+	if cmdOptions.mergeProject != "" {
+		cmdOptions.mergeProject = filepath.Clean(cmdOptions.mergeProject)
+		cmdOptions.outputFolder = filepath.Dir(cmdOptions.mergeProject)
+		cmdOptions.projectName = filepath.Base(cmdOptions.mergeProject)
+	}
+	return cmdOptions
+}
+
 func doDownloadConfigs(fs afero.Fs, clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions) error {
+	if opts.dryRun {
+		log.Info("Estimating download from environment '%v'", opts.environmentURL)
+		entries, err := estimateDownload(clientSet, apisToDownload, opts, defaultDownloadFn)
+		if err != nil {
+			return err
+		}
+		estimate.Log(entries)
+		return nil
+	}
+
 	err := preDownloadValidations(fs, opts.downloadOptionsShared)
 	if err != nil {
 		return err
 	}
 
+	if opts.extractionRulesFile != "" {
+		opts.extractionRules, err = extraction.LoadRules(fs, opts.extractionRulesFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	var existingConfigs []config.Config
+	if opts.mergeProject != "" {
+		existingConfigs, err = download.LoadProjectToMerge(fs, opts.mergeProject)
+		if err != nil {
+			return err
+		}
+	}
+
+	stateFile := filepath.Join(opts.outputFolder, opts.projectName, incremental.StateFileName)
+	if opts.incremental {
+		opts.incrementalState, err = incremental.LoadState(fs, stateFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.outputFolder != "" && !opts.incremental {
+		checkpointFile := filepath.Join(opts.outputFolder, checkpoint.FileName)
+		if opts.resume {
+			opts.checkpoint, err = checkpoint.Load(fs, checkpointFile)
+			if err != nil {
+				return err
+			}
+		} else {
+			opts.checkpoint = checkpoint.New(fs, checkpointFile)
+		}
+	}
+
 	log.Info("Downloading from environment '%v' into project '%v'", opts.environmentURL, opts.projectName)
 	downloadedConfigs, err := downloadConfigs(clientSet, apisToDownload, opts, defaultDownloadFn)
+	if err == nil && opts.onlyMonacoManaged {
+		log.Info("Keeping only configurations managed by monaco")
+		downloadedConfigs = managed.Filter(downloadedConfigs, apisToDownload)
+	}
+	if err == nil {
+		err = persistDownloadedConfigs(fs, downloadedConfigs, existingConfigs, stateFile, opts)
+	}
+
+	if opts.checkpoint == nil {
+		return err
+	}
 	if err != nil {
+		if saveErr := opts.checkpoint.Save(); saveErr != nil {
+			log.Warn("Failed to save download checkpoint: %v", saveErr)
+		} else {
+			log.Info("Download progress was saved. Run the download again with '--resume' to continue where it stopped.")
+		}
 		return err
 	}
+	return opts.checkpoint.Remove()
+}
 
+// persistDownloadedConfigs resolves the dependencies between the downloaded configs and writes them to disk, or merges
+// them into the project to merge into
+func persistDownloadedConfigs(fs afero.Fs, downloadedConfigs project.ConfigsPerType, existingConfigs []config.Config, stateFile string, opts downloadConfigsOptions) error {
 	if len(downloadedConfigs) == 0 {
 		log.Info("No configurations downloaded. No project will be created.")
 		return nil
 	}
 
+	if len(opts.extractionRules) > 0 {
+		log.Info("Applying extraction rules")
+		var err error
+		downloadedConfigs, err = extraction.ApplyRules(downloadedConfigs, opts.extractionRules)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Info("Resolving dependencies between configurations")
-	downloadedConfigs, err = dependency_resolution.ResolveDependencies(downloadedConfigs)
+	downloadedConfigs, err := dependency_resolution.ResolveDependencies(downloadedConfigs)
 	if err != nil {
 		return err
 	}
@@ -219,30 +374,70 @@ func doDownloadConfigs(fs afero.Fs, clientSet *client.ClientSet, apisToDownload
 		return err
 	}
 
-	return writeConfigs(downloadedConfigs, opts.downloadOptionsShared, fs)
+	if featureflags.DownloadRedactSecrets().Enabled() {
+		log.Info("Replacing secrets with environment variable parameters")
+		var secrets []secret_redaction.Secret
+		downloadedConfigs, secrets, err = secret_redaction.RedactSecretsIntoEnvParameters(downloadedConfigs)
+		if err != nil {
+			return err
+		}
+		logRedactedSecrets(secrets)
+	}
+
+	if opts.mergeProject != "" {
+		log.Info("Merging downloaded configurations into project %q", opts.mergeProject)
+		return download.WriteMergedProject(fs, opts.mergeProject, download.Merge(downloadedConfigs, existingConfigs), configwriter.Layouts[opts.outputLayout])
+	}
+
+	if err := writeConfigs(downloadedConfigs, opts.downloadOptionsShared, fs); err != nil {
+		return err
+	}
+
+	if opts.incrementalState != nil {
+		return opts.incrementalState.Write(fs, stateFile)
+	}
+	return nil
 }
 
 type downloadFn struct {
-	classicDownload    func(client.ConfigClient, string, api.APIs, classic.ContentFilters) (projectv2.ConfigsPerType, error)
-	settingsDownload   func(client.SettingsClient, string, settings.Filters, ...config.SettingsType) (projectv2.ConfigsPerType, error)
-	automationDownload func(client.AutomationClient, string, ...config.AutomationType) (projectv2.ConfigsPerType, error)
-	bucketDownload     func(client.BucketClient, string) (projectv2.ConfigsPerType, error)
-	documentDownload   func(client.DocumentClient, string) (projectv2.ConfigsPerType, error)
+	classicDownload      func(client.ConfigClient, string, api.APIs, classic.ContentFilters, scope.Filter) (projectv2.ConfigsPerType, error)
+	settingsDownload     func(client.SettingsClient, string, settings.Filters, scope.Filter, ...config.SettingsType) (projectv2.ConfigsPerType, error)
+	automationDownload   func(client.AutomationClient, string, ...config.AutomationType) (projectv2.ConfigsPerType, error)
+	bucketDownload       func(client.BucketClient, string) (projectv2.ConfigsPerType, error)
+	segmentDownload      func(client.SegmentClient, string) (projectv2.ConfigsPerType, error)
+	openPipelineDownload func(client.OpenPipelineClient, string) (projectv2.ConfigsPerType, error)
+	documentDownload     func(client.DocumentClient, string) (projectv2.ConfigsPerType, error)
 }
 
 var defaultDownloadFn = downloadFn{
-	classicDownload:    classic.Download,
-	settingsDownload:   settings.Download,
-	automationDownload: automation.Download,
-	bucketDownload:     bucket.Download,
-	documentDownload:   document.Download,
+	classicDownload:      classic.Download,
+	settingsDownload:     settings.Download,
+	automationDownload:   automation.Download,
+	bucketDownload:       bucket.Download,
+	segmentDownload:      segment.Download,
+	openPipelineDownload: openpipeline.Download,
+	documentDownload:     document.Download,
 }
 
 func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions, fn downloadFn) (project.ConfigsPerType, error) {
 	configs := make(project.ConfigsPerType)
 
-	if shouldDownloadConfigs(opts) {
-		classicCfgs, err := fn.classicDownload(clientSet.Classic(), opts.projectName, prepareAPIs(apisToDownload, opts), classic.ApiContentFilters)
+	scopeFilter, err := makeScopeFilter(clientSet.Settings(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if shouldDownloadConfigs(opts) && opts.auth.HasOAuth() && !opts.auth.HasToken() {
+		if opts.onlyAPIs {
+			return nil, errors.New("can't download configurations of classic Config APIs: no access token configured")
+		}
+		log.Warn("No access token configured - configurations of classic Config APIs are not downloaded")
+	} else if shouldDownloadConfigs(opts) {
+		var classicClient client.ConfigClient = clientSet.Classic()
+		if opts.checkpoint != nil {
+			classicClient = checkpoint.NewConfigClient(classicClient, opts.checkpoint)
+		}
+		classicCfgs, err := fn.classicDownload(classicClient, opts.projectName, prepareAPIs(apisToDownload, opts), classic.ApiContentFilters, scopeFilter)
 		if err != nil {
 			return nil, err
 		}
@@ -251,7 +446,18 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 
 	if shouldDownloadSettings(opts) {
 		log.Info("Downloading settings objects")
-		settingCfgs, err := fn.settingsDownload(clientSet.Settings(), opts.projectName, settings.DefaultSettingsFilters, makeSettingTypes(opts.specificSchemas)...)
+		var settingsClient client.SettingsClient = clientSet.Settings()
+		if opts.incrementalState != nil {
+			settingsClient = incremental.NewSettingsClient(settingsClient, opts.incrementalState)
+		}
+		if opts.checkpoint != nil {
+			settingsClient = checkpoint.NewSettingsClient(settingsClient, opts.checkpoint)
+		}
+		schemaIDs, err := selectSchemas(settingsClient, opts.specificSchemas, opts.excludeSchemas)
+		if err != nil {
+			return nil, err
+		}
+		settingCfgs, err := fn.settingsDownload(settingsClient, opts.projectName, settings.DefaultSettingsFilters, scopeFilter, makeSettingTypes(schemaIDs)...)
 		if err != nil {
 			return nil, err
 		}
@@ -280,6 +486,22 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 		copyConfigs(configs, bucketCfgs)
 	}
 
+	if shouldDownloadBuckets(opts) && opts.auth.OAuth != nil && opts.includeSegmentsAndOpenPipeline {
+		log.Info("Downloading Grail segments")
+		segmentCfgs, err := fn.segmentDownload(clientSet.Segment(), opts.projectName)
+		if err != nil {
+			return nil, err
+		}
+		copyConfigs(configs, segmentCfgs)
+
+		log.Info("Downloading OpenPipeline configurations")
+		openPipelineCfgs, err := fn.openPipelineDownload(clientSet.OpenPipeline(), opts.projectName)
+		if err != nil {
+			return nil, err
+		}
+		copyConfigs(configs, openPipelineCfgs)
+	}
+
 	if featureflags.Documents().Enabled() {
 		if shouldDownloadDocuments(opts) {
 			if opts.auth.OAuth != nil {
@@ -298,6 +520,111 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 	return configs, nil
 }
 
+// estimateDownload lists the classic configs and settings objects that would be downloaded, using the list endpoints
+// only. Automation resources, buckets and documents are not estimated.
+func estimateDownload(clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions, fn downloadFn) ([]estimate.Entry, error) {
+	scopeFilter, err := makeScopeFilter(clientSet.Settings(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []estimate.Entry
+	if shouldDownloadConfigs(opts) {
+		log.Info("Listing classic configurations")
+		entries = append(entries, classic.Estimate(clientSet.Classic(), prepareAPIs(apisToDownload, opts), classic.ApiContentFilters, scopeFilter)...)
+	}
+
+	if shouldDownloadSettings(opts) {
+		log.Info("Listing settings objects")
+		schemaIDs, err := selectSchemas(clientSet.Settings(), opts.specificSchemas, opts.excludeSchemas)
+		if err != nil {
+			return nil, err
+		}
+		// settings are downloaded using their list endpoint anyway, so the download itself gives the exact result
+		settingCfgs, err := fn.settingsDownload(clientSet.Settings(), opts.projectName, settings.DefaultSettingsFilters, scopeFilter, makeSettingTypes(schemaIDs)...)
+		if err != nil {
+			return nil, err
+		}
+		settingEntries, err := estimate.FromConfigs(settingCfgs)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, settingEntries...)
+	}
+
+	if shouldDownloadAutomationResources(opts) || shouldDownloadBuckets(opts) || shouldDownloadDocuments(opts) {
+		log.Info("Automation resources, buckets and documents are not included in the estimation")
+	}
+	return entries, nil
+}
+
+// makeScopeFilter creates the filter restricting the download of settings and classic configs to the scopes and
+// management zones given by the options
+func makeScopeFilter(c client.SettingsClient, opts downloadConfigsOptions) (scope.Filter, error) {
+	mzs, err := scope.ResolveManagementZones(context.TODO(), c, opts.filterMZs)
+	if err != nil {
+		return scope.Filter{}, fmt.Errorf("failed to resolve management zones to filter by: %w", err)
+	}
+	f := scope.Filter{Scopes: opts.filterScopes, ManagementZones: mzs}
+	if f.IsSet() {
+		log.Info("Only downloading settings and classic configurations of scopes %q and management zones %q. Automation resources, buckets and documents are not filtered.", opts.filterScopes, opts.filterMZs)
+	}
+	return f, nil
+}
+
+// selectSchemas resolves the glob patterns of included and excluded schemas to the IDs of the schemas to download.
+// If neither patterns nor exclusions are given, the included schemas are returned as they are.
+func selectSchemas(c client.SettingsClient, include, exclude []string) ([]string, error) {
+	if len(exclude) == 0 && !slices.ContainsFunc(include, isPattern) {
+		return include, nil
+	}
+
+	schemaList, err := c.ListSchemas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings schemas: %w", err)
+	}
+
+	matched := make(map[string]bool, len(include))
+	var schemaIDs []string
+	for _, s := range schemaList {
+		included := len(include) == 0
+		for _, p := range include {
+			if ok, _ := path.Match(p, s.SchemaId); ok {
+				matched[p] = true
+				included = true
+			}
+		}
+		if included && !matchesAnyPattern(s.SchemaId, exclude) {
+			schemaIDs = append(schemaIDs, s.SchemaId)
+		}
+	}
+
+	for _, p := range include {
+		if !matched[p] {
+			return nil, fmt.Errorf("settings schema %q provided via \"--settings-schema\" flag does not match any known schema", p)
+		}
+	}
+	if len(schemaIDs) == 0 {
+		return nil, errors.New("all settings schemas are excluded from download")
+	}
+
+	log.Debug("Settings schemas selected for download: %v", schemaIDs)
+	return schemaIDs, nil
+}
+
+func isPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[\\")
+}
+
+func matchesAnyPattern(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func makeSettingTypes(specificSchemas []string) []config.SettingsType {
 	var settingTypes []config.SettingsType
 	for _, schema := range specificSchemas {
@@ -328,7 +655,8 @@ func shouldDownloadAutomationResources(opts downloadConfigsOptions) bool {
 		!opts.onlyAPIs && len(opts.specificAPIs) == 0 && !opts.onlyDocuments
 }
 
-// shouldDownloadBuckets returns true if download is not limited to another specific type
+// shouldDownloadBuckets returns true if download is not limited to another specific type. Segments and OpenPipeline
+// configurations are downloaded together with buckets, if they are included explicitly.
 func shouldDownloadBuckets(opts downloadConfigsOptions) bool {
 	return !opts.onlySettings && len(opts.specificSchemas) == 0 && // only settings requested
 		!opts.onlyAPIs && len(opts.specificAPIs) == 0 && // only Config APIs requested
@@ -19,6 +19,7 @@
 package download
 
 import (
+	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/testutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
@@ -26,7 +27,12 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/checkpoint"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/estimate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/incremental"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/settings"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	projectv2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
@@ -163,7 +169,7 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 
 func TestDownload_Options(t *testing.T) {
 	type wantDownload struct {
-		config, settings, bucket, automation, document bool
+		config, settings, bucket, segment, openPipeline, automation, document bool
 	}
 	tests := []struct {
 		name  string
@@ -185,6 +191,24 @@ func TestDownload_Options(t *testing.T) {
 				document:   true,
 			},
 		},
+		{
+			"download segments and OpenPipeline configurations only if included",
+			downloadConfigsOptions{
+				downloadOptionsShared: downloadOptionsShared{
+					auth: manifest.Auth{OAuth: &manifest.OAuth{}}, // OAuth required to be defined for platform types
+				},
+				includeSegmentsAndOpenPipeline: true,
+			},
+			wantDownload{
+				config:       true,
+				settings:     true,
+				bucket:       true,
+				segment:      true,
+				openPipeline: true,
+				automation:   true,
+				document:     true,
+			},
+		},
 		{
 			"only settings requested",
 			downloadConfigsOptions{onlySettings: true},
@@ -225,13 +249,13 @@ func TestDownload_Options(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fn := downloadFn{
-				classicDownload: func(client.ConfigClient, string, api.APIs, classic.ContentFilters) (projectv2.ConfigsPerType, error) {
+				classicDownload: func(client.ConfigClient, string, api.APIs, classic.ContentFilters, scope.Filter) (projectv2.ConfigsPerType, error) {
 					if !tt.want.config {
 						t.Fatalf("classic config download was not meant to be called but was")
 					}
 					return nil, nil
 				},
-				settingsDownload: func(settingsClient client.SettingsClient, s string, filters settings.Filters, settingsType ...config.SettingsType) (projectv2.ConfigsPerType, error) {
+				settingsDownload: func(settingsClient client.SettingsClient, s string, filters settings.Filters, scopeFilter scope.Filter, settingsType ...config.SettingsType) (projectv2.ConfigsPerType, error) {
 					if !tt.want.settings {
 						t.Fatalf("settings download was not meant to be called but was")
 					}
@@ -249,6 +273,18 @@ func TestDownload_Options(t *testing.T) {
 					}
 					return nil, nil
 				},
+				segmentDownload: func(client.SegmentClient, string) (projectv2.ConfigsPerType, error) {
+					if !tt.want.segment {
+						t.Fatalf("segment download was not meant to be called but was")
+					}
+					return nil, nil
+				},
+				openPipelineDownload: func(client.OpenPipelineClient, string) (projectv2.ConfigsPerType, error) {
+					if !tt.want.openPipeline {
+						t.Fatalf("OpenPipeline download was not meant to be called but was")
+					}
+					return nil, nil
+				},
 				documentDownload: func(b client.DocumentClient, s string) (projectv2.ConfigsPerType, error) {
 					if !tt.want.document {
 						t.Fatalf("document download was not meant to be called but was")
@@ -451,6 +487,41 @@ func Test_downloadConfigsOptions_valid(t *testing.T) {
 		assert.Len(t, errs, 1)
 		assert.ErrorContains(t, errs[0], "unknown api")
 	})
+	t.Run("no error for pattern matching known apis", func(t *testing.T) {
+		given := downloadConfigsOptions{specificAPIs: []string{"alerting-*"}}
+
+		errs := given.valid()
+
+		assert.Len(t, errs, 0)
+	})
+	t.Run("report error for pattern matching no api", func(t *testing.T) {
+		given := downloadConfigsOptions{specificAPIs: []string{"unknown-*"}}
+
+		errs := given.valid()
+
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "unknown-*")
+	})
+	t.Run("report error for invalid patterns", func(t *testing.T) {
+		given := downloadConfigsOptions{
+			specificAPIs:    []string{"alerting-["},
+			specificSchemas: []string{"builtin:["},
+			excludeAPIs:     []string{"dashboard["},
+			excludeSchemas:  []string{"builtin:alerting.["},
+		}
+
+		errs := given.valid()
+
+		assert.Len(t, errs, 4)
+	})
+	t.Run("report error for unknown output layout", func(t *testing.T) {
+		given := downloadConfigsOptions{downloadOptionsShared: downloadOptionsShared{outputLayout: "nested"}}
+
+		errs := given.valid()
+
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "nested")
+	})
 }
 
 func Test_copyConfigs(t *testing.T) {
@@ -539,3 +610,165 @@ func Test_copyConfigs(t *testing.T) {
 		})
 	})
 }
+
+func TestDownloadConfigs_ScopeFilter(t *testing.T) {
+	mzObjectID := "vu9U3hXa3q0AAAABABhidWlsdGluOm1hbmFnZW1lbnQtem9uZXMABnRlbmFudAAGdGVuYW50ACRjNDZlNDZiMy02ZDk2LTMyYTctOGI1Yi1mNjExNzcyZDAxNjW-71TeFdrerQ"
+	opts := downloadConfigsOptions{onlySettings: true, filterScopes: []string{"HOST-*"}, filterMZs: []string{"my zone"}}
+
+	t.Run("filter is passed to downloads", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:management-zones", gomock.Any()).Return([]dtclient.DownloadSettingsObject{{ObjectId: mzObjectID, Value: []byte(`{"name": "my zone"}`)}}, nil)
+
+		var got scope.Filter
+		fn := downloadFn{
+			settingsDownload: func(_ client.SettingsClient, _ string, _ settings.Filters, scopeFilter scope.Filter, _ ...config.SettingsType) (projectv2.ConfigsPerType, error) {
+				got = scopeFilter
+				return nil, nil
+			},
+		}
+
+		_, err := downloadConfigs(&client.ClientSet{DTClient: c}, api.NewAPIs(), opts, fn)
+		assert.NoError(t, err)
+		assert.Equal(t, scope.Filter{
+			Scopes:          []string{"HOST-*"},
+			ManagementZones: []scope.ManagementZone{{Name: "my zone", ObjectID: mzObjectID, NumericID: "-4292415658385853785"}},
+		}, got)
+	})
+
+	t.Run("unknown management zone fails the download", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:management-zones", gomock.Any()).Return(nil, nil)
+
+		_, err := downloadConfigs(&client.ClientSet{DTClient: c}, api.NewAPIs(), opts, downloadFn{})
+		assert.ErrorContains(t, err, `management zone "my zone" does not exist`)
+	})
+}
+
+func TestDownloadConfigs_IncrementalWrapsSettingsClient(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", dtclient.ListSettingsOptions{}).Return([]dtclient.DownloadSettingsObject{{ObjectId: "o1", Modified: 1}}, nil)
+
+	state := incremental.NewState()
+	opts := downloadConfigsOptions{onlySettings: true, incremental: true, incrementalState: state}
+	fn := downloadFn{
+		settingsDownload: func(c client.SettingsClient, _ string, _ settings.Filters, _ scope.Filter, _ ...config.SettingsType) (projectv2.ConfigsPerType, error) {
+			_, err := c.ListSettings(context.TODO(), "builtin:alerting.profile", dtclient.ListSettingsOptions{})
+			return nil, err
+		},
+	}
+
+	_, err := downloadConfigs(&client.ClientSet{DTClient: c}, api.NewAPIs(), opts, fn)
+	assert.NoError(t, err)
+	assert.Equal(t, []dtclient.DownloadSettingsObject{{ObjectId: "o1", Modified: 1}}, state.Settings["builtin:alerting.profile"])
+}
+
+func TestDownloadConfigs_CheckpointWrapsClients(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Return([]dtclient.Value{{Id: "id", Name: "name"}}, nil).Times(1)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", dtclient.ListSettingsOptions{}).Return([]dtclient.DownloadSettingsObject{{ObjectId: "o1"}}, nil).Times(1)
+
+	cp := checkpoint.New(afero.NewMemMapFs(), checkpoint.FileName)
+	opts := downloadConfigsOptions{checkpoint: cp, specificAPIs: []string{api.AlertingProfile}, specificSchemas: []string{"builtin:alerting.profile"}}
+	fn := downloadFn{
+		classicDownload: func(c client.ConfigClient, _ string, apis api.APIs, _ classic.ContentFilters, _ scope.Filter) (projectv2.ConfigsPerType, error) {
+			for i := 0; i < 2; i++ {
+				if _, err := c.ListConfigs(context.TODO(), apis[api.AlertingProfile]); err != nil {
+					return nil, err
+				}
+			}
+			return nil, nil
+		},
+		settingsDownload: func(c client.SettingsClient, _ string, _ settings.Filters, _ scope.Filter, _ ...config.SettingsType) (projectv2.ConfigsPerType, error) {
+			for i := 0; i < 2; i++ {
+				if _, err := c.ListSettings(context.TODO(), "builtin:alerting.profile", dtclient.ListSettingsOptions{}); err != nil {
+					return nil, err
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	_, err := downloadConfigs(&client.ClientSet{DTClient: c}, api.NewAPIs(), opts, fn)
+	assert.NoError(t, err)
+	assert.Contains(t, cp.ClassicLists, api.AlertingProfile)
+	assert.Contains(t, cp.Settings, "builtin:alerting.profile")
+}
+
+func TestEstimateDownload_Settings(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	opts := downloadConfigsOptions{onlySettings: true}
+	fn := downloadFn{
+		settingsDownload: func(client.SettingsClient, string, settings.Filters, scope.Filter, ...config.SettingsType) (projectv2.ConfigsPerType, error) {
+			return projectv2.ConfigsPerType{
+				"builtin:alerting.profile": {
+					{Template: template.NewInMemoryTemplate("a", "{}")},
+					{Template: template.NewInMemoryTemplate("b", "{\"a\":1}")},
+				},
+			}, nil
+		},
+	}
+
+	entries, err := estimateDownload(&client.ClientSet{DTClient: c}, api.NewAPIs(), opts, fn)
+	assert.NoError(t, err)
+	assert.Equal(t, []estimate.Entry{{Type: "builtin:alerting.profile", Count: 2, Size: 9}}, entries)
+}
+
+func TestSelectSchemas(t *testing.T) {
+	schemas := dtclient.SchemaList{
+		{SchemaId: "builtin:anomaly-detection.rum-web"},
+		{SchemaId: "builtin:anomaly-detection.services"},
+		{SchemaId: "builtin:alerting.profile"},
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "patterns and exclusions",
+			include: []string{"builtin:anomaly-detection.*"},
+			exclude: []string{"builtin:anomaly-detection.rum*"},
+			want:    []string{"builtin:anomaly-detection.services"},
+		},
+		{
+			name:    "exclusions only",
+			exclude: []string{"builtin:anomaly-detection.*"},
+			want:    []string{"builtin:alerting.profile"},
+		},
+		{
+			name:    "pattern matching nothing",
+			include: []string{"builtin:unknown.*"},
+			wantErr: `"builtin:unknown.*" provided via "--settings-schema" flag does not match any known schema`,
+		},
+		{
+			name:    "everything excluded",
+			include: []string{"builtin:alerting.*"},
+			exclude: []string{"builtin:*"},
+			wantErr: "all settings schemas are excluded",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := client.NewMockSettingsClient(gomock.NewController(t))
+			c.EXPECT().ListSchemas().Return(schemas, nil)
+
+			got, err := selectSchemas(c, tc.include, tc.exclude)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("schemas without patterns are not resolved", func(t *testing.T) {
+		c := client.NewMockSettingsClient(gomock.NewController(t))
+		got, err := selectSchemas(c, []string{"builtin:alerting.profile"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"builtin:alerting.profile"}, got)
+	})
+}
@@ -21,32 +21,81 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/checkpoint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/extraction"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/incremental"
+	configwriter "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/writer"
+	"path"
 )
 
 type downloadConfigsOptions struct {
 	downloadOptionsShared
 	specificAPIs    []string
 	specificSchemas []string
+	excludeAPIs     []string
+	excludeSchemas  []string
 	onlyAPIs        bool
 	onlySettings    bool
 	onlyAutomation  bool
 	onlyDocuments   bool
+	// includeSegmentsAndOpenPipeline additionally downloads Grail segments and OpenPipeline configurations, which can
+	// not be deployed yet
+	includeSegmentsAndOpenPipeline bool
+	filterScopes                   []string
+	filterMZs                      []string
+	incremental                    bool
+	// resume continues a previous download into the same output folder from its checkpoint
+	resume bool
+	// checkpoint records the progress of the download if an output folder is set
+	checkpoint *checkpoint.Checkpoint
+	// onlyMonacoManaged keeps only the downloaded configs which were deployed by monaco
+	onlyMonacoManaged bool
+	// extractionRulesFile is the path of a file defining which template fields to extract into parameters
+	extractionRulesFile string
+	// extractionRules are the rules loaded from extractionRulesFile
+	extractionRules []extraction.Rule
+	// dryRun only lists the objects that would be downloaded instead of downloading them
+	dryRun bool
+	// mergeProject is the path of an existing project folder to merge the downloaded configs into
+	mergeProject string
+	// incrementalState holds the previously downloaded settings objects if incremental is set
+	incrementalState *incremental.State
 }
 
 func (opts downloadConfigsOptions) valid() []error {
 	var retVal []error
 	knownEndpoints := api.NewAPIs().Filter(api.RemoveDisabled)
 	for _, e := range opts.specificAPIs {
-		if !knownEndpoints.Contains(e) {
+		if _, err := path.Match(e, ""); err != nil {
+			retVal = append(retVal, fmt.Errorf("invalid pattern %q provided via \"--api\" flag: %w", e, err))
+		} else if len(knownEndpoints.Filter(api.RetainByPattern([]string{e}))) == 0 {
 			retVal = append(retVal, fmt.Errorf("unknown (or unsupported) classic endpoint with name %q provided via \"--api\" flag. A list of supported classic endpoints is in the documentation", e))
 		}
 	}
 
+	retVal = append(retVal, validatePatterns("settings-schema", opts.specificSchemas)...)
+	retVal = append(retVal, validatePatterns("exclude-api", opts.excludeAPIs)...)
+	retVal = append(retVal, validatePatterns("exclude-schema", opts.excludeSchemas)...)
+
+	if _, ok := configwriter.Layouts[opts.outputLayout]; !ok && opts.outputLayout != "" {
+		retVal = append(retVal, fmt.Errorf("unknown output layout %q provided via \"--output-layout\" flag, must be one of %q", opts.outputLayout, []string{configwriter.FlatLayoutName, configwriter.HierarchicalLayoutName}))
+	}
+
 	return retVal
 }
 
+func validatePatterns(flag string, patterns []string) []error {
+	var errs []error
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid pattern %q provided via \"--%s\" flag: %w", p, flag, err))
+		}
+	}
+	return errs
+}
+
 func prepareAPIs(apis api.APIs, opts downloadConfigsOptions) api.APIs {
-	apis = apis.Filter(api.RemoveDisabled)
+	apis = apis.Filter(api.RemoveDisabled, api.RemoveByPattern(opts.excludeAPIs))
 	switch {
 	case opts.onlyDocuments:
 		return nil
@@ -57,7 +106,7 @@ func prepareAPIs(apis api.APIs, opts downloadConfigsOptions) api.APIs {
 	case opts.onlyAPIs:
 		return apis.Filter(removeSkipDownload, removeDeprecated(withWarn()))
 	case len(opts.specificAPIs) > 0:
-		return apis.Filter(api.RetainByName(opts.specificAPIs), removeSkipDownload, warnDeprecated())
+		return apis.Filter(api.RetainByPattern(opts.specificAPIs), removeSkipDownload, warnDeprecated())
 	case len(opts.specificSchemas) == 0:
 		return apis.Filter(removeSkipDownload, removeDeprecated())
 	default:
@@ -275,3 +275,21 @@ func Test_prepareAPIs(t *testing.T) {
 		}
 	})
 }
+
+func Test_prepareAPIs_Patterns(t *testing.T) {
+	apis := api.APIs{
+		"alerting-profile": api.API{ID: "alerting-profile"},
+		"alerting-rule":    api.API{ID: "alerting-rule"},
+		"dashboard":        api.API{ID: "dashboard"},
+	}
+
+	t.Run("specific APIs by pattern", func(t *testing.T) {
+		actual := prepareAPIs(apis, downloadConfigsOptions{specificAPIs: []string{"alerting-*"}})
+		assert.ElementsMatch(t, []string{"alerting-profile", "alerting-rule"}, actual.GetNames())
+	})
+
+	t.Run("excluded APIs are removed", func(t *testing.T) {
+		actual := prepareAPIs(apis, downloadConfigsOptions{excludeAPIs: []string{"*-rule", "dashboard"}})
+		assert.ElementsMatch(t, []string{"alerting-profile"}, actual.GetNames())
+	})
+}
@@ -0,0 +1,60 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dynatrace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+)
+
+// AccountEnvironmentDiscoverer discovers the environments of an account using the Account Management API
+type AccountEnvironmentDiscoverer struct{}
+
+// DiscoverEnvironments returns all environments of the account
+func (AccountEnvironmentDiscoverer) DiscoverEnvironments(ctx context.Context, acc manifest.Account) ([]manifestloader.DiscoveredEnvironment, error) {
+	accClients, err := CreateAccountClients(map[string]manifest.Account{acc.Name: acc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account client: %w", err)
+	}
+
+	var result []manifestloader.DiscoveredEnvironment
+	for _, c := range accClients {
+		r, resp, err := c.EnvironmentManagementAPI.GetEnvironments(ctx, acc.AccountUUID.String()).Execute()
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environments: %w", err)
+		}
+		if r == nil {
+			return nil, errors.New("no environments response data received")
+		}
+
+		for _, e := range r.Data {
+			result = append(result, manifestloader.DiscoveredEnvironment{
+				ID:     e.Id,
+				Name:   e.Name,
+				URL:    e.Url,
+				Active: e.Active,
+			})
+		}
+	}
+	return result, nil
+}
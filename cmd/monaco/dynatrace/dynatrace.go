@@ -19,6 +19,7 @@ package dynatrace
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/accounts"
 	lib "github.com/dynatrace/dynatrace-configuration-as-code-core/api/rest"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients"
@@ -45,6 +46,9 @@ func VerifyEnvironmentGeneration(envs manifest.Environments) bool {
 		return true
 	}
 	for _, env := range envs {
+		if env.IsCluster() {
+			continue
+		}
 		if (env.Auth.OAuth == nil && !isClassicEnvironment(env)) || (env.Auth.OAuth != nil && !isPlatformEnvironment(env)) {
 			return false
 		}
@@ -85,21 +89,74 @@ func isPlatformEnvironment(env manifest.EnvironmentDefinition) bool {
 	return true
 }
 
+// CreateClientsForEnvironment creates a new client set for the given environment, honoring its retry policy and HTTP settings.
+func CreateClientsForEnvironment(env manifest.EnvironmentDefinition) (*client.ClientSet, error) {
+	if env.IsCluster() {
+		return nil, fmt.Errorf("environment %q is a Managed cluster, which is only supported for deployments", env.Name)
+	}
+	return createClients(env.URL.Value, env.Auth, environmentClientOptions(env))
+}
+
+// environmentClientOptions returns the client options defined for an environment in the manifest
+func environmentClientOptions(env manifest.EnvironmentDefinition) client.ClientOptions {
+	return client.ClientOptions{
+		SupportArchive: support.SupportArchive,
+		Middlewares:    support.HTTPMiddlewares(),
+		RetryPolicy:    toRetryPolicy(env.RetryPolicy),
+		HTTPSettings:   toHTTPSettings(env.HTTPSettings),
+	}
+}
+
 // CreateClients creates a new client set based on the provided URL and authentication information.
 func CreateClients(url string, auth manifest.Auth) (*client.ClientSet, error) {
+	return createClients(url, auth, client.ClientOptions{
+		SupportArchive: support.SupportArchive,
+		Middlewares:    support.HTTPMiddlewares(),
+	})
+}
+
+func createClients(url string, auth manifest.Auth, opts client.ClientOptions) (*client.ClientSet, error) {
 	if auth.OAuth == nil {
-		return client.CreateClassicClientSet(url, auth.Token.Value.Value(), client.ClientOptions{
-			SupportArchive: support.SupportArchive,
-		})
+		return client.CreateClassicClientSet(url, auth.Token.Value.Value(), opts)
 	}
 	return client.CreatePlatformClientSet(url, client.PlatformAuth{
 		OauthClientID:     auth.OAuth.ClientID.Value.Value(),
 		OauthClientSecret: auth.OAuth.ClientSecret.Value.Value(),
 		Token:             auth.Token.Value.Value(),
 		OauthTokenURL:     auth.OAuth.GetTokenEndpointValue(),
-	}, client.ClientOptions{
-		SupportArchive: support.SupportArchive,
-	})
+	}, opts)
+}
+
+// toRetryPolicy converts the retry policy defined for an environment in the manifest to the one used by the rest clients
+func toRetryPolicy(p *manifest.RetryPolicy) *rest.RetryPolicy {
+	if p == nil {
+		return nil
+	}
+	return &rest.RetryPolicy{
+		MaxRetries:           p.MaxRetries,
+		WaitTime:             p.WaitTime,
+		BackoffFactor:        p.BackoffFactor,
+		MaxWaitTime:          p.MaxWaitTime,
+		RetriableStatusCodes: p.RetriableStatusCodes,
+	}
+}
+
+// toHTTPSettings converts the HTTP settings defined for an environment in the manifest to the ones used by the clients
+func toHTTPSettings(s *manifest.HTTPSettings) *client.HTTPSettings {
+	if s == nil {
+		return nil
+	}
+	return &client.HTTPSettings{
+		ProxyURL:       s.Proxy,
+		CACertificates: s.CACertificates,
+		Timeout:        s.Timeout,
+
+		MaxIdleConnsPerHost:    s.MaxIdleConnsPerHost,
+		MaxConnsPerHost:        s.MaxConnsPerHost,
+		IdleConnTimeout:        s.IdleConnTimeout,
+		DisableTLSSessionReuse: s.DisableTLSSessionReuse,
+		DisableHTTP2:           s.DisableHTTP2,
+	}
 }
 
 // CreateAccountClients gives back clients to use for specific accounts
@@ -165,8 +222,13 @@ func (e EnvironmentClients) Names() []string {
 func CreateEnvironmentClients(environments manifest.Environments) (EnvironmentClients, error) {
 	clients := make(EnvironmentClients, len(environments))
 	for _, env := range environments {
-
-		clientSet, err := CreateClients(env.URL.Value, env.Auth)
+		var clientSet *client.ClientSet
+		var err error
+		if env.IsCluster() {
+			clientSet, err = client.CreateClusterClientSet(env.URL.Value, env.Auth.Token.Value.Value(), environmentClientOptions(env))
+		} else {
+			clientSet, err = CreateClientsForEnvironment(env)
+		}
 		if err != nil {
 			return EnvironmentClients{}, err
 		}
@@ -29,6 +29,7 @@ import (
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"path"
 	"path/filepath"
 )
 
@@ -37,6 +38,7 @@ func Command(fs afero.Fs) (cmd *cobra.Command) {
 	var fileName, outputFolder string
 	var projects, environments []string
 	var includeTypes, excludeTypes []string
+	var only []string
 
 	cmd = &cobra.Command{
 		Use:               "deletefile <manifest.yaml>",
@@ -49,6 +51,12 @@ func Command(fs afero.Fs) (cmd *cobra.Command) {
 
 			manifestName := args[0]
 
+			for _, p := range only {
+				if _, err := path.Match(p, ""); err != nil {
+					return fmt.Errorf("invalid '--only' pattern %q: %w", p, err)
+				}
+			}
+
 			if !files.IsYamlFileExtension(manifestName) {
 				err := fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", manifestName)
 				return err
@@ -85,6 +93,7 @@ func Command(fs afero.Fs) (cmd *cobra.Command) {
 				fileName:         fileName,
 				includeTypes:     includeTypes,
 				excludeTypes:     excludeTypes,
+				only:             only,
 				outputFolder:     outputFolder,
 			}
 
@@ -102,6 +111,9 @@ func Command(fs afero.Fs) (cmd *cobra.Command) {
 	cmd.Flags().StringSliceVarP(&projects, "project", "p", nil, "Projects to generate delete file entries for. If not defined, all projects in the manifest will be used.")
 	cmd.Flags().StringSliceVar(&excludeTypes, "exclude-types", nil, "Comma-separated list of config types to be excluded from the generation process.")
 	cmd.Flags().StringSliceVar(&includeTypes, "types", nil, "Comma-separated list of config types to be included in the generation process.")
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Only generate delete entries for configurations matching the given coordinate 'project:type:configId'. "+
+		"Supports wildcards, e.g. 'my-project:builtin:alerting.profile:*'. "+
+		"To select multiple configurations either repeat this flag, or separate them using a comma (,).")
 
 	cmd.Flags().StringSliceVarP(&environments, "environment", "e", []string{},
 		"Specify one (or multiple) environment(s) to generate delete entries for. If not defined, entries for all environments will be generated. It is generally safe and recommended to generate a full delete file for all environments, but you may sometimes want to create a file limited to a specific environment's overrides.")
@@ -25,6 +25,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
@@ -34,6 +35,7 @@ import (
 	"github.com/spf13/afero"
 	"golang.org/x/exp/maps"
 	"gopkg.in/yaml.v2"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -44,7 +46,9 @@ type createDeleteFileOptions struct {
 	fileName         string
 	includeTypes     []string
 	excludeTypes     []string
-	outputFolder     string
+	// only restricts the delete entries to configs whose coordinate matches one of these patterns, if set
+	only         []string
+	outputFolder string
 }
 
 func createDeleteFile(fs afero.Fs, projects []project.Project, apis api.APIs, options createDeleteFileOptions) error {
@@ -127,7 +131,7 @@ func generateDeleteEntries(apis api.APIs, projects []project.Project, options cr
 	for _, p := range projects {
 		log.Info("Adding delete entries for project %q...", p.Id)
 		p.ForEveryConfigDo(func(c config.Config) {
-			if skipping(c.Coordinate.Type, inclTypesLookup, exclTypesLookup) {
+			if skipping(c.Coordinate.Type, inclTypesLookup, exclTypesLookup) || !selected(c.Coordinate, options.only) {
 				return
 			}
 
@@ -153,7 +157,7 @@ func generateDeleteEntriesForEnvironments(apis api.APIs, projects []project.Proj
 		for _, env := range options.environmentNames {
 			log.Info("Adding delete entries for project %q and environment %q...", p.Id, env)
 			p.ForEveryConfigInEnvironmentDo(env, func(c config.Config) {
-				if skipping(c.Coordinate.Type, inclTypesLookup, exclTypesLookup) {
+				if skipping(c.Coordinate.Type, inclTypesLookup, exclTypesLookup) || !selected(c.Coordinate, options.only) {
 					return
 				}
 				entry, err := createDeleteEntry(c, apis, p)
@@ -189,6 +193,19 @@ func skipping(ttype string, included, excluded map[string]struct{}) bool {
 	return false
 }
 
+// selected returns whether the coordinate matches one of the given glob patterns, or true if no patterns are given
+func selected(c coordinate.Coordinate, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, c.String()); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // toMapKey creates a unique key for persistence.DeleteEntries so that they can be managed in maps.
 // Note, that persistence.DeleteEntry itself cannot be used as a map key, because it contains a map as field for which
 // no == and != comparison is defined.
@@ -444,3 +444,33 @@ func readFile(t *testing.T, fs afero.Fs, file string) []byte {
 	require.NoError(t, err)
 	return content
 }
+
+func TestGeneratesValidDeleteFile_ForSelectedConfigs(t *testing.T) {
+
+	t.Setenv("TOKEN", "some-value")
+
+	fs := testutils.CreateTestFileSystem()
+
+	outputFolder := "output-folder"
+
+	cmd := deletefile.Command(fs)
+
+	cmd.SetArgs([]string{
+		"./test-resources/manifest.yaml",
+		"-o",
+		outputFolder,
+		"--only",
+		"project:alerting-profile:profile,project:alerting-profile:profile3",
+	})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	expectedFile := filepath.Join(outputFolder, "delete.yaml")
+	assertFileExists(t, fs, expectedFile)
+
+	entries, errs := delete.LoadEntriesFromFile(fs, expectedFile)
+	assert.NoError(t, errs)
+
+	assert.Len(t, entries, 1)
+	assertDeleteEntries(t, entries, "alerting-profile", "Star Trek Service", "Star Gate Service")
+}
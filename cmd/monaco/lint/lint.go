@@ -0,0 +1,123 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/lint"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"path/filepath"
+)
+
+func Command(fs afero.Fs) *cobra.Command {
+	var environments, groups, projects []string
+
+	cmd := &cobra.Command{
+		Use:   "lint <manifest.yaml>",
+		Short: "Statically check the templates and parameters of all configurations defined in the manifest's projects",
+		Long: `Statically check the templates and parameters of all configurations defined in the manifest's projects, without connecting to any environment.
+
+Every template is parsed and checked for template variables that are not declared as parameters of the config, for parameters
+that are neither used by the template nor by other parameters, and for being valid JSON once rendered. As parameter values
+are not resolved, templates are rendered with placeholder values - plain values are used as they are.`,
+		Example:           "monaco lint manifest.yaml -e dev-environment",
+		Args:              cobra.ExactArgs(1),
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		ValidArgsFunction: completion.SingleArgumentManifestFileCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestPath := args[0]
+			if !files.IsYamlFileExtension(manifestPath) {
+				return fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", manifestPath)
+			}
+			return lintProjects(fs, manifestPath, environments, groups, projects)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&groups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) whose configurations should be checked. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'. "+
+			"If neither --groups nor --environment is present, all environments are used.")
+	cmd.Flags().StringSliceVarP(&environments, "environment", "e", []string{},
+		"Specify one (or multiple) environments(s) whose configurations should be checked. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'. "+
+			"If neither --groups nor --environment is present, all environments are used.")
+	cmd.Flags().StringSliceVarP(&projects, "project", "p", []string{}, "Project(s) to check. If not set, all projects are checked.")
+
+	if err := cmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := cmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	cmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return cmd
+}
+
+func lintProjects(fs afero.Fs, manifestPath string, environments, groups, projectNames []string) error {
+	m, errs := manifestloader.Load(&manifestloader.Context{
+		Fs:           fs,
+		ManifestPath: filepath.Clean(manifestPath),
+		Environments: environments,
+		Groups:       groups,
+		Opts: manifestloader.Options{
+			DoNotResolveEnvVars:      true,
+			RequireEnvironmentGroups: true,
+		},
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to load manifest %q", manifestPath)
+	}
+
+	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
+		KnownApis:       api.NewAPIs().GetApiNameLookup(),
+		WorkingDir:      filepath.Dir(manifestPath),
+		Manifest:        m,
+		ParametersSerde: config.DefaultParameterParsers,
+	}, projectNames)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to load projects - %d errors occurred", len(errs))
+	}
+
+	findings := lint.Lint(projects)
+	for _, f := range findings {
+		log.WithFields(field.Coordinate(f.Coordinate), field.F("file", f.File), field.F("line", f.Line), field.F("rule", f.Rule)).Error("%s", f)
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("linting found %d problems", len(findings))
+	}
+
+	log.Info("No problems found")
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLintProjects(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: g
+    environments: [{name: e, url: {value: "https://abc.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}]
+`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "a/config.yaml", []byte(`
+configs:
+  - id: profile
+    config:
+      name: profile
+      template: profile.json
+    type:
+      api: alerting-profile
+`), 0644))
+
+	t.Run("no problems", func(t *testing.T) {
+		require.NoError(t, afero.WriteFile(fs, "a/profile.json", []byte(`{"name": "{{ .name }}"}`), 0644))
+		assert.NoError(t, lintProjects(fs, "manifest.yaml", nil, nil, nil))
+	})
+
+	t.Run("problems are reported", func(t *testing.T) {
+		require.NoError(t, afero.WriteFile(fs, "a/profile.json", []byte(`{"name": "{{ .name }}", "enabled": {{ .enabled }}}`), 0644))
+		assert.EqualError(t, lintProjects(fs, "manifest.yaml", nil, nil, nil), "linting found 1 problems")
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		assert.ErrorContains(t, lintProjects(fs, "manifest.yaml", nil, nil, []string{"unknown"}), "failed to load projects")
+	})
+}
@@ -0,0 +1,45 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func Command(fs afero.Fs) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "manifest <command>",
+		Short: "Validate, upgrade, and print the JSON schema of manifests",
+		Long: `Validate manifests without deploying them, upgrade them to the newest manifest version, and print the JSON schema of manifests for IDEs and CI pipelines.
+
+Examples:
+	Validate a manifest:
+		monaco manifest validate manifest.yaml [--env-vars-file .env.example]
+	Upgrade a manifest to the newest manifest version:
+		monaco manifest upgrade manifest.yaml [--dry-run]
+	Print the JSON schema of manifests:
+		monaco manifest schema > monaco-manifest.schema.json
+`,
+	}
+
+	command.AddCommand(validateCommand(fs))
+	command.AddCommand(upgradeCommand(fs))
+	command.AddCommand(schemaCommand())
+
+	return command
+}
@@ -0,0 +1,85 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"io"
+	"path/filepath"
+)
+
+func upgradeCommand(fs afero.Fs) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade <manifest.yaml>",
+		Short: "Upgrade a manifest to the newest manifest version",
+		Long: `Upgrade a manifest to the newest 'manifestVersion' supported by this version of monaco.
+
+All changes of the manifest format between the manifest's current version and the newest one are applied, and the manifest
+file is overwritten. The order of all fields is kept, but comments are removed. Manifests included by the manifest are not
+upgraded - run the command for each of them.`,
+		Example: "monaco manifest upgrade manifest.yaml [--dry-run]",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestPath := filepath.Clean(args[0])
+			if !files.IsYamlFileExtension(manifestPath) {
+				return fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", manifestPath)
+			}
+			return upgrade(fs, manifestPath, dryRun, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the upgraded manifest instead of overwriting the manifest file")
+
+	return cmd
+}
+
+func upgrade(fs afero.Fs, manifestPath string, dryRun bool, out io.Writer) error {
+	content, err := afero.ReadFile(fs, manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest %q: %w", manifestPath, err)
+	}
+
+	result, err := manifestloader.Upgrade(content)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade manifest %q: %w", manifestPath, err)
+	}
+
+	if dryRun {
+		_, err = out.Write(result.Content)
+		return err
+	}
+
+	if !result.Upgraded() {
+		log.Info("Manifest %q already has the newest manifest version %s", manifestPath, result.ToVersion)
+		return nil
+	}
+
+	if err := afero.WriteFile(fs, manifestPath, result.Content, 0644); err != nil {
+		return fmt.Errorf("failed to write upgraded manifest %q: %w", manifestPath, err)
+	}
+	log.Info("Upgraded manifest %q from version %s to %s", manifestPath, result.FromVersion, result.ToVersion)
+	return nil
+}
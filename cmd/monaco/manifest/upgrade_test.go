@@ -0,0 +1,70 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"bytes"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpgrade(t *testing.T) {
+	const manifest = `manifestVersion: "1.0"
+projects:
+- name: project
+`
+	const upgraded = `manifestVersion: "` + version.ManifestVersion + `"
+projects:
+- name: project
+`
+
+	t.Run("dry run prints the upgraded manifest", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifest), 0644))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, upgrade(fs, "manifest.yaml", true, out))
+		assert.Equal(t, upgraded, out.String())
+
+		content, err := afero.ReadFile(fs, "manifest.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, manifest, string(content), "manifest must not be changed in a dry run")
+	})
+
+	t.Run("manifest file is overwritten", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifest), 0644))
+
+		out := &bytes.Buffer{}
+		require.NoError(t, upgrade(fs, "manifest.yaml", false, out))
+		assert.Empty(t, out.String())
+
+		content, err := afero.ReadFile(fs, "manifest.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, upgraded, string(content))
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		err := upgrade(afero.NewMemMapFs(), "manifest.yaml", false, &bytes.Buffer{})
+		assert.ErrorContains(t, err, `failed to read manifest "manifest.yaml"`)
+	})
+}
@@ -0,0 +1,117 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"strings"
+)
+
+func validateCommand(fs afero.Fs) *cobra.Command {
+	var envVarsFile string
+	var variables map[string]string
+
+	cmd := &cobra.Command{
+		Use:   "validate <manifest.yaml>",
+		Short: "Validate a manifest without deploying it",
+		Long: `Validate a manifest without deploying it or connecting to any environment.
+
+Besides the checks done when loading a manifest, it is verified that the folders of all projects exist, that all URLs are valid,
+and that every environment group defines or discovers environments. Environment variables referenced by the manifest are not
+resolved, but listed - and if '--env-vars-file' is given, each of them must be documented in that file.`,
+		Example: "monaco manifest validate manifest.yaml --env-vars-file .env.example",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestPath := args[0]
+			if !files.IsYamlFileExtension(manifestPath) {
+				return fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", manifestPath)
+			}
+			return validate(fs, manifestPath, envVarsFile, variables)
+		},
+	}
+
+	cmd.Flags().StringVar(&envVarsFile, "env-vars-file", "", "File documenting the environment variables the manifest may reference - e.g. a '.env.example'. "+
+		"Each line documents one variable as 'NAME' or 'NAME=value', empty lines and lines starting with '#' are ignored. "+
+		"If set, referencing an environment variable that is not documented in this file is an error.")
+	cmdutils.AddVariablesFlag(cmd, &variables)
+
+	return cmd
+}
+
+func validate(fs afero.Fs, manifestPath string, envVarsFile string, variables map[string]string) error {
+	var documented []string
+	if envVarsFile != "" {
+		var err error
+		if documented, err = readDocumentedEnvVars(fs, envVarsFile); err != nil {
+			return err
+		}
+	}
+
+	envVars, errs := manifestloader.Validate(&manifestloader.Context{
+		Fs:           fs,
+		ManifestPath: filepath.Clean(manifestPath),
+		Variables:    variables,
+	}, documented)
+
+	if len(envVars) > 0 {
+		log.Info("Manifest %q references the environment variables: %s", manifestPath, strings.Join(envVars, ", "))
+	}
+
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("manifest %q is invalid - %d errors occurred", manifestPath, len(errs))
+	}
+
+	log.Info("Manifest %q is valid", manifestPath)
+	return nil
+}
+
+// readDocumentedEnvVars reads the names of the environment variables documented in the given file. Each line documents one
+// variable, either as 'NAME' or as 'NAME=value', optionally prefixed by 'export'. Empty lines and comments are ignored.
+func readDocumentedEnvVars(fs afero.Fs, path string) ([]string, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment variables file %q: %w", path, err)
+	}
+
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		name, _, _ := strings.Cut(line, "=")
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, fmt.Errorf("environment variables file %q contains a line without a variable name", path)
+		}
+		names = append(names, name)
+	}
+	return names, scanner.Err()
+}
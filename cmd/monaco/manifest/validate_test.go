@@ -0,0 +1,96 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReadDocumentedEnvVars(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, ".env.example", []byte(`
+# token of the environments
+TOKEN=dt0c01.xyz
+export CLIENT_ID=
+  CLIENT_SECRET
+`), 0644))
+
+	got, err := readDocumentedEnvVars(fs, ".env.example")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TOKEN", "CLIENT_ID", "CLIENT_SECRET"}, got)
+
+	t.Run("lines without name are rejected", func(t *testing.T) {
+		require.NoError(t, afero.WriteFile(fs, "invalid", []byte("=value"), 0644))
+		_, err := readDocumentedEnvVars(fs, "invalid")
+		assert.ErrorContains(t, err, `environment variables file "invalid" contains a line without a variable name`)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := readDocumentedEnvVars(fs, "missing")
+		assert.ErrorContains(t, err, `failed to read environment variables file "missing"`)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+variables:
+  tenant: abc
+projects: [{name: a}]
+environmentGroups:
+  - name: g
+    environments: [{name: e, url: {value: "https://{{ .tenant }}.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}]
+`), 0644))
+	require.NoError(t, fs.MkdirAll("a", 0777))
+	require.NoError(t, afero.WriteFile(fs, ".env.example", []byte("TOKEN=\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "empty.env", []byte(""), 0644))
+
+	t.Run("valid manifest", func(t *testing.T) {
+		assert.NoError(t, validate(fs, "manifest.yaml", ".env.example", nil))
+	})
+
+	t.Run("variables are overridden", func(t *testing.T) {
+		err := validate(fs, "manifest.yaml", "", map[string]string{"tenant": "not a host"})
+		assert.ErrorContains(t, err, `manifest "manifest.yaml" is invalid - 1 errors occurred`)
+	})
+
+	t.Run("undocumented environment variable", func(t *testing.T) {
+		err := validate(fs, "manifest.yaml", "empty.env", nil)
+		assert.ErrorContains(t, err, `manifest "manifest.yaml" is invalid - 1 errors occurred`)
+	})
+}
+
+func TestSchemaCommand(t *testing.T) {
+	cmd := schemaCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+	assert.Contains(t, schema, "properties")
+}
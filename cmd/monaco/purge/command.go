@@ -31,6 +31,7 @@ func GetPurgeCommand(fs afero.Fs) (purgeCmd *cobra.Command) {
 	var environment []string
 	var manifestName string
 	var specificApis []string
+	var opts purgeOptions
 
 	purgeCmd = &cobra.Command{
 		Use:     "purge <manifest.yaml>",
@@ -48,13 +49,22 @@ func GetPurgeCommand(fs afero.Fs) (purgeCmd *cobra.Command) {
 				return err
 			}
 
-			return purge(fs, manifestName, environment, specificApis)
+			return purge(fs, manifestName, environment, specificApis, opts)
 		},
 		ValidArgsFunction: completion.PurgeCompletion,
 	}
 
 	purgeCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Deletes configuration only for specified environments. All environments are included if this property is not set. ")
 	purgeCmd.Flags().StringSliceVarP(&specificApis, "api", "a", make([]string, 0), "One or more specific APIs to delete from (flag can be repeated or value defined as comma-separated list)")
+	purgeCmd.Flags().StringSliceVar(&opts.excludedTypes, "exclude", make([]string, 0), "One or more classic APIs, settings schemas, automation resource types or 'bucket' that must not be deleted (flag can be repeated or value defined as comma-separated list)")
+	purgeCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only report the configurations that would be deleted, with their type, name, ID, age and last modifier when available, and write the report to the file given by '--report-file'")
+	purgeCmd.Flags().StringVar(&opts.reportFile, "report-file", "purge-report.json", "File the report of a dry run is written to")
+	purgeCmd.Flags().StringVar(&opts.confirmationFile, "confirmation-file", "", "Report of a previous dry run. Only the configurations listed in it are deleted")
+	purgeCmd.Flags().BoolVar(&opts.yesIKnow, "yes-i-know", false, "Delete all configurations without reviewing a dry run report first")
+	purgeCmd.Flags().StringVar(&opts.archiveFolder, "archive-folder", "", "Folder to archive configurations to before they are deleted. Archived configurations are written as a monaco project into a timestamped sub-folder per environment and can be restored by deploying it")
+	cmdutils.AddVariablesFlag(purgeCmd, &opts.variables)
+	purgeCmd.MarkFlagsMutuallyExclusive("dry-run", "confirmation-file", "yes-i-know")
+	purgeCmd.MarkFlagsMutuallyExclusive("dry-run", "archive-folder")
 
 	if err := purgeCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
 		log.Fatal("failed to setup CLI %v", err)
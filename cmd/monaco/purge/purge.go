@@ -25,14 +25,41 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/archive"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
 	"github.com/spf13/afero"
 	"golang.org/x/exp/maps"
 	"path/filepath"
+	"slices"
+	"time"
 )
 
-func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string, apiNames []string) error {
+// purgeOptions guard the purge of all configurations, which needs to be reviewed in a dry run first
+type purgeOptions struct {
+	// excludedTypes are the types of configurations that are never deleted
+	excludedTypes []string
+	// dryRun only reports the configurations that would be deleted and writes the report to reportFile
+	dryRun     bool
+	reportFile string
+	// confirmationFile is the report of a previous dry run, only the configurations listed in it are deleted
+	confirmationFile string
+	// yesIKnow deletes all configurations without a report
+	yesIKnow bool
+	// archiveFolder is the folder configurations are archived to before they are deleted. If empty, nothing is archived.
+	archiveFolder string
+	// variables override the values of variables defined in the manifest
+	variables map[string]string
+}
+
+var errNotConfirmed = errors.New("purge deletes ALL configurations of an environment. Run it with '--dry-run' first to review what would be deleted, " +
+	"then pass the written report with '--confirmation-file' to delete the reviewed configurations, or use '--yes-i-know' to skip the review")
+
+func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string, apiNames []string, opts purgeOptions) error {
+	if !opts.dryRun && opts.confirmationFile == "" && !opts.yesIKnow {
+		return errNotConfirmed
+	}
 
 	deploymentManifestPath = filepath.Clean(deploymentManifestPath)
 	deploymentManifestPath, manifestErr := filepath.Abs(deploymentManifestPath)
@@ -47,7 +74,10 @@ func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string
 		Fs:           fs,
 		ManifestPath: deploymentManifestPath,
 		Environments: environmentNames,
+		Variables:    opts.variables,
 		Opts:         manifestloader.Options{RequireEnvironmentGroups: true},
+
+		EnvironmentDiscoverer: dynatrace.AccountEnvironmentDiscoverer{},
 	})
 
 	if manifestLoadError != nil {
@@ -55,13 +85,52 @@ func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string
 		return errors.New("error while loading manifest")
 	}
 
-	return purgeConfigs(maps.Values(mani.Environments), apis)
+	environments := maps.Values(mani.Environments)
+	switch {
+	case opts.dryRun:
+		return reportConfigs(fs, environments, apis, opts)
+	case opts.confirmationFile != "":
+		r, err := loadReport(fs, opts.confirmationFile)
+		if err != nil {
+			return err
+		}
+		return purgeReportedConfigs(fs, environments, apis, r, opts)
+	default:
+		return purgeConfigs(fs, environments, apis, opts)
+	}
+}
+
+// reportConfigs collects the configurations that would be purged from each environment, logs them and writes them to
+// the report file
+func reportConfigs(fs afero.Fs, environments []manifest.EnvironmentDefinition, apis api.APIs, opts purgeOptions) error {
+	r := report{Environments: map[string][]pointer.RemoteObject{}}
+	for _, env := range environments {
+		deleteClients, err := getClientSet(env)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
+		objects, err := delete.Collect(ctx, deleteClients, apis, opts.excludedTypes)
+		if err != nil {
+			log.WithCtxFields(ctx).Warn("Not all configurations of environment %s could be collected, the report is incomplete - check logs for details.", env.Name)
+		}
+
+		logReport(ctx, env.Name, objects, time.Now())
+		r.Environments[env.Name] = objects
+	}
+
+	if err := writeReport(fs, opts.reportFile, r); err != nil {
+		return err
+	}
+	log.Info("Report written to %q. Review it and run purge with '--confirmation-file %s' to delete the listed configurations.", opts.reportFile, opts.reportFile)
+	return nil
 }
 
-func purgeConfigs(environments []manifest.EnvironmentDefinition, apis api.APIs) error {
+func purgeConfigs(fs afero.Fs, environments []manifest.EnvironmentDefinition, apis api.APIs, opts purgeOptions) error {
 
 	for _, env := range environments {
-		err := purgeForEnvironment(env, apis)
+		err := purgeForEnvironment(fs, env, apis, opts)
 		if err != nil {
 			return err
 		}
@@ -70,7 +139,7 @@ func purgeConfigs(environments []manifest.EnvironmentDefinition, apis api.APIs)
 	return nil
 }
 
-func purgeForEnvironment(env manifest.EnvironmentDefinition, apis api.APIs) error {
+func purgeForEnvironment(fs afero.Fs, env manifest.EnvironmentDefinition, apis api.APIs, opts purgeOptions) error {
 
 	deleteClients, err := getClientSet(env)
 	if err != nil {
@@ -81,14 +150,70 @@ func purgeForEnvironment(env manifest.EnvironmentDefinition, apis api.APIs) erro
 
 	log.WithCtxFields(ctx).Info("Deleting configs for environment `%s`", env.Name)
 
-	if err := delete.All(ctx, deleteClients, apis); err != nil {
+	objects, collectErr := delete.Collect(ctx, deleteClients, apis, opts.excludedTypes)
+	if err := archiveObjects(ctx, fs, env, deleteClients, apis, objects, opts.archiveFolder); err != nil {
+		return err
+	}
+	if err := delete.Objects(ctx, deleteClients, apis, objects); err != nil || collectErr != nil {
 		log.Error("Encountered errors while puring configurations from environment %s, further manual cleanup may be needed - check logs for details.", env.Name)
 	}
 	return nil
 }
 
+// purgeReportedConfigs deletes the configurations listed for each environment in the report of a previous dry run
+func purgeReportedConfigs(fs afero.Fs, environments []manifest.EnvironmentDefinition, apis api.APIs, r report, opts purgeOptions) error {
+	allAPIs := api.NewAPIs()
+	for _, env := range environments {
+		ctx := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
+
+		objects, found := r.Environments[env.Name]
+		if !found {
+			log.WithCtxFields(ctx).Warn("Skipping environment %s as it is not part of the confirmed report", env.Name)
+			continue
+		}
+		objects = slices.DeleteFunc(slices.Clone(objects), func(o pointer.RemoteObject) bool {
+			return slices.Contains(opts.excludedTypes, o.Type) || (allAPIs.Contains(o.Type) && !apis.Contains(o.Type))
+		})
+
+		deleteClients, err := getClientSet(env)
+		if err != nil {
+			return err
+		}
+
+		if err := archiveObjects(ctx, fs, env, deleteClients, apis, objects, opts.archiveFolder); err != nil {
+			return err
+		}
+
+		log.WithCtxFields(ctx).Info("Deleting %d reviewed configs for environment `%s`", len(objects), env.Name)
+		if err := delete.Objects(ctx, deleteClients, apis, objects); err != nil {
+			log.Error("Encountered errors while puring configurations from environment %s, further manual cleanup may be needed - check logs for details.", env.Name)
+		}
+	}
+	return nil
+}
+
+// archiveObjects archives the given objects before they are purged, if an archive folder is given. If archiving fails,
+// the environment must not be purged.
+func archiveObjects(ctx context.Context, fs afero.Fs, env manifest.EnvironmentDefinition, clients delete.ClientSet, apis api.APIs, objects []pointer.RemoteObject, archiveFolder string) error {
+	if archiveFolder == "" || len(objects) == 0 {
+		return nil
+	}
+
+	var types []string
+	for _, o := range objects {
+		if !slices.Contains(types, o.Type) {
+			types = append(types, o.Type)
+		}
+	}
+
+	if _, err := archive.Archive(ctx, fs, archiveFolder, env, clients, apis, types, archive.ObjectsSelector(objects)); err != nil {
+		return fmt.Errorf("failed to archive configurations of environment %s, nothing was deleted: %w", env.Name, err)
+	}
+	return nil
+}
+
 func getClientSet(env manifest.EnvironmentDefinition) (delete.ClientSet, error) {
-	clients, err := dynatrace.CreateClients(env.URL.Value, env.Auth)
+	clients, err := dynatrace.CreateClientsForEnvironment(env)
 	if err != nil {
 		return delete.ClientSet{}, fmt.Errorf("failed to create a client for env `%s` due to the following error: %w", env.Name, err)
 	}
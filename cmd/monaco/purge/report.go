@@ -0,0 +1,85 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"github.com/spf13/afero"
+	"time"
+)
+
+// report lists the configurations a purge deletes per environment name. It is written by a dry run and read again
+// to confirm the purge.
+type report struct {
+	Environments map[string][]pointer.RemoteObject `json:"environments"`
+}
+
+func writeReport(fs afero.Fs, file string, r report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal purge report: %w", err)
+	}
+	if err := afero.WriteFile(fs, file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write purge report %q: %w", file, err)
+	}
+	return nil
+}
+
+func loadReport(fs afero.Fs, file string) (report, error) {
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return report{}, fmt.Errorf("failed to read purge report %q: %w", file, err)
+	}
+
+	var r report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return report{}, fmt.Errorf("failed to parse purge report %q: %w", file, err)
+	}
+	return r, nil
+}
+
+// logReport logs every object that would be deleted from the given environment
+func logReport(ctx context.Context, envName string, objects []pointer.RemoteObject, now time.Time) {
+	logger := log.WithCtxFields(ctx)
+	logger.Info("%d configurations would be deleted from environment %s:", len(objects), envName)
+	for _, o := range objects {
+		logger.Info("\t%s\t%s\t%s\tage: %s\tlast modified by: %s", o.Type, orDash(o.Name), o.ID, formatAge(o.Created, now), orDash(o.LastModifiedBy))
+	}
+}
+
+// formatAge returns the time passed since created in days, or hours for objects younger than a day
+func formatAge(created time.Time, now time.Time) string {
+	if created.IsZero() {
+		return "unknown"
+	}
+	age := now.Sub(created)
+	if age < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
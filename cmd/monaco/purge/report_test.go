@@ -0,0 +1,58 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package purge
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPurgeRequiresConfirmation(t *testing.T) {
+	err := purge(afero.NewMemMapFs(), "manifest.yaml", nil, nil, purgeOptions{})
+	assert.ErrorIs(t, err, errNotConfirmed)
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	r := report{Environments: map[string][]pointer.RemoteObject{
+		"env": {
+			{Type: "alerting-profile", ID: "1234", Name: "profile"},
+			{Type: "builtin:alerting.profile", ID: "vu9U3hXa3q0AAAABAB", Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), LastModifiedBy: "someone"},
+		},
+	}}
+
+	require.NoError(t, writeReport(fs, "report.json", r))
+	got, err := loadReport(fs, "report.json")
+	require.NoError(t, err)
+	assert.Equal(t, r, got)
+
+	_, err = loadReport(fs, "missing.json")
+	assert.Error(t, err)
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "unknown", formatAge(time.Time{}, now))
+	assert.Equal(t, "5h", formatAge(now.Add(-5*time.Hour), now))
+	assert.Equal(t, "3d", formatAge(now.Add(-75*time.Hour), now))
+}
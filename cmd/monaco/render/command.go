@@ -0,0 +1,98 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package render
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+)
+
+type options struct {
+	manifestPath string
+	environment  string
+	live         bool
+}
+
+func Command(fs afero.Fs) *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "render <coordinate>",
+		Short: "Render the payload of a single configuration, as it would be deployed to an environment",
+		Long: `Render the payload of a single configuration, as it would be deployed to an environment, and print it.
+
+The configuration is given by its coordinate 'project:type:configId'. Its parameters are resolved for the given environment.
+IDs of referenced configurations, and parameters which query the environment, are replaced by placeholders of the form
+'<project:type:configId:property>'. With '--live', they are resolved using the environment instead - configurations are
+not deployed in either case.`,
+		Example: "monaco render my-project:builtin:alerting.profile:my-profile -e dev-environment",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !files.IsYamlFileExtension(opts.manifestPath) {
+				return fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", opts.manifestPath)
+			}
+
+			coord, err := coordinate.Parse(args[0])
+			if err != nil {
+				return err
+			}
+
+			return render(cmd.Context(), fs, opts, coord, newClientFactory(fs, opts.manifestPath), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.manifestPath, "manifest", "m", "manifest.yaml", "Name (and the path) to the manifest file. Defaults to 'manifest.yaml'.")
+	cmd.Flags().StringVarP(&opts.environment, "environment", "e", "", "The environment to render the configuration for")
+	cmd.Flags().BoolVar(&opts.live, "live", false, "Resolve the IDs of referenced configurations, and parameters querying the environment, using the environment")
+
+	if err := cmd.MarkFlagRequired("environment"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return cmd
+}
+
+// newClientFactory returns a clientFactory creating the clients of the environment defined in the manifest.
+func newClientFactory(fs afero.Fs, manifestPath string) clientFactory {
+	return func(env string) (Client, error) {
+		m, errs := manifestloader.Load(&manifestloader.Context{
+			Fs:           fs,
+			ManifestPath: manifestPath,
+			Environments: []string{env},
+			Opts:         manifestloader.Options{RequireEnvironmentGroups: true},
+		})
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to load manifest %q: %w", manifestPath, errs[0])
+		}
+
+		clients, err := dynatrace.CreateClientsForEnvironment(m.Environments[env])
+		if err != nil {
+			return nil, err
+		}
+		return clients.DTClient, nil
+	}
+}
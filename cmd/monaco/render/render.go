@@ -0,0 +1,298 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	crossEnvironmentParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/crossenvironment"
+	entitySelectorParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entityselector"
+	httpLookupParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/httplookup"
+	managementZoneParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/managementzone"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+// Client is used to look up the IDs of deployed configs, if parameters are resolved live.
+type Client interface {
+	ConfigExistsByName(ctx context.Context, a api.API, name string) (exists bool, id string, err error)
+	ListSettings(ctx context.Context, schemaId string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error)
+}
+
+// clientFactory creates the Client for the environment, it is replaced in tests.
+type clientFactory func(env string) (Client, error)
+
+// loadConfigs loads all configs of the given environment from the manifest's projects that the project of the given
+// coordinate may reference.
+func loadConfigs(fs afero.Fs, manifestPath string, environment string, projectName string, resolveEnvVars bool) (map[coordinate.Coordinate]config.Config, error) {
+	m, errs := manifestloader.Load(&manifestloader.Context{
+		Fs:           fs,
+		ManifestPath: filepath.Clean(manifestPath),
+		Environments: []string{environment},
+		Opts: manifestloader.Options{
+			DoNotResolveEnvVars:      !resolveEnvVars,
+			RequireEnvironmentGroups: true,
+		},
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return nil, fmt.Errorf("failed to load manifest %q", manifestPath)
+	}
+
+	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
+		KnownApis:       api.NewAPIs().GetApiNameLookup(),
+		WorkingDir:      filepath.Dir(manifestPath),
+		Manifest:        m,
+		ParametersSerde: config.DefaultParameterParsers,
+	}, []string{projectName})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return nil, errors.New("failed to load projects")
+	}
+
+	configs := make(map[coordinate.Coordinate]config.Config)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			if c.Environment == environment {
+				configs[c.Coordinate] = c
+			}
+		})
+	}
+	return configs, nil
+}
+
+// render resolves the parameters of the config with the given coordinate and writes its rendered, indented payload.
+func render(ctx context.Context, fs afero.Fs, opts options, coord coordinate.Coordinate, newClient clientFactory, out io.Writer) error {
+	configs, err := loadConfigs(fs, opts.manifestPath, opts.environment, coord.Project, opts.live)
+	if err != nil {
+		return err
+	}
+
+	c, found := configs[coord]
+	if !found {
+		return fmt.Errorf("config %s is not defined for environment %q", coord, opts.environment)
+	}
+
+	r := &resolver{ctx: ctx, configs: configs, resolved: make(map[coordinate.Coordinate]entities.ResolvedEntity)}
+	if opts.live {
+		if r.client, err = newClient(opts.environment); err != nil {
+			return fmt.Errorf("failed to create a client for environment %q: %w", opts.environment, err)
+		}
+	}
+
+	c = r.prepare(c)
+	properties, errs := c.ResolveParameterValues(r)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to resolve parameters of config %s", coord)
+	}
+
+	rendered, err := c.Render(properties)
+	if err != nil {
+		return err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(rendered), "", "  "); err != nil {
+		return fmt.Errorf("failed to format payload of config %s: %w", coord, err)
+	}
+	indented.WriteString("\n")
+
+	_, err = out.Write(indented.Bytes())
+	return err
+}
+
+// resolver resolves the properties of the configs referenced by the rendered config. Unless it is resolving live,
+// the IDs of configs and parameters that require access to an environment are replaced by placeholders.
+type resolver struct {
+	ctx     context.Context
+	configs map[coordinate.Coordinate]config.Config
+	// client to look up the IDs of deployed configs - nil, if not resolving live
+	client Client
+
+	resolved  map[coordinate.Coordinate]entities.ResolvedEntity
+	resolving []coordinate.Coordinate
+}
+
+var _ config.EntityLookup = (*resolver)(nil)
+
+func (r *resolver) GetResolvedEntity(coord coordinate.Coordinate) (entities.ResolvedEntity, bool) {
+	if e, found := r.resolved[coord]; found {
+		return e, true
+	}
+
+	c, found := r.configs[coord]
+	if !found {
+		return entities.ResolvedEntity{}, false
+	}
+
+	for _, current := range r.resolving {
+		if current == coord {
+			// circular references are reported when deploying, the placeholders suffice for a preview
+			return entities.ResolvedEntity{Coordinate: coord, Properties: parameter.Properties{}, Skip: c.Skip}, true
+		}
+	}
+	r.resolving = append(r.resolving, coord)
+	defer func() { r.resolving = r.resolving[:len(r.resolving)-1] }()
+
+	c = r.prepare(c)
+	properties, errs := c.ResolveParameterValues(r)
+	for _, err := range errs {
+		log.WithFields(field.Coordinate(coord), field.Error(err)).Warn("Failed to resolve parameters of referenced config %s: %v", coord, err)
+	}
+	if properties == nil {
+		properties = parameter.Properties{}
+	}
+	properties[config.IdParameter] = r.id(c, properties)
+
+	e := entities.ResolvedEntity{Coordinate: coord, Properties: properties, Skip: c.Skip}
+	r.resolved[coord] = e
+	return e, true
+}
+
+func (r *resolver) GetResolvedProperty(coord coordinate.Coordinate, propertyName string) (any, bool) {
+	e, found := r.GetResolvedEntity(coord)
+	if !found {
+		return nil, false
+	}
+	if v, found := e.Properties[propertyName]; found {
+		return v, true
+	}
+	return placeholder(coord, propertyName), true
+}
+
+// id returns the ID of the deployed object of the config. Unless resolving live, a placeholder is returned.
+func (r *resolver) id(c config.Config, properties parameter.Properties) string {
+	if r.client == nil {
+		return placeholder(c.Coordinate, config.IdParameter)
+	}
+
+	id, err := r.lookupID(c, properties)
+	if err != nil {
+		log.WithFields(field.Coordinate(c.Coordinate), field.Error(err)).Warn("Failed to look up the ID of config %s, using a placeholder: %v", c.Coordinate, err)
+		return placeholder(c.Coordinate, config.IdParameter)
+	}
+	return id
+}
+
+func (r *resolver) lookupID(c config.Config, properties parameter.Properties) (string, error) {
+	switch t := c.Type.(type) {
+	case config.ClassicApiType:
+		a, found := api.NewAPIs()[t.Api]
+		if !found || a.HasParent() {
+			return "", fmt.Errorf("looking up IDs of configs of API %q is not supported", t.Api)
+		}
+		name, found := properties[config.NameParameter]
+		if !found {
+			return "", errors.New("config has no name")
+		}
+		exists, id, err := r.client.ConfigExistsByName(r.ctx, a, fmt.Sprint(name))
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", fmt.Errorf("no %s named %q exists", t.Api, name)
+		}
+		return id, nil
+
+	case config.SettingsType:
+		externalID, err := idutils.GenerateExternalIDForSettingsObject(c.Coordinate)
+		if err != nil {
+			return "", err
+		}
+		objects, err := r.client.ListSettings(r.ctx, t.SchemaId, dtclient.ListSettingsOptions{
+			DiscardValue: true,
+			Filter:       func(o dtclient.DownloadSettingsObject) bool { return o.ExternalId == externalID },
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(objects) == 0 {
+			return "", fmt.Errorf("no settings object of schema %q with external ID %q exists", t.SchemaId, externalID)
+		}
+		return objects[0].ObjectId, nil
+
+	default:
+		return "", fmt.Errorf("looking up IDs of %s configs is not supported", c.Type.ID())
+	}
+}
+
+// prepare returns a copy of the config, in which all parameters that require access to an environment are replaced
+// by placeholders, unless resolving live.
+func (r *resolver) prepare(c config.Config) config.Config {
+	if r.client != nil {
+		return c
+	}
+
+	params := make(config.Parameters, len(c.Parameters))
+	for name, p := range c.Parameters {
+		switch p := p.(type) {
+		case *entitySelectorParam.EntitySelectorParameter:
+			if p.Cardinality != entitySelectorParam.One {
+				params[name] = placeholderParameter(fmt.Sprintf("[ %q ]", placeholder(c.Coordinate, name)))
+				continue
+			}
+			params[name] = placeholderParameter(placeholder(c.Coordinate, name))
+		case *managementZoneParam.ManagementZoneParameter, *httpLookupParam.HTTPLookupParameter, *crossEnvironmentParam.CrossEnvironmentReferenceParameter:
+			params[name] = placeholderParameter(placeholder(c.Coordinate, name))
+		default:
+			params[name] = p
+		}
+	}
+	c.Parameters = params
+	return c
+}
+
+// placeholder is the value used for properties that can not be resolved without access to an environment
+func placeholder(coord coordinate.Coordinate, property string) string {
+	return fmt.Sprintf("<%s>", parameter.ParameterReference{Config: coord, Property: property})
+}
+
+// placeholderParameter replaces parameters that can not be resolved without access to an environment
+type placeholderParameter string
+
+var _ parameter.Parameter = placeholderParameter("")
+
+func (p placeholderParameter) GetType() string {
+	return "placeholder"
+}
+
+func (p placeholderParameter) GetReferences() []parameter.ParameterReference {
+	return nil
+}
+
+func (p placeholderParameter) ResolveValue(parameter.ResolveContext) (any, error) {
+	return string(p), nil
+}
@@ -0,0 +1,128 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+)
+
+type fakeClient struct {
+	ids map[string]string
+}
+
+func (f fakeClient) ConfigExistsByName(_ context.Context, _ api.API, name string) (bool, string, error) {
+	id, found := f.ids[name]
+	return found, id, nil
+}
+
+func (f fakeClient) ListSettings(context.Context, string, dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+	return nil, nil
+}
+
+func newTestFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: g
+    environments: [{name: e, url: {value: "https://abc.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}]
+`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "a/config.yaml", []byte(`
+configs:
+  - id: profile
+    config:
+      name: Profile
+      template: profile.json
+    type:
+      api: alerting-profile
+  - id: notification
+    config:
+      name: Notification
+      template: notification.json
+      parameters:
+        profileId: {type: reference, configType: alerting-profile, configId: profile, property: id}
+        profileName: {type: reference, configType: alerting-profile, configId: profile, property: name}
+        hosts: {type: entitySelector, selector: "type(HOST)", cardinality: any}
+    type:
+      api: notification
+`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "a/profile.json", []byte(`{"name": "{{ .name }}"}`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "a/notification.json", []byte(`{"name": "{{ .name }}", "profile": "{{ .profileId }}", "profileName": "{{ .profileName }}", "hosts": {{ .hosts }}}`), 0644))
+	return fs
+}
+
+func TestRender(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "a", Type: "notification", ConfigId: "notification"}
+	noClient := func(string) (Client, error) {
+		t.Fatal("no client must be created")
+		return nil, nil
+	}
+
+	t.Run("with placeholders", func(t *testing.T) {
+		var out bytes.Buffer
+		err := render(context.TODO(), newTestFs(t), options{manifestPath: "manifest.yaml", environment: "e"}, coord, noClient, &out)
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "name": "Notification",
+  "profile": "<a:alerting-profile:profile:id>",
+  "profileName": "Profile",
+  "hosts": [
+    "<a:notification:notification:hosts>"
+  ]
+}
+`, out.String())
+	})
+
+	t.Run("live", func(t *testing.T) {
+		t.Setenv("TOKEN", "token")
+		// entity selectors query the environment themselves, thus only the reference is resolved live here
+		fs := newTestFs(t)
+		content, err := afero.ReadFile(fs, "a/config.yaml")
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, "a/config.yaml", bytes.ReplaceAll(content, []byte(`hosts: {type: entitySelector, selector: "type(HOST)", cardinality: any}`), nil), 0644))
+		require.NoError(t, afero.WriteFile(fs, "a/notification.json", []byte(`{"profile": "{{ .profileId }}", "name": "{{ .name }}"}`), 0644))
+
+		var out bytes.Buffer
+		err = render(context.TODO(), fs, options{manifestPath: "manifest.yaml", environment: "e", live: true}, coord, func(string) (Client, error) {
+			return fakeClient{ids: map[string]string{"Profile": "profile-id"}}, nil
+		}, &out)
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "profile": "profile-id",
+  "name": "Notification"
+}
+`, out.String())
+	})
+
+	t.Run("unknown config", func(t *testing.T) {
+		err := render(context.TODO(), newTestFs(t), options{manifestPath: "manifest.yaml", environment: "e"}, coordinate.Coordinate{Project: "a", Type: "notification", ConfigId: "unknown"}, noClient, &bytes.Buffer{})
+		assert.EqualError(t, err, `config a:notification:unknown is not defined for environment "e"`)
+	})
+}
@@ -21,7 +21,11 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/download"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/generate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/lint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/purge"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/render"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/servemock"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/support"
 	versionCommand "github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
@@ -63,7 +67,7 @@ Examples:
   Deploy a specific environment within an manifest
     monaco deploy service.yaml -e dev`,
 
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			log.PrepareLogging(fs, verbose, logSpy, featureflags.LogToFile().Enabled() || support.SupportArchive)
 
 			s := cmd.Name()
@@ -74,6 +78,8 @@ Examples:
 			}
 
 			memory.SetDefaultLimit()
+
+			return support.StartHTTPDump(fs)
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
@@ -83,6 +89,8 @@ Examples:
 
 	// define finalizer method(s) run after cobra commands ran
 	cobra.OnFinalize(func() {
+		support.StopHTTPDump()
+
 		if support.SupportArchive {
 			if err := support.Archive(fs); err != nil {
 				log.WithFields(field.Error(err)).Error("Encountered error creating support archive. Archive may be missing or incomplete: %s", err)
@@ -93,6 +101,8 @@ Examples:
 	// global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
 	rootCmd.PersistentFlags().BoolVar(&support.SupportArchive, "support-archive", false, "Create support archive")
+	rootCmd.PersistentFlags().StringVar(&support.DumpHTTP, "dump-http", "", "Write all HTTP requests and responses to the given directory, with authentication and secrets redacted")
+	rootCmd.PersistentFlags().StringVar(&support.DumpHTTPFormat, "dump-http-format", support.DumpHTTPFormat, "Format of the HTTP dump, one of 'ndjson' or 'har'")
 
 	// commands
 	rootCmd.AddCommand(download.GetDownloadCommand(fs, &download.DefaultCommand{}))
@@ -101,6 +111,10 @@ Examples:
 	rootCmd.AddCommand(delete.GetDeleteCommand(fs))
 	rootCmd.AddCommand(versionCommand.GetVersionCommand())
 	rootCmd.AddCommand(generate.Command(fs))
+	rootCmd.AddCommand(manifest.Command(fs))
+	rootCmd.AddCommand(lint.Command(fs))
+	rootCmd.AddCommand(render.Command(fs))
+	rootCmd.AddCommand(servemock.Command())
 
 	if featureflags.AccountManagement().Enabled() {
 		rootCmd.AddCommand(account.Command(fs))
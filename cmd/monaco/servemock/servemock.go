@@ -0,0 +1,92 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servemock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/mockserver"
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout limits the time to wait for in-flight requests when the server is stopped
+const shutdownTimeout = 5 * time.Second
+
+func Command() *cobra.Command {
+	var address string
+
+	cmd := &cobra.Command{
+		Use:   "serve-mock",
+		Short: "Serve a mock Dynatrace environment emulating the classic config and Settings 2.0 APIs",
+		Long: `Serve a mock Dynatrace environment emulating the classic config and Settings 2.0 APIs, keeping all configurations in memory.
+
+Point the URL of an environment in your manifest to the mock server to deploy your projects without a live environment,
+e.g. in integration tests running in CI. Any access token is accepted. All configurations are lost when the server stops.`,
+		Example: "monaco serve-mock --address localhost:8080",
+		Args:    cobra.NoArgs,
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %q: %w", address, err)
+			}
+			return serve(ctx, listener, mockserver.New())
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "address", "localhost:8080", "Address the mock server listens on")
+
+	return cmd
+}
+
+// serve serves the handler on the listener until the context is cancelled
+func serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+	log.Info("Serving mock environment at http://%s - press Ctrl+C to stop", listener.Addr())
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("mock server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to stop mock server: %w", err)
+	}
+	log.Info("Mock server stopped")
+	return nil
+}
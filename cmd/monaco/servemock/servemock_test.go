@@ -0,0 +1,129 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servemock
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/mockserver"
+)
+
+func TestServe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- serve(ctx, listener, mockserver.New())
+	}()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+listener.Addr().String()+"/api/v1/config/clusterversion", nil)
+	req.Header.Set("Authorization", "Api-Token token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server did not stop")
+	}
+}
+
+func TestDeployToMockServer(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	server := mockserver.New()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = serve(ctx, listener, server)
+	}()
+
+	manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: mock
+    url:
+      value: http://` + listener.Addr().String() + `
+    auth:
+      token:
+        name: ENV_TOKEN
+`
+	configYaml := `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+- id: setting
+  config:
+    template: setting.json
+  type:
+    settings:
+      schema: builtin:test
+      scope: environment
+`
+	fs := afero.NewMemMapFs()
+	write := func(path, content string) {
+		p, _ := filepath.Abs(path)
+		require.NoError(t, afero.WriteFile(fs, p, []byte(content), 0644))
+	}
+	write("manifest.yaml", manifestYaml)
+	write("project/config/config.yaml", configYaml)
+	write("project/config/profile.json", `{"name": "{{ .name }}", "rules": []}`)
+	write("project/config/setting.json", `{"enabled": true}`)
+	manifestPath, _ := filepath.Abs("manifest.yaml")
+
+	// deploying twice must update the configurations created by the first deployment
+	for i := 0; i < 2; i++ {
+		cmd := deploy.GetDeployCommand(fs)
+		cmd.SetArgs([]string{manifestPath})
+		require.NoError(t, cmd.Execute())
+	}
+
+	require.Len(t, server.Configs(api.AlertingProfile), 1)
+	var profile map[string]any
+	require.NoError(t, json.Unmarshal(server.Configs(api.AlertingProfile)[0], &profile))
+	assert.Equal(t, "alerting-profile", profile["name"])
+	require.Len(t, server.Settings("builtin:test"), 1)
+	assert.JSONEq(t, `{"enabled": true}`, string(server.Settings("builtin:test")[0]))
+}
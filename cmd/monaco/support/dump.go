@@ -0,0 +1,68 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package support
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/spf13/afero"
+)
+
+// DumpHTTP is the directory all HTTP traffic is dumped to. Dumping is disabled if it is empty.
+var DumpHTTP string
+
+// DumpHTTPFormat is the format HTTP traffic is dumped in
+var DumpHTTPFormat = string(rest.DumpFormatNDJSON)
+
+var httpDump *rest.HTTPDump
+
+// StartHTTPDump prepares dumping all HTTP traffic of the clients to DumpHTTP, if set
+func StartHTTPDump(fs afero.Fs) error {
+	if DumpHTTP == "" {
+		return nil
+	}
+
+	d, err := rest.NewHTTPDump(fs, DumpHTTP, rest.DumpFormat(DumpHTTPFormat))
+	if err != nil {
+		return err
+	}
+	httpDump = d
+	return nil
+}
+
+// HTTPMiddlewares returns the middlewares all clients need to use, e.g. to dump their HTTP traffic
+func HTTPMiddlewares() []rest.Middleware {
+	if httpDump == nil {
+		return nil
+	}
+	return []rest.Middleware{httpDump}
+}
+
+// StopHTTPDump finishes dumping HTTP traffic, if it was started
+func StopHTTPDump() {
+	if httpDump == nil {
+		return
+	}
+
+	if err := httpDump.Close(); err != nil {
+		log.WithFields(field.Error(err)).Error("Failed to write HTTP dump %q: %v", httpDump.Path(), err)
+	} else {
+		log.Info("Saved HTTP dump to %s", httpDump.Path())
+	}
+	httpDump = nil
+}
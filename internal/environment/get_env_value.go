@@ -27,23 +27,31 @@ const (
 	ConcurrentRequestsEnvKey          = "MONACO_CONCURRENT_REQUESTS"
 	defaultValueKey                   = "DEFAULT"
 	KeyUserActionWebWaitSecondsEnvKey = "MONACO_KUA_WEB_WAIT_SECONDS"
+	SettingsBatchSizeEnvKey           = "MONACO_SETTINGS_BATCH_SIZE"
+	ConcurrentDownloadsPerAPIEnvKey   = "MONACO_CONCURRENT_DOWNLOADS_PER_API"
 )
 
 var defaultValuesInt = map[string]int{
 	ConcurrentRequestsEnvKey:          5,
 	defaultValueKey:                   0,
 	KeyUserActionWebWaitSecondsEnvKey: 1,
+	SettingsBatchSizeEnvKey:           1,
+	ConcurrentDownloadsPerAPIEnvKey:   0,
 }
 
 var logStringInt = map[string]string{
 	ConcurrentRequestsEnvKey:          "Concurrent Request Limit: %d, from '%s' environment variable",
 	defaultValueKey:                   "Environment variable %s: %d",
 	KeyUserActionWebWaitSecondsEnvKey: "Key User Action Web wait seconds: %d, from '%s' environment variable",
+	SettingsBatchSizeEnvKey:           "Settings batch size: %d, from '%s' environment variable",
+	ConcurrentDownloadsPerAPIEnvKey:   "Concurrent downloads per API: %d, from '%s' environment variable",
 }
 var logStringIntDefault = map[string]string{
 	ConcurrentRequestsEnvKey:          "Concurrent Request Limit: %d, '%s' environment variable is NOT set, using default value",
 	defaultValueKey:                   "Environment variable %s: %d, variable is NOT set, using default value",
 	KeyUserActionWebWaitSecondsEnvKey: "Key User Action Web wait seconds: %d, from '%s' environment variable is NOT set, using default value",
+	SettingsBatchSizeEnvKey:           "Settings batch size: %d, '%s' environment variable is NOT set, using default value",
+	ConcurrentDownloadsPerAPIEnvKey:   "Concurrent downloads per API: %d, '%s' environment variable is NOT set, using default value",
 }
 
 func getDefaultInt(env string) int {
@@ -73,6 +73,15 @@ func DownloadFilterClassicConfigs() FeatureFlag {
 	}
 }
 
+// DownloadRedactSecrets returns the feature flag controlling whether secret values in downloaded templates are
+// replaced by environment variable parameters.
+func DownloadRedactSecrets() FeatureFlag {
+	return FeatureFlag{
+		envName:        "MONACO_FEAT_DOWNLOAD_REDACT_SECRETS",
+		defaultEnabled: true,
+	}
+}
+
 // SkipVersionCheck returns the feature flag to control disabling the version check that happens at the end of each monaco run
 func SkipVersionCheck() FeatureFlag {
 	return FeatureFlag{
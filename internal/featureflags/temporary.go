@@ -132,7 +132,7 @@ func SkipReadOnlyAccountGroupUpdates() FeatureFlag {
 func Documents() FeatureFlag {
 	return FeatureFlag{
 		envName:        "MONACO_FEAT_DOCUMENTS",
-		defaultEnabled: false,
+		defaultEnabled: true,
 	}
 }
 
@@ -141,6 +141,6 @@ func Documents() FeatureFlag {
 func PersistSettingsOrder() FeatureFlag {
 	return FeatureFlag{
 		envName:        "MONACO_FEAT_PERSIST_SETTINGS_ORDER",
-		defaultEnabled: false,
+		defaultEnabled: true,
 	}
 }
@@ -20,13 +20,16 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"strings"
 )
 
+const documentExternalIDPrefix = "monaco-"
+
 // GenerateExternalIDForSettingsObject generates a string that serves as an external ID for a Settings 2.0 object.
 // It requires a [[coordinate.Coordinate]] as input and produces a string in the format "monaco:<BASE64_ENCODED_STR>"
 // If Type or ConfigId of the passed [[coordinate.Coordinate]] is empty, an error is returned
 func GenerateExternalIDForSettingsObject(c coordinate.Coordinate) (string, error) {
-	const prefix = "monaco:"
+	const prefix = settingsExternalIDPrefix
 	const externalIDMaxLength = 500
 
 	if c.Type == "" || c.ConfigId == "" {
@@ -57,7 +60,7 @@ func GenerateExternalIDForDocument(c coordinate.Coordinate) (string, error) {
 	const maxLength = 50
 
 	// prefix should be 7 characters
-	const prefix = "monaco-"
+	const prefix = documentExternalIDPrefix
 
 	// uuid should be 8 + 1 + 4 + 1 + 4 + 1 + 4 + 1 + 12 = 36 characters
 	uuid := GenerateUUIDFromCoordinate(c)
@@ -70,3 +73,10 @@ func GenerateExternalIDForDocument(c coordinate.Coordinate) (string, error) {
 	}
 	return externalID, nil
 }
+
+// IsExternalIDForDocument returns whether the given external ID of a document was generated by
+// GenerateExternalIDForDocument.
+func IsExternalIDForDocument(externalID string) bool {
+	uuid, found := strings.CutPrefix(externalID, documentExternalIDPrefix)
+	return found && IsUUID(uuid)
+}
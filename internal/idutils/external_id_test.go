@@ -132,3 +132,13 @@ func TestGenerateExternalIdRawIdParts(t *testing.T) {
 	copy(rawId, decoded)
 	assert.Equal(t, "project-name$schema-id$config-id", string(decoded))
 }
+
+func TestIsExternalIDForDocument(t *testing.T) {
+	id, err := GenerateExternalIDForDocument(coordinate.Coordinate{Project: "project-name", Type: "document", ConfigId: "config-id"})
+	assert.NoError(t, err)
+	assert.True(t, IsExternalIDForDocument(id))
+
+	assert.False(t, IsExternalIDForDocument(""))
+	assert.False(t, IsExternalIDForDocument("monaco-not-a-uuid"))
+	assert.False(t, IsExternalIDForDocument("some-other-external-id"))
+}
@@ -0,0 +1,137 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idutils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+)
+
+const (
+	// ManagedByTag marks a configuration as managed by monaco
+	ManagedByTag = "managed-by:monaco"
+
+	ownershipTagPrefix       = "monaco-"
+	projectTagPrefix         = ownershipTagPrefix + "project:"
+	configTagPrefix          = ownershipTagPrefix + "config:"
+	deployedAtTagPrefix      = ownershipTagPrefix + "deployed:"
+	settingsExternalIDPrefix = "monaco:"
+)
+
+// SLOOwnershipTagsPath is the path to the tags of the payload of an SLO, which are stamped with ownership tags
+var SLOOwnershipTagsPath = []string{"tags"}
+
+// GenerateOwnershipTags generates tags identifying a configuration as deployed by monaco. The tags contain the
+// project, the full coordinate of the configuration, and the time of the deployment, e.g.
+//
+//	managed-by:monaco
+//	monaco-project:my-project
+//	monaco-config:my-project:dashboard:my-dashboard
+//	monaco-deployed:2024-05-10T14:12:00Z
+//
+// The deployment time changes with every deployment. Use IsDeployedAtTag to leave it out when comparing payloads.
+func GenerateOwnershipTags(c coordinate.Coordinate, deployedAt time.Time) []string {
+	return []string{
+		ManagedByTag,
+		projectTagPrefix + c.Project,
+		configTagPrefix + c.String(),
+		deployedAtTagPrefix + deployedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// IsOwnershipTag returns whether the given tag is one of the tags generated by GenerateOwnershipTags
+func IsOwnershipTag(tag string) bool {
+	return tag == ManagedByTag || strings.HasPrefix(tag, projectTagPrefix) || strings.HasPrefix(tag, configTagPrefix) || IsDeployedAtTag(tag)
+}
+
+// IsDeployedAtTag returns whether the given tag is the deployment time tag generated by GenerateOwnershipTags
+func IsDeployedAtTag(tag string) bool {
+	return strings.HasPrefix(tag, deployedAtTagPrefix)
+}
+
+// StampOwnershipTags adds the given ownership tags to the string array at the given path of the JSON payload, e.g.
+// "dashboardMetadata.tags". Missing objects along the path are created. Ownership tags of previous deployments are
+// replaced, all other tags are kept.
+func StampOwnershipTags(payload string, path []string, tags []string) (string, error) {
+	if len(path) == 0 {
+		return "", fmt.Errorf("no path to stamp ownership tags at")
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(payload), &obj); err != nil {
+		return "", fmt.Errorf("failed to unmarshal payload to add ownership tags: %w", err)
+	}
+
+	parent := obj
+	for _, key := range path[:len(path)-1] {
+		child, ok := parent[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			parent[key] = child
+		}
+		parent = child
+	}
+
+	tagsKey := path[len(path)-1]
+	existing, _ := parent[tagsKey].([]any)
+	stamped := make([]any, 0, len(existing)+len(tags))
+	for _, t := range existing {
+		if s, ok := t.(string); ok && IsOwnershipTag(s) {
+			continue
+		}
+		stamped = append(stamped, t)
+	}
+	for _, t := range tags {
+		stamped = append(stamped, t)
+	}
+	parent[tagsKey] = stamped
+
+	result, err := json.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload with ownership tags: %w", err)
+	}
+	return string(result), nil
+}
+
+// DecodeExternalIDForSettingsObject returns the coordinate encoded in an external ID generated by
+// GenerateExternalIDForSettingsObject. It returns an error if the external ID was not generated by monaco.
+// Coordinates of legacy external IDs, which were generated without a project, have an empty Project.
+func DecodeExternalIDForSettingsObject(externalID string) (coordinate.Coordinate, error) {
+	encoded, found := strings.CutPrefix(externalID, settingsExternalIDPrefix)
+	if !found {
+		return coordinate.Coordinate{}, fmt.Errorf("external id %q was not generated by monaco", externalID)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return coordinate.Coordinate{}, fmt.Errorf("failed to decode external id %q: %w", externalID, err)
+	}
+
+	switch parts := strings.Split(string(decoded), "$"); len(parts) {
+	case 2:
+		return coordinate.Coordinate{Type: parts[0], ConfigId: parts[1]}, nil
+	case 3:
+		return coordinate.Coordinate{Project: parts[0], Type: parts[1], ConfigId: parts[2]}, nil
+	default:
+		return coordinate.Coordinate{}, fmt.Errorf("external id %q does not contain a valid coordinate", externalID)
+	}
+}
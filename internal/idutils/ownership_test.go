@@ -0,0 +1,108 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateOwnershipTags(t *testing.T) {
+	c := coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dash"}
+	deployedAt := time.Date(2024, 5, 10, 16, 12, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tags := GenerateOwnershipTags(c, deployedAt)
+	assert.Equal(t, []string{
+		"managed-by:monaco",
+		"monaco-project:project",
+		"monaco-config:project:dashboard:dash",
+		"monaco-deployed:2024-05-10T14:12:00Z",
+	}, tags)
+
+	for _, tag := range tags {
+		assert.True(t, IsOwnershipTag(tag), tag)
+	}
+	assert.False(t, IsOwnershipTag("owner:team-a"))
+
+	assert.True(t, IsDeployedAtTag("monaco-deployed:2024-05-10T14:12:00Z"))
+	assert.False(t, IsDeployedAtTag("monaco-project:project"))
+}
+
+func TestStampOwnershipTags(t *testing.T) {
+	tags := []string{"managed-by:monaco", "monaco-project:project"}
+
+	t.Run("tags are added at a top level path", func(t *testing.T) {
+		got, err := StampOwnershipTags(`{"name": "slo", "tags": ["team-a"]}`, SLOOwnershipTagsPath, tags)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name": "slo", "tags": ["team-a", "managed-by:monaco", "monaco-project:project"]}`, got)
+	})
+
+	t.Run("missing objects along the path are created", func(t *testing.T) {
+		got, err := StampOwnershipTags(`{}`, []string{"metadata", "tags"}, tags)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"metadata": {"tags": ["managed-by:monaco", "monaco-project:project"]}}`, got)
+	})
+
+	t.Run("stamping twice yields the same payload", func(t *testing.T) {
+		once, err := StampOwnershipTags(`{"tags": ["team-a"]}`, SLOOwnershipTagsPath, tags)
+		assert.NoError(t, err)
+		twice, err := StampOwnershipTags(once, SLOOwnershipTagsPath, tags)
+		assert.NoError(t, err)
+		assert.JSONEq(t, once, twice)
+	})
+
+	t.Run("invalid JSON or an empty path return an error", func(t *testing.T) {
+		_, err := StampOwnershipTags(`{`, SLOOwnershipTagsPath, tags)
+		assert.Error(t, err)
+
+		_, err = StampOwnershipTags(`{}`, nil, tags)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeExternalIDForSettingsObject(t *testing.T) {
+	tests := []struct {
+		name       string
+		coordinate coordinate.Coordinate
+	}{
+		{"with project", coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"}},
+		{"legacy without project", coordinate.Coordinate{Type: "builtin:alerting.profile", ConfigId: "profile"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			externalID, err := GenerateExternalIDForSettingsObject(tc.coordinate)
+			assert.NoError(t, err)
+
+			decoded, err := DecodeExternalIDForSettingsObject(externalID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.coordinate, decoded)
+		})
+	}
+
+	t.Run("external IDs not generated by monaco return an error", func(t *testing.T) {
+		_, err := DecodeExternalIDForSettingsObject("some-external-id")
+		assert.Error(t, err)
+
+		_, err = DecodeExternalIDForSettingsObject("monaco:not base64")
+		assert.Error(t, err)
+	})
+}
@@ -59,13 +59,16 @@ func Type[X ~string](t X) Field {
 	return Field{"type", t}
 }
 
+// LogEnvironment is the type to be used to log environments as context fields
+type LogEnvironment struct {
+	Group string `json:"group"`
+	Name  string `json:"name"`
+}
+
 // Environment builds a Field containing environment information for structured logging
 func Environment(environment, group string) Field {
 	return Field{"environment",
-		struct {
-			Group string `json:"group"`
-			Name  string `json:"name"`
-		}{
+		LogEnvironment{
 			group,
 			environment,
 		}}
@@ -17,8 +17,10 @@
 package console
 
 import (
+	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/loggers"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	"github.com/go-logr/logr"
 	"log"
 )
@@ -37,23 +39,23 @@ func (l Logger) WithFields(fields ...field.Field) loggers.Logger {
 }
 
 func (l Logger) Info(msg string, args ...interface{}) {
-	l.consoleLogger.Printf("INFO "+msg+"\n", args...)
+	l.consoleLogger.Print(secret.Mask(fmt.Sprintf("INFO "+msg+"\n", args...)))
 }
 
 func (l Logger) Error(msg string, args ...interface{}) {
-	l.consoleLogger.Printf("ERROR "+msg+"\n", args...)
+	l.consoleLogger.Print(secret.Mask(fmt.Sprintf("ERROR "+msg+"\n", args...)))
 }
 
 func (l Logger) Debug(msg string, args ...interface{}) {
-	l.consoleLogger.Printf("DEBUG "+msg+"\n", args...)
+	l.consoleLogger.Print(secret.Mask(fmt.Sprintf("DEBUG "+msg+"\n", args...)))
 }
 
 func (l Logger) Warn(msg string, args ...interface{}) {
-	l.consoleLogger.Printf("WARN "+msg+"\n", args...)
+	l.consoleLogger.Print(secret.Mask(fmt.Sprintf("WARN "+msg+"\n", args...)))
 }
 
 func (l Logger) Fatal(msg string, args ...interface{}) {
-	l.consoleLogger.Fatalf("FATAL "+msg+"\n", args...)
+	l.consoleLogger.Fatal(secret.Mask(fmt.Sprintf("FATAL "+msg+"\n", args...)))
 }
 
 func (l Logger) Level() loggers.LogLevel {
@@ -40,7 +40,7 @@ func newFixedFieldsConsoleEncoder() zapcore.Encoder {
 }
 
 // fixedFieldsConsoleEncoder is a custom console encoder that prints only prints the context
-// fields with key "coordinate" and "componentId" (currently hard coded). Further,
+// fields with key "environment", "coordinate" and "componentId" (currently hard coded). Further,
 // it takes care that the context is printed before that actual message and after the log level
 type fixedFieldsConsoleEncoder struct {
 	*concurrentMapObjectEncoder
@@ -68,6 +68,13 @@ func (e fixedFieldsConsoleEncoder) EncodeEntry(entry zapcore.Entry, _ []zapcore.
 	line.AppendString("\t")
 
 	additionalTab := false
+	if f, ok := e.Fields()["environment"]; ok {
+		if logEnvironment, ook := f.(field.LogEnvironment); ook {
+			additionalTab = true
+			line.AppendString(fmt.Sprintf("[%s=%v]", "env", logEnvironment.Name))
+		}
+	}
+
 	if f, ok := e.Fields()["coordinate"]; ok {
 		additionalTab = true
 		if logCoordinate, ook := f.(field.LogCoordinate); ook {
@@ -89,3 +89,25 @@ func TestEncodeEntry_UsesFieldsFromObjectEncoder(t *testing.T) {
 	expectedOutput := "2023-07-27T12:34:56Z\tinfo\t[coord=a:b:c][gid=4]\tTest log message\n"
 	assert.Equal(t, expectedOutput, buffer.String(), "Unexpected encoded output")
 }
+
+func TestEncodeEntry_PrefixesEnvironment(t *testing.T) {
+	objEnc := zapcore.NewMapObjectEncoder()
+	objEnc.Fields = map[string]interface{}{"environment": field.LogEnvironment{Name: "env1", Group: "group"}, "coordinate": field.LogCoordinate{Reference: "a:b:c"}}
+	encoder := fixedFieldsConsoleEncoder{
+		concurrentMapObjectEncoder: &concurrentMapObjectEncoder{
+			mu:  sync.RWMutex{},
+			moe: objEnc,
+		},
+	}
+	entry := zapcore.Entry{
+		Time:    time.Date(2023, 7, 27, 12, 34, 56, 0, time.UTC),
+		Level:   zapcore.InfoLevel,
+		Message: "Test log message",
+	}
+
+	buffer, err := encoder.EncodeEntry(entry, nil)
+	assert.NoError(t, err, "Error encoding entry")
+
+	expectedOutput := "2023-07-27T12:34:56Z\tinfo\t[env=env1][coord=a:b:c]\tTest log message\n"
+	assert.Equal(t, expectedOutput, buffer.String(), "Unexpected encoded output")
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/loggers"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
@@ -48,25 +49,25 @@ func (l *Logger) WithFields(fields ...field.Field) loggers.Logger {
 
 // Info logs an info-level message
 func (l *Logger) Info(msg string, args ...interface{}) {
-	l.baseLogger.Info(fmt.Sprintf(msg, args...))
+	l.baseLogger.Info(secret.Mask(fmt.Sprintf(msg, args...)))
 }
 
 // Error logs an error-level message
 func (l *Logger) Error(msg string, args ...interface{}) {
-	l.baseLogger.Error(fmt.Sprintf(msg, args...))
+	l.baseLogger.Error(secret.Mask(fmt.Sprintf(msg, args...)))
 }
 
 // Debug logs a debug-level message
 func (l *Logger) Debug(msg string, args ...interface{}) {
-	l.baseLogger.Debug(fmt.Sprintf(msg, args...))
+	l.baseLogger.Debug(secret.Mask(fmt.Sprintf(msg, args...)))
 }
 
 func (l *Logger) Warn(msg string, args ...interface{}) {
-	l.baseLogger.Warn(fmt.Sprintf(msg, args...))
+	l.baseLogger.Warn(secret.Mask(fmt.Sprintf(msg, args...)))
 }
 
 func (l *Logger) Fatal(msg string, args ...interface{}) {
-	l.baseLogger.Fatal(fmt.Sprintf(msg, args...))
+	l.baseLogger.Fatal(secret.Mask(fmt.Sprintf(msg, args...)))
 }
 
 func (l *Logger) Level() loggers.LogLevel {
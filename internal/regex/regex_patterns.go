@@ -70,7 +70,7 @@ func IsSimpleValueDefinition(s string) bool {
 // ListVariableRegexPattern matching list references in a Template,
 // capturing the variable name as well as the enclosing square bracket block
 // Sample format: "listKey": [ {{.list_variable}} ], captures: "[ {{.list_variable}} ]" and "list_variable"
-var ListVariableRegexPattern = regexp.MustCompile(`"[\w\s]+"\s*:\s*(\[\s*\{\{\s*\.([\w]+)\s*}}\s*])`)
+var ListVariableRegexPattern = regexp.MustCompile(`"[^"]+"\s*:\s*(\[\s*\{\{\s*\.([\w]+)\s*}}\s*])`)
 
 func MatchListVariable(s string) (fullMatch string, listMatch string, variableName string, err error) {
 	match := ListVariableRegexPattern.FindStringSubmatch(s)
@@ -205,6 +205,7 @@ func TestListVariableRegex(t *testing.T) {
 "list": [{{.expectedValue}}]`,
 		`  "list  " : [
   {{.expectedValue}}],`,
+		`"list-with.special_chars": [ {{ .expectedValue }} ]`,
 		`"lists": {
 "
 list"   :
@@ -0,0 +1,55 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"strings"
+	"sync"
+)
+
+// masked holds all values registered with [RegisterMaskedValue]
+var masked = struct {
+	sync.RWMutex
+	values []string
+}{}
+
+// RegisterMaskedValue registers a sensitive value that is replaced in all log output by [Mask]. Values shorter than
+// four characters are not registered, as masking them would garble unrelated log output.
+func RegisterMaskedValue(value string) {
+	if len(value) < 4 {
+		return
+	}
+
+	masked.Lock()
+	defer masked.Unlock()
+	for _, v := range masked.values {
+		if v == value {
+			return
+		}
+	}
+	masked.values = append(masked.values, value)
+}
+
+// Mask replaces all values registered with [RegisterMaskedValue] in the given string.
+func Mask(s string) string {
+	masked.RLock()
+	defer masked.RUnlock()
+	for _, v := range masked.values {
+		s = strings.ReplaceAll(s, v, MaskedString(v).String())
+	}
+	return s
+}
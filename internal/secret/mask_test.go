@@ -0,0 +1,34 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "nothing registered s3cr3t-value", Mask("nothing registered s3cr3t-value"))
+
+	RegisterMaskedValue("s3cr3t-value")
+	RegisterMaskedValue("s3cr3t-value")
+	RegisterMaskedValue("abc")
+
+	assert.Equal(t, `token "****" and "****" - abc`, Mask(`token "s3cr3t-value" and "s3cr3t-value" - abc`))
+}
@@ -0,0 +1,114 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template
+
+import (
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// Annotation holds the comment and the anchor of a yaml node
+type Annotation struct {
+	// Comment of the node, without the leading '#' of each line
+	Comment string
+	// Anchor is the name of the anchor the node's value was defined with or referenced by
+	Anchor string
+}
+
+// Annotations of a yaml file of a config or environment definition, by top-level key and list key.
+// The annotations of the top-level keys themselves are stored with an empty list key.
+type Annotations map[string]map[string]Annotation
+
+// UnmarshalYamlAnnotations takes the contents of a yaml file in the format of UnmarshalYaml and returns the comments
+// and anchors of its entries, which are lost when unmarshalling the yaml into a map.
+func UnmarshalYamlAnnotations(text string) (Annotations, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(ensureAnyTemplateStringsAreInQuotes(text)), &doc); err != nil {
+		return nil, err
+	}
+
+	result := make(Annotations)
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return result, nil
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, list := root.Content[i], root.Content[i+1]
+
+		entries := make(map[string]Annotation)
+		if a := newAnnotation(key, list); a != (Annotation{}) {
+			entries[""] = a
+		}
+
+		if list.Kind == yaml.SequenceNode {
+			for _, item := range list.Content {
+				if item.Kind != yaml.MappingNode {
+					continue
+				}
+				for j := 0; j+1 < len(item.Content); j += 2 {
+					a := newAnnotation(item.Content[j], item.Content[j+1])
+					if j == 0 {
+						a.Comment = joinComments(commentText(item.HeadComment), a.Comment)
+					}
+					if a != (Annotation{}) {
+						entries[item.Content[j].Value] = a
+					}
+				}
+			}
+		}
+
+		if len(entries) > 0 {
+			result[key.Value] = entries
+		}
+	}
+	return result, nil
+}
+
+func newAnnotation(key, value *yaml.Node) Annotation {
+	a := Annotation{
+		Comment: joinComments(commentText(key.HeadComment), commentText(key.LineComment), commentText(value.HeadComment), commentText(value.LineComment)),
+		Anchor:  value.Anchor,
+	}
+	if value.Kind == yaml.AliasNode {
+		a.Anchor = value.Value
+	}
+	return a
+}
+
+// commentText removes the comment markers from each line of the comment
+func commentText(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	lines := strings.Split(comment, "\n")
+	for i, l := range lines {
+		l = strings.TrimPrefix(strings.TrimSpace(l), "#")
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func joinComments(comments ...string) string {
+	var nonEmpty []string
+	for _, c := range comments {
+		if c != "" {
+			nonEmpty = append(nonEmpty, c)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
@@ -0,0 +1,61 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUnmarshalYamlAnnotations(t *testing.T) {
+	annotations, err := UnmarshalYamlAnnotations(`config:
+  # the main profile
+  - profile: "profile.json"
+
+# alerting for all services
+profile:
+  # shown in the UI
+  # keep short
+  - name: &profileName "Services" # line comment
+  - threshold: {{ .Env.THRESHOLD }}
+
+other:
+  - name: *profileName
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, Annotations{
+		"config": {
+			"profile": {Comment: "the main profile"},
+		},
+		"profile": {
+			"":     {Comment: "alerting for all services"},
+			"name": {Comment: "shown in the UI\nkeep short\nline comment", Anchor: "profileName"},
+		},
+		"other": {
+			"name": {Anchor: "profileName"},
+		},
+	}, annotations)
+}
+
+func TestUnmarshalYamlAnnotations_FailsOnInvalidYaml(t *testing.T) {
+	_, err := UnmarshalYamlAnnotations("config:\n  - a: *unknownAnchor")
+	assert.Error(t, err)
+}
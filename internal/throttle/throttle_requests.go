@@ -17,6 +17,7 @@
 package throttle
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"time"
@@ -35,7 +36,7 @@ func ThrottleCallAfterError(backoffMultiplier int, message string, a ...any) {
 	sleepDuration = ApplyMinMaxDefaults(sleepDuration)
 
 	log.Debug("simpleSleepRateLimitStrategy: %s, waiting %d seconds until %s to avoid Too Many Request errors", fmt.Sprintf(message, a...), sleepDuration.Seconds(), humanReadableTimestamp)
-	timelineProvider.Sleep(sleepDuration)
+	_ = timelineProvider.Sleep(context.Background(), sleepDuration)
 	log.Debug("simpleSleepRateLimitStrategy: Slept for %f seconds", sleepDuration.Seconds())
 }
 
@@ -17,6 +17,7 @@
 package timeutils
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -32,8 +33,9 @@ type TimelineProvider interface {
 	// Now Returns the current (client-side) time in UTC
 	Now() time.Time
 
-	// Sleep suspends the current goroutine for the specified duration
-	Sleep(duration time.Duration)
+	// Sleep suspends the current goroutine for the specified duration, or until the given context is done. If the
+	// context is done first, its error is returned.
+	Sleep(ctx context.Context, duration time.Duration) error
 }
 
 // NewTimelineProvider creates a new TimelineProvider
@@ -50,8 +52,22 @@ func (d *defaultTimelineProvider) Now() time.Time {
 	return nowInLocalTimeZone.In(location)
 }
 
-func (d *defaultTimelineProvider) Sleep(duration time.Duration) {
-	time.Sleep(duration)
+func (d *defaultTimelineProvider) Sleep(ctx context.Context, duration time.Duration) error {
+	return SleepWithContext(ctx, duration)
+}
+
+// SleepWithContext suspends the current goroutine for the specified duration, or until the given context is done.
+// If the context is done first, its error is returned.
+func SleepWithContext(ctx context.Context, duration time.Duration) error {
+	t := time.NewTimer(duration)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // StringTimestampToHumanReadableFormat parses and sanity-checks a unix timestamp as string and returns it
@@ -19,6 +19,7 @@
 package timeutils
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -56,3 +57,13 @@ func TestTimelineProviderReturnsUTC(t *testing.T) {
 	location, _ := time.LoadLocation("UTC")
 	require.Equal(t, now.UnixNano(), now.In(location).UnixNano())
 }
+
+func TestSleepWithContext(t *testing.T) {
+	assert.NoError(t, SleepWithContext(context.TODO(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	start := time.Now()
+	assert.ErrorIs(t, SleepWithContext(ctx, time.Minute), context.Canceled)
+	assert.Less(t, time.Since(start), time.Minute)
+}
@@ -21,6 +21,7 @@ import (
 	lib "github.com/dynatrace/dynatrace-configuration-as-code-core/api/rest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
 	"github.com/google/uuid"
 	"github.com/spf13/afero"
@@ -132,7 +133,7 @@ func (l *FileBasedLogger) logRequest(id string, request *http.Request, body io.R
 	// write body
 	if body != nil {
 		defer body.Close()
-		if _, err := io.Copy(l.requestLogFile, body); err != nil {
+		if err := writeMasked(l.requestLogFile, body); err != nil {
 			return err
 		}
 	}
@@ -170,7 +171,7 @@ func (l *FileBasedLogger) logResponse(id string, response *http.Response, body i
 	// write body
 	if body != nil {
 		defer body.Close()
-		if _, err := io.Copy(l.responseLogFile, body); err != nil {
+		if err := writeMasked(l.responseLogFile, body); err != nil {
 			return err
 		}
 	}
@@ -181,6 +182,17 @@ func (l *FileBasedLogger) logResponse(id string, response *http.Response, body i
 	}
 	return l.responseLogFile.Sync()
 }
+
+// writeMasked writes the given body to the log file, masking all sensitive values registered with [secret.RegisterMaskedValue]
+func writeMasked(file afero.File, body io.Reader) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(secret.Mask(string(b)))
+	return err
+}
+
 func (l *FileBasedLogger) openRequestLogFile() error {
 	if l.requestLogFile == nil {
 
@@ -76,6 +76,9 @@ type API struct {
 	// DeployWaitDuration defines the amount of time that shall elapse between deploying configs of this type.
 	// Note, that this only applies to configs within the same independent graph component
 	DeployWaitDuration time.Duration
+	// OwnershipTagsPath is the path to a string array property of the payload holding tags, e.g. "dashboardMetadata.tags".
+	// If set, configs of this type can be stamped with ownership tags identifying them as deployed by monaco.
+	OwnershipTagsPath []string
 }
 
 func (a API) CreateURL(environmentURL string) string {
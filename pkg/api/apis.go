@@ -16,7 +16,10 @@
 
 package api
 
-import "golang.org/x/exp/maps"
+import (
+	"golang.org/x/exp/maps"
+	"path"
+)
 
 // APIs is a collection of API
 type APIs map[string]API
@@ -92,6 +95,35 @@ func RetainByName(apis []string) Filter {
 	}
 }
 
+// RetainByPattern creates a Filter that leaves the API in the map if API.ID matches any of the provided glob patterns
+// (see path.Match). If the provided list is empty, a no-op filter is returned.
+func RetainByPattern(patterns []string) Filter {
+	if len(patterns) == 0 {
+		return noFilter
+	}
+
+	return func(api API) bool {
+		return !matchesAny(api.ID, patterns)
+	}
+}
+
+// RemoveByPattern creates a Filter that removes every API whose API.ID matches any of the provided glob patterns
+// (see path.Match).
+func RemoveByPattern(patterns []string) Filter {
+	return func(api API) bool {
+		return matchesAny(api.ID, patterns)
+	}
+}
+
+func matchesAny(id string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNames return names of API contained by this structure
 func (apis APIs) GetNames() []string {
 	return maps.Keys(apis)
@@ -164,6 +164,39 @@ func TestApiMapFilter(t *testing.T) {
 				apis: api.APIs{},
 			},
 		},
+		{
+			name: "RetainByPattern - with glob",
+			given: given{
+				apis: api.APIs{
+					"api_1":   api.API{ID: "api_1"},
+					"api_2":   api.API{ID: "api_2"},
+					"other_1": api.API{ID: "other_1"},
+				},
+				filters: []api.Filter{api.RetainByPattern([]string{"api_*"})},
+			},
+			expected: expected{
+				apis: api.APIs{
+					"api_1": api.API{ID: "api_1"},
+					"api_2": api.API{ID: "api_2"},
+				},
+			},
+		},
+		{
+			name: "RemoveByPattern - with glob",
+			given: given{
+				apis: api.APIs{
+					"api_1":   api.API{ID: "api_1"},
+					"api_2":   api.API{ID: "api_2"},
+					"other_1": api.API{ID: "other_1"},
+				},
+				filters: []api.Filter{api.RetainByPattern([]string{"api_*"}), api.RemoveByPattern([]string{"*_2"})},
+			},
+			expected: expected{
+				apis: api.APIs{
+					"api_1": api.API{ID: "api_1"},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,64 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+const (
+	// ClusterUserGroups are the user groups of a Managed cluster
+	ClusterUserGroups = "cluster-user-group"
+	// ClusterEnvironments are the environments of a Managed cluster, including their license quotas
+	ClusterEnvironments = "cluster-environment"
+	// ClusterProxy is the proxy a Managed cluster uses to connect to the internet
+	ClusterProxy = "cluster-proxy"
+)
+
+// ClusterAPI defines an endpoint of the Cluster API of a Dynatrace Managed cluster. Unlike an API, it configures the
+// cluster itself instead of one of its environments.
+type ClusterAPI struct {
+	ID string
+	// URLPath is the path of the endpoint, relative to the cluster URL
+	URLPath string
+	// PropertyNameOfGetAllResponse is the property of list responses holding the objects. If it is empty, list
+	// responses are JSON arrays of the objects.
+	PropertyNameOfGetAllResponse string
+	// SingleConfiguration are those endpoints holding a single configuration of the cluster, which is updated in place.
+	// It can't be created or deleted.
+	SingleConfiguration bool
+	// UpdateWithIDInPayload indicates that objects are updated by sending them including their ID to URLPath, instead
+	// of sending them to URLPath/{id}
+	UpdateWithIDInPayload bool
+}
+
+// NewClusterAPIs returns all supported endpoints of the Cluster API, by their ID
+func NewClusterAPIs() map[string]ClusterAPI {
+	return map[string]ClusterAPI{
+		ClusterUserGroups: {
+			ID:                    ClusterUserGroups,
+			URLPath:               "/api/v1.0/onpremise/groups",
+			UpdateWithIDInPayload: true,
+		},
+		ClusterEnvironments: {
+			ID:                           ClusterEnvironments,
+			URLPath:                      "/api/cluster/v2/environments",
+			PropertyNameOfGetAllResponse: "environments",
+		},
+		ClusterProxy: {
+			ID:                  ClusterProxy,
+			URLPath:             "/api/v1.0/onpremise/proxy/configuration",
+			SingleConfiguration: true,
+		},
+	}
+}
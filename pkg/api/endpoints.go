@@ -109,6 +109,7 @@ func NewAPIs() APIs {
 			URLPath:                      "/api/config/v1/dashboards",
 			PropertyNameOfGetAllResponse: "dashboards",
 			NonUniqueName:                true,
+			OwnershipTagsPath:            []string{"dashboardMetadata", "tags"},
 		}
 
 		// ApplicationWeb has KeyUserActionsWeb as a child API and so is defined here explicitly
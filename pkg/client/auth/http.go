@@ -19,7 +19,7 @@ package auth
 import (
 	"context"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
-	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2"
 	"net/http"
 	"strings"
 )
@@ -35,21 +35,45 @@ type OauthCredentials struct {
 
 // NewTokenAuthClient creates a new HTTP client that supports token based authorization
 func NewTokenAuthClient(token string) *http.Client {
+	return NewTokenAuthClientWithBase(&http.Client{}, token)
+}
+
+// NewTokenAuthClientWithBase returns a client authenticating using the given token, which sends requests using the
+// transport of base and honors its timeout.
+func NewTokenAuthClientWithBase(base *http.Client, token string) *http.Client {
 	if !isNewDynatraceTokenFormat(token) {
 		log.Warn("The supplied token does not match the expected format and may be invalid. If authentication fails, please check your manifest and environment variable configuration.\nIf you are using a token created before Dynatrace 1.205, please consider generating a new token: https://www.dynatrace.com/support/help/shortlink/api-authentication")
 	}
-	return &http.Client{Transport: NewTokenAuthTransport(nil, token)}
+	return &http.Client{Transport: NewTokenAuthTransport(base.Transport, token), Timeout: base.Timeout}
 }
 
-// NewOAuthClient creates a new HTTP client that supports OAuth2 client credentials based authorization
+// NewOAuthClient creates a new HTTP client that supports OAuth2 client credentials based authorization.
+// Tokens are cached and refreshed shortly before they expire. All clients created for the same credentials share their
+// tokens, so that tokens are not requested again for every client.
 func NewOAuthClient(ctx context.Context, oauthConfig OauthCredentials) *http.Client {
-	config := clientcredentials.Config{
-		ClientID:     oauthConfig.ClientID,
-		ClientSecret: oauthConfig.ClientSecret,
-		TokenURL:     oauthConfig.TokenURL,
-		Scopes:       oauthConfig.Scopes,
+	return NewOAuthClientWithBase(ctx, &http.Client{}, oauthConfig)
+}
+
+// NewOAuthClientWithBase returns a client authenticating using the given OAuth credentials, which sends requests -
+// including the ones requesting tokens - using the transport of base and honors its timeout.
+func NewOAuthClientWithBase(ctx context.Context, base *http.Client, oauthConfig OauthCredentials) *http.Client {
+	transport := base.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
 	}
-	return config.Client(ctx)
+	return &http.Client{
+		Transport: &OAuthTransport{
+			base:   transport,
+			source: newCachedTokenSource(context.WithValue(ctx, oauth2.HTTPClient, base), oauthConfig),
+		},
+		Timeout: base.Timeout,
+	}
+}
+
+// NewMissingCredentialsClient creates a new HTTP client failing every request with the given error. It is used for APIs
+// whose credentials are not defined, so that calls to them fail with a precise error instead of being rejected as unauthorized.
+func NewMissingCredentialsClient(err error) *http.Client {
+	return &http.Client{Transport: &MissingCredentialsTransport{err: err}}
 }
 
 func isNewDynatraceTokenFormat(token string) bool {
@@ -0,0 +1,136 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenRefreshMargin is the time before their expiry at which tokens are refreshed, so that requests sent with them
+// don't fail because they expire in flight
+const tokenRefreshMargin = time.Minute
+
+// tokenCaches holds the tokenCache of each OAuth client, so that all HTTP clients using the same credentials share
+// their tokens during a run
+var tokenCaches = struct {
+	sync.Mutex
+	caches map[tokenCacheKey]*tokenCache
+}{caches: map[tokenCacheKey]*tokenCache{}}
+
+type tokenCacheKey struct {
+	clientID, clientSecret, tokenURL, scopes string
+}
+
+// tokenCache caches a token until shortly before it expires. Only a single token is requested at a time - concurrent
+// callers wait for it instead of each requesting a token of their own.
+type tokenCache struct {
+	mutex sync.Mutex
+	token *oauth2.Token
+	now   func() time.Time
+}
+
+// cachedTokenSource is an oauth2.TokenSource requesting tokens using its config and HTTP client, which caches them in
+// the tokenCache shared by all sources of the same credentials
+type cachedTokenSource struct {
+	cache *tokenCache
+	fetch func() (*oauth2.Token, error)
+}
+
+// newCachedTokenSource returns a cachedTokenSource for the given credentials. Tokens are requested using the HTTP
+// client set as oauth2.HTTPClient in ctx.
+func newCachedTokenSource(ctx context.Context, oauthConfig OauthCredentials) *cachedTokenSource {
+	config := clientcredentials.Config{
+		ClientID:     oauthConfig.ClientID,
+		ClientSecret: oauthConfig.ClientSecret,
+		TokenURL:     oauthConfig.TokenURL,
+		Scopes:       oauthConfig.Scopes,
+	}
+	return &cachedTokenSource{
+		cache: sharedTokenCache(oauthConfig),
+		fetch: func() (*oauth2.Token, error) { return config.Token(ctx) },
+	}
+}
+
+// sharedTokenCache returns the tokenCache of the given credentials
+func sharedTokenCache(oauthConfig OauthCredentials) *tokenCache {
+	key := tokenCacheKey{
+		clientID:     oauthConfig.ClientID,
+		clientSecret: oauthConfig.ClientSecret,
+		tokenURL:     oauthConfig.TokenURL,
+		scopes:       strings.Join(oauthConfig.Scopes, " "),
+	}
+
+	tokenCaches.Lock()
+	defer tokenCaches.Unlock()
+
+	if c, ok := tokenCaches.caches[key]; ok {
+		return c
+	}
+	c := &tokenCache{now: time.Now}
+	tokenCaches.caches[key] = c
+	return c
+}
+
+func (s *cachedTokenSource) Token() (*oauth2.Token, error) {
+	return s.cache.get(s.fetch)
+}
+
+// invalidate drops the given token from the cache, so that the next call to Token requests a new one
+func (s *cachedTokenSource) invalidate(token *oauth2.Token) {
+	s.cache.invalidate(token)
+}
+
+// get returns the cached token, or fetches a new one if it is missing or about to expire
+func (c *tokenCache) get(fetch func() (*oauth2.Token, error)) (*oauth2.Token, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.valid() {
+		return c.token, nil
+	}
+
+	token, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.token = token
+	return token, nil
+}
+
+func (c *tokenCache) valid() bool {
+	if c.token == nil || c.token.AccessToken == "" {
+		return false
+	}
+	return c.token.Expiry.IsZero() || c.now().Add(tokenRefreshMargin).Before(c.token.Expiry)
+}
+
+// invalidate drops the given token. If the cache already holds another token - e.g. as another goroutine already
+// refreshed it - it is kept.
+func (c *tokenCache) invalidate(token *oauth2.Token) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != nil && token != nil && c.token.AccessToken == token.AccessToken {
+		c.token = nil
+	}
+}
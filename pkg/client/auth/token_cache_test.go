@@ -0,0 +1,144 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTokenServer returns a server issuing the tokens "token-1", "token-2", ... and the number of issued tokens
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		time.Sleep(10 * time.Millisecond) // give concurrent requests the chance to pile up
+		n := issued.Add(1)
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(rw, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func TestNewOAuthClient_SharesTokenBetweenConcurrentRequests(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+	}))
+	defer apiServer.Close()
+
+	credentials := OauthCredentials{ClientID: "id", ClientSecret: "secret", TokenURL: tokenServer.URL}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// every goroutine uses a client of its own, which still share their token
+			resp, err := NewOAuthClient(context.Background(), credentials).Get(apiServer.URL)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), issued.Load())
+}
+
+func TestNewOAuthClient_RetriesUnauthorizedRequestsWithNewToken(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") == "Bearer token-1" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "payload", string(body))
+	}))
+	defer apiServer.Close()
+
+	c := NewOAuthClient(context.Background(), OauthCredentials{ClientID: "id", ClientSecret: "secret", TokenURL: tokenServer.URL})
+	resp, err := c.Post(apiServer.URL, "text/plain", bytes.NewBufferString("payload"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestNewOAuthClient_TokenRequestFails(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer tokenServer.Close()
+
+	c := NewOAuthClient(context.Background(), OauthCredentials{ClientID: "id", ClientSecret: "secret", TokenURL: tokenServer.URL})
+	_, err := c.Get("http://localhost")
+	assert.ErrorContains(t, err, "oauth2")
+}
+
+func TestTokenCache_RefreshesTokensBeforeTheyExpire(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := &tokenCache{now: func() time.Time { return now }}
+
+	fetched := 0
+	fetch := func() (*oauth2.Token, error) {
+		fetched++
+		return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", fetched), Expiry: now.Add(5 * time.Minute)}, nil
+	}
+
+	token, err := c.get(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+
+	now = now.Add(3 * time.Minute)
+	token, err = c.get(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+
+	now = now.Add(90 * time.Second) // within tokenRefreshMargin of the expiry
+	token, err = c.get(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.AccessToken)
+}
+
+func TestTokenCache_InvalidateKeepsNewerTokens(t *testing.T) {
+	c := &tokenCache{now: time.Now}
+	old := &oauth2.Token{AccessToken: "old"}
+	c.token = &oauth2.Token{AccessToken: "new"}
+
+	c.invalidate(old)
+	assert.Equal(t, "new", c.token.AccessToken)
+
+	c.invalidate(c.token)
+	assert.Nil(t, c.token)
+}
@@ -17,6 +17,8 @@
 package auth
 
 import (
+	"golang.org/x/oauth2"
+	"io"
 	"net/http"
 )
 
@@ -52,3 +54,60 @@ func (t *TokenAuthTransport) RoundTrip(req *http.Request) (*http.Response, error
 func (t *TokenAuthTransport) setHeader(key, value string) {
 	t.header.Set(key, value)
 }
+
+// OAuthTransport authenticates requests using OAuth tokens, which are cached and shared by all transports using the same
+// credentials. Requests rejected as unauthorized are retried once using a new token, in case the token was revoked or
+// expired while the request was sent.
+type OAuthTransport struct {
+	base   http.RoundTripper
+	source *cachedTokenSource
+}
+
+func (t *OAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, token, err := t.roundTrip(req, req.Body)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// requests with a body can only be retried if the body can be read again
+	body := req.Body
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		if body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	t.source.invalidate(token)
+	resp, _, err = t.roundTrip(req, body)
+	return resp, err
+}
+
+func (t *OAuthTransport) roundTrip(req *http.Request, body io.ReadCloser) (*http.Response, *oauth2.Token, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// a RoundTripper must not modify the request, so authenticate a copy of it
+	r := req.Clone(req.Context())
+	r.Body = body
+	token.SetAuthHeader(r)
+
+	resp, err := t.base.RoundTrip(r)
+	return resp, token, err
+}
+
+// MissingCredentialsTransport fails every request with its error, without sending it
+type MissingCredentialsTransport struct {
+	err error
+}
+
+func (t *MissingCredentialsTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
@@ -18,9 +18,10 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	automationApi "github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/automation"
 	lib "github.com/dynatrace/dynatrace-configuration-as-code-core/api/rest"
-	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
@@ -29,16 +30,26 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/trafficlogs"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	clientAuth "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/auth"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/metadata"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/openpipeline"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/segment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/useragent"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
-	"golang.org/x/oauth2/clientcredentials"
+	"net/http"
+	neturl "net/url"
 	"runtime"
+	"strings"
 	"time"
 )
 
+// ErrMissingAccessToken is returned by calls to the classic Config APIs of platform environments that only define OAuth
+// credentials, but no access token.
+var ErrMissingAccessToken = errors.New("no access token is defined for the environment ('auth.token' in the manifest), but it is required for calls to the classic Config APIs")
+
 var (
 	_ SettingsClient  = (*dtclient.DynatraceClient)(nil)
 	_ ConfigClient    = (*dtclient.DynatraceClient)(nil)
@@ -120,6 +131,19 @@ type SettingsClient interface {
 	DeleteSettings(string) error
 }
 
+// ValidationClient sends payloads to the validation endpoints of a Dynatrace environment. Validation does not create or
+// modify any object on the environment.
+type ValidationClient interface {
+	// ValidateConfig validates the payload of a classic config using the validator endpoint of the API. E.g. for alerting profiles this would be:
+	//    POST <environment-url>/api/config/v1/alertingProfiles/validator
+	// APIs without a validator endpoint are not validated and return no error.
+	ValidateConfig(ctx context.Context, a api.API, payload []byte) error
+
+	// ValidateSettings validates the given settings object by posting it in 'validateOnly' mode:
+	//    POST <environment-url>/api/v2/settings/objects?validateOnly=true
+	ValidateSettings(ctx context.Context, obj dtclient.SettingsObject) error
+}
+
 //go:generate mockgen -source=clientset.go -destination=client_mock.go -package=client DynatraceClient
 
 // DynatraceClient provides the functionality for performing basic CRUD operations on any Dynatrace API
@@ -133,6 +157,7 @@ type SettingsClient interface {
 type DynatraceClient interface {
 	ConfigClient
 	SettingsClient
+	ValidationClient
 }
 
 type AutomationClient interface {
@@ -161,6 +186,31 @@ type DocumentClient interface {
 	Delete(ctx context.Context, id string) (documents.Response, error)
 }
 
+type SLOClient interface {
+	Get(ctx context.Context, id string) (slo.Response, error)
+	List(ctx context.Context) ([]slo.Response, error)
+	Create(ctx context.Context, data []byte) (slo.Response, error)
+	Update(ctx context.Context, id string, data []byte) (slo.Response, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type ClusterClient interface {
+	List(ctx context.Context, a api.ClusterAPI) ([]cluster.Response, error)
+	Create(ctx context.Context, a api.ClusterAPI, data []byte) (cluster.Response, error)
+	Update(ctx context.Context, a api.ClusterAPI, id string, data []byte) (cluster.Response, error)
+	Delete(ctx context.Context, a api.ClusterAPI, id string) error
+}
+
+type SegmentClient interface {
+	Get(ctx context.Context, uid string) (segment.Response, error)
+	List(ctx context.Context) ([]segment.Response, error)
+}
+
+type OpenPipelineClient interface {
+	Get(ctx context.Context, id string) (openpipeline.Response, error)
+	List(ctx context.Context) ([]openpipeline.Response, error)
+}
+
 var DefaultMonacoUserAgent = "Dynatrace Monitoring as Code/" + version.MonitoringAsCode + " " + (runtime.GOOS + " " + runtime.GOARCH)
 
 // ClientSet composes a "full" set of sub-clients to access Dynatrace APIs
@@ -175,6 +225,15 @@ type ClientSet struct {
 	BucketClient BucketClient
 	// DocumentClient is a client capable of manipulating documents
 	DocumentClient DocumentClient
+	// SLOClient is a client capable of manipulating service-level objectives of the Platform SLO API
+	SLOClient SLOClient
+	// SegmentClient is a client capable of reading Grail filter segments
+	SegmentClient SegmentClient
+	// OpenPipelineClient is a client capable of reading OpenPipeline configurations
+	OpenPipelineClient OpenPipelineClient
+	// ClusterClient is a client capable of manipulating cluster-wide configurations of a Managed cluster. It is only
+	// set for cluster environments, which have no other clients.
+	ClusterClient ClusterClient
 }
 
 func (s ClientSet) Classic() ConfigClient {
@@ -197,10 +256,35 @@ func (s ClientSet) Document() DocumentClient {
 	return s.DocumentClient
 }
 
+func (s ClientSet) SLO() SLOClient {
+	return s.SLOClient
+}
+
+func (s ClientSet) Segment() SegmentClient {
+	return s.SegmentClient
+}
+
+func (s ClientSet) OpenPipeline() OpenPipelineClient {
+	return s.OpenPipelineClient
+}
+
 type ClientOptions struct {
 	CustomUserAgent string
 	SupportArchive  bool
 	CachingDisabled bool
+	// RetryPolicy overrides the default retry behavior of the clients, if set
+	RetryPolicy *rest.RetryPolicy
+	// HTTPSettings customize the HTTP connections of the clients, if set
+	HTTPSettings *HTTPSettings
+	// Middlewares intercept all requests sent by the DTClient, e.g. to add custom headers or for audit logging
+	Middlewares []rest.Middleware
+}
+
+func (o ClientOptions) getRetrySettings() rest.RetrySettings {
+	if o.RetryPolicy == nil {
+		return rest.DefaultRetrySettings
+	}
+	return rest.DefaultRetrySettings.WithPolicy(*o.RetryPolicy)
 }
 
 func (o ClientOptions) getUserAgentString() string {
@@ -213,20 +297,27 @@ func (o ClientOptions) getUserAgentString() string {
 func CreateClassicClientSet(url string, token string, opts ClientOptions) (*ClientSet, error) {
 	concurrentRequestLimit := environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey)
 
-	tokenClient := clientAuth.NewTokenAuthClient(token)
+	baseClient, err := opts.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenClient := clientAuth.NewTokenAuthClientWithBase(baseClient, token)
 	var trafficLogger *trafficlogs.FileBasedLogger
 	if opts.SupportArchive {
 		trafficLogger = trafficlogs.NewFileBased()
 	}
 
-	restClient := rest.NewRestClient(tokenClient, trafficLogger, rest.CreateRateLimitStrategy())
+	restClient := rest.NewRestClient(tokenClient, trafficLogger, rest.CreateRateLimitStrategy(), opts.Middlewares...)
 	dtClient, err := dtclient.NewClassicClient(
 		url,
 		restClient,
 		dtclient.WithCachingDisabled(opts.CachingDisabled),
 		dtclient.WithAutoServerVersion(),
 		dtclient.WithClientRequestLimiter(concurrency.NewLimiter(concurrentRequestLimit)),
+		dtclient.WithSettingsBatchSize(environment.GetEnvValueIntLog(environment.SettingsBatchSizeEnvKey)),
 		dtclient.WithCustomUserAgentString(opts.getUserAgentString()),
+		dtclient.WithRetrySettings(opts.getRetrySettings()),
 	)
 	if err != nil {
 		return nil, err
@@ -237,11 +328,52 @@ func CreateClassicClientSet(url string, token string, opts ClientOptions) (*Clie
 	}, nil
 }
 
+// CreateClusterClientSet creates a client set for the Cluster API of the Managed cluster at url, authenticating using
+// the given Cluster API token
+func CreateClusterClientSet(url string, token string, opts ClientOptions) (*ClientSet, error) {
+	baseClient, err := opts.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var trafficLogger *trafficlogs.FileBasedLogger
+	if opts.SupportArchive {
+		trafficLogger = trafficlogs.NewFileBased()
+	}
+
+	restClient := rest.NewRestClient(clientAuth.NewTokenAuthClientWithBase(baseClient, token), trafficLogger, rest.CreateRateLimitStrategy(), opts.Middlewares...)
+	restClient.Client().Transport = useragent.NewCustomUserAgentTransport(restClient.Client().Transport, opts.getUserAgentString())
+
+	return &ClientSet{
+		ClusterClient: cluster.NewClient(strings.TrimSuffix(url, "/"), restClient),
+	}, nil
+}
+
+// PlatformAuth holds the credentials of a platform environment. The OAuth credentials are used for the Dynatrace Platform
+// APIs, the Token for the classic Config APIs. The Token is optional - if it is empty, calls to the classic Config APIs
+// fail with ErrMissingAccessToken.
 type PlatformAuth struct {
 	OauthClientID, OauthClientSecret, OauthTokenURL string
 	Token                                           string
 }
 
+// newCoreRestClient creates a rest client of the core library, sending requests to the given environment URL using httpClient
+func newCoreRestClient(environmentURL string, httpClient *http.Client, trafficLogger *trafficlogs.FileBasedLogger, userAgent string) (*lib.Client, error) {
+	parsedURL, err := neturl.Parse(environmentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL %q: %w", environmentURL, err)
+	}
+
+	var opts []lib.Option
+	if trafficLogger != nil {
+		opts = append(opts, lib.WithHTTPListener(&lib.HTTPListener{Callback: trafficLogger.LogToFiles}))
+	}
+
+	c := lib.NewClient(parsedURL, httpClient, opts...)
+	c.SetHeader("User-Agent", userAgent)
+	return c, nil
+}
+
 func CreatePlatformClientSet(url string, auth PlatformAuth, opts ClientOptions) (*ClientSet, error) {
 	concurrentRequestLimit := environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey)
 
@@ -251,23 +383,36 @@ func CreatePlatformClientSet(url string, auth PlatformAuth, opts ClientOptions)
 		TokenURL:     auth.OauthTokenURL,
 	}
 
-	tokenClient := clientAuth.NewTokenAuthClient(auth.Token)
-	oauthClient := clientAuth.NewOAuthClient(context.TODO(), oauthCredentials)
+	baseClient, err := opts.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var tokenClient *http.Client
+	if auth.Token != "" {
+		tokenClient = clientAuth.NewTokenAuthClientWithBase(baseClient, auth.Token)
+	} else {
+		tokenClient = clientAuth.NewMissingCredentialsClient(ErrMissingAccessToken)
+	}
+	oauthClient := clientAuth.NewOAuthClientWithBase(context.TODO(), baseClient, oauthCredentials)
 
 	var trafficLogger *trafficlogs.FileBasedLogger
 	if opts.SupportArchive {
 		trafficLogger = trafficlogs.NewFileBased()
 	}
 
-	classicUrlClient := rest.NewRestClient(oauthClient, trafficLogger, rest.CreateRateLimitStrategy())
+	// all clients of the environment share the rate limit
+	rateLimitStrategy := rest.CreateRateLimitStrategy()
+
+	classicUrlClient := rest.NewRestClient(oauthClient, trafficLogger, rateLimitStrategy, opts.Middlewares...)
 	classicUrlClient.Client().Transport = useragent.NewCustomUserAgentTransport(classicUrlClient.Client().Transport, opts.getUserAgentString())
 	classicURL, err := metadata.GetDynatraceClassicURL(context.TODO(), classicUrlClient, url)
 	if err != nil {
 		return nil, err
 	}
 
-	client := rest.NewRestClient(oauthClient, trafficLogger, rest.CreateRateLimitStrategy())
-	clientClassic := rest.NewRestClient(tokenClient, trafficLogger, rest.CreateRateLimitStrategy())
+	client := rest.NewRestClient(oauthClient, trafficLogger, rateLimitStrategy, opts.Middlewares...)
+	clientClassic := rest.NewRestClient(tokenClient, trafficLogger, rateLimitStrategy, opts.Middlewares...)
 
 	dtClient, err := dtclient.NewPlatformClient(
 		url,
@@ -277,44 +422,32 @@ func CreatePlatformClientSet(url string, auth PlatformAuth, opts ClientOptions)
 		dtclient.WithCachingDisabled(opts.CachingDisabled),
 		dtclient.WithAutoServerVersion(),
 		dtclient.WithClientRequestLimiter(concurrency.NewLimiter(concurrentRequestLimit)),
+		dtclient.WithSettingsBatchSize(environment.GetEnvValueIntLog(environment.SettingsBatchSizeEnvKey)),
 		dtclient.WithCustomUserAgentString(opts.getUserAgentString()),
+		dtclient.WithRetrySettings(opts.getRetrySettings()),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	clientFactory := clients.Factory().
-		WithOAuthCredentials(clientcredentials.Config{
-			ClientID:     auth.OauthClientID,
-			ClientSecret: auth.OauthClientSecret,
-			TokenURL:     auth.OauthTokenURL,
-		}).
-		WithEnvironmentURL(url).
-		WithUserAgent(opts.getUserAgentString())
-
-	if opts.SupportArchive {
-		clientFactory = clientFactory.WithHTTPListener(&lib.HTTPListener{Callback: trafficLogger.LogToFiles})
-	}
-
-	bucketClient, err := clientFactory.BucketClientWithRetrySettings(15, time.Second, 5*time.Minute)
+	// the clients of the core library share the OAuth client, so that they honor the HTTP settings as well
+	coreClient, err := newCoreRestClient(url, oauthClient, trafficLogger, opts.getUserAgentString())
 	if err != nil {
 		return nil, err
 	}
 
-	autClient, err := clientFactory.AutomationClient()
-	if err != nil {
-		return nil, err
-	}
-
-	documentClient, err := clientFactory.DocumentClient()
-	if err != nil {
-		return nil, err
-	}
+	bucketRetries := opts.getRetrySettings().Normal
+	bucketClient := buckets.NewClient(coreClient, buckets.WithRetrySettings(bucketRetries.MaxRetries, bucketRetries.WaitTime, 5*time.Minute))
+	autClient := automation.NewClient(coreClient)
+	documentClient := documents.NewClient(coreClient)
 
 	return &ClientSet{
-		DTClient:       dtClient,
-		AutClient:      autClient,
-		BucketClient:   bucketClient,
-		DocumentClient: documentClient,
+		DTClient:           dtClient,
+		AutClient:          autClient,
+		BucketClient:       bucketClient,
+		DocumentClient:     documentClient,
+		SLOClient:          slo.NewClient(url, client),
+		SegmentClient:      segment.NewClient(url, client),
+		OpenPipelineClient: openpipeline.NewClient(url, client),
 	}, nil
 }
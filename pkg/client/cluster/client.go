@@ -0,0 +1,202 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cluster implements a client for the Cluster API of Dynatrace Managed clusters.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// Response represents a single object of an endpoint of the Cluster API
+type Response struct {
+	// ID of the object
+	ID string
+	// Name of the object
+	Name string
+	// Data is the full JSON payload of the object
+	Data []byte
+}
+
+type objectMetadata struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Client accesses the Cluster API of a Managed cluster.
+type Client struct {
+	client     *rest.Client
+	clusterURL string
+}
+
+// NewClient creates a Client for the cluster at clusterURL, using the given rest.Client to send requests.
+func NewClient(clusterURL string, client *rest.Client) *Client {
+	return &Client{client: client, clusterURL: clusterURL}
+}
+
+// List returns all objects of the given endpoint.
+func (c *Client) List(ctx context.Context, a api.ClusterAPI) ([]Response, error) {
+	u, err := url.Parse(c.clusterURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cluster URL %q: %w", c.clusterURL, err)
+	}
+	u = u.JoinPath(a.URLPath)
+
+	var result []Response
+	for {
+		resp, err := c.client.Get(ctx, u.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", a.ID, err)
+		}
+		if !resp.IsSuccess() {
+			return nil, rest.NewRespErr(fmt.Sprintf("failed to list %s", a.ID), resp).WithRequestInfo(http.MethodGet, u.String())
+		}
+
+		objects, err := unmarshalList(a, resp.Body)
+		if err != nil {
+			return nil, rest.NewRespErr(fmt.Sprintf("failed to unmarshal %s list", a.ID), resp).WithRequestInfo(http.MethodGet, u.String()).WithErr(err)
+		}
+		for _, o := range objects {
+			r, err := newResponse(o)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, r)
+		}
+
+		if resp.NextPageKey == "" {
+			return result, nil
+		}
+		// follow-up pages must only be requested by their page key
+		u.RawQuery = url.Values{"nextPageKey": []string{resp.NextPageKey}}.Encode()
+	}
+}
+
+func unmarshalList(a api.ClusterAPI, body []byte) ([]json.RawMessage, error) {
+	var objects []json.RawMessage
+	if a.PropertyNameOfGetAllResponse == "" {
+		err := json.Unmarshal(body, &objects)
+		return objects, err
+	}
+
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, err
+	}
+	if list, ok := page[a.PropertyNameOfGetAllResponse]; ok {
+		if err := json.Unmarshal(list, &objects); err != nil {
+			return nil, err
+		}
+	}
+	return objects, nil
+}
+
+// Create creates a new object of the given endpoint from the given payload.
+func (c *Client) Create(ctx context.Context, a api.ClusterAPI, data []byte) (Response, error) {
+	if a.SingleConfiguration {
+		return Response{}, fmt.Errorf("%s holds a single configuration, which can't be created", a.ID)
+	}
+
+	u, err := url.JoinPath(c.clusterURL, a.URLPath)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to build URL for %s: %w", a.ID, err)
+	}
+
+	resp, err := c.client.Post(ctx, u, data)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to POST %s: %w", a.ID, err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to create %s", a.ID), resp).WithRequestInfo(http.MethodPost, u)
+	}
+	return newResponse(resp.Body)
+}
+
+// Update replaces the object with the given ID with the given payload. The ID is ignored for endpoints holding a
+// single configuration.
+func (c *Client) Update(ctx context.Context, a api.ClusterAPI, id string, data []byte) (Response, error) {
+	var u string
+	var err error
+	switch {
+	case a.SingleConfiguration:
+		u, err = url.JoinPath(c.clusterURL, a.URLPath)
+	case a.UpdateWithIDInPayload:
+		u, err = url.JoinPath(c.clusterURL, a.URLPath)
+		if err == nil {
+			data, err = withID(data, id)
+		}
+	default:
+		u, err = url.JoinPath(c.clusterURL, a.URLPath, id)
+	}
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to prepare update of %s %q: %w", a.ID, id, err)
+	}
+
+	resp, err := c.client.Put(ctx, u, data)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to PUT %s %q: %w", a.ID, id, err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to update %s %q", a.ID, id), resp).WithRequestInfo(http.MethodPut, u)
+	}
+	// not all endpoints return the updated object
+	return Response{ID: id, Data: data}, nil
+}
+
+// Delete removes the object with the given ID. Objects that do not exist are ignored.
+func (c *Client) Delete(ctx context.Context, a api.ClusterAPI, id string) error {
+	if a.SingleConfiguration {
+		return fmt.Errorf("%s holds a single configuration, which can't be deleted", a.ID)
+	}
+
+	u, err := url.JoinPath(c.clusterURL, a.URLPath, id)
+	if err != nil {
+		return fmt.Errorf("failed to build URL for %s %q: %w", a.ID, id, err)
+	}
+
+	resp, err := c.client.Delete(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to DELETE %s %q: %w", a.ID, id, err)
+	}
+	if !resp.IsSuccess() && resp.StatusCode != http.StatusNotFound {
+		return rest.NewRespErr(fmt.Sprintf("failed to delete %s %q", a.ID, id), resp).WithRequestInfo(http.MethodDelete, u)
+	}
+	return nil
+}
+
+func withID(data []byte, id string) ([]byte, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	payload["id"] = id
+	return json.Marshal(payload)
+}
+
+func newResponse(data []byte) (Response, error) {
+	var m objectMetadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Response{}, fmt.Errorf("failed to unmarshal cluster API object: %w", err)
+	}
+	return Response{ID: m.ID, Name: m.Name, Data: data}, nil
+}
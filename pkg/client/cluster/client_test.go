@@ -0,0 +1,200 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *cluster.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return cluster.NewClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+}
+
+func TestList(t *testing.T) {
+	apis := api.NewClusterAPIs()
+
+	t.Run("lists objects of array responses", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodGet, req.Method)
+			assert.Equal(t, "/api/v1.0/onpremise/groups", req.URL.Path)
+			_, _ = rw.Write([]byte(`[{"id": "1", "name": "group-a"}, {"id": "2", "name": "group-b"}]`))
+		})
+
+		got, err := c.List(context.TODO(), apis[api.ClusterUserGroups])
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "1", got[0].ID)
+		assert.Equal(t, "group-a", got[0].Name)
+		assert.JSONEq(t, `{"id": "1", "name": "group-a"}`, string(got[0].Data))
+		assert.Equal(t, "2", got[1].ID)
+	})
+
+	t.Run("follows pages of paginated responses", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "/api/cluster/v2/environments", req.URL.Path)
+			if req.URL.Query().Get("nextPageKey") == "" {
+				_, _ = rw.Write([]byte(`{"environments": [{"id": "env-1", "name": "one"}], "nextPageKey": "page-2"}`))
+				return
+			}
+			assert.Equal(t, "page-2", req.URL.Query().Get("nextPageKey"))
+			_, _ = rw.Write([]byte(`{"environments": [{"id": "env-2", "name": "two"}]}`))
+		})
+
+		got, err := c.List(context.TODO(), apis[api.ClusterEnvironments])
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "env-1", got[0].ID)
+		assert.Equal(t, "env-2", got[1].ID)
+	})
+
+	t.Run("returns an error on unsuccessful responses", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusForbidden)
+		})
+
+		_, err := c.List(context.TODO(), apis[api.ClusterUserGroups])
+		var respErr rest.RespError
+		require.ErrorAs(t, err, &respErr)
+		assert.Equal(t, http.StatusForbidden, respErr.StatusCode)
+	})
+}
+
+func TestCreate(t *testing.T) {
+	apis := api.NewClusterAPIs()
+
+	t.Run("posts the payload and returns the created object", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/api/v1.0/onpremise/groups", req.URL.Path)
+			body, _ := io.ReadAll(req.Body)
+			assert.JSONEq(t, `{"name": "group-a"}`, string(body))
+			_, _ = rw.Write([]byte(`{"id": "1", "name": "group-a"}`))
+		})
+
+		got, err := c.Create(context.TODO(), apis[api.ClusterUserGroups], []byte(`{"name": "group-a"}`))
+		require.NoError(t, err)
+		assert.Equal(t, "1", got.ID)
+		assert.Equal(t, "group-a", got.Name)
+	})
+
+	t.Run("fails for endpoints holding a single configuration", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			t.Fatal("no request expected")
+		})
+
+		_, err := c.Create(context.TODO(), apis[api.ClusterProxy], []byte(`{}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	apis := api.NewClusterAPIs()
+
+	tests := []struct {
+		name         string
+		api          string
+		id           string
+		wantPath     string
+		wantBody     string
+		givenPayload string
+	}{
+		{
+			name:         "puts to the object path",
+			api:          api.ClusterEnvironments,
+			id:           "env-1",
+			wantPath:     "/api/cluster/v2/environments/env-1",
+			givenPayload: `{"name": "one"}`,
+			wantBody:     `{"name": "one"}`,
+		},
+		{
+			name:         "puts the ID in the payload",
+			api:          api.ClusterUserGroups,
+			id:           "1",
+			wantPath:     "/api/v1.0/onpremise/groups",
+			givenPayload: `{"name": "group-a"}`,
+			wantBody:     `{"id": "1", "name": "group-a"}`,
+		},
+		{
+			name:         "puts single configurations to the endpoint path",
+			api:          api.ClusterProxy,
+			wantPath:     "/api/v1.0/onpremise/proxy/configuration",
+			givenPayload: `{"scheme": "https"}`,
+			wantBody:     `{"scheme": "https"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodPut, req.Method)
+				assert.Equal(t, tt.wantPath, req.URL.Path)
+				body, _ := io.ReadAll(req.Body)
+				assert.JSONEq(t, tt.wantBody, string(body))
+				rw.WriteHeader(http.StatusNoContent)
+			})
+
+			got, err := c.Update(context.TODO(), apis[tt.api], tt.id, []byte(tt.givenPayload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, got.ID)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	apis := api.NewClusterAPIs()
+
+	t.Run("deletes the object", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodDelete, req.Method)
+			assert.Equal(t, "/api/v1.0/onpremise/groups/1", req.URL.Path)
+			rw.WriteHeader(http.StatusNoContent)
+		})
+
+		assert.NoError(t, c.Delete(context.TODO(), apis[api.ClusterUserGroups], "1"))
+	})
+
+	t.Run("ignores objects that do not exist", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+		})
+
+		assert.NoError(t, c.Delete(context.TODO(), apis[api.ClusterUserGroups], "1"))
+	})
+
+	t.Run("fails for endpoints holding a single configuration", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+			t.Fatal("no request expected")
+		})
+
+		assert.Error(t, c.Delete(context.TODO(), apis[api.ClusterProxy], ""))
+	})
+}
@@ -28,10 +28,10 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/auth"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/useragent"
 	dtVersion "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
-	"golang.org/x/oauth2"
 	"net/http"
 	"net/url"
 	"strings"
@@ -46,6 +46,12 @@ type DownloadSettingsObject struct {
 	Scope            string                    `json:"scope"`
 	Value            json.RawMessage           `json:"value"`
 	ModificationInfo *SettingsModificationInfo `json:"modificationInfo"`
+	// Modified is the time of the last modification of the object, in milliseconds since the epoch
+	Modified int64 `json:"modified,omitempty"`
+	// Created is the time the object was created, in milliseconds since the epoch
+	Created int64 `json:"created,omitempty"`
+	// ModifiedBy is the user who last modified the object
+	ModifiedBy string `json:"modifiedBy,omitempty"`
 }
 
 type SettingsModificationInfo struct {
@@ -59,17 +65,22 @@ type SettingsModificationInfo struct {
 // ErrSettingNotFound is returned when no settings 2.0 object could be found
 var ErrSettingNotFound = errors.New("settings object not found")
 
+// InsertAfterFront can be used as UpsertSettingsOptions.InsertAfter to place an object at the front of an ordered schema
+const InsertAfterFront = "front"
+
 type UpsertSettingsOptions struct {
 	OverrideRetry *rest.RetrySetting
-	InsertAfter   string
+	// InsertAfter is the object ID of the settings object the upserted object is placed after.
+	// Use InsertAfterFront to place it first. If empty, the API's default ordering applies.
+	InsertAfter string
 }
 
 // defaultListSettingsFields  are the fields we are interested in when getting setting objects
-const defaultListSettingsFields = "objectId,value,externalId,schemaVersion,schemaId,scope,modificationInfo"
+const defaultListSettingsFields = "objectId,value,externalId,schemaVersion,schemaId,scope,modificationInfo,modified,created,modifiedBy"
 
 // reducedListSettingsFields are the fields we are interested in when getting settings objects but don't care about the
 // actual value payload
-const reducedListSettingsFields = "objectId,externalId,schemaVersion,schemaId,scope,modificationInfo"
+const reducedListSettingsFields = "objectId,externalId,schemaVersion,schemaId,scope,modificationInfo,modified,created,modifiedBy"
 const defaultPageSize = "500"
 
 // ListSettingsOptions are additional options for the ListSettings method
@@ -127,6 +138,9 @@ type DynatraceClient struct {
 
 	// classicConfigsCache caches classic settings values
 	classicConfigsCache cache.Cache[[]Value]
+
+	// settingsBatcher accumulates settings objects to create or update them in batches, if set
+	settingsBatcher *settingsBatcher
 }
 
 func WithExternalIDGenerator(g idutils.ExternalIDGenerator) func(client *DynatraceClient) {
@@ -164,7 +178,7 @@ func WithAutoServerVersion() func(client *DynatraceClient) {
 		var serverVersion version.Version
 		var err error
 
-		if _, ok := d.platformClient.Client().Transport.(*oauth2.Transport); ok {
+		if _, ok := d.platformClient.Client().Transport.(*auth.OAuthTransport); ok {
 			// for platform enabled tenants there is no dedicated version endpoint
 			// so this call would need to be "redirected" to the second gen URL, which do not currently resolve
 			d.serverVersion = version.UnknownVersion
@@ -180,6 +194,18 @@ func WithAutoServerVersion() func(client *DynatraceClient) {
 	}
 }
 
+// WithSettingsBatchSize specifies that settings objects are not created or updated one by one, but accumulated per
+// schema and sent in batches of up to the given size. Sizes <= 1 disable batching.
+func WithSettingsBatchSize(size int) func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		if size <= 1 {
+			d.settingsBatcher = nil
+			return
+		}
+		d.settingsBatcher = newSettingsBatcher(size, defaultSettingsBatchDelay, d.sendSettingsBatch)
+	}
+}
+
 // WithCachingDisabled allows disabling the client's builtin caching mechanism for
 // classic configs, schema constraints and settings objects. Disabling the caching
 // is recommended in situations where configs are fetched immediately after their creation (e.g. in test scenarios)
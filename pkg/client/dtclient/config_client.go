@@ -483,11 +483,17 @@ func (d *DynatraceClient) getExistingObjectId(ctx context.Context, objectName st
 	return objID, nil
 }
 
-func (d *DynatraceClient) fetchExistingValues(ctx context.Context, theApi api.API, urlString string) (values []Value, err error) {
+// CachesConfigsOf returns whether the configs listed of the given API are cached by the DynatraceClient, so that
+// subsequent lookups by name do not call the API again
+func CachesConfigsOf(theApi api.API) bool {
 	// caching cannot be used for subPathAPI as well because there is potentially more than one config per api type/id to consider.
 	// the cache cannot deal with that
-	if (!theApi.NonUniqueName && !theApi.HasParent()) && //there is potentially more than one config per api type/id to consider
-		(theApi.ID != api.ApplicationWeb && theApi.ID != api.ApplicationMobile) { //there is no refresh mechanism for delete; outdated values can cause decreasing performance during delete (unnecessary retrying)
+	return (!theApi.NonUniqueName && !theApi.HasParent()) && //there is potentially more than one config per api type/id to consider
+		(theApi.ID != api.ApplicationWeb && theApi.ID != api.ApplicationMobile) //there is no refresh mechanism for delete; outdated values can cause decreasing performance during delete (unnecessary retrying)
+}
+
+func (d *DynatraceClient) fetchExistingValues(ctx context.Context, theApi api.API, urlString string) (values []Value, err error) {
+	if CachesConfigsOf(theApi) {
 		if values, cached := d.classicConfigsCache.Get(theApi.ID); cached {
 			return values, nil
 		}
@@ -505,7 +511,7 @@ func (d *DynatraceClient) fetchExistingValues(ctx context.Context, theApi api.AP
 	if theApi.HasParent() {
 		resp, err = rest.SendWithRetryWithInitialTry(ctx, func(ctx context.Context, url string, _ []byte) (rest.Response, error) {
 			return d.classicClient.Get(ctx, url)
-		}, parsedUrl.String(), nil, rest.DefaultRetrySettings.Long)
+		}, parsedUrl.String(), nil, d.retrySettings.Long)
 	} else {
 		resp, err = d.classicClient.Get(ctx, parsedUrl.String())
 	}
@@ -285,3 +285,11 @@ func (c *DummyClient) ListSettings(_ context.Context, _ string, _ ListSettingsOp
 func (c *DummyClient) DeleteSettings(_ string) error {
 	return nil
 }
+
+func (c *DummyClient) ValidateConfig(_ context.Context, _ api.API, _ []byte) error {
+	return nil
+}
+
+func (c *DummyClient) ValidateSettings(_ context.Context, _ SettingsObject) error {
+	return nil
+}
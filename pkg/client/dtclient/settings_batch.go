@@ -0,0 +1,166 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dtclient
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// defaultSettingsBatchDelay is the time a batch of settings objects waits for further objects before it is sent
+// although it is not full
+const defaultSettingsBatchDelay = 200 * time.Millisecond
+
+type settingsBatchResult struct {
+	entity DynatraceEntity
+	err    error
+}
+
+type settingsBatchEntry struct {
+	req    settingsUpsertRequest
+	result chan settingsBatchResult
+}
+
+type settingsBatch struct {
+	entries []settingsBatchEntry
+	timer   *time.Timer
+}
+
+// settingsBatcher accumulates settings objects per schema. A batch is sent once it reaches the batch size, or once its
+// first object waited for the batch delay.
+type settingsBatcher struct {
+	size  int
+	delay time.Duration
+	send  func(entries []settingsBatchEntry)
+
+	mu      sync.Mutex
+	pending map[string]*settingsBatch
+}
+
+func newSettingsBatcher(size int, delay time.Duration, send func(entries []settingsBatchEntry)) *settingsBatcher {
+	return &settingsBatcher{
+		size:    size,
+		delay:   delay,
+		send:    send,
+		pending: map[string]*settingsBatch{},
+	}
+}
+
+// add adds the object to the batch of its schema and waits until the batch was sent
+func (b *settingsBatcher) add(ctx context.Context, req settingsUpsertRequest) (DynatraceEntity, error) {
+	entry := settingsBatchEntry{req: req, result: make(chan settingsBatchResult, 1)}
+	schema := req.data.SchemaId
+
+	b.mu.Lock()
+	batch, found := b.pending[schema]
+	if !found {
+		batch = &settingsBatch{}
+		batch.timer = time.AfterFunc(b.delay, func() { b.flush(schema, batch) })
+		b.pending[schema] = batch
+	}
+	batch.entries = append(batch.entries, entry)
+	full := len(batch.entries) >= b.size
+	b.mu.Unlock()
+
+	if full {
+		b.flush(schema, batch)
+	}
+
+	select {
+	case r := <-entry.result:
+		return r.entity, r.err
+	case <-ctx.Done():
+		return DynatraceEntity{}, ctx.Err()
+	}
+}
+
+// flush sends the given batch of the schema, unless it was already sent
+func (b *settingsBatcher) flush(schema string, batch *settingsBatch) {
+	b.mu.Lock()
+	if b.pending[schema] != batch {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, schema)
+	batch.timer.Stop()
+	b.mu.Unlock()
+
+	b.send(batch.entries)
+}
+
+// succeeded returns whether the object was created or updated. Responses not stating the code of each object are only
+// returned by successful requests.
+func (r postResponse) succeeded() bool {
+	return r.ObjectId != "" && (r.Code == 0 || (r.Code >= 200 && r.Code <= 299))
+}
+
+// sendSettingsBatch creates or updates all objects of the batch in a single request. Objects that are rejected, or all
+// objects if the request fails as a whole, are sent again one by one, so that they are retried, and their errors are
+// reported, exactly as if they were not batched.
+func (d *DynatraceClient) sendSettingsBatch(entries []settingsBatchEntry) {
+	ctx := context.WithoutCancel(entries[0].req.ctx)
+
+	data := make([]settingsRequest, len(entries))
+	for i, e := range entries {
+		data[i] = e.req.data
+	}
+
+	results := make([]postResponse, len(entries))
+	payload, err := marshalSettingsRequests(ctx, data)
+	if err == nil {
+		var resp rest.Response
+		d.limiter.ExecuteBlocking(func() {
+			resp, err = d.platformClient.Post(ctx, d.environmentURL+d.settingsObjectAPIPath, payload)
+		})
+		if err == nil {
+			// the API responds with the result of each object in the order of the request, even if some were rejected
+			err = json.Unmarshal(resp.Body, &results)
+			if err == nil && len(results) != len(entries) {
+				results = make([]postResponse, len(entries))
+			}
+		}
+	}
+	if err != nil {
+		log.WithCtxFields(ctx).WithFields(field.Error(err)).Debug("Failed to send batch of %d settings objects of schema %q, sending them one by one: %v", len(entries), data[0].SchemaId, err)
+	}
+
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		if r := results[i]; r.succeeded() {
+			log.WithCtxFields(e.req.ctx).Debug("Created/Updated object %s (%s) with externalId %s", e.req.coordinate.ConfigId, e.req.data.SchemaId, e.req.externalID)
+			e.result <- settingsBatchResult{entity: DynatraceEntity{Id: r.ObjectId, Name: r.ObjectId}}
+			continue
+		}
+
+		wg.Add(1)
+		go func(e settingsBatchEntry) {
+			defer wg.Done()
+			var r settingsBatchResult
+			d.limiter.ExecuteBlocking(func() {
+				r.entity, r.err = d.postSettingsObject(e.req.ctx, e.req)
+			})
+			e.result <- r
+		}(e)
+	}
+	wg.Wait()
+}
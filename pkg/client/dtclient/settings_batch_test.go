@@ -0,0 +1,170 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dtclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/concurrency"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchTestServer returns a server responding to each POST using the given function, which is passed the external
+// IDs of all posted objects
+func newBatchTestServer(t *testing.T, respond func(externalIDs []string) (int, string)) (*httptest.Server, *[][]string) {
+	var mu sync.Mutex
+	var posts [][]string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte("{}"))
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var objects []settingsRequest
+		require.NoError(t, json.Unmarshal(body, &objects))
+
+		externalIDs := make([]string, len(objects))
+		for i, o := range objects {
+			externalIDs[i] = o.ExternalId
+		}
+		mu.Lock()
+		posts = append(posts, externalIDs)
+		mu.Unlock()
+
+		code, resp := respond(externalIDs)
+		rw.WriteHeader(code)
+		_, _ = rw.Write([]byte(resp))
+	}))
+	t.Cleanup(server.Close)
+	return server, &posts
+}
+
+func newBatchTestClient(t *testing.T, server *httptest.Server, batchSize int) *DynatraceClient {
+	restClient := rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy())
+	c, err := NewClassicClient(server.URL, restClient,
+		WithRetrySettings(testRetrySettings),
+		WithClientRequestLimiter(concurrency.NewLimiter(5)),
+		WithExternalIDGenerator(func(c coordinate.Coordinate) (string, error) { return c.ConfigId, nil }),
+		WithSettingsBatchSize(batchSize))
+	require.NoError(t, err)
+	return c
+}
+
+// upsertConcurrently upserts objects with the given config IDs in parallel and returns their results by config ID
+func upsertConcurrently(c *DynatraceClient, ids ...string) map[string]settingsBatchResult {
+	var mu sync.Mutex
+	results := map[string]settingsBatchResult{}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			entity, err := c.UpsertSettings(context.TODO(), SettingsObject{
+				Coordinate: coordinate.Coordinate{Type: "some:schema", ConfigId: id},
+				SchemaId:   "some:schema",
+				Content:    []byte("{}"),
+			}, UpsertSettingsOptions{})
+
+			mu.Lock()
+			results[id] = settingsBatchResult{entity: entity, err: err}
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return results
+}
+
+func TestUpsertSettings_Batched(t *testing.T) {
+	server, posts := newBatchTestServer(t, func(externalIDs []string) (int, string) {
+		var results []string
+		for _, id := range externalIDs {
+			results = append(results, fmt.Sprintf(`{"code": 200, "objectId": "object-%s"}`, id))
+		}
+		return http.StatusOK, fmt.Sprintf("[%s]", strings.Join(results, ","))
+	})
+
+	results := upsertConcurrently(newBatchTestClient(t, server, 3), "a", "b", "c")
+
+	require.Len(t, *posts, 1, "all objects must be sent in a single request")
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, (*posts)[0])
+	for _, id := range []string{"a", "b", "c"} {
+		assert.NoError(t, results[id].err)
+		assert.Equal(t, DynatraceEntity{Id: "object-" + id, Name: "object-" + id}, results[id].entity)
+	}
+}
+
+func TestUpsertSettings_Batched_ResendsRejectedObjectsOneByOne(t *testing.T) {
+	server, posts := newBatchTestServer(t, func(externalIDs []string) (int, string) {
+		if len(externalIDs) == 1 {
+			if externalIDs[0] == "b" {
+				return http.StatusBadRequest, `[{"code": 400, "error": {"message": "invalid"}}]`
+			}
+			return http.StatusOK, fmt.Sprintf(`[{"code": 200, "objectId": "object-%s"}]`, externalIDs[0])
+		}
+
+		var results []string
+		for _, id := range externalIDs {
+			if id == "b" {
+				results = append(results, `{"code": 400, "error": {"message": "invalid"}}`)
+			} else {
+				results = append(results, fmt.Sprintf(`{"code": 200, "objectId": "object-%s"}`, id))
+			}
+		}
+		return http.StatusMultiStatus, fmt.Sprintf("[%s]", strings.Join(results, ","))
+	})
+
+	results := upsertConcurrently(newBatchTestClient(t, server, 2), "a", "b")
+
+	require.Len(t, *posts, 1+1+testRetrySettings.Normal.MaxRetries, "the rejected object must be resent and retried one by one")
+	assert.Equal(t, []string{"b"}, (*posts)[1])
+	assert.NoError(t, results["a"].err)
+	assert.Equal(t, "object-a", results["a"].entity.Id)
+	assert.Error(t, results["b"].err)
+}
+
+func TestSettingsBatcher_SendsIncompleteBatchesAfterDelay(t *testing.T) {
+	sent := make(chan int, 1)
+	b := newSettingsBatcher(10, 10*time.Millisecond, func(entries []settingsBatchEntry) {
+		sent <- len(entries)
+		for _, e := range entries {
+			e.result <- settingsBatchResult{entity: DynatraceEntity{Id: "id"}}
+		}
+	})
+
+	entity, err := b.add(context.TODO(), settingsUpsertRequest{data: settingsRequest{SchemaId: "some:schema"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "id", entity.Id)
+	assert.Equal(t, 1, <-sent)
+}
@@ -54,6 +54,7 @@ type (
 
 	Schema struct {
 		SchemaId         string
+		Version          string
 		Ordered          bool
 		UniqueProperties [][]string
 	}
@@ -69,17 +70,19 @@ type (
 	}
 
 	postResponse struct {
+		// Code is the HTTP status code of the creation or update of the individual object
+		Code     int    `json:"code"`
 		ObjectId string `json:"objectId"`
 	}
 
 	settingsRequest struct {
-		SchemaId      string `json:"schemaId"`
-		ExternalId    string `json:"externalId,omitempty"`
-		Scope         string `json:"scope"`
-		Value         any    `json:"value"`
-		SchemaVersion string `json:"schemaVersion,omitempty"`
-		ObjectId      string `json:"objectId,omitempty"`
-		InsertAfter   string `json:"insertAfter,omitempty"`
+		SchemaId      string  `json:"schemaId"`
+		ExternalId    string  `json:"externalId,omitempty"`
+		Scope         string  `json:"scope"`
+		Value         any     `json:"value"`
+		SchemaVersion string  `json:"schemaVersion,omitempty"`
+		ObjectId      string  `json:"objectId,omitempty"`
+		InsertAfter   *string `json:"insertAfter,omitempty"`
 	}
 
 	schemaConstraint struct {
@@ -90,6 +93,7 @@ type (
 	// schemaDetailsResponse is the response type returned by the getSchema operation
 	schemaDetailsResponse struct {
 		SchemaId          string             `json:"schemaId"`
+		Version           string             `json:"version"`
 		Ordered           bool               `json:"ordered"`
 		SchemaConstraints []schemaConstraint `json:"schemaConstraints"`
 	}
@@ -166,19 +170,52 @@ func (d *DynatraceClient) getSchema(ctx context.Context, schemaID string) (Schem
 		}
 	}
 	ret.Ordered = sd.Ordered
+	ret.Version = sd.Version
 
 	d.schemaCache.Set(schemaID, ret)
 	return ret, nil
 }
 
 func (d *DynatraceClient) UpsertSettings(ctx context.Context, obj SettingsObject, options UpsertSettingsOptions) (result DynatraceEntity, err error) {
+	if d.settingsBatcher == nil {
+		d.limiter.ExecuteBlocking(func() {
+			result, err = d.upsertSettings(ctx, obj, options)
+		})
+		return
+	}
+
+	// the object is prepared right away, but only sent together with the other objects of its batch. Waiting for the
+	// batch must not block the limiter, as the batch may wait for further objects to be prepared.
+	var req *settingsUpsertRequest
 	d.limiter.ExecuteBlocking(func() {
-		result, err = d.upsertSettings(ctx, obj, options)
+		result, req, err = d.prepareSettingsUpsert(ctx, obj, options)
 	})
-	return
+	if err != nil || req == nil {
+		return
+	}
+	return d.settingsBatcher.add(ctx, *req)
 }
 
 func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject, options UpsertSettingsOptions) (DynatraceEntity, error) {
+	entity, req, err := d.prepareSettingsUpsert(ctx, obj, options)
+	if err != nil || req == nil {
+		return entity, err
+	}
+	return d.postSettingsObject(ctx, *req)
+}
+
+// settingsUpsertRequest is a prepared request to create or update a single settings object
+type settingsUpsertRequest struct {
+	ctx          context.Context
+	coordinate   coordinate.Coordinate
+	externalID   string
+	data         settingsRequest
+	retrySetting rest.RetrySetting
+}
+
+// prepareSettingsUpsert determines which existing object, if any, the given object updates, and prepares the request
+// to do so. If no request needs to be sent, the resulting entity is returned instead.
+func (d *DynatraceClient) prepareSettingsUpsert(ctx context.Context, obj SettingsObject, options UpsertSettingsOptions) (DynatraceEntity, *settingsUpsertRequest, error) {
 	// special handling for updating settings 2.0 objects on tenants with version pre 1.262.0
 	// Tenants with versions < 1.262 are not able to handle updates of existing
 	// settings 2.0 objects that are non-deletable.
@@ -187,19 +224,19 @@ func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject
 	if !d.serverVersion.Invalid() && d.serverVersion.SmallerThan(version.Version{Major: 1, Minor: 262, Patch: 0}) {
 		fetchedSettingObj, err := d.getSettingById(ctx, obj.OriginObjectId)
 		if err != nil && !errors.Is(err, ErrSettingNotFound) {
-			return DynatraceEntity{}, fmt.Errorf("unable to fetch settings object with object id %q: %w", obj.OriginObjectId, err)
+			return DynatraceEntity{}, nil, fmt.Errorf("unable to fetch settings object with object id %q: %w", obj.OriginObjectId, err)
 		}
 		if fetchedSettingObj != nil {
 			log.WithCtxFields(ctx).Warn("Unable to update Settings 2.0 object of schema %q and object id %q on Dynatrace environment with a version < 1.262.0", obj.SchemaId, obj.OriginObjectId)
 			return DynatraceEntity{
 				Id:   fetchedSettingObj.ObjectId,
 				Name: fetchedSettingObj.ObjectId,
-			}, nil
+			}, nil, nil
 		}
 	}
 
 	if matchingObject, found, err := d.findObjectWithMatchingConstraints(ctx, obj); err != nil {
-		return DynatraceEntity{}, err
+		return DynatraceEntity{}, nil, err
 	} else if found {
 
 		var props []string
@@ -217,14 +254,14 @@ func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject
 	// This can be removed in a later release of monaco
 	legacyExternalID, err := d.generateExternalID(coordinate.Coordinate{Type: obj.Coordinate.Type, ConfigId: obj.Coordinate.ConfigId})
 	if err != nil {
-		return DynatraceEntity{}, fmt.Errorf("unable to generate external id: %w", err)
+		return DynatraceEntity{}, nil, fmt.Errorf("unable to generate external id: %w", err)
 	}
 
 	settingsWithExternalID, err := d.listSettings(ctx, obj.SchemaId, ListSettingsOptions{
 		Filter: func(object DownloadSettingsObject) bool { return object.ExternalId == legacyExternalID },
 	})
 	if err != nil {
-		return DynatraceEntity{}, err
+		return DynatraceEntity{}, nil, err
 	}
 
 	if len(settingsWithExternalID) > 0 {
@@ -233,7 +270,7 @@ func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject
 
 	externalID, err := d.generateExternalID(obj.Coordinate)
 	if err != nil {
-		return DynatraceEntity{}, fmt.Errorf("unable to generate external id: %w", err)
+		return DynatraceEntity{}, nil, fmt.Errorf("unable to generate external id: %w", err)
 	}
 
 	// If the server contains two configs, one with the origin-object-id and a second config with the externalID,
@@ -246,7 +283,7 @@ func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject
 		},
 	})
 	if err != nil {
-		return DynatraceEntity{}, err
+		return DynatraceEntity{}, nil, err
 	}
 	if len(settings) == 2 {
 		var exIdSetting, ooIdSetting string
@@ -264,32 +301,46 @@ func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject
 
 	if schema, ok := d.schemaCache.Get(obj.SchemaId); ok {
 		if options.InsertAfter != "" && !schema.Ordered {
-			return DynatraceEntity{}, fmt.Errorf("'%s' is not an ordered setting, hence 'insertAfter' is not supported for this type of setting object", obj.SchemaId)
+			return DynatraceEntity{}, nil, fmt.Errorf("'%s' is not an ordered setting, hence 'insertAfter' is not supported for this type of setting object", obj.SchemaId)
 		}
 	}
 
-	payload, err := buildPostRequestPayload(ctx, obj, externalID, options.InsertAfter)
+	data, err := buildSettingsRequest(obj, externalID, options.InsertAfter)
 	if err != nil {
-		return DynatraceEntity{}, fmt.Errorf("failed to build settings object: %w", err)
+		return DynatraceEntity{}, nil, fmt.Errorf("failed to build settings object: %w", err)
 	}
 
-	var retrySetting rest.RetrySetting
+	retrySetting := d.retrySettings.Normal
 	if options.OverrideRetry != nil {
 		retrySetting = *options.OverrideRetry
-	} else {
-		retrySetting = d.retrySettings.Normal
+	}
+
+	return DynatraceEntity{}, &settingsUpsertRequest{
+		ctx:          ctx,
+		coordinate:   obj.Coordinate,
+		externalID:   externalID,
+		data:         data,
+		retrySetting: retrySetting,
+	}, nil
+}
+
+// postSettingsObject sends a prepared request for a single settings object, retrying it according to its retry setting
+func (d *DynatraceClient) postSettingsObject(ctx context.Context, req settingsUpsertRequest) (DynatraceEntity, error) {
+	payload, err := marshalSettingsRequests(ctx, []settingsRequest{req.data})
+	if err != nil {
+		return DynatraceEntity{}, fmt.Errorf("failed to build settings object: %w", err)
 	}
 
 	requestUrl := d.environmentURL + d.settingsObjectAPIPath
-	resp, err := rest.SendWithRetryWithInitialTry(ctx, d.platformClient.Post, requestUrl, payload, retrySetting)
+	resp, err := rest.SendWithRetryWithInitialTry(ctx, d.platformClient.Post, requestUrl, payload, req.retrySetting)
 	if err != nil {
-		d.settingsCache.Delete(obj.SchemaId)
-		return DynatraceEntity{}, fmt.Errorf("failed to create or update Settings object with externalId %s: %w", externalID, err)
+		d.settingsCache.Delete(req.data.SchemaId)
+		return DynatraceEntity{}, fmt.Errorf("failed to create or update Settings object with externalId %s: %w", req.externalID, err)
 	}
 
 	if !resp.IsSuccess() {
-		d.settingsCache.Delete(obj.SchemaId)
-		return DynatraceEntity{}, rest.NewRespErr(fmt.Sprintf("failed to create or update Settings object with externalId %s (HTTP %d)!\n\tResponse was: %s", externalID, resp.StatusCode, string(resp.Body)), resp).WithRequestInfo(http.MethodPost, requestUrl)
+		d.settingsCache.Delete(req.data.SchemaId)
+		return DynatraceEntity{}, rest.NewRespErr(fmt.Sprintf("failed to create or update Settings object with externalId %s (HTTP %d)!\n\tResponse was: %s", req.externalID, resp.StatusCode, string(resp.Body)), resp).WithRequestInfo(http.MethodPost, requestUrl)
 	}
 
 	entity, err := parsePostResponse(resp)
@@ -297,7 +348,7 @@ func (d *DynatraceClient) upsertSettings(ctx context.Context, obj SettingsObject
 		return DynatraceEntity{}, rest.NewRespErr("failed to parse response", resp).WithRequestInfo(http.MethodPost, requestUrl).WithErr(err)
 	}
 
-	log.WithCtxFields(ctx).Debug("Created/Updated object %s (%s) with externalId %s", obj.Coordinate.ConfigId, obj.SchemaId, externalID)
+	log.WithCtxFields(ctx).Debug("Created/Updated object %s (%s) with externalId %s", req.coordinate.ConfigId, req.data.SchemaId, req.externalID)
 	return entity, nil
 }
 
@@ -415,9 +466,17 @@ func isSameValueForKey(key string, c1 []byte, c2 []byte) (same bool, matchingVal
 // Currently, we only encode one object into an array of objects, but we can optimize it to contain multiple elements to update.
 // Note payload limitations: https://www.dynatrace.com/support/help/dynatrace-api/basics/access-limit#payload-limit
 func buildPostRequestPayload(ctx context.Context, obj SettingsObject, externalID string, insertAfter string) ([]byte, error) {
+	data, err := buildSettingsRequest(obj, externalID, insertAfter)
+	if err != nil {
+		return nil, err
+	}
+	return marshalSettingsRequests(ctx, []settingsRequest{data})
+}
+
+func buildSettingsRequest(obj SettingsObject, externalID string, insertAfter string) (settingsRequest, error) {
 	var value any
 	if err := json.Unmarshal(obj.Content, &value); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal rendered config: %w", err)
+		return settingsRequest{}, fmt.Errorf("failed to unmarshal rendered config: %w", err)
 	}
 
 	data := settingsRequest{
@@ -427,12 +486,22 @@ func buildPostRequestPayload(ctx context.Context, obj SettingsObject, externalID
 		Value:         value,
 		SchemaVersion: obj.SchemaVersion,
 		ObjectId:      obj.OriginObjectId,
-		InsertAfter:   insertAfter,
 	}
 
-	// Create json obj. We currently marshal everything into an array, but we can optimize it to include multiple objects in the
-	// future. Look up limits when imp
-	fullObj, err := json.Marshal([]interface{}{data})
+	// the Settings API places an object at the front if insertAfter is an empty string, and at the back if it is omitted
+	switch insertAfter {
+	case "":
+	case InsertAfterFront:
+		data.InsertAfter = new(string)
+	default:
+		data.InsertAfter = &insertAfter
+	}
+	return data, nil
+}
+
+// marshalSettingsRequests creates the payload of a request creating or updating all given objects at once
+func marshalSettingsRequests(ctx context.Context, data []settingsRequest) ([]byte, error) {
+	fullObj, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal full object: %w", err)
 	}
@@ -1677,3 +1677,36 @@ func TestDeleteSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildPostRequestPayload_InsertAfter(t *testing.T) {
+	obj := SettingsObject{SchemaId: "builtin:ordered", Scope: "environment", Content: []byte(`{}`)}
+
+	tests := []struct {
+		name        string
+		insertAfter string
+		want        string
+	}{
+		{
+			name:        "omitted if empty",
+			insertAfter: "",
+			want:        `[{"schemaId":"builtin:ordered","scope":"environment","value":{}}]`,
+		},
+		{
+			name:        "empty string for front",
+			insertAfter: InsertAfterFront,
+			want:        `[{"schemaId":"builtin:ordered","scope":"environment","value":{},"insertAfter":""}]`,
+		},
+		{
+			name:        "object ID",
+			insertAfter: "object-id",
+			want:        `[{"schemaId":"builtin:ordered","scope":"environment","value":{},"insertAfter":"object-id"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := buildPostRequestPayload(context.TODO(), obj, "", tt.insertAfter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(payload))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dtclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// validatorPathSuffix is appended to the URL of a config API to reach its validation endpoint, e.g.
+// POST <environment-url>/api/config/v1/alertingProfiles/validator
+const validatorPathSuffix = "validator"
+
+func (d *DynatraceClient) ValidateConfig(ctx context.Context, a api.API, payload []byte) (err error) {
+	d.limiter.ExecuteBlocking(func() {
+		err = d.validateConfig(ctx, a, payload)
+	})
+	return
+}
+
+func (d *DynatraceClient) validateConfig(ctx context.Context, a api.API, payload []byte) error {
+	if a.ID == api.Extension {
+		log.WithCtxFields(ctx).Debug("Skipping validation of %q config, as extensions can not be validated", a.ID)
+		return nil
+	}
+
+	u, err := url.JoinPath(a.CreateURL(d.environmentURLClassic), validatorPathSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to build validation URL for API %q: %w", a.ID, err)
+	}
+
+	resp, err := d.classicClient.Post(ctx, u, payload)
+	if err != nil {
+		return fmt.Errorf("failed to validate config of API %q: %w", a.ID, err)
+	}
+
+	// not every config API offers a validation endpoint - in that case there is nothing we can validate against
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		log.WithCtxFields(ctx).Debug("API %q does not offer a validation endpoint (HTTP %d) - skipping validation", a.ID, resp.StatusCode)
+		return nil
+	}
+
+	if !resp.IsSuccess() {
+		return rest.NewRespErr(fmt.Sprintf("validation of %q config failed (HTTP %d)!\n\tResponse was: %s", a.ID, resp.StatusCode, string(resp.Body)), resp).WithRequestInfo(http.MethodPost, u)
+	}
+	return nil
+}
+
+func (d *DynatraceClient) ValidateSettings(ctx context.Context, obj SettingsObject) (err error) {
+	d.limiter.ExecuteBlocking(func() {
+		err = d.validateSettings(ctx, obj)
+	})
+	return
+}
+
+func (d *DynatraceClient) validateSettings(ctx context.Context, obj SettingsObject) error {
+	externalID, err := d.generateExternalID(obj.Coordinate)
+	if err != nil {
+		return fmt.Errorf("unable to generate external id: %w", err)
+	}
+
+	payload, err := buildPostRequestPayload(ctx, obj, externalID, "")
+	if err != nil {
+		return fmt.Errorf("failed to build settings object: %w", err)
+	}
+
+	u, err := buildUrl(d.environmentURL, d.settingsObjectAPIPath, url.Values{"validateOnly": []string{"true"}})
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.platformClient.Post(ctx, u.String(), payload)
+	if err != nil {
+		return fmt.Errorf("failed to validate Settings object with externalId %s: %w", externalID, err)
+	}
+
+	if !resp.IsSuccess() {
+		return rest.NewRespErr(fmt.Sprintf("validation of Settings object with externalId %s failed (HTTP %d)!\n\tResponse was: %s", externalID, resp.StatusCode, string(resp.Body)), resp).WithRequestInfo(http.MethodPost, u.String())
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dtclient
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	theAPI := api.API{ID: "alerting-profile", URLPath: "/api/config/v1/alertingProfiles"}
+
+	tests := []struct {
+		name         string
+		api          api.API
+		responseCode int
+		wantCalled   bool
+		wantErr      bool
+	}{
+		{
+			name:         "valid payload",
+			api:          theAPI,
+			responseCode: http.StatusNoContent,
+			wantCalled:   true,
+		},
+		{
+			name:         "invalid payload returns error",
+			api:          theAPI,
+			responseCode: http.StatusBadRequest,
+			wantCalled:   true,
+			wantErr:      true,
+		},
+		{
+			name:         "API without validator is skipped",
+			api:          theAPI,
+			responseCode: http.StatusNotFound,
+			wantCalled:   true,
+		},
+		{
+			name:       "extensions are not validated",
+			api:        api.API{ID: api.Extension, URLPath: "/api/config/v1/extensions"},
+			wantCalled: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, tc.api.URLPath+"/validator", req.URL.Path)
+				body, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{"name": "profile"}`, string(body))
+				rw.WriteHeader(tc.responseCode)
+			}))
+			defer server.Close()
+
+			c, err := NewClassicClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+			require.NoError(t, err)
+
+			err = c.ValidateConfig(context.TODO(), tc.api, []byte(`{"name": "profile"}`))
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantCalled, called)
+		})
+	}
+}
+
+func TestValidateSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseCode int
+		wantErr      bool
+	}{
+		{
+			name:         "valid settings object",
+			responseCode: http.StatusOK,
+		},
+		{
+			name:         "invalid settings object returns error",
+			responseCode: http.StatusBadRequest,
+			wantErr:      true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, settingsObjectAPIPathClassic, req.URL.Path)
+				assert.Equal(t, "true", req.URL.Query().Get("validateOnly"))
+				rw.WriteHeader(tc.responseCode)
+				_, _ = rw.Write([]byte(`[{"code": 200}]`))
+			}))
+			defer server.Close()
+
+			c, err := NewClassicClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+			require.NoError(t, err)
+
+			err = c.ValidateSettings(context.TODO(), SettingsObject{
+				Coordinate: coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"},
+				SchemaId:   "builtin:alerting.profile",
+				Scope:      "environment",
+				Content:    []byte(`{"name": "profile"}`),
+			})
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
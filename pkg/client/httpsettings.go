@@ -0,0 +1,99 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPSettings customize the HTTP connections to an environment
+type HTTPSettings struct {
+	// ProxyURL is the URL of the proxy all requests are sent through. If it is nil, the proxy is taken from the
+	// environment variables HTTPS_PROXY, HTTP_PROXY, and NO_PROXY.
+	ProxyURL *url.URL
+	// CACertificates are PEM encoded certificates of CAs that are trusted in addition to the system's CAs
+	CACertificates []byte
+	// Timeout limits the duration of a single request, if > 0
+	Timeout time.Duration
+	// MaxIdleConnsPerHost is the number of idle connections kept open for reuse, defaults to defaultMaxIdleConnsPerHost if 0
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the number of connections, if > 0
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long idle connections are kept open, defaults to defaultIdleConnTimeout if 0
+	IdleConnTimeout time.Duration
+	// DisableTLSSessionReuse disables resuming TLS sessions when opening new connections
+	DisableTLSSessionReuse bool
+	// DisableHTTP2 disables HTTP/2, so that all requests are sent using HTTP/1.1
+	DisableHTTP2 bool
+}
+
+// The transport defaults are tuned for sending many concurrent requests to few hosts. Go's defaults only keep two idle
+// connections per host, resulting in connections being closed and opened again all the time during large deployments.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 32
+	defaultIdleConnTimeout     = 90 * time.Second
+	tlsSessionCacheSize        = 64
+)
+
+// httpClient returns the unauthenticated client all clients of a client set are based on
+func (o ClientOptions) httpClient() (*http.Client, error) {
+	settings := HTTPSettings{}
+	if o.HTTPSettings != nil {
+		settings = *o.HTTPSettings
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = cmp.Or(settings.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost)
+	transport.MaxIdleConns = max(defaultMaxIdleConns, transport.MaxIdleConnsPerHost)
+	transport.MaxConnsPerHost = settings.MaxConnsPerHost
+	transport.IdleConnTimeout = cmp.Or(settings.IdleConnTimeout, defaultIdleConnTimeout)
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if !settings.DisableTLSSessionReuse {
+		transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(tlsSessionCacheSize)
+	}
+
+	if settings.DisableHTTP2 {
+		// a non-nil, empty map prevents the transport from upgrading to HTTP/2
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	if settings.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(settings.ProxyURL)
+	}
+
+	if len(settings.CACertificates) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(settings.CACertificates) {
+			return nil, errors.New("failed to parse CA certificates: no PEM encoded certificate found")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	return &http.Client{Transport: transport, Timeout: settings.Timeout}, nil
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientOptions_httpClient(t *testing.T) {
+	t.Run("without http settings the transport is tuned for high throughput", func(t *testing.T) {
+		c, err := ClientOptions{}.httpClient()
+		require.NoError(t, err)
+		assert.Zero(t, c.Timeout)
+
+		transport := c.Transport.(*http.Transport)
+		assert.Equal(t, defaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, defaultIdleConnTimeout, transport.IdleConnTimeout)
+		assert.Zero(t, transport.MaxConnsPerHost)
+		assert.True(t, transport.ForceAttemptHTTP2)
+		assert.NotNil(t, transport.TLSClientConfig.ClientSessionCache)
+	})
+
+	t.Run("connection pooling is tuned", func(t *testing.T) {
+		c, err := ClientOptions{HTTPSettings: &HTTPSettings{
+			MaxIdleConnsPerHost:    200,
+			MaxConnsPerHost:        250,
+			IdleConnTimeout:        time.Minute,
+			DisableTLSSessionReuse: true,
+			DisableHTTP2:           true,
+		}}.httpClient()
+		require.NoError(t, err)
+
+		transport := c.Transport.(*http.Transport)
+		assert.Equal(t, 200, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, 200, transport.MaxIdleConns)
+		assert.Equal(t, 250, transport.MaxConnsPerHost)
+		assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+		assert.Nil(t, transport.TLSClientConfig.ClientSessionCache)
+		assert.False(t, transport.ForceAttemptHTTP2)
+		assert.NotNil(t, transport.TLSNextProto)
+	})
+
+	t.Run("proxy and timeout are set", func(t *testing.T) {
+		proxy, err := url.Parse("http://proxy.example.com:8080")
+		require.NoError(t, err)
+
+		c, err := ClientOptions{HTTPSettings: &HTTPSettings{ProxyURL: proxy, Timeout: time.Minute}}.httpClient()
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, c.Timeout)
+
+		req := httptest.NewRequest(http.MethodGet, "https://env.example.com", nil)
+		got, err := c.Transport.(*http.Transport).Proxy(req)
+		require.NoError(t, err)
+		assert.Equal(t, proxy, got)
+	})
+
+	t.Run("custom CA certificates are trusted", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c, err := ClientOptions{HTTPSettings: &HTTPSettings{}}.httpClient()
+		require.NoError(t, err)
+		_, err = c.Get(server.URL)
+		assert.Error(t, err, "certificate of test server should not be trusted without its CA")
+
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		c, err = ClientOptions{HTTPSettings: &HTTPSettings{CACertificates: ca}}.httpClient()
+		require.NoError(t, err)
+		resp, err := c.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("invalid CA certificates are rejected", func(t *testing.T) {
+		_, err := ClientOptions{HTTPSettings: &HTTPSettings{CACertificates: []byte("not a certificate")}}.httpClient()
+		assert.ErrorContains(t, err, "failed to parse CA certificates")
+	})
+}
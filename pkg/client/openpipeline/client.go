@@ -0,0 +1,104 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openpipeline implements a client for the configurations of the Dynatrace Platform OpenPipeline API.
+package openpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// APIPath is the path of the OpenPipeline configurations API of a platform environment
+const APIPath = "/platform/openpipeline/v1/configurations"
+
+// Response represents a single OpenPipeline configuration as returned by the OpenPipeline API.
+type Response struct {
+	// ID of the configuration, which is the kind of data it processes, e.g. "logs" or "events"
+	ID string
+	// Data is the full JSON payload of the configuration
+	Data []byte
+}
+
+type listEntry struct {
+	ID string `json:"id"`
+}
+
+// Client accesses the OpenPipeline API of a platform environment.
+type Client struct {
+	client         *rest.Client
+	environmentURL string
+}
+
+// NewClient creates a Client for the environment at environmentURL, using the given rest.Client to send requests.
+func NewClient(environmentURL string, client *rest.Client) *Client {
+	return &Client{client: client, environmentURL: environmentURL}
+}
+
+// Get returns the configuration with the given ID. If no such configuration exists, a rest.RespError with status 404 is
+// returned.
+func (c *Client) Get(ctx context.Context, id string) (Response, error) {
+	u, err := url.JoinPath(c.environmentURL, APIPath, id)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to build URL for OpenPipeline configuration %q: %w", id, err)
+	}
+
+	resp, err := c.client.Get(ctx, u)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to GET OpenPipeline configuration %q: %w", id, err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to get OpenPipeline configuration %q", id), resp).WithRequestInfo(http.MethodGet, u)
+	}
+	return Response{ID: id, Data: resp.Body}, nil
+}
+
+// List returns all configurations of the environment. As the list endpoint only returns the IDs of the
+// configurations, every configuration is fetched individually.
+func (c *Client) List(ctx context.Context) ([]Response, error) {
+	u, err := url.JoinPath(c.environmentURL, APIPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build URL for OpenPipeline configurations: %w", err)
+	}
+
+	resp, err := c.client.Get(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OpenPipeline configurations: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, rest.NewRespErr("failed to list OpenPipeline configurations", resp).WithRequestInfo(http.MethodGet, u)
+	}
+
+	var entries []listEntry
+	if err := json.Unmarshal(resp.Body, &entries); err != nil {
+		return nil, rest.NewRespErr("failed to unmarshal OpenPipeline configuration list", resp).WithRequestInfo(http.MethodGet, u).WithErr(err)
+	}
+
+	result := make([]Response, 0, len(entries))
+	for _, e := range entries {
+		r, err := c.Get(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
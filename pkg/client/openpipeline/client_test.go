@@ -0,0 +1,53 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openpipeline_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/openpipeline"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_List_FetchesEveryConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case openpipeline.APIPath:
+			fmt.Fprint(w, `[{"id": "logs", "editable": true}, {"id": "events", "editable": true}]`)
+		case openpipeline.APIPath + "/logs", openpipeline.APIPath + "/events":
+			fmt.Fprintf(w, `{"id": %q, "version": "1", "pipelines": []}`, r.URL.Path[len(openpipeline.APIPath)+1:])
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := openpipeline.NewClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+
+	got, err := c.List(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "logs", got[0].ID)
+	assert.JSONEq(t, `{"id": "events", "version": "1", "pipelines": []}`, string(got[1].Data))
+}
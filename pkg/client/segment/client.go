@@ -0,0 +1,118 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package segment implements a client for the filter segments of the Dynatrace Platform Grail storage API.
+package segment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// APIPath is the path of the filter segments API of a platform environment
+const APIPath = "/platform/storage/filter-segments/v1/filter-segments"
+
+// Response represents a single segment as returned by the filter segments API.
+type Response struct {
+	// UID of the segment
+	UID string
+	// Name of the segment
+	Name string
+	// Data is the full JSON payload of the segment
+	Data []byte
+}
+
+type segmentMetadata struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+type listResponse struct {
+	FilterSegments []segmentMetadata `json:"filterSegments"`
+}
+
+// Client accesses the filter segments API of a platform environment.
+type Client struct {
+	client         *rest.Client
+	environmentURL string
+}
+
+// NewClient creates a Client for the environment at environmentURL, using the given rest.Client to send requests.
+func NewClient(environmentURL string, client *rest.Client) *Client {
+	return &Client{client: client, environmentURL: environmentURL}
+}
+
+// Get returns the segment with the given UID, including its includes and variables. If no such segment exists, a
+// rest.RespError with status 404 is returned.
+func (c *Client) Get(ctx context.Context, uid string) (Response, error) {
+	u, err := url.Parse(c.environmentURL)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to parse environment URL %q: %w", c.environmentURL, err)
+	}
+	u = u.JoinPath(APIPath, uid)
+	u.RawQuery = url.Values{"add-fields": []string{"INCLUDES", "VARIABLES"}}.Encode()
+
+	resp, err := c.client.Get(ctx, u.String())
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to GET segment %q: %w", uid, err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to get segment %q", uid), resp).WithRequestInfo(http.MethodGet, u.String())
+	}
+
+	var m segmentMetadata
+	if err := json.Unmarshal(resp.Body, &m); err != nil {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to unmarshal segment %q", uid), resp).WithRequestInfo(http.MethodGet, u.String()).WithErr(err)
+	}
+	return Response{UID: m.UID, Name: m.Name, Data: resp.Body}, nil
+}
+
+// List returns all segments of the environment. As the list endpoint only returns the metadata of the segments, every
+// segment is fetched individually.
+func (c *Client) List(ctx context.Context) ([]Response, error) {
+	u, err := url.JoinPath(c.environmentURL, APIPath+":lean")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build URL for segments: %w", err)
+	}
+
+	resp, err := c.client.Get(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, rest.NewRespErr("failed to list segments", resp).WithRequestInfo(http.MethodGet, u)
+	}
+
+	var list listResponse
+	if err := json.Unmarshal(resp.Body, &list); err != nil {
+		return nil, rest.NewRespErr("failed to unmarshal segment list", resp).WithRequestInfo(http.MethodGet, u).WithErr(err)
+	}
+
+	result := make([]Response, 0, len(list.FilterSegments))
+	for _, s := range list.FilterSegments {
+		r, err := c.Get(ctx, s.UID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
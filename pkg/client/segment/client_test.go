@@ -0,0 +1,69 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segment_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/segment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_List_FetchesEverySegment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case segment.APIPath + ":lean":
+			fmt.Fprint(w, `{"filterSegments": [{"uid": "a", "name": "first"}, {"uid": "b", "name": "second"}], "totalCount": 2}`)
+		case segment.APIPath + "/a", segment.APIPath + "/b":
+			assert.ElementsMatch(t, []string{"INCLUDES", "VARIABLES"}, r.URL.Query()["add-fields"])
+			uid := r.URL.Path[len(segment.APIPath)+1:]
+			fmt.Fprintf(w, `{"uid": %q, "name": "name-%s", "includes": []}`, uid, uid)
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := segment.NewClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+
+	got, err := c.List(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].UID)
+	assert.Equal(t, "name-a", got[0].Name)
+	assert.JSONEq(t, `{"uid": "b", "name": "name-b", "includes": []}`, string(got[1].Data))
+}
+
+func TestClient_Get_Fails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	c := segment.NewClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+
+	_, err := c.Get(context.TODO(), "a")
+	var respErr rest.RespError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+}
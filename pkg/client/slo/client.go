@@ -0,0 +1,212 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package slo implements a client for the service-level objectives (SLOs) of the Dynatrace Platform SLO API.
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// APIPath is the path of the SLO API of a platform environment
+const APIPath = "/platform/slo/v1/slos"
+
+// Response represents a single SLO as returned by the SLO API.
+type Response struct {
+	// ID of the SLO
+	ID string
+	// Name of the SLO
+	Name string
+	// Version is the current version of the SLO, which is required to modify it
+	Version string
+	// Data is the full JSON payload of the SLO
+	Data []byte
+}
+
+type sloMetadata struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type listResponse struct {
+	SLOs        []json.RawMessage `json:"slos"`
+	NextPageKey string            `json:"nextPageKey"`
+}
+
+// Client accesses the SLO API of a platform environment.
+type Client struct {
+	client         *rest.Client
+	environmentURL string
+}
+
+// NewClient creates a Client for the environment at environmentURL, using the given rest.Client to send requests.
+func NewClient(environmentURL string, client *rest.Client) *Client {
+	return &Client{client: client, environmentURL: environmentURL}
+}
+
+// Get returns the SLO with the given ID. If no such SLO exists, a rest.RespError with status 404 is returned.
+func (c *Client) Get(ctx context.Context, id string) (Response, error) {
+	u, err := url.JoinPath(c.environmentURL, APIPath, id)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to build URL for SLO %q: %w", id, err)
+	}
+
+	resp, err := c.client.Get(ctx, u)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to GET SLO %q: %w", id, err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to get SLO %q", id), resp).WithRequestInfo(http.MethodGet, u)
+	}
+	return newResponse(resp.Body)
+}
+
+// List returns all SLOs of the environment.
+func (c *Client) List(ctx context.Context) ([]Response, error) {
+	u, err := url.Parse(c.environmentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment URL %q: %w", c.environmentURL, err)
+	}
+	u = u.JoinPath(APIPath)
+
+	var result []Response
+	for {
+		resp, err := c.client.Get(ctx, u.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SLOs: %w", err)
+		}
+		if !resp.IsSuccess() {
+			return nil, rest.NewRespErr("failed to list SLOs", resp).WithRequestInfo(http.MethodGet, u.String())
+		}
+
+		var page listResponse
+		if err := json.Unmarshal(resp.Body, &page); err != nil {
+			return nil, rest.NewRespErr("failed to unmarshal SLO list", resp).WithRequestInfo(http.MethodGet, u.String()).WithErr(err)
+		}
+		for _, s := range page.SLOs {
+			r, err := newResponse(s)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, r)
+		}
+
+		if page.NextPageKey == "" {
+			return result, nil
+		}
+		// follow-up pages must only be requested by their page key
+		u.RawQuery = url.Values{"page-key": []string{page.NextPageKey}}.Encode()
+	}
+}
+
+// Create creates a new SLO from the given payload.
+func (c *Client) Create(ctx context.Context, data []byte) (Response, error) {
+	u, err := url.JoinPath(c.environmentURL, APIPath)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to build URL for SLOs: %w", err)
+	}
+
+	resp, err := c.client.Post(ctx, u, data)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to POST SLO: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr("failed to create SLO", resp).WithRequestInfo(http.MethodPost, u)
+	}
+	return newResponse(resp.Body)
+}
+
+// Update replaces the SLO with the given ID with the given payload. As the SLO API uses optimistic locking, the current
+// version of the SLO is fetched before it is updated.
+func (c *Client) Update(ctx context.Context, id string, data []byte) (Response, error) {
+	existing, err := c.Get(ctx, id)
+	if err != nil {
+		return Response{}, err
+	}
+
+	u, err := c.versionedURL(id, existing.Version)
+	if err != nil {
+		return Response{}, err
+	}
+
+	resp, err := c.client.Put(ctx, u, data)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to PUT SLO %q: %w", id, err)
+	}
+	if !resp.IsSuccess() {
+		return Response{}, rest.NewRespErr(fmt.Sprintf("failed to update SLO %q", id), resp).WithRequestInfo(http.MethodPut, u)
+	}
+
+	// a successful update does not return the SLO
+	return c.Get(ctx, id)
+}
+
+// Delete removes the SLO with the given ID. SLOs that do not exist are ignored.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	existing, err := c.Get(ctx, id)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	u, err := c.versionedURL(id, existing.Version)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Delete(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to DELETE SLO %q: %w", id, err)
+	}
+	if !resp.IsSuccess() && resp.StatusCode != http.StatusNotFound {
+		return rest.NewRespErr(fmt.Sprintf("failed to delete SLO %q", id), resp).WithRequestInfo(http.MethodDelete, u)
+	}
+	return nil
+}
+
+func (c *Client) versionedURL(id string, version string) (string, error) {
+	u, err := url.Parse(c.environmentURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse environment URL %q: %w", c.environmentURL, err)
+	}
+	u = u.JoinPath(APIPath, id)
+	u.RawQuery = url.Values{"optimistic-locking-version": []string{version}}.Encode()
+	return u.String(), nil
+}
+
+// IsNotFound returns whether the given error states that an SLO does not exist.
+func IsNotFound(err error) bool {
+	var notFound rest.NotFoundError
+	return errors.As(err, &notFound)
+}
+
+func newResponse(data []byte) (Response, error) {
+	var m sloMetadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Response{}, fmt.Errorf("failed to unmarshal SLO: %w", err)
+	}
+	return Response{ID: m.ID, Name: m.Name, Version: m.Version, Data: data}, nil
+}
@@ -0,0 +1,94 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slo_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *slo.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return slo.NewClient(server.URL, rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy()))
+}
+
+func TestClient_List_FollowsPages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, slo.APIPath, r.URL.Path)
+		switch r.URL.Query().Get("page-key") {
+		case "":
+			fmt.Fprint(w, `{"slos": [{"id": "a", "name": "first", "version": "1"}], "nextPageKey": "next"}`)
+		case "next":
+			fmt.Fprint(w, `{"slos": [{"id": "b", "name": "second", "version": "3"}]}`)
+		default:
+			t.Errorf("unexpected page key %q", r.URL.Query().Get("page-key"))
+		}
+	})
+
+	got, err := c.List(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].ID)
+	assert.Equal(t, "first", got[0].Name)
+	assert.Equal(t, "b", got[1].ID)
+	assert.Equal(t, "3", got[1].Version)
+}
+
+func TestClient_Update_UsesCurrentVersion(t *testing.T) {
+	var updated string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, slo.APIPath+"/a", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"id": "a", "name": "first", "version": "7"}`)
+		case http.MethodPut:
+			assert.Equal(t, "7", r.URL.Query().Get("optimistic-locking-version"))
+			body, _ := io.ReadAll(r.Body)
+			updated = string(body)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	_, err := c.Update(context.TODO(), "a", []byte(`{"name": "first"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "first"}`, updated)
+}
+
+func TestClient_Get_NotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := c.Get(context.TODO(), "a")
+	assert.True(t, slo.IsNotFound(err))
+
+	// deleting an SLO that does not exist is not an error
+	assert.NoError(t, c.Delete(context.TODO(), "a"))
+}
@@ -23,13 +23,24 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
 	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	assetParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/asset"
 	compoundParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/compound"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/constraint"
+	crossEnvironmentParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/crossenvironment"
+	entitySelectorParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entityselector"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	envParameterParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environmentparameter"
 	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
+	generatedParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/generated"
+	httpLookupParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/httplookup"
 	listParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
+	managementZoneParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/managementzone"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	secretParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"maps"
+	"strings"
 )
 
 const (
@@ -50,6 +61,10 @@ const (
 	// It is only a parameter iff the config is a settings-config.
 	InsertAfterParameter = "insertAfter"
 
+	// InsertAfterFront is the special value of the InsertAfterParameter that places a
+	// settings object at the front of an ordered schema.
+	InsertAfterFront = "front"
+
 	// SkipParameter is special in that config should be deployed or not
 	SkipParameter = "skip"
 
@@ -67,12 +82,16 @@ type Parameters map[string]parameter.Parameter
 type TypeId string
 
 const (
-	SettingsTypeId   TypeId = "settings"
-	ClassicApiTypeId TypeId = "classic"
-	EntityTypeId     TypeId = "entity"
-	AutomationTypeId TypeId = "automation"
-	BucketTypeId     TypeId = "bucket"
-	DocumentTypeId   TypeId = "document"
+	SettingsTypeId     TypeId = "settings"
+	ClassicApiTypeId   TypeId = "classic"
+	EntityTypeId       TypeId = "entity"
+	AutomationTypeId   TypeId = "automation"
+	BucketTypeId       TypeId = "bucket"
+	DocumentTypeId     TypeId = "document"
+	SLOTypeId          TypeId = "slo-v2"
+	SegmentTypeId      TypeId = "segment"
+	OpenPipelineTypeId TypeId = "openpipeline"
+	ClusterTypeId      TypeId = "cluster"
 )
 
 type Type interface {
@@ -143,6 +162,41 @@ func (DocumentType) ID() TypeId {
 	return DocumentTypeId
 }
 
+// SLOType represents a service-level objective deployed using the Platform SLO API. It replaces the classic "slo" API.
+type SLOType struct{}
+
+func (SLOType) ID() TypeId {
+	return SLOTypeId
+}
+
+// SegmentType represents a Grail filter segment.
+type SegmentType struct{}
+
+func (SegmentType) ID() TypeId {
+	return SegmentTypeId
+}
+
+// OpenPipelineType represents the OpenPipeline configuration of one kind of data.
+type OpenPipelineType struct {
+	// Kind is the kind of data the configuration processes, e.g. "logs" or "events". It is the ID of the configuration.
+	Kind string
+}
+
+func (OpenPipelineType) ID() TypeId {
+	return OpenPipelineTypeId
+}
+
+// ClusterType represents a cluster-wide configuration of a Dynatrace Managed cluster, deployed using its Cluster API.
+// Configs of this type can only be deployed to cluster environments.
+type ClusterType struct {
+	// Api is the ID of the endpoint of the Cluster API, e.g. "cluster-user-group"
+	Api string
+}
+
+func (ClusterType) ID() TypeId {
+	return ClusterTypeId
+}
+
 // Config struct defining a configuration which can be deployed.
 type Config struct {
 	// template used to render the request send to the dynatrace api
@@ -159,6 +213,10 @@ type Config struct {
 	// in the template
 	Parameters Parameters
 
+	// ParameterConstraints restrict the values parameters may resolve to, by parameter name. They are checked whenever
+	// the parameters are resolved.
+	ParameterConstraints map[string]constraint.Constraints
+
 	// Skip flag indicates if the deployment of this configuration should be skipped. It is resolved during project loading.
 	Skip bool
 
@@ -166,10 +224,92 @@ type Config struct {
 	// It is required as the object itself does only store the resolved 'skip' value, not the actual parameter.
 	SkipForConversion parameter.Parameter
 
+	// AnnotationsForConversion is only used for converting v1-configs to v2-configs.
+	// It holds the comments and anchors of the v1 yaml, so that they are kept in the converted config.yaml.
+	AnnotationsForConversion ConversionAnnotations
+
 	// OriginObjectId is the DT object ID of the object when it was downloaded from an environment
 	OriginObjectId string
+
+	// OriginExternalId is the external ID of the object when it was downloaded from an environment, if it had one
+	OriginExternalId string
+
+	// MatchStrategy defines how a config of a classic API with non-unique names is matched with existing objects. If
+	// empty, the default strategy is used.
+	MatchStrategy MatchStrategy
+
+	// MissingKeys defines how template variables are handled that are not defined as parameters. If empty, they are
+	// handled strictly.
+	MissingKeys MissingKeyMode
+}
+
+// MatchStrategy defines how a config of a classic API with non-unique names is matched with existing objects of the
+// same name when it is deployed
+type MatchStrategy string
+
+const (
+	// MatchByNameFirstMatch updates the first existing object of the same name, or creates a new object if none exists
+	MatchByNameFirstMatch MatchStrategy = "by-name-first-match"
+	// MatchByGeneratedID creates or updates the object with the ID generated from the config's coordinate, regardless
+	// of other objects of the same name
+	MatchByGeneratedID MatchStrategy = "by-generated-id"
+	// MatchAlwaysCreate creates a new object on every deployment
+	MatchAlwaysCreate MatchStrategy = "always-create"
+)
+
+// ConversionAnnotations hold the yaml annotations of a config and its parameters
+type ConversionAnnotations struct {
+	// Config holds the annotations of the config itself
+	Config YamlAnnotation
+	// Parameters holds the annotations by parameter name. The skip parameter is stored as SkipParameter.
+	Parameters map[string]YamlAnnotation
+}
+
+// IsEmpty returns true if there are no annotations at all
+func (a ConversionAnnotations) IsEmpty() bool {
+	return a.Config == (YamlAnnotation{}) && len(a.Parameters) == 0
 }
 
+// YamlAnnotation holds the documentation of a yaml node, which is not part of its value
+type YamlAnnotation struct {
+	// Comment of the node, without the leading '#' of each line
+	Comment string
+	// Anchor is the name of the anchor the node's value was defined with or referenced by
+	Anchor string
+}
+
+// Merge adds the comment of the other annotation, unless one of the comments already contains the other. The other
+// annotation's anchor takes precedence, if it has one.
+func (a YamlAnnotation) Merge(other YamlAnnotation) YamlAnnotation {
+	switch {
+	case strings.Contains(a.Comment, other.Comment):
+	case strings.Contains(other.Comment, a.Comment):
+		a.Comment = other.Comment
+	default:
+		a.Comment = a.Comment + "\n" + other.Comment
+	}
+	if other.Anchor != "" {
+		a.Anchor = other.Anchor
+	}
+	return a
+}
+
+// MatchStrategies lists all supported match strategies
+var MatchStrategies = []MatchStrategy{MatchByNameFirstMatch, MatchByGeneratedID, MatchAlwaysCreate}
+
+// MissingKeyMode defines how template variables are handled that are not defined as parameters of a config
+type MissingKeyMode string
+
+const (
+	// MissingKeysStrict fails rendering a template which references undefined parameters. This is the default.
+	MissingKeysStrict MissingKeyMode = "strict"
+	// MissingKeysLenient renders references to undefined parameters as empty strings
+	MissingKeysLenient MissingKeyMode = "lenient"
+)
+
+// MissingKeyModes lists all supported missing key modes
+var MissingKeyModes = []MissingKeyMode{MissingKeysStrict, MissingKeysLenient}
+
 func (c *Config) Render(properties map[string]interface{}) (string, error) {
 	if c == nil || c.Template == nil {
 		return "", nil
@@ -180,6 +320,10 @@ func (c *Config) Render(properties map[string]interface{}) (string, error) {
 		templatePath = t.FilePath()
 	}
 
+	if c.MissingKeys == MissingKeysLenient {
+		properties = withMissingKeys(c.Template, properties)
+	}
+
 	renderedConfig, err := template.Render(c.Template, properties)
 	if err != nil {
 		return "", configErrors.InvalidJsonError{
@@ -215,14 +359,44 @@ func (c *Config) Render(properties map[string]interface{}) (string, error) {
 	return renderedConfig, nil
 }
 
+// withMissingKeys returns a copy of the properties, in which all keys the template references, but which are not
+// defined, are set to an empty string. If the template can not be analysed, the properties are returned unchanged.
+func withMissingKeys(t template.Template, properties map[string]interface{}) map[string]interface{} {
+	keys, err := template.ReferencedKeys(t)
+	if err != nil {
+		return properties
+	}
+
+	result := maps.Clone(properties)
+	if result == nil {
+		result = make(map[string]interface{}, len(keys))
+	}
+	for _, k := range keys {
+		if _, found := result[k]; !found {
+			result[k] = ""
+		}
+	}
+	return result
+}
+
 // DefaultParameterParsers map defining a set of default parsers which can be used to load configurations
 var DefaultParameterParsers = map[string]parameter.ParameterSerDe{
-	refParam.ReferenceParameterType:           refParam.ReferenceParameterSerde,
-	valueParam.ValueParameterType:             valueParam.ValueParameterSerde,
-	envParam.EnvironmentVariableParameterType: envParam.EnvironmentVariableParameterSerde,
-	compoundParam.CompoundParameterType:       compoundParam.CompoundParameterSerde,
-	listParam.ListParameterType:               listParam.ListParameterSerde,
-	fileParam.FileParameterType:               fileParam.FileParameterSerde,
+	refParam.ReferenceParameterType:                              refParam.ReferenceParameterSerde,
+	valueParam.ValueParameterType:                                valueParam.ValueParameterSerde,
+	envParam.EnvironmentVariableParameterType:                    envParam.EnvironmentVariableParameterSerde,
+	envParameterParam.EnvironmentParameterType:                   envParameterParam.EnvironmentParameterSerde,
+	compoundParam.CompoundParameterType:                          compoundParam.CompoundParameterSerde,
+	listParam.ListParameterType:                                  listParam.ListParameterSerde,
+	fileParam.FileParameterType:                                  fileParam.FileParameterSerde,
+	secretParam.SecretParameterType:                              secretParam.SecretParameterSerde,
+	httpLookupParam.HTTPLookupParameterType:                      httpLookupParam.HTTPLookupParameterSerde,
+	entitySelectorParam.EntitySelectorParameterType:              entitySelectorParam.EntitySelectorParameterSerde,
+	crossEnvironmentParam.CrossEnvironmentReferenceParameterType: crossEnvironmentParam.CrossEnvironmentReferenceParameterSerde,
+	generatedParam.UUIDParameterType:                             generatedParam.UUIDParameterSerde,
+	generatedParam.TimestampParameterType:                        generatedParam.TimestampParameterSerde,
+	generatedParam.RandomStringParameterType:                     generatedParam.RandomStringParameterSerde,
+	managementZoneParam.ManagementZoneParameterType:              managementZoneParam.ManagementZoneParameterSerde,
+	assetParam.AssetParameterType:                                assetParam.AssetParameterSerde,
 }
 
 func (c *Config) References() []coordinate.Coordinate {
@@ -246,7 +420,9 @@ func (c *Config) References() []coordinate.Coordinate {
 	return refs
 }
 
-// EntityLookup is used in parameter resolution to fetch the resolved entity of deployed configuration
+// EntityLookup is used in parameter resolution to fetch the resolved entity of deployed configuration.
+// If it also implements parameter.EnvironmentPropertyResolver, it is used to resolve references to configs deployed to
+// other environments.
 type EntityLookup interface {
 	parameter.PropertyResolver
 
@@ -23,6 +23,8 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/constraint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -93,6 +95,49 @@ func TestResolveParameterValues(t *testing.T) {
 	assert.Equal(t, timeout, values[timeoutParameterName])
 }
 
+// cachingEntityLookup is an entityLookup providing a parameter value cache
+type cachingEntityLookup struct {
+	entityLookup
+	cache *parameter.ValueCache
+}
+
+func (e cachingEntityLookup) ParameterValueCache() *parameter.ValueCache {
+	return e.cache
+}
+
+func TestResolveParameterValuesCachesValuesOfCacheableParameters(t *testing.T) {
+	t.Setenv("CACHED_VARIABLE", "first")
+
+	newConfig := func(id string) Config {
+		return Config{
+			Template:    generateDummyTemplate(t),
+			Coordinate:  coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: id},
+			Environment: "development",
+			Parameters: Parameters{
+				NameParameter: environment.New("CACHED_VARIABLE"),
+			},
+		}
+	}
+	lookup := cachingEntityLookup{entityLookup: entityLookup{}, cache: parameter.NewValueCache()}
+
+	c1 := newConfig("dashboard-1")
+	values, errs := c1.ResolveParameterValues(lookup)
+	assert.Empty(t, errs)
+	assert.Equal(t, "first", values[NameParameter])
+
+	// the variable is only read once per cache, thus changes are not picked up by other configs
+	t.Setenv("CACHED_VARIABLE", "second")
+	c2 := newConfig("dashboard-2")
+	values, errs = c2.ResolveParameterValues(lookup)
+	assert.Empty(t, errs)
+	assert.Equal(t, "first", values[NameParameter])
+
+	// without a cache, every config reads the variable
+	values, errs = c2.ResolveParameterValues(entityLookup{})
+	assert.Empty(t, errs)
+	assert.Equal(t, "second", values[NameParameter])
+}
+
 func TestResolveParameterValuesShouldFailWhenReferencingNonExistingConfig(t *testing.T) {
 	nonExistingConfig := coordinate.Coordinate{
 		Project:  "non-existing",
@@ -204,6 +249,34 @@ func TestResolveParameterValuesShouldFailWhenParameterResolveReturnsError(t *tes
 	assert.NotEmpty(t, errs, "there should be errors (no : %d)", len(errs))
 }
 
+func TestResolveParameterValuesShouldFailWhenViolatingConstraints(t *testing.T) {
+	parameters := []parameter.NamedParameter{
+		{Name: NameParameter, Parameter: &parameter.DummyParameter{Value: "name"}},
+		{Name: "threshold", Parameter: &parameter.DummyParameter{Value: 150}},
+		{Name: "severity", Parameter: &parameter.DummyParameter{Value: "ERROR"}},
+	}
+
+	conf := Config{
+		Template: generateDummyTemplate(t),
+		Coordinate: coordinate.Coordinate{
+			Project:  "project1",
+			Type:     "dashboard",
+			ConfigId: "dashboard-1",
+		},
+		Environment: "development",
+		Parameters:  toParameterMap(parameters),
+		ParameterConstraints: map[string]constraint.Constraints{
+			"severity":  {Enum: []string{"ERROR", "WARNING"}},
+			"threshold": {Max: new(float64)},
+		},
+	}
+
+	_, errs := conf.ResolveParameterValues(entityLookup{})
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "value 150 is greater than the maximum 0")
+}
+
 func TestValidateParameterReferences(t *testing.T) {
 	configCoordinates := coordinate.Coordinate{
 		Project:  "project1",
@@ -386,3 +459,25 @@ func TestConfigMethodsAreNilSafe(t *testing.T) {
 		})
 	})
 }
+
+func TestRenderWithMissingKeys(t *testing.T) {
+	c := Config{
+		Template: template.NewInMemoryTemplate("t", `{"name": "{{ .name }}", "description": "{{ .description }}"}`),
+	}
+	properties := map[string]interface{}{"name": "n"}
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := c.Render(properties)
+		assert.ErrorContains(t, err, `map has no entry for key "description"`)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		c := c
+		c.MissingKeys = MissingKeysLenient
+
+		got, err := c.Render(properties)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name": "n", "description": ""}`, got)
+		assert.NotContains(t, properties, "description", "properties must not be modified")
+	})
+}
@@ -14,7 +14,10 @@
 
 package coordinate
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Coordinate struct used to specify the location of a certain configuration
 type Coordinate struct {
@@ -38,3 +41,14 @@ func (c Coordinate) Match(coordinate Coordinate) bool {
 		c.Type == coordinate.Type &&
 		c.ConfigId == coordinate.ConfigId
 }
+
+// Parse parses a coordinate in the form 'project:type:configId', as returned by Coordinate.String. As types may contain
+// colons themselves - e.g. 'builtin:alerting.profile' - the project is the first, and the config ID the last part.
+func Parse(s string) (Coordinate, error) {
+	project, rest, _ := strings.Cut(s, ":")
+	i := strings.LastIndex(rest, ":")
+	if project == "" || i <= 0 || i == len(rest)-1 {
+		return Coordinate{}, fmt.Errorf("invalid coordinate %q, expected the form 'project:type:configId'", s)
+	}
+	return Coordinate{Project: project, Type: rest[:i], ConfigId: rest[i+1:]}, nil
+}
@@ -68,3 +68,30 @@ func TestMatchShouldReturnFalseOnNonMatchingSameApi(t *testing.T) {
 
 	assert.False(t, result, "shouldn't match")
 }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		given   string
+		want    Coordinate
+		wantErr bool
+	}{
+		{given: "project:dashboard:config", want: Coordinate{Project: "project", Type: "dashboard", ConfigId: "config"}},
+		{given: "project:builtin:alerting.profile:config", want: Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "config"}},
+		{given: "project:dashboard", wantErr: true},
+		{given: ":dashboard:config", wantErr: true},
+		{given: "project::config", wantErr: true},
+		{given: "project:dashboard:", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			got, err := Parse(tt.given)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.given, got.String())
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package asset
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/spf13/afero"
+)
+
+// AssetParameterType specifies the type of the parameter used in config files
+const AssetParameterType = "asset"
+
+var AssetParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeAssetParameter,
+	Deserializer: parseAssetParameter,
+}
+
+// AssetParameter inserts the content of a binary file - e.g. an extension archive - as base64 encoded string into a
+// template. Unlike the file parameter, the content is never rendered as template, but used byte by byte.
+// The path is relative to the folder of the config. Configs are written with their assets stored as separate files.
+type AssetParameter struct {
+	Fs   afero.Fs
+	Path string
+	// content of the asset, if it is not stored in Fs - e.g. for downloaded configs
+	content []byte
+}
+
+// New creates an AssetParameter holding the given content, which is written to the path relative to the config's folder
+// when the config is persisted.
+func New(path string, content []byte) *AssetParameter {
+	return &AssetParameter{Path: path, content: content}
+}
+
+// this forces the compiler to check if AssetParameter is of type CacheableParameter
+var _ parameter.CacheableParameter = (*AssetParameter)(nil)
+
+func (p *AssetParameter) GetType() string {
+	return AssetParameterType
+}
+
+func (p *AssetParameter) GetReferences() []parameter.ParameterReference {
+	return []parameter.ParameterReference{}
+}
+
+// Content returns the raw content of the asset
+func (p *AssetParameter) Content() ([]byte, error) {
+	if p.content != nil {
+		return p.content, nil
+	}
+	if p.Fs == nil {
+		return nil, fmt.Errorf("asset %q has no content", p.Path)
+	}
+	content, err := afero.ReadFile(p.Fs, p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset: %w", err)
+	}
+	return content, nil
+}
+
+func (p *AssetParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	content, err := p.Content()
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, err.Error())
+	}
+	return base64.StdEncoding.EncodeToString(content), nil
+}
+
+// CacheKey identifies assets stored in a file by their real path. Assets held in memory are not cached.
+func (p *AssetParameter) CacheKey() (string, bool) {
+	if p.content != nil || p.Fs == nil {
+		return "", false
+	}
+	if baseFs, ok := p.Fs.(*afero.BasePathFs); ok {
+		realPath, err := baseFs.RealPath(p.Path)
+		if err != nil {
+			return "", false
+		}
+		return realPath, true
+	}
+	return p.Path, true
+}
+
+func parseAssetParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	if context.Fs == nil {
+		return nil, parameter.NewParameterParserError(context, "missing filesystem handle to load parameter")
+	}
+
+	path, ok := context.Value["path"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `path`")
+	}
+
+	return &AssetParameter{Fs: context.Fs, Path: strings.ToString(path)}, nil
+}
+
+func writeAssetParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	assetParam, ok := context.Parameter.(*AssetParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `AssetParameter`")
+	}
+
+	return map[string]interface{}{
+		"path": assetParam.Path,
+	}, nil
+}
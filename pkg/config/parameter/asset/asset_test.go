@@ -0,0 +1,96 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package asset
+
+import (
+	"encoding/base64"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseAssetParameter(t *testing.T) {
+	param, err := parseAssetParameter(parameter.ParameterParserContext{
+		Fs:    afero.NewMemMapFs(),
+		Value: map[string]any{"path": "extension.zip"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "asset", param.GetType())
+	assert.Equal(t, "extension.zip", param.(*AssetParameter).Path)
+}
+
+func TestParseAssetParameter_MissingPath(t *testing.T) {
+	param, err := parseAssetParameter(parameter.ParameterParserContext{Fs: afero.NewMemMapFs()})
+
+	assert.Nil(t, param)
+	assert.IsType(t, parameter.ParameterParserError{}, err)
+}
+
+func TestWriteAssetParameter(t *testing.T) {
+	result, err := writeAssetParameter(parameter.ParameterWriterContext{Parameter: New("extension.zip", []byte{1})})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"path": "extension.zip"}, result)
+}
+
+func TestWriteAssetParameter_WrongType(t *testing.T) {
+	result, err := writeAssetParameter(parameter.ParameterWriterContext{Parameter: envParam.New("env")})
+
+	assert.Nil(t, result)
+	assert.IsType(t, &parameter.ParameterWriterError{}, err)
+}
+
+func TestResolveValue(t *testing.T) {
+	content := []byte{0x50, 0x4b, 0x03, 0x04, 0x00, 0xff, '{', '{'}
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "extension.zip", content, 0644))
+
+	result, err := (&AssetParameter{Fs: fs, Path: "extension.zip"}).ResolveValue(parameter.ResolveContext{})
+
+	require.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(content), result)
+}
+
+func TestResolveValue_InMemory(t *testing.T) {
+	result, err := New("extension.zip", []byte("content")).ResolveValue(parameter.ResolveContext{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Y29udGVudA==", result)
+}
+
+func TestResolveValue_MissingFile(t *testing.T) {
+	_, err := (&AssetParameter{Fs: afero.NewMemMapFs(), Path: "extension.zip"}).ResolveValue(parameter.ResolveContext{})
+
+	assert.IsType(t, parameter.ParameterResolveValueError{}, err)
+}
+
+func TestCacheKey(t *testing.T) {
+	fs := afero.NewBasePathFs(afero.NewMemMapFs(), "project/extension")
+
+	key, ok := (&AssetParameter{Fs: fs, Path: "extension.zip"}).CacheKey()
+	assert.True(t, ok)
+	assert.Equal(t, "project/extension/extension.zip", key)
+
+	_, ok = New("extension.zip", []byte("content")).CacheKey()
+	assert.False(t, ok)
+}
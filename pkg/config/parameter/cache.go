@@ -0,0 +1,85 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parameter
+
+import (
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheableParameter is implemented by parameters whose value does not depend on the config, group or environment it
+// is resolved for. Their values are resolved only once per deployment by a [ValueCache].
+type CacheableParameter interface {
+	Parameter
+
+	// CacheKey identifies the value of the parameter among all parameters of the same type. Parameters of the same type
+	// with equal keys resolve to the same value. If cacheable is false, the parameter is resolved every time.
+	CacheKey() (key string, cacheable bool)
+}
+
+// ValueCacheProvider is implemented by entity lookups that provide a [ValueCache] shared by all configs of a deployment
+type ValueCacheProvider interface {
+	ParameterValueCache() *ValueCache
+}
+
+// ValueCache memoizes the resolved values of [CacheableParameter]s by their type and cache key. Concurrent resolutions of
+// the same value wait for the first one to finish. Errors are not cached, as they refer to the config the parameter was
+// resolved for.
+type ValueCache struct {
+	mutex  sync.Mutex
+	values map[string]any
+	group  singleflight.Group
+}
+
+// NewValueCache returns an empty [ValueCache]
+func NewValueCache() *ValueCache {
+	return &ValueCache{values: make(map[string]any)}
+}
+
+// Resolve returns the cached value of the given parameter, resolving it with the given context if it is not cached
+// yet. A nil cache resolves every parameter.
+func (c *ValueCache) Resolve(p CacheableParameter, context ResolveContext) (any, error) {
+	key, cacheable := p.CacheKey()
+	if c == nil || !cacheable {
+		return p.ResolveValue(context)
+	}
+	key = p.GetType() + ":" + key
+
+	c.mutex.Lock()
+	v, found := c.values[key]
+	c.mutex.Unlock()
+	if found {
+		return v, nil
+	}
+
+	v, err, shared := c.group.Do(key, func() (any, error) {
+		v, err := p.ResolveValue(context)
+		if err != nil {
+			return nil, err
+		}
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		c.values[key] = v
+		return v, nil
+	})
+	if err != nil && shared {
+		// the error may refer to another config waiting for the same value
+		return p.ResolveValue(context)
+	}
+	return v, err
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parameter
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingParameter counts how often it is resolved
+type countingParameter struct {
+	DummyParameter
+	key       string
+	cacheable bool
+	resolved  atomic.Int32
+}
+
+func (p *countingParameter) ResolveValue(context ResolveContext) (interface{}, error) {
+	p.resolved.Add(1)
+	return p.DummyParameter.ResolveValue(context)
+}
+
+func (p *countingParameter) CacheKey() (string, bool) {
+	return p.key, p.cacheable
+}
+
+func TestValueCache_ResolvesEachKeyOnce(t *testing.T) {
+	cache := NewValueCache()
+	p := &countingParameter{DummyParameter: DummyParameter{Value: "value"}, key: "key", cacheable: true}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.Resolve(p, ResolveContext{})
+			assert.NoError(t, err)
+			assert.Equal(t, "value", v)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), p.resolved.Load())
+
+	other := &countingParameter{DummyParameter: DummyParameter{Value: "other"}, key: "other-key", cacheable: true}
+	v, err := cache.Resolve(other, ResolveContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, "other", v)
+	assert.Equal(t, int32(1), other.resolved.Load())
+}
+
+func TestValueCache_DoesNotCacheUncacheableParameters(t *testing.T) {
+	cache := NewValueCache()
+	p := &countingParameter{DummyParameter: DummyParameter{Value: "value"}, key: "key", cacheable: false}
+
+	_, _ = cache.Resolve(p, ResolveContext{})
+	_, _ = cache.Resolve(p, ResolveContext{})
+
+	assert.Equal(t, int32(2), p.resolved.Load())
+}
+
+func TestValueCache_DoesNotCacheErrors(t *testing.T) {
+	cache := NewValueCache()
+	p := &countingParameter{DummyParameter: DummyParameter{Err: errors.New("failed")}, key: "key", cacheable: true}
+
+	_, err := cache.Resolve(p, ResolveContext{})
+	assert.Error(t, err)
+
+	p.Err = nil
+	p.Value = "value"
+	v, err := cache.Resolve(p, ResolveContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, "value", v)
+	assert.Equal(t, int32(2), p.resolved.Load())
+}
+
+func TestValueCache_NilCacheResolvesEveryTime(t *testing.T) {
+	var cache *ValueCache
+	p := &countingParameter{DummyParameter: DummyParameter{Value: "value"}, key: "key", cacheable: true}
+
+	_, _ = cache.Resolve(p, ResolveContext{})
+	_, _ = cache.Resolve(p, ResolveContext{})
+
+	assert.Equal(t, int32(2), p.resolved.Load())
+}
@@ -23,7 +23,6 @@ import (
 	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
-	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 )
 
 // CompoundParameterType specifies the type of the parameter used in config files
@@ -35,24 +34,34 @@ var CompoundParameterSerde = parameter.ParameterSerDe{
 }
 
 type CompoundParameter struct {
-	format               *templ.Template
+	// name of the format template. The template itself is parsed again when resolving the value, as templates
+	// holding functions cannot be compared.
+	name                 string
 	rawFormatString      string
 	referencedParameters []parameter.ParameterReference
 }
 
 func New(name string, format string, referencedParameters []parameter.ParameterReference) (*CompoundParameter, error) {
-	formatTempl, err := template.ParseTemplate(name, format)
-	if err != nil {
+	if _, err := parseFormat(name, format); err != nil {
 		return &CompoundParameter{}, err
 	}
 
 	return &CompoundParameter{
-		format:               formatTempl,
+		name:                 name,
 		rawFormatString:      format,
 		referencedParameters: referencedParameters,
 	}, nil
 }
 
+// parseFormat parses the given format into a template supporting all [Functions]
+func parseFormat(name string, format string) (*templ.Template, error) {
+	t, err := templ.New(name).Option("missingkey=error").Funcs(funcMap()).Parse(format)
+	if err != nil {
+		return nil, withFunctionsHint(err)
+	}
+	return t, nil
+}
+
 // this forces the compiler to check if CompoundParameter is of type Parameter
 var _ parameter.Parameter = (*CompoundParameter)(nil)
 
@@ -71,8 +80,13 @@ func (p *CompoundParameter) ResolveValue(context parameter.ResolveContext) (inte
 		compoundData[param.Property] = context.ResolvedParameterValues[param.Property]
 	}
 
+	format, err := parseFormat(p.name, p.rawFormatString)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving compound value: %w", err)
+	}
+
 	out := bytes.Buffer{}
-	err := p.format.Execute(&out, compoundData)
+	err = format.Execute(&out, compoundData)
 
 	if err != nil {
 		return nil, fmt.Errorf("error resolving compound value: %w", err)
@@ -92,6 +106,7 @@ func (p *CompoundParameter) Equal(o *CompoundParameter) bool {
 // This requires a string `format` and a slice of strings `references`, where `format`
 // is a template string and `references` are all the used references in `format` refering
 // to other parameters within the config.
+// Besides the builtin functions of Go templates, `format` may use the [Functions] of this package.
 func parseCompoundParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
 	format, ok := context.Value["format"]
 	if !ok {
@@ -113,7 +128,11 @@ func parseCompoundParameter(context parameter.ParameterParserContext) (parameter
 		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("invalid parameter references: %v", err))
 	}
 
-	return New(context.ParameterName, strings.ToString(format), referencedParameters)
+	p, err := New(context.ParameterName, strings.ToString(format), referencedParameters)
+	if err != nil {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("invalid format: %v", err))
+	}
+	return p, nil
 }
 
 func writeCompoundParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
@@ -224,3 +224,54 @@ func TestWriteCompoundParameterErrorOnMissingReferences(t *testing.T) {
 	_, err = writeCompoundParameter(context)
 	require.Error(t, err, "expected an error writing missing references")
 }
+
+func TestResolveValueWithFunctions(t *testing.T) {
+	context := parameter.ResolveContext{
+		ResolvedParameterValues: parameter.Properties{
+			"env":   "prod",
+			"app":   "Checkout-Service",
+			"empty": "",
+			"tags":  []interface{}{"a", "b", "c"},
+			"owner": map[string]interface{}{"team": "checkout"},
+		},
+	}
+	refs := []parameter.ParameterReference{{Property: "env"}, {Property: "app"}, {Property: "empty"}, {Property: "tags"}, {Property: "owner"}}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{`{{ .env }}-{{ .app | lower }}`, "prod-checkout-service"},
+		{`{{ upper .env }}`, "PROD"},
+		{`{{ .app | replace "-" "_" }}`, "Checkout_Service"},
+		{`{{ .empty | default "fallback" }}/{{ .env | default "fallback" }}`, "fallback/prod"},
+		{`{{ join ", " .tags }}`, "a, b, c"},
+		{`{{ toJson .owner }}`, `{\"team\":\"checkout\"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			compoundParameter, err := New("testName", tt.format, refs)
+			require.NoError(t, err)
+
+			result, err := compoundParameter.ResolveValue(context)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, strings.ToString(result))
+		})
+	}
+}
+
+func TestParseCompoundParameterErrorOnUnknownFunction(t *testing.T) {
+	_, err := parseCompoundParameter(parameter.ParameterParserContext{
+		ParameterName: "name",
+		Value: map[string]interface{}{
+			"format":     "{{ .app | title }}",
+			"references": []interface{}{"app"},
+		},
+	})
+
+	assert.ErrorContains(t, err, `invalid format: template: name:1: function "title" not defined - available functions are 'default <default> <value>', 'join <separator> <list>'`)
+}
+
+func TestFunctionNames(t *testing.T) {
+	assert.Equal(t, []string{"default", "join", "lower", "replace", "toJson", "upper"}, FunctionNames())
+}
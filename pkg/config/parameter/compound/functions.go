@@ -0,0 +1,145 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compound
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+
+	stringutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"golang.org/x/exp/maps"
+)
+
+// Function describes a function available in the format of compound parameters
+type Function struct {
+	// Usage shows how the function is called
+	Usage string
+	// Description explains what the function does
+	Description string
+
+	impl any
+}
+
+// Functions are the functions available in the format of compound parameters, in addition to the builtin functions
+// of Go templates. Their names and arguments follow the ones of the Sprig library, e.g. '{{ .app | lower }}'.
+var Functions = map[string]Function{
+	"upper": {
+		Usage:       "upper <string>",
+		Description: "converts the string to upper case",
+		impl:        func(s any) string { return strings.ToUpper(stringutils.ToString(s)) },
+	},
+	"lower": {
+		Usage:       "lower <string>",
+		Description: "converts the string to lower case",
+		impl:        func(s any) string { return strings.ToLower(stringutils.ToString(s)) },
+	},
+	"replace": {
+		Usage:       "replace <old> <new> <string>",
+		Description: "replaces all occurrences of old in the string by new",
+		impl: func(old, replacement string, s any) string {
+			return strings.ReplaceAll(stringutils.ToString(s), old, replacement)
+		},
+	},
+	"default": {
+		Usage:       "default <default> <value>",
+		Description: "returns the value, or the default if the value is empty",
+		impl: func(def any, v any) any {
+			if isEmpty(v) {
+				return def
+			}
+			return v
+		},
+	},
+	"join": {
+		Usage:       "join <separator> <list>",
+		Description: "joins the elements of the list with the separator",
+		impl:        join,
+	},
+	"toJson": {
+		Usage:       "toJson <value>",
+		Description: "encodes the value as JSON",
+		impl: func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	},
+}
+
+// FunctionNames returns the sorted names of all [Functions]
+func FunctionNames() []string {
+	names := maps.Keys(Functions)
+	slices.Sort(names)
+	return names
+}
+
+func funcMap() templ.FuncMap {
+	m := make(templ.FuncMap, len(Functions))
+	for name, f := range Functions {
+		m[name] = f.impl
+	}
+	return m
+}
+
+func join(sep string, list any) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return stringutils.ToString(list)
+	}
+	elems := make([]string, v.Len())
+	for i := range elems {
+		elems[i] = stringutils.ToString(v.Index(i).Interface())
+	}
+	return strings.Join(elems, sep)
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return r.Len() == 0
+	case reflect.Bool:
+		return !r.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return r.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return r.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return r.Float() == 0
+	case reflect.Pointer, reflect.Interface:
+		return r.IsNil()
+	}
+	return false
+}
+
+// withFunctionsHint lists the available functions in errors of formats using unknown functions
+func withFunctionsHint(err error) error {
+	if !strings.Contains(err.Error(), "function") || !strings.Contains(err.Error(), "not defined") {
+		return err
+	}
+	usages := make([]string, 0, len(Functions))
+	for _, name := range FunctionNames() {
+		usages = append(usages, fmt.Sprintf("'%s'", Functions[name].Usage))
+	}
+	return fmt.Errorf("%w - available functions are %s", err, strings.Join(usages, ", "))
+}
@@ -0,0 +1,143 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package constraint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+)
+
+const (
+	patternField = "pattern"
+	enumField    = "enum"
+	minField     = "min"
+	maxField     = "max"
+)
+
+// Constraints restrict the values a parameter may resolve to. They are declared next to the type of a parameter in the
+// config.yaml, e.g.
+//
+//	threshold:
+//	  type: environment
+//	  name: THRESHOLD
+//	  min: 0
+//	  max: 100
+type Constraints struct {
+	// Pattern is a regular expression the whole value has to match
+	Pattern string
+
+	// Enum lists the allowed values
+	Enum []string
+
+	// Min and Max are the inclusive bounds of numeric values
+	Min *float64
+	Max *float64
+}
+
+// Parse reads the Constraints declared in the definition of a parameter. It returns false if there are none.
+func Parse(definition map[string]any) (Constraints, bool, error) {
+	var c Constraints
+	found := false
+
+	if v, ok := definition[patternField]; ok {
+		found = true
+		c.Pattern = strings.ToString(v)
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return Constraints{}, false, fmt.Errorf("invalid `%s`: %w", patternField, err)
+		}
+	}
+
+	if v, ok := definition[enumField]; ok {
+		found = true
+		values, isList := v.([]any)
+		if !isList || len(values) == 0 {
+			return Constraints{}, false, fmt.Errorf("`%s` must be a non-empty list of allowed values", enumField)
+		}
+		for _, value := range values {
+			c.Enum = append(c.Enum, strings.ToString(value))
+		}
+	}
+
+	for field, bound := range map[string]**float64{minField: &c.Min, maxField: &c.Max} {
+		v, ok := definition[field]
+		if !ok {
+			continue
+		}
+		found = true
+		f, err := toNumber(v)
+		if err != nil {
+			return Constraints{}, false, fmt.Errorf("`%s` must be a number, but is %q", field, strings.ToString(v))
+		}
+		*bound = &f
+	}
+
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return Constraints{}, false, fmt.Errorf("`%s` (%v) must not be greater than `%s` (%v)", minField, *c.Min, maxField, *c.Max)
+	}
+
+	return c, found, nil
+}
+
+// Check returns an error if the given resolved value violates any of the constraints
+func (c Constraints) Check(value any) error {
+	s := strings.ToString(value)
+
+	if c.Pattern != "" {
+		// the pattern is validated when it is parsed, and has to match the whole value
+		if !regexp.MustCompile("^(?:" + c.Pattern + ")$").MatchString(s) {
+			return fmt.Errorf("value %q does not match pattern %q", s, c.Pattern)
+		}
+	}
+
+	if len(c.Enum) > 0 && !slices.Contains(c.Enum, s) {
+		return fmt.Errorf("value %q is not one of the allowed values %q", s, c.Enum)
+	}
+
+	if c.Min != nil || c.Max != nil {
+		f, err := toNumber(value)
+		if err != nil {
+			return fmt.Errorf("value %q is not a number", s)
+		}
+		if c.Min != nil && f < *c.Min {
+			return fmt.Errorf("value %v is less than the minimum %v", f, *c.Min)
+		}
+		if c.Max != nil && f > *c.Max {
+			return fmt.Errorf("value %v is greater than the maximum %v", f, *c.Max)
+		}
+	}
+
+	return nil
+}
+
+func toNumber(v any) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	default:
+		return strconv.ParseFloat(strings.ToString(v), 64)
+	}
+}
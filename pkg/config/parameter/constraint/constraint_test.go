@@ -0,0 +1,99 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package constraint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+func TestParse(t *testing.T) {
+	c, found, err := Parse(map[string]any{
+		"type":    "environment",
+		"name":    "VAR",
+		"pattern": "[a-z]+",
+		"enum":    []any{"a", 1},
+		"min":     0,
+		"max":     "10.5",
+	})
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Constraints{Pattern: "[a-z]+", Enum: []string{"a", "1"}, Min: ptr(0), Max: ptr(10.5)}, c)
+
+	_, found, err = Parse(map[string]any{"type": "value", "value": "x"})
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		definition    map[string]any
+		expectedError string
+	}{
+		{"invalid pattern", map[string]any{"pattern": "[a-z"}, "invalid `pattern`"},
+		{"enum is no list", map[string]any{"enum": "a"}, "`enum` must be a non-empty list"},
+		{"empty enum", map[string]any{"enum": []any{}}, "`enum` must be a non-empty list"},
+		{"min is no number", map[string]any{"min": "low"}, "`min` must be a number, but is \"low\""},
+		{"min greater than max", map[string]any{"min": 5, "max": 1}, "`min` (5) must not be greater than `max` (1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(tt.definition)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		constraints   Constraints
+		value         any
+		expectedError string
+	}{
+		{"pattern matches", Constraints{Pattern: "[a-z]+"}, "abc", ""},
+		{"pattern has to match the whole value", Constraints{Pattern: "[a-z]+"}, "abc1", `value "abc1" does not match pattern "[a-z]+"`},
+		{"alternatives in pattern", Constraints{Pattern: "a|b"}, "ab", `does not match pattern`},
+		{"enum contains value", Constraints{Enum: []string{"ERROR", "WARNING"}}, "ERROR", ""},
+		{"enum contains number", Constraints{Enum: []string{"1", "2"}}, 2, ""},
+		{"enum does not contain value", Constraints{Enum: []string{"ERROR"}}, "INFO", `value "INFO" is not one of the allowed values ["ERROR"]`},
+		{"in range", Constraints{Min: ptr(0), Max: ptr(10)}, 10, ""},
+		{"string in range", Constraints{Min: ptr(0), Max: ptr(10)}, "2.5", ""},
+		{"less than min", Constraints{Min: ptr(0)}, -1, "value -1 is less than the minimum 0"},
+		{"greater than max", Constraints{Max: ptr(10)}, 10.5, "value 10.5 is greater than the maximum 10"},
+		{"not a number", Constraints{Max: ptr(10)}, "ten", `value "ten" is not a number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.constraints.Check(tt.value)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crossenvironment
+
+import (
+	"fmt"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+// CrossEnvironmentReferenceParameterType specifies the type of the parameter used in config files
+const CrossEnvironmentReferenceParameterType = "crossEnvironmentReference"
+
+var CrossEnvironmentReferenceParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeCrossEnvironmentReferenceParameter,
+	Deserializer: parseCrossEnvironmentReferenceParameter,
+}
+
+// CrossEnvironmentReferenceParameter is a parameter which evaluates to the value of a property of a config deployed to
+// another environment of the same deployment - e.g. a production dashboard linking to the ID of a staging SLO.
+//
+// The referenced environment has to be deployed in the same run. It is deployed before the environment of the config
+// holding this parameter.
+type CrossEnvironmentReferenceParameter struct {
+	// Environment is the name of the environment the referenced config is deployed to
+	Environment string
+
+	parameter.ParameterReference
+}
+
+func New(environment string, project string, configType string, config string, property string) *CrossEnvironmentReferenceParameter {
+	return &CrossEnvironmentReferenceParameter{
+		Environment: environment,
+		ParameterReference: parameter.ParameterReference{
+			Config:   coordinate.Coordinate{Project: project, Type: configType, ConfigId: config},
+			Property: property,
+		},
+	}
+}
+
+// this forces the compiler to check if CrossEnvironmentReferenceParameter is of type Parameter
+var _ parameter.Parameter = (*CrossEnvironmentReferenceParameter)(nil)
+
+func (p *CrossEnvironmentReferenceParameter) GetType() string {
+	return CrossEnvironmentReferenceParameterType
+}
+
+func (p *CrossEnvironmentReferenceParameter) GetReferences() []parameter.ParameterReference {
+	// references to other environments are no dependencies within the environment of the config. The order of
+	// environments is ensured by the deployment.
+	return []parameter.ParameterReference{}
+}
+
+func (p *CrossEnvironmentReferenceParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.EnvironmentResolver == nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("cannot resolve reference %s of environment %q: references to other environments can only be resolved during a deployment", p.ParameterReference, p.Environment))
+	}
+
+	val, err := context.EnvironmentResolver.GetResolvedPropertyOfEnvironment(p.Environment, p.Config, p.Property)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("cannot resolve reference %s of environment %q: %s", p.ParameterReference, p.Environment, err))
+	}
+	return val, nil
+}
+
+const environmentField = "environment"
+const projectField = "project"
+const typeField = "configType"
+const idField = "configId"
+const propertyField = "property"
+
+func writeCrossEnvironmentReferenceParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	refParam, ok := context.Parameter.(*CrossEnvironmentReferenceParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `CrossEnvironmentReferenceParameter`")
+	}
+
+	result := make(map[string]interface{})
+	sameProject := context.Coordinate.Project == refParam.Config.Project
+	sameType := context.Coordinate.Type == refParam.Config.Type
+	sameConfig := context.Coordinate.ConfigId == refParam.Config.ConfigId
+
+	result[environmentField] = refParam.Environment
+
+	if !sameProject {
+		result[projectField] = refParam.Config.Project
+	}
+
+	if !sameProject || !sameType {
+		result[typeField] = refParam.Config.Type
+	}
+
+	if !sameProject || !sameType || !sameConfig {
+		result[idField] = refParam.Config.ConfigId
+	}
+
+	result[propertyField] = refParam.Property
+
+	return result, nil
+}
+
+// parseCrossEnvironmentReferenceParameter tries to parse a CrossEnvironmentReferenceParameter from a given context.
+// it requires an `environment` and a `property` config value. Like for reference parameters, all other values
+// (project, type, config) will be filled in from the current context if missing - referencing the same config
+// deployed to the other environment.
+func parseCrossEnvironmentReferenceParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	var environment string
+	project := context.Coordinate.Project
+	configType := context.Coordinate.Type
+	config := context.Coordinate.ConfigId
+	var property string
+	projectSet := false
+	typeSet := false
+	configSet := false
+
+	if val, ok := context.Value[environmentField]; ok {
+		environment = strings.ToString(val)
+	} else {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("missing `%s` - please specify the environment of the referenced config", environmentField))
+	}
+
+	if environment == context.Environment {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("`%s` is the environment of the config itself - please use a `reference` parameter instead", environmentField))
+	}
+
+	if val, ok := context.Value[projectField]; ok {
+		projectSet = true
+		project = strings.ToString(val)
+	}
+
+	if val, ok := context.Value[typeField]; ok {
+		typeSet = true
+		configType = strings.ToString(val)
+	}
+
+	if val, ok := context.Value[idField]; ok {
+		configSet = true
+		config = strings.ToString(val)
+	}
+
+	if val, ok := context.Value[propertyField]; ok {
+		property = strings.ToString(val)
+	} else {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("missing `%s` - please specify which %s should be referenced", propertyField, propertyField))
+	}
+
+	// ensure that we do not have "holes" in the reference definition
+	if projectSet && (!typeSet || !configSet) {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("`%s` is set, but either `%s` or `%s` isn't! please specify `%s` and `%s`", projectField, typeField, idField, typeField, idField))
+	}
+
+	if typeSet && !configSet {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("`%s` is set, but `%s` isn't! please specify `%s`", typeField, idField, idField))
+	}
+
+	return New(environment, project, configType, config, property), nil
+}
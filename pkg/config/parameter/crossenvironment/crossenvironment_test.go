@@ -0,0 +1,130 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crossenvironment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var currentConfig = coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"}
+
+func TestParseCrossEnvironmentReferenceParameter(t *testing.T) {
+	param, err := parseCrossEnvironmentReferenceParameter(parameter.ParameterParserContext{
+		Coordinate:  currentConfig,
+		Environment: "prod",
+		Value: map[string]interface{}{
+			"environment": "staging",
+			"configType":  "slo-v2",
+			"configId":    "availability",
+			"property":    "id",
+		},
+	})
+	require.NoError(t, err)
+
+	ref, ok := param.(*CrossEnvironmentReferenceParameter)
+	require.True(t, ok, "parsed parameter should be cross environment reference parameter")
+	assert.Equal(t, "crossEnvironmentReference", ref.GetType())
+	assert.Empty(t, ref.GetReferences(), "references to other environments must not be dependencies within the environment")
+	assert.Equal(t, New("staging", "project", "slo-v2", "availability", "id"), ref)
+}
+
+func TestParseCrossEnvironmentReferenceParameter_DefaultsToSameConfig(t *testing.T) {
+	param, err := parseCrossEnvironmentReferenceParameter(parameter.ParameterParserContext{
+		Coordinate:  currentConfig,
+		Environment: "prod",
+		Value:       map[string]interface{}{"environment": "staging", "property": "id"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, New("staging", "project", "dashboard", "dashboard", "id"), param)
+}
+
+func TestParseCrossEnvironmentReferenceParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{"missing environment", map[string]interface{}{"property": "id"}, "missing `environment`"},
+		{"same environment", map[string]interface{}{"environment": "prod", "property": "id"}, "please use a `reference` parameter instead"},
+		{"missing property", map[string]interface{}{"environment": "staging"}, "missing `property`"},
+		{"project without config", map[string]interface{}{"environment": "staging", "project": "other", "property": "id"}, "`project` is set, but either `configType` or `configId` isn't"},
+		{"type without config", map[string]interface{}{"environment": "staging", "configType": "slo-v2", "property": "id"}, "`configType` is set, but `configId` isn't"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCrossEnvironmentReferenceParameter(parameter.ParameterParserContext{
+				Coordinate:  currentConfig,
+				Environment: "prod",
+				Value:       tt.value,
+			})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestWriteCrossEnvironmentReferenceParameter(t *testing.T) {
+	result, err := writeCrossEnvironmentReferenceParameter(parameter.ParameterWriterContext{
+		Coordinate: currentConfig,
+		Parameter:  New("staging", "project", "slo-v2", "availability", "id"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"environment": "staging",
+		"configType":  "slo-v2",
+		"configId":    "availability",
+		"property":    "id",
+	}, result)
+
+	result, err = writeCrossEnvironmentReferenceParameter(parameter.ParameterWriterContext{
+		Coordinate: currentConfig,
+		Parameter:  New("staging", "project", "dashboard", "dashboard", "id"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"environment": "staging", "property": "id"}, result)
+}
+
+type environmentResolver map[string]map[coordinate.Coordinate]map[string]any
+
+func (r environmentResolver) GetResolvedPropertyOfEnvironment(environment string, c coordinate.Coordinate, propertyName string) (any, error) {
+	if v, found := r[environment][c][propertyName]; found {
+		return v, nil
+	}
+	return nil, errors.New("config has not been deployed")
+}
+
+func TestResolveValue(t *testing.T) {
+	slo := coordinate.Coordinate{Project: "project", Type: "slo-v2", ConfigId: "availability"}
+	resolver := environmentResolver{"staging": {slo: {"id": "slo-id"}}}
+
+	v, err := New("staging", "project", "slo-v2", "availability", "id").ResolveValue(parameter.ResolveContext{EnvironmentResolver: resolver})
+	require.NoError(t, err)
+	assert.Equal(t, "slo-id", v)
+
+	_, err = New("staging", "project", "slo-v2", "other", "id").ResolveValue(parameter.ResolveContext{EnvironmentResolver: resolver})
+	assert.ErrorContains(t, err, `of environment "staging": config has not been deployed`)
+
+	_, err = New("staging", "project", "slo-v2", "availability", "id").ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "references to other environments can only be resolved during a deployment")
+}
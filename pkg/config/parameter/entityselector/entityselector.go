@@ -0,0 +1,338 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entityselector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	stringutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"golang.org/x/sync/singleflight"
+)
+
+// EntitySelectorParameterType specifies the type of the parameter used in config files
+const EntitySelectorParameterType = "entitySelector"
+
+var EntitySelectorParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeEntitySelectorParameter,
+	Deserializer: parseEntitySelectorParameter,
+}
+
+// Cardinality defines how many entities an entity selector is expected to match
+type Cardinality string
+
+const (
+	// One requires exactly one matching entity, and resolves to its ID
+	One Cardinality = "one"
+	// AtLeastOne requires one or more matching entities, and resolves to a list of their IDs
+	AtLeastOne Cardinality = "atLeastOne"
+	// Any allows any number of matching entities, including none, and resolves to a list of their IDs
+	Any Cardinality = "any"
+)
+
+// entitiesPath is the path of the Monitored Entities API v2
+const entitiesPath = "/api/v2/entities"
+
+// requestTimeout limits the duration of a single entities request
+const requestTimeout = 30 * time.Second
+
+// maxErrorBodyLength limits how much of the body of a failed response is reported
+const maxErrorBodyLength = 500
+
+// EntitySelectorParameter defines a parameter which resolves to the IDs of the entities matching an entity selector
+// (e.g. `type(HOST),tag(team:checkout)`) at resolve time. The entities are queried with the Monitored Entities API v2
+// of the environment the config is deployed to.
+//
+// With the cardinality One the parameter resolves to a single entity ID, otherwise to a list of entity IDs in the
+// format of list parameters.
+type EntitySelectorParameter struct {
+	// Selector is the entity selector to query
+	Selector string
+
+	// Cardinality is the expected number of matching entities. Defaults to One.
+	Cardinality Cardinality
+
+	// From optionally overrides the start of the timeframe entities are queried for, e.g. `now-7d`
+	From string
+
+	// environmentURL and environmentToken are used to query the environment's API
+	environmentURL   string
+	environmentToken string
+}
+
+func New(selector string, cardinality Cardinality, from string) (*EntitySelectorParameter, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, fmt.Errorf("entity selector must not be empty")
+	}
+
+	if cardinality == "" {
+		cardinality = One
+	}
+	if cardinality != One && cardinality != AtLeastOne && cardinality != Any {
+		return nil, fmt.Errorf("invalid cardinality %q - expected one of %q, %q or %q", cardinality, One, AtLeastOne, Any)
+	}
+
+	return &EntitySelectorParameter{
+		Selector:    selector,
+		Cardinality: cardinality,
+		From:        from,
+	}, nil
+}
+
+// this forces the compiler to check if EntitySelectorParameter is of type Parameter
+var _ parameter.Parameter = (*EntitySelectorParameter)(nil)
+
+func (p *EntitySelectorParameter) GetType() string {
+	return EntitySelectorParameterType
+}
+
+func (p *EntitySelectorParameter) GetReferences() []parameter.ParameterReference {
+	// entity selector parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *EntitySelectorParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if p.environmentURL == "" {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("cannot query entities matching %q: the environment has no URL", p.Selector))
+	}
+	if p.environmentToken == "" {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("cannot query entities matching %q: the environment has no API token", p.Selector))
+	}
+
+	ids, err := defaultCache.get(query{
+		environmentURL:   strings.TrimSuffix(p.environmentURL, "/"),
+		environmentToken: p.environmentToken,
+		selector:         p.Selector,
+		from:             p.From,
+	})
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to query entities matching %q: %s", p.Selector, err))
+	}
+
+	switch {
+	case p.Cardinality == One && len(ids) != 1:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("entity selector %q matches %d entities, but must match exactly one%s", p.Selector, len(ids), summarize(ids)))
+	case p.Cardinality == AtLeastOne && len(ids) == 0:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("entity selector %q matches no entities, but must match at least one", p.Selector))
+	}
+
+	log.Debug("Entity selector %q of parameter %q matches %d entities", p.Selector, context.ParameterName, len(ids))
+
+	if p.Cardinality == One {
+		return template.EscapeSpecialCharactersInValue(ids[0], template.FullStringEscapeFunction)
+	}
+
+	escaped := make([]string, len(ids))
+	for i, id := range ids {
+		e, err := template.EscapeSpecialCharactersInValue(id, template.FullStringEscapeFunction)
+		if err != nil {
+			return nil, parameter.NewParameterResolveValueError(context, err.Error())
+		}
+		escaped[i] = fmt.Sprintf("%q", e)
+	}
+	return fmt.Sprintf("[ %s ]", strings.Join(escaped, ",")), nil
+}
+
+// summarize lists the first few IDs of several matching entities, to help refine the selector
+func summarize(ids []string) string {
+	const maxIDs = 5
+	switch {
+	case len(ids) < 2:
+		return ""
+	case len(ids) > maxIDs:
+		return fmt.Sprintf(" (%s, ...)", strings.Join(ids[:maxIDs], ", "))
+	default:
+		return fmt.Sprintf(" (%s)", strings.Join(ids, ", "))
+	}
+}
+
+// query identifies an entities query against an environment
+type query struct {
+	environmentURL   string
+	environmentToken string
+	selector         string
+	from             string
+}
+
+// entitiesResponse is the part of a response of the Monitored Entities API v2 needed to collect entity IDs
+type entitiesResponse struct {
+	NextPageKey string `json:"nextPageKey"`
+	Entities    []struct {
+		EntityID string `json:"entityId"`
+	} `json:"entities"`
+}
+
+// resultCache caches the entity IDs of queries, so that each distinct query is only sent once, no matter how many
+// configs use it. Failed queries are cached as well, so that each failure is only caused once.
+type resultCache struct {
+	client   *http.Client
+	mu       sync.Mutex
+	results  map[query]cachedResult
+	inflight singleflight.Group
+}
+
+type cachedResult struct {
+	ids []string
+	err error
+}
+
+var defaultCache = newResultCache(&http.Client{Timeout: requestTimeout})
+
+func newResultCache(client *http.Client) *resultCache {
+	return &resultCache{client: client, results: make(map[query]cachedResult)}
+}
+
+func (c *resultCache) get(q query) ([]string, error) {
+	c.mu.Lock()
+	cached, found := c.results[q]
+	c.mu.Unlock()
+	if found {
+		return cached.ids, cached.err
+	}
+
+	key := fmt.Sprintf("%s\n%s\n%s\n%s", q.environmentURL, q.environmentToken, q.selector, q.from)
+	ids, err, _ := c.inflight.Do(key, func() (any, error) {
+		ids, err := c.queryAllPages(q)
+
+		c.mu.Lock()
+		c.results[q] = cachedResult{ids: ids, err: err}
+		c.mu.Unlock()
+		return ids, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids.([]string), nil
+}
+
+// queryAllPages collects the IDs of all entities matching the query, following the pages of the response
+func (c *resultCache) queryAllPages(q query) ([]string, error) {
+	params := url.Values{}
+	params.Set("entitySelector", q.selector)
+	params.Set("fields", "entityId")
+	if q.from != "" {
+		params.Set("from", q.from)
+	}
+
+	ids := make([]string, 0)
+	for {
+		resp, err := c.send(q, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Entities {
+			ids = append(ids, e.EntityID)
+		}
+		if resp.NextPageKey == "" {
+			return ids, nil
+		}
+		// follow-up pages must only be requested with the page key
+		params = url.Values{}
+		params.Set("nextPageKey", resp.NextPageKey)
+	}
+}
+
+func (c *resultCache) send(q query, params url.Values) (entitiesResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, q.environmentURL+entitiesPath+"?"+params.Encode(), nil)
+	if err != nil {
+		return entitiesResponse{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Api-Token "+q.environmentToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return entitiesResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return entitiesResponse{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > maxErrorBodyLength {
+			msg = msg[:maxErrorBodyLength] + "..."
+		}
+		return entitiesResponse{}, fmt.Errorf("HTTP status %d: %s", resp.StatusCode, msg)
+	}
+
+	var result entitiesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return entitiesResponse{}, fmt.Errorf("response is no valid JSON: %w", err)
+	}
+	return result, nil
+}
+
+// parseEntitySelectorParameter parses an EntitySelectorParameter from a given context.
+// it requires the `selector` field to be set. `cardinality` and `from` are optional fields.
+func parseEntitySelectorParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	selector, ok := context.Value["selector"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `selector`")
+	}
+
+	var cardinality Cardinality
+	if c, ok := context.Value["cardinality"]; ok {
+		cardinality = Cardinality(stringutils.ToString(c))
+	}
+
+	var from string
+	if f, ok := context.Value["from"]; ok {
+		from = stringutils.ToString(f)
+	}
+
+	p, err := New(stringutils.ToString(selector), cardinality, from)
+	if err != nil {
+		return nil, parameter.NewParameterParserError(context, err.Error())
+	}
+	p.environmentURL = context.EnvironmentURL
+	p.environmentToken = context.EnvironmentToken
+	return p, nil
+}
+
+func writeEntitySelectorParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	selectorParam, ok := context.Parameter.(*EntitySelectorParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `EntitySelectorParameter`")
+	}
+
+	result := map[string]interface{}{
+		"selector": selectorParam.Selector,
+	}
+
+	if selectorParam.Cardinality != "" && selectorParam.Cardinality != One {
+		result["cardinality"] = string(selectorParam.Cardinality)
+	}
+
+	if selectorParam.From != "" {
+		result["from"] = selectorParam.From
+	}
+
+	return result, nil
+}
@@ -0,0 +1,194 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entityselector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntitySelectorParameter(t *testing.T) {
+	param, err := parseEntitySelectorParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"selector":    "type(HOST),tag(team:checkout)",
+			"cardinality": "atLeastOne",
+			"from":        "now-7d",
+		},
+		EnvironmentURL:   "https://abc.live.dynatrace.com",
+		EnvironmentToken: "dt0c01.token",
+	})
+	require.NoError(t, err)
+
+	p, ok := param.(*EntitySelectorParameter)
+	require.True(t, ok, "parsed parameter should be entity selector parameter")
+	assert.Equal(t, "entitySelector", p.GetType())
+	assert.Empty(t, p.GetReferences())
+	assert.Equal(t, "type(HOST),tag(team:checkout)", p.Selector)
+	assert.Equal(t, AtLeastOne, p.Cardinality)
+	assert.Equal(t, "now-7d", p.From)
+	assert.Equal(t, "https://abc.live.dynatrace.com", p.environmentURL)
+	assert.Equal(t, "dt0c01.token", p.environmentToken)
+}
+
+func TestParseEntitySelectorParameter_DefaultsToCardinalityOne(t *testing.T) {
+	param, err := parseEntitySelectorParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{"selector": "type(HOST)"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, One, param.(*EntitySelectorParameter).Cardinality)
+}
+
+func TestParseEntitySelectorParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{"missing selector", map[string]interface{}{"cardinality": "one"}, "missing property `selector`"},
+		{"empty selector", map[string]interface{}{"selector": " "}, "entity selector must not be empty"},
+		{"invalid cardinality", map[string]interface{}{"selector": "type(HOST)", "cardinality": "two"}, `invalid cardinality "two"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEntitySelectorParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestWriteEntitySelectorParameter(t *testing.T) {
+	one, err := New("type(HOST)", One, "")
+	require.NoError(t, err)
+	result, err := writeEntitySelectorParameter(parameter.ParameterWriterContext{Parameter: one})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"selector": "type(HOST)"}, result)
+
+	many, err := New("type(HOST)", Any, "now-7d")
+	require.NoError(t, err)
+	many.environmentToken = "dt0c01.token"
+	result, err = writeEntitySelectorParameter(parameter.ParameterWriterContext{Parameter: many})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"selector": "type(HOST)", "cardinality": "any", "from": "now-7d"}, result)
+}
+
+func TestResolveValue(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/api/v2/entities" || r.Header.Get("Authorization") != "Api-Token dt0c01.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Query().Get("nextPageKey") == "page2" {
+			assert.Empty(t, r.URL.Query().Get("entitySelector"), "follow-up pages must only be requested with the page key")
+			_, _ = w.Write([]byte(`{"entities": [{"entityId": "HOST-3"}]}`))
+			return
+		}
+
+		switch r.URL.Query().Get("entitySelector") {
+		case "type(HOST),tag(team:checkout)":
+			_, _ = w.Write([]byte(`{"entities": [{"entityId": "HOST-1"}, {"entityId": "HOST-2"}], "nextPageKey": "page2"}`))
+		case "type(HOST),entityName(web)":
+			assert.Equal(t, "now-7d", r.URL.Query().Get("from"))
+			_, _ = w.Write([]byte(`{"entities": [{"entityId": "HOST-1"}]}`))
+		case "type(HOST),tag(team:none)":
+			_, _ = w.Write([]byte(`{"entities": []}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "invalid entity selector"}}`))
+		}
+	}))
+	defer server.Close()
+
+	newParam := func(t *testing.T, selector string, cardinality Cardinality, from string) *EntitySelectorParameter {
+		p, err := New(selector, cardinality, from)
+		require.NoError(t, err)
+		p.environmentURL = server.URL
+		p.environmentToken = "dt0c01.token"
+		return p
+	}
+	context := parameter.ResolveContext{ParameterName: "hosts"}
+
+	t.Run("single entity", func(t *testing.T) {
+		v, err := newParam(t, "type(HOST),entityName(web)", One, "now-7d").ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, "HOST-1", v)
+	})
+
+	t.Run("several entities across pages", func(t *testing.T) {
+		v, err := newParam(t, "type(HOST),tag(team:checkout)", AtLeastOne, "").ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, `[ "HOST-1","HOST-2","HOST-3" ]`, v)
+	})
+
+	t.Run("no entities", func(t *testing.T) {
+		v, err := newParam(t, "type(HOST),tag(team:none)", Any, "").ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, `[  ]`, v)
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		before := calls.Load()
+		_, err := newParam(t, "type(HOST),tag(team:checkout)", Any, "").ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, before, calls.Load())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			param         *EntitySelectorParameter
+			expectedError string
+		}{
+			{"no entity for one", newParam(t, "type(HOST),tag(team:none)", One, ""), "matches 0 entities, but must match exactly one"},
+			{"several entities for one", newParam(t, "type(HOST),tag(team:checkout)", One, ""), "matches 3 entities, but must match exactly one (HOST-1, HOST-2, HOST-3)"},
+			{"no entity for at least one", newParam(t, "type(HOST),tag(team:none)", AtLeastOne, ""), "matches no entities, but must match at least one"},
+			{"failed request", newParam(t, "invalid(", Any, ""), "HTTP status 400"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.param.ResolveValue(context)
+				assert.ErrorContains(t, err, tt.expectedError)
+			})
+		}
+
+		p := newParam(t, "type(HOST)", One, "")
+		p.environmentToken = ""
+		_, err := p.ResolveValue(context)
+		assert.ErrorContains(t, err, "the environment has no API token")
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	ids := make([]string, 7)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("HOST-%d", i)
+	}
+	assert.Equal(t, "", summarize(ids[:1]))
+	assert.Equal(t, " (HOST-0, HOST-1)", summarize(ids[:2]))
+	assert.Equal(t, " (HOST-0, HOST-1, HOST-2, HOST-3, HOST-4, ...)", summarize(ids))
+}
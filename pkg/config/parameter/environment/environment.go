@@ -33,7 +33,7 @@ var EnvironmentVariableParameterSerde = parameter.ParameterSerDe{
 
 // EnvironmentVariableParameter defines a parameter which can load an value from the
 // environment variables. there is even the possibility to define a default value,
-// if the one from the environment is missing.
+// if the one from the environment is missing, or to declare the variable as optional.
 type EnvironmentVariableParameter struct {
 	// name of the referenced environment variable
 	Name string
@@ -45,6 +45,10 @@ type EnvironmentVariableParameter struct {
 	// default value used if environment variable specified by `name` cannot be found.
 	// note: this value is only used, if the `HasDefaultValue` flag is set to true.
 	DefaultValue string
+
+	// flag indicating that the environment variable does not need to be set. if it is
+	// missing and there is no default value, the parameter resolves to an empty string.
+	Optional bool
 }
 
 func New(name string) *EnvironmentVariableParameter {
@@ -62,8 +66,16 @@ func NewWithDefault(name string, defaultValue string) *EnvironmentVariableParame
 	}
 }
 
-// this forces the compiler to check if EnvironmentVariableParameter is of type Parameter
-var _ parameter.Parameter = (*EnvironmentVariableParameter)(nil)
+// NewOptional creates a parameter for an environment variable which does not need to be set
+func NewOptional(name string) *EnvironmentVariableParameter {
+	return &EnvironmentVariableParameter{
+		Name:     name,
+		Optional: true,
+	}
+}
+
+// this forces the compiler to check if EnvironmentVariableParameter is of type CacheableParameter
+var _ parameter.CacheableParameter = (*EnvironmentVariableParameter)(nil)
 
 func (p *EnvironmentVariableParameter) GetType() string {
 	return EnvironmentVariableParameterType
@@ -74,11 +86,25 @@ func (p *EnvironmentVariableParameter) GetReferences() []parameter.ParameterRefe
 	return []parameter.ParameterReference{}
 }
 
+// CacheKey identifies the parameter by the name of the environment variable and how a missing variable is handled, as
+// all parameters sharing those resolve to the same value
+func (p *EnvironmentVariableParameter) CacheKey() (string, bool) {
+	return fmt.Sprintf("%s|%t|%s|%t", p.Name, p.HasDefaultValue, p.DefaultValue, p.Optional), true
+}
+
+// IsRequired returns whether the environment variable has to be set, as there is neither a default value, nor is it
+// declared optional
+func (p *EnvironmentVariableParameter) IsRequired() bool {
+	return !p.HasDefaultValue && !p.Optional
+}
+
 func (p *EnvironmentVariableParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
 
 	val, found := os.LookupEnv(p.Name)
 	if !found && p.HasDefaultValue {
 		val = p.DefaultValue
+	} else if !found && p.Optional {
+		val = ""
 	} else if !found {
 		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("environment variable `%s` not set", p.Name))
 	}
@@ -87,19 +113,34 @@ func (p *EnvironmentVariableParameter) ResolveValue(context parameter.ResolveCon
 }
 
 // parseEnvironmentValueParameter parses an EnvironmentVariableParameter from a given context.
-// it requires a `name` field to be set. `default` is an optional field.
+// it requires a `name` field to be set. `default` and `required` are optional fields.
+// a variable is required by default, unless it has a default value.
 func parseEnvironmentValueParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
-	if name, ok := context.Value["name"]; ok {
-		defaultValue := ""
+	name, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
 
-		if val, ok := context.Value["default"]; ok {
-			defaultValue = strings.ToString(val)
-			return NewWithDefault(strings.ToString(name), defaultValue), nil
+	required, requiredSet := true, false
+	if val, ok := context.Value["required"]; ok {
+		b, isBool := val.(bool)
+		if !isBool {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("property `required` must be `true` or `false`, but is %q", strings.ToString(val)))
 		}
-		return New(strings.ToString(name)), nil
+		required, requiredSet = b, true
+	}
+
+	if val, ok := context.Value["default"]; ok {
+		if requiredSet && required {
+			return nil, parameter.NewParameterParserError(context, "a required environment variable cannot have a `default` value")
+		}
+		return NewWithDefault(strings.ToString(name), strings.ToString(val)), nil
+	}
 
+	if !required {
+		return NewOptional(strings.ToString(name)), nil
 	}
-	return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	return New(strings.ToString(name)), nil
 }
 
 func writeEnvironmentValueParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
@@ -113,6 +154,8 @@ func writeEnvironmentValueParameter(context parameter.ParameterWriterContext) (m
 
 	if envParam.HasDefaultValue {
 		result["default"] = envParam.DefaultValue
+	} else if envParam.Optional {
+		result["required"] = false
 	}
 
 	result["name"] = envParam.Name
@@ -77,6 +77,44 @@ func TestParseValueParameterMissingRequiredField(t *testing.T) {
 	require.Error(t, err, "error should be present")
 }
 
+func TestParseValueParameterRequired(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    map[string]interface{}
+		expected *EnvironmentVariableParameter
+	}{
+		{"required by default", map[string]interface{}{"name": "VAR"}, New("VAR")},
+		{"explicitly required", map[string]interface{}{"name": "VAR", "required": true}, New("VAR")},
+		{"optional", map[string]interface{}{"name": "VAR", "required": false}, NewOptional("VAR")},
+		{"optional with default", map[string]interface{}{"name": "VAR", "required": false, "default": "x"}, NewWithDefault("VAR", "x")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param, err := parseEnvironmentValueParameter(parameter.ParameterParserContext{Value: tt.value})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, param)
+		})
+	}
+}
+
+func TestParseValueParameterRequiredErrors(t *testing.T) {
+	_, err := parseEnvironmentValueParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{"name": "VAR", "required": true, "default": "x"},
+	})
+	assert.ErrorContains(t, err, "a required environment variable cannot have a `default` value")
+
+	_, err = parseEnvironmentValueParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{"name": "VAR", "required": "yes"},
+	})
+	assert.ErrorContains(t, err, "property `required` must be `true` or `false`")
+}
+
+func TestIsRequired(t *testing.T) {
+	assert.True(t, New("VAR").IsRequired())
+	assert.False(t, NewWithDefault("VAR", "").IsRequired())
+	assert.False(t, NewOptional("VAR").IsRequired())
+}
+
 func TestGetReferences(t *testing.T) {
 	fixture := New("test")
 
@@ -144,6 +182,17 @@ func TestResolveValueErrorOnUnsetEnvVar(t *testing.T) {
 	require.Error(t, err, "expected an error when resolving unset var without default")
 }
 
+func TestResolveValueOptionalUnsetEnvVar(t *testing.T) {
+	name := "__not_set_test"
+
+	result, err := NewOptional(name).ResolveValue(parameter.ResolveContext{
+		ParameterName: name,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "", result)
+}
+
 func TestWriteEnvironmentValueParameter(t *testing.T) {
 	name := "TEST"
 	envParam := New(name)
@@ -185,6 +234,15 @@ func TestWriteEnvironmentValueParameterWithDefault(t *testing.T) {
 	assert.Equal(t, name, resultEnv)
 }
 
+func TestWriteEnvironmentValueParameterOptional(t *testing.T) {
+	result, err := writeEnvironmentValueParameter(parameter.ParameterWriterContext{
+		Parameter: NewOptional("TEST"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "TEST", "required": false}, result)
+}
+
 func TestWriteEnvironmentValueParameterErrorOnOtherParameterType(t *testing.T) {
 	valueParam := value.ValueParameter{}
 
@@ -196,3 +254,17 @@ func TestWriteEnvironmentValueParameterErrorOnOtherParameterType(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestCacheKeyDistinguishesMissingVariableHandling(t *testing.T) {
+	keys := map[string]bool{}
+	for _, p := range []*EnvironmentVariableParameter{New("TEST"), New("OTHER"), NewWithDefault("TEST", "default"), NewWithDefault("TEST", ""), NewOptional("TEST")} {
+		key, cacheable := p.CacheKey()
+		assert.True(t, cacheable)
+		assert.False(t, keys[key], "duplicate cache key %q", key)
+		keys[key] = true
+	}
+
+	key, _ := New("TEST").CacheKey()
+	sameKey, _ := New("TEST").CacheKey()
+	assert.Equal(t, key, sameKey)
+}
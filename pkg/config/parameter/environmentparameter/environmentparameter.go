@@ -0,0 +1,131 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package environmentparameter
+
+import (
+	"fmt"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+// EnvironmentParameterType specifies the type of the parameter used in config files
+const EnvironmentParameterType = "environmentParameter"
+
+var EnvironmentParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeEnvironmentParameter,
+	Deserializer: parseEnvironmentParameter,
+}
+
+// EnvironmentParameter defines a parameter which loads its value from the parameters defined
+// for an environment (or its group) in the manifest. A default value can be defined, which is
+// used if the environment does not define the parameter.
+type EnvironmentParameter struct {
+	// Name of the referenced manifest parameter
+	Name string
+
+	// HasDefaultValue indicates that a default value has been set. this is needed, as
+	// we cannot distinguish an empty value from a not set value.
+	HasDefaultValue bool
+
+	// DefaultValue is used if the environment does not define the parameter specified by `Name`.
+	// note: this value is only used, if the `HasDefaultValue` flag is set to true.
+	DefaultValue interface{}
+
+	// Value is the value defined for the environment the parameter has been loaded for.
+	// it is resolved at config load time.
+	Value interface{}
+}
+
+func New(name string, value interface{}) *EnvironmentParameter {
+	return &EnvironmentParameter{
+		Name:  name,
+		Value: value,
+	}
+}
+
+func NewWithDefault(name string, value interface{}, defaultValue interface{}) *EnvironmentParameter {
+	return &EnvironmentParameter{
+		Name:            name,
+		HasDefaultValue: true,
+		DefaultValue:    defaultValue,
+		Value:           value,
+	}
+}
+
+// this forces the compiler to check if EnvironmentParameter is of type Parameter
+var _ parameter.Parameter = (*EnvironmentParameter)(nil)
+
+func (p *EnvironmentParameter) GetType() string {
+	return EnvironmentParameterType
+}
+
+func (p *EnvironmentParameter) GetReferences() []parameter.ParameterReference {
+	// environment parameters cannot have references, as their value is defined in the manifest
+	return []parameter.ParameterReference{}
+}
+
+func (p *EnvironmentParameter) ResolveValue(_ parameter.ResolveContext) (interface{}, error) {
+	return template.EscapeSpecialCharactersInValue(p.Value, template.FullStringEscapeFunction)
+}
+
+// parseEnvironmentParameter parses an EnvironmentParameter from a given context.
+// it requires a `name` field to be set. `default` is an optional field.
+// The value is looked up in the environment parameters of the context.
+func parseEnvironmentParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	rawName, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+	name := strings.ToString(rawName)
+
+	value, found := context.EnvironmentParameters[name]
+	defaultValue, hasDefault := context.Value["default"]
+
+	if !found && !hasDefault {
+		return nil, parameter.NewParameterParserError(context,
+			fmt.Sprintf("environment parameter `%s` is not defined for environment `%s` or group `%s` in the manifest and no default is set", name, context.Environment, context.Group))
+	}
+
+	if !found {
+		value = defaultValue
+	}
+
+	if hasDefault {
+		return NewWithDefault(name, value, defaultValue), nil
+	}
+	return New(name, value), nil
+}
+
+func writeEnvironmentParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	envParam, ok := context.Parameter.(*EnvironmentParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `EnvironmentParameter`")
+	}
+
+	result := make(map[string]interface{})
+
+	if envParam.HasDefaultValue {
+		result["default"] = envParam.DefaultValue
+	}
+
+	result["name"] = envParam.Name
+
+	return result, nil
+}
@@ -0,0 +1,114 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package environmentparameter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+func TestParseEnvironmentParameter(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        map[string]interface{}
+		envParams    map[string]any
+		wantParam    *EnvironmentParameter
+		wantErrorMsg string
+	}{
+		{
+			name:      "value defined for environment",
+			value:     map[string]interface{}{"name": "alertingEmail"},
+			envParams: map[string]any{"alertingEmail": "prod@example.com"},
+			wantParam: New("alertingEmail", "prod@example.com"),
+		},
+		{
+			name:      "value defined for environment takes precedence over default",
+			value:     map[string]interface{}{"name": "alertingEmail", "default": "fallback@example.com"},
+			envParams: map[string]any{"alertingEmail": "prod@example.com"},
+			wantParam: NewWithDefault("alertingEmail", "prod@example.com", "fallback@example.com"),
+		},
+		{
+			name:      "default is used if environment does not define the parameter",
+			value:     map[string]interface{}{"name": "alertingEmail", "default": "fallback@example.com"},
+			envParams: map[string]any{},
+			wantParam: NewWithDefault("alertingEmail", "fallback@example.com", "fallback@example.com"),
+		},
+		{
+			name:         "missing value without default",
+			value:        map[string]interface{}{"name": "alertingEmail"},
+			envParams:    map[string]any{"other": "value"},
+			wantErrorMsg: "environment parameter `alertingEmail` is not defined for environment `env` or group `group`",
+		},
+		{
+			name:         "missing name",
+			value:        map[string]interface{}{"default": "fallback@example.com"},
+			wantErrorMsg: "missing property `name`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param, err := parseEnvironmentParameter(parameter.ParameterParserContext{
+				Group:                 "group",
+				Environment:           "env",
+				Value:                 tt.value,
+				EnvironmentParameters: tt.envParams,
+			})
+
+			if tt.wantErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantParam, param)
+			assert.Equal(t, EnvironmentParameterType, param.GetType())
+			assert.Empty(t, param.GetReferences())
+		})
+	}
+}
+
+func TestResolveEnvironmentParameter(t *testing.T) {
+	param := New("alertingEmail", `"quoted"@example.com`)
+
+	result, err := param.ResolveValue(parameter.ResolveContext{})
+
+	require.NoError(t, err)
+	assert.Equal(t, `\"quoted\"@example.com`, result)
+}
+
+func TestWriteEnvironmentParameter(t *testing.T) {
+	t.Run("without default", func(t *testing.T) {
+		result, err := writeEnvironmentParameter(parameter.ParameterWriterContext{Parameter: New("alertingEmail", "prod@example.com")})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "alertingEmail"}, result)
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		result, err := writeEnvironmentParameter(parameter.ParameterWriterContext{Parameter: NewWithDefault("alertingEmail", "prod@example.com", "fallback@example.com")})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "alertingEmail", "default": "fallback@example.com"}, result)
+	})
+}
@@ -17,12 +17,14 @@
 package file
 
 import (
+	"encoding/base64"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	tmpl "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/spf13/afero"
+	"slices"
 )
 
 const FileParameterType = "file"
@@ -32,12 +34,34 @@ var FileParameterSerde = parameter.ParameterSerDe{
 	Deserializer: parseFileValueParameter,
 }
 
+// Encoding defines how the content of a file is inserted into templates
+type Encoding string
+
+const (
+	// EncodingJSON escapes the content for use inside a JSON string. It is the default encoding.
+	EncodingJSON Encoding = "json"
+	// EncodingBase64 inserts the content as base64 encoded string
+	EncodingBase64 Encoding = "base64"
+	// EncodingNone inserts the content as is, e.g. to embed a JSON object or array into a template
+	EncodingNone Encoding = "none"
+)
+
+// Encodings lists all supported encodings
+var Encodings = []Encoding{EncodingJSON, EncodingBase64, EncodingNone}
+
+// FileParameter loads the content of a file relative to the folder of the config, e.g. a script, into a template. The
+// content is rendered as template with the values of the referenced parameters, and inserted with the given encoding.
 type FileParameter struct {
-	Fs                   afero.Fs
-	Path                 string
+	Fs   afero.Fs
+	Path string
+	// Encoding of the content. If empty, EncodingJSON is used.
+	Encoding             Encoding
 	referencedParameters []parameter.ParameterReference
 }
 
+// this forces the compiler to check if FileParameter is of type CacheableParameter
+var _ parameter.CacheableParameter = (*FileParameter)(nil)
+
 func (f *FileParameter) GetType() string {
 	return FileParameterType
 }
@@ -60,7 +84,33 @@ func (f *FileParameter) ResolveValue(context parameter.ResolveContext) (interfac
 		return nil, parameter.NewParameterResolveValueError(context, err.Error())
 	}
 
-	return template.EscapeSpecialCharactersInValue(strContent, template.FullStringEscapeFunction)
+	switch f.Encoding {
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString([]byte(strContent)), nil
+	case EncodingNone:
+		return strContent, nil
+	default:
+		return template.EscapeSpecialCharactersInValue(strContent, template.FullStringEscapeFunction)
+	}
+}
+
+// CacheKey identifies the parameter by the path of the file and its encoding. As paths are relative to the folder of
+// the config, the real path is used for file systems based on that folder. Files referencing other parameters are
+// rendered with the values of the config they are resolved for, thus their values are not cached.
+func (f *FileParameter) CacheKey() (string, bool) {
+	if len(f.referencedParameters) > 0 {
+		return "", false
+	}
+
+	path := f.Path
+	if baseFs, ok := f.Fs.(*afero.BasePathFs); ok {
+		realPath, err := baseFs.RealPath(f.Path)
+		if err != nil {
+			return "", false
+		}
+		path = realPath
+	}
+	return string(f.Encoding) + "|" + path, true
 }
 
 func parseFileValueParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
@@ -73,9 +123,14 @@ func parseFileValueParameter(context parameter.ParameterParserContext) (paramete
 		return nil, parameter.NewParameterParserError(context, "missing property `path`")
 	}
 
+	encoding, err := parseEncoding(context)
+	if err != nil {
+		return nil, err
+	}
+
 	references, ok := context.Value["references"]
 	if !ok {
-		return &FileParameter{Fs: context.Fs, Path: strings.ToString((path))}, nil
+		return &FileParameter{Fs: context.Fs, Path: strings.ToString((path)), Encoding: encoding}, nil
 	}
 
 	referencedParameterSlice, ok := references.([]interface{})
@@ -88,8 +143,22 @@ func parseFileValueParameter(context parameter.ParameterParserContext) (paramete
 		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("invalid parameter references: %v", err))
 	}
 
-	return &FileParameter{Fs: context.Fs, Path: strings.ToString((path)), referencedParameters: referencedParameters}, nil
+	return &FileParameter{Fs: context.Fs, Path: strings.ToString((path)), Encoding: encoding, referencedParameters: referencedParameters}, nil
+
+}
+
+// parseEncoding returns the optional encoding of the parameter, which has to be one of [Encodings]
+func parseEncoding(context parameter.ParameterParserContext) (Encoding, error) {
+	val, ok := context.Value["encoding"]
+	if !ok {
+		return "", nil
+	}
 
+	encoding := Encoding(strings.ToString(val))
+	if !slices.Contains(Encodings, encoding) {
+		return "", parameter.NewParameterParserError(context, fmt.Sprintf("unknown encoding `%s`, expected one of %q", encoding, Encodings))
+	}
+	return encoding, nil
 }
 
 func writeFileValueParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
@@ -102,6 +171,9 @@ func writeFileValueParameter(context parameter.ParameterWriterContext) (map[stri
 	result := make(map[string]interface{})
 
 	result["path"] = fileParam.Path
+	if fileParam.Encoding != "" {
+		result["encoding"] = string(fileParam.Encoding)
+	}
 
 	return result, nil
 }
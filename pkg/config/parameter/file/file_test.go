@@ -92,6 +92,51 @@ func TestResolveValue(t *testing.T) {
 	assert.Equal(t, "test-content", result)
 }
 
+func TestResolveValueWithEncoding(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "script.js", []byte("const name = \"{{ .name }}\";\nrun(name);"), 0644)
+
+	tests := []struct {
+		encoding string
+		want     string
+	}{
+		{"json", `const name = \"my-name\";\nrun(name);`},
+		{"base64", "Y29uc3QgbmFtZSA9ICJteS1uYW1lIjsKcnVuKG5hbWUpOw=="},
+		{"none", "const name = \"my-name\";\nrun(name);"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			param, err := parseFileValueParameter(parameter.ParameterParserContext{
+				Fs:    fs,
+				Value: map[string]any{"path": "script.js", "encoding": tt.encoding, "references": []any{"name"}},
+			})
+			require.NoError(t, err)
+
+			result, err := param.ResolveValue(parameter.ResolveContext{ResolvedParameterValues: map[string]any{"name": "my-name"}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestParseFileValueParameter_UnknownEncoding(t *testing.T) {
+	param, err := parseFileValueParameter(parameter.ParameterParserContext{
+		Fs:    afero.NewMemMapFs(),
+		Value: map[string]any{"path": "something.txt", "encoding": "hex"},
+	})
+
+	assert.Nil(t, param)
+	assert.ErrorContains(t, err, "unknown encoding `hex`")
+}
+
+func TestWriteFileValueParameter_Encoding(t *testing.T) {
+	result, err := writeFileValueParameter(parameter.ParameterWriterContext{
+		Parameter: &FileParameter{Path: "myfile", Encoding: EncodingBase64},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"path": "myfile", "encoding": "base64"}, result)
+}
+
 func TestResolveValueWithRefernces(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	afero.WriteFile(fs, "test-content", []byte("test-content {{ .ref1 }} - {{ .ref2 }}"), 0644)
@@ -145,3 +190,25 @@ func TestResolveValue_FileNotFound(t *testing.T) {
 	assert.Nil(t, result)
 	assert.IsType(t, parameter.ParameterResolveValueError{}, err)
 }
+
+func TestCacheKey(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	param, _ := parseFileValueParameter(parameter.ParameterParserContext{Fs: fs, Value: map[string]any{"path": "test-content"}})
+	key, cacheable := param.(*FileParameter).CacheKey()
+	assert.True(t, cacheable)
+
+	param, _ = parseFileValueParameter(parameter.ParameterParserContext{Fs: fs, Value: map[string]any{"path": "test-content", "encoding": "base64"}})
+	base64Key, _ := param.(*FileParameter).CacheKey()
+	assert.NotEqual(t, key, base64Key, "encodings of the same file are different values")
+
+	param, _ = parseFileValueParameter(parameter.ParameterParserContext{Fs: afero.NewBasePathFs(fs, "project/a"), Value: map[string]any{"path": "test-content"}})
+	keyOfA, _ := param.(*FileParameter).CacheKey()
+	param, _ = parseFileValueParameter(parameter.ParameterParserContext{Fs: afero.NewBasePathFs(fs, "project/b"), Value: map[string]any{"path": "test-content"}})
+	keyOfB, _ := param.(*FileParameter).CacheKey()
+	assert.NotEqual(t, keyOfA, keyOfB, "files of different config folders are different values")
+
+	param, _ = parseFileValueParameter(parameter.ParameterParserContext{Fs: fs, Value: map[string]any{"path": "test-content", "references": []any{"ref1"}}})
+	_, cacheable = param.(*FileParameter).CacheKey()
+	assert.False(t, cacheable, "files referencing parameters are rendered per config")
+}
@@ -0,0 +1,286 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generated
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/google/uuid"
+)
+
+const (
+	// UUIDParameterType specifies the type of the uuid parameter used in config files
+	UUIDParameterType = "uuid"
+	// TimestampParameterType specifies the type of the timestamp parameter used in config files
+	TimestampParameterType = "timestamp"
+	// RandomStringParameterType specifies the type of the random string parameter used in config files
+	RandomStringParameterType = "randomString"
+)
+
+// TimestampFormatUnix and TimestampFormatUnixMilli are special timestamp formats, which render the seconds or
+// milliseconds since the Unix epoch
+const (
+	TimestampFormatUnix      = "unix"
+	TimestampFormatUnixMilli = "unixMilli"
+)
+
+// DefaultCharset is used by random string parameters that do not define a charset
+const DefaultCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// DefaultLength is the length of random strings of parameters that do not define a length
+const DefaultLength = 16
+
+var UUIDParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeUUIDParameter,
+	Deserializer: parseUUIDParameter,
+}
+
+var TimestampParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeTimestampParameter,
+	Deserializer: parseTimestampParameter,
+}
+
+var RandomStringParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeRandomStringParameter,
+	Deserializer: parseRandomStringParameter,
+}
+
+// now returns the current time, it is replaced in tests
+var now = time.Now
+
+// UUIDParameter generates a random UUID whenever it is resolved. If it is stable, the UUID is derived from the
+// coordinate of the config and the name of the parameter instead, thus it is the same for every deployment.
+type UUIDParameter struct {
+	Stable bool
+}
+
+// TimestampParameter renders the time the parameter is resolved at in UTC, using the given format. The format is a Go
+// time layout (e.g. '2006-01-02'), or one of TimestampFormatUnix and TimestampFormatUnixMilli.
+type TimestampParameter struct {
+	Format string
+}
+
+// RandomStringParameter generates a random string of the given length out of the characters of the given charset
+// whenever it is resolved. If it is stable, the string is derived from the coordinate of the config and the name of the
+// parameter instead, thus it is the same for every deployment.
+type RandomStringParameter struct {
+	Length  int
+	Charset string
+	Stable  bool
+}
+
+// this forces the compiler to check if the generated parameters are of type Parameter
+var (
+	_ parameter.Parameter = (*UUIDParameter)(nil)
+	_ parameter.Parameter = (*TimestampParameter)(nil)
+	_ parameter.Parameter = (*RandomStringParameter)(nil)
+)
+
+func (p *UUIDParameter) GetType() string {
+	return UUIDParameterType
+}
+
+func (p *UUIDParameter) GetReferences() []parameter.ParameterReference {
+	// generated parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *UUIDParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if p.Stable {
+		return idutils.GenerateUUIDFromString(stableSeed(context)), nil
+	}
+	return uuid.NewString(), nil
+}
+
+func (p *TimestampParameter) GetType() string {
+	return TimestampParameterType
+}
+
+func (p *TimestampParameter) GetReferences() []parameter.ParameterReference {
+	// generated parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *TimestampParameter) ResolveValue(_ parameter.ResolveContext) (interface{}, error) {
+	t := now().UTC()
+	switch p.Format {
+	case TimestampFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case TimestampFormatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	case "":
+		return t.Format(time.RFC3339), nil
+	default:
+		return template.EscapeSpecialCharactersInValue(t.Format(p.Format), template.FullStringEscapeFunction)
+	}
+}
+
+func (p *RandomStringParameter) GetType() string {
+	return RandomStringParameterType
+}
+
+func (p *RandomStringParameter) GetReferences() []parameter.ParameterReference {
+	// generated parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *RandomStringParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	var seed [32]byte
+	if p.Stable {
+		seed = sha256.Sum256([]byte(stableSeed(context)))
+	} else if _, err := cryptorand.Read(seed[:]); err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to generate random string: %s", err))
+	}
+	r := rand.New(rand.NewChaCha8(seed))
+
+	charset := []rune(p.charset())
+	result := make([]rune, p.length())
+	for i := range result {
+		result[i] = charset[r.IntN(len(charset))]
+	}
+	return template.EscapeSpecialCharactersInValue(string(result), template.FullStringEscapeFunction)
+}
+
+func (p *RandomStringParameter) charset() string {
+	if p.Charset == "" {
+		return DefaultCharset
+	}
+	return p.Charset
+}
+
+func (p *RandomStringParameter) length() int {
+	if p.Length == 0 {
+		return DefaultLength
+	}
+	return p.Length
+}
+
+// stableSeed identifies the parameter being resolved by the coordinate of its config and its name
+func stableSeed(context parameter.ResolveContext) string {
+	return context.ConfigCoordinate.String() + ":" + context.ParameterName
+}
+
+func parseUUIDParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	stable, err := parseStable(context)
+	if err != nil {
+		return nil, err
+	}
+	return &UUIDParameter{Stable: stable}, nil
+}
+
+func parseTimestampParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	var format string
+	if val, ok := context.Value["format"]; ok {
+		format = strings.ToString(val)
+		if format == "" {
+			return nil, parameter.NewParameterParserError(context, "property `format` must not be empty")
+		}
+	}
+	return &TimestampParameter{Format: format}, nil
+}
+
+func parseRandomStringParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	stable, err := parseStable(context)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &RandomStringParameter{Stable: stable}
+	if val, ok := context.Value["length"]; ok {
+		length, isInt := val.(int)
+		if !isInt || length <= 0 {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("property `length` must be a positive number, but is %q", strings.ToString(val)))
+		}
+		p.Length = length
+	}
+
+	if val, ok := context.Value["charset"]; ok {
+		p.Charset = strings.ToString(val)
+		if p.Charset == "" {
+			return nil, parameter.NewParameterParserError(context, "property `charset` must not be empty")
+		}
+	}
+	return p, nil
+}
+
+// parseStable parses the optional `stable` property, which has to be a boolean
+func parseStable(context parameter.ParameterParserContext) (bool, error) {
+	val, ok := context.Value["stable"]
+	if !ok {
+		return false, nil
+	}
+
+	stable, isBool := val.(bool)
+	if !isBool {
+		return false, parameter.NewParameterParserError(context, fmt.Sprintf("property `stable` must be `true` or `false`, but is %q", strings.ToString(val)))
+	}
+	return stable, nil
+}
+
+func writeUUIDParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	p, ok := context.Parameter.(*UUIDParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `UUIDParameter`")
+	}
+
+	result := make(map[string]interface{})
+	if p.Stable {
+		result["stable"] = true
+	}
+	return result, nil
+}
+
+func writeTimestampParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	p, ok := context.Parameter.(*TimestampParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `TimestampParameter`")
+	}
+
+	result := make(map[string]interface{})
+	if p.Format != "" {
+		result["format"] = p.Format
+	}
+	return result, nil
+}
+
+func writeRandomStringParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	p, ok := context.Parameter.(*RandomStringParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `RandomStringParameter`")
+	}
+
+	result := make(map[string]interface{})
+	if p.Length != 0 {
+		result["length"] = p.Length
+	}
+	if p.Charset != "" {
+		result["charset"] = p.Charset
+	}
+	if p.Stable {
+		result["stable"] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,224 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package generated
+
+import (
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+func resolveContext(configId string, parameterName string) parameter.ResolveContext {
+	return parameter.ResolveContext{
+		ConfigCoordinate: coordinate.Coordinate{Project: "project", Type: "type", ConfigId: configId},
+		ParameterName:    parameterName,
+	}
+}
+
+func TestParseGeneratedParameters(t *testing.T) {
+	tests := []struct {
+		name         string
+		serde        parameter.ParameterSerDe
+		value        map[string]any
+		want         parameter.Parameter
+		wantErrorMsg string
+	}{
+		{
+			name:  "uuid",
+			serde: UUIDParameterSerde,
+			value: map[string]any{},
+			want:  &UUIDParameter{},
+		},
+		{
+			name:  "stable uuid",
+			serde: UUIDParameterSerde,
+			value: map[string]any{"stable": true},
+			want:  &UUIDParameter{Stable: true},
+		},
+		{
+			name:         "uuid with invalid stable",
+			serde:        UUIDParameterSerde,
+			value:        map[string]any{"stable": "yes"},
+			wantErrorMsg: "property `stable` must be `true` or `false`",
+		},
+		{
+			name:  "timestamp",
+			serde: TimestampParameterSerde,
+			value: map[string]any{"format": "2006-01-02"},
+			want:  &TimestampParameter{Format: "2006-01-02"},
+		},
+		{
+			name:         "timestamp with empty format",
+			serde:        TimestampParameterSerde,
+			value:        map[string]any{"format": ""},
+			wantErrorMsg: "property `format` must not be empty",
+		},
+		{
+			name:  "random string",
+			serde: RandomStringParameterSerde,
+			value: map[string]any{"length": 8, "charset": "abc", "stable": true},
+			want:  &RandomStringParameter{Length: 8, Charset: "abc", Stable: true},
+		},
+		{
+			name:         "random string with negative length",
+			serde:        RandomStringParameterSerde,
+			value:        map[string]any{"length": -1},
+			wantErrorMsg: "property `length` must be a positive number",
+		},
+		{
+			name:         "random string with non-numeric length",
+			serde:        RandomStringParameterSerde,
+			value:        map[string]any{"length": "long"},
+			wantErrorMsg: "property `length` must be a positive number",
+		},
+		{
+			name:         "random string with empty charset",
+			serde:        RandomStringParameterSerde,
+			value:        map[string]any{"charset": ""},
+			wantErrorMsg: "property `charset` must not be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.serde.Deserializer(parameter.ParameterParserContext{Value: tt.value})
+			if tt.wantErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.wantErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			written, err := tt.serde.Serializer(parameter.ParameterWriterContext{Parameter: got})
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, written)
+		})
+	}
+}
+
+func TestWriteGeneratedParameters_WrongType(t *testing.T) {
+	for _, serde := range []parameter.ParameterSerDe{UUIDParameterSerde, TimestampParameterSerde, RandomStringParameterSerde} {
+		_, err := serde.Serializer(parameter.ParameterWriterContext{Parameter: &parameter.DummyParameter{}})
+		assert.IsType(t, &parameter.ParameterWriterError{}, err)
+	}
+}
+
+func TestUUIDParameter_ResolveValue(t *testing.T) {
+	t.Run("random", func(t *testing.T) {
+		p := &UUIDParameter{}
+		first, err := p.ResolveValue(resolveContext("config", "id"))
+		require.NoError(t, err)
+		second, err := p.ResolveValue(resolveContext("config", "id"))
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+		_, err = uuid.Parse(first.(string))
+		assert.NoError(t, err)
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		p := &UUIDParameter{Stable: true}
+		first, err := p.ResolveValue(resolveContext("config", "id"))
+		require.NoError(t, err)
+		second, err := p.ResolveValue(resolveContext("config", "id"))
+		require.NoError(t, err)
+		ofOtherConfig, err := p.ResolveValue(resolveContext("other-config", "id"))
+		require.NoError(t, err)
+		ofOtherParameter, err := p.ResolveValue(resolveContext("config", "other-id"))
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, ofOtherConfig)
+		assert.NotEqual(t, first, ofOtherParameter)
+	})
+}
+
+func TestTimestampParameter_ResolveValue(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2024, 3, 4, 5, 6, 7, 8_000_000, time.FixedZone("CET", 3600))
+	}
+	t.Cleanup(func() { now = time.Now })
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"", "2024-03-04T04:06:07Z"},
+		{"2006-01-02", "2024-03-04"},
+		{TimestampFormatUnix, "1709525167"},
+		{TimestampFormatUnixMilli, "1709525167008"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := (&TimestampParameter{Format: tt.format}).ResolveValue(resolveContext("config", "ts"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRandomStringParameter_ResolveValue(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		got, err := (&RandomStringParameter{}).ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		assert.Len(t, got, DefaultLength)
+		assert.Regexp(t, "^[a-zA-Z0-9]+$", got)
+	})
+
+	t.Run("charset and length", func(t *testing.T) {
+		got, err := (&RandomStringParameter{Length: 32, Charset: "äb"}).ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		assert.Equal(t, 32, utf8.RuneCountInString(got.(string)))
+		assert.Regexp(t, "^[äb]+$", got)
+	})
+
+	t.Run("random", func(t *testing.T) {
+		p := &RandomStringParameter{Length: 32}
+		first, err := p.ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		second, err := p.ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		p := &RandomStringParameter{Length: 32, Stable: true}
+		first, err := p.ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		second, err := p.ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		ofOtherConfig, err := p.ResolveValue(resolveContext("other-config", "s"))
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, ofOtherConfig)
+	})
+
+	t.Run("special characters are escaped", func(t *testing.T) {
+		got, err := (&RandomStringParameter{Length: 4, Charset: `"`}).ResolveValue(resolveContext("config", "s"))
+		require.NoError(t, err)
+		assert.Equal(t, `\"\"\"\"`, got)
+	})
+}
@@ -0,0 +1,302 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httplookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	stringutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"golang.org/x/exp/maps"
+	"golang.org/x/sync/singleflight"
+)
+
+// HTTPLookupParameterType specifies the type of the parameter used in config files
+const HTTPLookupParameterType = "httpLookup"
+
+var HTTPLookupParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeHTTPLookupParameter,
+	Deserializer: parseHTTPLookupParameter,
+}
+
+// requestTimeout limits the duration of a single lookup request
+const requestTimeout = 30 * time.Second
+
+// maxErrorBodyLength limits how much of the body of a failed response is reported
+const maxErrorBodyLength = 500
+
+// HTTPLookupParameter defines a parameter which looks up its value with a GET request at resolve time, and extracts it
+// from the JSON response with a JSONPath expression - e.g. the entity ID of a host by its name.
+//
+// A URL starting with '/' is relative to the environment the config is deployed to, and the request is authenticated
+// with the environment's API token. Any other URL is called as is, with the given headers only.
+type HTTPLookupParameter struct {
+	// URL to send the GET request to - either absolute, or a path of the environment's API
+	URL string
+
+	// JSONPath selects the value from the response. It has to select exactly one value.
+	JSONPath string
+
+	// Headers are optional additional headers sent with the request
+	Headers map[string]string
+
+	path jsonPath
+
+	// environmentURL and environmentToken are used for URLs relative to the environment
+	environmentURL   string
+	environmentToken string
+}
+
+func New(rawURL string, jsonPathExpr string, headers map[string]string) (*HTTPLookupParameter, error) {
+	p, err := compileJSONPath(jsonPathExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(rawURL, "/") {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid URL %q - expected a path of the environment's API starting with '/', or an absolute HTTP(S) URL", rawURL)
+		}
+	}
+
+	return &HTTPLookupParameter{
+		URL:      rawURL,
+		JSONPath: jsonPathExpr,
+		Headers:  headers,
+		path:     p,
+	}, nil
+}
+
+// this forces the compiler to check if HTTPLookupParameter is of type Parameter
+var _ parameter.Parameter = (*HTTPLookupParameter)(nil)
+
+func (p *HTTPLookupParameter) GetType() string {
+	return HTTPLookupParameterType
+}
+
+func (p *HTTPLookupParameter) GetReferences() []parameter.ParameterReference {
+	// http lookup parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *HTTPLookupParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	req, err := p.request()
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, err.Error())
+	}
+
+	body, err := defaultCache.get(req)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("lookup of %q failed: %s", p.URL, err))
+	}
+
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("lookup of %q failed: response is no valid JSON: %s", p.URL, err))
+	}
+
+	values := p.path.evaluate(data)
+	switch {
+	case len(values) == 0:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("lookup of %q failed: JSONPath %q selects no value of the response", p.URL, p.JSONPath))
+	case len(values) > 1:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("lookup of %q failed: JSONPath %q selects %d values of the response, but must select exactly one", p.URL, p.JSONPath, len(values)))
+	}
+
+	value := values[0]
+	switch value.(type) {
+	case map[string]any, []any:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("lookup of %q failed: JSONPath %q selects an object or array, but must select a single value", p.URL, p.JSONPath))
+	case nil:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("lookup of %q failed: JSONPath %q selects null", p.URL, p.JSONPath))
+	}
+
+	log.Debug("Looked up value %q of parameter %q with %q", value, context.ParameterName, p.URL)
+	return template.EscapeSpecialCharactersInValue(stringutils.ToString(value), template.FullStringEscapeFunction)
+}
+
+// request returns the GET request of the lookup
+func (p *HTTPLookupParameter) request() (*http.Request, error) {
+	target := p.URL
+	if strings.HasPrefix(target, "/") {
+		if p.environmentURL == "" {
+			return nil, fmt.Errorf("cannot look up %q: the URL is relative to the environment, but the environment has no URL", p.URL)
+		}
+		target = strings.TrimSuffix(p.environmentURL, "/") + target
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up %q: %w", p.URL, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if strings.HasPrefix(p.URL, "/") {
+		if p.environmentToken == "" {
+			return nil, fmt.Errorf("cannot look up %q: the URL is relative to the environment, but the environment has no API token", p.URL)
+		}
+		req.Header.Set("Authorization", "Api-Token "+p.environmentToken)
+	}
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// responseCache caches the responses of lookups, so that each distinct request is only sent once, no matter how many
+// configs and environments use it. Failed requests are cached as well, so that each failure is only caused once.
+type responseCache struct {
+	client    *http.Client
+	mu        sync.Mutex
+	responses map[string]cachedResponse
+	inflight  singleflight.Group
+}
+
+type cachedResponse struct {
+	body []byte
+	err  error
+}
+
+var defaultCache = newResponseCache(&http.Client{Timeout: requestTimeout})
+
+func newResponseCache(client *http.Client) *responseCache {
+	return &responseCache{client: client, responses: make(map[string]cachedResponse)}
+}
+
+func (c *responseCache) get(req *http.Request) ([]byte, error) {
+	key := cacheKey(req)
+
+	c.mu.Lock()
+	cached, found := c.responses[key]
+	c.mu.Unlock()
+	if found {
+		return cached.body, cached.err
+	}
+
+	body, err, _ := c.inflight.Do(key, func() (any, error) {
+		body, err := c.send(req)
+
+		c.mu.Lock()
+		c.responses[key] = cachedResponse{body: body, err: err}
+		c.mu.Unlock()
+		return body, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body.([]byte), nil
+}
+
+func (c *responseCache) send(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > maxErrorBodyLength {
+			msg = msg[:maxErrorBodyLength] + "..."
+		}
+		return nil, fmt.Errorf("HTTP status %d: %s", resp.StatusCode, msg)
+	}
+	return body, nil
+}
+
+// cacheKey identifies a request by its URL and headers
+func cacheKey(req *http.Request) string {
+	names := maps.Keys(req.Header)
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString(req.URL.String())
+	for _, n := range names {
+		b.WriteString("\n" + n + ":" + strings.Join(req.Header[n], ","))
+	}
+	return b.String()
+}
+
+// parseHTTPLookupParameter parses an HTTPLookupParameter from a given context.
+// it requires the `url` and `jsonPath` fields to be set. `headers` is an optional field.
+func parseHTTPLookupParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	rawURL, ok := context.Value["url"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `url`")
+	}
+	rawPath, ok := context.Value["jsonPath"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `jsonPath`")
+	}
+
+	headers := map[string]string{}
+	if rawHeaders, ok := context.Value["headers"]; ok {
+		switch m := rawHeaders.(type) {
+		case map[any]any:
+			for k, v := range m {
+				headers[stringutils.ToString(k)] = stringutils.ToString(v)
+			}
+		case map[string]any:
+			for k, v := range m {
+				headers[k] = stringutils.ToString(v)
+			}
+		default:
+			return nil, parameter.NewParameterParserError(context, "property `headers` must be a map of header names to values")
+		}
+	}
+
+	p, err := New(stringutils.ToString(rawURL), stringutils.ToString(rawPath), headers)
+	if err != nil {
+		return nil, parameter.NewParameterParserError(context, err.Error())
+	}
+	p.environmentURL = context.EnvironmentURL
+	p.environmentToken = context.EnvironmentToken
+	return p, nil
+}
+
+func writeHTTPLookupParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	lookupParam, ok := context.Parameter.(*HTTPLookupParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `HTTPLookupParameter`")
+	}
+
+	result := map[string]interface{}{
+		"url":      lookupParam.URL,
+		"jsonPath": lookupParam.JSONPath,
+	}
+
+	if len(lookupParam.Headers) > 0 {
+		result["headers"] = lookupParam.Headers
+	}
+
+	return result, nil
+}
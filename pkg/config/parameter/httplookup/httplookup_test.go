@@ -0,0 +1,183 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httplookup
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHTTPLookupParameter(t *testing.T) {
+	param, err := parseHTTPLookupParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"url":      "/api/v2/entities?entitySelector=type(HOST)",
+			"jsonPath": "$.entities[0].entityId",
+			"headers":  map[interface{}]interface{}{"X-Custom": "value"},
+		},
+		EnvironmentURL:   "https://abc.live.dynatrace.com",
+		EnvironmentToken: "dt0c01.token",
+	})
+	require.NoError(t, err)
+
+	p, ok := param.(*HTTPLookupParameter)
+	require.True(t, ok, "parsed parameter should be http lookup parameter")
+	assert.Equal(t, "httpLookup", p.GetType())
+	assert.Empty(t, p.GetReferences())
+	assert.Equal(t, "/api/v2/entities?entitySelector=type(HOST)", p.URL)
+	assert.Equal(t, "$.entities[0].entityId", p.JSONPath)
+	assert.Equal(t, map[string]string{"X-Custom": "value"}, p.Headers)
+	assert.Equal(t, "https://abc.live.dynatrace.com", p.environmentURL)
+	assert.Equal(t, "dt0c01.token", p.environmentToken)
+}
+
+func TestParseHTTPLookupParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{"missing url", map[string]interface{}{"jsonPath": "$.a"}, "missing property `url`"},
+		{"missing jsonPath", map[string]interface{}{"url": "/api"}, "missing property `jsonPath`"},
+		{"invalid jsonPath", map[string]interface{}{"url": "/api", "jsonPath": "a.b"}, "JSONPath must start with '$'"},
+		{"invalid url", map[string]interface{}{"url": "ftp://host/file", "jsonPath": "$.a"}, `invalid URL "ftp://host/file"`},
+		{"invalid headers", map[string]interface{}{"url": "/api", "jsonPath": "$.a", "headers": []interface{}{"a"}}, "property `headers` must be a map"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseHTTPLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestWriteHTTPLookupParameter(t *testing.T) {
+	p, err := New("https://example.com/hosts", "$.id", map[string]string{"X-Custom": "value"})
+	require.NoError(t, err)
+	p.environmentToken = "dt0c01.token"
+
+	result, err := writeHTTPLookupParameter(parameter.ParameterWriterContext{Parameter: p})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"url":      "https://example.com/hosts",
+		"jsonPath": "$.id",
+		"headers":  map[string]string{"X-Custom": "value"},
+	}, result)
+}
+
+func TestResolveValue(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/api/v2/entities":
+			if r.Header.Get("Authorization") != "Api-Token dt0c01.token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error": {"code": 401, "message": "missing token"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(testDocument))
+		case "/external":
+			assert.Empty(t, r.Header.Get("Authorization"))
+			assert.Equal(t, "value", r.Header.Get("X-Custom"))
+			_, _ = w.Write([]byte(`{"name": "a \"quoted\" name"}`))
+		case "/invalid":
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	newParam := func(t *testing.T, url, path string, headers map[string]string) *HTTPLookupParameter {
+		p, err := New(url, path, headers)
+		require.NoError(t, err)
+		p.environmentURL = server.URL
+		p.environmentToken = "dt0c01.token"
+		return p
+	}
+	context := parameter.ResolveContext{ParameterName: "hostId"}
+
+	t.Run("value of the environment's API", func(t *testing.T) {
+		v, err := newParam(t, "/api/v2/entities", "$.entities[?(@.displayName == 'web')].entityId", nil).ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, "HOST-1", v)
+
+		v, err = newParam(t, "/api/v2/entities", "$.totalCount", nil).ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, "3", v)
+	})
+
+	t.Run("responses are cached", func(t *testing.T) {
+		before := calls.Load()
+		_, err := newParam(t, "/api/v2/entities", "$.entities[1].entityId", nil).ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, before, calls.Load())
+	})
+
+	t.Run("value of an external API is escaped", func(t *testing.T) {
+		v, err := newParam(t, server.URL+"/external", "$.name", map[string]string{"X-Custom": "value"}).ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, `a \"quoted\" name`, v)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			param         *HTTPLookupParameter
+			expectedError string
+		}{
+			{"no match", newParam(t, "/api/v2/entities", "$.entities[?(@.displayName == 'x')].entityId", nil), "selects no value of the response"},
+			{"several matches", newParam(t, "/api/v2/entities", "$.entities[?(@.displayName == 'db')].entityId", nil), "selects 2 values of the response, but must select exactly one"},
+			{"object", newParam(t, "/api/v2/entities", "$.entities[0]", nil), "selects an object or array"},
+			{"failed request", newParam(t, "/missing", "$.a", nil), "HTTP status 404"},
+			{"invalid response", newParam(t, "/invalid", "$.a", nil), "response is no valid JSON"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.param.ResolveValue(context)
+				assert.ErrorContains(t, err, tt.expectedError)
+			})
+		}
+
+		p := newParam(t, "/api/v2/entities", "$.a", nil)
+		p.environmentToken = ""
+		_, err := p.ResolveValue(context)
+		assert.ErrorContains(t, err, "the environment has no API token")
+	})
+}
+
+func TestResponseCache_ReportsBodyOfFailedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid entity selector"}`))
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = newResponseCache(server.Client()).get(req)
+	assert.EqualError(t, err, `HTTP status 400: {"error": "invalid entity selector"}`)
+}
@@ -0,0 +1,234 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httplookup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. It supports the following subset of JSONPath:
+//   - '$' - the root value, every expression has to start with it
+//   - '.key' and '['key']' - the value of a key of an object
+//   - '[n]' - the n-th element of an array, negative indices count from the end
+//   - '.*' and '[*]' - all values of an object or array
+//   - '[?(@.key == 'value')]' - all elements of an array with a value equal to (or with '!=', not equal to) the given
+//     string, number, or boolean. The path after '@' may consist of several keys, e.g. '@.properties.name'.
+type jsonPath []segment
+
+// segment selects values from a single value
+type segment func(v any) []any
+
+// compileJSONPath parses the given JSONPath expression
+func compileJSONPath(expr string) (jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, errors.New("JSONPath must start with '$'")
+	}
+
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		var s segment
+		var err error
+		switch rest[0] {
+		case '.':
+			s, rest, err = parseDotSegment(rest[1:])
+		case '[':
+			s, rest, err = parseBracketSegment(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected character %q", rest[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+		}
+		path = append(path, s)
+	}
+	return path, nil
+}
+
+// evaluate returns all values of the given JSON value the path selects
+func (p jsonPath) evaluate(root any) []any {
+	values := []any{root}
+	for _, s := range p {
+		var next []any
+		for _, v := range values {
+			next = append(next, s(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func parseDotSegment(s string) (segment, string, error) {
+	end := strings.IndexAny(s, ".[")
+	if end == -1 {
+		end = len(s)
+	}
+	key := s[:end]
+	if key == "" {
+		return nil, "", errors.New("missing key after '.'")
+	}
+	if key == "*" {
+		return selectAll, s[end:], nil
+	}
+	return selectKey(key), s[end:], nil
+}
+
+func parseBracketSegment(s string) (segment, string, error) {
+	if strings.HasPrefix(s, "?(") {
+		end := strings.Index(s, ")]")
+		if end == -1 {
+			return nil, "", errors.New("missing ')]' at end of filter")
+		}
+		f, err := parseFilter(s[2:end])
+		return f, s[end+2:], err
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end == -1 {
+		return nil, "", errors.New("missing ']'")
+	}
+	content := strings.TrimSpace(s[:end])
+	rest := s[end+1:]
+
+	if content == "*" {
+		return selectAll, rest, nil
+	}
+	if key, ok := unquote(content); ok {
+		return selectKey(key), rest, nil
+	}
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid index %q", content)
+	}
+	return selectIndex(index), rest, nil
+}
+
+// parseFilter parses a filter expression of the form "@.key == value"
+func parseFilter(expr string) (segment, error) {
+	op := "=="
+	i := strings.Index(expr, "==")
+	if j := strings.Index(expr, "!="); j != -1 && (i == -1 || j < i) {
+		op, i = "!=", j
+	}
+	if i == -1 {
+		return nil, fmt.Errorf("unsupported filter %q - expected '@.key == value' or '@.key != value'", expr)
+	}
+	left, right := expr[:i], expr[i+len(op):]
+
+	left = strings.TrimSpace(left)
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("unsupported filter %q - the left side must start with '@'", expr)
+	}
+	keyPath, err := compileJSONPath("$" + left[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := parseLiteral(strings.TrimSpace(right))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(v any) []any {
+		var matches []any
+		for _, e := range elements(v) {
+			got := keyPath.evaluate(e)
+			equal := len(got) == 1 && got[0] == want
+			if equal == (op == "==") {
+				matches = append(matches, e)
+			}
+		}
+		return matches
+	}, nil
+}
+
+// parseLiteral parses a quoted string, a number, a boolean, or null
+func parseLiteral(s string) (any, error) {
+	if str, ok := unquote(s); ok {
+		return str, nil
+	}
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q - expected a quoted string, a number, a boolean, or null", s)
+	}
+	return f, nil
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+func selectKey(key string) segment {
+	return func(v any) []any {
+		if obj, ok := v.(map[string]any); ok {
+			if e, found := obj[key]; found {
+				return []any{e}
+			}
+		}
+		return nil
+	}
+}
+
+func selectIndex(index int) segment {
+	return func(v any) []any {
+		arr, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		i := index
+		if i < 0 {
+			i += len(arr)
+		}
+		if i < 0 || i >= len(arr) {
+			return nil
+		}
+		return []any{arr[i]}
+	}
+}
+
+func selectAll(v any) []any {
+	return elements(v)
+}
+
+// elements returns the elements of an array, or the values of an object
+func elements(v any) []any {
+	switch t := v.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		values := make([]any, 0, len(t))
+		for _, e := range t {
+			values = append(values, e)
+		}
+		return values
+	}
+	return nil
+}
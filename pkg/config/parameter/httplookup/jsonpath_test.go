@@ -0,0 +1,87 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httplookup
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{
+  "totalCount": 3,
+  "entities": [
+    {"entityId": "HOST-1", "displayName": "web", "properties": {"cpuCores": 4, "monitored": true}},
+    {"entityId": "HOST-2", "displayName": "db", "properties": {"cpuCores": 8, "monitored": false}},
+    {"entityId": "HOST-3", "displayName": "db", "properties": {"cpuCores": 8, "monitored": true}}
+  ],
+  "odd.key": "dotted"
+}`
+
+func TestJSONPath(t *testing.T) {
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(testDocument), &doc))
+
+	tests := []struct {
+		path     string
+		expected []any
+	}{
+		{"$.totalCount", []any{float64(3)}},
+		{"$.entities[0].entityId", []any{"HOST-1"}},
+		{"$.entities[-1].entityId", []any{"HOST-3"}},
+		{"$['entities'][1][\"displayName\"]", []any{"db"}},
+		{"$['odd.key']", []any{"dotted"}},
+		{"$.entities[*].entityId", []any{"HOST-1", "HOST-2", "HOST-3"}},
+		{"$.entities[?(@.displayName == 'web')].entityId", []any{"HOST-1"}},
+		{"$.entities[?(@.displayName != 'web')].entityId", []any{"HOST-2", "HOST-3"}},
+		{"$.entities[?(@.properties.cpuCores == 8)].entityId", []any{"HOST-2", "HOST-3"}},
+		{"$.entities[?(@.properties.monitored == false)].entityId", []any{"HOST-2"}},
+		{"$.entities[?(@.displayName == 'missing')].entityId", nil},
+		{"$.entities[5]", nil},
+		{"$.missing.key", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := compileJSONPath(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, p.evaluate(doc))
+		})
+	}
+}
+
+func TestJSONPath_InvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"entities",
+		"$.",
+		"$.entities[0",
+		"$.entities[abc]",
+		"$.entities[?(@.a == 'b']",
+		"$.entities[?(@.a > 1)]",
+		"$.entities[?(a == 1)]",
+		"$.entities[?(@.a == unquoted)]",
+		"$x",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := compileJSONPath(expr)
+			assert.Error(t, err)
+		})
+	}
+}
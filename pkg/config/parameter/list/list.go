@@ -15,9 +15,14 @@
 package list
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/maps"
+	stringutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"strings"
 )
@@ -30,12 +35,13 @@ var ListParameterSerde = parameter.ParameterSerDe{
 	Deserializer: parseListParameter,
 }
 
-// ListParameter represents a simple list of string values.
+// ListParameter represents a list of string values. Each entry of the list is either a value, a reference to a
+// property of a config, or an [IncludedList] adding all values of another list parameter of the same config.
 type ListParameter struct {
-	Values []value.ValueParameter // TODO(CA-1517): allow for parameter.Parameter
+	Values []parameter.Parameter
 }
 
-func New(values []value.ValueParameter) *ListParameter {
+func New(values []parameter.Parameter) *ListParameter {
 	return &ListParameter{Values: values}
 }
 
@@ -47,25 +53,94 @@ func (p *ListParameter) GetType() string {
 }
 
 func (p *ListParameter) GetReferences() []parameter.ParameterReference {
-	// TODO(CA-1517): implement handling of references in list values
-	// the value parameter cannot have references, as it is a simple value
-	return []parameter.ParameterReference{}
+	refs := []parameter.ParameterReference{}
+	for _, v := range p.Values {
+		refs = append(refs, v.GetReferences()...)
+	}
+	return refs
 }
 
 func (p *ListParameter) ResolveValue(c parameter.ResolveContext) (interface{}, error) {
 
-	listValues := make([]string, len(p.Values))
-	for i, v := range p.Values {
+	listValues := make([]string, 0, len(p.Values))
+	for _, v := range p.Values {
+		if included, ok := v.(*IncludedList); ok {
+			values, err := included.resolveValues(c)
+			if err != nil {
+				return nil, err
+			}
+			listValues = append(listValues, values...)
+			continue
+		}
+
 		resolved, err := v.ResolveValue(c)
 		if err != nil {
 			return nil, err
 		}
-		listValues[i] = fmt.Sprintf(`"%s"`, resolved)
+		if _, isValue := v.(*value.ValueParameter); !isValue {
+			// values of referenced properties are not yet escaped
+			if resolved, err = template.EscapeSpecialCharactersInValue(stringutils.ToString(resolved), template.FullStringEscapeFunction); err != nil {
+				return nil, err
+			}
+		}
+		listValues = append(listValues, fmt.Sprintf(`"%s"`, resolved))
 	}
 	list := fmt.Sprintf("[ %s ]", strings.Join(listValues, ","))
 	return list, nil
 }
 
+// IncludedListType is the type of list entries including another list parameter
+const IncludedListType = ListParameterType
+
+// IncludedList is an entry of a ListParameter adding all values of another list parameter of the same config, e.g.:
+//
+//	values:
+//	  - "MZ-1"
+//	  - type: list
+//	    name: baseZones
+type IncludedList struct {
+	parameter.ParameterReference
+}
+
+// this forces the compiler to check if IncludedList is of type Parameter
+var _ parameter.Parameter = (*IncludedList)(nil)
+
+func (p *IncludedList) GetType() string {
+	return IncludedListType
+}
+
+func (p *IncludedList) GetReferences() []parameter.ParameterReference {
+	return []parameter.ParameterReference{p.ParameterReference}
+}
+
+// ResolveValue returns the resolved value of the included list parameter
+func (p *IncludedList) ResolveValue(c parameter.ResolveContext) (interface{}, error) {
+	v, found := c.ResolvedParameterValues[p.Property]
+	if !found {
+		return nil, parameter.NewParameterResolveValueError(c, fmt.Sprintf("included list parameter `%s` has not been resolved yet or does not exist", p.Property))
+	}
+	return v, nil
+}
+
+// resolveValues returns the quoted values of the included list parameter
+func (p *IncludedList) resolveValues(c parameter.ResolveContext) ([]string, error) {
+	v, err := p.ResolveValue(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(stringutils.ToString(v)), &elements); err != nil {
+		return nil, parameter.NewParameterResolveValueError(c, fmt.Sprintf("included parameter `%s` is no list parameter", p.Property))
+	}
+
+	values := make([]string, len(elements))
+	for i, e := range elements {
+		values[i] = string(e)
+	}
+	return values, nil
+}
+
 func writeListParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
 	listParam, ok := context.Parameter.(*ListParameter)
 
@@ -73,33 +148,50 @@ func writeListParameter(context parameter.ParameterWriterContext) (map[string]in
 		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `ValueParameter`")
 	}
 
+	values, err := toWritableValues(context, listParam.Values)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make(map[string]interface{})
 
-	result["values"] = toWritableValues(context, listParam.Values)
+	result["values"] = values
 
 	return result, nil
 }
 
-// TODO(CA-1517): call the underlying parameter's Deserialzer/write methods
-// toWriteableValues turns the underlying ValueParameters into a simple string list to write them in their short form
-func toWritableValues(context parameter.ParameterWriterContext, values []value.ValueParameter) []interface{} {
+// toWriteableValues turns the list entries into a list to write, using the short form for simple string values
+func toWritableValues(context parameter.ParameterWriterContext, values []parameter.Parameter) ([]interface{}, error) {
 	writableValues := make([]interface{}, len(values))
 	for i, v := range values {
-		v := v // to avoid implicit memory aliasing (gosec G601)
-		if s, ok := v.Value.(string); ok {
-			writableValues[i] = s
-			continue
-		}
-
 		subCtxt := context
-		subCtxt.Parameter = &v
-		writableVal, _ := value.ValueParameterSerde.Serializer(subCtxt)
+		subCtxt.Parameter = v
+
+		var writableVal map[string]interface{}
+		var err error
+		switch t := v.(type) {
+		case *value.ValueParameter:
+			if s, ok := t.Value.(string); ok {
+				writableValues[i] = s
+				continue
+			}
+			writableVal, err = value.ValueParameterSerde.Serializer(subCtxt)
+		case *reference.ReferenceParameter:
+			writableVal, err = reference.ReferenceParameterSerde.Serializer(subCtxt)
+		case *IncludedList:
+			writableVal = map[string]interface{}{"name": t.Property}
+		default:
+			return nil, parameter.NewParameterWriterError(context, fmt.Sprintf("unsupported list entry of type `%s` at index %d", v.GetType(), i))
+		}
+		if err != nil {
+			return nil, err
+		}
 
 		writableVal["type"] = v.GetType()
 
 		writableValues[i] = writableVal
 	}
-	return writableValues
+	return writableValues, nil
 }
 
 func parseListParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
@@ -113,11 +205,11 @@ func parseListParameter(context parameter.ParameterParserContext) (parameter.Par
 		return nil, parameter.NewParameterParserError(context, "malformed property `values` - expected list")
 	}
 
-	parameterSlice := make([]value.ValueParameter, len(valueSlice))
+	parameterSlice := make([]parameter.Parameter, len(valueSlice))
 	for i, v := range valueSlice {
 
 		if s, ok := v.(string); ok {
-			parameterSlice[i] = value.ValueParameter{Value: s}
+			parameterSlice[i] = &value.ValueParameter{Value: s}
 		} else {
 			p, err := parseSubParameter(v, context)
 			if err != nil {
@@ -132,10 +224,10 @@ func parseListParameter(context parameter.ParameterParserContext) (parameter.Par
 	return New(parameterSlice), nil
 }
 
-func parseSubParameter(paramValue interface{}, context parameter.ParameterParserContext) (value.ValueParameter, error) {
+func parseSubParameter(paramValue interface{}, context parameter.ParameterParserContext) (parameter.Parameter, error) {
 	mapVal, ok := paramValue.(map[interface{}]interface{})
 	if !ok {
-		return value.ValueParameter{}, fmt.Errorf("malformed list entry `%v`", paramValue)
+		return nil, fmt.Errorf("malformed list entry `%v`", paramValue)
 	}
 	subValue := maps.ToStringMap(mapVal)
 	subContext := parameter.ParameterParserContext{
@@ -145,9 +237,27 @@ func parseSubParameter(paramValue interface{}, context parameter.ParameterParser
 		ParameterName: context.ParameterName,
 		Value:         subValue,
 	}
-	p, err := value.ValueParameterSerde.Deserializer(subContext)
-	if err != nil {
-		return value.ValueParameter{}, err
+
+	entryType := value.ValueParameterType
+	if t, ok := subValue["type"]; ok {
+		entryType = stringutils.ToString(t)
+	}
+
+	switch entryType {
+	case value.ValueParameterType:
+		return value.ValueParameterSerde.Deserializer(subContext)
+	case reference.ReferenceParameterType:
+		return reference.ReferenceParameterSerde.Deserializer(subContext)
+	case IncludedListType:
+		name, ok := subValue["name"]
+		if !ok {
+			return nil, errors.New("missing property `name` of the included list parameter")
+		}
+		if stringutils.ToString(name) == context.ParameterName {
+			return nil, fmt.Errorf("list parameter `%s` cannot include itself", context.ParameterName)
+		}
+		return &IncludedList{ParameterReference: parameter.ParameterReference{Config: context.Coordinate, Property: stringutils.ToString(name)}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported list entry type `%s` - expected `%s`, `%s`, or `%s`", entryType, value.ValueParameterType, reference.ReferenceParameterType, IncludedListType)
 	}
-	return *p.(*value.ValueParameter), nil
 }
@@ -18,6 +18,8 @@ package list
 
 import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,7 +34,7 @@ func TestParseListParameter(t *testing.T) {
 	tests := []struct {
 		name     string
 		context  parameter.ParameterParserContext
-		wantVals []value.ValueParameter
+		wantVals []parameter.Parameter
 	}{
 		{
 			"simple values",
@@ -41,7 +43,7 @@ func TestParseListParameter(t *testing.T) {
 					"values": []interface{}{"firstName", "lastName"},
 				},
 			},
-			[]value.ValueParameter{{"firstName"}, {"lastName"}},
+			[]parameter.Parameter{&value.ValueParameter{Value: "firstName"}, &value.ValueParameter{Value: "lastName"}},
 		},
 		{
 			"full values",
@@ -59,7 +61,7 @@ func TestParseListParameter(t *testing.T) {
 					},
 				},
 			},
-			[]value.ValueParameter{{"firstName"}, {"lastName"}},
+			[]parameter.Parameter{&value.ValueParameter{Value: "firstName"}, &value.ValueParameter{Value: "lastName"}},
 		},
 		{
 			"complex values",
@@ -76,7 +78,7 @@ func TestParseListParameter(t *testing.T) {
 					},
 				},
 			},
-			[]value.ValueParameter{{map[interface{}]interface{}{
+			[]parameter.Parameter{&value.ValueParameter{Value: map[interface{}]interface{}{
 				"firstName": "John",
 				"lastName":  "Dorian",
 			}}},
@@ -88,7 +90,7 @@ func TestParseListParameter(t *testing.T) {
 					"values": []interface{}{},
 				},
 			},
-			[]value.ValueParameter{},
+			[]parameter.Parameter{},
 		},
 	}
 
@@ -161,7 +163,7 @@ func TestParseListParameter_Error(t *testing.T) {
 func TestResolveValue(t *testing.T) {
 	context := parameter.ResolveContext{}
 
-	compoundParameter := New([]value.ValueParameter{{Value: "a"}, {Value: "b"}, {Value: "c"}})
+	compoundParameter := New([]parameter.Parameter{&value.ValueParameter{Value: "a"}, &value.ValueParameter{Value: "b"}, &value.ValueParameter{Value: "c"}})
 
 	result, err := compoundParameter.ResolveValue(context)
 	require.NoError(t, err)
@@ -171,7 +173,7 @@ func TestResolveValue(t *testing.T) {
 func TestResolveSingleValue(t *testing.T) {
 	context := parameter.ResolveContext{}
 
-	compoundParameter := New([]value.ValueParameter{{Value: "a"}})
+	compoundParameter := New([]parameter.Parameter{&value.ValueParameter{Value: "a"}})
 
 	result, err := compoundParameter.ResolveValue(context)
 	require.NoError(t, err)
@@ -181,7 +183,7 @@ func TestResolveSingleValue(t *testing.T) {
 func TestResolveEmptyValue(t *testing.T) {
 	context := parameter.ResolveContext{}
 
-	compoundParameter := New([]value.ValueParameter{})
+	compoundParameter := New([]parameter.Parameter{})
 
 	result, err := compoundParameter.ResolveValue(context)
 	require.NoError(t, err)
@@ -199,7 +201,7 @@ func Test_writeListParameter(t *testing.T) {
 			"simple write",
 			parameter.ParameterWriterContext{
 				Parameter: &ListParameter{
-					Values: []value.ValueParameter{{"one"}, {"two"}, {"three"}},
+					Values: []parameter.Parameter{&value.ValueParameter{Value: "one"}, &value.ValueParameter{Value: "two"}, &value.ValueParameter{Value: "three"}},
 				},
 			},
 			map[string]interface{}{"values": []interface{}{"one", "two", "three"}},
@@ -209,9 +211,9 @@ func Test_writeListParameter(t *testing.T) {
 			"complex write",
 			parameter.ParameterWriterContext{
 				Parameter: &ListParameter{
-					Values: []value.ValueParameter{
-						{
-							map[interface{}]interface{}{
+					Values: []parameter.Parameter{
+						&value.ValueParameter{
+							Value: map[interface{}]interface{}{
 								"firstName": "John",
 								"lastName":  "Dorian",
 							},
@@ -236,7 +238,7 @@ func Test_writeListParameter(t *testing.T) {
 			"does not fail on empty values",
 			parameter.ParameterWriterContext{
 				Parameter: &ListParameter{
-					Values: []value.ValueParameter{},
+					Values: []parameter.Parameter{},
 				},
 			},
 			map[string]interface{}{"values": []interface{}{}},
@@ -266,3 +268,124 @@ func Test_writeListParameter(t *testing.T) {
 		})
 	}
 }
+
+func TestParseListParameter_ReferencesAndIncludedLists(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+
+	param, err := parseListParameter(parameter.ParameterParserContext{
+		Coordinate:    coord,
+		ParameterName: "zones",
+		Value: map[string]interface{}{
+			"values": []interface{}{
+				"MZ-1",
+				map[interface{}]interface{}{
+					"type":       "reference",
+					"configType": "management-zone",
+					"configId":   "zone-a",
+					"property":   "id",
+				},
+				map[interface{}]interface{}{
+					"type": "list",
+					"name": "baseZones",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	listParam := param.(*ListParameter)
+	assert.Equal(t, []parameter.Parameter{
+		&value.ValueParameter{Value: "MZ-1"},
+		reference.New("project", "management-zone", "zone-a", "id"),
+		&IncludedList{ParameterReference: parameter.ParameterReference{Config: coord, Property: "baseZones"}},
+	}, listParam.Values)
+
+	assert.Equal(t, []parameter.ParameterReference{
+		{Config: coordinate.Coordinate{Project: "project", Type: "management-zone", ConfigId: "zone-a"}, Property: "id"},
+		{Config: coord, Property: "baseZones"},
+	}, listParam.GetReferences())
+}
+
+func TestParseListParameter_InvalidEntries(t *testing.T) {
+	tests := []struct {
+		name          string
+		entry         map[interface{}]interface{}
+		expectedError string
+	}{
+		{"unknown type", map[interface{}]interface{}{"type": "environment", "name": "X"}, "unsupported list entry type `environment`"},
+		{"included list without name", map[interface{}]interface{}{"type": "list"}, "missing property `name`"},
+		{"list including itself", map[interface{}]interface{}{"type": "list", "name": "zones"}, "list parameter `zones` cannot include itself"},
+		{"invalid reference", map[interface{}]interface{}{"type": "reference", "configId": "x"}, "missing `property`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseListParameter(parameter.ParameterParserContext{
+				ParameterName: "zones",
+				Value:         map[string]interface{}{"values": []interface{}{tt.entry}},
+			})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+type propertyResolver map[coordinate.Coordinate]map[string]any
+
+func (r propertyResolver) GetResolvedProperty(c coordinate.Coordinate, propertyName string) (any, bool) {
+	v, found := r[c][propertyName]
+	return v, found
+}
+
+func TestResolveValue_ReferencesAndIncludedLists(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+	zone := coordinate.Coordinate{Project: "project", Type: "management-zone", ConfigId: "zone-a"}
+
+	context := parameter.ResolveContext{
+		ConfigCoordinate: coord,
+		PropertyResolver: propertyResolver{zone: {"id": "-123456"}},
+		ResolvedParameterValues: parameter.Properties{
+			"baseZones": `[ "MZ-2","MZ-\"3\"" ]`,
+			"empty":     `[  ]`,
+			"name":      "not a list",
+		},
+	}
+
+	result, err := New([]parameter.Parameter{
+		&value.ValueParameter{Value: "MZ-1"},
+		reference.NewWithCoordinate(zone, "id"),
+		&IncludedList{ParameterReference: parameter.ParameterReference{Config: coord, Property: "baseZones"}},
+		&IncludedList{ParameterReference: parameter.ParameterReference{Config: coord, Property: "empty"}},
+	}).ResolveValue(context)
+	require.NoError(t, err)
+	assert.Equal(t, `[ "MZ-1","-123456","MZ-2","MZ-\"3\"" ]`, result)
+
+	_, err = New([]parameter.Parameter{
+		&IncludedList{ParameterReference: parameter.ParameterReference{Config: coord, Property: "name"}},
+	}).ResolveValue(context)
+	assert.ErrorContains(t, err, "included parameter `name` is no list parameter")
+
+	_, err = New([]parameter.Parameter{
+		&IncludedList{ParameterReference: parameter.ParameterReference{Config: coord, Property: "missing"}},
+	}).ResolveValue(context)
+	assert.ErrorContains(t, err, "included list parameter `missing` has not been resolved yet or does not exist")
+}
+
+func Test_writeListParameter_ReferencesAndIncludedLists(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+
+	got, err := writeListParameter(parameter.ParameterWriterContext{
+		Coordinate: coord,
+		Parameter: New([]parameter.Parameter{
+			&value.ValueParameter{Value: "MZ-1"},
+			reference.New("project", "management-zone", "zone-a", "id"),
+			&IncludedList{ParameterReference: parameter.ParameterReference{Config: coord, Property: "baseZones"}},
+		}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"values": []interface{}{
+			"MZ-1",
+			map[string]interface{}{"type": "reference", "configType": "management-zone", "configId": "zone-a", "property": "id"},
+			map[string]interface{}{"type": "list", "name": "baseZones"},
+		},
+	}, got)
+}
@@ -0,0 +1,221 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package managementzone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	stringutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"golang.org/x/sync/singleflight"
+)
+
+// ManagementZoneParameterType specifies the type of the parameter used in config files
+const ManagementZoneParameterType = "managementZone"
+
+var ManagementZoneParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeManagementZoneParameter,
+	Deserializer: parseManagementZoneParameter,
+}
+
+// managementZonesPath is the path of the classic Management Zones API listing the IDs and names of all zones
+const managementZonesPath = "/api/config/v1/managementZones"
+
+// requestTimeout limits the duration of a single management zones request
+const requestTimeout = 30 * time.Second
+
+// maxErrorBodyLength limits how much of the body of a failed response is reported
+const maxErrorBodyLength = 500
+
+// ManagementZoneParameter defines a parameter which resolves to the ID of the management zone with the given name at
+// resolve time - e.g. to scope settings to a management zone that is not managed by monaco. Exactly one management
+// zone of the environment the config is deployed to has to have the name.
+type ManagementZoneParameter struct {
+	// Name of the management zone
+	Name string
+
+	// environmentURL and environmentToken are used to query the environment's API
+	environmentURL   string
+	environmentToken string
+}
+
+func New(name string) *ManagementZoneParameter {
+	return &ManagementZoneParameter{Name: name}
+}
+
+// this forces the compiler to check if ManagementZoneParameter is of type Parameter
+var _ parameter.Parameter = (*ManagementZoneParameter)(nil)
+
+func (p *ManagementZoneParameter) GetType() string {
+	return ManagementZoneParameterType
+}
+
+func (p *ManagementZoneParameter) GetReferences() []parameter.ParameterReference {
+	// management zone parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *ManagementZoneParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if p.environmentURL == "" {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("cannot look up management zone %q: the environment has no URL", p.Name))
+	}
+	if p.environmentToken == "" {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("cannot look up management zone %q: the environment has no API token", p.Name))
+	}
+
+	zones, err := defaultCache.get(environment{url: strings.TrimSuffix(p.environmentURL, "/"), token: p.environmentToken})
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to look up management zone %q: %s", p.Name, err))
+	}
+
+	var ids []string
+	for _, z := range zones {
+		if z.Name == p.Name {
+			ids = append(ids, z.ID)
+		}
+	}
+	if len(ids) != 1 {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("%d management zones are named %q, but exactly one must be", len(ids), p.Name))
+	}
+
+	return template.EscapeSpecialCharactersInValue(ids[0], template.FullStringEscapeFunction)
+}
+
+// environment identifies the environment management zones are looked up in
+type environment struct {
+	url   string
+	token string
+}
+
+// zone is a management zone as listed by the Management Zones API
+type zone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// zoneCache caches the management zones of environments, so that the zones of each environment are only listed once,
+// no matter how many configs use them. Failed requests are cached as well, so that each failure is only caused once.
+type zoneCache struct {
+	client   *http.Client
+	mu       sync.Mutex
+	results  map[environment]cachedResult
+	inflight singleflight.Group
+}
+
+type cachedResult struct {
+	zones []zone
+	err   error
+}
+
+var defaultCache = newZoneCache(&http.Client{Timeout: requestTimeout})
+
+func newZoneCache(client *http.Client) *zoneCache {
+	return &zoneCache{client: client, results: make(map[environment]cachedResult)}
+}
+
+func (c *zoneCache) get(env environment) ([]zone, error) {
+	c.mu.Lock()
+	cached, found := c.results[env]
+	c.mu.Unlock()
+	if found {
+		return cached.zones, cached.err
+	}
+
+	zones, err, _ := c.inflight.Do(env.url+"\n"+env.token, func() (any, error) {
+		zones, err := c.list(env)
+
+		c.mu.Lock()
+		c.results[env] = cachedResult{zones: zones, err: err}
+		c.mu.Unlock()
+		return zones, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return zones.([]zone), nil
+}
+
+func (c *zoneCache) list(env environment) ([]zone, error) {
+	req, err := http.NewRequest(http.MethodGet, env.url+managementZonesPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Api-Token "+env.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > maxErrorBodyLength {
+			msg = msg[:maxErrorBodyLength] + "..."
+		}
+		return nil, fmt.Errorf("HTTP status %d: %s", resp.StatusCode, msg)
+	}
+
+	var result struct {
+		Values []zone `json:"values"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("response is no valid JSON: %w", err)
+	}
+	return result.Values, nil
+}
+
+// parseManagementZoneParameter parses a ManagementZoneParameter from a given context.
+// it requires the `name` field to be set.
+func parseManagementZoneParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	name, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+	if strings.TrimSpace(stringutils.ToString(name)) == "" {
+		return nil, parameter.NewParameterParserError(context, "property `name` must not be empty")
+	}
+
+	p := New(stringutils.ToString(name))
+	p.environmentURL = context.EnvironmentURL
+	p.environmentToken = context.EnvironmentToken
+	return p, nil
+}
+
+func writeManagementZoneParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	zoneParam, ok := context.Parameter.(*ManagementZoneParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `ManagementZoneParameter`")
+	}
+
+	return map[string]interface{}{
+		"name": zoneParam.Name,
+	}, nil
+}
@@ -0,0 +1,116 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package managementzone
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManagementZoneParameter(t *testing.T) {
+	p, err := parseManagementZoneParameter(parameter.ParameterParserContext{
+		Value:            map[string]any{"name": "Team Checkout"},
+		EnvironmentURL:   "https://example.com",
+		EnvironmentToken: "dt0c01.token",
+	})
+	require.NoError(t, err)
+
+	zoneParam := p.(*ManagementZoneParameter)
+	assert.Equal(t, "Team Checkout", zoneParam.Name)
+	assert.Equal(t, "https://example.com", zoneParam.environmentURL)
+	assert.Equal(t, "dt0c01.token", zoneParam.environmentToken)
+	assert.Equal(t, ManagementZoneParameterType, p.GetType())
+	assert.Empty(t, p.GetReferences())
+}
+
+func TestParseManagementZoneParameter_Errors(t *testing.T) {
+	_, err := parseManagementZoneParameter(parameter.ParameterParserContext{Value: map[string]any{}})
+	assert.ErrorContains(t, err, "missing property `name`")
+
+	_, err = parseManagementZoneParameter(parameter.ParameterParserContext{Value: map[string]any{"name": " "}})
+	assert.ErrorContains(t, err, "property `name` must not be empty")
+}
+
+func TestWriteManagementZoneParameter(t *testing.T) {
+	result, err := writeManagementZoneParameter(parameter.ParameterWriterContext{Parameter: New("Team Checkout")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Team Checkout"}, result)
+
+	_, err = writeManagementZoneParameter(parameter.ParameterWriterContext{Parameter: &parameter.DummyParameter{}})
+	assert.IsType(t, &parameter.ParameterWriterError{}, err)
+}
+
+func TestResolveValue(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/api/config/v1/managementZones" || r.Header.Get("Authorization") != "Api-Token dt0c01.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"values": [{"id": "123", "name": "Team Checkout"}, {"id": "456", "name": "Team Search"}, {"id": "789", "name": "Team Search"}]}`))
+	}))
+	defer server.Close()
+
+	newParam := func(name string, token string) *ManagementZoneParameter {
+		p := New(name)
+		p.environmentURL = server.URL + "/"
+		p.environmentToken = token
+		return p
+	}
+	context := parameter.ResolveContext{ParameterName: "scope"}
+
+	t.Run("unique name", func(t *testing.T) {
+		v, err := newParam("Team Checkout", "dt0c01.token").ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, "123", v)
+	})
+
+	t.Run("zones are cached", func(t *testing.T) {
+		before := calls.Load()
+		_, err := newParam("Team Checkout", "dt0c01.token").ResolveValue(context)
+		require.NoError(t, err)
+		assert.Equal(t, before, calls.Load())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			param         *ManagementZoneParameter
+			expectedError string
+		}{
+			{"unknown name", newParam("Team Unknown", "dt0c01.token"), `0 management zones are named "Team Unknown", but exactly one must be`},
+			{"ambiguous name", newParam("Team Search", "dt0c01.token"), `2 management zones are named "Team Search", but exactly one must be`},
+			{"failed request", newParam("Team Checkout", "dt0c01.invalid"), "HTTP status 401"},
+			{"missing token", newParam("Team Checkout", ""), "the environment has no API token"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.param.ResolveValue(context)
+				assert.ErrorContains(t, err, tt.expectedError)
+			})
+		}
+	})
+}
@@ -30,6 +30,14 @@ type PropertyResolver interface {
 	GetResolvedProperty(coordinate coordinate.Coordinate, propertyName string) (any, bool)
 }
 
+// EnvironmentPropertyResolver is used in parameter resolution to fetch the values of configs deployed to other
+// environments of the same deployment
+type EnvironmentPropertyResolver interface {
+	// GetResolvedPropertyOfEnvironment returns the value of the property of the config deployed to the given
+	// environment, or an error describing why it is not available.
+	GetResolvedPropertyOfEnvironment(environment string, coordinate coordinate.Coordinate, propertyName string) (any, error)
+}
+
 // ResolveContext used to give some more information on the resolving phase
 type ResolveContext struct {
 	PropertyResolver PropertyResolver
@@ -48,6 +56,9 @@ type ResolveContext struct {
 
 	// resolved values of the current config
 	ResolvedParameterValues Properties
+
+	// EnvironmentResolver resolves values of configs deployed to other environments. It is only set during deployments.
+	EnvironmentResolver EnvironmentPropertyResolver
 }
 
 type Parameter interface {
@@ -88,6 +99,15 @@ type ParameterParserContext struct {
 	Fs            afero.Fs
 	Value         map[string]interface {
 	}
+
+	// EnvironmentParameters are the values of the environment parameters defined in the manifest for the environment
+	// the config is loaded for
+	EnvironmentParameters map[string]any
+
+	// EnvironmentURL and EnvironmentToken are the URL and API token of the environment the config is loaded for. They
+	// are used by parameters calling the environment's API.
+	EnvironmentURL   string
+	EnvironmentToken string
 }
 
 type ParameterParserError struct {
@@ -110,12 +110,59 @@ func (p *ReferenceParameter) ResolveValue(context parameter.ResolveContext) (int
 	return nil, newUnresolvedReferenceError(context, p.ParameterReference, "config has not been resolved yet or does not exist")
 }
 
+// NameReferenceParameter is a reference to a config of the same project, which is identified by its type and name
+// instead of its config ID. It cannot be resolved itself, but is replaced by a ReferenceParameter to the config of that
+// name when the project is loaded.
+type NameReferenceParameter struct {
+	// ConfigType is the type of the referenced config
+	ConfigType string
+
+	// Name is the name of the referenced config
+	Name string
+
+	// Property is the referenced property of the config
+	Property string
+}
+
+func NewByName(configType string, name string, property string) *NameReferenceParameter {
+	return &NameReferenceParameter{
+		ConfigType: configType,
+		Name:       name,
+		Property:   property,
+	}
+}
+
+// this forces the compiler to check if NameReferenceParameter is of type Parameter
+var _ parameter.Parameter = (*NameReferenceParameter)(nil)
+
+func (p *NameReferenceParameter) GetType() string {
+	return ReferenceParameterType
+}
+
+func (p *NameReferenceParameter) GetReferences() []parameter.ParameterReference {
+	// the referenced config is only known once the reference has been replaced when loading the project
+	return []parameter.ParameterReference{}
+}
+
+func (p *NameReferenceParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("reference to the %s config named %q has not been replaced by a reference to its config ID", p.ConfigType, p.Name))
+}
+
 const projectField = "project"
 const typeField = "configType"
 const idField = "configId"
+const nameField = "name"
 const propertyField = "property"
 
 func writeReferenceParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	if nameRef, ok := context.Parameter.(*NameReferenceParameter); ok {
+		return map[string]interface{}{
+			typeField:     nameRef.ConfigType,
+			nameField:     nameRef.Name,
+			propertyField: nameRef.Property,
+		}, nil
+	}
+
 	refParam, ok := context.Parameter.(*ReferenceParameter)
 
 	if !ok {
@@ -148,7 +195,14 @@ func writeReferenceParameter(context parameter.ParameterWriterContext) (map[stri
 // it requires at least a `property` config value. All other values (project, type, config)
 // will be filled in from the current context if missing.  it is not allowed to leave
 // for example only `type` empty.
+//
+// instead of `configId`, the referenced config can be identified by its `name`, in which case
+// a NameReferenceParameter is returned.
 func parseReferenceParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	if _, ok := context.Value[nameField]; ok {
+		return parseNameReferenceParameter(context)
+	}
+
 	project := context.Coordinate.Project
 	configType := context.Coordinate.Type
 	config := context.Coordinate.ConfigId
@@ -196,3 +250,26 @@ func newUnresolvedReferenceError(context parameter.ResolveContext, reference par
 		ParameterReference:         reference,
 	}
 }
+
+// parseNameReferenceParameter parses a NameReferenceParameter from a given context. it requires the `configType`,
+// `name` and `property` config values. As the name is resolved within the project, `project` and `configId` must
+// not be set.
+func parseNameReferenceParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	for _, field := range []string{projectField, idField} {
+		if _, ok := context.Value[field]; ok {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("`%s` and `%s` cannot both be set! references by `%s` are resolved within the project", nameField, field, nameField))
+		}
+	}
+
+	configType, ok := context.Value[typeField]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("`%s` is set, but `%s` isn't! please specify `%s`", nameField, typeField, typeField))
+	}
+
+	property, ok := context.Value[propertyField]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("missing `%s` - please specifiy which %s should be referenced", propertyField, propertyField))
+	}
+
+	return NewByName(strings.ToString(configType), strings.ToString(context.Value[nameField]), strings.ToString(property)), nil
+}
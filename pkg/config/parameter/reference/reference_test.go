@@ -527,3 +527,51 @@ func TestWriteCompoundParameterErrorOnNonCompoundParameter(t *testing.T) {
 	_, err := writeReferenceParameter(context)
 	require.Error(t, err, "expected an error writing wrong parameter type")
 }
+
+func TestParseReferenceParameterByName(t *testing.T) {
+	param, err := parseReferenceParameter(parameter.ParameterParserContext{
+		Coordinate: coordinate.Coordinate{Project: "projectA", Type: "dashboard", ConfigId: "dashboard"},
+		Value: map[string]interface{}{
+			"configType": "management-zone",
+			"name":       "Production",
+			"property":   "id",
+		},
+	})
+	require.NoError(t, err)
+
+	nameRef, ok := param.(*NameReferenceParameter)
+	require.True(t, ok, "parsed parameter should be name reference parameter")
+	assert.Equal(t, "reference", nameRef.GetType())
+	assert.Empty(t, nameRef.GetReferences())
+	assert.Equal(t, NewByName("management-zone", "Production", "id"), nameRef)
+
+	_, err = nameRef.ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, `reference to the management-zone config named "Production" has not been replaced`)
+}
+
+func TestParseReferenceParameterByNameErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{"missing type", map[string]interface{}{"name": "Production", "property": "id"}, "`name` is set, but `configType` isn't"},
+		{"missing property", map[string]interface{}{"name": "Production", "configType": "management-zone"}, "missing `property`"},
+		{"config id set", map[string]interface{}{"name": "Production", "configType": "management-zone", "configId": "mz", "property": "id"}, "`name` and `configId` cannot both be set"},
+		{"project set", map[string]interface{}{"name": "Production", "configType": "management-zone", "project": "other", "property": "id"}, "`name` and `project` cannot both be set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseReferenceParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestWriteReferenceParameterByName(t *testing.T) {
+	result, err := writeReferenceParameter(parameter.ParameterWriterContext{
+		Parameter: NewByName("management-zone", "Production", "id"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"configType": "management-zone", "name": "Production", "property": "id"}, result)
+}
@@ -0,0 +1,112 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AzureKeyVaultProvider fetches secrets from Azure Key Vault. The name of a secret is either '<vault-name>/<secret-name>',
+// optionally followed by '/<version>', or the full secret identifier - e.g. 'https://my-vault.vault.azure.net/secrets/my-secret'.
+//
+// The service principal used to authenticate is read from the environment variables AZURE_TENANT_ID, AZURE_CLIENT_ID and
+// AZURE_CLIENT_SECRET. AZURE_AUTHORITY_HOST optionally overrides the Microsoft Entra endpoint to authenticate with.
+type AzureKeyVaultProvider struct{}
+
+const (
+	azureKeyVaultAPIVersion    = "7.4"
+	azureKeyVaultScope         = "https://vault.azure.net/.default"
+	azureDefaultAuthorityHost  = "https://login.microsoftonline.com"
+	azureKeyVaultDomainPattern = "https://%s.vault.azure.net/secrets/%s"
+)
+
+func (AzureKeyVaultProvider) GetSecret(name string) (string, error) {
+	secretURL, err := azureSecretURL(name)
+	if err != nil {
+		return "", err
+	}
+
+	tenant, clientID, clientSecret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+	if tenant == "" || clientID == "" || clientSecret == "" {
+		return "", errors.New("environment variables `AZURE_TENANT_ID`, `AZURE_CLIENT_ID` and `AZURE_CLIENT_SECRET` must be set")
+	}
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = azureDefaultAuthorityHost
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authority, "/"), tenant),
+		Scopes:       []string{azureKeyVaultScope},
+		AuthStyle:    oauth2.AuthStyleInParams,
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: httpTimeout})
+	client := credentials.Client(ctx)
+	client.Timeout = httpTimeout
+
+	req, err := http.NewRequest(http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	body, err := doSecretRequest(client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Value *string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resp.Value == nil {
+		return "", errors.New("response holds no secret value")
+	}
+	return *resp.Value, nil
+}
+
+// azureSecretURL returns the URL to fetch the secret with the given name from
+func azureSecretURL(name string) (string, error) {
+	if !strings.HasPrefix(name, "https://") && !strings.HasPrefix(name, "http://") {
+		parts := strings.Split(name, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return "", fmt.Errorf("invalid secret name %q - expected '<vault-name>/<secret-name>[/<version>]' or a secret identifier", name)
+		}
+		name = fmt.Sprintf(azureKeyVaultDomainPattern, parts[0], strings.Join(parts[1:], "/"))
+	}
+
+	u, err := url.Parse(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret identifier %q: %w", name, err)
+	}
+	q := u.Query()
+	q.Set("api-version", azureKeyVaultAPIVersion)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
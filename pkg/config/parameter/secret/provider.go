@@ -0,0 +1,147 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/sync/singleflight"
+)
+
+// Provider fetches secrets from a secret store
+type Provider interface {
+	// GetSecret returns the value of the secret with the given name
+	GetSecret(name string) (string, error)
+}
+
+// ProviderFunc is a function implementing [Provider]
+type ProviderFunc func(name string) (string, error)
+
+func (f ProviderFunc) GetSecret(name string) (string, error) {
+	return f(name)
+}
+
+// Registry holds the providers secret parameters can fetch their values from. Fetched secrets are cached, so that each
+// secret is only fetched once, no matter how many configs and environments use it.
+type Registry struct {
+	mu        sync.Mutex
+	providers map[string]Provider
+	cache     map[cacheKey]string
+	inflight  singleflight.Group
+}
+
+type cacheKey struct {
+	provider, name string
+}
+
+// NewRegistry returns a [Registry] holding the given providers by their name
+func NewRegistry(providers map[string]Provider) *Registry {
+	return &Registry{
+		providers: maps.Clone(providers),
+		cache:     make(map[cacheKey]string),
+	}
+}
+
+// DefaultRegistry is the [Registry] used when resolving secret parameters. It holds the providers
+//   - 'environment' - reads environment variables
+//   - 'file' - reads files, e.g. secrets mounted into a container
+//   - 'vault' - reads secrets from HashiCorp Vault, see [VaultProvider]
+//   - 'awsSecretsManager' - reads secrets from AWS Secrets Manager, see [AWSSecretsManagerProvider]
+//   - 'azureKeyVault' - reads secrets from Azure Key Vault, see [AzureKeyVaultProvider]
+var DefaultRegistry = NewRegistry(map[string]Provider{
+	"environment":       ProviderFunc(getEnvironmentSecret),
+	"file":              ProviderFunc(getFileSecret),
+	"vault":             VaultProvider{},
+	"awsSecretsManager": AWSSecretsManagerProvider{},
+	"azureKeyVault":     AzureKeyVaultProvider{},
+})
+
+// Register adds the given provider to the registry, replacing any provider of the same name
+func (r *Registry) Register(name string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = provider
+	for k := range r.cache {
+		if k.provider == name {
+			delete(r.cache, k)
+		}
+	}
+}
+
+// Providers returns the sorted names of all registered providers
+func (r *Registry) Providers() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := maps.Keys(r.providers)
+	slices.Sort(names)
+	return names
+}
+
+// Resolve returns the secret with the given name from the provider with the given name. Each secret is only fetched
+// once, subsequent calls return the cached value. Concurrent calls for the same secret wait for a single fetch, while
+// other secrets are fetched in parallel.
+func (r *Registry) Resolve(provider string, name string) (string, error) {
+	key := cacheKey{provider: provider, name: name}
+
+	r.mu.Lock()
+	v, found := r.cache[key]
+	p, known := r.providers[provider]
+	r.mu.Unlock()
+	if found {
+		return v, nil
+	}
+	if !known {
+		return "", fmt.Errorf("unknown secret provider `%s`", provider)
+	}
+
+	fetched, err, _ := r.inflight.Do(provider+"\x00"+name, func() (any, error) {
+		v, err := p.GetSecret(name)
+		if err != nil {
+			return "", err
+		}
+
+		r.mu.Lock()
+		r.cache[key] = v
+		r.mu.Unlock()
+		return v, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fetched.(string), nil
+}
+
+func getEnvironmentSecret(name string) (string, error) {
+	v, found := os.LookupEnv(name)
+	if !found {
+		return "", fmt.Errorf("environment variable `%s` not set", name)
+	}
+	return v, nil
+}
+
+func getFileSecret(name string) (string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,227 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Resolve(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	r := NewRegistry(map[string]Provider{
+		"slow": ProviderFunc(func(name string) (string, error) {
+			calls.Add(1)
+			<-release
+			return "slow-" + name, nil
+		}),
+		"fast": ProviderFunc(func(name string) (string, error) {
+			return "fast-" + name, nil
+		}),
+	})
+
+	var wg sync.WaitGroup
+	results := make([]string, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = r.Resolve("slow", "secret")
+		}()
+	}
+
+	v, err := r.Resolve("fast", "secret")
+	require.NoError(t, err, "other secrets must be resolved while a secret is being fetched")
+	assert.Equal(t, "fast-secret", v)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"slow-secret", "slow-secret", "slow-secret"}, results)
+	assert.LessOrEqual(t, calls.Load(), int32(3))
+
+	calls.Store(0)
+	v, err = r.Resolve("slow", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "slow-secret", v)
+	assert.Zero(t, calls.Load(), "resolved secrets must be cached")
+
+	_, err = r.Resolve("unknown", "secret")
+	assert.ErrorContains(t, err, "unknown secret provider `unknown`")
+}
+
+func TestEnvironmentAndFileProviders(t *testing.T) {
+	t.Setenv("MY_SECRET", "from-env")
+	v, err := getEnvironmentSecret("MY_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", v)
+
+	_, err = getEnvironmentSecret("MONACO_UNDEFINED_SECRET")
+	assert.ErrorContains(t, err, "environment variable `MONACO_UNDEFINED_SECRET` not set")
+
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0600))
+	v, err = getFileSecret(file)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", v)
+
+	_, err = getFileSecret(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "failed to read file")
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/monaco":
+			_, _ = w.Write([]byte(`{"data": {"data": {"password": "kv2"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/monaco":
+			_, _ = w.Write([]byte(`{"data": {"password": "kv1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	v, err := VaultProvider{}.GetSecret("secret/data/monaco")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password": "kv2"}`, v)
+
+	v, err = VaultProvider{}.GetSecret("kv/monaco")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password": "kv1"}`, v)
+
+	_, err = VaultProvider{}.GetSecret("missing")
+	assert.ErrorContains(t, err, "request failed with HTTP status 404")
+
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = VaultProvider{}.GetSecret("kv/monaco")
+	assert.ErrorContains(t, err, "environment variable `VAULT_TOKEN` not set")
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "session-token", r.Header.Get("X-Amz-Security-Token"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access-key/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request")
+
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch req["SecretId"] {
+		case "monaco":
+			_, _ = w.Write([]byte(`{"Name": "monaco", "SecretString": "{\"password\": \"aws\"}"}`))
+		case "binary":
+			_, _ = w.Write([]byte(`{"Name": "binary", "SecretBinary": "YmluYXJ5"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret-key")
+	t.Setenv("AWS_SESSION_TOKEN", "session-token")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+
+	v, err := AWSSecretsManagerProvider{}.GetSecret("monaco")
+	require.NoError(t, err)
+	assert.Equal(t, `{"password": "aws"}`, v)
+
+	v, err = AWSSecretsManagerProvider{}.GetSecret("binary")
+	require.NoError(t, err)
+	assert.Equal(t, "binary", v)
+
+	_, err = AWSSecretsManagerProvider{}.GetSecret("missing")
+	assert.ErrorContains(t, err, "request failed with HTTP status 400")
+}
+
+func TestSignAWSRequest(t *testing.T) {
+	// "get-vanilla" example of the AWS Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	signAWSRequest(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+func TestAzureKeyVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tenant/oauth2/v2.0/token":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client-id", r.Form.Get("client_id"))
+			assert.Equal(t, "https://vault.azure.net/.default", r.Form.Get("scope"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "azure-token", "token_type": "Bearer", "expires_in": 3600}`))
+		case "/secrets/monaco":
+			assert.Equal(t, "Bearer azure-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "7.4", r.URL.Query().Get("api-version"))
+			_, _ = w.Write([]byte(`{"value": "azure", "id": "https://vault/secrets/monaco/1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "client-id")
+	t.Setenv("AZURE_CLIENT_SECRET", "client-secret")
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+
+	v, err := AzureKeyVaultProvider{}.GetSecret(server.URL + "/secrets/monaco")
+	require.NoError(t, err)
+	assert.Equal(t, "azure", v)
+
+	_, err = AzureKeyVaultProvider{}.GetSecret(server.URL + "/secrets/missing")
+	assert.ErrorContains(t, err, "request failed with HTTP status 404")
+}
+
+func Test_azureSecretURL(t *testing.T) {
+	u, err := azureSecretURL("my-vault/my-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "https://my-vault.vault.azure.net/secrets/my-secret?api-version=7.4", u)
+
+	u, err = azureSecretURL("my-vault/my-secret/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://my-vault.vault.azure.net/secrets/my-secret/v1?api-version=7.4", u)
+
+	_, err = azureSecretURL("my-secret")
+	assert.ErrorContains(t, err, "invalid secret name")
+}
@@ -0,0 +1,160 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	maskedsecret "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	stringutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+// SecretParameterType specifies the type of the parameter used in config files
+const SecretParameterType = "secret"
+
+var SecretParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeSecretParameter,
+	Deserializer: parseSecretParameter,
+}
+
+// SecretParameter defines a parameter which fetches its value from an external secret store at deploy time. The value is
+// masked in all logs, and only the reference to the secret is ever written to config files.
+type SecretParameter struct {
+	// Provider is the name of the [Provider] in the [DefaultRegistry] the secret is fetched from
+	Provider string
+
+	// Name identifies the secret in the secret store of the provider - e.g. the name of an environment variable, or the
+	// path of a Vault secret
+	Name string
+
+	// Key is optional. If set, the secret is expected to be a JSON object, and the value of this key is used.
+	Key string
+}
+
+func New(provider string, name string, key string) *SecretParameter {
+	return &SecretParameter{
+		Provider: provider,
+		Name:     name,
+		Key:      key,
+	}
+}
+
+// this forces the compiler to check if SecretParameter is of type CacheableParameter
+var _ parameter.CacheableParameter = (*SecretParameter)(nil)
+
+func (p *SecretParameter) GetType() string {
+	return SecretParameterType
+}
+
+func (p *SecretParameter) GetReferences() []parameter.ParameterReference {
+	// secret parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *SecretParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := DefaultRegistry.Resolve(p.Provider, p.Name)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to fetch secret `%s` from provider `%s`: %s", p.Name, p.Provider, err))
+	}
+
+	if p.Key != "" {
+		if val, err = extractKey(val, p.Key); err != nil {
+			return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to read key `%s` of secret `%s` from provider `%s`: %s", p.Key, p.Name, p.Provider, err))
+		}
+	}
+
+	maskedsecret.RegisterMaskedValue(val)
+	escaped, err := template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+	if err != nil {
+		return nil, err
+	}
+
+	// the escaped value is rendered into templates and thus may appear in logs of request payloads as well
+	if s, ok := escaped.(string); ok {
+		maskedsecret.RegisterMaskedValue(s)
+	}
+	return escaped, nil
+}
+
+// CacheKey identifies the parameter by the secret it fetches
+func (p *SecretParameter) CacheKey() (string, bool) {
+	return fmt.Sprintf("%s|%s|%s", p.Provider, p.Name, p.Key), true
+}
+
+// extractKey returns the value of the given key of a secret holding a JSON object
+func extractKey(secret string, key string) (string, error) {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(secret), &obj); err != nil {
+		return "", errors.New("secret is no JSON object")
+	}
+
+	v, found := obj[key]
+	if !found {
+		return "", fmt.Errorf("secret has no key `%s`", key)
+	}
+	return stringutils.ToString(v), nil
+}
+
+// parseSecretParameter parses a SecretParameter from a given context.
+// it requires the `provider` and `name` fields to be set. `key` is an optional field.
+func parseSecretParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	rawProvider, ok := context.Value["provider"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("missing property `provider` - must be one of: %s", strings.Join(DefaultRegistry.Providers(), ", ")))
+	}
+	provider := stringutils.ToString(rawProvider)
+	if !slices.Contains(DefaultRegistry.Providers(), provider) {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("unknown secret provider `%s` - must be one of: %s", provider, strings.Join(DefaultRegistry.Providers(), ", ")))
+	}
+
+	rawName, ok := context.Value["name"]
+	if !ok || stringutils.ToString(rawName) == "" {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+
+	key := ""
+	if rawKey, ok := context.Value["key"]; ok {
+		key = stringutils.ToString(rawKey)
+	}
+
+	return New(provider, stringutils.ToString(rawName), key), nil
+}
+
+func writeSecretParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	secretParam, ok := context.Parameter.(*SecretParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `SecretParameter`")
+	}
+
+	result := map[string]interface{}{
+		"provider": secretParam.Provider,
+		"name":     secretParam.Name,
+	}
+
+	if secretParam.Key != "" {
+		result["key"] = secretParam.Key
+	}
+
+	return result, nil
+}
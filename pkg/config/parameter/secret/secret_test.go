@@ -0,0 +1,134 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"errors"
+	"testing"
+
+	maskedsecret "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretParameter(t *testing.T) {
+	param, err := parseSecretParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"provider": "vault",
+			"name":     "secret/data/monaco",
+			"key":      "password",
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, New("vault", "secret/data/monaco", "password"), param)
+	assert.Equal(t, "secret", param.GetType())
+	assert.Empty(t, param.GetReferences())
+}
+
+func TestParseSecretParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         map[string]interface{}
+		expectedError string
+	}{
+		{
+			"missing provider",
+			map[string]interface{}{"name": "n"},
+			"missing property `provider` - must be one of: awsSecretsManager, azureKeyVault, environment, file, vault",
+		},
+		{
+			"unknown provider",
+			map[string]interface{}{"provider": "unknown", "name": "n"},
+			"unknown secret provider `unknown`",
+		},
+		{
+			"missing name",
+			map[string]interface{}{"provider": "environment"},
+			"missing property `name`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSecretParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestWriteSecretParameter(t *testing.T) {
+	result, err := writeSecretParameter(parameter.ParameterWriterContext{Parameter: New("environment", "TOKEN", "")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"provider": "environment", "name": "TOKEN"}, result)
+
+	result, err = writeSecretParameter(parameter.ParameterWriterContext{Parameter: New("vault", "secret/data/monaco", "password")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"provider": "vault", "name": "secret/data/monaco", "key": "password"}, result)
+}
+
+func TestResolveValue(t *testing.T) {
+	calls := 0
+	DefaultRegistry.Register("test", ProviderFunc(func(name string) (string, error) {
+		calls++
+		switch name {
+		case "plain":
+			return `my "secret" value`, nil
+		case "json":
+			return `{"user": "admin", "password": "pa55word-from-json", "port": 8080}`, nil
+		}
+		return "", errors.New("not found")
+	}))
+
+	t.Run("value is escaped and masked in logs", func(t *testing.T) {
+		result, err := New("test", "plain", "").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		require.NoError(t, err)
+		assert.Equal(t, `my \"secret\" value`, result)
+		assert.Equal(t, "token: ****", maskedsecret.Mask(`token: my "secret" value`))
+		assert.Equal(t, `{"token": "****"}`, maskedsecret.Mask(`{"token": "my \"secret\" value"}`), "rendered payloads must be masked as well")
+	})
+
+	t.Run("key of a JSON secret", func(t *testing.T) {
+		result, err := New("test", "json", "password").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		require.NoError(t, err)
+		assert.Equal(t, "pa55word-from-json", result)
+
+		result, err = New("test", "json", "port").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		require.NoError(t, err)
+		assert.Equal(t, "8080", result)
+	})
+
+	t.Run("secrets are fetched once", func(t *testing.T) {
+		calls = 0
+		_, err := New("test", "json", "user").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		require.NoError(t, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := New("test", "missing", "").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		assert.ErrorContains(t, err, "failed to fetch secret `missing` from provider `test`: not found")
+
+		_, err = New("test", "json", "missing").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		assert.ErrorContains(t, err, "secret has no key `missing`")
+
+		_, err = New("test", "plain", "key").ResolveValue(parameter.ResolveContext{ParameterName: "p"})
+		assert.ErrorContains(t, err, "secret is no JSON object")
+	})
+}
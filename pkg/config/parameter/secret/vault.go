@@ -0,0 +1,101 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// httpTimeout limits the duration of a single request to a secret store
+const httpTimeout = 30 * time.Second
+
+// VaultProvider fetches secrets from HashiCorp Vault. The name of a secret is its API path without the '/v1/' prefix,
+// e.g. 'secret/data/monaco' for the secret 'monaco' of a KV version 2 secrets engine mounted at 'secret'. As Vault
+// secrets hold key-value pairs, they are returned as JSON object - use the 'key' of the parameter to select a value.
+//
+// The address and token of Vault are read from the environment variables VAULT_ADDR and VAULT_TOKEN. If set, the
+// Vault Enterprise namespace is read from VAULT_NAMESPACE.
+type VaultProvider struct{}
+
+func (VaultProvider) GetSecret(name string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", errors.New("environment variable `VAULT_ADDR` not set")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return "", errors.New("environment variable `VAULT_TOKEN` not set")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(name, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	body, err := doSecretRequest(&http.Client{Timeout: httpTimeout}, req)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resp.Data == nil {
+		return "", errors.New("response holds no data")
+	}
+
+	// secrets of KV version 2 engines hold the actual key-value pairs in a nested 'data' object
+	_, hasMetadata := resp.Data["metadata"]
+	if nested, hasData := resp.Data["data"]; hasData && hasMetadata {
+		return string(nested), nil
+	}
+
+	b, err := json.Marshal(resp.Data)
+	return string(b), err
+}
+
+// doSecretRequest sends the request and returns the response body, or an error if the request did not succeed
+func doSecretRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("request failed with HTTP status %d", resp.StatusCode)
+	}
+	return body, nil
+}
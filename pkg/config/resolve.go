@@ -32,6 +32,11 @@ func resolveValues(
 	var errors []error
 
 	properties := make(parameter.Properties)
+	environmentResolver, _ := entities.(parameter.EnvironmentPropertyResolver)
+	var valueCache *parameter.ValueCache
+	if provider, ok := entities.(parameter.ValueCacheProvider); ok {
+		valueCache = provider.ParameterValueCache()
+	}
 
 	for _, container := range parameters {
 		name := container.Name
@@ -44,20 +49,35 @@ func resolveValues(
 			continue
 		}
 
-		val, err := param.ResolveValue(parameter.ResolveContext{
+		context := parameter.ResolveContext{
 			PropertyResolver:        entities,
 			ConfigCoordinate:        c.Coordinate,
 			Group:                   c.Group,
 			Environment:             c.Environment,
 			ParameterName:           name,
 			ResolvedParameterValues: properties,
-		})
+			EnvironmentResolver:     environmentResolver,
+		}
+		var val any
+		var err error
+		if cacheable, ok := param.(parameter.CacheableParameter); ok {
+			val, err = valueCache.Resolve(cacheable, context)
+		} else {
+			val, err = param.ResolveValue(context)
+		}
 
 		if err != nil {
 			errors = append(errors, err)
 			continue
 		}
 
+		if constraints, found := c.ParameterConstraints[name]; found {
+			if err := constraints.Check(val); err != nil {
+				errors = append(errors, parameter.NewParameterResolveValueError(context, err.Error()))
+				continue
+			}
+		}
+
 		if name == NameParameter {
 			properties[name] = strings.ToString(val)
 		} else {
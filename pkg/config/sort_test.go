@@ -21,8 +21,10 @@ package config
 import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	listParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -319,3 +321,29 @@ func TestSearchValueParameterForKey(t *testing.T) {
 		})
 	}
 }
+
+func TestSortParametersShouldFailOnCircularListInclusion(t *testing.T) {
+	configCoordinates := coordinate.Coordinate{
+		Project:  "project-1",
+		Type:     "alerting-profile",
+		ConfigId: "profile-1",
+	}
+
+	c := &Config{
+		Environment: "dev",
+		Coordinate:  configCoordinates,
+		Parameters: Parameters{
+			"zones": listParam.New([]parameter.Parameter{
+				&listParam.IncludedList{ParameterReference: parameter.ParameterReference{Config: configCoordinates, Property: "moreZones"}},
+			}),
+			"moreZones": listParam.New([]parameter.Parameter{
+				&listParam.IncludedList{ParameterReference: parameter.ParameterReference{Config: configCoordinates, Property: "zones"}},
+			}),
+		},
+	}
+
+	_, errs := getSortedParameters(c)
+
+	require.NotEmpty(t, errs)
+	assert.ErrorContains(t, errs[0], "circular dependency detected")
+}
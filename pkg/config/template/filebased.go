@@ -35,6 +35,8 @@ type FileBasedTemplate struct {
 	fs afero.Fs
 	// path of the template file
 	path string
+	// partials included by the template
+	partials []Partial
 }
 
 func (t *FileBasedTemplate) ID() string {
@@ -53,6 +55,12 @@ func (t *FileBasedTemplate) FilePath() string {
 	return t.path
 }
 
+// Partials returns the partials included by the template. They are loaded once, when the template is created
+// using NewFileTemplateWithPartials.
+func (t *FileBasedTemplate) Partials() []Partial {
+	return t.partials
+}
+
 func (t *FileBasedTemplate) UpdateContent(newContent string) error {
 	f, err := t.fs.Open(t.path)
 	if err != nil {
@@ -85,3 +93,26 @@ func NewFileTemplate(fs afero.Fs, path string) (Template, error) {
 
 	return &template, nil
 }
+
+// NewFileTemplateWithPartials creates a FileBasedTemplate like NewFileTemplate, and loads all partials the template
+// includes from the given project folder.
+// If the file or any included partial can not be accessed an error will be returned.
+func NewFileTemplateWithPartials(fs afero.Fs, path string, projectFolder string) (Template, error) {
+	t, err := NewFileTemplate(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := t.Content()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+
+	partials, err := LoadPartials(fs, projectFolder, t.ID(), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+
+	t.(*FileBasedTemplate).partials = partials
+	return t, nil
+}
@@ -0,0 +1,127 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	escaping "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"reflect"
+	"strings"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+)
+
+// Item is an element of a list iterated over with the 'items' template function. It is rendered as JSON value, e.g.
+// strings are quoted and escaped, so that it can be inserted into JSON templates as is.
+type Item struct {
+	// Value is the unescaped value of the element
+	Value any
+}
+
+func (i Item) String() string {
+	s, err := toJSON(i.Value)
+	if err != nil {
+		return fmt.Sprint(i.Value)
+	}
+	return s
+}
+
+// functions are the functions available in templates, in addition to the builtin functions of Go templates
+var functions = templ.FuncMap{
+	"items":  items,
+	"toJson": toJSON,
+	"raw":    raw,
+	"escape": escape,
+}
+
+// items returns the elements of the given list as [Item]s, so that they can be iterated over with 'range', e.g.
+//
+//	"dimensions": [ {{ range $i, $d := items .dimensions }}{{ if $i }},{{ end }}{ "key": {{ $d }} }{{ end }} ]
+//
+// Lists are either the values of list parameters, which are JSON arrays, or slices.
+func items(list any) ([]Item, error) {
+	if s, ok := list.(string); ok {
+		var values []any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &values); err != nil {
+			return nil, fmt.Errorf("value is no list: %w", err)
+		}
+		list = values
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot iterate over value of type %T", list)
+	}
+
+	result := make([]Item, v.Len())
+	for i := range result {
+		result[i] = Item{Value: v.Index(i).Interface()}
+	}
+	return result, nil
+}
+
+// toJSON encodes the given value as JSON. Unlike json.Marshal, it does not escape HTML characters.
+func toJSON(v any) (string, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// raw reverts the JSON string escaping applied to parameter values when they are resolved. This allows to insert
+// values that intentionally contain JSON objects or arrays as they are, e.g.
+//
+//	"filter": {{ .filter | raw }}
+//
+// Combined with toJson, a value is inserted as quoted JSON string: {{ .value | raw | toJson }}.
+// Strings that are not escaped JSON strings - e.g. the values of list parameters - are returned unchanged.
+func raw(v any) any {
+	switch v := v.(type) {
+	case string:
+		var s string
+		if err := json.Unmarshal([]byte(`"`+v+`"`), &s); err != nil {
+			return v
+		}
+		return s
+	case map[string]string:
+		result := make(map[string]string, len(v))
+		for key, value := range v {
+			result[key] = raw(value).(string)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, value := range v {
+			result[key] = raw(value)
+		}
+		return result
+	case Item:
+		return v.Value
+	default:
+		return v
+	}
+}
+
+// escape applies the same JSON string escaping as used for parameter values to the given value. It is the
+// counterpart of raw, and is useful for values that are not escaped yet, e.g. '{{ printf "%s-%s" .a .b | raw | escape }}'.
+func escape(v any) (any, error) {
+	return escaping.EscapeSpecialCharactersInValue(v, escaping.FullStringEscapeFunction)
+}
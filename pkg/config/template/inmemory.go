@@ -29,6 +29,8 @@ type InMemoryTemplate struct {
 	content string
 	// optional path we'd like this Template to be written to if it's persisted
 	path *string
+	// partials included by the template
+	partials []Partial
 }
 
 func (t *InMemoryTemplate) ID() string {
@@ -51,6 +53,11 @@ func (t *InMemoryTemplate) FilePath() *string {
 	return t.path
 }
 
+// Partials returns the partials included by the template.
+func (t *InMemoryTemplate) Partials() []Partial {
+	return t.partials
+}
+
 // NewInMemoryTemplate creates a new InMemoryTemplate without a dedicated path it should be written to if persisted.
 // To create an InMemoryTemplate with a fixed target path, use NewInMemoryTemplateWithPath.
 func NewInMemoryTemplate(id, content string) Template {
@@ -68,3 +75,13 @@ func NewInMemoryTemplateWithPath(filepath, content string) Template {
 		content: content,
 	}
 }
+
+// NewInMemoryTemplateWithPartials creates a new InMemoryTemplate like NewInMemoryTemplateWithPath, which includes the
+// given partials. This is used to persist loaded templates again.
+func NewInMemoryTemplateWithPartials(filepath, content string, partials []Partial) Template {
+	return &InMemoryTemplate{
+		path:     &filepath,
+		content:  content,
+		partials: partials,
+	}
+}
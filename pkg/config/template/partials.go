@@ -0,0 +1,173 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strings"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+	"text/template/parse"
+)
+
+// Partial is a template file that is included by other templates of the same project using
+// '{{ template "partials/tile.json" . }}'. Its name is the path of the file relative to the project's folder.
+type Partial struct {
+	// Name the partial is included by
+	Name string
+	// Path of the partial file
+	Path string
+	// Content of the partial file
+	Content string
+}
+
+// PartialsProvider is implemented by templates which include partials.
+type PartialsProvider interface {
+	// Partials returns all partials the template includes, directly or via other partials.
+	Partials() []Partial
+}
+
+// Partials returns the partials included by the given template, or nil if it does not include any.
+func Partials(t Template) []Partial {
+	if p, ok := t.(PartialsProvider); ok {
+		return p.Partials()
+	}
+	return nil
+}
+
+// LoadPartials returns all partials the given template content includes, directly or via other partials. Partials are
+// resolved relative to the projectFolder and must be located within it. Templates defined in the content itself using
+// 'define' are no partials.
+// If the content is no valid template, no partials are returned - the error surfaces once the template is rendered.
+func LoadPartials(fs afero.Fs, projectFolder, id, content string) ([]Partial, error) {
+	var partials []Partial
+	loaded := map[string]struct{}{}
+
+	type includer struct{ id, content string }
+	queue := []includer{{id: id, content: content}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		parsed, err := ParseTemplate(current.id, escapeCurlyBraces(current.content))
+		if err != nil {
+			continue
+		}
+
+		for _, name := range includedTemplates(parsed) {
+			if _, found := loaded[name]; found {
+				continue
+			}
+			loaded[name] = struct{}{}
+
+			p, err := loadPartial(fs, projectFolder, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load partial %q included by %q: %w", name, current.id, err)
+			}
+			partials = append(partials, p)
+			queue = append(queue, includer{id: p.Name, content: p.Content})
+		}
+	}
+
+	return partials, nil
+}
+
+func loadPartial(fs afero.Fs, projectFolder, name string) (Partial, error) {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return Partial{}, fmt.Errorf("partials must be located within the project folder")
+	}
+
+	path := filepath.Join(projectFolder, rel)
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return Partial{}, err
+	}
+
+	return Partial{Name: name, Path: path, Content: string(content)}, nil
+}
+
+// includedTemplates returns the names of all templates included by the parsed template that are not defined within it.
+func includedTemplates(t *templ.Template) []string {
+	defined := map[string]struct{}{}
+	for _, d := range t.Templates() {
+		defined[d.Name()] = struct{}{}
+	}
+
+	var names []string
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.IfNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			if _, found := defined[n.Name]; !found {
+				names = append(names, n.Name)
+			}
+		}
+	}
+
+	for _, d := range t.Templates() {
+		if d.Tree != nil {
+			walk(d.Tree.Root)
+		}
+	}
+	return names
+}
+
+// Parse parses the given template, together with all partials it includes.
+func Parse(t Template) (*templ.Template, error) {
+	content, err := t.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := ParseTemplate(t.ID(), escapeCurlyBraces(content))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range Partials(t) {
+		if _, err := parsed.New(p.Name).Parse(escapeCurlyBraces(p.Content)); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %q: %w", p.Name, err)
+		}
+	}
+	return parsed, nil
+}
+
+// escapeCurlyBraces fixes the case that a payload was fetched that after the download and processing results in three
+// subsequent {. This can happen e.g. if the payload allows to have content embraced between curly braces like
+// {"somekey" : "some {VALUE}"}
+func escapeCurlyBraces(content string) string {
+	return strings.ReplaceAll(content, "{{{", "{{\"{\"}}{{")
+}
@@ -0,0 +1,82 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template_test
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPartials(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "proj/partials/tile.json", []byte(`{"name": "{{ .name }}", "bounds": {{ template "partials/bounds.json" . }}}`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "proj/partials/bounds.json", []byte(`{"top": 0}`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "outside.json", []byte(`{}`), 0644))
+
+	t.Run("partials are loaded transitively", func(t *testing.T) {
+		got, err := template.LoadPartials(fs, "proj", "dashboard.json", `{"tiles": [{{ template "partials/tile.json" . }}, {{ template "partials/tile.json" . }}]}`)
+		require.NoError(t, err)
+		assert.Equal(t, []template.Partial{
+			{Name: "partials/tile.json", Path: filepath.FromSlash("proj/partials/tile.json"), Content: `{"name": "{{ .name }}", "bounds": {{ template "partials/bounds.json" . }}}`},
+			{Name: "partials/bounds.json", Path: filepath.FromSlash("proj/partials/bounds.json"), Content: `{"top": 0}`},
+		}, got)
+	})
+
+	t.Run("defined templates are no partials", func(t *testing.T) {
+		got, err := template.LoadPartials(fs, "proj", "dashboard.json", `{{ define "tile" }}{}{{ end }}[{{ template "tile" . }}]`)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("missing partial", func(t *testing.T) {
+		_, err := template.LoadPartials(fs, "proj", "dashboard.json", `{{ template "partials/missing.json" . }}`)
+		assert.ErrorContains(t, err, `failed to load partial "partials/missing.json" included by "dashboard.json"`)
+	})
+
+	t.Run("partial outside of project", func(t *testing.T) {
+		_, err := template.LoadPartials(fs, "proj", "dashboard.json", `{{ template "../outside.json" . }}`)
+		assert.ErrorContains(t, err, "partials must be located within the project folder")
+	})
+}
+
+func TestRender_WithPartials(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "proj/dashboard/dashboard.json", []byte(`{"tiles": [{{ template "partials/tile.json" . }}]}`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "proj/partials/tile.json", []byte(`{"name": "{{ .name }}"}`), 0644))
+
+	tmpl, err := template.NewFileTemplateWithPartials(fs, "proj/dashboard/dashboard.json", "proj")
+	require.NoError(t, err)
+	assert.Len(t, template.Partials(tmpl), 1)
+
+	got, err := template.Render(tmpl, map[string]any{"name": "Tile"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"tiles": [{"name": "Tile"}]}`, got)
+
+	t.Run("without partials rendering fails", func(t *testing.T) {
+		tmpl, err := template.NewFileTemplate(fs, "proj/dashboard/dashboard.json")
+		require.NoError(t, err)
+
+		_, err = template.Render(tmpl, map[string]any{"name": "Tile"})
+		assert.ErrorContains(t, err, `template "partials/tile.json" not defined`)
+	})
+}
@@ -0,0 +1,151 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+	"text/template/parse"
+)
+
+// ReferencedKeys returns the sorted names of all top-level fields of the template's data the template accesses.
+func ReferencedKeys(t Template) ([]string, error) {
+	parsed, err := Parse(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, r := range References(parsed) {
+		if !slices.Contains(keys, r.Name) {
+			keys = append(keys, r.Name)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// Reference is an access of a top-level field of a template's data, i.e. of a parameter.
+type Reference struct {
+	// Name of the accessed field
+	Name string
+	// Template is the name of the (partial) template the reference is located in
+	Template string
+	// Line of the reference within the template, starting at 1
+	Line int
+}
+
+// References returns all top-level fields of the template's data that are accessed by the parsed template, including
+// accesses within partials and defined templates invoked with the template's data.
+func References(t *templ.Template) []Reference {
+	var refs []Reference
+	if t.Tree == nil || t.Tree.Root == nil {
+		return refs
+	}
+
+	visited := map[string]struct{}{t.Name(): {}}
+
+	// dotIsRoot is false within range and with blocks, where '.' no longer refers to the template's data.
+	var walk func(tree *parse.Tree, node parse.Node, dotIsRoot bool)
+	walk = func(tree *parse.Tree, node parse.Node, dotIsRoot bool) {
+		add := func(name string) {
+			refs = append(refs, Reference{Name: name, Template: tree.ParseName, Line: nodeLine(tree, node)})
+		}
+
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(tree, child, dotIsRoot)
+			}
+		case *parse.ActionNode:
+			walk(tree, n.Pipe, dotIsRoot)
+		case *parse.IfNode:
+			walk(tree, n.Pipe, dotIsRoot)
+			walk(tree, n.List, dotIsRoot)
+			walk(tree, n.ElseList, dotIsRoot)
+		case *parse.RangeNode:
+			walk(tree, n.Pipe, dotIsRoot)
+			walk(tree, n.List, false)
+			walk(tree, n.ElseList, dotIsRoot)
+		case *parse.WithNode:
+			walk(tree, n.Pipe, dotIsRoot)
+			walk(tree, n.List, false)
+			walk(tree, n.ElseList, dotIsRoot)
+		case *parse.TemplateNode:
+			walk(tree, n.Pipe, dotIsRoot)
+			if _, found := visited[n.Name]; found || !passesData(n.Pipe, dotIsRoot) {
+				return
+			}
+			visited[n.Name] = struct{}{}
+			if included := t.Lookup(n.Name); included != nil && included.Tree != nil {
+				walk(included.Tree, included.Tree.Root, true)
+			}
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(tree, cmd, dotIsRoot)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(tree, arg, dotIsRoot)
+			}
+		case *parse.ChainNode:
+			walk(tree, n.Node, dotIsRoot)
+		case *parse.FieldNode:
+			if dotIsRoot {
+				add(n.Ident[0])
+			}
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				add(n.Ident[1])
+			}
+		}
+	}
+	walk(t.Tree, t.Tree.Root, true)
+
+	return refs
+}
+
+// passesData returns whether the pipeline of a template invocation passes the template's data, i.e. '.' or '$'.
+func passesData(pipe *parse.PipeNode, dotIsRoot bool) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	switch n := pipe.Cmds[0].Args[0].(type) {
+	case *parse.DotNode:
+		return dotIsRoot
+	case *parse.VariableNode:
+		return len(n.Ident) == 1 && n.Ident[0] == "$"
+	}
+	return false
+}
+
+// nodeLine returns the line of the node within the text of the template it was parsed from.
+func nodeLine(tree *parse.Tree, node parse.Node) int {
+	location, _ := tree.ErrorContext(node)
+	// location has the form '<name>:<line>:<column>'
+	location = location[:strings.LastIndex(location, ":")]
+	line, _ := strconv.Atoi(location[strings.LastIndex(location, ":")+1:])
+	return line
+}
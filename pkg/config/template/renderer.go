@@ -17,24 +17,13 @@ package template
 import (
 	"bytes"
 	"fmt"
-	"strings"
 	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
 )
 
 // Render tries to render a given template with the given properties and returns the
 // resulting string. if any error occurs during rendering, an error is returned.
 func Render(template Template, properties map[string]interface{}) (string, error) {
-	content, err := template.Content()
-	if err != nil {
-		return "", fmt.Errorf("failure trying to render template %s: %w", template.ID(), err)
-	}
-
-	// special handling to fix the case that a payload was fetched that after the download and processing
-	// results in three subsequent {. This can happen e.g. if the payload allows to have content embraced between
-	// curly braces like {"somekey" : "some {VALUE}"}
-	content = strings.ReplaceAll(content, "{{{", "{{\"{\"}}{{")
-	parsedTemplate, err := ParseTemplate(template.ID(), content)
-
+	parsedTemplate, err := Parse(template)
 	if err != nil {
 		return "", fmt.Errorf("failure trying to render template %s: %w", template.ID(), err)
 	}
@@ -50,7 +39,9 @@ func Render(template Template, properties map[string]interface{}) (string, error
 }
 
 // ParseTemplate creates go Template with the given id from the given string content
-// in any error occurs creating the template, an erro is returned
+// in any error occurs creating the template, an erro is returned.
+// Besides the builtin functions of Go templates, the template may use 'items' to iterate over list parameters,
+// 'toJson' to encode values as JSON, and 'raw' and 'escape' to control the escaping of inserted values.
 func ParseTemplate(id, content string) (*templ.Template, error) {
-	return templ.New(id).Option("missingkey=error").Parse(content)
+	return templ.New(id).Option("missingkey=error").Funcs(functions).Parse(content)
 }
@@ -19,7 +19,6 @@
 package template
 
 import (
-	"reflect"
 	"testing"
 	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
 )
@@ -83,8 +82,15 @@ func TestParseTemplate(t *testing.T) {
 				t.Errorf("ParseTemplate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ParseTemplate() got = %v, want %v", got, tt.want)
+			// templates holding functions are never deeply equal, thus their names and parse trees are compared
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("ParseTemplate() got = %v, want nil", got)
+				}
+				return
+			}
+			if got.Name() != tt.want.Name() || got.Root.String() != tt.want.Root.String() {
+				t.Errorf("ParseTemplate() got = %v, want %v", got.Root, tt.want.Root)
 			}
 		})
 	}
@@ -206,6 +212,87 @@ func TestRender(t *testing.T) {
 			"{ \"key\":\nthe-key\n}",
 			false,
 		},
+		{
+			"iterates over list parameter values",
+			&InMemoryTemplate{
+				content: `[ {{ range $i, $d := items .dims }}{{ if $i }},{{ end }}{"key": {{ $d }}}{{ end }} ]`,
+			},
+			map[string]interface{}{"dims": `[ "dt.entity.host","say \"hi\" <now>" ]`},
+			`[ {"key": "dt.entity.host"},{"key": "say \"hi\" <now>"} ]`,
+			false,
+		},
+		{
+			"iterates over slices",
+			&InMemoryTemplate{
+				content: `{{ range items .values }}{{ . }};{{ end }}`,
+			},
+			map[string]interface{}{"values": []any{1, "a", true}},
+			`1;"a";true;`,
+			false,
+		},
+		{
+			"encodes values as JSON",
+			&InMemoryTemplate{
+				content: `{"value": {{ toJson .value }}}`,
+			},
+			map[string]interface{}{"value": map[string]any{"a": "b"}},
+			`{"value": {"a":"b"}}`,
+			false,
+		},
+		{
+			"inserts escaped values as they are",
+			&InMemoryTemplate{
+				content: `{"filter": {{ .filter | raw }}, "name": "{{ .name }}"}`,
+			},
+			map[string]interface{}{"filter": `{\"a\": [1, 2]}`, "name": `say \"hi\"`},
+			`{"filter": {"a": [1, 2]}, "name": "say \"hi\""}`,
+			false,
+		},
+		{
+			"raw values of maps",
+			&InMemoryTemplate{
+				content: `{{ (raw .value).a }}`,
+			},
+			map[string]interface{}{"value": map[string]any{"a": `\"quoted\"`}},
+			`"quoted"`,
+			false,
+		},
+		{
+			"raw keeps unescaped values",
+			&InMemoryTemplate{
+				content: `[ {{ .list | raw }} ]`,
+			},
+			map[string]interface{}{"list": `"a", "b"`},
+			`[ "a", "b" ]`,
+			false,
+		},
+		{
+			"raw values encoded as JSON",
+			&InMemoryTemplate{
+				content: `{"value": {{ .value | raw | toJson }}}`,
+			},
+			map[string]interface{}{"value": `line\nbreak`},
+			`{"value": "line\nbreak"}`,
+			false,
+		},
+		{
+			"escapes values",
+			&InMemoryTemplate{
+				content: `{"value": "{{ printf "%s %s" .a .b | raw | escape }}"}`,
+			},
+			map[string]interface{}{"a": `say`, "b": `\"hi\"`},
+			`{"value": "say \"hi\""}`,
+			false,
+		},
+		{
+			"fails to iterate over values that are no lists",
+			&InMemoryTemplate{
+				content: `{{ range items .value }}{{ . }}{{ end }}`,
+			},
+			map[string]interface{}{"value": "no list"},
+			"",
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
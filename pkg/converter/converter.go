@@ -35,6 +35,8 @@ import (
 	projectV2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
+	"path"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
@@ -46,11 +48,33 @@ const (
 	DefaultGroup = "default"
 )
 
+// SecretProvider defines how the auth sections of the converted manifest reference the environments' access tokens
+type SecretProvider string
+
+const (
+	// SecretProviderEnvironment reads a token from the environment-variable named like the v1 'env-token-name'
+	SecretProviderEnvironment SecretProvider = "environment"
+	// SecretProviderFile reads a token from a file named like the v1 'env-token-name' in the ConverterContext's
+	// SecretFolder - e.g. as provided by a Vault agent or a Kubernetes secrets store CSI driver
+	SecretProviderFile SecretProvider = "file"
+)
+
+// SecretProviders lists all supported secret providers
+var SecretProviders = []SecretProvider{SecretProviderEnvironment, SecretProviderFile}
+
 type ConverterContext struct {
 	Fs afero.Fs
 
 	ResolveSkip    bool
 	UnescapeValues bool
+
+	// SecretProvider defines how access tokens are referenced in the converted manifest. Defaults to SecretProviderEnvironment.
+	SecretProvider SecretProvider
+	// SecretFolder is the folder token files are read from when using SecretProviderFile
+	SecretFolder string
+
+	// Report collects findings which need manual attention after the conversion. If nil, findings are only logged.
+	Report *Report
 }
 
 type configConvertContext struct {
@@ -58,6 +82,8 @@ type configConvertContext struct {
 	ProjectId             string
 	KnownListParameterIds map[string]struct{}
 	V1Apis                api.APIs
+	// KnownProjectIds holds the ids of all v1 projects that are converted, using '/' as path separator
+	KnownProjectIds []string
 }
 
 type ConvertConfigError struct {
@@ -120,7 +146,7 @@ var (
 // Convert takes v1 environments and projects and converts them into a v2 manifest and projects
 func Convert(context ConverterContext, environments map[string]*v1environment.EnvironmentV1,
 	projects []projectV1.Project) (manifest.Manifest, []projectV2.Project, []error) {
-	environmentDefinitions := convertEnvironments(environments)
+	environmentDefinitions := convertEnvironments(&context, environments)
 	projectDefinitions, convertedProjects, errors := convertProjects(&context, environmentDefinitions, projects)
 
 	return manifest.Manifest{
@@ -135,9 +161,14 @@ func convertProjects(context *ConverterContext, environments map[string]manifest
 	var convertedProjects []projectV2.Project
 	projectDefinitions := make(manifest.ProjectDefinitionByProjectID)
 
+	knownProjectIds := make([]string, len(projects))
+	for i, p := range projects {
+		knownProjectIds[i] = filepath.ToSlash(p.GetId())
+	}
+
 	for _, p := range projects {
 		adjustedId := adjustProjectId(p.GetId())
-		projectDefinition, convertedProject, convertErrors := convertProject(context, environments, adjustedId, p)
+		projectDefinition, convertedProject, convertErrors := convertProject(context, environments, adjustedId, p, knownProjectIds)
 
 		if convertErrors != nil {
 			errors = append(errors, convertErrors...)
@@ -162,12 +193,13 @@ func adjustProjectId(projectId string) string {
 }
 
 func convertProject(context *ConverterContext, environments map[string]manifest.EnvironmentDefinition,
-	adjustedId string, project projectV1.Project) (manifest.ProjectDefinition, projectV2.Project, []error) {
+	adjustedId string, project projectV1.Project, knownProjectIds []string) (manifest.ProjectDefinition, projectV2.Project, []error) {
 
 	convertedConfigs, errors := convertConfigs(&configConvertContext{
 		ConverterContext: context,
 		ProjectId:        adjustedId,
 		V1Apis:           api.NewV1APIs(),
+		KnownProjectIds:  knownProjectIds,
 	}, environments, project.GetConfigs())
 
 	if errors != nil {
@@ -220,19 +252,21 @@ func convertConfig(context *configConvertContext, environment manifest.Environme
 	apiId := c.GetApi().ID
 	convertedTemplatePath := c.GetFilePath()
 	apiConversion := api.GetV2ID(c.GetApi())
+	coord := coordinate.Coordinate{
+		Project:  context.ProjectId,
+		Type:     apiConversion,
+		ConfigId: c.GetId(),
+	}
+
 	if apiId != apiConversion {
 		log.Info("Converting config %q from deprecated API %q to %q", c.GetId(), apiId, apiConversion)
+		context.Report.addDeprecatedApi(coord, apiId, apiConversion)
 		convertedTemplatePath = strings.Replace(convertedTemplatePath, apiId, apiConversion, 1)
 		convertedTemplatePath = strings.Replace(convertedTemplatePath, ".json", "-"+apiId+".json", 1) // ensure modified template paths don't overlap with existing ones
 		apiId = apiConversion
 	} else if deprecatedBy := c.GetApi().DeprecatedBy; deprecatedBy != "" && context.V1Apis.Contains(deprecatedBy) && context.V1Apis[deprecatedBy].NonUniqueName {
 		log.Info("Converting config %q from deprecated API %q to config with non-unique-name handling (see https://dt-url.net/non-unique-name-config)", c.GetId(), apiId)
-	}
-
-	coord := coordinate.Coordinate{
-		Project:  context.ProjectId,
-		Type:     apiId,
-		ConfigId: c.GetId(),
+		context.Report.addDeprecatedApi(coord, apiId, deprecatedBy)
 	}
 
 	templ, envParams, listParamIds, errs := convertTemplate(context, c.GetFilePath(), convertedTemplatePath)
@@ -264,13 +298,15 @@ func convertConfig(context *configConvertContext, environment manifest.Environme
 	if _, found := parameters[config.NameParameter]; !found {
 		name := c.GetId() + " - monaco-conversion created name"
 		parameters[config.NameParameter] = valueParam.New(name)
-		log.Info(`Missing name in config "%s/%s/%s". Using name %q.`, c.GetProject(), c.GetType(), c.GetId(), name)
+		context.Report.addManualStep(coord, config.NameParameter, fmt.Sprintf("name is missing, using generated name %q", name))
 	}
 
 	if errors != nil {
 		return config.Config{}, errors
 	}
 
+	context.Report.addConfig(coord, c.GetFilePath())
+
 	return config.Config{
 		Type:              config.ClassicApiType{Api: apiId},
 		Template:          templ,
@@ -280,9 +316,39 @@ func convertConfig(context *configConvertContext, environment manifest.Environme
 		Parameters:        parameters,
 		Skip:              false,
 		SkipForConversion: skipParameter,
+
+		AnnotationsForConversion: convertAnnotations(environment, c),
 	}, nil
 }
 
+// convertAnnotations collects the comments and anchors of the v1 config and its properties for the given environment,
+// keyed by the converted parameter names.
+func convertAnnotations(environment manifest.EnvironmentDefinition, c *projectV1.Config) config.ConversionAnnotations {
+	var result config.ConversionAnnotations
+
+	for _, key := range []string{c.GetId(), c.GetId() + "." + environment.Group, c.GetId() + "." + environment.Name} {
+		for property, a := range c.GetAnnotations()[key] {
+			if property == "" {
+				result.Config = result.Config.Merge(config.YamlAnnotation(a))
+				continue
+			}
+
+			name := convertReservedParameterNames(property)
+			if property == projectV1.SkipConfigDeploymentParameter {
+				name = config.SkipParameter
+			}
+
+			if result.Parameters == nil {
+				result.Parameters = make(map[string]config.YamlAnnotation)
+			}
+			result.Parameters[name] = result.Parameters[name].Merge(config.YamlAnnotation(a))
+		}
+	}
+
+	return result
+}
+
+
 type TemplateConversionError struct {
 	// TemplatePath is the path to the template JSON file that failed to be converted
 	TemplatePath string `json:"templatePath"`
@@ -452,6 +518,11 @@ func convertParameters(context *configConvertContext, environment manifest.Envir
 				parameters[newName] = c
 			}
 		} else {
+			if regex.IsListDefinition(value) {
+				context.Report.addManualStep(configCoordinate(context, config), name,
+					"value looks like a list, but the template does not use it in the form `\"key\": [ {{ .property }} ]`. It was converted to a plain value parameter - consider changing the template and converting it to a list parameter")
+			}
+
 			s := value
 			if context.UnescapeValues {
 				s = removeEscapeChars(s)
@@ -547,7 +618,7 @@ func parseReference(context *configConvertContext, config *projectV1.Config, par
 		referencedConfigId = parts[1]
 
 	default:
-		projectId = strings.Join(parts[0:numberOfParts-2], ".")
+		projectId = resolveReferencedProject(context, config, parameterName, strings.Join(parts[0:numberOfParts-2], "/"))
 		referencedApiId = parts[numberOfParts-2]
 		referencedConfigId = parts[numberOfParts-1]
 	}
@@ -561,6 +632,49 @@ func parseReference(context *configConvertContext, config *projectV1.Config, par
 	return refParam.New(projectId, currentApiId, referencedConfigId, property), nil
 }
 
+// resolveReferencedProject returns the v2 project id of the project referenced by the given v1 project path.
+// Like v1 did when resolving dependencies, a project path also matches any nested project it is a suffix of - e.g. a
+// reference to 'infrastructure/management-zone/zone.id' matches the project 'projects/infrastructure'.
+// If no or several projects match, the finding is reported and the best guess is returned.
+func resolveReferencedProject(context *configConvertContext, config *projectV1.Config, parameterName string, projectPath string) string {
+	if len(context.KnownProjectIds) == 0 {
+		return adjustProjectId(projectPath)
+	}
+
+	var candidates []string
+	for _, id := range context.KnownProjectIds {
+		if id == projectPath {
+			return adjustProjectId(id)
+		}
+		if strings.HasSuffix(id, "/"+projectPath) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		context.Report.addManualStep(configCoordinate(context, config), parameterName,
+			fmt.Sprintf("referenced project %q is not part of the conversion. Make sure it is available when deploying", projectPath))
+		return adjustProjectId(projectPath)
+	case 1:
+		return adjustProjectId(candidates[0])
+	default:
+		slices.Sort(candidates)
+		context.Report.addManualStep(configCoordinate(context, config), parameterName,
+			fmt.Sprintf("referenced project %q is ambiguous, it matches the projects %v. Using %q - check that this is the intended project", projectPath, candidates, candidates[0]))
+		return adjustProjectId(candidates[0])
+	}
+}
+
+// configCoordinate returns the v2 coordinate the given v1 config is converted to.
+func configCoordinate(context *configConvertContext, config *projectV1.Config) coordinate.Coordinate {
+	return coordinate.Coordinate{
+		Project:  context.ProjectId,
+		Type:     api.GetV2ID(config.GetApi()),
+		ConfigId: config.GetId(),
+	}
+}
+
 func loadPropertiesForEnvironment(environment manifest.EnvironmentDefinition, config *projectV1.Config) map[string]string {
 	result := make(map[string]string)
 
@@ -579,25 +693,25 @@ func loadPropertiesForEnvironment(environment manifest.EnvironmentDefinition, co
 	return result
 }
 
-func parseListStringToValueSlice(s string) ([]valueParam.ValueParameter, error) {
+func parseListStringToValueSlice(s string) ([]parameter.Parameter, error) {
 	if !regex.IsListDefinition(s) && !regex.IsSimpleValueDefinition(s) {
-		return []valueParam.ValueParameter{}, fmt.Errorf("failed to parse value for list parameter, '%s' is not in expected list format", s)
+		return []parameter.Parameter{}, fmt.Errorf("failed to parse value for list parameter, '%s' is not in expected list format", s)
 	}
 
-	var slice []valueParam.ValueParameter
+	var slice []parameter.Parameter
 	splitOnColon := strings.Split(s, ",")
 	for _, entry := range splitOnColon {
 		entry = strings.TrimSpace(entry)
 		entry = strings.TrimPrefix(entry, `"`)
 		entry = strings.TrimSuffix(entry, `"`)
 		if len(entry) > 0 {
-			slice = append(slice, valueParam.ValueParameter{Value: entry})
+			slice = append(slice, &valueParam.ValueParameter{Value: entry})
 		}
 	}
 	return slice, nil
 }
 
-func convertEnvironments(environments map[string]*v1environment.EnvironmentV1) map[string]manifest.EnvironmentDefinition {
+func convertEnvironments(context *ConverterContext, environments map[string]*v1environment.EnvironmentV1) map[string]manifest.EnvironmentDefinition {
 	result := make(map[string]manifest.EnvironmentDefinition)
 
 	for _, env := range environments {
@@ -609,7 +723,11 @@ func convertEnvironments(environments map[string]*v1environment.EnvironmentV1) m
 			group = env.GetGroup()
 		}
 
-		result[env.GetId()] = newEnvironmentDefinitionFromV1(env, group)
+		definition := newEnvironmentDefinitionFromV1(env, group)
+		if context.SecretProvider == SecretProviderFile {
+			definition.Auth.Token.Path = path.Join(context.SecretFolder, env.GetTokenName())
+		}
+		result[env.GetId()] = definition
 	}
 
 	return result
@@ -125,7 +125,7 @@ func TestConvertParameters(t *testing.T) {
 
 	listParameter, found := parameters[listParameterName]
 	assert.Equal(t, true, found)
-	assert.Equal(t, []valueParam.ValueParameter{{"GEOLOCATION-41"}, {"GEOLOCATION-42"}, {"GEOLOCATION-43"}}, listParameter.(*listParam.ListParameter).Values)
+	assert.Equal(t, []parameter.Parameter{&valueParam.ValueParameter{Value: "GEOLOCATION-41"}, &valueParam.ValueParameter{Value: "GEOLOCATION-42"}, &valueParam.ValueParameter{Value: "GEOLOCATION-43"}}, listParameter.(*listParam.ListParameter).Values)
 
 	envParameter, found := parameters[envParameterName]
 	assert.Equal(t, true, found)
@@ -791,7 +791,7 @@ func TestConvertConfigs(t *testing.T) {
 	assert.Equal(t, "id", c.Parameters[referenceParameterName].(*refParam.ReferenceParameter).Property)
 
 	// assert list param is converted as expected
-	assert.Equal(t, []valueParam.ValueParameter{{"GEOLOCATION-41"}, {"GEOLOCATION-42"}, {"GEOLOCATION-43"}}, c.Parameters[listParameterName].(*listParam.ListParameter).Values)
+	assert.Equal(t, []parameter.Parameter{&valueParam.ValueParameter{Value: "GEOLOCATION-41"}, &valueParam.ValueParameter{Value: "GEOLOCATION-42"}, &valueParam.ValueParameter{Value: "GEOLOCATION-43"}}, c.Parameters[listParameterName].(*listParam.ListParameter).Values)
 
 	transformedEnvVarName := transformEnvironmentToParamName(envVariableName)
 	// assert env reference in template has created correct env parameter
@@ -825,7 +825,7 @@ func TestConvertConfigs(t *testing.T) {
 
 	// assert override list param is converted as expected
 	// assert list param is converted as expected
-	assert.Equal(t, []valueParam.ValueParameter{{"james.t.kirk@dynatrace.com"}}, c.Parameters[listParameterName].(*listParam.ListParameter).Values)
+	assert.Equal(t, []parameter.Parameter{&valueParam.ValueParameter{Value: "james.t.kirk@dynatrace.com"}}, c.Parameters[listParameterName].(*listParam.ListParameter).Values)
 }
 
 func TestConvertWithMissingName(t *testing.T) {
@@ -1133,52 +1133,52 @@ func TestAdjustProjectId(t *testing.T) {
 func Test_parseListStringToValueSlice(t *testing.T) {
 	tests := []struct {
 		inputString string
-		want        []valueParam.ValueParameter
+		want        []parameter.Parameter
 		wantErr     bool
 	}{
 		{
 			`"a", "b", "c"`,
-			[]valueParam.ValueParameter{{"a"}, {"b"}, {"c"}},
+			[]parameter.Parameter{&valueParam.ValueParameter{Value: "a"}, &valueParam.ValueParameter{Value: "b"}, &valueParam.ValueParameter{Value: "c"}},
 			false,
 		},
 		{
 			`  " a " , " b "`,
-			[]valueParam.ValueParameter{{" a "}, {" b "}},
+			[]parameter.Parameter{&valueParam.ValueParameter{Value: " a "}, &valueParam.ValueParameter{Value: " b "}},
 			false,
 		},
 		{
 			`  "e@mail.com" , "first.last@domain.com"  `,
-			[]valueParam.ValueParameter{{"e@mail.com"}, {"first.last@domain.com"}},
+			[]parameter.Parameter{&valueParam.ValueParameter{Value: "e@mail.com"}, &valueParam.ValueParameter{Value: "first.last@domain.com"}},
 			false,
 		},
 		{
 			`  " a " , " b "   , `,
-			[]valueParam.ValueParameter{{" a "}, {" b "}},
+			[]parameter.Parameter{&valueParam.ValueParameter{Value: " a "}, &valueParam.ValueParameter{Value: " b "}},
 			false,
 		},
 		{
 			`"a"`,
-			[]valueParam.ValueParameter{{"a"}},
+			[]parameter.Parameter{&valueParam.ValueParameter{Value: "a"}},
 			false,
 		},
 		{
 			`"e@mail.com"`,
-			[]valueParam.ValueParameter{{"e@mail.com"}},
+			[]parameter.Parameter{&valueParam.ValueParameter{Value: "e@mail.com"}},
 			false,
 		},
 		{
 			``,
-			[]valueParam.ValueParameter{},
+			[]parameter.Parameter{},
 			true,
 		},
 		{
 			`"inval,id`,
-			[]valueParam.ValueParameter{},
+			[]parameter.Parameter{},
 			true,
 		},
 		{
 			`"",`,
-			[]valueParam.ValueParameter{},
+			[]parameter.Parameter{},
 			true,
 		},
 	}
@@ -1296,6 +1296,93 @@ func Test_parseReference(t *testing.T) {
 	}
 }
 
+func Test_parseReference_ResolvesNestedProjects(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenReference  string
+		wantProject     string
+		wantManualSteps int
+	}{
+		{
+			"exact project path",
+			"/projects/infrastructure/management-zone/zone.id",
+			"projects.infrastructure",
+			0,
+		},
+		{
+			"project path matching a nested project",
+			"/infrastructure/management-zone/zone.id",
+			"projects.infrastructure",
+			0,
+		},
+		{
+			"project path matching several nested projects",
+			"/shared/management-zone/zone.id",
+			"projects.a.shared",
+			1,
+		},
+		{
+			"unknown project",
+			"/unknown/management-zone/zone.id",
+			"unknown",
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &Report{}
+			testContext := &configConvertContext{
+				ConverterContext: &ConverterContext{
+					Fs:     setupDummyFs(t),
+					Report: report,
+				},
+				V1Apis:          api.NewV1APIs(),
+				ProjectId:       "test-project",
+				KnownProjectIds: []string{"test-project", "projects/infrastructure", "projects/b/shared", "projects/a/shared"},
+			}
+
+			got, err := parseReference(testContext, generateDummyConfig(t), "test-param", tt.givenReference)
+			assert.NoError(t, err)
+			assert.Equal(t, refParam.New(tt.wantProject, "management-zone", "zone", "id"), got)
+			assert.Len(t, report.ManualSteps, tt.wantManualSteps)
+		})
+	}
+}
+
+func TestConvertParameters_ReportsListValuesNotUsedAsList(t *testing.T) {
+	report := &Report{}
+	convertContext := &configConvertContext{
+		ConverterContext: &ConverterContext{
+			Fs:     setupDummyFs(t),
+			Report: report,
+		},
+		V1Apis:    api.NewV1APIs(),
+		ProjectId: "projectA",
+	}
+
+	testConfig := generateDummyConfig(t)
+	testConfig.GetProperties()[testConfig.GetId()] = map[string]string{
+		"name":            "Alerting Profile 1",
+		listParameterName: `"GEOLOCATION-41","GEOLOCATION-42"`,
+	}
+
+	environment := manifest.EnvironmentDefinition{Name: "test", URL: createSimpleUrlDefinition()}
+
+	// converting for several environments must only report the finding once
+	for i := 0; i < 2; i++ {
+		parameters, _, errs := convertParameters(convertContext, environment, testConfig)
+		assert.Empty(t, errs)
+		assert.IsType(t, &valueParam.ValueParameter{}, parameters[listParameterName])
+	}
+
+	if assert.Len(t, report.ManualSteps, 1) {
+		f := report.ManualSteps[0]
+		assert.Equal(t, coordinate.Coordinate{Project: "projectA", Type: "alerting-profile", ConfigId: testConfig.GetId()}, f.Coordinate)
+		assert.Equal(t, listParameterName, f.Parameter)
+		assert.Contains(t, f.Message, "looks like a list")
+	}
+}
+
 func Test_convertReservedParameters(t *testing.T) {
 
 	tests := []struct {
@@ -1371,6 +1458,22 @@ func TestNewEnvironmentDefinitionFromV1(t *testing.T) {
 	}
 }
 
+func TestConvertEnvironments_SecretProviders(t *testing.T) {
+	environments := map[string]*v1environment.EnvironmentV1{
+		"test": v1environment.NewEnvironmentV1("test", "name", "", "http://google.com", "TEST_TOKEN"),
+	}
+
+	t.Run("environment secret provider references environment-variables", func(t *testing.T) {
+		got := convertEnvironments(&ConverterContext{SecretProvider: SecretProviderEnvironment}, environments)
+		assert.Equal(t, manifest.AuthSecret{Name: "TEST_TOKEN"}, got["test"].Auth.Token)
+	})
+
+	t.Run("file secret provider references token files", func(t *testing.T) {
+		got := convertEnvironments(&ConverterContext{SecretProvider: SecretProviderFile, SecretFolder: "/run/secrets"}, environments)
+		assert.Equal(t, manifest.AuthSecret{Name: "TEST_TOKEN", Path: "/run/secrets/TEST_TOKEN"}, got["test"].Auth.Token)
+	})
+}
+
 func Test_convertToParameters(t *testing.T) {
 	type args struct {
 		envReference string
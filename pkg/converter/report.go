@@ -0,0 +1,197 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"slices"
+	"strings"
+)
+
+// Report collects everything noticed during a conversion: the converted configs, configs of deprecated APIs, skipped
+// projects and all findings that need to be reviewed or fixed manually before the converted configurations can be deployed.
+type Report struct {
+	// Configs lists all converted configs
+	Configs []ConvertedConfig `json:"configs"`
+	// DeprecatedApis lists all configs of deprecated v1 APIs
+	DeprecatedApis []DeprecatedApi `json:"deprecatedApis"`
+	// SkippedProjects lists the v1 projects that were not converted as they contain no configs
+	SkippedProjects []string `json:"skippedProjects"`
+	// ManualSteps lists all findings which the converter could not (fully) resolve on its own
+	ManualSteps []Finding `json:"manualSteps"`
+}
+
+// ConvertedConfig describes a single converted config.
+type ConvertedConfig struct {
+	// Coordinate of the converted config
+	Coordinate coordinate.Coordinate `json:"coordinate"`
+	// SourceTemplate is the path of the template the config was converted from
+	SourceTemplate string `json:"sourceTemplate"`
+}
+
+// DeprecatedApi describes a config of a deprecated v1 API.
+type DeprecatedApi struct {
+	// Coordinate of the converted config
+	Coordinate coordinate.Coordinate `json:"coordinate"`
+	// Api is the deprecated API of the v1 config
+	Api string `json:"api"`
+	// ReplacedBy is the API deprecating Api. The config was converted to it if it equals the type of Coordinate.
+	ReplacedBy string `json:"replacedBy"`
+}
+
+// Finding describes a single conversion issue of a config.
+type Finding struct {
+	// Coordinate of the converted config the finding refers to
+	Coordinate coordinate.Coordinate `json:"coordinate"`
+	// Parameter is the name of the property or parameter the finding refers to, if any
+	Parameter string `json:"parameter,omitempty"`
+	// Message describing what needs to be checked or done
+	Message string `json:"message"`
+}
+
+// addConfig records a converted config. As configs are converted once per environment, each config is only recorded once.
+func (r *Report) addConfig(coord coordinate.Coordinate, sourceTemplate string) {
+	if r == nil {
+		return
+	}
+	c := ConvertedConfig{Coordinate: coord, SourceTemplate: sourceTemplate}
+	if !slices.Contains(r.Configs, c) {
+		r.Configs = append(r.Configs, c)
+	}
+}
+
+// addDeprecatedApi records a config of a deprecated API. Each config is only recorded once.
+func (r *Report) addDeprecatedApi(coord coordinate.Coordinate, api string, replacedBy string) {
+	if r == nil {
+		return
+	}
+	d := DeprecatedApi{Coordinate: coord, Api: api, ReplacedBy: replacedBy}
+	if !slices.Contains(r.DeprecatedApis, d) {
+		r.DeprecatedApis = append(r.DeprecatedApis, d)
+	}
+}
+
+// addManualStep records a finding and logs it as a warning. As configs are converted once per environment, the same
+// finding is only recorded once. Calling addManualStep on a nil Report only logs the warning.
+func (r *Report) addManualStep(coord coordinate.Coordinate, parameter string, message string) {
+	f := Finding{Coordinate: coord, Parameter: parameter, Message: message}
+
+	if r != nil {
+		if slices.Contains(r.ManualSteps, f) {
+			return
+		}
+		r.ManualSteps = append(r.ManualSteps, f)
+	}
+
+	if parameter != "" {
+		log.WithFields(field.Coordinate(coord)).Warn("Config %s, property %q needs manual attention: %s", coord, parameter, message)
+		return
+	}
+	log.WithFields(field.Coordinate(coord)).Warn("Config %s needs manual attention: %s", coord, message)
+}
+
+// ReportFormat is the file format a Report can be written in
+type ReportFormat string
+
+const (
+	// ReportFormatMarkdown writes the report as Markdown document with one table per section
+	ReportFormatMarkdown ReportFormat = "markdown"
+	// ReportFormatJSON writes the report as JSON object
+	ReportFormatJSON ReportFormat = "json"
+)
+
+// ReportFormats lists all supported report formats
+var ReportFormats = []ReportFormat{ReportFormatMarkdown, ReportFormatJSON}
+
+// FileExtension returns the file extension, including the leading dot, of files in the format
+func (f ReportFormat) FileExtension() string {
+	if f == ReportFormatMarkdown {
+		return ".md"
+	}
+	return "." + string(f)
+}
+
+// Write writes the report in the given format to the given path
+func (r *Report) Write(fs afero.Fs, path string, format ReportFormat) error {
+	var content []byte
+	var err error
+	switch format {
+	case ReportFormatMarkdown:
+		content = r.marshalMarkdown()
+	case ReportFormatJSON:
+		content, err = json.MarshalIndent(r, "", "  ")
+	default:
+		return fmt.Errorf("unknown conversion report format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversion report: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write conversion report to %q: %w", path, err)
+	}
+	return nil
+}
+
+func (r *Report) marshalMarkdown() []byte {
+	var b strings.Builder
+	b.WriteString("# Conversion report\n")
+
+	fmt.Fprintf(&b, "\n## Manual steps (%d)\n\n", len(r.ManualSteps))
+	if len(r.ManualSteps) == 0 {
+		b.WriteString("No manual steps required.\n")
+	} else {
+		b.WriteString("| Config | Property | Required action |\n|---|---|---|\n")
+		for _, f := range r.ManualSteps {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(f.Coordinate.String()), markdownCell(f.Parameter), markdownCell(f.Message))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Deprecated APIs (%d)\n\n", len(r.DeprecatedApis))
+	if len(r.DeprecatedApis) > 0 {
+		b.WriteString("| Config | Deprecated API | Replaced by |\n|---|---|---|\n")
+		for _, d := range r.DeprecatedApis {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(d.Coordinate.String()), markdownCell(d.Api), markdownCell(d.ReplacedBy))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Skipped projects (%d)\n\n", len(r.SkippedProjects))
+	for _, p := range r.SkippedProjects {
+		fmt.Fprintf(&b, "- %s\n", p)
+	}
+
+	fmt.Fprintf(&b, "\n## Converted configs (%d)\n\n", len(r.Configs))
+	if len(r.Configs) > 0 {
+		b.WriteString("| Config | Source template |\n|---|---|\n")
+		for _, c := range r.Configs {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(c.Coordinate.String()), markdownCell(c.SourceTemplate))
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// markdownCell escapes the given value to be used in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,99 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package converter
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestReport_RecordsConfigsOnlyOnce(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "a"}
+
+	r := &Report{}
+	r.addConfig(coord, "p/alerting-profile/a.json")
+	r.addConfig(coord, "p/alerting-profile/a.json")
+	r.addDeprecatedApi(coord, "old-api", "alerting-profile")
+	r.addDeprecatedApi(coord, "old-api", "alerting-profile")
+
+	assert.Len(t, r.Configs, 1)
+	assert.Len(t, r.DeprecatedApis, 1)
+}
+
+func TestReport_NilReportDoesNotPanic(t *testing.T) {
+	var r *Report
+	assert.NotPanics(t, func() {
+		r.addConfig(coordinate.Coordinate{}, "")
+		r.addDeprecatedApi(coordinate.Coordinate{}, "", "")
+		r.addManualStep(coordinate.Coordinate{}, "", "")
+	})
+}
+
+func TestReport_Write_Markdown(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "a"}
+	r := &Report{
+		Configs:         []ConvertedConfig{{Coordinate: coord, SourceTemplate: "p/alerting-profile/a.json"}},
+		DeprecatedApis:  []DeprecatedApi{{Coordinate: coord, Api: "old-api", ReplacedBy: "alerting-profile"}},
+		SkippedProjects: []string{"empty"},
+		ManualSteps:     []Finding{{Coordinate: coord, Parameter: "list", Message: "value a|b\nlooks like a list"}},
+	}
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, r.Write(fs, "report.md", ReportFormatMarkdown))
+
+	content, err := afero.ReadFile(fs, "report.md")
+	require.NoError(t, err)
+	assert.Equal(t, `# Conversion report
+
+## Manual steps (1)
+
+| Config | Property | Required action |
+|---|---|---|
+| p:alerting-profile:a | list | value a\|b looks like a list |
+
+## Deprecated APIs (1)
+
+| Config | Deprecated API | Replaced by |
+|---|---|---|
+| p:alerting-profile:a | old-api | alerting-profile |
+
+## Skipped projects (1)
+
+- empty
+
+## Converted configs (1)
+
+| Config | Source template |
+|---|---|
+| p:alerting-profile:a | p/alerting-profile/a.json |
+`, string(content))
+}
+
+func TestReport_Write_FailsOnUnknownFormat(t *testing.T) {
+	r := &Report{}
+	assert.ErrorContains(t, r.Write(afero.NewMemMapFs(), "report.txt", "txt"), "unknown conversion report format")
+}
+
+func TestReportFormat_FileExtension(t *testing.T) {
+	assert.Equal(t, ".md", ReportFormatMarkdown.FileExtension())
+	assert.Equal(t, ".json", ReportFormatJSON.FileExtension())
+}
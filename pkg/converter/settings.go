@@ -0,0 +1,413 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	v2template "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	projectV2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"golang.org/x/exp/maps"
+	"slices"
+	"strings"
+)
+
+// settingsMapping describes how the JSON of a classic config is transformed into an object of a Settings 2.0 schema
+type settingsMapping struct {
+	schemaId string
+	fields   []fieldMapping
+	// constants are set in every created settings object, by their path
+	constants map[string]any
+	// ignored are the paths of classic fields that have no equivalent and need no conversion, like IDs
+	ignored []string
+}
+
+// fieldMapping maps a classic field to a settings field. Both are given as paths separated by '.'.
+type fieldMapping struct {
+	from, to string
+	// items maps the fields of every item, if the field is a list of objects
+	items []fieldMapping
+	// itemConstants are set in every mapped item
+	itemConstants map[string]any
+	// values translates values that differ between the classic API and the schema, like enum constants
+	values map[string]any
+}
+
+// SupportedSettingsConversions returns the classic APIs that can be converted to settings, mapped to their schema.
+func SupportedSettingsConversions() map[string]string {
+	result := make(map[string]string, len(settingsMappings))
+	for a, m := range settingsMappings {
+		result[a] = m.schemaId
+	}
+	return result
+}
+
+// ConvertToSettings converts all configs of classic APIs with a built-in mapping to configs of the equivalent Settings 2.0
+// schema, transforming their templates with the mapping rules. Configs of other types are kept as they are.
+// References to converted configs are updated to the new coordinates. Everything that needs manual attention - classic
+// fields that could not be mapped, references to the IDs of converted configs, and the classic configurations to be
+// removed - is recorded in the context's Report.
+func ConvertToSettings(context ConverterContext, projects []projectV2.Project) []projectV2.Project {
+	converted := make(map[coordinate.Coordinate]coordinate.Coordinate)
+	result := make([]projectV2.Project, len(projects))
+
+	for i, p := range projects {
+		configs := make(projectV2.ConfigsPerTypePerEnvironments, len(p.Configs))
+		for _, env := range sortedKeys(p.Configs) {
+			configs[env] = make(projectV2.ConfigsPerType)
+			for _, t := range sortedKeys(p.Configs[env]) {
+				for _, c := range p.Configs[env][t] {
+					s, ok := convertToSettings(context.Report, c)
+					if !ok {
+						configs[env][t] = append(configs[env][t], asWritable(c))
+						continue
+					}
+					converted[c.Coordinate] = s.Coordinate
+					configs[env][s.Coordinate.Type] = append(configs[env][s.Coordinate.Type], s)
+				}
+			}
+		}
+		p.Configs = configs
+		result[i] = p
+	}
+
+	for _, p := range result {
+		for _, env := range sortedKeys(p.Configs) {
+			for _, t := range sortedKeys(p.Configs[env]) {
+				for i, c := range p.Configs[env][t] {
+					p.Configs[env][t][i] = renameConvertedReferences(context.Report, c, converted)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func convertToSettings(report *Report, c config.Config) (config.Config, bool) {
+	classic, isClassic := c.Type.(config.ClassicApiType)
+	if !isClassic {
+		return c, false
+	}
+	mapping, found := settingsMappings[classic.Api]
+	if !found {
+		return c, false
+	}
+
+	content, err := c.Template.Content()
+	if err != nil {
+		report.addManualStep(c.Coordinate, "", fmt.Sprintf("not converted to settings schema %q: %s", mapping.schemaId, err))
+		return c, false
+	}
+
+	transformed, unmapped, err := mapping.transform(content)
+	if err != nil {
+		report.addManualStep(c.Coordinate, "", fmt.Sprintf("not converted to settings schema %q: template can not be parsed as JSON: %s", mapping.schemaId, err))
+		return c, false
+	}
+
+	coord := coordinate.Coordinate{
+		Project:  c.Coordinate.Project,
+		Type:     mapping.schemaId,
+		ConfigId: c.Coordinate.ConfigId,
+	}
+
+	sourceTemplate := c.Template.ID()
+	if path, ok := templatePath(c.Template); ok {
+		sourceTemplate = path
+	}
+	report.addConfig(coord, sourceTemplate)
+	for _, field := range unmapped {
+		report.addManualStep(coord, "", fmt.Sprintf("field %q of the classic config can not be converted automatically, add its equivalent to the template", field))
+	}
+	report.addManualStep(coord, "", fmt.Sprintf("converted from classic config %s, delete the classic configuration once the settings object is deployed", c.Coordinate))
+
+	params := maps.Clone(c.Parameters)
+	if params == nil {
+		params = make(config.Parameters)
+	}
+	params[config.ScopeParameter] = valueParam.New("environment")
+
+	c.Type = config.SettingsType{SchemaId: mapping.schemaId}
+	c.Coordinate = coord
+	c.Template = v2template.NewInMemoryTemplate(coord.ConfigId, transformed)
+	c.Parameters = params
+	return c, true
+}
+
+// renameConvertedReferences updates the reference parameters of the config that point to converted configs
+func renameConvertedReferences(report *Report, c config.Config, converted map[coordinate.Coordinate]coordinate.Coordinate) config.Config {
+	var params config.Parameters
+	for _, name := range sortedKeys(c.Parameters) {
+		ref, isRef := c.Parameters[name].(*refParam.ReferenceParameter)
+		if !isRef {
+			continue
+		}
+		to, found := converted[ref.Config]
+		if !found {
+			continue
+		}
+
+		if params == nil {
+			params = maps.Clone(c.Parameters)
+		}
+		params[name] = refParam.NewWithCoordinate(to, ref.Property)
+
+		if ref.Property == config.IdParameter {
+			report.addManualStep(c.Coordinate, name, fmt.Sprintf("references the ID of %s, which was converted to a settings object. Check that the referencing configuration accepts the ID of the settings object", ref.Config))
+		}
+	}
+
+	if params != nil {
+		c.Parameters = params
+	}
+	return c
+}
+
+// transform maps the classic JSON template to a settings template. Go template actions in the template are kept.
+// The paths of all classic fields that could not be mapped are returned as well.
+func (m settingsMapping) transform(content string) (string, []string, error) {
+	replaced, actions := replaceTemplateActions(content)
+
+	d := json.NewDecoder(strings.NewReader(replaced))
+	d.UseNumber()
+	var classic map[string]any
+	if err := d.Decode(&classic); err != nil {
+		return "", nil, err
+	}
+
+	settings, unmapped := applyFields(m.fields, m.constants, m.ignored, classic)
+
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(settings); err != nil {
+		return "", nil, err
+	}
+
+	return restoreTemplateActions(b.String(), actions), unmapped, nil
+}
+
+func applyFields(fields []fieldMapping, constants map[string]any, ignored []string, classic map[string]any) (map[string]any, []string) {
+	result := make(map[string]any)
+	for p, v := range constants {
+		setPath(result, p, v)
+	}
+
+	var unmapped []string
+	for _, f := range fields {
+		v, found := getPath(classic, f.from)
+		if !found || v == nil {
+			continue
+		}
+
+		switch {
+		case f.items != nil:
+			list, isList := v.([]any)
+			if !isList {
+				unmapped = append(unmapped, f.from)
+				continue
+			}
+			items := make([]any, 0, len(list))
+			if existing, found := getPath(result, f.to); found {
+				items, _ = existing.([]any)
+			}
+			for _, item := range list {
+				m, isMap := item.(map[string]any)
+				if !isMap {
+					unmapped = append(unmapped, f.from+"[]")
+					continue
+				}
+				mapped, itemUnmapped := applyFields(f.items, f.itemConstants, nil, m)
+				for _, u := range itemUnmapped {
+					unmapped = append(unmapped, f.from+"[]."+u)
+				}
+				items = append(items, mapped)
+			}
+			setPath(result, f.to, items)
+
+		case f.values != nil:
+			s, _ := v.(string)
+			translated, found := f.values[s]
+			if !found {
+				unmapped = append(unmapped, f.from)
+				continue
+			}
+			setPath(result, f.to, translated)
+
+		default:
+			setPath(result, f.to, v)
+		}
+	}
+
+	unmapped = append(unmapped, unmappedFields(classic, "", fields, ignored)...)
+	slices.Sort(unmapped)
+	return result, slices.Compact(unmapped)
+}
+
+// unmappedFields returns the paths of all non-empty fields of the classic object that are neither mapped nor ignored
+func unmappedFields(classic map[string]any, prefix string, fields []fieldMapping, ignored []string) []string {
+	var result []string
+	for k, v := range classic {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+
+		isMapped := func(f fieldMapping) bool { return f.from == p }
+		if isEmpty(v) || slices.Contains(ignored, p) || slices.ContainsFunc(fields, isMapped) {
+			continue
+		}
+
+		isMappedChild := func(f fieldMapping) bool { return strings.HasPrefix(f.from, p+".") }
+		if nested, isMap := v.(map[string]any); isMap && slices.ContainsFunc(fields, isMappedChild) {
+			result = append(result, unmappedFields(nested, p, fields, ignored)...)
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func getPath(obj map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		nested, isMap := obj[k].(map[string]any)
+		if !isMap {
+			return nil, false
+		}
+		obj = nested
+	}
+	v, found := obj[keys[len(keys)-1]]
+	return v, found
+}
+
+func setPath(obj map[string]any, path string, v any) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		nested, isMap := obj[k].(map[string]any)
+		if !isMap {
+			nested = make(map[string]any)
+			obj[k] = nested
+		}
+		obj = nested
+	}
+	obj[keys[len(keys)-1]] = v
+}
+
+const (
+	// valuePlaceholder replaces template actions used as JSON values, e.g. for list parameters
+	valuePlaceholder = "__MONACO_VALUE_%d__"
+	// stringPlaceholder replaces template actions within JSON strings
+	stringPlaceholder = "__MONACO_STRING_%d__"
+)
+
+// replaceTemplateActions replaces all Go template actions in the content with placeholders, so that the content can
+// be parsed as JSON. Actions used as values are replaced by quoted placeholders.
+func replaceTemplateActions(content string) (string, map[string]string) {
+	actions := make(map[string]string)
+	var b strings.Builder
+
+	inString, escaped := false, false
+	for i := 0; i < len(content); i++ {
+		if strings.HasPrefix(content[i:], "{{") {
+			if end := strings.Index(content[i:], "}}"); end >= 0 {
+				action := content[i : i+end+2]
+				if inString {
+					placeholder := fmt.Sprintf(stringPlaceholder, len(actions))
+					actions[placeholder] = action
+					b.WriteString(placeholder)
+				} else {
+					placeholder := fmt.Sprintf(`"`+valuePlaceholder+`"`, len(actions))
+					actions[placeholder] = action
+					b.WriteString(placeholder)
+				}
+				i += end + 1
+				continue
+			}
+		}
+
+		c := content[i]
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && inString:
+			escaped = true
+		case c == '"':
+			inString = !inString
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), actions
+}
+
+// restoreTemplateActions replaces the placeholders created by replaceTemplateActions with the original actions
+func restoreTemplateActions(content string, actions map[string]string) string {
+	for placeholder, action := range actions {
+		content = strings.ReplaceAll(content, placeholder, action)
+	}
+	return content
+}
+
+// asWritable prepares a loaded config to be written again. Loaded templates are converted to in-memory templates
+// keeping their original path.
+func asWritable(c config.Config) config.Config {
+	if path, ok := templatePath(c.Template); ok {
+		if content, err := c.Template.Content(); err == nil {
+			c.Template = v2template.NewInMemoryTemplateWithPath(path, content)
+		}
+	}
+	return c
+}
+
+func templatePath(t v2template.Template) (string, bool) {
+	switch t := t.(type) {
+	case *v2template.FileBasedTemplate:
+		return t.FilePath(), true
+	case *v2template.InMemoryTemplate:
+		if p := t.FilePath(); p != nil {
+			return *p, true
+		}
+	}
+	return "", false
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
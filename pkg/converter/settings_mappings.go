@@ -0,0 +1,77 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package converter
+
+// settingsMappings are the built-in rules to convert configs of classic APIs to their Settings 2.0 equivalents, by classic API.
+var settingsMappings = map[string]settingsMapping{
+	"alerting-profile": {
+		schemaId: "builtin:alerting.profile",
+		fields: []fieldMapping{
+			{from: "displayName", to: "name"},
+			{from: "mzId", to: "managementZone"},
+			{from: "rules", to: "severityRules", items: []fieldMapping{
+				{from: "severityLevel", to: "severityLevel"},
+				{from: "delayInMinutes", to: "delayInMinutes"},
+				{from: "tagFilter.includeMode", to: "tagFilterIncludeMode"},
+				{from: "tagFilter.tagFilters", to: "tagFilter"},
+			}},
+		},
+		ignored: []string{"id", "metadata"},
+	},
+	"auto-tag": {
+		schemaId: "builtin:tags.auto-tagging",
+		fields: []fieldMapping{
+			{from: "name", to: "name"},
+			{from: "description", to: "description"},
+			{from: "entitySelectorBasedRules", to: "rules", items: []fieldMapping{
+				{from: "enabled", to: "enabled"},
+				{from: "entitySelector", to: "entitySelector"},
+				{from: "valueFormat", to: "valueFormat"},
+				{from: "normalization", to: "valueNormalization", values: map[string]any{
+					"LEAVE_TEXT_AS_IS": "Leave text as-is",
+					"TO_LOWER_CASE":    "To lower case",
+					"TO_UPPER_CASE":    "To upper case",
+				}},
+			}, itemConstants: map[string]any{"type": "SELECTOR"}},
+		},
+		ignored: []string{"id", "metadata"},
+	},
+	"management-zone": {
+		schemaId: "builtin:management-zones",
+		fields: []fieldMapping{
+			{from: "name", to: "name"},
+			{from: "description", to: "description"},
+			{from: "entitySelectorBasedRules", to: "rules", items: []fieldMapping{
+				{from: "enabled", to: "enabled"},
+				{from: "entitySelector", to: "entitySelector"},
+			}, itemConstants: map[string]any{"type": "SELECTOR"}},
+		},
+		ignored: []string{"id", "metadata"},
+	},
+	"maintenance-window": {
+		schemaId: "builtin:alerting.maintenance-window",
+		fields: []fieldMapping{
+			{from: "name", to: "generalProperties.name"},
+			{from: "description", to: "generalProperties.description"},
+			{from: "type", to: "generalProperties.maintenanceType"},
+			{from: "suppression", to: "generalProperties.suppression"},
+			{from: "suppressSyntheticMonitorsExecution", to: "generalProperties.disableSyntheticMonitorExecution"},
+		},
+		constants: map[string]any{"enabled": true},
+		ignored:   []string{"id", "metadata"},
+	},
+}
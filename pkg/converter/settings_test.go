@@ -0,0 +1,196 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package converter
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	projectV2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSettingsMapping_Transform(t *testing.T) {
+	tests := []struct {
+		name             string
+		api              string
+		content          string
+		expected         string
+		expectedUnmapped []string
+	}{
+		{
+			name: "maps fields and keeps template actions",
+			api:  "alerting-profile",
+			content: `{
+  "id": "some-id",
+  "displayName": "{{ .name }}",
+  "mzId": {{ .mzId }},
+  "rules": [
+    {
+      "severityLevel": "AVAILABILITY",
+      "delayInMinutes": 5,
+      "tagFilter": {"includeMode": "NONE", "tagFilters": []}
+    }
+  ]
+}`,
+			expected: `{
+  "managementZone": {{ .mzId }},
+  "name": "{{ .name }}",
+  "severityRules": [
+    {
+      "delayInMinutes": 5,
+      "severityLevel": "AVAILABILITY",
+      "tagFilter": [],
+      "tagFilterIncludeMode": "NONE"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "translates enum values and sets constants",
+			api:     "auto-tag",
+			content: `{"name": "Tag of {{ .name }}", "entitySelectorBasedRules": [{"enabled": true, "entitySelector": "type(HOST)", "normalization": "TO_LOWER_CASE"}]}`,
+			expected: `{
+  "name": "Tag of {{ .name }}",
+  "rules": [
+    {
+      "enabled": true,
+      "entitySelector": "type(HOST)",
+      "type": "SELECTOR",
+      "valueNormalization": "To lower case"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "reports unmapped fields and values",
+			api:     "auto-tag",
+			content: `{"name": "tag", "rules": [{"type": "HOST"}], "entitySelectorBasedRules": [{"normalization": "UNKNOWN", "unknown": 1}]}`,
+			expected: `{
+  "name": "tag",
+  "rules": [
+    {
+      "type": "SELECTOR"
+    }
+  ]
+}
+`,
+			expectedUnmapped: []string{"entitySelectorBasedRules[].normalization", "entitySelectorBasedRules[].unknown", "rules"},
+		},
+		{
+			name:    "maps nested settings fields",
+			api:     "maintenance-window",
+			content: `{"name": "window", "type": "PLANNED", "suppression": "DONT_DETECT_PROBLEMS", "scope": {"entities": ["HOST-1"]}}`,
+			expected: `{
+  "enabled": true,
+  "generalProperties": {
+    "maintenanceType": "PLANNED",
+    "name": "window",
+    "suppression": "DONT_DETECT_PROBLEMS"
+  }
+}
+`,
+			expectedUnmapped: []string{"scope"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, unmapped, err := settingsMappings[tt.api].transform(tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectedUnmapped, unmapped)
+		})
+	}
+}
+
+func TestSettingsMapping_TransformFailsOnInvalidJSON(t *testing.T) {
+	_, _, err := settingsMappings["auto-tag"].transform(`{"name": `)
+	assert.Error(t, err)
+}
+
+func TestConvertToSettings(t *testing.T) {
+	tagCoordinate := coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "tag"}
+	dashboardCoordinate := coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "dashboard"}
+
+	projects := []projectV2.Project{
+		{
+			Id: "p",
+			Configs: projectV2.ConfigsPerTypePerEnvironments{
+				"env": {
+					"auto-tag": {
+						{
+							Coordinate:  tagCoordinate,
+							Type:        config.ClassicApiType{Api: "auto-tag"},
+							Template:    template.NewInMemoryTemplateWithPath("p/auto-tag/tag.json", `{"name": "{{ .name }}", "rules": []}`),
+							Parameters:  config.Parameters{config.NameParameter: valueParam.New("my tag")},
+							Environment: "env",
+						},
+					},
+					"dashboard": {
+						{
+							Coordinate: dashboardCoordinate,
+							Type:       config.ClassicApiType{Api: "dashboard"},
+							Template:   template.NewInMemoryTemplateWithPath("p/dashboard/dashboard.json", `{}`),
+							Parameters: config.Parameters{
+								"tagName": refParam.NewWithCoordinate(tagCoordinate, "name"),
+								"tagId":   refParam.NewWithCoordinate(tagCoordinate, config.IdParameter),
+							},
+							Environment: "env",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	report := &Report{}
+	result := ConvertToSettings(ConverterContext{Report: report}, projects)
+
+	require.Len(t, result, 1)
+	configs := result[0].Configs["env"]
+	assert.NotContains(t, configs, "auto-tag")
+
+	settingsCoordinate := coordinate.Coordinate{Project: "p", Type: "builtin:tags.auto-tagging", ConfigId: "tag"}
+	require.Len(t, configs["builtin:tags.auto-tagging"], 1)
+	converted := configs["builtin:tags.auto-tagging"][0]
+	assert.Equal(t, settingsCoordinate, converted.Coordinate)
+	assert.Equal(t, config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}, converted.Type)
+	assert.Equal(t, valueParam.New("environment"), converted.Parameters[config.ScopeParameter])
+	assert.Equal(t, valueParam.New("my tag"), converted.Parameters[config.NameParameter])
+	content, err := converted.Template.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"{{ .name }}\"\n}\n", content)
+
+	require.Len(t, configs["dashboard"], 1)
+	dashboard := configs["dashboard"][0]
+	assert.Equal(t, refParam.NewWithCoordinate(settingsCoordinate, "name"), dashboard.Parameters["tagName"])
+	assert.Equal(t, refParam.NewWithCoordinate(settingsCoordinate, config.IdParameter), dashboard.Parameters["tagId"])
+
+	assert.Equal(t, []ConvertedConfig{{Coordinate: settingsCoordinate, SourceTemplate: "p/auto-tag/tag.json"}}, report.Configs)
+	assert.Len(t, report.ManualSteps, 2)
+	assert.Equal(t, dashboardCoordinate, report.ManualSteps[1].Coordinate)
+	assert.Equal(t, "tagId", report.ManualSteps[1].Parameter)
+}
@@ -0,0 +1,212 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package archive downloads configurations before they are deleted, so that accidentally deleted configurations can be
+// restored by deploying the archived project.
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/settings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"slices"
+)
+
+// projectName is the name of the project archived configurations are written to
+const projectName = "archive"
+
+// Selector decides whether a downloaded configuration is archived.
+type Selector func(c config.Config) bool
+
+// Archive downloads the configurations of the given types from an environment and writes all selected ones as a monaco
+// project to a timestamped folder within the given folder. The written manifest points to the environment, so
+// archived configurations can be restored by deploying it. The folder the project was written to is returned.
+//
+// Types may be classic APIs, settings schemas, automation resources or "bucket". Types which can't be downloaded, as
+// their client is unavailable, are skipped.
+func Archive(ctx context.Context, fs afero.Fs, folder string, env manifest.EnvironmentDefinition, clients delete.ClientSet, apis api.APIs, types []string, selected Selector) (string, error) {
+	configs, err := downloadTypes(ctx, clients, apis, types)
+	if err != nil {
+		return "", fmt.Errorf("failed to download configurations to archive: %w", err)
+	}
+
+	count := 0
+	for t, cfgs := range configs {
+		configs[t] = slices.DeleteFunc(cfgs, func(c config.Config) bool { return !selected(c) })
+		count += len(configs[t])
+	}
+	if count == 0 {
+		log.WithCtxFields(ctx).Info("No existing configurations to archive")
+		return "", nil
+	}
+
+	outputFolder := filepath.Join(folder, fmt.Sprintf("%s_%s", env.Name, timeutils.TimeAnchor().Format("2006-01-02-150405")))
+	err = download.WriteToDisk(fs, download.WriterContext{
+		EnvironmentUrl: env.URL.Value,
+		ProjectToWrite: download.CreateProjectData(configs, projectName),
+		Auth:           env.Auth,
+		OutputFolder:   outputFolder,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	log.WithCtxFields(ctx).WithFields(field.F("archiveFolder", outputFolder)).Info("Archived %d configurations to %q - deploy its manifest to restore them", count, outputFolder)
+	return outputFolder, nil
+}
+
+func downloadTypes(ctx context.Context, clients delete.ClientSet, apis api.APIs, types []string) (project.ConfigsPerType, error) {
+	logger := log.WithCtxFields(ctx)
+	result := make(project.ConfigsPerType)
+	copyConfigs := func(configs project.ConfigsPerType) {
+		for t, cfgs := range configs {
+			result[t] = append(result[t], cfgs...)
+		}
+	}
+
+	classicAPIs := make(api.APIs)
+	var schemas []config.SettingsType
+	var errs []error
+	for _, t := range types {
+		switch {
+		case t == "bucket":
+			if clients.Buckets == nil {
+				logger.Warn("Skipped archiving Grail Bucket configurations as API client was unavailable.")
+				continue
+			}
+			configs, err := bucket.Download(clients.Buckets, projectName)
+			errs = append(errs, err)
+			copyConfigs(configs)
+		case isAutomationResource(t):
+			if clients.Automation == nil {
+				logger.Warn("Skipped archiving Automation configurations of type %q as API client was unavailable.", t)
+				continue
+			}
+			configs, err := automation.Download(clients.Automation, projectName, config.AutomationType{Resource: config.AutomationResource(t)})
+			errs = append(errs, err)
+			copyConfigs(configs)
+		case apis.Contains(t):
+			classicAPIs[t] = apis[t]
+		default: // assume it's a Settings Schema
+			schemas = append(schemas, config.SettingsType{SchemaId: t})
+		}
+	}
+
+	if len(classicAPIs) > 0 {
+		configs, err := classic.Download(clients.Classic, projectName, classicAPIs, nil, scope.Filter{})
+		errs = append(errs, err)
+		copyConfigs(configs)
+	}
+	if len(schemas) > 0 {
+		configs, err := settings.Download(clients.Settings, projectName, nil, scope.Filter{}, schemas...)
+		errs = append(errs, err)
+		copyConfigs(configs)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func isAutomationResource(t string) bool {
+	switch config.AutomationResource(t) {
+	case config.Workflow, config.SchedulingRule, config.BusinessCalendar:
+		return true
+	}
+	return false
+}
+
+// ObjectsSelector selects the configurations of the given objects, as collected by delete.Collect.
+func ObjectsSelector(objects []pointer.RemoteObject) Selector {
+	ids := make(map[string]struct{}, len(objects))
+	for _, o := range objects {
+		ids[o.ID] = struct{}{}
+	}
+	return func(c config.Config) bool {
+		_, found := ids[objectIDOf(c)]
+		return found
+	}
+}
+
+// EntriesSelector selects the configurations identified by the given delete entries. Entries selecting objects by
+// criteria select all configurations of their type, as their criteria can only be evaluated on the live objects.
+func EntriesSelector(entries delete.DeleteEntries) Selector {
+	return func(c config.Config) bool {
+		for _, e := range entries[c.Coordinate.Type] {
+			if matches(e, c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func matches(e pointer.DeletePointer, c config.Config) bool {
+	switch {
+	case e.Criteria != nil:
+		return true
+	case e.OriginObjectId != "":
+		return e.OriginObjectId == objectIDOf(c)
+	case e.Project == "": // classic configs and automations identified by their name or title
+		return nameOf(c) == e.Identifier
+	case isAutomationResource(e.Type):
+		return idutils.GenerateUUIDFromCoordinate(e.AsCoordinate()) == c.OriginObjectId
+	default: // settings identified by the external ID generated from their coordinate, or by its legacy variant
+		externalID, err := idutils.GenerateExternalIDForSettingsObject(e.AsCoordinate())
+		if err == nil && externalID == c.OriginExternalId {
+			return true
+		}
+		legacy := e.AsCoordinate()
+		legacy.Project = ""
+		legacyExternalID, err := idutils.GenerateExternalIDForSettingsObject(legacy)
+		return err == nil && legacyExternalID == c.OriginExternalId
+	}
+}
+
+// objectIDOf returns the Dynatrace ID of a downloaded configuration. Downloaded classic configurations have no origin
+// object ID, but use their Dynatrace ID as config ID.
+func objectIDOf(c config.Config) string {
+	if c.OriginObjectId != "" {
+		return c.OriginObjectId
+	}
+	return c.Coordinate.ConfigId
+}
+
+func nameOf(c config.Config) string {
+	if p, ok := c.Parameters[config.NameParameter].(*valueParam.ValueParameter); ok {
+		if name, ok := p.Value.(string); ok {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package archive_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/archive"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestObjectsSelector(t *testing.T) {
+	selected := archive.ObjectsSelector([]pointer.RemoteObject{{Type: "builtin:alerting.profile", ID: "object-id"}, {Type: "alerting-profile", ID: "classic-id"}})
+
+	assert.True(t, selected(config.Config{OriginObjectId: "object-id"}))
+	assert.True(t, selected(config.Config{Coordinate: coordinate.Coordinate{Type: "alerting-profile", ConfigId: "classic-id"}}))
+	assert.False(t, selected(config.Config{OriginObjectId: "other-id"}))
+}
+
+func TestEntriesSelector(t *testing.T) {
+	settingsCoordinate := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	externalID, err := idutils.GenerateExternalIDForSettingsObject(settingsCoordinate)
+	require.NoError(t, err)
+	workflowCoordinate := coordinate.Coordinate{Project: "project", Type: "workflow", ConfigId: "workflow"}
+
+	selected := archive.EntriesSelector(delete.DeleteEntries{
+		"alerting-profile": {{Type: "alerting-profile", Identifier: "my profile"}},
+		"builtin:alerting.profile": {
+			{Project: "project", Type: "builtin:alerting.profile", Identifier: "profile"},
+			{Type: "builtin:alerting.profile", OriginObjectId: "object-id"},
+		},
+		"workflow":          {{Project: "project", Type: "workflow", Identifier: "workflow"}},
+		"synthetic-monitor": {{Type: "synthetic-monitor", Criteria: &pointer.Criteria{Name: "tmp-*"}}},
+	})
+
+	named := func(t, name string) config.Config {
+		return config.Config{
+			Coordinate: coordinate.Coordinate{Type: t, ConfigId: "id"},
+			Parameters: map[string]parameter.Parameter{config.NameParameter: &value.ValueParameter{Value: name}},
+		}
+	}
+	settings := func(externalID, objectID string) config.Config {
+		return config.Config{Coordinate: coordinate.Coordinate{Type: "builtin:alerting.profile"}, OriginExternalId: externalID, OriginObjectId: objectID}
+	}
+
+	assert.True(t, selected(named("alerting-profile", "my profile")), "classic config by name")
+	assert.False(t, selected(named("alerting-profile", "other profile")), "classic config with other name")
+	assert.True(t, selected(settings(externalID, "some-id")), "settings by coordinate")
+	assert.True(t, selected(settings("", "object-id")), "settings by object ID")
+	assert.False(t, selected(settings("monaco:other", "other-id")), "other settings")
+	assert.True(t, selected(config.Config{Coordinate: coordinate.Coordinate{Type: "workflow"}, OriginObjectId: idutils.GenerateUUIDFromCoordinate(workflowCoordinate)}), "workflow by coordinate")
+	assert.True(t, selected(named("synthetic-monitor", "any name")), "all configs of types with criteria")
+	assert.False(t, selected(named("management-zone", "my profile")), "config of other type")
+}
+
+func TestArchive(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(dtclient.SchemaList{{SchemaId: "builtin:alerting.profile"}}, nil)
+	c.EXPECT().GetSchemaById("builtin:alerting.profile").Return(dtclient.Schema{SchemaId: "builtin:alerting.profile"}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+		{ObjectId: "deleted", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: []byte(`{"name": "deleted"}`)},
+		{ObjectId: "kept", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: []byte(`{"name": "kept"}`)},
+	}, nil)
+
+	fs := afero.NewMemMapFs()
+	env := manifest.EnvironmentDefinition{
+		Name: "env",
+		URL:  manifest.URLDefinition{Type: manifest.ValueURLType, Value: "https://example.com"},
+		Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "TOKEN"}},
+	}
+
+	folder, err := archive.Archive(context.TODO(), fs, "archive", env, delete.ClientSet{Settings: c}, api.NewAPIs(), []string{"builtin:alerting.profile"},
+		archive.ObjectsSelector([]pointer.RemoteObject{{Type: "builtin:alerting.profile", ID: "deleted"}}))
+	require.NoError(t, err)
+	assert.Equal(t, "archive", filepath.Dir(folder))
+
+	exists, err := afero.Exists(fs, filepath.Join(folder, "manifest.yaml"))
+	require.NoError(t, err)
+	assert.True(t, exists, "expected manifest to be written")
+
+	configFile, err := afero.ReadFile(fs, filepath.Join(folder, "archive", "builtinalerting.profile", "config.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(configFile), "originObjectId: deleted")
+	assert.NotContains(t, string(configFile), "originObjectId: kept")
+}
+
+func TestArchive_NothingSelected(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas().Return(dtclient.SchemaList{{SchemaId: "builtin:alerting.profile"}}, nil)
+	c.EXPECT().GetSchemaById("builtin:alerting.profile").Return(dtclient.Schema{SchemaId: "builtin:alerting.profile"}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return([]dtclient.DownloadSettingsObject{}, nil)
+
+	fs := afero.NewMemMapFs()
+	folder, err := archive.Archive(context.TODO(), fs, "archive", manifest.EnvironmentDefinition{Name: "env"}, delete.ClientSet{Settings: c}, api.NewAPIs(), []string{"builtin:alerting.profile"},
+		archive.ObjectsSelector(nil))
+	require.NoError(t, err)
+	assert.Empty(t, folder)
+
+	exists, err := afero.DirExists(fs, "archive")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
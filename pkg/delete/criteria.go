@@ -0,0 +1,91 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/setting"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"slices"
+)
+
+// splitCriteria separates entries identifying a single object from the criteria of entries selecting objects by their
+// metadata.
+func splitCriteria(entries []pointer.DeletePointer) ([]pointer.DeletePointer, []pointer.Criteria) {
+	var identified []pointer.DeletePointer
+	var criteria []pointer.Criteria
+	for _, e := range entries {
+		if e.Criteria != nil {
+			criteria = append(criteria, *e.Criteria)
+		} else {
+			identified = append(identified, e)
+		}
+	}
+	return identified, criteria
+}
+
+// deleteMatching collects all objects of the given type that match any of the given criteria and deletes them.
+func deleteMatching(ctx context.Context, clients ClientSet, apis api.APIs, automationResources map[string]config.AutomationResource, entryType string, criteria []pointer.Criteria, results *summary.Summary) error {
+	logger := log.WithCtxFields(ctx).WithFields(field.Type(entryType))
+	resource, isAutomation := automationResources[entryType]
+	theAPI, isClassic := apis[entryType]
+
+	var objects []pointer.RemoteObject
+	var errs error
+	for _, c := range criteria {
+		var matching []pointer.RemoteObject
+		var err error
+		switch {
+		case isAutomation:
+			matching, err = automation.CollectMatching(ctx, clients.Automation, resource, c)
+		case isClassic:
+			matching, err = classic.CollectMatching(ctx, clients.Classic, theAPI, c)
+		default: // assume it's a Settings Schema
+			matching, err = setting.CollectMatching(ctx, clients.Settings, entryType, c)
+		}
+		errs = errors.Join(errs, err)
+
+		logger.Info("Found %d configuration(s) of type %q matching criteria %s", len(matching), entryType, c)
+		for _, o := range matching {
+			if !slices.ContainsFunc(objects, func(other pointer.RemoteObject) bool { return other.ID == o.ID }) {
+				objects = append(objects, o)
+			}
+		}
+	}
+
+	if len(objects) == 0 {
+		return errs
+	}
+
+	switch {
+	case isAutomation:
+		errs = errors.Join(errs, automation.DeleteObjects(ctx, clients.Automation, objects, results))
+	case isClassic:
+		errs = errors.Join(errs, classic.DeleteObjects(ctx, clients.Classic, apis, objects, results))
+	default:
+		errs = errors.Join(errs, setting.DeleteObjects(ctx, clients.Settings, objects, results))
+	}
+	return errs
+}
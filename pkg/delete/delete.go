@@ -18,17 +18,24 @@ package delete
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/bucket"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/classic"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/setting"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"golang.org/x/exp/maps"
+	"slices"
 )
 
 type ClientSet struct {
@@ -45,7 +52,19 @@ type DeleteEntries = map[configurationType][]pointer.DeletePointer
 
 // Configs removes all given entriesToDelete from the Dynatrace environment the given client connects to
 func Configs(ctx context.Context, clients ClientSet, apis api.APIs, automationResources map[string]config.AutomationResource, entriesToDelete DeleteEntries) error {
+	return ConfigsInOrder(ctx, clients, apis, automationResources, entriesToDelete, nil)
+}
+
+// ConfigsInOrder removes all given entriesToDelete from the Dynatrace environment the given client connects to.
+// Configuration types are deleted in reverse order of the given dependencies, so that configurations are removed
+// before the ones they reference. If deleting a type fails, the types which may still reference it are logged.
+// Configurations of the same type are deleted concurrently, backing off when rate limited, and the number of deleted
+// and failed configurations per type is logged at the end.
+func ConfigsInOrder(ctx context.Context, clients ClientSet, apis api.APIs, automationResources map[string]config.AutomationResource, entriesToDelete DeleteEntries, dependencies TypeDependencies) error {
 	var deleteErrors int
+	typesToDelete := maps.Keys(entriesToDelete)
+	results := summary.New()
+	defer results.Log(ctx)
 
 	// Delete automation resources (in the specified order)
 	automationTypeOrder := []config.AutomationResource{config.Workflow, config.SchedulingRule, config.BusinessCalendar}
@@ -56,7 +75,11 @@ func Configs(ctx context.Context, clients ClientSet, apis api.APIs, automationRe
 			delete(entriesToDelete, string(key))
 			continue
 		}
-		err := automation.Delete(ctx, clients.Automation, automationResources[string(key)], entries)
+		entries, criteria := splitCriteria(entries)
+		err := automation.Delete(ctx, clients.Automation, automationResources[string(key)], entries, results)
+		if len(criteria) > 0 {
+			err = errors.Join(err, deleteMatching(ctx, clients, apis, automationResources, string(key), criteria, results))
+		}
 		if err != nil {
 			log.WithFields(field.Error(err)).Error("Error during deletion: %v", err)
 			deleteErrors += 1
@@ -72,22 +95,27 @@ func Configs(ctx context.Context, clients ClientSet, apis api.APIs, automationRe
 	}
 
 	// Delete rest of config types
-	for entryType, entries := range entriesToDelete {
+	for _, entryType := range orderTypes(maps.Keys(entriesToDelete), dependencies) {
+		entries, criteria := splitCriteria(entriesToDelete[entryType])
 		var err error
 		if theAPI, isClassicAPI := apis[entryType]; isClassicAPI {
-			err = classic.Delete(ctx, clients.Classic, theAPI, entries)
+			err = classic.Delete(ctx, clients.Classic, theAPI, entries, results)
 		} else if entryType == "bucket" {
 			if clients.Buckets == nil {
 				log.WithCtxFields(ctx).WithFields(field.Type(entryType)).Warn("Skipped deletion of %d Grail Bucket configuration(s) as API client was unavailable.", len(entries))
 				continue
 			}
-			err = bucket.Delete(ctx, clients.Buckets, entries)
+			err = bucket.Delete(ctx, clients.Buckets, entries, results)
 		} else { // assume it's a Settings Schema
-			err = setting.Delete(ctx, clients.Settings, entries)
+			err = setting.Delete(ctx, clients.Settings, entries, results)
+		}
+		if len(criteria) > 0 {
+			err = errors.Join(err, deleteMatching(ctx, clients, apis, automationResources, entryType, criteria, results))
 		}
 
 		if err != nil {
 			log.WithFields(field.Error(err)).Error("Error during deletion: %v", err)
+			logRemainingReferences(ctx, entryType, dependencies[entryType], typesToDelete)
 			deleteErrors += 1
 		}
 	}
@@ -98,6 +126,22 @@ func Configs(ctx context.Context, clients ClientSet, apis api.APIs, automationRe
 	return nil
 }
 
+// logRemainingReferences warns about the types that may still reference configurations of a type which could not be
+// deleted. Referencing types that are not part of the deletion are the likely cause for deletion being blocked.
+func logRemainingReferences(ctx context.Context, entryType configurationType, referencingTypes []configurationType, typesToDelete []configurationType) {
+	var notDeleted []configurationType
+	for _, t := range referencingTypes {
+		if !slices.Contains(typesToDelete, t) {
+			notDeleted = append(notDeleted, t)
+		}
+	}
+	if len(notDeleted) > 0 {
+		log.WithCtxFields(ctx).WithFields(field.Type(entryType)).Warn("Configurations of type %q may still be referenced by configurations of types %v, which are not part of the deletion. Delete or update them first.", entryType, notDeleted)
+	} else if len(referencingTypes) > 0 {
+		log.WithCtxFields(ctx).WithFields(field.Type(entryType)).Warn("Configurations of type %q may still be referenced by configurations of types %v.", entryType, referencingTypes)
+	}
+}
+
 // All collects and deletes ALL configuration objects using the provided ClientSet.
 // To delete specific configurations use Configs instead!
 //
@@ -105,30 +149,125 @@ func Configs(ctx context.Context, clients ClientSet, apis api.APIs, automationRe
 //   - ctx (context.Context): The context in which the function operates.
 //   - clients (ClientSet): A set of API clients used to collect and delete configurations from an environment.
 func All(ctx context.Context, clients ClientSet, apis api.APIs) error {
+	objects, collectErr := Collect(ctx, clients, apis, nil)
+	if err := Objects(ctx, clients, apis, objects); err != nil {
+		return err
+	}
+	return collectErr
+}
+
+// Collect lists ALL configuration objects All would delete using the provided ClientSet, except for objects of the
+// given excluded types. Excluded types may be classic APIs, settings schemas, automation resources or "bucket".
+// Objects that could be collected are returned even if collecting others failed.
+//
+// Parameters:
+//   - ctx (context.Context): The context in which the function operates.
+//   - clients (ClientSet): A set of API clients used to collect configurations from an environment.
+//   - apis (api.APIs): The classic APIs to collect configurations of.
+//   - excludedTypes ([]string): The types of configurations not to collect.
+func Collect(ctx context.Context, clients ClientSet, apis api.APIs, excludedTypes []string) ([]pointer.RemoteObject, error) {
+	var objects []pointer.RemoteObject
 	errs := 0
 
-	if err := classic.DeleteAll(ctx, clients.Classic, apis); err != nil {
-		log.Error("Failed to delete all classic API configurations: %v", err)
+	classicObjects, err := classic.CollectAll(ctx, clients.Classic, apis.Filter(func(a api.API) bool { return slices.Contains(excludedTypes, a.ID) }))
+	if err != nil {
+		log.Error("Failed to collect all classic API configurations: %v", err)
 		errs++
 	}
+	objects = append(objects, classicObjects...)
 
-	if err := setting.DeleteAll(ctx, clients.Settings); err != nil {
-		log.Error("Failed to delete all Settings 2.0 objects: %v", err)
+	settingsObjects, err := setting.CollectAll(ctx, clients.Settings, excludedTypes, nil)
+	if err != nil {
+		log.Error("Failed to collect all Settings 2.0 objects: %v", err)
 		errs++
 	}
+	objects = append(objects, settingsObjects...)
 
 	if clients.Automation == nil {
-		log.Warn("Skipped deletion of Automation configurations as API client was unavailable.")
-	} else if err := automation.DeleteAll(ctx, clients.Automation); err != nil {
-		log.Error("Failed to delete all Automation configurations: %v", err)
-		errs++
+		log.Warn("Skipped Automation configurations as API client was unavailable.")
+	} else {
+		automationObjects, err := automation.CollectAll(ctx, clients.Automation, excludedTypes)
+		if err != nil {
+			log.Error("Failed to collect all Automation configurations: %v", err)
+			errs++
+		}
+		objects = append(objects, automationObjects...)
 	}
 
 	if clients.Buckets == nil {
-		log.Warn("Skipped deletion of Grail Bucket configurations as API client was unavailable.")
-	} else if err := bucket.DeleteAll(ctx, clients.Buckets); err != nil {
-		log.Error("Failed to delete all Grail Bucket configurations: %v", err)
-		errs++
+		log.Warn("Skipped Grail Bucket configurations as API client was unavailable.")
+	} else if !slices.Contains(excludedTypes, "bucket") {
+		bucketObjects, err := bucket.CollectAll(ctx, clients.Buckets)
+		if err != nil {
+			log.Error("Failed to collect all Grail Bucket configurations: %v", err)
+			errs++
+		}
+		objects = append(objects, bucketObjects...)
+	}
+
+	if errs > 0 {
+		return objects, fmt.Errorf("failed to collect all configurations for %d types", errs)
+	}
+	return objects, nil
+}
+
+// Objects deletes the given configuration objects, as returned by Collect, by their Dynatrace ID using the provided
+// ClientSet. Objects are deleted concurrently, backing off when rate limited, and the number of deleted and failed
+// objects per type is logged at the end.
+func Objects(ctx context.Context, clients ClientSet, apis api.APIs, objects []pointer.RemoteObject) error {
+	var classicObjects, settingsObjects, automationObjects, bucketObjects []pointer.RemoteObject
+	for _, o := range objects {
+		switch {
+		case o.Type == "bucket":
+			bucketObjects = append(bucketObjects, o)
+		case isAutomationResource(o.Type):
+			automationObjects = append(automationObjects, o)
+		case apis[o.Type].ID != "":
+			classicObjects = append(classicObjects, o)
+		default: // assume it's a Settings Schema
+			settingsObjects = append(settingsObjects, o)
+		}
+	}
+
+	errs := 0
+	logger := log.WithCtxFields(ctx)
+	results := summary.New()
+	defer results.Log(ctx)
+
+	if len(classicObjects) > 0 {
+		logger.Info("Deleting %d classic API configurations...", len(classicObjects))
+		if err := classic.DeleteObjects(ctx, clients.Classic, apis, classicObjects, results); err != nil {
+			log.Error("Failed to delete all classic API configurations: %v", err)
+			errs++
+		}
+	}
+
+	if len(settingsObjects) > 0 {
+		logger.Info("Deleting %d Settings 2.0 objects...", len(settingsObjects))
+		if err := setting.DeleteObjects(ctx, clients.Settings, settingsObjects, results); err != nil {
+			log.Error("Failed to delete all Settings 2.0 objects: %v", err)
+			errs++
+		}
+	}
+
+	if len(automationObjects) > 0 && clients.Automation == nil {
+		log.Warn("Skipped deletion of %d Automation configurations as API client was unavailable.", len(automationObjects))
+	} else if len(automationObjects) > 0 {
+		logger.Info("Deleting %d Automation configurations...", len(automationObjects))
+		if err := automation.DeleteObjects(ctx, clients.Automation, automationObjects, results); err != nil {
+			log.Error("Failed to delete all Automation configurations: %v", err)
+			errs++
+		}
+	}
+
+	if len(bucketObjects) > 0 && clients.Buckets == nil {
+		log.Warn("Skipped deletion of %d Grail Bucket configurations as API client was unavailable.", len(bucketObjects))
+	} else if len(bucketObjects) > 0 {
+		logger.Info("Deleting %d Grail Bucket configurations...", len(bucketObjects))
+		if err := bucket.DeleteObjects(ctx, clients.Buckets, bucketObjects, results); err != nil {
+			log.Error("Failed to delete all Grail Bucket configurations: %v", err)
+			errs++
+		}
 	}
 
 	if errs > 0 {
@@ -136,3 +275,32 @@ func All(ctx context.Context, clients ClientSet, apis api.APIs) error {
 	}
 	return nil
 }
+
+// CollectOrphans lists the Settings 2.0 objects monaco deployed for one of the given projects whose coordinate is not
+// part of the given known coordinates anymore, e.g. because the config was removed from the project.
+// Only settings objects are considered, as their externalId identifies the project and config they were deployed for.
+// Objects with legacy external IDs, which do not contain the project, are never considered orphaned.
+func CollectOrphans(ctx context.Context, clients ClientSet, projects []string, known []coordinate.Coordinate) ([]pointer.RemoteObject, error) {
+	knownSet := make(map[coordinate.Coordinate]struct{}, len(known))
+	for _, c := range known {
+		knownSet[c] = struct{}{}
+	}
+
+	isOrphan := func(o dtclient.DownloadSettingsObject) bool {
+		c, err := idutils.DecodeExternalIDForSettingsObject(o.ExternalId)
+		if err != nil || !slices.Contains(projects, c.Project) {
+			return false
+		}
+		_, exists := knownSet[c]
+		return !exists
+	}
+	return setting.CollectAll(ctx, clients.Settings, nil, isOrphan)
+}
+
+func isAutomationResource(t string) bool {
+	switch config.AutomationResource(t) {
+	case config.Workflow, config.SchedulingRule, config.BusinessCalendar:
+		return true
+	}
+	return false
+}
@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/api/rest"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
@@ -166,6 +167,45 @@ func TestDeleteSettings(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("TestDeleteSettings - Objects with legacy external ID are deleted", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Eq("builtin:alerting.profile"), gomock.Any()).DoAndReturn(func(ctx context.Context, schemaID string, listOpts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+			legacyExtID := "monaco:YnVpbHRpbjphbGVydGluZy5wcm9maWxlJGlkMQ=="
+			assert.True(t, listOpts.Filter(dtclient.DownloadSettingsObject{ExternalId: legacyExtID}), "Expected request filtering for legacy externalID %q", legacyExtID)
+			assert.False(t, listOpts.Filter(dtclient.DownloadSettingsObject{ExternalId: "monaco:other"}))
+			return []dtclient.DownloadSettingsObject{{ExternalId: legacyExtID, SchemaId: "builtin:alerting.profile", ObjectId: "12345"}}, nil
+		})
+		c.EXPECT().DeleteSettings(gomock.Eq("12345")).Return(nil)
+		entriesToDelete := delete.DeleteEntries{
+			"builtin:alerting.profile": {
+				{
+					Type:       "builtin:alerting.profile",
+					Project:    "project",
+					Identifier: "id1",
+				},
+			},
+		}
+		err := delete.Configs(context.TODO(), delete.ClientSet{Settings: c}, api.NewAPIs(), automationTypes, entriesToDelete)
+		assert.NoError(t, err)
+	})
+
+	t.Run("TestDeleteSettings - Delete of referenced object fails", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return([]dtclient.DownloadSettingsObject{{SchemaId: "builtin:alerting.profile", ObjectId: "12345"}}, nil)
+		c.EXPECT().DeleteSettings(gomock.Eq("12345")).Return(monacoREST.RespError{StatusCode: http.StatusBadRequest})
+		entriesToDelete := delete.DeleteEntries{
+			"builtin:alerting.profile": {
+				{
+					Type:       "builtin:alerting.profile",
+					Project:    "project",
+					Identifier: "id1",
+				},
+			},
+		}
+		err := delete.Configs(context.TODO(), delete.ClientSet{Settings: c}, api.NewAPIs(), automationTypes, entriesToDelete)
+		assert.Error(t, err)
+	})
+
 	t.Run("TestDeleteSettings - List settings with external ID fails", func(t *testing.T) {
 		c := client.NewMockDynatraceClient(gomock.NewController(t))
 		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return([]dtclient.DownloadSettingsObject{}, monacoREST.RespError{Err: fmt.Errorf("WHOPS"), StatusCode: 0})
@@ -1035,3 +1075,130 @@ func TestDeleteClassicKeyUserActionsWeb(t *testing.T) {
 	})
 
 }
+
+func TestCollectAndDeleteObjects(t *testing.T) {
+	apis := api.NewAPIs().Filter(api.RetainByName([]string{"alerting-profile", "management-zone"}))
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), matcher.EqAPI(apis["alerting-profile"])).Return([]dtclient.Value{{Id: "ap-1", Name: "profile"}}, nil)
+	c.EXPECT().ListSchemas().Return(dtclient.SchemaList{{SchemaId: "builtin:alerting.profile"}, {SchemaId: "builtin:excluded"}}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+		{ObjectId: "so-1", Created: 1704164645000, ModifiedBy: "someone"},
+		{ObjectId: "so-2", ModificationInfo: &dtclient.SettingsModificationInfo{Deletable: false}},
+	}, nil)
+
+	clients := delete.ClientSet{Classic: c, Settings: c}
+	objects, err := delete.Collect(context.TODO(), clients, apis, []string{"management-zone", "builtin:excluded"})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []pointer.RemoteObject{
+		{Type: "alerting-profile", ID: "ap-1", Name: "profile"},
+		{Type: "builtin:alerting.profile", ID: "so-1", Created: time.UnixMilli(1704164645000), LastModifiedBy: "someone"},
+	}, objects)
+
+	c.EXPECT().DeleteConfigById(matcher.EqAPI(apis["alerting-profile"]), "ap-1").Return(nil)
+	c.EXPECT().DeleteSettings("so-1").Return(nil)
+
+	err = delete.Objects(context.TODO(), clients, apis, objects)
+	assert.NoError(t, err)
+}
+
+func TestDeleteByCriteria(t *testing.T) {
+	t.Run("classic configs matching name and tags", func(t *testing.T) {
+		theAPI := api.NewAPIs()["synthetic-monitor"]
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), matcher.EqAPI(theAPI)).Return([]dtclient.Value{
+			{Id: "tagged", Name: "tmp-tagged"},
+			{Id: "untagged", Name: "tmp-untagged"},
+			{Id: "other", Name: "production"},
+		}, nil)
+		c.EXPECT().ReadConfigById(matcher.EqAPI(theAPI), "tagged").Return([]byte(`{"tags": [{"key": "temporary", "context": "CONTEXTLESS"}]}`), nil)
+		c.EXPECT().ReadConfigById(matcher.EqAPI(theAPI), "untagged").Return([]byte(`{"tags": []}`), nil)
+		c.EXPECT().DeleteConfigById(matcher.EqAPI(theAPI), "tagged").Return(nil)
+
+		given := delete.DeleteEntries{
+			"synthetic-monitor": {
+				{
+					Type:     "synthetic-monitor",
+					Criteria: &pointer.Criteria{Name: "tmp-*", Tags: []string{"temporary"}},
+				},
+			},
+		}
+
+		err := delete.Configs(context.TODO(), delete.ClientSet{Classic: c}, api.NewAPIs(), automationTypes, given)
+		require.NoError(t, err)
+	})
+
+	t.Run("settings objects older than given age", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+			{ObjectId: "old", Created: time.Now().Add(-48 * time.Hour).UnixMilli()},
+			{ObjectId: "new", Created: time.Now().UnixMilli()},
+			{ObjectId: "unknown"},
+		}, nil)
+		c.EXPECT().DeleteSettings("old").Return(nil)
+
+		given := delete.DeleteEntries{
+			"builtin:alerting.profile": {
+				{
+					Type:     "builtin:alerting.profile",
+					Criteria: &pointer.Criteria{OlderThan: 24 * time.Hour},
+				},
+			},
+		}
+
+		err := delete.Configs(context.TODO(), delete.ClientSet{Settings: c}, api.NewAPIs(), automationTypes, given)
+		require.NoError(t, err)
+	})
+}
+
+func TestDeleteAutomationsByTitle(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "workflows") {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"count": 3, "results": [
+				{"id": "unique-id", "title": "unique workflow"},
+				{"id": "duplicate-1", "title": "duplicate workflow"},
+				{"id": "duplicate-2", "title": "duplicate workflow"}
+			]}`))
+			return
+		}
+		if req.Method == http.MethodDelete && strings.Contains(req.RequestURI, "workflows") {
+			deleted = append(deleted, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+			rw.WriteHeader(http.StatusOK)
+			return
+		}
+		assert.Fail(t, "unexpected HTTP call")
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	c := automation.NewClient(rest.NewClient(serverURL, server.Client()))
+
+	t.Run("unique title is resolved", func(t *testing.T) {
+		deleted = nil
+		entriesToDelete := delete.DeleteEntries{
+			"workflow": {
+				{Type: "workflow", Identifier: "unique workflow"},
+				{Type: "workflow", Identifier: "unknown workflow"},
+			},
+		}
+		err := delete.Configs(context.TODO(), delete.ClientSet{Automation: c}, api.NewAPIs(), automationTypes, entriesToDelete)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"unique-id"}, deleted)
+	})
+
+	t.Run("ambiguous title is not deleted", func(t *testing.T) {
+		deleted = nil
+		entriesToDelete := delete.DeleteEntries{
+			"workflow": {
+				{Type: "workflow", Identifier: "duplicate workflow"},
+			},
+		}
+		err := delete.Configs(context.TODO(), delete.ClientSet{Automation: c}, api.NewAPIs(), automationTypes, entriesToDelete)
+		assert.Error(t, err)
+		assert.Empty(t, deleted)
+	})
+}
@@ -17,9 +17,12 @@
 package automation
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/api"
 	automationAPI "github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/automation"
@@ -29,6 +32,8 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/parallel"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
 	"golang.org/x/net/context"
 )
@@ -38,42 +43,57 @@ type Client interface {
 	List(ctx context.Context, resourceType automationAPI.ResourceType) (automation.ListResponse, error)
 }
 
-func Delete(ctx context.Context, c Client, automationResource config.AutomationResource, entries []pointer.DeletePointer) error {
+// Delete removes the automation objects of the given entries. Entries are deleted concurrently, the result of each
+// deletion is counted in the given summary.Summary.
+func Delete(ctx context.Context, c Client, automationResource config.AutomationResource, entries []pointer.DeletePointer, s *summary.Summary) error {
 
 	logger := log.WithCtxFields(ctx).WithFields(field.Type(string(automationResource)))
 	logger.Info("Deleting %d config(s) of type %q...", len(entries), automationResource)
 
-	deleteErrs := 0
+	resourceType, err := automationutils.ClientResourceTypeFromConfigType(automationResource)
+	if err != nil {
+		return fmt.Errorf("failed to delete Automation objects of type %q: %w", automationResource, err)
+	}
 
-	for _, e := range entries {
+	// entries without project and object ID are identified by their title, which needs to be resolved to an ID
+	var known []pointer.RemoteObject
+	var collectErr error
+	if slices.ContainsFunc(entries, isIdentifiedByTitle) {
+		known, collectErr = CollectMatching(ctx, c, automationResource, pointer.Criteria{})
+	}
 
+	errs := parallel.ForEach(entries, func(e pointer.DeletePointer) error {
 		logger := logger.WithFields(field.Coordinate(e.AsCoordinate()))
 
 		id := e.OriginObjectId
-		if id == "" {
+		if isIdentifiedByTitle(e) {
+			var err error
+			if collectErr != nil {
+				return fmt.Errorf("unable to resolve title %q: %w", e.Identifier, collectErr)
+			} else if id, err = resolveTitle(known, e.Identifier); err != nil {
+				return fmt.Errorf("unable to resolve title %q: %w", e.Identifier, err)
+			} else if id == "" {
+				logger.Debug("%v with title %q doesn't exist - no need for action", automationResource, e.Identifier)
+				return nil
+			}
+		} else if id == "" {
 			id = idutils.GenerateUUIDFromCoordinate(e.AsCoordinate())
 		}
 
 		logger.Debug("Deleting %v with id %q.", automationResource, id)
-
-		resourceType, err := automationutils.ClientResourceTypeFromConfigType(automationResource)
-		if err != nil {
-			logger.WithFields(field.Error(err)).Error("Failed to delete %v with ID %q: %v", automationResource, id, err)
-			deleteErrs++
+		if err := deleteByID(ctx, c, resourceType, id); err != nil {
+			return fmt.Errorf("failed to delete ID %q - %w", id, err)
 		}
+		s.Deleted(string(automationResource))
+		return nil
+	})
 
-		_, err = c.Delete(ctx, resourceType, id)
+	deleteErrs := 0
+	for i, err := range errs {
 		if err != nil {
-			var apiErr api.APIError
-			if errors.As(err, &apiErr) {
-				if apiErr.StatusCode != http.StatusNotFound {
-					logger.WithFields(field.Error(err)).Error("Failed to delete %v with ID %q - rejected by API: %v", automationResource, id, err)
-					deleteErrs++
-				}
-			} else {
-				logger.WithFields(field.Error(err)).Error("Failed to delete %v with ID %q - network error: %v", automationResource, id, err)
-				deleteErrs++
-			}
+			logger.WithFields(field.Coordinate(entries[i].AsCoordinate()), field.Error(err)).Error("Failed to delete %v: %v", automationResource, err)
+			s.Failed(string(automationResource))
+			deleteErrs++
 		}
 	}
 
@@ -84,24 +104,70 @@ func Delete(ctx context.Context, c Client, automationResource config.AutomationR
 	return nil
 }
 
-// DeleteAll collects and deletes automations resources using the given automation client.
+// deleteByID deletes the automation object with the given ID. Objects which don't exist are not considered an error.
+func deleteByID(ctx context.Context, c Client, resourceType automationAPI.ResourceType, id string) error {
+	_, err := c.Delete(ctx, resourceType, id)
+	if err == nil {
+		return nil
+	}
+
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("rejected by API: %w", err)
+	}
+	return fmt.Errorf("network error: %w", err)
+}
+
+func isIdentifiedByTitle(e pointer.DeletePointer) bool {
+	return e.OriginObjectId == "" && e.Project == ""
+}
+
+// resolveTitle returns the ID of the single object with the given title, or an empty ID if no object has the title.
+func resolveTitle(objects []pointer.RemoteObject, title string) (string, error) {
+	var ids []string
+	for _, o := range objects {
+		if o.Name == title {
+			ids = append(ids, o.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("title is not unique - matching IDs are %v", ids)
+	}
+}
+
+// CollectAll collects all automation resources using the given automation client.
 //
 // Parameters:
 //   - ctx (context.Context): The context in which the function operates.
 //   - c (automationClient): An implementation of the automationClient interface for performing automation-related operations.
+//   - excludedTypes ([]string): Automation resource types whose objects are not collected.
 //
 // Returns:
-//   - error: After all deletions where attempted an error is returned if any attempt failed.
-func DeleteAll(ctx context.Context, c Client) error {
+//   - []pointer.RemoteObject: The collected automation objects.
+//   - error: After all resource types where collected an error is returned if any attempt failed.
+func CollectAll(ctx context.Context, c Client, excludedTypes []string) ([]pointer.RemoteObject, error) {
+	var objects []pointer.RemoteObject
 	errs := 0
 
 	resources := []config.AutomationResource{config.Workflow, config.SchedulingRule, config.BusinessCalendar}
 	for _, resource := range resources {
+		if slices.Contains(excludedTypes, string(resource)) {
+			continue
+		}
 		logger := log.WithCtxFields(ctx).WithFields(field.Type(string(resource)))
 
 		t, err := automationutils.ClientResourceTypeFromConfigType(resource)
 		if err != nil {
-			logger.Error("Failed to delete Automation objects of type %q: %v", err)
+			logger.Error("Failed to collect Automation objects of type %q: %v", resource, err)
 			errs++
 			continue
 		}
@@ -112,44 +178,96 @@ func DeleteAll(ctx context.Context, c Client) error {
 			var apiErr api.APIError
 			if errors.As(err, &apiErr) {
 				logger.WithFields(field.Error(err)).Error("Failed to collect Automation objects of type %q - rejected by API: %v", resource, err)
-				errs++
-				continue
 			} else {
 				logger.Error("Failed to collect Automation objects of type %q - network error: %v", resource, err)
-				errs++
-				continue
 			}
+			errs++
+			continue
 		}
 
-		objects, err := automationutils.DecodeListResponse(resp)
+		responses, err := automationutils.DecodeListResponse(resp)
 		if err != nil {
-			logger.WithFields(field.Error(err)).Error("ailed to collect Automation objects of type %q: %v", resource, err)
+			logger.WithFields(field.Error(err)).Error("Failed to collect Automation objects of type %q: %v", resource, err)
 			errs++
 			continue
 		}
 
-		logger.Info("Deleting %d objects of type %q...", len(objects), resource)
-		for _, o := range objects {
-			logger := logger.WithFields(field.F("object", o))
-			logger.Debug("Deleting Automation object with id %q...", o.ID)
-			_, err := c.Delete(ctx, t, o.ID)
-			if err != nil {
-				var apiErr api.APIError
-				if errors.As(err, &apiErr) {
-					if apiErr.StatusCode != http.StatusNotFound {
-						logger.WithFields(field.Error(err)).Error("Failed to delete %v with ID %q - rejected by API: %v", resource, o.ID, err)
-						errs++
-					}
-				} else {
-					logger.WithFields(field.Error(err)).Error("Failed to delete %v with ID %q - network error: %v", resource, o.ID, err)
-					errs++
-				}
-			}
+		for _, r := range responses {
+			objects = append(objects, toRemoteObject(resource, r))
 		}
 	}
 
 	if errs > 0 {
-		return fmt.Errorf("failed to delete %d Automation object(s)", errs)
+		return objects, fmt.Errorf("failed to collect %d Automation object type(s)", errs)
+	}
+	return objects, nil
+}
+
+// CollectMatching collects all objects of the given automation resource that match the given criteria.
+func CollectMatching(ctx context.Context, c Client, resource config.AutomationResource, criteria pointer.Criteria) ([]pointer.RemoteObject, error) {
+	others := slices.DeleteFunc([]string{string(config.Workflow), string(config.SchedulingRule), string(config.BusinessCalendar)}, func(t string) bool {
+		return t == string(resource)
+	})
+	objects, err := CollectAll(ctx, c, others)
+
+	now := time.Now()
+	return slices.DeleteFunc(objects, func(o pointer.RemoteObject) bool {
+		return !criteria.Matches(o, now)
+	}), err
+}
+
+// toRemoteObject reads the title and modification info of an automation object, which all automation resources share
+func toRemoteObject(resource config.AutomationResource, r automationutils.Response) pointer.RemoteObject {
+	var payload struct {
+		Title            string `json:"title"`
+		ModificationInfo struct {
+			CreatedTime    time.Time `json:"createdTime"`
+			LastModifiedBy string    `json:"lastModifiedBy"`
+		} `json:"modificationInfo"`
+	}
+	_ = json.Unmarshal(r.Data, &payload) // metadata is informational only
+
+	return pointer.RemoteObject{
+		Type:           string(resource),
+		ID:             r.ID,
+		Name:           payload.Title,
+		Created:        payload.ModificationInfo.CreatedTime,
+		LastModifiedBy: payload.ModificationInfo.LastModifiedBy,
+	}
+}
+
+// DeleteObjects deletes the given automation objects by their ID using the given automation client. Objects are
+// deleted concurrently, the result of each deletion is counted in the given summary.Summary.
+//
+// Returns:
+//   - error: After all deletions where attempted an error is returned if any attempt failed.
+func DeleteObjects(ctx context.Context, c Client, objects []pointer.RemoteObject, s *summary.Summary) error {
+	errs := parallel.ForEach(objects, func(o pointer.RemoteObject) error {
+		t, err := automationutils.ClientResourceTypeFromConfigType(config.AutomationResource(o.Type))
+		if err != nil {
+			return err
+		}
+
+		log.WithCtxFields(ctx).WithFields(field.Type(o.Type), field.F("object", o)).Debug("Deleting Automation object with id %q...", o.ID)
+		if err := deleteByID(ctx, c, t, o.ID); err != nil {
+			return err
+		}
+		s.Deleted(o.Type)
+		return nil
+	})
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			o := objects[i]
+			log.WithCtxFields(ctx).WithFields(field.Type(o.Type), field.F("object", o), field.Error(err)).Error("Failed to delete %v with ID %q - %v", o.Type, o.ID, err)
+			s.Failed(o.Type)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d Automation object(s)", failed)
 	}
 
 	return nil
@@ -28,6 +28,8 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/parallel"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
 	"golang.org/x/net/context"
 )
@@ -37,34 +39,33 @@ type Client interface {
 	List(ctx context.Context) (buckets.ListResponse, error)
 }
 
-func Delete(ctx context.Context, c Client, entries []pointer.DeletePointer) error {
+// Delete removes the buckets of the given entries. Entries are deleted concurrently, the result of each deletion is
+// counted in the given summary.Summary.
+func Delete(ctx context.Context, c Client, entries []pointer.DeletePointer, s *summary.Summary) error {
 
 	logger := log.WithCtxFields(ctx).WithFields(field.Type("bucket"))
 	logger.Info(`Deleting %d config(s) of type "bucket"...`, len(entries))
 
-	deleteErrs := 0
-	for _, e := range entries {
-
-		logger := logger.WithFields(field.Coordinate(e.AsCoordinate()))
-
+	errs := parallel.ForEach(entries, func(e pointer.DeletePointer) error {
 		bucketName := e.OriginObjectId
 		if e.OriginObjectId == "" {
 			bucketName = idutils.GenerateBucketName(e.AsCoordinate())
 		}
 
-		logger.Debug("Deleting bucket: %s.", e, bucketName)
-		_, err := c.Delete(ctx, bucketName)
+		logger.WithFields(field.Coordinate(e.AsCoordinate())).Debug("Deleting bucket: %s.", bucketName)
+		if err := deleteByName(ctx, c, bucketName); err != nil {
+			return err
+		}
+		s.Deleted("bucket")
+		return nil
+	})
+
+	deleteErrs := 0
+	for i, err := range errs {
 		if err != nil {
-			var apiErr api.APIError
-			if errors.As(err, &apiErr) {
-				if apiErr.StatusCode != http.StatusNotFound {
-					logger.WithFields(field.Error(err)).Error("Failed to delete Grail Bucket configuration - rejected by API: %v", e, bucketName, err)
-					deleteErrs++
-				}
-			} else {
-				logger.WithFields(field.Error(err)).Error("Failed to delete Grail Bucket configuration - network error: %v", e, bucketName, err)
-				deleteErrs++
-			}
+			logger.WithFields(field.Coordinate(entries[i].AsCoordinate()), field.Error(err)).Error("Failed to delete Grail Bucket configuration - %v", err)
+			s.Failed("bucket")
+			deleteErrs++
 		}
 	}
 
@@ -75,62 +76,97 @@ func Delete(ctx context.Context, c Client, entries []pointer.DeletePointer) erro
 	return nil
 }
 
-// AllBuckets collects and deletes objects of type "bucket" using the provided bucketClient.
+// deleteByName deletes the bucket with the given name. Buckets which don't exist are not considered an error.
+func deleteByName(ctx context.Context, c Client, bucketName string) error {
+	_, err := c.Delete(ctx, bucketName)
+	if err == nil {
+		return nil
+	}
+
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("rejected by API: %w", err)
+	}
+	return fmt.Errorf("network error: %w", err)
+}
+
+// CollectAll collects all non-default objects of type "bucket" using the provided bucketClient.
 //
 // Parameters:
 //   - ctx (context.Context): The context for the operation.
-//   - c (bucketClient): The bucketClient used for listing and deleting objects.
+//   - c (bucketClient): The bucketClient used for listing objects.
 //
 // Returns:
-//   - error: After all deletions where attempted an error is returned if any attempt failed.
-func DeleteAll(ctx context.Context, c Client) error {
+//   - []pointer.RemoteObject: The collected buckets.
+//   - error: An error is returned if the buckets could not be listed.
+func CollectAll(ctx context.Context, c Client) ([]pointer.RemoteObject, error) {
 	logger := log.WithCtxFields(ctx).WithFields(field.Type("bucket"))
 	logger.Info("Collecting Grail Bucket configurations...")
 
 	response, err := c.List(ctx)
 	if err != nil {
 		logger.Error("Failed to collect Grail Bucket configurations: %v", err)
-		return err
+		return nil, err
 	}
 
-	logger.Info("Deleting %d objects of type %q...", len(response.All()), "bucket")
+	var objects []pointer.RemoteObject
 	errs := 0
 	for _, obj := range response.All() {
-		var bucketName struct {
-			BucketName string `json:"bucketName"`
+		var bucket struct {
+			BucketName  string `json:"bucketName"`
+			DisplayName string `json:"displayName"`
 		}
 
-		if err := json.Unmarshal(obj, &bucketName); err != nil {
+		if err := json.Unmarshal(obj, &bucket); err != nil {
 			logger.Error("Failed to parse bucket JSON: %v", err)
 			errs++
 			continue
 		}
 
 		// exclude builtin bucket names, they cannot be deleted anyway
-		if buckettools.IsDefault(bucketName.BucketName) {
+		if buckettools.IsDefault(bucket.BucketName) {
 			continue
 		}
 
-		_, err := c.Delete(ctx, bucketName.BucketName)
-		if err != nil {
-			var apiErr api.APIError
-			if errors.As(err, &apiErr) {
-				if apiErr.StatusCode != http.StatusNotFound {
-					logger.Error("Failed to delete bucket %q - rejected by API: %v", bucketName.BucketName, err)
-					errs++
-					continue
-				}
-			} else {
-				logger.Error("Failed to delete bucket %q - network error: %v", bucketName.BucketName, err)
-				errs++
-				continue
-			}
+		objects = append(objects, pointer.RemoteObject{Type: "bucket", ID: bucket.BucketName, Name: bucket.DisplayName})
+	}
+
+	if errs > 0 {
+		return objects, fmt.Errorf("failed to collect %d Grail Bucket configuration(s)", errs)
+	}
+	return objects, nil
+}
+
+// DeleteObjects deletes the given buckets by their bucket name using the provided bucketClient. Buckets are deleted
+// concurrently, the result of each deletion is counted in the given summary.Summary.
+//
+// Returns:
+//   - error: After all deletions where attempted an error is returned if any attempt failed.
+func DeleteObjects(ctx context.Context, c Client, objects []pointer.RemoteObject, s *summary.Summary) error {
+	logger := log.WithCtxFields(ctx).WithFields(field.Type("bucket"))
 
+	errs := parallel.ForEach(objects, func(o pointer.RemoteObject) error {
+		if err := deleteByName(ctx, c, o.ID); err != nil {
+			return err
+		}
+		s.Deleted("bucket")
+		return nil
+	})
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			logger.Error("Failed to delete bucket %q - %v", objects[i].ID, err)
+			s.Failed("bucket")
+			failed++
 		}
 	}
 
-	if errs > 0 {
-		return fmt.Errorf("failed to delete %d Grail Bucket configuration(s)", errs)
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d Grail Bucket configuration(s)", failed)
 	}
 
 	return nil
@@ -17,9 +17,10 @@
 package classic
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 
@@ -27,52 +28,66 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/parallel"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"golang.org/x/net/context"
 )
 
-// Delete removes the given pointer.DeletePointer entries from the environment the supplied client dtclient.Client connects to
-func Delete(ctx context.Context, client client.ConfigClient, theAPI api.API, dps []pointer.DeletePointer) error {
+// Delete removes the given pointer.DeletePointer entries from the environment the supplied client dtclient.Client connects to.
+// Entries are deleted concurrently, the result of each deletion is counted in the given summary.Summary.
+func Delete(ctx context.Context, client client.ConfigClient, theAPI api.API, dps []pointer.DeletePointer, s *summary.Summary) error {
+	errs := parallel.ForEach(dps, func(dp pointer.DeletePointer) error {
+		return deleteEntry(ctx, client, theAPI, dp, s)
+	})
+
 	var err error
+	for i, e := range errs {
+		if e != nil {
+			log.WithCtxFields(ctx).WithFields(field.Coordinate(dps[i].AsCoordinate()), field.Error(e)).Error("failed to delete config: %v", e)
+			s.Failed(theAPI.ID)
+			err = errors.Join(err, e)
+		}
+	}
+	return err
+}
 
-	for _, dp := range dps {
-		log := log.WithCtxFields(ctx).WithFields(field.Coordinate(dp.AsCoordinate()))
-		var parentID string
-		var e error
-		if theAPI.HasParent() {
-			parentID, e = resolveIdentifier(ctx, client, theAPI.Parent, toIdentifier(dp.Scope, "", ""))
-			if e != nil && !is404(e) {
-				log.WithFields(field.Error(e)).Error("unable to resolve config ID: %w")
-				err = errors.Join(err, e)
-				continue
-			} else if parentID == "" {
-				log.Debug("parent doesn't exist - no need for action")
-				continue
-			}
+func deleteEntry(ctx context.Context, client client.ConfigClient, theAPI api.API, dp pointer.DeletePointer, s *summary.Summary) error {
+	log := log.WithCtxFields(ctx).WithFields(field.Coordinate(dp.AsCoordinate()))
+	var parentID string
+	var e error
+	if theAPI.HasParent() {
+		parentID, e = resolveIdentifier(ctx, client, theAPI.Parent, toIdentifier(dp.Scope, "", ""))
+		if e != nil && !is404(e) {
+			return fmt.Errorf("unable to resolve config ID: %w", e)
+		} else if parentID == "" {
+			log.Debug("parent doesn't exist - no need for action")
+			return nil
 		}
+	}
 
-		a := theAPI.ApplyParentObjectID(parentID)
-		id := dp.OriginObjectId
-		if id == "" {
-			id, e = resolveIdentifier(ctx, client, &a, toIdentifier(dp.Identifier, dp.ActionType, dp.Domain))
-			if e != nil && !is404(e) {
-				log.WithFields(field.Error(e)).Error("unable to resolve config ID: %w")
-				err = errors.Join(err, e)
-				continue
-			} else if id == "" {
-				log.Debug("config doesn't exist - no need for action")
-				continue
-			}
+	a := theAPI.ApplyParentObjectID(parentID)
+	id := dp.OriginObjectId
+	if id == "" {
+		id, e = resolveIdentifier(ctx, client, &a, toIdentifier(dp.Identifier, dp.ActionType, dp.Domain))
+		if e != nil && !is404(e) {
+			return fmt.Errorf("unable to resolve config ID: %w", e)
+		} else if id == "" {
+			log.Debug("config doesn't exist - no need for action")
+			return nil
 		}
+	}
 
-		if e := client.DeleteConfigById(a, id); e != nil && !is404(e) {
-			log.WithFields(field.Error(e)).Error("failed to delete config: %w", e)
-			err = errors.Join(err, e)
+	if e := client.DeleteConfigById(a, id); e != nil {
+		if is404(e) {
+			return nil
 		}
-		log.Debug("successfully deleted")
+		return e
 	}
-	return err
+	log.Debug("successfully deleted")
+	s.Deleted(theAPI.ID)
+	return nil
 }
 
 type identifier map[string]any
@@ -86,11 +101,8 @@ func toIdentifier(identifier, actionType, domain string) identifier {
 }
 
 func is404(err error) bool {
-	var v rest.RespError
-	if errors.As(err, &v) {
-		return v.StatusCode == http.StatusNotFound
-	}
-	return false
+	var notFound rest.NotFoundError
+	return errors.As(err, &notFound)
 }
 
 // resolveIdentifier get the actual ID from DT and update entries with it
@@ -135,47 +147,123 @@ func findUniqueID(knownValues []dtclient.Value, identifier identifier, checkEqua
 	return "", fmt.Errorf("unable to find unique config - matching IDs are %s", knownByName)
 }
 
-// DeleteAll collects and deletes all classic API configuration objects using the provided ConfigClient.
+// CollectAll collects all classic API configuration objects of the given APIs using the provided ConfigClient.
+// Configs of APIs with a parent API are not collected, as they are deleted together with their parent.
 //
 // Parameters:
 //   - ctx (context.Context): The context in which the function operates.
 //   - client (dtclient.ConfigClient): An implementation of the ConfigClient interface for managing configuration objects.
-//   - apis (api.APIs): A list of APIs for which configuration values need to be collected and deleted.
+//   - apis (api.APIs): A list of APIs for which configuration values need to be collected.
 //
 // Returns:
-//   - error: After all deletions where attempted an error is returned if any attempt failed.
-func DeleteAll(ctx context.Context, client client.ConfigClient, apis api.APIs) error {
-
+//   - []pointer.RemoteObject: The collected configuration objects.
+//   - error: After all APIs where collected an error is returned if any attempt failed.
+func CollectAll(ctx context.Context, client client.ConfigClient, apis api.APIs) ([]pointer.RemoteObject, error) {
+	var objects []pointer.RemoteObject
 	errs := 0
 
 	for _, a := range apis {
 		logger := log.WithCtxFields(ctx).WithFields(field.Type(a.ID))
 		if a.HasParent() {
 			logger.Debug("Skipping %q, will be deleted by the parent api %q", a.ID, a.Parent)
+			continue
 		}
 		logger.Info("Collecting configs of type %q...", a.ID)
 		values, err := client.ListConfigs(ctx, a)
 		if err != nil {
+			logger.WithFields(field.Error(err)).Error("Failed to collect configs of type %q: %v", a.ID, err)
 			errs++
 			continue
 		}
 
-		logger.Info("Deleting %d configs of type %q...", len(values), a.ID)
-
 		for _, v := range values {
-			logger := logger.WithFields(field.F("value", v))
-			logger.Debug("Deleting config %s:%s...", a.ID, v.Id)
-			err := client.DeleteConfigById(a, v.Id)
+			objects = append(objects, pointer.RemoteObject{Type: a.ID, ID: v.Id, Name: v.Name})
+		}
+	}
+
+	if errs > 0 {
+		return objects, fmt.Errorf("failed to collect configs of %d API(s)", errs)
+	}
+	return objects, nil
+}
 
+// CollectMatching collects all configuration objects of the given API that match the given criteria. Objects are only
+// read individually to retrieve their tags if the criteria require tags and the object's name matches.
+func CollectMatching(ctx context.Context, client client.ConfigClient, a api.API, criteria pointer.Criteria) ([]pointer.RemoteObject, error) {
+	logger := log.WithCtxFields(ctx).WithFields(field.Type(a.ID))
+	values, err := client.ListConfigs(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect configs of type %q: %w", a.ID, err)
+	}
+
+	now := time.Now()
+	var objects []pointer.RemoteObject
+	errs := 0
+	for _, v := range values {
+		o := pointer.RemoteObject{Type: a.ID, ID: v.Id, Name: v.Name}
+		if !criteria.MatchesName(o.Name) {
+			continue
+		}
+
+		if len(criteria.Tags) > 0 {
+			payload, err := client.ReadConfigById(a, v.Id)
 			if err != nil {
-				logger.WithFields(field.Error(err)).Error("Failed to delete %s with ID %s: %v", a.ID, v.Id, err)
+				logger.WithFields(field.Error(err)).Error("Failed to read tags of %s with ID %s: %v", a.ID, v.Id, err)
 				errs++
+				continue
+			}
+			var m map[string]any
+			if err := json.Unmarshal(payload, &m); err != nil {
+				logger.WithFields(field.Error(err)).Error("Failed to read tags of %s with ID %s: %v", a.ID, v.Id, err)
+				errs++
+				continue
 			}
+			o.Tags = pointer.TagsOf(m)
+		}
+
+		if criteria.Matches(o, now) {
+			objects = append(objects, o)
 		}
 	}
 
 	if errs > 0 {
-		return fmt.Errorf("failed to delete %d config(s)", errs)
+		return objects, fmt.Errorf("failed to read %d config(s) of type %q", errs, a.ID)
+	}
+	return objects, nil
+}
+
+// DeleteObjects deletes the given classic API configuration objects by their ID using the provided ConfigClient.
+// Objects are deleted concurrently, the result of each deletion is counted in the given summary.Summary.
+//
+// Returns:
+//   - error: After all deletions where attempted an error is returned if any attempt failed.
+func DeleteObjects(ctx context.Context, client client.ConfigClient, apis api.APIs, objects []pointer.RemoteObject, s *summary.Summary) error {
+	errs := parallel.ForEach(objects, func(o pointer.RemoteObject) error {
+		a, ok := apis[o.Type]
+		if !ok {
+			return errors.New("unknown API")
+		}
+
+		log.WithCtxFields(ctx).WithFields(field.Type(o.Type), field.F("object", o)).Debug("Deleting config %s:%s...", o.Type, o.ID)
+		if err := client.DeleteConfigById(a, o.ID); err != nil && !is404(err) {
+			return err
+		}
+		s.Deleted(o.Type)
+		return nil
+	})
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			o := objects[i]
+			log.WithCtxFields(ctx).WithFields(field.Type(o.Type), field.F("object", o), field.Error(err)).Error("Failed to delete %s with ID %s: %v", o.Type, o.ID, err)
+			s.Failed(o.Type)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d config(s)", failed)
 	}
 
 	return nil
@@ -0,0 +1,117 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package parallel runs deletions concurrently, adapting the number of concurrent deletions when rate limited.
+package parallel
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	coreapi "github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+// ForEach calls fn for all items, with at most as many calls running at once as configured by the
+// MONACO_CONCURRENT_REQUESTS environment variable. Calls are not retried, as the clients already retry rate limited
+// requests; if a call still fails because the API is rate limited, the number of concurrent calls is halved. Each
+// successful call allows one more concurrent call again.
+//
+// The returned errors are in the order of the items, with a nil error for each successful call.
+func ForEach[T any](items []T, fn func(T) error) []error {
+	return forEach(items, environment.GetEnvValueInt(environment.ConcurrentRequestsEnvKey), fn)
+}
+
+func forEach[T any](items []T, maxConcurrent int, fn func(T) error) []error {
+	l := newLimiter(maxConcurrent)
+	errs := make([]error, len(items))
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		l.acquire()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := fn(item)
+			if IsRateLimited(err) {
+				l.throttle()
+			}
+			errs[i] = err
+			l.release(err == nil)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// IsRateLimited returns whether the given error was caused by the API rejecting a request as too many requests were
+// sent.
+func IsRateLimited(err error) bool {
+	var rateLimited rest.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		return true
+	}
+	var apiErr coreapi.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// limiter bounds the number of running calls to a limit, which is halved when rate limited and increased by one for
+// each successful call, up to the initial maximum.
+type limiter struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	max     int
+	limit   int
+	running int
+}
+
+func newLimiter(maxConcurrent int) *limiter {
+	maxConcurrent = max(1, maxConcurrent)
+	l := &limiter{max: maxConcurrent, limit: maxConcurrent}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *limiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.running >= l.limit {
+		l.cond.Wait()
+	}
+	l.running++
+}
+
+func (l *limiter) throttle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = max(1, l.limit/2)
+}
+
+func (l *limiter) release(succeeded bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running--
+	if succeeded && l.limit < l.max {
+		l.limit++
+	}
+	l.cond.Broadcast()
+}
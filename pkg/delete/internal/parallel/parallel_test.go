@@ -0,0 +1,106 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parallel
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	coreapi "github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach_ReturnsErrorsInOrderOfItems(t *testing.T) {
+	failure := errors.New("failure")
+
+	errs := forEach([]int{1, 2, 3, 4}, 2, func(i int) error {
+		if i%2 == 0 {
+			return failure
+		}
+		return nil
+	})
+
+	assert.Equal(t, []error{nil, failure, nil, failure}, errs)
+}
+
+func TestForEach_LimitsConcurrentCalls(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	forEach(make([]int, 50), 3, func(int) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+
+	assert.LessOrEqual(t, maxRunning, 3)
+}
+
+func TestForEach_DoesNotRetryRateLimitedCalls(t *testing.T) {
+	var calls atomic.Int32
+	rateLimited := coreapi.APIError{StatusCode: http.StatusTooManyRequests}
+
+	errs := forEach([]int{1}, 2, func(int) error {
+		calls.Add(1)
+		return rateLimited
+	})
+
+	assert.Equal(t, []error{rateLimited}, errs)
+	assert.Equal(t, int32(1), calls.Load(), "rate limited calls are retried by the clients already")
+}
+
+func TestLimiter_AdaptsToRateLimiting(t *testing.T) {
+	l := newLimiter(8)
+
+	l.throttle()
+	assert.Equal(t, 4, l.limit)
+	l.throttle()
+	l.throttle()
+	l.throttle()
+	assert.Equal(t, 1, l.limit, "limit must not drop below one")
+
+	l.acquire()
+	l.release(true)
+	assert.Equal(t, 2, l.limit)
+
+	l.acquire()
+	l.release(false)
+	assert.Equal(t, 2, l.limit, "failed calls must not increase the limit")
+}
+
+func TestIsRateLimited(t *testing.T) {
+	assert.True(t, IsRateLimited(rest.RespError{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, IsRateLimited(coreapi.APIError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, IsRateLimited(rest.RespError{StatusCode: http.StatusNotFound}))
+	assert.False(t, IsRateLimited(errors.New("network error")))
+	assert.False(t, IsRateLimited(nil))
+}
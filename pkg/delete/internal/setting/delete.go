@@ -17,18 +17,28 @@
 package setting
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/parallel"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"golang.org/x/net/context"
 )
 
-func Delete(ctx context.Context, c client.SettingsClient, entries []pointer.DeletePointer) error {
+// Delete removes the settings objects of the given entries, which must all be of the same schema. Entries are deleted
+// concurrently, the result of each deletion is counted in the given summary.Summary.
+func Delete(ctx context.Context, c client.SettingsClient, entries []pointer.DeletePointer, s *summary.Summary) error {
 
 	if len(entries) == 0 {
 		return nil
@@ -38,111 +48,233 @@ func Delete(ctx context.Context, c client.SettingsClient, entries []pointer.Dele
 	logger := log.WithCtxFields(ctx).WithFields(field.Type(schema))
 	logger.Info("Deleting %d settings objects(s) of schema %q...", len(entries), schema)
 
+	errs := parallel.ForEach(entries, func(e pointer.DeletePointer) error {
+		return deleteEntry(ctx, c, e, s)
+	})
+
 	deleteErrs := 0
-	for _, e := range entries {
-
-		logger := logger.WithFields(field.Coordinate(e.AsCoordinate()))
-
-		var filterFn dtclient.ListSettingsFilter
-		if e.OriginObjectId != "" { //delete by riginObjectId
-			filterFn = func(o dtclient.DownloadSettingsObject) bool { return o.ObjectId == e.OriginObjectId }
-		} else {
-			externalID, err := idutils.GenerateExternalIDForSettingsObject(e.AsCoordinate())
-			if err != nil {
-				logger.Error("unable to generate externalID, Setting will not be deleted: %v", err)
-				deleteErrs++
-				continue
-			}
-			filterFn = func(o dtclient.DownloadSettingsObject) bool { return o.ExternalId == externalID }
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if parallel.IsRateLimited(err) { // other errors were already logged when they occurred
+			logger.WithFields(field.Coordinate(entries[i].AsCoordinate())).Error("Failed to delete settings object(s) - still rate limited after retries: %v", err)
+			s.Failed(schema)
 		}
+		deleteErrs++
+	}
 
-		// get settings objects with matching external ID
-		objects, err := c.ListSettings(ctx, e.Type, dtclient.ListSettingsOptions{DiscardValue: true, Filter: filterFn})
+	if deleteErrs > 0 {
+		return fmt.Errorf("failed to delete %d settings objects(s) of schema %q", deleteErrs, schema)
+	}
+
+	return nil
+}
+
+// deleteEntry deletes the settings objects of a single entry. Errors are logged and counted, the returned error is
+// only used to retry the entry if rate limited.
+func deleteEntry(ctx context.Context, c client.SettingsClient, e pointer.DeletePointer, s *summary.Summary) error {
+	logger := log.WithCtxFields(ctx).WithFields(field.Type(e.Type), field.Coordinate(e.AsCoordinate()))
+
+	var filterFn dtclient.ListSettingsFilter
+	if e.OriginObjectId != "" { //delete by riginObjectId
+		filterFn = func(o dtclient.DownloadSettingsObject) bool { return o.ObjectId == e.OriginObjectId }
+	} else {
+		externalIDs, err := externalIDsOf(e)
 		if err != nil {
+			logger.Error("unable to generate externalID, Setting will not be deleted: %v", err)
+			s.Failed(e.Type)
+			return err
+		}
+		filterFn = func(o dtclient.DownloadSettingsObject) bool { return slices.Contains(externalIDs, o.ExternalId) }
+	}
+
+	// get settings objects with matching external ID
+	objects, err := c.ListSettings(ctx, e.Type, dtclient.ListSettingsOptions{DiscardValue: true, Filter: filterFn})
+	if err != nil {
+		if !parallel.IsRateLimited(err) {
 			logger.Error("Could not fetch settings object: %v", err)
-			deleteErrs++
-			continue
+			s.Failed(e.Type)
 		}
+		return err
+	}
 
-		if len(objects) == 0 {
-			logger.Debug("No settings object found to delete")
+	if len(objects) == 0 {
+		logger.Debug("No settings object found to delete")
+		return nil
+	}
+
+	var errs error
+	for _, obj := range objects {
+		if obj.ModificationInfo != nil && !obj.ModificationInfo.Deletable {
+			logger.WithFields(field.F("object", obj)).Warn("Requested settings object with ID %s is not deletable.", obj.ObjectId)
 			continue
 		}
 
-		for _, obj := range objects {
-			if obj.ModificationInfo != nil && !obj.ModificationInfo.Deletable {
-				logger.WithFields(field.F("object", obj)).Warn("Requested settings object with ID %s is not deletable.", obj.ObjectId)
+		logger.Debug("Deleting settings object with objectId %q.", obj.ObjectId)
+		if err := c.DeleteSettings(obj.ObjectId); err != nil {
+			errs = errors.Join(errs, err)
+			if parallel.IsRateLimited(err) {
 				continue
 			}
-
-			logger.Debug("Deleting settings object with objectId %q.", obj.ObjectId)
-			err := c.DeleteSettings(obj.ObjectId)
-			if err != nil {
-				logger.Error("Failed to delete settings object with object ID %s: %v", obj.ObjectId, err)
-				deleteErrs++
+			logger.Error("Failed to delete settings object with object ID %s: %v", obj.ObjectId, err)
+			if isReferencedError(err) {
+				logger.Warn("Settings object with object ID %s might still be referenced by other configurations. Delete the referencing configurations first, e.g. by listing them before it in the delete file.", obj.ObjectId)
 			}
+			s.Failed(e.Type)
+			continue
 		}
+		s.Deleted(e.Type)
 	}
+	return errs
+}
 
-	if deleteErrs > 0 {
-		return fmt.Errorf("failed to delete %d settings objects(s) of schema %q", deleteErrs, schema)
+// externalIDsOf returns the external IDs a settings object deployed for the given entry may have. Besides the external
+// ID of the entry's coordinate, this is the legacy external ID without project, which is only replaced on the next
+// deployment of the object.
+func externalIDsOf(e pointer.DeletePointer) ([]string, error) {
+	externalID, err := idutils.GenerateExternalIDForSettingsObject(e.AsCoordinate())
+	if err != nil {
+		return nil, err
+	}
+	if e.Project == "" {
+		return []string{externalID}, nil
 	}
 
-	return nil
+	legacyExternalID, err := idutils.GenerateExternalIDForSettingsObject(coordinate.Coordinate{Type: e.Type, ConfigId: e.Identifier})
+	if err != nil {
+		return nil, err
+	}
+	return []string{externalID, legacyExternalID}, nil
 }
 
-// DeleteAll collects and deletes settings objects using the provided SettingsClient.
+// isReferencedError returns whether the deletion of a settings object was rejected by the API, which is the case if
+// the object is still referenced by other objects
+func isReferencedError(err error) bool {
+	var schemaValidation rest.SchemaValidationError
+	var conflict rest.ConflictError
+	return errors.As(err, &schemaValidation) || errors.As(err, &conflict)
+}
+
+// CollectAll collects all deletable settings objects using the provided SettingsClient.
 //
 // Parameters:
 //   - ctx (context.Context): The context in which the function operates.
 //   - c (dtclient.SettingsClient): An implementation of the SettingsClient interface for managing settings objects.
+//   - excludedSchemas ([]string): Schemas whose objects are not collected.
+//   - filter (dtclient.ListSettingsFilter): If set, only objects matching it are collected.
 //
 // Returns:
-//   - error: After all deletions where attempted an error is returned if any attempt failed.
-func DeleteAll(ctx context.Context, c client.SettingsClient) error {
-	errs := 0
-
+//   - []pointer.RemoteObject: The collected settings objects.
+//   - error: After all schemas where collected an error is returned if any attempt failed.
+func CollectAll(ctx context.Context, c client.SettingsClient, excludedSchemas []string, filter dtclient.ListSettingsFilter) ([]pointer.RemoteObject, error) {
 	schemas, err := c.ListSchemas()
 	if err != nil {
-		return fmt.Errorf("failed to fetch settings schemas. No settings will be deleted. Reason: %w", err)
+		return nil, fmt.Errorf("failed to fetch settings schemas. No settings will be deleted. Reason: %w", err)
 	}
 
-	schemaIds := make([]string, len(schemas))
+	schemaIds := make([]string, 0, len(schemas))
 	for i := range schemas {
-		schemaIds[i] = schemas[i].SchemaId
+		if !slices.Contains(excludedSchemas, schemas[i].SchemaId) {
+			schemaIds = append(schemaIds, schemas[i].SchemaId)
+		}
 	}
 
 	logger := log.WithCtxFields(ctx)
-	logger.Debug("Deleting settings of schemas %v...", schemaIds)
+	logger.Debug("Collecting settings of schemas %v...", schemaIds)
 
+	var objects []pointer.RemoteObject
+	errs := 0
 	for _, s := range schemaIds {
 		logger := logger.WithFields(field.Type(s))
 		logger.Info("Collecting objects of type %q...", s)
 
-		settings, err := c.ListSettings(ctx, s, dtclient.ListSettingsOptions{DiscardValue: true})
+		settings, err := c.ListSettings(ctx, s, dtclient.ListSettingsOptions{DiscardValue: true, Filter: filter})
 		if err != nil {
 			logger.WithFields(field.Error(err)).Error("Failed to collect object for schema %q: %v", s, err)
 			errs++
 			continue
 		}
 
-		logger.Info("Deleting %d objects of type %q...", len(settings), s)
 		for _, setting := range settings {
 			if setting.ModificationInfo != nil && !setting.ModificationInfo.Deletable {
 				continue
 			}
-
-			logger.WithFields(field.F("object", setting)).Debug("Deleting settings object with objectId %q...", setting.ObjectId)
-			err := c.DeleteSettings(setting.ObjectId)
-			if err != nil {
-				logger.Error("Failed to delete settings object with object ID %s: %v", setting.ObjectId, err)
-				errs++
+			o := pointer.RemoteObject{Type: s, ID: setting.ObjectId, LastModifiedBy: setting.ModifiedBy}
+			if setting.Created != 0 {
+				o.Created = time.UnixMilli(setting.Created)
 			}
+			objects = append(objects, o)
 		}
 	}
 
 	if errs > 0 {
-		return fmt.Errorf("failed to delete %d setting(s)", errs)
+		return objects, fmt.Errorf("failed to collect settings of %d schema(s)", errs)
+	}
+	return objects, nil
+}
+
+// CollectMatching collects all deletable settings objects of the given schema that match the given criteria. The name
+// and tags of an object are read from the 'name' and 'tags' properties of its value, if the criteria require them.
+func CollectMatching(ctx context.Context, c client.SettingsClient, schemaID string, criteria pointer.Criteria) ([]pointer.RemoteObject, error) {
+	needsValue := criteria.Name != "" || len(criteria.Tags) > 0
+	settings, err := c.ListSettings(ctx, schemaID, dtclient.ListSettingsOptions{DiscardValue: !needsValue})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect objects of schema %q: %w", schemaID, err)
+	}
+
+	now := time.Now()
+	var objects []pointer.RemoteObject
+	for _, setting := range settings {
+		if setting.ModificationInfo != nil && !setting.ModificationInfo.Deletable {
+			continue
+		}
+		o := pointer.RemoteObject{Type: schemaID, ID: setting.ObjectId, LastModifiedBy: setting.ModifiedBy}
+		if setting.Created != 0 {
+			o.Created = time.UnixMilli(setting.Created)
+		}
+		if needsValue {
+			var value map[string]any
+			if err := json.Unmarshal(setting.Value, &value); err == nil {
+				o.Name, _ = value["name"].(string)
+				o.Tags = pointer.TagsOf(value)
+			}
+		}
+
+		if criteria.Matches(o, now) {
+			objects = append(objects, o)
+		}
+	}
+	return objects, nil
+}
+
+// DeleteObjects deletes the given settings objects by their object ID using the provided SettingsClient.
+// Objects are deleted concurrently, the result of each deletion is counted in the given summary.Summary.
+//
+// Returns:
+//   - error: After all deletions where attempted an error is returned if any attempt failed.
+func DeleteObjects(ctx context.Context, c client.SettingsClient, objects []pointer.RemoteObject, s *summary.Summary) error {
+	errs := parallel.ForEach(objects, func(o pointer.RemoteObject) error {
+		log.WithCtxFields(ctx).WithFields(field.Type(o.Type), field.F("object", o)).Debug("Deleting settings object with objectId %q...", o.ID)
+		if err := c.DeleteSettings(o.ID); err != nil {
+			return err
+		}
+		s.Deleted(o.Type)
+		return nil
+	})
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			o := objects[i]
+			log.WithCtxFields(ctx).WithFields(field.Type(o.Type), field.F("object", o)).Error("Failed to delete settings object with object ID %s: %v", o.ID, err)
+			s.Failed(o.Type)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d setting(s)", failed)
 	}
 
 	return nil
@@ -0,0 +1,105 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package summary counts the deleted and failed configurations per type, to be logged once deletion finished.
+package summary
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"golang.org/x/exp/maps"
+	"slices"
+)
+
+// Summary counts the deleted and failed configurations per type. It is safe for concurrent use, and all methods
+// can be called on a nil Summary, which counts nothing.
+type Summary struct {
+	mu      sync.Mutex
+	results map[string]*Result
+}
+
+// Result holds the counts of deleted and failed configurations of a type.
+type Result struct {
+	Deleted int
+	Failed  int
+}
+
+// New creates an empty Summary.
+func New() *Summary {
+	return &Summary{results: make(map[string]*Result)}
+}
+
+// Deleted counts a deleted configuration of the given type.
+func (s *Summary) Deleted(t string) {
+	s.add(t, func(r *Result) { r.Deleted++ })
+}
+
+// Failed counts a configuration of the given type that could not be deleted.
+func (s *Summary) Failed(t string) {
+	s.add(t, func(r *Result) { r.Failed++ })
+}
+
+func (s *Summary) add(t string, fn func(r *Result)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.results[t]; !ok {
+		s.results[t] = &Result{}
+	}
+	fn(s.results[t])
+}
+
+// Results returns the counts per type.
+func (s *Summary) Results() map[string]Result {
+	results := make(map[string]Result)
+	if s == nil {
+		return results
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for t, r := range s.results {
+		results[t] = *r
+	}
+	return results
+}
+
+// Log logs the counts of deleted and failed configurations per type, sorted by type.
+func (s *Summary) Log(ctx context.Context) {
+	results := s.Results()
+	if len(results) == 0 {
+		return
+	}
+
+	types := maps.Keys(results)
+	slices.Sort(types)
+
+	logger := log.WithCtxFields(ctx)
+	logger.Info("Deletion summary:")
+	for _, t := range types {
+		r := results[t]
+		l := logger.WithFields(field.Type(t), field.F("deleted", r.Deleted), field.F("failed", r.Failed))
+		if r.Failed > 0 {
+			l.Warn("  %s: %d deleted, %d failed", t, r.Deleted, r.Failed)
+		} else {
+			l.Info("  %s: %d deleted", t, r.Deleted)
+		}
+	}
+}
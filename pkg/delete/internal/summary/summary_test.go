@@ -0,0 +1,57 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package summary_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/internal/summary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	s := summary.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Deleted("builtin:alerting.profile")
+		}()
+	}
+	wg.Wait()
+	s.Deleted("management-zone")
+	s.Failed("management-zone")
+
+	assert.Equal(t, map[string]summary.Result{
+		"builtin:alerting.profile": {Deleted: 10},
+		"management-zone":          {Deleted: 1, Failed: 1},
+	}, s.Results())
+}
+
+func TestNilSummary(t *testing.T) {
+	var s *summary.Summary
+
+	s.Deleted("management-zone")
+	s.Failed("management-zone")
+
+	assert.Empty(t, s.Results())
+}
@@ -19,8 +19,11 @@ package delete
 import (
 	"errors"
 	"fmt"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/persistence"
@@ -136,10 +139,17 @@ func convert(entry interface{}) (pointer.DeletePointer, error) {
 	if parsed.Type == "" {
 		return pointer.DeletePointer{}, errors.New("'type' is not supported for this API")
 	}
+	if parsed.Criteria != nil {
+		return convertCriteriaEntry(parsed)
+	}
 	if a, known := api.NewAPIs()[parsed.Type]; known {
 		if err := verifyAPIEntry(parsed, a); err != nil {
 			return pointer.DeletePointer{}, fmt.Errorf("failed to parse entry for API '%s': %w", a.ID, err)
 		}
+	} else if isAutomationResource(parsed.Type) && parsed.ConfigName != "" {
+		if err := verifyAutomationTitleEntry(parsed); err != nil {
+			return pointer.DeletePointer{}, err
+		}
 	} else {
 		if err := verifyCoordinateEntry(parsed); err != nil {
 			return pointer.DeletePointer{}, err
@@ -154,7 +164,7 @@ func convert(entry interface{}) (pointer.DeletePointer, error) {
 		Domain:         parsed.CustomValues["domain"],
 		OriginObjectId: parsed.ObjectId,
 	}
-	if _, known := api.NewAPIs()[parsed.Type]; known {
+	if _, known := api.NewAPIs()[parsed.Type]; known || parsed.ConfigName != "" {
 		dp.Identifier = parsed.ConfigName
 	} else {
 		dp.Identifier = parsed.ConfigId
@@ -163,6 +173,65 @@ func convert(entry interface{}) (pointer.DeletePointer, error) {
 	return dp, nil
 }
 
+func convertCriteriaEntry(parsed persistence.DeleteEntry) (pointer.DeletePointer, error) {
+	if parsed.ConfigId != "" || parsed.ConfigName != "" || parsed.ObjectId != "" || parsed.Project != "" || parsed.Scope != "" || len(parsed.CustomValues) > 0 {
+		return pointer.DeletePointer{}, errors.New("'criteria' can't be combined with any other field than 'type'")
+	}
+	if parsed.Type == "bucket" {
+		return pointer.DeletePointer{}, errors.New("'criteria' are not supported for Grail Buckets")
+	}
+	if a, known := api.NewAPIs()[parsed.Type]; known && a.HasParent() {
+		return pointer.DeletePointer{}, fmt.Errorf("'criteria' are not supported for API '%s', as it requires a 'scope'", a.ID)
+	}
+
+	c := parsed.Criteria
+	if c.Name == "" && c.OlderThan == "" && len(c.Tags) == 0 {
+		return pointer.DeletePointer{}, errors.New("'criteria' require at least one of 'name', 'olderThan' or 'tags'")
+	}
+	if _, err := path.Match(c.Name, ""); err != nil {
+		return pointer.DeletePointer{}, fmt.Errorf("invalid 'name' pattern %q: %w", c.Name, err)
+	}
+
+	var olderThan time.Duration
+	if c.OlderThan != "" {
+		var err error
+		if olderThan, err = parseAge(c.OlderThan); err != nil {
+			return pointer.DeletePointer{}, fmt.Errorf("invalid 'olderThan' value %q: %w", c.OlderThan, err)
+		}
+	}
+
+	return pointer.DeletePointer{
+		Type: parsed.Type,
+		Criteria: &pointer.Criteria{
+			Name:      c.Name,
+			OlderThan: olderThan,
+			Tags:      c.Tags,
+		},
+	}, nil
+}
+
+// parseAge parses a positive age given either in days (e.g. '30d') or as a time.Duration (e.g. '12h')
+func parseAge(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, isDays := strings.CutSuffix(s, "d"); isDays {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errors.New("expected a number of days, e.g. '30d'")
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, err
+		}
+	}
+
+	if d <= 0 {
+		return 0, errors.New("age must be positive")
+	}
+	return d, nil
+}
+
 func verifyAPIEntry(parsed persistence.DeleteEntry, a api.API) error {
 	if parsed.ConfigId != "" {
 		return errors.New("'id' is not supported for this API")
@@ -196,6 +265,15 @@ func verifyAPIEntry(parsed persistence.DeleteEntry, a api.API) error {
 	return nil
 }
 
+// verifyAutomationTitleEntry verifies entries of automation resources identified by their title instead of a
+// monaco coordinate or object ID
+func verifyAutomationTitleEntry(parsed persistence.DeleteEntry) error {
+	if parsed.ConfigId != "" || parsed.Project != "" || parsed.ObjectId != "" {
+		return errors.New("'name' can't be combined with 'id', 'project' or 'objectId'")
+	}
+	return nil
+}
+
 func verifyCoordinateEntry(parsed persistence.DeleteEntry) error {
 	if parsed.ConfigName != "" {
 		return errors.New("'name' is not supported for this API")
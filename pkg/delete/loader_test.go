@@ -21,8 +21,10 @@ package delete_test
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -410,3 +412,117 @@ func createDeleteFile(t testing.TB, content []byte) (afero.Fs, string) {
 
 	return fs, deleteFilePath
 }
+
+func TestCriteriaEntry(t *testing.T) {
+	fileContent := []byte(`delete:
+- type: synthetic-monitor
+  criteria:
+    name: tmp-*
+    olderThan: 30d
+    tags: [temporary, "owner:team-a"]
+- type: builtin:alerting.profile
+  criteria:
+    olderThan: 12h
+`)
+	want := delete.DeleteEntries{
+		"synthetic-monitor": {{
+			Type: "synthetic-monitor",
+			Criteria: &pointer.Criteria{
+				Name:      "tmp-*",
+				OlderThan: 30 * 24 * time.Hour,
+				Tags:      []string{"temporary", "owner:team-a"},
+			},
+		}},
+		"builtin:alerting.profile": {{
+			Type:     "builtin:alerting.profile",
+			Criteria: &pointer.Criteria{OlderThan: 12 * time.Hour},
+		}}}
+	actual, err := delete.LoadEntriesFromFile(createDeleteFile(t, fileContent))
+	require.NoError(t, err)
+	require.Equal(t, want, actual)
+}
+
+func TestCriteriaEntryFailsIfInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{
+			"no criteria defined",
+			"- type: synthetic-monitor\n  criteria: {}\n",
+		},
+		{
+			"combined with name",
+			"- type: synthetic-monitor\n  name: monitor\n  criteria:\n    name: tmp-*\n",
+		},
+		{
+			"combined with project and id",
+			"- type: builtin:alerting.profile\n  project: project\n  id: profile\n  criteria:\n    olderThan: 1d\n",
+		},
+		{
+			"invalid name pattern",
+			"- type: synthetic-monitor\n  criteria:\n    name: \"tmp-[\"\n",
+		},
+		{
+			"invalid age",
+			"- type: synthetic-monitor\n  criteria:\n    olderThan: a month\n",
+		},
+		{
+			"negative age",
+			"- type: synthetic-monitor\n  criteria:\n    olderThan: -1d\n",
+		},
+		{
+			"buckets",
+			"- type: bucket\n  criteria:\n    olderThan: 1d\n",
+		},
+		{
+			"API requiring a scope",
+			"- type: key-user-actions-web\n  criteria:\n    name: tmp-*\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := delete.LoadEntriesFromFile(createDeleteFile(t, []byte("delete:\n"+tt.entry)))
+			var e delete.ParseErrors
+			assert.ErrorAs(t, err, &e)
+			assert.Equal(t, 1, len(e))
+			assert.Empty(t, actual)
+		})
+	}
+}
+
+func TestAutomationEntryWithTitle(t *testing.T) {
+	fileContent := []byte(`delete:
+- type: workflow
+  name: my workflow
+- type: scheduling-rule
+  project: project
+  id: my-rule
+`)
+	want := delete.DeleteEntries{
+		"workflow": {{
+			Type:       "workflow",
+			Identifier: "my workflow",
+		}},
+		"scheduling-rule": {{
+			Project:    "project",
+			Type:       "scheduling-rule",
+			Identifier: "my-rule",
+		}}}
+	actual, err := delete.LoadEntriesFromFile(createDeleteFile(t, fileContent))
+	require.NoError(t, err)
+	require.Equal(t, want, actual)
+}
+
+func TestAutomationEntryWithTitleFailsIfCombinedWithID(t *testing.T) {
+	fileContent := []byte(`delete:
+- type: workflow
+  name: my workflow
+  objectId: 7a4b2c1e
+`)
+	actual, err := delete.LoadEntriesFromFile(createDeleteFile(t, fileContent))
+	var e delete.ParseErrors
+	assert.ErrorAs(t, err, &e)
+	assert.Equal(t, 1, len(e))
+	assert.Empty(t, actual)
+}
@@ -0,0 +1,99 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
+	"golang.org/x/exp/maps"
+	gonum "gonum.org/v1/gonum/graph"
+	"slices"
+)
+
+// TypeDependencies maps a configuration type to the configuration types that reference configurations of it.
+// Configurations of the referencing types need to be deleted before the ones they reference.
+type TypeDependencies map[configurationType][]configurationType
+
+// DependenciesOf derives TypeDependencies from a configuration dependency graph, as created by graph.New for
+// deployments. If g is nil, no dependencies are returned.
+func DependenciesOf(g gonum.Directed) TypeDependencies {
+	deps := make(TypeDependencies)
+	if g == nil {
+		return deps
+	}
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		referenced := nodes.Node().(graph.ConfigNode).Config.Coordinate.Type
+		referencing := g.From(nodes.Node().ID())
+		for referencing.Next() {
+			t := referencing.Node().(graph.ConfigNode).Config.Coordinate.Type
+			if t != referenced && !slices.Contains(deps[referenced], t) {
+				deps[referenced] = append(deps[referenced], t)
+			}
+		}
+	}
+	for _, referencing := range deps {
+		slices.Sort(referencing)
+	}
+	return deps
+}
+
+// orderTypes sorts the given types so that each type comes after all types referencing it, i.e. in reverse
+// topological order of the reference graph. Types without dependencies between them are sorted alphabetically.
+// If the dependencies contain a cycle, the remaining types are appended alphabetically.
+func orderTypes(types []configurationType, deps TypeDependencies) []configurationType {
+	pending := make(map[configurationType]int, len(types)) // number of not yet ordered types referencing a type
+	for _, t := range types {
+		pending[t] = 0
+	}
+	for _, t := range types {
+		for _, referencing := range deps[t] {
+			if _, ok := pending[referencing]; ok {
+				pending[t]++
+			}
+		}
+	}
+
+	ordered := make([]configurationType, 0, len(types))
+	for len(pending) > 0 {
+		var ready []configurationType
+		for t, n := range pending {
+			if n == 0 {
+				ready = append(ready, t)
+			}
+		}
+		if len(ready) == 0 { // cyclic dependencies - no sensible order exists for the rest
+			rest := maps.Keys(pending)
+			slices.Sort(rest)
+			return append(ordered, rest...)
+		}
+		slices.Sort(ready)
+
+		for _, t := range ready {
+			delete(pending, t)
+			ordered = append(ordered, t)
+		}
+		for referenced := range pending {
+			for _, referencing := range deps[referenced] {
+				if slices.Contains(ready, referencing) {
+					pending[referenced]--
+				}
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,121 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	zoneSchema    = "builtin:management-zones"
+	tagSchema     = "builtin:tags.auto-tagging"
+	profileSchema = "builtin:alerting.profile"
+)
+
+func dependencyGraph() graph.ConfigGraphPerEnvironment {
+	zone := coordinate.Coordinate{Project: "project", Type: zoneSchema, ConfigId: "zone"}
+	tag := coordinate.Coordinate{Project: "project", Type: tagSchema, ConfigId: "tag"}
+	profile := coordinate.Coordinate{Project: "project", Type: profileSchema, ConfigId: "profile"}
+
+	referencing := func(c coordinate.Coordinate, refs ...coordinate.Coordinate) config.Config {
+		p := &parameter.DummyParameter{Value: "value"}
+		for _, r := range refs {
+			p.References = append(p.References, parameter.ParameterReference{Config: r, Property: "id"})
+		}
+		return config.Config{Coordinate: c, Environment: "env", Parameters: map[string]parameter.Parameter{"ref": p}}
+	}
+
+	projects := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": {
+					zoneSchema:    []config.Config{referencing(zone)},
+					tagSchema:     []config.Config{referencing(tag, zone)},
+					profileSchema: []config.Config{referencing(profile, zone, tag)},
+				},
+			},
+		},
+	}
+	return graph.New(projects, []string{"env"})
+}
+
+func TestDependenciesOf(t *testing.T) {
+	graphs := dependencyGraph()
+
+	got := delete.DependenciesOf(graphs["env"])
+
+	assert.Equal(t, delete.TypeDependencies{
+		zoneSchema: {profileSchema, tagSchema},
+		tagSchema:  {profileSchema},
+	}, got)
+	assert.Empty(t, delete.DependenciesOf(nil))
+}
+
+func TestConfigsInOrder_DeletesReferencingTypesFirst(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	gomock.InOrder(
+		c.EXPECT().ListSettings(gomock.Any(), profileSchema, gomock.Any()).Return([]dtclient.DownloadSettingsObject{{ObjectId: "profile-id"}}, nil),
+		c.EXPECT().DeleteSettings("profile-id").Return(nil),
+		c.EXPECT().ListSettings(gomock.Any(), tagSchema, gomock.Any()).Return([]dtclient.DownloadSettingsObject{{ObjectId: "tag-id"}}, nil),
+		c.EXPECT().DeleteSettings("tag-id").Return(nil),
+		c.EXPECT().ListSettings(gomock.Any(), zoneSchema, gomock.Any()).Return([]dtclient.DownloadSettingsObject{{ObjectId: "zone-id"}}, nil),
+		c.EXPECT().DeleteSettings("zone-id").Return(nil),
+	)
+
+	entriesToDelete := delete.DeleteEntries{
+		zoneSchema:    {{Project: "project", Type: zoneSchema, Identifier: "zone"}},
+		tagSchema:     {{Project: "project", Type: tagSchema, Identifier: "tag"}},
+		profileSchema: {{Project: "project", Type: profileSchema, Identifier: "profile"}},
+	}
+	dependencies := delete.DependenciesOf(dependencyGraph()["env"])
+
+	err := delete.ConfigsInOrder(context.TODO(), delete.ClientSet{Settings: c}, api.NewAPIs(), automationTypes, entriesToDelete, dependencies)
+	assert.NoError(t, err)
+}
+
+func TestConfigsInOrder_ContinuesOnCyclicDependencies(t *testing.T) {
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return([]dtclient.DownloadSettingsObject{}, nil).Times(2)
+
+	entriesToDelete := delete.DeleteEntries{
+		zoneSchema: {{Project: "project", Type: zoneSchema, Identifier: "zone"}},
+		tagSchema:  {{Project: "project", Type: tagSchema, Identifier: "tag"}},
+	}
+	dependencies := delete.TypeDependencies{
+		zoneSchema: {tagSchema},
+		tagSchema:  {zoneSchema},
+	}
+
+	err := delete.ConfigsInOrder(context.TODO(), delete.ClientSet{Settings: c}, api.NewAPIs(), automationTypes, entriesToDelete, dependencies)
+	assert.NoError(t, err)
+}
@@ -45,16 +45,28 @@ type DeleteEntry struct {
 	Type string `yaml:"type" json:"type" mapstructure:"type" jsonschema:"required,description=The type of config to be deleted."`
 	// ConfigId is the monaco ID of the config to be deleted - required for configs with generated IDs (e.g. Settings 2.0, Automations, Grail Buckets)
 	ConfigId string `yaml:"id,omitempty" json:"id,omitempty" mapstructure:"id" jsonschema:"description=The monaco ID of the config to be deleted - required for configs with generated IDs (e.g. Settings 2.0, Automations, Grail Buckets). It can't be combined with 'objectId' or 'name'."`
-	// ConfigName is the name of the config to be deleted - required for configs deleted by name (classic Config API types)
-	ConfigName string `yaml:"name,omitempty" json:"name,omitempty" mapstructure:"name" jsonschema:"description=The name of the config to be deleted - required for configs deleted by name (classic Config API types). It can't be combined with 'objectId' or 'id'."`
+	// ConfigName is the name of the config to be deleted - required for configs deleted by name (classic Config API types), or the title of an Automation to be deleted
+	ConfigName string `yaml:"name,omitempty" json:"name,omitempty" mapstructure:"name" jsonschema:"description=The name of the config to be deleted - required for configs deleted by name (classic Config API types). For Automations (workflow, scheduling-rule, business-calendar) it is their title, which can be used if their ID is unknown. It can't be combined with 'objectId' or 'id'."`
 	//ObjectId is the dynatrace ID of the object
 	ObjectId string `yaml:"objectId,omitempty" json:"objectId,omitempty" mapstructure:"objectId" jsonschema:"ID of the configuration in the Dynatrace. It can't be combined with 'name' or 'id'."`
 	// Scope is the parent scope of a config. This field must be set if a classic config is used, and the classic config requires the scope to be set.
 	Scope string `yaml:"scope,omitempty" json:"scope,omitempty" mapstructure:"scope" jsonschema:"description=The scope of the config to be deleted - required for API configs that require a scope"`
+	// Criteria select all objects of the type matching them, instead of a single object identified by 'id', 'name' or 'objectId'
+	Criteria *Criteria `yaml:"criteria,omitempty" json:"criteria,omitempty" mapstructure:"criteria" jsonschema:"description=Criteria selecting all objects of the type matching them. It can't be combined with 'id', 'name', 'objectId' or 'project'."`
 	// CustomValues holds special values that are not general enough to add as a field to a DeleteEntry but are still important for specific APIs
 	CustomValues map[string]string `yaml:",inline" mapstructure:",remain"`
 }
 
+// Criteria select objects to delete by their metadata. All defined criteria must be fulfilled for an object to be deleted.
+type Criteria struct {
+	// Name is a glob pattern the name of an object must match
+	Name string `yaml:"name,omitempty" json:"name,omitempty" mapstructure:"name" jsonschema:"description=A glob pattern (e.g. 'tmp-*') the name of an object must match."`
+	// OlderThan is the minimum age of an object, e.g. '30d' or '12h'
+	OlderThan string `yaml:"olderThan,omitempty" json:"olderThan,omitempty" mapstructure:"olderThan" jsonschema:"description=The minimum age of an object, in days (e.g. '30d') or as a duration (e.g. '12h'). Objects with unknown creation time are not deleted."`
+	// Tags an object must have
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty" mapstructure:"tags" jsonschema:"description=Tags an object must have - either plain tags or 'key:value' pairs."`
+}
+
 type DeleteEntries []DeleteEntry
 
 // JSONSchema defines a custom schema definition for ReferenceSlice as it contains either Reference objects or strings
@@ -0,0 +1,92 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pointer
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"time"
+)
+
+// Criteria select the objects of a type to delete by their metadata instead of by their identifier.
+// An object is selected only if it fulfils all defined criteria.
+type Criteria struct {
+	// Name is a glob pattern (as supported by path.Match) the name of an object must match
+	Name string
+	// OlderThan is the minimum age of an object. Objects with an unknown creation time are never selected.
+	OlderThan time.Duration
+	// Tags are the tags an object must have - either plain tags or 'key:value' pairs
+	Tags []string
+}
+
+// Matches returns whether the given object fulfils all criteria at the given time.
+func (c Criteria) Matches(o RemoteObject, now time.Time) bool {
+	if !c.MatchesName(o.Name) {
+		return false
+	}
+	if c.OlderThan > 0 && (o.Created.IsZero() || now.Sub(o.Created) < c.OlderThan) {
+		return false
+	}
+	for _, t := range c.Tags {
+		if !slices.Contains(o.Tags, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesName returns whether the given name matches the Name pattern. If no pattern is defined, any name matches.
+// As this is the cheapest criterion to evaluate, it can be used to filter objects before retrieving further metadata.
+func (c Criteria) MatchesName(name string) bool {
+	if c.Name == "" {
+		return true
+	}
+	matches, _ := path.Match(c.Name, name) // pattern is validated when loading the delete file
+	return matches
+}
+
+func (c Criteria) String() string {
+	return fmt.Sprintf("name=%q, olderThan=%s, tags=%v", c.Name, c.OlderThan, c.Tags)
+}
+
+// TagsOf reads the tags of a configuration object payload. Tags are expected in a top-level 'tags' field, either as
+// plain strings or as objects with a 'key' and an optional 'value', which are returned as 'key:value'.
+func TagsOf(payload map[string]any) []string {
+	raw, ok := payload["tags"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var tags []string
+	for _, t := range raw {
+		switch t := t.(type) {
+		case string:
+			tags = append(tags, t)
+		case map[string]any:
+			key, _ := t["key"].(string)
+			if key == "" {
+				continue
+			}
+			if value, _ := t["value"].(string); value != "" {
+				key += ":" + value
+			}
+			tags = append(tags, key)
+		}
+	}
+	return tags
+}
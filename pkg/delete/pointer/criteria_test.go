@@ -0,0 +1,75 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pointer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCriteria_Matches(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	object := pointer.RemoteObject{
+		Type:    "synthetic-monitor",
+		ID:      "id",
+		Name:    "tmp-monitor",
+		Created: now.Add(-40 * 24 * time.Hour),
+		Tags:    []string{"temporary", "owner:team-a"},
+	}
+
+	tests := []struct {
+		name     string
+		criteria pointer.Criteria
+		object   pointer.RemoteObject
+		want     bool
+	}{
+		{"matching name", pointer.Criteria{Name: "tmp-*"}, object, true},
+		{"other name", pointer.Criteria{Name: "prod-*"}, object, false},
+		{"old enough", pointer.Criteria{OlderThan: 30 * 24 * time.Hour}, object, true},
+		{"too young", pointer.Criteria{OlderThan: 50 * 24 * time.Hour}, object, false},
+		{"unknown creation time", pointer.Criteria{OlderThan: time.Hour}, pointer.RemoteObject{Name: "tmp-monitor"}, false},
+		{"all tags present", pointer.Criteria{Tags: []string{"owner:team-a", "temporary"}}, object, true},
+		{"tag missing", pointer.Criteria{Tags: []string{"temporary", "owner:team-b"}}, object, false},
+		{"all criteria", pointer.Criteria{Name: "tmp-*", OlderThan: 30 * 24 * time.Hour, Tags: []string{"temporary"}}, object, true},
+		{"one of all criteria unmet", pointer.Criteria{Name: "tmp-*", OlderThan: 30 * 24 * time.Hour, Tags: []string{"permanent"}}, object, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.criteria.Matches(tt.object, now))
+		})
+	}
+}
+
+func TestTagsOf(t *testing.T) {
+	payload := map[string]any{
+		"tags": []any{
+			"plain",
+			map[string]any{"key": "owner", "value": "team-a", "context": "CONTEXTLESS"},
+			map[string]any{"key": "temporary"},
+			map[string]any{"value": "no key"},
+			42,
+		},
+	}
+
+	assert.Equal(t, []string{"plain", "owner:team-a", "temporary"}, pointer.TagsOf(payload))
+	assert.Empty(t, pointer.TagsOf(map[string]any{"name": "untagged"}))
+}
@@ -40,6 +40,9 @@ type DeletePointer struct {
 
 	//OriginObjectId is DT ID of the configuration. Mutually exclusive with Identifier.
 	OriginObjectId string
+
+	// Criteria select all objects of the Type matching them. Mutually exclusive with Identifier and OriginObjectId.
+	Criteria *Criteria
 }
 
 func (d DeletePointer) AsCoordinate() coordinate.Coordinate {
@@ -51,6 +54,9 @@ func (d DeletePointer) AsCoordinate() coordinate.Coordinate {
 }
 
 func (d DeletePointer) String() string {
+	if d.Criteria != nil {
+		return fmt.Sprintf("%s[%s]", d.Type, d.Criteria)
+	}
 	if d.Project != "" {
 		return d.AsCoordinate().String()
 	}
@@ -0,0 +1,36 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pointer
+
+import "time"
+
+// RemoteObject is an object found in a Dynatrace environment when collecting all objects to delete. In contrast to a
+// DeletePointer it is always identified by its Dynatrace ID.
+type RemoteObject struct {
+	// Type is the classic API, settings schema or automation resource of the object, or "bucket"
+	Type string `json:"type"`
+	// ID of the object in the Dynatrace environment
+	ID string `json:"id"`
+	// Name of the object, if its type has names
+	Name string `json:"name,omitempty"`
+	// Created is the time the object was created, if known
+	Created time.Time `json:"created"`
+	// LastModifiedBy is the user who last modified the object, if known
+	LastModifiedBy string `json:"lastModifiedBy,omitempty"`
+	// Tags of the object, if it has any and they were retrieved
+	Tags []string `json:"tags,omitempty"`
+}
@@ -18,26 +18,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"sync"
 	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/concurrency"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/mutlierror"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/cluster"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/document"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/plan"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/prefetch"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/rollback"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/setting"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/unchanged"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/validate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/policy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/secrets"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	clientErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
@@ -53,6 +68,108 @@ type DeployConfigsOptions struct {
 	// DryRun states that the deployment shall just run in dry-run mode, meaning
 	// that actual deployment of the configuration to a tenant will be skipped
 	DryRun bool
+	// MaxConcurrentDeployments limits the number of configurations that are deployed in parallel to one environment.
+	// Configurations without any dependency relationship are deployed concurrently, dependent configurations are still
+	// deployed in topological order. A value <= 0 means no limit.
+	MaxConcurrentDeployments int
+	// RemoteValidation states that during a dry-run, rendered classic config and settings payloads are validated
+	// against the validation endpoints of the target environment. It has no effect if DryRun is not set.
+	RemoteValidation bool
+	// Plan states that no configuration is deployed. Instead, the rendered configurations are compared to the current
+	// state of the environment and a plan of what would be created, updated, or left unchanged is printed.
+	// Like DryRun, a plan continues on errors.
+	Plan bool
+	// RollbackOnError states that all modifications done to an environment are reverted if the deployment to that
+	// environment fails. Updated objects are restored to their state before the deployment, created objects are deleted.
+	// It has no effect for a DryRun or Plan.
+	RollbackOnError bool
+	// Only restricts the deployment to configurations whose coordinate (project:type:configId) matches at least one
+	// of the given patterns, plus all configurations they depend on. Patterns use the syntax of path.Match, e.g.
+	// 'project:builtin:*:*'. If empty, all configurations are deployed.
+	Only []string
+	// StampOwnership states that deployed configs are stamped with metadata identifying the monaco project, the
+	// coordinate of the config, and the time of the deployment. Classic configs are stamped with ownership tags if their
+	// API supports tags, and so are SLOs. Settings 2.0 objects and documents are always identifiable, as the project and
+	// coordinate are encoded in their externalId. The deployment time is ignored by SkipUnchanged, so an object that
+	// is otherwise unchanged keeps the time it was last written.
+	StampOwnership bool
+	// SkipUnchanged states that before creating or updating an object, its current state is read from the environment.
+	// If it already matches the rendered payload, the object is not written again. This reduces API calls and audit
+	// log entries for repeated deployments of the same configuration. It has no effect for a DryRun or Plan.
+	SkipUnchanged bool
+	// MaxParallelEnvironments limits the number of environments that are deployed to in parallel. As environments are
+	// independent of each other, deploying to them in parallel speeds up deployments to many environments. Values <= 1
+	// deploy to one environment after the other.
+	MaxParallelEnvironments int
+	// Reporter is notified about the outcome of the deployment of every configuration. Optional.
+	Reporter report.Reporter
+	// Policy checks the rendered payloads of all configurations before the deployment starts. If any configuration
+	// violates a policy, nothing is deployed, unless ContinueOnErr or DryRun is set, in which case only the violating
+	// configurations fail to deploy. Optional.
+	Policy policy.Evaluator
+	// ConfigTimeout limits the time the deployment of a single configuration may take, including all retries.
+	// Values <= 0 do not limit the deployment time.
+	ConfigTimeout time.Duration
+	// SecretScan defines whether rendered payloads are scanned for values that look like secrets before they are
+	// deployed, and whether configs containing such values fail to deploy or are only warned about. Defaults to
+	// secrets.LevelOff.
+	SecretScan secrets.Level
+	// SecretScanAllowList lists values that are not reported by the secret scan
+	SecretScanAllowList secrets.AllowList
+	// SkipDeprecated states that configs of deprecated classic APIs are skipped with a warning instead of being
+	// deployed. Such configs are reported as skipped, naming the API replacing the deprecated one.
+	SkipDeprecated bool
+	// Prefetch states that before deploying to an environment, the existing objects of all classic APIs and settings
+	// schemas used by the deployment are listed once, in parallel. Subsequent lookups of existing objects during the
+	// deployment are then served from the client's cache instead of listing the same API repeatedly. It has no effect
+	// for a DryRun.
+	Prefetch bool
+	// AcceptNewerSchema states that settings configs declaring an older schema version than the environment provides
+	// are deployed against the newer version. Otherwise, configs declaring a version with a different major version
+	// fail to deploy before any request modifying the environment is sent.
+	AcceptNewerSchema bool
+	// ResolvedParameters collects the resolved and masked parameter values of every config, if set. The values of
+	// each config are also logged at debug level during a DryRun.
+	ResolvedParameters *ResolvedParameters
+	// Results holds the entities deployed by earlier Deploy calls of the same deployment, e.g. to earlier canary
+	// stages, which configs may reference. If nil, only configs deployed by this call can be referenced.
+	Results *Results
+	// EnvironmentHooks are notified before and after the configs are deployed to each environment. Optional.
+	EnvironmentHooks EnvironmentHooks
+}
+
+// EnvironmentHooks are notified before and after the configs of an environment are deployed
+type EnvironmentHooks interface {
+	// Before is called before the given configs are deployed to the environment. If it returns an error, nothing is
+	// deployed to the environment and the deployment is aborted.
+	Before(ctx context.Context, environment string, configs []*config.Config) error
+	// After is called after the given configs were deployed to the environment, with the error of the deployment,
+	// if any. An error returned by After fails the deployment to the environment.
+	After(ctx context.Context, environment string, configs []*config.Config, deployErr error) error
+}
+
+// configDeployOptions holds the options applied to the deployment of every single config of an environment
+type configDeployOptions struct {
+	// stampOwnership states that classic configs and SLOs are stamped with ownership tags
+	stampOwnership bool
+	// deploymentTime is the time the deployment started, which is shared by all configs
+	deploymentTime time.Time
+	// policyViolations holds the policy violations of configs found before the deployment, which fail to deploy
+	policyViolations policyViolations
+	// timeout limits the deployment time of each config, if > 0
+	timeout time.Duration
+	// skipDeprecated states that configs of deprecated classic APIs are skipped
+	skipDeprecated bool
+	// secretScanner scans rendered payloads for secrets before they are deployed, if set
+	secretScanner *secrets.Scanner
+	// secretScan defines whether configs containing secrets fail to deploy
+	secretScan secrets.Level
+	// acceptNewerSchema states that settings configs are upgraded to newer schema versions of the environment
+	acceptNewerSchema bool
+	// resolvedParameters collects the resolved parameter values of every config, if set
+	resolvedParameters *ResolvedParameters
+	// dryRun states that resolved parameter values are logged
+	dryRun bool
 }
 
 type ClientSet struct {
@@ -61,6 +178,8 @@ type ClientSet struct {
 	Automation automation.Client
 	Bucket     bucket.Client
 	Document   document.Client
+	SLO        slo.Client
+	Cluster    cluster.Client
 }
 
 var DummyClientSet = ClientSet{
@@ -69,6 +188,8 @@ var DummyClientSet = ClientSet{
 	Automation: &automation.DummyClient{},
 	Bucket:     &bucket.DummyClient{},
 	Document:   &document.DummyClient{},
+	SLO:        &slo.DummyClient{},
+	Cluster:    &cluster.DummyClient{},
 }
 
 var (
@@ -77,50 +198,152 @@ var (
 	skipError = errors.New("skip error")
 )
 
-func Deploy(projects []project.Project, environmentClients dynatrace.EnvironmentClients, opts DeployConfigsOptions) error {
+// rollbackTimeout limits the time the rollback of an environment may take
+const rollbackTimeout = 10 * time.Minute
+
+// deprecatedAPISkipError is returned instead of deploying a config of a deprecated classic API, if SkipDeprecated is set
+type deprecatedAPISkipError struct {
+	api api.API
+}
+
+func (e deprecatedAPISkipError) Error() string {
+	return fmt.Sprintf("API %q is deprecated, migrate to %q", e.api.ID, e.api.DeprecatedBy)
+}
+
+func (e deprecatedAPISkipError) Is(target error) bool {
+	return target == skipError
+}
+
+// Deploy deploys the configs of the given projects to all environments of the given clients. Cancelling the context
+// stops the deployment: in-flight requests are cancelled and no further configs or environments are deployed.
+func Deploy(ctx context.Context, projects []project.Project, environmentClients dynatrace.EnvironmentClients, opts DeployConfigsOptions) error {
 	g := graph.New(projects, environmentClients.Names())
 	deploymentErrors := make(deployErrors.EnvironmentDeploymentErrors)
 
+	dryRun := opts.DryRun || opts.Plan
+	configOpts := configDeployOptions{
+		stampOwnership:     opts.StampOwnership,
+		deploymentTime:     time.Now(),
+		timeout:            opts.ConfigTimeout,
+		skipDeprecated:     opts.SkipDeprecated,
+		secretScan:         opts.SecretScan,
+		acceptNewerSchema:  opts.AcceptNewerSchema,
+		resolvedParameters: opts.ResolvedParameters,
+		dryRun:             dryRun,
+	}
+	if opts.SecretScan != "" && opts.SecretScan != secrets.LevelOff {
+		configOpts.secretScanner = secrets.NewScanner(opts.SecretScanAllowList)
+	}
+
 	if validationErrs := validate.Validate(projects); validationErrs != nil {
-		if !opts.ContinueOnErr && !opts.DryRun {
+		if !opts.ContinueOnErr && !dryRun {
 			return validationErrs
 		}
 		errors.As(validationErrs, &deploymentErrors)
 	}
 
-	for env, clients := range environmentClients {
-		ctx := createContextWithEnvironment(env)
-		log.WithCtxFields(ctx).Info("Deploying configurations to environment %q...", env.Name)
-
-		sortedConfigs, err := g.GetIndependentlySortedConfigs(env.Name)
-		if err != nil {
-			return fmt.Errorf("failed to get independently sorted configs for environment %q: %w", env.Name, err)
-		}
+	// environments referenced by configs of another environment are deployed before that environment
+	envDependencies := environmentDependencies(projects)
+	if err := checkEnvironmentCycles(envDependencies); err != nil {
+		return err
+	}
 
-		var clientSet ClientSet
-		if opts.DryRun {
-			clientSet = DummyClientSet
-		} else {
-			clientSet = ClientSet{
-				Classic:    clients.DTClient,
-				Settings:   clients.DTClient,
-				Automation: clients.AutClient,
-				Bucket:     clients.BucketClient,
-				Document:   clients.DocumentClient,
+	// configs are selected before the deployment starts, so that the total number of configs is known in advance
+	total := 0
+	for env := range environmentClients {
+		if len(opts.Only) > 0 {
+			matched, err := g.RetainWithDependencies(env.Name, matchesAnyPattern(opts.Only))
+			if err != nil {
+				return fmt.Errorf("failed to select configs for environment %q: %w", env.Name, err)
 			}
+			log.WithFields(field.Environment(env.Name, env.Group)).Info("%d configurations of environment %q match %q", matched, env.Name, opts.Only)
+		}
+		if eg, ok := g[env.Name]; ok {
+			total += eg.Nodes().Len()
 		}
+	}
+	if opts.Reporter != nil {
+		report.ReportPlanned(opts.Reporter, total)
+	}
 
-		if err = deployComponents(ctx, sortedConfigs, clientSet); err != nil {
-			log.WithFields(field.Environment(env.Name, env.Group), field.Error(err)).Error("Deployment failed for environment %q: %v", env.Name, err)
-			deploymentErrors = deploymentErrors.Append(env.Name, err)
-			if !opts.ContinueOnErr && !opts.DryRun {
-				return deploymentErrors
+	results := opts.Results
+	if results == nil {
+		results = NewResults()
+	}
+	results.add(environmentClients.Names())
+	if opts.Policy != nil {
+		violations, err := checkPolicies(ctx, g, environmentClients.Names(), results.valueCache, opts.Policy)
+		if err != nil {
+			return err
+		}
+		if len(violations) > 0 && !opts.ContinueOnErr && !dryRun {
+			policyErrs := make(deployErrors.EnvironmentDeploymentErrors)
+			for env, errs := range violations {
+				for _, err := range errs {
+					policyErrs = policyErrs.Append(env, err)
+				}
 			}
-		} else {
-			log.WithFields(field.Environment(env.Name, env.Group)).Info("Deployment successful for environment %q", env.Name)
+			return policyErrs
 		}
+		configOpts.policyViolations = violations
 	}
 
+	var (
+		mutex    sync.Mutex
+		fatalErr error
+		aborted  bool
+		wg       sync.WaitGroup
+	)
+	envLimiter := concurrency.NewLimiter(max(opts.MaxParallelEnvironments, 1))
+	for env, clients := range environmentClients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer results.markDone(env.Name)
+
+			// dependencies are awaited before occupying a slot of the limiter, so that waiting environments cannot
+			// block the deployment of the environments they wait for
+			results.waitFor(envDependencies[env.Name])
+
+			envLimiter.ExecuteBlocking(func() {
+				mutex.Lock()
+				skip := aborted
+				mutex.Unlock()
+				if ctx.Err() != nil {
+					log.WithFields(field.Environment(env.Name, env.Group)).Warn("Skipping deployment to environment %q, as the deployment was cancelled", env.Name)
+					return
+				}
+				if skip {
+					log.WithFields(field.Environment(env.Name, env.Group)).Warn("Skipping deployment to environment %q, as the deployment to another environment failed", env.Name)
+					return
+				}
+
+				err := deployEnvironment(ctx, env, clients, g, results.lookupFor(env.Name), opts, configOpts)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+				var deploymentErrs deployErrors.DeploymentErrors
+				if err != nil && !errors.As(err, &deploymentErrs) {
+					fatalErr = errors.Join(fatalErr, err)
+					aborted = true
+				} else if err != nil {
+					deploymentErrors = deploymentErrors.Append(env.Name, err)
+					if !opts.ContinueOnErr && !dryRun {
+						aborted = true
+					}
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	envLimiter.Close()
+
+	if fatalErr != nil {
+		return fatalErr
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("deployment cancelled: %w", err)
+	}
 	if len(deploymentErrors) != 0 {
 		return deploymentErrors
 	}
@@ -128,16 +351,197 @@ func Deploy(projects []project.Project, environmentClients dynatrace.Environment
 	return nil
 }
 
-func deployComponents(ctx context.Context, components []graph.SortedComponent, clients ClientSet) error {
+// deployEnvironment deploys all configs of the given graph to one environment. Deployment errors of single configs are
+// returned as deployErrors.DeploymentErrors, all other errors prevent the deployment to the environment altogether.
+func deployEnvironment(ctx context.Context, env dynatrace.EnvironmentInfo, clients *client.ClientSet, g graph.ConfigGraphPerEnvironment, resolvedEntities environmentEntities, opts DeployConfigsOptions, configOpts configDeployOptions) error {
+	ctx = report.NewContextWithReporter(createContextWithEnvironment(ctx, env), opts.Reporter)
+	log.WithCtxFields(ctx).Info("Deploying configurations to environment %q...", env.Name)
+
+	sortedConfigs, err := g.GetIndependentlySortedConfigs(env.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get independently sorted configs for environment %q: %w", env.Name, err)
+	}
+
+	var clientSet ClientSet
+	var p *plan.Plan
+	var journal *rollback.Journal
+	if opts.Plan {
+		p = plan.New()
+		planClient := plan.NewDynatraceClient(clients.DTClient, p)
+		clientSet = ClientSet{
+			Classic:    planClient,
+			Settings:   planClient,
+			Automation: plan.NewAutomationClient(clients.AutClient, p),
+			Bucket:     plan.NewBucketClient(clients.BucketClient, p),
+			Document:   plan.NewDocumentClient(clients.DocumentClient, p),
+			SLO:        plan.NewSLOClient(clients.SLOClient, p),
+			Cluster:    DummyClientSet.Cluster,
+		}
+		if clients.ClusterClient != nil {
+			clientSet.Cluster = plan.NewClusterClient(clients.ClusterClient, p)
+		}
+		clientSet = withoutMissingClients(clientSet, clients)
+	} else if opts.DryRun && opts.RemoteValidation {
+		validationClient := validate.NewRemoteValidationClient(clients.DTClient)
+		clientSet = ClientSet{
+			Classic:    validationClient,
+			Settings:   validationClient,
+			Automation: DummyClientSet.Automation,
+			Bucket:     DummyClientSet.Bucket,
+			Document:   DummyClientSet.Document,
+			SLO:        DummyClientSet.SLO,
+			Cluster:    DummyClientSet.Cluster,
+		}
+	} else if opts.DryRun {
+		clientSet = DummyClientSet
+	} else {
+		var dtClient unchanged.ConfigAndSettingsClient = clients.DTClient
+		var autClient automation.Client = clients.AutClient
+		var bucketClient bucket.Client = clients.BucketClient
+		var documentClient document.Client = clients.DocumentClient
+		var sloClient slo.Client = clients.SLOClient
+		var clusterClient cluster.Client = clients.ClusterClient
+		if opts.RollbackOnError {
+			journal = rollback.NewJournal()
+			dtClient = rollback.NewDynatraceClient(clients.DTClient, journal)
+			autClient = rollback.NewAutomationClient(clients.AutClient, journal)
+			bucketClient = rollback.NewBucketClient(clients.BucketClient, journal)
+			documentClient = rollback.NewDocumentClient(clients.DocumentClient, journal)
+			sloClient = rollback.NewSLOClient(clients.SLOClient, journal)
+			clusterClient = rollback.NewClusterClient(clients.ClusterClient, journal)
+		}
+		if opts.SkipUnchanged {
+			dtClient = unchanged.NewDynatraceClient(clients.DTClient, dtClient)
+			autClient = unchanged.NewAutomationClient(clients.AutClient, autClient)
+			bucketClient = unchanged.NewBucketClient(clients.BucketClient, bucketClient)
+			documentClient = unchanged.NewDocumentClient(documentClient)
+			sloClient = unchanged.NewSLOClient(clients.SLOClient, sloClient)
+			clusterClient = unchanged.NewClusterClient(clients.ClusterClient, clusterClient)
+		}
+		clientSet = ClientSet{
+			Classic:    dtClient,
+			Settings:   dtClient,
+			Automation: autClient,
+			Bucket:     bucketClient,
+			Document:   documentClient,
+			SLO:        sloClient,
+			Cluster:    clusterClient,
+		}
+		clientSet = withoutMissingClients(clientSet, clients)
+	}
+
+	if opts.Prefetch && !opts.DryRun && clients.DTClient != nil {
+		prefetch.Prefetch(ctx, clients.DTClient, api.NewAPIs(), configsOf(sortedConfigs))
+	}
+
+	if opts.EnvironmentHooks != nil {
+		if err := opts.EnvironmentHooks.Before(ctx, env.Name, configsOf(sortedConfigs)); err != nil {
+			return fmt.Errorf("pre-deployment hooks of environment %q failed: %w", env.Name, err)
+		}
+	}
+
+	limiter := concurrency.NewLimiter(opts.MaxConcurrentDeployments)
+	err = deployComponents(ctx, sortedConfigs, clientSet, resolvedEntities, limiter, configOpts)
+	limiter.Close()
+	if p != nil {
+		log.WithCtxFields(ctx).Info("Deployment plan for environment %q:", env.Name)
+		p.Log(ctx)
+	}
+	if err != nil {
+		log.WithFields(field.Environment(env.Name, env.Group), field.Error(err)).Error("Deployment failed for environment %q: %v", env.Name, err)
+		if journal != nil {
+			rollbackEnvironment(ctx, env, journal)
+		}
+	}
+
+	if opts.EnvironmentHooks != nil {
+		// post-deployment hooks report the outcome of a cancelled deployment as well
+		if hookErr := opts.EnvironmentHooks.After(context.WithoutCancel(ctx), env.Name, configsOf(sortedConfigs), err); hookErr != nil {
+			err = errors.Join(err, fmt.Errorf("post-deployment hooks of environment %q failed: %w", env.Name, hookErr))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(field.Environment(env.Name, env.Group)).Info("Deployment successful for environment %q", env.Name)
+	return nil
+}
+
+// withoutMissingClients unsets all clients of the given ClientSet whose underlying client of the environment is not set,
+// e.g. the platform clients of an environment without OAuth credentials. Wrapping clients like the plan or rollback
+// clients would otherwise call the missing client.
+func withoutMissingClients(clientSet ClientSet, clients *client.ClientSet) ClientSet {
+	if clients.DTClient == nil {
+		clientSet.Classic = nil
+		clientSet.Settings = nil
+	}
+	if clients.AutClient == nil {
+		clientSet.Automation = nil
+	}
+	if clients.BucketClient == nil {
+		clientSet.Bucket = nil
+	}
+	if clients.DocumentClient == nil {
+		clientSet.Document = nil
+	}
+	if clients.SLOClient == nil {
+		clientSet.SLO = nil
+	}
+	if clients.ClusterClient == nil {
+		clientSet.Cluster = nil
+	}
+	return clientSet
+}
+
+// matchesAnyPattern returns a function checking whether a coordinate matches at least one of the given patterns.
+// Invalid patterns never match - they are expected to be validated by the caller.
+func matchesAnyPattern(patterns []string) func(coordinate.Coordinate) bool {
+	return func(c coordinate.Coordinate) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, c.String()); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// rollbackEnvironment reverts all modifications recorded in the given journal during the deployment to env. As a
+// cancelled or timed out deployment is rolled back as well, the rollback is not cancelled with the deployment, but
+// limited by rollbackTimeout.
+func rollbackEnvironment(ctx context.Context, env dynatrace.EnvironmentInfo, journal *rollback.Journal) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	log.WithFields(field.Environment(env.Name, env.Group)).Warn("Rolling back %d modifications of environment %q...", journal.Len(), env.Name)
+	if err := journal.Rollback(ctx); err != nil {
+		log.WithFields(field.Environment(env.Name, env.Group), field.Error(err)).Error("Rollback of environment %q failed, the environment may be left in a partially deployed state: %v", env.Name, err)
+		return
+	}
+	log.WithFields(field.Environment(env.Name, env.Group)).Info("Rollback of environment %q successful", env.Name)
+}
+
+// configsOf returns all configs of the given components
+func configsOf(components []graph.SortedComponent) []*config.Config {
+	var configs []*config.Config
+	for _, c := range components {
+		for _, n := range c.SortedNodes {
+			configs = append(configs, n.(graph.ConfigNode).Config)
+		}
+	}
+	return configs
+}
+
+func deployComponents(ctx context.Context, components []graph.SortedComponent, clients ClientSet, resolvedEntities environmentEntities, limiter *concurrency.Limiter, opts configDeployOptions) error {
 	log.WithCtxFields(ctx).Info("Deploying %d independent configuration sets in parallel...", len(components))
 	errCount := 0
 	errChan := make(chan error, len(components))
 
-	resolvedEntities := entities.New()
 	// Iterate over components and launch a goroutine for each component deployment.
 	for i := range components {
 		go func(ctx context.Context, component graph.SortedComponent) {
-			errChan <- deployGraph(ctx, component.Graph, clients, resolvedEntities)
+			errChan <- deployGraph(ctx, component.Graph, clients, resolvedEntities, limiter, opts)
 		}(context.WithValue(ctx, log.CtxGraphComponentId{}, log.CtxValGraphComponentId(i)), components[i])
 	}
 
@@ -160,7 +564,9 @@ func deployComponents(ctx context.Context, components []graph.SortedComponent, c
 	return nil
 }
 
-func deployGraph(ctx context.Context, configGraph *simple.DirectedGraph, clients ClientSet, resolvedEntities *entities.EntityMap) error {
+// deployGraph deploys the given graph level by level, starting with its roots. The deployment of the nodes of one level
+// happens in parallel, bounded by the given limiter, which is shared between all components of an environment.
+func deployGraph(ctx context.Context, configGraph *simple.DirectedGraph, clients ClientSet, resolvedEntities environmentEntities, limiter *concurrency.Limiter, opts configDeployOptions) error {
 	g := simple.NewDirectedGraph()
 	gonum.Copy(g, configGraph)
 
@@ -172,10 +578,12 @@ func deployGraph(ctx context.Context, configGraph *simple.DirectedGraph, clients
 
 		for _, root := range roots {
 			node := root.(graph.ConfigNode)
-			time.Sleep(api.NewAPIs()[node.Config.Coordinate.Type].DeployWaitDuration)
-			go func(ctx context.Context, node graph.ConfigNode) {
-				errChan <- deployNode(ctx, node, configGraph, clients, resolvedEntities)
-			}(context.WithValue(ctx, log.CtxKeyCoord{}, node.Config.Coordinate), node)
+			nodeCtx := context.WithValue(ctx, log.CtxKeyCoord{}, node.Config.Coordinate)
+			// waiting is cut short if the deployment is cancelled, in which case deployConfig fails right away
+			_ = timeutils.SleepWithContext(ctx, api.NewAPIs()[node.Config.Coordinate.Type].DeployWaitDuration)
+			limiter.Execute(func() {
+				errChan <- deployNode(nodeCtx, node, configGraph, clients, resolvedEntities, opts)
+			})
 		}
 
 		for range roots {
@@ -200,8 +608,12 @@ func deployGraph(ctx context.Context, configGraph *simple.DirectedGraph, clients
 	return nil
 }
 
-func deployNode(ctx context.Context, n graph.ConfigNode, configGraph graph.ConfigGraph, clients ClientSet, resolvedEntities *entities.EntityMap) error {
-	resolvedEntity, err := deployConfig(ctx, n.Config, clients, resolvedEntities)
+func deployNode(ctx context.Context, n graph.ConfigNode, configGraph graph.ConfigGraph, clients ClientSet, resolvedEntities environmentEntities, opts configDeployOptions) error {
+	start := time.Now()
+	deployCtx, cancel := withConfigTimeout(ctx, opts.timeout)
+	resolvedEntity, err := deployConfig(deployCtx, n.Config, clients, resolvedEntities, opts)
+	cancel()
+	duration := time.Since(start)
 
 	if err != nil {
 		failed := !errors.Is(err, skipError)
@@ -211,16 +623,32 @@ func deployNode(ctx context.Context, n graph.ConfigNode, configGraph graph.Confi
 		lock.Unlock()
 
 		if failed {
+			report.GetReporterFromContextOrDiscard(ctx).ReportDeployment(ctx, n.Config.Coordinate, report.StateFailed, duration, err)
 			return err
 		}
+		// configs skipped for a reason other than being marked as skipped report it
+		var reason error
+		if err != skipError {
+			reason = err
+		}
+		report.GetReporterFromContextOrDiscard(ctx).ReportDeployment(ctx, n.Config.Coordinate, report.StateSkipped, duration, reason)
 		return nil
 	}
 
 	resolvedEntities.Put(resolvedEntity)
+	report.GetReporterFromContextOrDiscard(ctx).ReportDeployment(ctx, n.Config.Coordinate, report.StateDeployed, duration, nil)
 	log.WithCtxFields(ctx).WithFields(field.StatusDeployed()).Info("Deployment successful")
 	return nil
 }
 
+// withConfigTimeout returns a context limiting the deployment of a single config to the given timeout, if it is > 0
+func withConfigTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func removeChildren(ctx context.Context, parent, root graph.ConfigNode, configGraph graph.ConfigGraph, failed bool) {
 
 	children := configGraph.From(parent.ID())
@@ -246,6 +674,7 @@ func removeChildren(ctx context.Context, parent, root graph.ConfigNode, configGr
 		} else {
 			l.Warn("Skipping deployment of %v, as it depends on %v which %s", childCfg.Coordinate, parent.Config.Coordinate, reason)
 		}
+		report.GetReporterFromContextOrDiscard(ctx).ReportDeployment(ctx, childCfg.Coordinate, report.StateSkipped, 0, fmt.Errorf("depends on %v which %s", parent.Config.Coordinate, reason))
 
 		removeChildren(ctx, child, root, configGraph, failed)
 
@@ -253,12 +682,30 @@ func removeChildren(ctx context.Context, parent, root graph.ConfigNode, configGr
 	}
 }
 
-func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, resolvedEntities config.EntityLookup) (entities.ResolvedEntity, error) {
+func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, resolvedEntities config.EntityLookup, opts configDeployOptions) (entities.ResolvedEntity, error) {
 	if c.Skip {
 		log.WithCtxFields(ctx).WithFields(field.StatusDeploymentSkipped()).Info("Skipping deployment of config")
 		return entities.ResolvedEntity{}, skipError //fake resolved entity that "old" deploy creates is never needed, as we don't even try to deploy dependencies of skipped configs (so no reference will ever be attempted to resolve)
 	}
 
+	if t, ok := c.Type.(config.ClassicApiType); ok && opts.skipDeprecated {
+		if a := api.NewAPIs()[t.Api]; a.DeprecatedBy != "" {
+			err := deprecatedAPISkipError{api: a}
+			log.WithCtxFields(ctx).WithFields(field.StatusDeploymentSkipped()).Warn("Skipping deployment of config: %v", err)
+			return entities.ResolvedEntity{}, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Deployment cancelled: %v", err)
+		return entities.ResolvedEntity{}, fmt.Errorf("deployment cancelled: %w", err)
+	}
+
+	if err := opts.policyViolations.get(c); err != nil {
+		log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Invalid configuration - policy check failed: %v", err)
+		return entities.ResolvedEntity{}, err
+	}
+
 	properties, errs := c.ResolveParameterValues(resolvedEntities)
 	if len(errs) > 0 {
 		err := mutlierror.New(errs...)
@@ -266,15 +713,54 @@ func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, reso
 		return entities.ResolvedEntity{}, err
 	}
 
+	recordResolvedParameters(ctx, c, properties, opts)
+
 	renderedConfig, err := c.Render(properties)
 	if err != nil {
 		log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Invalid configuration - failed to render JSON template: %v", err)
 		return entities.ResolvedEntity{}, err
 	}
 
+	if opts.secretScanner != nil {
+		if err := opts.secretScanner.Scan(c.Coordinate, []byte(renderedConfig)); err != nil {
+			if opts.secretScan == secrets.LevelFail {
+				log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Invalid configuration - secret scan failed: %v", err)
+				return entities.ResolvedEntity{}, err
+			}
+			log.WithCtxFields(ctx).WithFields(field.Error(err)).Warn("Secret scan: %v", err)
+		}
+	}
+
+	if _, ok := c.Type.(config.SettingsType); ok && clients.Settings != nil {
+		if c, err = setting.CheckSchemaVersion(ctx, clients.Settings, c, opts.acceptNewerSchema); err != nil {
+			log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Invalid configuration - schema version check failed: %v", err)
+			return entities.ResolvedEntity{}, err
+		}
+	}
+
 	log.WithCtxFields(ctx).WithFields(field.StatusDeploying()).Info("Deploying config")
-	var resolvedEntity entities.ResolvedEntity
-	var deployErr error
+	// rate limited requests are retried by the rest client already, thus a deployment failing nonetheless is not retried
+	resolvedEntity, deployErr := deployConfigOfType(ctx, c, clients, properties, renderedConfig, opts)
+
+	if deployErr != nil {
+		var responseErr clientErrors.RespError
+		if errors.As(deployErr, &responseErr) {
+			logResponseError(ctx, responseErr)
+			return entities.ResolvedEntity{}, responseErr
+		}
+
+		log.WithCtxFields(ctx).WithFields(field.Error(deployErr)).Error("Deployment failed - Monaco Error: %v", deployErr)
+		return entities.ResolvedEntity{}, deployErr
+	}
+	return resolvedEntity, nil
+}
+
+// deployConfigOfType deploys the rendered config using the client matching its type
+func deployConfigOfType(ctx context.Context, c *config.Config, clients ClientSet, properties parameter.Properties, renderedConfig string, opts configDeployOptions) (resolvedEntity entities.ResolvedEntity, deployErr error) {
+	if !hasClientFor(c, clients) {
+		return entities.ResolvedEntity{}, fmt.Errorf("no client to deploy configs of type %q is available for the environment, check whether it defines the required credentials", c.Type.ID())
+	}
+
 	switch c.Type.(type) {
 	case config.SettingsType:
 		var insertAfter string
@@ -284,6 +770,11 @@ func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, reso
 		resolvedEntity, deployErr = setting.Deploy(ctx, clients.Settings, properties, renderedConfig, c, insertAfter)
 
 	case config.ClassicApiType:
+		if opts.stampOwnership {
+			if renderedConfig, deployErr = classic.StampOwnership(api.NewAPIs()[c.Coordinate.Type], renderedConfig, idutils.GenerateOwnershipTags(c.Coordinate, opts.deploymentTime)); deployErr != nil {
+				break
+			}
+		}
 		resolvedEntity, deployErr = classic.Deploy(ctx, clients.Classic, api.NewAPIs(), properties, renderedConfig, c)
 
 	case config.AutomationType:
@@ -299,38 +790,68 @@ func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, reso
 			deployErr = fmt.Errorf("unknown config-type (ID: %q)", c.Type.ID())
 		}
 
+	case config.SLOType:
+		if opts.stampOwnership {
+			if renderedConfig, deployErr = idutils.StampOwnershipTags(renderedConfig, idutils.SLOOwnershipTagsPath, idutils.GenerateOwnershipTags(c.Coordinate, opts.deploymentTime)); deployErr != nil {
+				break
+			}
+		}
+		resolvedEntity, deployErr = slo.Deploy(ctx, clients.SLO, properties, renderedConfig, c)
+
+	case config.ClusterType:
+		resolvedEntity, deployErr = cluster.Deploy(ctx, clients.Cluster, properties, renderedConfig, c)
+
+	case config.SegmentType, config.OpenPipelineType:
+		// segments and OpenPipeline configurations can be downloaded, but not yet deployed
+		deployErr = fmt.Errorf("deploying configs of type %q is not supported yet", c.Type.ID())
+
 	default:
 		deployErr = fmt.Errorf("unknown config-type (ID: %q)", c.Type.ID())
 	}
+	return resolvedEntity, deployErr
+}
 
-	if deployErr != nil {
-		var responseErr clientErrors.RespError
-		if errors.As(deployErr, &responseErr) {
-			logResponseError(ctx, responseErr)
-			return entities.ResolvedEntity{}, responseErr
-		}
-
-		log.WithCtxFields(ctx).WithFields(field.Error(deployErr)).Error("Deployment failed - Monaco Error: %v", deployErr)
-		return entities.ResolvedEntity{}, deployErr
+// hasClientFor returns whether the client deploying configs of the type of c is set
+func hasClientFor(c *config.Config, clients ClientSet) bool {
+	switch c.Type.(type) {
+	case config.SettingsType:
+		return clients.Settings != nil
+	case config.ClassicApiType:
+		return clients.Classic != nil
+	case config.AutomationType:
+		return clients.Automation != nil
+	case config.BucketType:
+		return clients.Bucket != nil
+	case config.DocumentType:
+		return clients.Document != nil
+	case config.SLOType:
+		return clients.SLO != nil
+	case config.ClusterType:
+		return clients.Cluster != nil
 	}
-	return resolvedEntity, nil
+	return true
 }
 
 // logResponseError prints user-friendly messages based on the response errors status
 func logResponseError(ctx context.Context, responseErr clientErrors.RespError) {
+	var hint string
+	if h := clientErrors.Hint(responseErr); h != "" {
+		hint = "\n    Hint: " + h
+	}
+
 	if responseErr.StatusCode >= 400 && responseErr.StatusCode <= 499 {
-		log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace API rejected HTTP request / JSON data: %v", responseErr)
+		log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace API rejected HTTP request / JSON data: %v%s", responseErr, hint)
 		return
 	}
 
 	if responseErr.StatusCode >= 500 && responseErr.StatusCode <= 599 {
-		log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace Server Error: %v", responseErr)
+		log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace Server Error: %v%s", responseErr, hint)
 		return
 	}
 
-	log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace API call unsuccessful: %v", responseErr)
+	log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace API call unsuccessful: %v%s", responseErr, hint)
 }
 
-func createContextWithEnvironment(env dynatrace.EnvironmentInfo) context.Context {
-	return context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
+func createContextWithEnvironment(ctx context.Context, env dynatrace.EnvironmentInfo) context.Context {
+	return context.WithValue(ctx, log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
 }
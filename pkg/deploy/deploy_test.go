@@ -17,25 +17,40 @@
 package deploy_test
 
 import (
+	"context"
 	"fmt"
+	coreAutomation "github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/crossenvironment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	secretParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/testutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/policy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/secrets"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var dashboardApi = api.API{ID: "dashboard", URLPath: "dashboard", DeprecatedBy: "dashboard-v2"}
@@ -99,7 +114,7 @@ func TestDeployConfigGraph_SingleConfig(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 
 	assert.Emptyf(t, errors, "errors: %v", errors)
 
@@ -162,10 +177,55 @@ func TestDeployConfigGraph_SettingShouldFailUpsert(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.NotEmpty(t, errors)
 }
 
+func TestDeployConfigGraph_DoesNotRetryRateLimitedDeployments(t *testing.T) {
+	parameters := []parameter.NamedParameter{
+		{
+			Name:      config.NameParameter,
+			Parameter: &parameter.DummyParameter{Value: "test"},
+		},
+		{
+			Name:      config.ScopeParameter,
+			Parameter: &parameter.DummyParameter{Value: "something"},
+		},
+	}
+
+	conf := config.Config{
+		Type:       config.SettingsType{SchemaId: "builtin:test"},
+		Template:   testutils.GenerateDummyTemplate(t),
+		Parameters: testutils.ToParameterMap(parameters),
+	}
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{
+					"builtin:test": []config.Config{conf},
+				},
+			},
+		},
+	}
+
+	rateLimited := rest.RateLimitedError{
+		RespError:  rest.NewRespErr("rate limited", rest.Response{StatusCode: http.StatusTooManyRequests}),
+		RetryAfter: time.Millisecond,
+	}
+
+	// the rest client retries rate limited requests already
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return(dtclient.DynatraceEntity{}, rateLimited).Times(1)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
+	assert.Error(t, err)
+}
+
 func TestDeployConfigGraph_DoesNotFailOnEmptyConfigs(t *testing.T) {
 
 	p := []project.Project{
@@ -186,7 +246,7 @@ func TestDeployConfigGraph_DoesNotFailOnEmptyConfigs(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -201,7 +261,7 @@ func TestDeployConfigGraph_DoesNotFailOnEmptyProject(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -213,7 +273,7 @@ func TestDeployConfigGraph_DoesNotFailNilProject(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(nil, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), nil, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -239,13 +299,57 @@ func TestDeployConfigGraph_DoesNotDeploySkippedConfig(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 	createdEntities, found := dummyClient.GetEntries(api.NewAPIs()["dashboard"])
 	assert.False(t, found, "expected NO entries for dashboard API to exist")
 	assert.Len(t, createdEntities, 0)
 }
 
+func TestDeployConfigGraph_SchemaVersionDrift(t *testing.T) {
+	newProjects := func() []project.Project {
+		return []project.Project{
+			{
+				Id: "proj",
+				Configs: project.ConfigsPerTypePerEnvironments{
+					"env": project.ConfigsPerType{
+						"builtin:test": {
+							{
+								Template:   testutils.GenerateDummyTemplate(t),
+								Coordinate: coordinate.Coordinate{Project: "proj", Type: "builtin:test", ConfigId: "some setting"},
+								Type:       config.SettingsType{SchemaId: "builtin:test", SchemaVersion: "1.2"},
+								Parameters: config.Parameters{config.ScopeParameter: &value.ValueParameter{Value: "tenant"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("incompatible schema version fails before deploying", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().GetSchemaById("builtin:test").Return(dtclient.Schema{SchemaId: "builtin:test", Version: "2.0"}, nil)
+
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c}}
+		err := deploy.Deploy(context.TODO(), newProjects(), clients, deploy.DeployConfigsOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("newer schema version is accepted", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().GetSchemaById("builtin:test").Return(dtclient.Schema{SchemaId: "builtin:test", Version: "2.0"}, nil)
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			assert.Equal(t, "2.0", obj.SchemaVersion)
+			return dtclient.DynatraceEntity{Id: "42", Name: "name"}, nil
+		})
+
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c}}
+		err := deploy.Deploy(context.TODO(), newProjects(), clients, deploy.DeployConfigsOptions{AcceptNewerSchema: true})
+		assert.NoError(t, err)
+	})
+}
+
 func TestDeployConfigGraph_DeploysSetting(t *testing.T) {
 	c := client.NewMockDynatraceClient(gomock.NewController(t))
 
@@ -266,6 +370,7 @@ func TestDeployConfigGraph_DeploysSetting(t *testing.T) {
 			},
 		},
 	}
+	c.EXPECT().GetSchemaById("builtin:test").Return(dtclient.Schema{SchemaId: "builtin:test"}, nil)
 	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{
 		Id:   "42",
 		Name: "Super Special Settings Object",
@@ -288,7 +393,7 @@ func TestDeployConfigGraph_DeploysSetting(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -334,7 +439,7 @@ func TestDeployConfigsTargetingClassicConfigUnique(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -380,7 +485,7 @@ func TestDeployConfigsTargetingClassicConfigNonUniqueWithExistingCfgsOfSameName(
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -435,7 +540,7 @@ func TestDeployConfigsWithDeploymentErrors(t *testing.T) {
 
 	t.Run("deployment error - always continues on error", func(t *testing.T) {
 
-		err := deploy.Deploy(p, c, deploy.DeployConfigsOptions{}) // continues even without option set
+		err := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{}) // continues even without option set
 		assert.Error(t, err)
 
 		envErrs := make(errors.EnvironmentDeploymentErrors)
@@ -446,6 +551,21 @@ func TestDeployConfigsWithDeploymentErrors(t *testing.T) {
 		assert.ErrorAs(t, envErrs[env][0], &depErr)
 		assert.Equal(t, 2, depErr.ErrorCount, "Expected deployment to continue after the first error and count errors for both invalid configs")
 	})
+
+	t.Run("deployment error - every failed config is reported", func(t *testing.T) {
+		summary := report.NewSummary()
+		err := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{ContinueOnErr: true, Reporter: summary})
+		assert.Error(t, err)
+
+		records := summary.Records()
+		require.Len(t, records, 2)
+		for i, r := range records {
+			assert.Equal(t, configs[i].Coordinate, r.Config)
+			assert.Equal(t, env, r.Environment)
+			assert.Equal(t, report.StateFailed, r.State)
+			assert.NotEmpty(t, r.Error)
+		}
+	})
 }
 
 func TestDeployConfigGraph_DoesNotDeployConfigsDependingOnSkippedConfigs(t *testing.T) {
@@ -560,7 +680,7 @@ func TestDeployConfigGraph_DoesNotDeployConfigsDependingOnSkippedConfigs(t *test
 		dynatrace.EnvironmentInfo{Name: environmentName}: &clientSet,
 	}
 
-	errs := deploy.Deploy(projects, clients, deploy.DeployConfigsOptions{})
+	errs := deploy.Deploy(context.TODO(), projects, clients, deploy.DeployConfigsOptions{})
 	assert.NoError(t, errs)
 	assert.Zero(t, dummyClient.CreatedObjects())
 }
@@ -674,7 +794,7 @@ func TestDeployConfigGraph_DeploysIndependentConfigurations(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: environmentName}: &clientSet,
 	}
 
-	errs := deploy.Deploy(projects, clients, deploy.DeployConfigsOptions{})
+	errs := deploy.Deploy(context.TODO(), projects, clients, deploy.DeployConfigsOptions{})
 	assert.NoError(t, errs)
 
 	dashboards, found := dummyClient.GetEntries(api.NewAPIs()["dashboard"])
@@ -793,7 +913,7 @@ func TestDeployConfigGraph_DeploysIndependentConfigurations_IfContinuingAfterFai
 		dynatrace.EnvironmentInfo{Name: environmentName}: &clientSet,
 	}
 
-	errs := deploy.Deploy(projects, clients, deploy.DeployConfigsOptions{ContinueOnErr: true})
+	errs := deploy.Deploy(context.TODO(), projects, clients, deploy.DeployConfigsOptions{ContinueOnErr: true})
 	assert.Len(t, errs, 1)
 
 	dashboards, found := dummyClient.GetEntries(api.NewAPIs()["dashboard"])
@@ -1179,7 +1299,7 @@ func TestDeployConfigsValidatesClassicAPINames(t *testing.T) {
 				dynatrace.EnvironmentInfo{Name: "env2"}: &clientSet,
 			}
 
-			err := deploy.Deploy(tc.given, c, deploy.DeployConfigsOptions{})
+			err := deploy.Deploy(context.TODO(), tc.given, c, deploy.DeployConfigsOptions{})
 			if len(tc.wantErrsContain) == 0 {
 				assert.NoError(t, err)
 			} else {
@@ -1270,7 +1390,7 @@ func TestDeployConfigGraph_CollectsAllErrors(t *testing.T) {
 	}
 
 	t.Run("stop on error - returns validation errors", func(t *testing.T) {
-		errs := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+		errs := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 		assert.Error(t, errs)
 
 		var envErrs errors.EnvironmentDeploymentErrors
@@ -1281,7 +1401,7 @@ func TestDeployConfigGraph_CollectsAllErrors(t *testing.T) {
 	})
 
 	t.Run("continue on error - returns validation and deployment", func(t *testing.T) {
-		errs := deploy.Deploy(p, c, deploy.DeployConfigsOptions{ContinueOnErr: true})
+		errs := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{ContinueOnErr: true})
 		assert.Error(t, errs)
 
 		var envErrs errors.EnvironmentDeploymentErrors
@@ -1294,3 +1414,820 @@ func TestDeployConfigGraph_CollectsAllErrors(t *testing.T) {
 	})
 
 }
+
+// concurrencyTrackingClient records the maximum number of parallel UpsertConfigByName calls
+type concurrencyTrackingClient struct {
+	dtclient.DummyClient
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (c *concurrencyTrackingClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (dtclient.DynatraceEntity, error) {
+	current := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		seen := c.maxInFlight.Load()
+		if current <= seen || c.maxInFlight.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return c.DummyClient.UpsertConfigByName(ctx, a, name, payload)
+}
+
+func TestDeployConfigGraph_RespectsMaxConcurrentDeployments(t *testing.T) {
+	var configs []config.Config
+	for i := 0; i < 10; i++ {
+		configs = append(configs, config.Config{
+			Type:     config.ClassicApiType{Api: "alerting-profile"},
+			Template: testutils.GenerateDummyTemplate(t),
+			Coordinate: coordinate.Coordinate{
+				Project:  "project",
+				Type:     "alerting-profile",
+				ConfigId: fmt.Sprintf("profile-%d", i),
+			},
+			Environment: "env",
+			Parameters: testutils.ToParameterMap([]parameter.NamedParameter{
+				{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: fmt.Sprintf("profile %d", i)}},
+			}),
+		})
+	}
+
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"alerting-profile": configs},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		maxConcurrent int
+		wantMax       int32
+	}{
+		{"sequential", 1, 1},
+		{"bounded", 3, 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trackingClient := &concurrencyTrackingClient{}
+			c := dynatrace.EnvironmentClients{
+				dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: trackingClient},
+			}
+
+			err := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{MaxConcurrentDeployments: tc.maxConcurrent})
+			assert.NoError(t, err)
+			assert.Equal(t, 10, trackingClient.CreatedObjects())
+			assert.LessOrEqual(t, trackingClient.maxInFlight.Load(), tc.wantMax)
+		})
+	}
+}
+
+func TestDeploy_RespectsMaxParallelEnvironments(t *testing.T) {
+	envs := []string{"env1", "env2", "env3", "env4"}
+
+	configsPerEnv := project.ConfigsPerTypePerEnvironments{}
+	for _, env := range envs {
+		configsPerEnv[env] = project.ConfigsPerType{"alerting-profile": {
+			{
+				Type:     config.ClassicApiType{Api: "alerting-profile"},
+				Template: testutils.GenerateDummyTemplate(t),
+				Coordinate: coordinate.Coordinate{
+					Project:  "project",
+					Type:     "alerting-profile",
+					ConfigId: "profile-" + env,
+				},
+				Environment: env,
+				Parameters: testutils.ToParameterMap([]parameter.NamedParameter{
+					{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "profile " + env}},
+				}),
+			},
+		}}
+	}
+	p := []project.Project{{Id: "project", Configs: configsPerEnv}}
+
+	tests := []struct {
+		name        string
+		maxParallel int
+		wantMax     int32
+	}{
+		{"sequential", 1, 1},
+		{"unset is sequential", 0, 1},
+		{"bounded", 2, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trackingClient := &concurrencyTrackingClient{}
+			c := dynatrace.EnvironmentClients{}
+			for _, env := range envs {
+				c[dynatrace.EnvironmentInfo{Name: env}] = &client.ClientSet{DTClient: trackingClient}
+			}
+
+			err := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{MaxParallelEnvironments: tc.maxParallel})
+			assert.NoError(t, err)
+			assert.Equal(t, len(envs), trackingClient.CreatedObjects())
+			assert.LessOrEqual(t, trackingClient.maxInFlight.Load(), tc.wantMax)
+		})
+	}
+}
+
+// recordingEnvironmentHooks records the calls of its hooks per environment
+type recordingEnvironmentHooks struct {
+	mutex     sync.Mutex
+	calls     map[string][]string
+	beforeErr error
+}
+
+func (h *recordingEnvironmentHooks) Before(_ context.Context, environment string, configs []*config.Config) error {
+	h.record(environment, fmt.Sprintf("before %d configs", len(configs)))
+	return h.beforeErr
+}
+
+func (h *recordingEnvironmentHooks) After(_ context.Context, environment string, configs []*config.Config, deployErr error) error {
+	h.record(environment, fmt.Sprintf("after %d configs, failed: %t", len(configs), deployErr != nil))
+	return nil
+}
+
+func (h *recordingEnvironmentHooks) record(environment, call string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.calls == nil {
+		h.calls = map[string][]string{}
+	}
+	h.calls[environment] = append(h.calls[environment], call)
+}
+
+func TestDeploy_CallsEnvironmentHooksAroundEachEnvironment(t *testing.T) {
+	newProjects := func() []project.Project {
+		configsPerEnv := project.ConfigsPerTypePerEnvironments{}
+		for _, env := range []string{"env1", "env2"} {
+			configsPerEnv[env] = project.ConfigsPerType{"alerting-profile": {
+				{
+					Type:        config.ClassicApiType{Api: "alerting-profile"},
+					Template:    testutils.GenerateDummyTemplate(t),
+					Coordinate:  coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"},
+					Environment: env,
+					Parameters: testutils.ToParameterMap([]parameter.NamedParameter{
+						{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "profile"}},
+					}),
+				},
+			}}
+		}
+		return []project.Project{{Id: "project", Configs: configsPerEnv}}
+	}
+
+	t.Run("hooks are called before and after deploying to each environment", func(t *testing.T) {
+		env1Client, env2Client := &dtclient.DummyClient{}, &dtclient.DummyClient{}
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env1"}: &client.ClientSet{DTClient: env1Client},
+			dynatrace.EnvironmentInfo{Name: "env2"}: &client.ClientSet{DTClient: env2Client},
+		}
+
+		hooks := &recordingEnvironmentHooks{}
+		err := deploy.Deploy(context.TODO(), newProjects(), clients, deploy.DeployConfigsOptions{EnvironmentHooks: hooks})
+		require.NoError(t, err)
+		assert.Equal(t, 1, env1Client.CreatedObjects())
+		assert.Equal(t, 1, env2Client.CreatedObjects())
+		assert.Equal(t, map[string][]string{
+			"env1": {"before 1 configs", "after 1 configs, failed: false"},
+			"env2": {"before 1 configs", "after 1 configs, failed: false"},
+		}, hooks.calls)
+	})
+
+	t.Run("a failing hook before the deployment aborts it", func(t *testing.T) {
+		// the client must not be called, as nothing is deployed if the hooks fail
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env1"}: &client.ClientSet{DTClient: client.NewMockDynatraceClient(gomock.NewController(t))},
+		}
+
+		hooks := &recordingEnvironmentHooks{beforeErr: fmt.Errorf("hook failed")}
+		err := deploy.Deploy(context.TODO(), newProjects(), clients, deploy.DeployConfigsOptions{EnvironmentHooks: hooks})
+		assert.ErrorContains(t, err, "hook failed")
+		assert.Equal(t, map[string][]string{"env1": {"before 1 configs"}}, hooks.calls)
+	})
+}
+
+func TestDeploy_ResolvesReferencesToOtherEnvironments(t *testing.T) {
+	newConfig := func(env string, configId string, params ...parameter.NamedParameter) config.Config {
+		return config.Config{
+			Type:     config.ClassicApiType{Api: "alerting-profile"},
+			Template: testutils.GenerateDummyTemplate(t),
+			Coordinate: coordinate.Coordinate{
+				Project:  "project",
+				Type:     "alerting-profile",
+				ConfigId: configId,
+			},
+			Environment: env,
+			Parameters: testutils.ToParameterMap(append(params,
+				parameter.NamedParameter{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: configId + " " + env}})),
+		}
+	}
+	stagingRef := parameter.NamedParameter{Name: "stagingId", Parameter: crossenvironment.New("staging", "project", "alerting-profile", "profile", "id")}
+
+	tests := []struct {
+		name    string
+		configs project.ConfigsPerTypePerEnvironments
+		wantErr string
+	}{
+		{
+			name: "referenced environment is deployed first",
+			configs: project.ConfigsPerTypePerEnvironments{
+				"staging": project.ConfigsPerType{"alerting-profile": {newConfig("staging", "profile")}},
+				"prod":    project.ConfigsPerType{"alerting-profile": {newConfig("prod", "profile", stagingRef)}},
+			},
+		},
+		{
+			name: "cyclic references between environments",
+			configs: project.ConfigsPerTypePerEnvironments{
+				"staging": project.ConfigsPerType{"alerting-profile": {newConfig("staging", "profile",
+					parameter.NamedParameter{Name: "prodId", Parameter: crossenvironment.New("prod", "project", "alerting-profile", "profile", "id")})}},
+				"prod": project.ConfigsPerType{"alerting-profile": {newConfig("prod", "profile", stagingRef)}},
+			},
+			wantErr: "environments reference each other in a cycle: prod -> staging -> prod",
+		},
+		{
+			name: "referenced config is not deployed to the environment",
+			configs: project.ConfigsPerTypePerEnvironments{
+				"staging": project.ConfigsPerType{"alerting-profile": {newConfig("staging", "other-profile")}},
+				"prod":    project.ConfigsPerType{"alerting-profile": {newConfig("prod", "profile", stagingRef)}},
+			},
+			wantErr: "1 deployment errors occurred",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := dynatrace.EnvironmentClients{
+				dynatrace.EnvironmentInfo{Name: "staging"}: &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+				dynatrace.EnvironmentInfo{Name: "prod"}:    &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+			}
+
+			err := deploy.Deploy(context.TODO(), []project.Project{{Id: "project", Configs: tc.configs}}, c, deploy.DeployConfigsOptions{MaxParallelEnvironments: 2, ContinueOnErr: true})
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeployConfigGraph_RemoteValidation(t *testing.T) {
+	settingsConfig := config.Config{
+		Type:     config.SettingsType{SchemaId: "builtin:test"},
+		Template: testutils.GenerateDummyTemplate(t),
+		Coordinate: coordinate.Coordinate{
+			Project:  "project",
+			Type:     "builtin:test",
+			ConfigId: "setting",
+		},
+		Environment: "env",
+		Parameters: testutils.ToParameterMap([]parameter.NamedParameter{
+			{Name: config.ScopeParameter, Parameter: &parameter.DummyParameter{Value: "environment"}},
+		}),
+	}
+
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{settingsConfig}},
+			},
+		},
+	}
+
+	t.Run("dry-run validates payloads against environment", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ValidateSettings(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{DryRun: true, RemoteValidation: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("validation errors fail dry-run", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ValidateSettings(gomock.Any(), gomock.Any()).Return(fmt.Errorf("schema violation"))
+
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{DryRun: true, RemoteValidation: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("remote validation is ignored without dry-run", func(t *testing.T) {
+		c := client.NewMockDynatraceClient(gomock.NewController(t))
+		c.EXPECT().ValidateSettings(gomock.Any(), gomock.Any()).Times(0)
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return(dtclient.DynatraceEntity{Id: "id"}, nil)
+
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{RemoteValidation: true})
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeployConfigGraph_OnlyDeploysSelectedConfigsAndDependencies(t *testing.T) {
+	settingsConfig := func(configID string, refs ...coordinate.Coordinate) config.Config {
+		params := []parameter.NamedParameter{
+			{Name: config.ScopeParameter, Parameter: &parameter.DummyParameter{Value: "environment"}},
+		}
+		for i, r := range refs {
+			params = append(params, parameter.NamedParameter{Name: fmt.Sprintf("ref%d", i), Parameter: reference.New(r.Project, r.Type, r.ConfigId, "id")})
+		}
+		return config.Config{
+			Type:        config.SettingsType{SchemaId: "builtin:test"},
+			Template:    testutils.GenerateDummyTemplate(t),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: configID},
+			Environment: "env",
+			Parameters:  testutils.ToParameterMap(params),
+		}
+	}
+
+	dependency := settingsConfig("dependency")
+	selected := settingsConfig("selected", dependency.Coordinate)
+	other := settingsConfig("other")
+
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{dependency, selected, other}},
+			},
+		},
+	}
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+	}
+
+	summary := report.NewSummary()
+	planner := &plannedCounter{}
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{Only: []string{"project:builtin:test:sel*"}, Reporter: report.Multi(summary, planner)})
+	assert.NoError(t, err)
+
+	records := summary.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, dependency.Coordinate, records[0].Config)
+	assert.Equal(t, selected.Coordinate, records[1].Config)
+	assert.Equal(t, 2, planner.total, "only selected configs and their dependencies are planned")
+}
+
+// plannedCounter is a report.Planner recording the planned total
+type plannedCounter struct {
+	total int
+}
+
+func (p *plannedCounter) ReportDeployment(context.Context, coordinate.Coordinate, report.State, time.Duration, error) {
+}
+
+func (p *plannedCounter) ReportPlanned(total int) {
+	p.total += total
+}
+
+func TestDeployConfigGraph_AutomationReferencingSettings(t *testing.T) {
+	setting := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    testutils.GenerateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "setting"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+		},
+	}
+	workflow := config.Config{
+		Type:        config.AutomationType{Resource: config.Workflow},
+		Template:    template.NewInMemoryTemplate("workflow", `{"settingsId": "{{ .settingsId }}"}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: string(config.Workflow), ConfigId: "workflow"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			"settingsId": reference.New("project", "builtin:test", "setting", "id"),
+		},
+	}
+
+	dtClient := client.NewMockDynatraceClient(gomock.NewController(t))
+	dtClient.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return(dtclient.DynatraceEntity{Id: "deployed-setting-id"}, nil)
+	autClient := client.NewMockAutomationClient(gomock.NewController(t))
+	autClient.EXPECT().Upsert(gomock.Any(), gomock.Any(), gomock.Any(), []byte(`{"settingsId": "deployed-setting-id"}`)).Return(coreAutomation.Response{StatusCode: 200, Data: []byte(`{"id": "deployed-workflow-id"}`)}, nil)
+
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{
+					"builtin:test":          []config.Config{setting},
+					string(config.Workflow): []config.Config{workflow},
+				},
+			},
+		},
+	}
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: dtClient, AutClient: autClient},
+	}
+
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
+	assert.NoError(t, err)
+}
+
+// denyConfigPolicy denies the config with the given ID and records the payloads of all evaluated configs
+type denyConfigPolicy struct {
+	configID string
+	payloads map[string]string
+}
+
+func (p denyConfigPolicy) Evaluate(_ context.Context, c coordinate.Coordinate, payload []byte) ([]policy.Violation, error) {
+	p.payloads[c.ConfigId] = string(payload)
+	if c.ConfigId == p.configID {
+		return []policy.Violation{{Policy: "deny", Message: "config is denied"}}, nil
+	}
+	return nil, nil
+}
+
+// policyTestProjects returns a project of a config and a config referencing its ID, which is only known once the first
+// config is deployed
+func policyTestProjects(t *testing.T) []project.Project {
+	base := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    testutils.GenerateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "base"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+		},
+	}
+	referencing := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    template.NewInMemoryTemplate("referencing", `{"base": "{{ .base }}", "owner": "{{ .owner }}"}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "referencing"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			"base":                reference.New("project", "builtin:test", "base", "id"),
+			"owner":               &value.ValueParameter{Value: "team-a"},
+		},
+	}
+	return []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{base, referencing}},
+			},
+			Dependencies: project.DependenciesPerEnvironment{},
+		},
+	}
+}
+
+func TestDeploy_ChecksPoliciesBeforeDeploying(t *testing.T) {
+	// the client must not be called, as a config violating a policy prevents the whole deployment
+	dtClient := client.NewMockDynatraceClient(gomock.NewController(t))
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: dtClient},
+	}
+
+	evaluator := denyConfigPolicy{configID: "referencing", payloads: map[string]string{}}
+	summary := report.NewSummary()
+	err := deploy.Deploy(context.TODO(), policyTestProjects(t), clients, deploy.DeployConfigsOptions{Policy: evaluator, Reporter: summary})
+	assert.ErrorContains(t, err, "deny: config is denied")
+	assert.Empty(t, summary.Records(), "no config is deployed")
+
+	assert.Contains(t, evaluator.payloads, "base")
+	assert.JSONEq(t, `{"base": "<resolved during deployment>", "owner": "team-a"}`, evaluator.payloads["referencing"], "references are rendered as placeholder")
+}
+
+func TestDeploy_ContinueOnErrorOnlyFailsConfigsViolatingPolicies(t *testing.T) {
+	evaluator := denyConfigPolicy{configID: "base", payloads: map[string]string{}}
+	summary := report.NewSummary()
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{},
+	}
+	err := deploy.Deploy(context.TODO(), policyTestProjects(t), clients, deploy.DeployConfigsOptions{Policy: evaluator, Reporter: summary, DryRun: true})
+	assert.Error(t, err)
+
+	records := summary.Records()
+	require.Len(t, records, 2)
+	states := map[string]report.State{}
+	for _, r := range records {
+		states[r.Config.ConfigId] = r.State
+		if r.Config.ConfigId == "base" {
+			assert.Contains(t, r.Error, "deny: config is denied")
+		}
+	}
+	assert.Equal(t, map[string]report.State{"base": report.StateFailed, "referencing": report.StateSkipped}, states, "configs depending on violating configs are skipped")
+}
+
+func TestDeploy_FailsConfigsWithoutClient(t *testing.T) {
+	c := config.Config{
+		Type:        config.DashboardType,
+		Template:    testutils.GenerateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard-document", ConfigId: "dashboard"},
+		Environment: "env",
+		Parameters: testutils.ToParameterMap([]parameter.NamedParameter{
+			{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "dashboard"}},
+		}),
+	}
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"dashboard-document": []config.Config{c}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts deploy.DeployConfigsOptions
+	}{
+		{"deploy", deploy.DeployConfigsOptions{}},
+		{"deploy with rollback", deploy.DeployConfigsOptions{RollbackOnError: true}},
+		{"plan", deploy.DeployConfigsOptions{Plan: true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// the environment only defines an access token, thus no document client exists
+			clients := dynatrace.EnvironmentClients{
+				dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: &dtclient.DummyClient{}},
+			}
+
+			summary := report.NewSummary()
+			tc.opts.Reporter = summary
+			err := deploy.Deploy(context.TODO(), p, clients, tc.opts)
+			assert.Error(t, err)
+
+			records := summary.Records()
+			require.Len(t, records, 1)
+			assert.Equal(t, report.StateFailed, records[0].State)
+			assert.Contains(t, records[0].Error, `no client to deploy configs of type "document"`)
+		})
+	}
+}
+
+func clusterUserGroupProject(t *testing.T, names ...string) []project.Project {
+	var configs []config.Config
+	for _, name := range names {
+		configs = append(configs, config.Config{
+			Type:        config.ClusterType{Api: api.ClusterUserGroups},
+			Template:    template.NewInMemoryTemplate(name, `{"name": "{{.name}}"}`),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: api.ClusterUserGroups, ConfigId: name},
+			Environment: "cluster",
+			Parameters: testutils.ToParameterMap([]parameter.NamedParameter{
+				{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: name}},
+			}),
+		})
+	}
+	return []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"cluster": project.ConfigsPerType{api.ClusterUserGroups: configs},
+			},
+		},
+	}
+}
+
+func TestDeploy_SkipsUnchangedClusterObjects(t *testing.T) {
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().List(gomock.Any(), gomock.Any()).Return([]cluster.Response{
+		{ID: "group-id", Name: "group", Data: []byte(`{"id": "group-id", "name": "group"}`)},
+	}, nil).AnyTimes()
+	c.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "cluster"}: &client.ClientSet{ClusterClient: c},
+	}
+	err := deploy.Deploy(context.TODO(), clusterUserGroupProject(t, "group"), clients, deploy.DeployConfigsOptions{SkipUnchanged: true})
+	assert.NoError(t, err)
+}
+
+func TestDeploy_RollsBackClusterObjects(t *testing.T) {
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	c.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ api.ClusterAPI, data []byte) (cluster.Response, error) {
+		if string(data) == `{"name": "failing"}` {
+			return cluster.Response{}, fmt.Errorf("failed to create group")
+		}
+		return cluster.Response{ID: "created-id", Name: "created"}, nil
+	}).Times(2)
+	c.EXPECT().Delete(gomock.Any(), gomock.Any(), "created-id").Return(nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "cluster"}: &client.ClientSet{ClusterClient: c},
+	}
+	err := deploy.Deploy(context.TODO(), clusterUserGroupProject(t, "created", "failing"), clients, deploy.DeployConfigsOptions{RollbackOnError: true})
+	assert.Error(t, err)
+}
+
+func TestDeploy_StopsWhenContextIsCancelled(t *testing.T) {
+	c := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    testutils.GenerateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "setting"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+		},
+	}
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{c}},
+			},
+		},
+	}
+
+	// the client must not be called, as the deployment is cancelled before it starts
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: client.NewMockDynatraceClient(gomock.NewController(t))},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err := deploy.Deploy(ctx, p, clients, deploy.DeployConfigsOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDeployConfigGraph_FailsConfigsExceedingConfigTimeout(t *testing.T) {
+	c := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    testutils.GenerateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "setting"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+		},
+	}
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{c}},
+			},
+		},
+	}
+
+	dtClient := client.NewMockDynatraceClient(gomock.NewController(t))
+	dtClient.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+		<-ctx.Done()
+		return dtclient.DynatraceEntity{}, ctx.Err()
+	})
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: dtClient},
+	}
+
+	summary := report.NewSummary()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{ConfigTimeout: 10 * time.Millisecond, Reporter: summary})
+	assert.Error(t, err)
+
+	records := summary.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, report.StateFailed, records[0].State)
+	assert.Contains(t, records[0].Error, context.DeadlineExceeded.Error())
+}
+
+func TestDeployConfigGraph_SkipsConfigsOfDeprecatedAPIs(t *testing.T) {
+	c := config.Config{
+		Type:        config.ClassicApiType{Api: api.AlertingProfile},
+		Template:    testutils.GenerateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: api.AlertingProfile, ConfigId: "profile"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.NameParameter: &value.ValueParameter{Value: "profile"},
+		},
+	}
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{api.AlertingProfile: []config.Config{c}},
+			},
+		},
+	}
+
+	// the client must not be called, as the config is skipped
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: client.NewMockDynatraceClient(gomock.NewController(t))},
+	}
+
+	summary := report.NewSummary()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{SkipDeprecated: true, Reporter: summary})
+	assert.NoError(t, err)
+
+	records := summary.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, report.StateSkipped, records[0].State)
+	assert.Contains(t, records[0].Error, "builtin:alerting.profile")
+}
+
+func TestDeployConfigGraph_SecretScan(t *testing.T) {
+	c := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    template.NewInMemoryTemplate("setting", `{"token": "dt0c01.ST2EY72KQINMH574WMNVI7YN.G3DFPBEJYMODIDAEX454M7YWBUVEFOWKPRVMWFASS64NFH52PX6BNDVFFM572RZM"}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "setting"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+		},
+	}
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{c}},
+			},
+		},
+	}
+
+	t.Run("fail rejects payloads containing secrets", func(t *testing.T) {
+		// the client must not be called, as the config is rejected before it is deployed
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: client.NewMockDynatraceClient(gomock.NewController(t))},
+		}
+
+		summary := report.NewSummary()
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{SecretScan: secrets.LevelFail, Reporter: summary})
+		assert.Error(t, err)
+
+		records := summary.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, report.StateFailed, records[0].State)
+		assert.Contains(t, records[0].Error, `dynatrace-token at "token"`)
+	})
+
+	t.Run("warn deploys payloads containing secrets", func(t *testing.T) {
+		dtClient := client.NewMockDynatraceClient(gomock.NewController(t))
+		dtClient.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return(dtclient.DynatraceEntity{Id: "42"}, nil)
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: dtClient},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{SecretScan: secrets.LevelWarn})
+		assert.NoError(t, err)
+	})
+
+	t.Run("allowed secrets are deployed", func(t *testing.T) {
+		dtClient := client.NewMockDynatraceClient(gomock.NewController(t))
+		dtClient.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return(dtclient.DynatraceEntity{Id: "42"}, nil)
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: dtClient},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{
+			SecretScan:          secrets.LevelFail,
+			SecretScanAllowList: secrets.AllowList{Allow: []secrets.AllowEntry{{Config: "project:builtin:test:setting", Paths: []string{"token"}}}},
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeployConfigGraph_CollectsResolvedParameters(t *testing.T) {
+	t.Setenv("RESOLVED_PARAMETERS_TEST_SECRET", "my-secret-token")
+
+	c := config.Config{
+		Type:        config.SettingsType{SchemaId: "builtin:test"},
+		Template:    template.NewInMemoryTemplate("setting", `{"name": "{{ .name }}", "url": "{{ .url }}", "token": "{{ .token }}"}`),
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "builtin:test", ConfigId: "setting"},
+		Environment: "env",
+		Parameters: config.Parameters{
+			config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			"name":                &value.ValueParameter{Value: "my-setting"},
+			"token":               secretParam.New("environment", "RESOLVED_PARAMETERS_TEST_SECRET", ""),
+			"url":                 &value.ValueParameter{Value: "https://example.com?token=my-secret-token"},
+		},
+	}
+	p := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env": project.ConfigsPerType{"builtin:test": []config.Config{c}},
+			},
+		},
+	}
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: client.NewMockDynatraceClient(gomock.NewController(t))},
+	}
+
+	resolvedParameters := deploy.NewResolvedParameters()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{DryRun: true, ResolvedParameters: resolvedParameters})
+	require.NoError(t, err)
+
+	got, found := resolvedParameters.Get("env", "project:builtin:test:setting")
+	require.True(t, found)
+	assert.Equal(t, "my-setting", got["name"])
+	assert.Equal(t, "****", got["token"])
+	assert.Equal(t, "https://example.com?token=****", got["url"])
+	assert.Equal(t, "environment", got[config.ScopeParameter])
+
+	_, found = resolvedParameters.Get("other", "project:builtin:test:setting")
+	assert.False(t, found)
+}
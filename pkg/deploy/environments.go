@@ -0,0 +1,208 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/crossenvironment"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"golang.org/x/exp/maps"
+)
+
+// Results holds the entities resolved by the deployment to each environment, so that configs can reference configs
+// deployed to other environments of the same deployment. A deployment done by multiple Deploy calls, e.g. one per
+// canary stage, shares its Results between the calls, so that configs can reference configs deployed by earlier calls.
+// It also holds the cache of parameter values shared by all environments.
+type Results struct {
+	mu         sync.RWMutex
+	entities   map[string]*entities.EntityMap
+	done       map[string]chan struct{}
+	valueCache *parameter.ValueCache
+}
+
+var _ parameter.EnvironmentPropertyResolver = (*Results)(nil)
+
+// NewResults returns empty Results, to be passed to all Deploy calls of a deployment via DeployConfigsOptions.Results
+func NewResults() *Results {
+	return &Results{
+		entities:   make(map[string]*entities.EntityMap),
+		done:       make(map[string]chan struct{}),
+		valueCache: parameter.NewValueCache(),
+	}
+}
+
+// add registers the environments deployed to by a Deploy call. Results of earlier deployments to the same
+// environments are replaced.
+func (r *Results) add(environments []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, env := range environments {
+		r.entities[env] = entities.New()
+		r.done[env] = make(chan struct{})
+	}
+}
+
+// lookupFor returns the config.EntityLookup used by the deployment to the given environment
+func (r *Results) lookupFor(environment string) environmentEntities {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return environmentEntities{EntityMap: r.entities[environment], results: r}
+}
+
+// markDone marks the deployment to the given environment as finished, whether it succeeded or not
+func (r *Results) markDone(environment string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	close(r.done[environment])
+}
+
+// waitFor blocks until the deployments to all given environments, which are part of the deployment, are finished
+func (r *Results) waitFor(environments []string) {
+	for _, env := range environments {
+		r.mu.RLock()
+		done, found := r.done[env]
+		r.mu.RUnlock()
+		if found {
+			<-done
+		}
+	}
+}
+
+func (r *Results) GetResolvedPropertyOfEnvironment(environment string, c coordinate.Coordinate, propertyName string) (any, error) {
+	r.mu.RLock()
+	resolved, found := r.entities[environment]
+	done := r.done[environment]
+	r.mu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("environment %q is not part of the deployment, it must be deployed together with the environments referencing it", environment)
+	}
+
+	select {
+	case <-done:
+	default:
+		return nil, fmt.Errorf("deployment to environment %q has not finished yet", environment)
+	}
+
+	if _, found := resolved.GetResolvedEntity(c); !found {
+		return nil, fmt.Errorf("config has not been deployed to environment %q", environment)
+	}
+
+	val, found := resolved.GetResolvedProperty(c, propertyName)
+	if !found {
+		return nil, fmt.Errorf("property does not exist in environment %q", environment)
+	}
+	return val, nil
+}
+
+// environmentEntities resolves references within an environment with the entities deployed to it, and references to
+// other environments with the results of the whole deployment
+type environmentEntities struct {
+	*entities.EntityMap
+	results *Results
+}
+
+var (
+	_ config.EntityLookup                   = environmentEntities{}
+	_ parameter.EnvironmentPropertyResolver = environmentEntities{}
+	_ parameter.ValueCacheProvider          = environmentEntities{}
+)
+
+// ParameterValueCache returns the cache of parameter values shared by all environments of the deployment
+func (e environmentEntities) ParameterValueCache() *parameter.ValueCache {
+	return e.results.valueCache
+}
+
+func (e environmentEntities) GetResolvedPropertyOfEnvironment(environment string, c coordinate.Coordinate, propertyName string) (any, error) {
+	return e.results.GetResolvedPropertyOfEnvironment(environment, c, propertyName)
+}
+
+// environmentDependencies returns the environments each environment depends on, as its configs reference configs
+// deployed to them
+func environmentDependencies(projects []project.Project) map[string][]string {
+	dependencies := make(map[string]map[string]struct{})
+	for _, p := range projects {
+		for env := range p.Configs {
+			p.ForEveryConfigInEnvironmentDo(env, func(c config.Config) {
+				for _, param := range c.Parameters {
+					ref, ok := param.(*crossenvironment.CrossEnvironmentReferenceParameter)
+					if !ok {
+						continue
+					}
+					if dependencies[env] == nil {
+						dependencies[env] = make(map[string]struct{})
+					}
+					dependencies[env][ref.Environment] = struct{}{}
+				}
+			})
+		}
+	}
+
+	result := make(map[string][]string, len(dependencies))
+	for env, deps := range dependencies {
+		result[env] = maps.Keys(deps)
+		slices.Sort(result[env])
+	}
+	return result
+}
+
+// checkEnvironmentCycles returns an error if environments depend on each other, as they could not be deployed one
+// after the other
+func checkEnvironmentCycles(dependencies map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+
+	var visit func(env string, path []string) error
+	visit = func(env string, path []string) error {
+		switch state[env] {
+		case visiting:
+			cycle := append(path[slices.Index(path, env):], env)
+			return fmt.Errorf("environments reference each other in a cycle: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+
+		state[env] = visiting
+		for _, dep := range dependencies[env] {
+			if err := visit(dep, append(path, env)); err != nil {
+				return err
+			}
+		}
+		state[env] = visited
+		return nil
+	}
+
+	envs := maps.Keys(dependencies)
+	slices.Sort(envs)
+	for _, env := range envs {
+		if err := visit(env, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -33,6 +33,11 @@ import (
 	"net/http"
 )
 
+// maxConflictRetries is the number of times an upsert is retried if the bucket was modified concurrently. Updates of
+// bucket definitions use optimistic locking: the client reads the current version and sends it with the update, which
+// is rejected with HTTP 409 if the bucket was modified in between. Retrying reads the new version.
+const maxConflictRetries = 3
+
 type Client interface {
 	Upsert(ctx context.Context, bucketName string, data []byte) (buckets.Response, error)
 }
@@ -59,7 +64,7 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 
 	// create new context to carry logger
 	ctx = logr.NewContext(ctx, log.WithCtxFields(ctx).GetLogr())
-	_, err := client.Upsert(ctx, bucketName, []byte(renderedConfig))
+	err := upsert(ctx, client, bucketName, []byte(renderedConfig))
 	if err != nil {
 		var apiErr api.APIError
 		if errors.As(err, &apiErr) {
@@ -77,3 +82,19 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 		Properties: properties,
 	}, nil
 }
+
+// upsert upserts the bucket, retrying if the update is rejected due to a concurrent modification
+func upsert(ctx context.Context, client Client, bucketName string, data []byte) error {
+	for attempt := 0; ; attempt++ {
+		_, err := client.Upsert(ctx, bucketName, data)
+		if err == nil || attempt >= maxConflictRetries || !isConflict(err) {
+			return err
+		}
+		log.WithCtxFields(ctx).Debug("Bucket %q was modified concurrently, retrying update (%d/%d)", bucketName, attempt+1, maxConflictRetries)
+	}
+}
+
+func isConflict(err error) bool {
+	var apiErr api.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
@@ -169,3 +169,52 @@ func TestDeploy(t *testing.T) {
 		})
 	}
 }
+
+func TestDeploy_RetriesOnVersionConflict(t *testing.T) {
+	conflict := api.APIError{StatusCode: 409, Body: []byte("version mismatch")}
+	givenConfig := config.Config{
+		Template:   template.NewInMemoryTemplate("path/file.json", "{}"),
+		Coordinate: coordinate.Coordinate{Project: "proj", Type: "bucket", ConfigId: "my-bucket"},
+		Type:       config.BucketType{},
+		Parameters: config.Parameters{},
+	}
+
+	t.Run("succeeds once the conflict is resolved", func(t *testing.T) {
+		calls := 0
+		c := testClient{t, func(t *testing.T, bucketName string, data []byte) (buckets.Response, error) {
+			calls++
+			if calls < 3 {
+				return buckets.Response{}, conflict
+			}
+			return buckets.Response{StatusCode: 200, Data: data}, nil
+		}}
+
+		_, err := bucket.Deploy(context.Background(), c, parameter.Properties{}, "{}", &givenConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("fails if the conflict persists", func(t *testing.T) {
+		calls := 0
+		c := testClient{t, func(t *testing.T, bucketName string, data []byte) (buckets.Response, error) {
+			calls++
+			return buckets.Response{}, conflict
+		}}
+
+		_, err := bucket.Deploy(context.Background(), c, parameter.Properties{}, "{}", &givenConfig)
+		assert.Error(t, err)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		calls := 0
+		c := testClient{t, func(t *testing.T, bucketName string, data []byte) (buckets.Response, error) {
+			calls++
+			return buckets.Response{}, api.APIError{StatusCode: 400}
+		}}
+
+		_, err := bucket.Deploy(context.Background(), c, parameter.Properties{}, "{}", &givenConfig)
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/extract"
+	"github.com/google/uuid"
 	"strings"
 )
 
@@ -110,6 +111,32 @@ func upsertNonUniqueNameConfig(ctx context.Context, client client.ConfigClient,
 		}
 	}
 
+	switch conf.MatchStrategy {
+	case config.MatchByGeneratedID:
+		// never match by name, always create or update the object with the generated ID
+		return client.UpsertConfigByNonUniqueNameAndId(ctx, apiToDeploy, entityUuid, configName, []byte(renderedConfig), true)
+	case config.MatchByNameFirstMatch:
+		// update the first existing object with the same name, or create a new one with the generated ID
+		existing, err := client.ListConfigs(ctx, apiToDeploy)
+		if err != nil {
+			return dtclient.DynatraceEntity{}, fmt.Errorf("failed to list existing configs of API %q: %w", apiToDeploy.ID, err)
+		}
+		for _, v := range existing {
+			if v.Name == configName {
+				entityUuid = v.Id
+				break
+			}
+		}
+		return client.UpsertConfigByNonUniqueNameAndId(ctx, apiToDeploy, entityUuid, configName, []byte(renderedConfig), true)
+	case config.MatchAlwaysCreate:
+		// create a new object with a random ID on every deployment
+		entityUuid = uuid.NewString()
+		if apiToDeploy.ID == api.UserActionAndSessionPropertiesMobile {
+			entityUuid = strings.ToLower(strings.ReplaceAll(entityUuid, "-", ""))
+		}
+		return client.UpsertConfigByNonUniqueNameAndId(ctx, apiToDeploy, entityUuid, configName, []byte(renderedConfig), true)
+	}
+
 	// check if we are dealing with a non-unique name configuration that appears multiple times
 	// in a monaco project. if that's the case, we need to handle it differently, by setting the
 	// duplicate parameter accordingly
@@ -20,7 +20,9 @@ package classic
 
 import (
 	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
@@ -28,6 +30,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/testutils"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 	"testing"
 )
 
@@ -212,3 +215,53 @@ func TestDeployConfigShouldFailOnReferenceOnSkipConfig(t *testing.T) {
 	_, errors := Deploy(context.TODO(), client, testApiMap, nil, "", &conf)
 	assert.NotEmpty(t, errors)
 }
+
+func TestDeploy_MatchStrategy(t *testing.T) {
+	nonUniqueAPI := api.API{ID: "nun-api", URLPath: "nun-api", NonUniqueName: true}
+	apis := api.APIs{"nun-api": nonUniqueAPI}
+	generatedID := idutils.GenerateUUIDFromConfigId("project", "config-id")
+
+	newConfig := func(strategy config.MatchStrategy) *config.Config {
+		return &config.Config{
+			Type:          config.ClassicApiType{Api: "nun-api"},
+			Template:      testutils.GenerateDummyTemplate(t),
+			Coordinate:    coordinate.Coordinate{Project: "project", Type: "nun-api", ConfigId: "config-id"},
+			Environment:   "development",
+			MatchStrategy: strategy,
+		}
+	}
+
+	t.Run("by-generated-id upserts the generated ID as duplicate", func(t *testing.T) {
+		c := client.NewMockConfigClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), nonUniqueAPI, generatedID, "name", gomock.Any(), true).Return(dtclient.DynatraceEntity{Id: generatedID, Name: "name"}, nil)
+
+		_, err := Deploy(context.TODO(), c, apis, parameter.Properties{config.NameParameter: "name"}, "{}", newConfig(config.MatchByGeneratedID))
+		assert.NoError(t, err)
+	})
+
+	t.Run("by-name-first-match updates the first object with the same name", func(t *testing.T) {
+		c := client.NewMockConfigClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), nonUniqueAPI).Return([]dtclient.Value{{Id: "other", Name: "other"}, {Id: "first", Name: "name"}, {Id: "second", Name: "name"}}, nil)
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), nonUniqueAPI, "first", "name", gomock.Any(), true).Return(dtclient.DynatraceEntity{Id: "first", Name: "name"}, nil)
+
+		_, err := Deploy(context.TODO(), c, apis, parameter.Properties{config.NameParameter: "name"}, "{}", newConfig(config.MatchByNameFirstMatch))
+		assert.NoError(t, err)
+	})
+
+	t.Run("by-name-first-match falls back to the generated ID", func(t *testing.T) {
+		c := client.NewMockConfigClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), nonUniqueAPI).Return([]dtclient.Value{{Id: "other", Name: "other"}}, nil)
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), nonUniqueAPI, generatedID, "name", gomock.Any(), true).Return(dtclient.DynatraceEntity{Id: generatedID, Name: "name"}, nil)
+
+		_, err := Deploy(context.TODO(), c, apis, parameter.Properties{config.NameParameter: "name"}, "{}", newConfig(config.MatchByNameFirstMatch))
+		assert.NoError(t, err)
+	})
+
+	t.Run("always-create upserts a random ID", func(t *testing.T) {
+		c := client.NewMockConfigClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), nonUniqueAPI, gomock.Not(generatedID), "name", gomock.Any(), true).Return(dtclient.DynatraceEntity{Id: "random", Name: "name"}, nil)
+
+		_, err := Deploy(context.TODO(), c, apis, parameter.Properties{config.NameParameter: "name"}, "{}", newConfig(config.MatchAlwaysCreate))
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,32 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classic
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+)
+
+// StampOwnership adds the given ownership tags to the rendered payload of a config of the given API. Ownership tags
+// of previous deployments are replaced, all other tags are kept. If the API does not support tags, the payload is
+// returned unchanged.
+func StampOwnership(a api.API, renderedConfig string, tags []string) (string, error) {
+	if len(a.OwnershipTagsPath) == 0 {
+		return renderedConfig, nil
+	}
+	return idutils.StampOwnershipTags(renderedConfig, a.OwnershipTagsPath, tags)
+}
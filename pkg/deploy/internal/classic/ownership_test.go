@@ -0,0 +1,69 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classic
+
+import (
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStampOwnership(t *testing.T) {
+	dashboardAPI := api.API{ID: "dashboard", OwnershipTagsPath: []string{"dashboardMetadata", "tags"}}
+	tags := []string{"managed-by:monaco", "monaco-project:project"}
+
+	tests := []struct {
+		name    string
+		api     api.API
+		payload string
+		want    string
+	}{
+		{
+			name:    "tags are added",
+			api:     dashboardAPI,
+			payload: `{"dashboardMetadata": {"name": "dash"}}`,
+			want:    `{"dashboardMetadata": {"name": "dash", "tags": ["managed-by:monaco", "monaco-project:project"]}}`,
+		},
+		{
+			name:    "user tags are kept and previous ownership tags replaced",
+			api:     dashboardAPI,
+			payload: `{"dashboardMetadata": {"tags": ["team-a", "monaco-project:old", "monaco-deployed:2023-01-01T00:00:00Z"]}}`,
+			want:    `{"dashboardMetadata": {"tags": ["team-a", "managed-by:monaco", "monaco-project:project"]}}`,
+		},
+		{
+			name:    "APIs without tags are not modified",
+			api:     api.API{ID: "alerting-profile"},
+			payload: `{"name": "profile"}`,
+			want:    `{"name": "profile"}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := StampOwnership(tc.api, tc.payload, tags)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.want, got)
+		})
+	}
+
+	t.Run("invalid JSON returns an error", func(t *testing.T) {
+		_, err := StampOwnership(dashboardAPI, `{`, tags)
+		assert.Error(t, err)
+	})
+}
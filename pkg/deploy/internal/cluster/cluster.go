@@ -0,0 +1,126 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+)
+
+type Client interface {
+	List(ctx context.Context, a api.ClusterAPI) ([]cluster.Response, error)
+	Create(ctx context.Context, a api.ClusterAPI, data []byte) (cluster.Response, error)
+	Update(ctx context.Context, a api.ClusterAPI, id string, data []byte) (cluster.Response, error)
+}
+
+var _ Client = (*DummyClient)(nil)
+
+type DummyClient struct{}
+
+func (c *DummyClient) List(context.Context, api.ClusterAPI) ([]cluster.Response, error) {
+	return nil, nil
+}
+
+func (c *DummyClient) Create(_ context.Context, _ api.ClusterAPI, data []byte) (cluster.Response, error) {
+	return cluster.Response{ID: uuid.NewString(), Data: data}, nil
+}
+
+func (c *DummyClient) Update(_ context.Context, _ api.ClusterAPI, id string, data []byte) (cluster.Response, error) {
+	return cluster.Response{ID: id, Data: data}, nil
+}
+
+// Deploy creates or updates the cluster-wide configuration of the given config. Endpoints holding a single
+// configuration are updated in place, objects of all other endpoints are identified by the origin object ID of the
+// config, or by their name.
+func Deploy(ctx context.Context, client Client, properties parameter.Properties, renderedConfig string, c *config.Config) (entities.ResolvedEntity, error) {
+	// create new context to carry logger
+	ctx = logr.NewContext(ctx, log.WithCtxFields(ctx).GetLogr())
+
+	t, ok := c.Type.(config.ClusterType)
+	if !ok {
+		return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("config was not of expected type %q, but %q", config.ClusterTypeId, c.Type.ID()))
+	}
+	a, ok := api.NewClusterAPIs()[t.Api]
+	if !ok {
+		return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("unknown Cluster API %q", t.Api))
+	}
+
+	name, _ := properties[config.NameParameter].(string)
+
+	if a.SingleConfiguration {
+		if _, err := client.Update(ctx, a, "", []byte(renderedConfig)); err != nil {
+			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to update %s", a.ID)).WithError(err)
+		}
+		properties[config.IdParameter] = a.ID
+		return entities.ResolvedEntity{EntityName: name, Coordinate: c.Coordinate, Properties: properties}, nil
+	}
+
+	if name == "" {
+		return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, "missing name parameter")
+	}
+
+	existing, err := client.List(ctx, a)
+	if err != nil {
+		return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to list existing %s objects", a.ID)).WithError(err)
+	}
+
+	id := findExisting(existing, c.OriginObjectId, name)
+	if id != "" {
+		if _, err := client.Update(ctx, a, id, []byte(renderedConfig)); err != nil {
+			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to update %s %q", a.ID, id)).WithError(err)
+		}
+	} else {
+		resp, err := client.Create(ctx, a, []byte(renderedConfig))
+		if err != nil {
+			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to create %s named %q", a.ID, name)).WithError(err)
+		}
+		id = resp.ID
+	}
+
+	properties[config.IdParameter] = id
+
+	return entities.ResolvedEntity{
+		EntityName: name,
+		Coordinate: c.Coordinate,
+		Properties: properties,
+	}, nil
+}
+
+// findExisting returns the ID of the object with the given origin object ID if it exists, or else the ID of the object
+// with the given name. If neither exists, an empty string is returned.
+func findExisting(objects []cluster.Response, originObjectId string, name string) string {
+	var byName string
+	for _, o := range objects {
+		if originObjectId != "" && o.ID == originObjectId {
+			return o.ID
+		}
+		if byName == "" && o.Name == name {
+			byName = o.ID
+		}
+	}
+	return byName
+}
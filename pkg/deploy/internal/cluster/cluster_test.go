@@ -0,0 +1,116 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	clusterClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/cluster"
+)
+
+type testClient struct {
+	existing []clusterClient.Response
+	created  []string
+	updated  map[string]string
+}
+
+func (c *testClient) List(context.Context, api.ClusterAPI) ([]clusterClient.Response, error) {
+	return c.existing, nil
+}
+
+func (c *testClient) Create(_ context.Context, _ api.ClusterAPI, data []byte) (clusterClient.Response, error) {
+	c.created = append(c.created, string(data))
+	return clusterClient.Response{ID: "new-id", Data: data}, nil
+}
+
+func (c *testClient) Update(_ context.Context, _ api.ClusterAPI, id string, data []byte) (clusterClient.Response, error) {
+	if c.updated == nil {
+		c.updated = map[string]string{}
+	}
+	c.updated[id] = string(data)
+	return clusterClient.Response{ID: id, Data: data}, nil
+}
+
+func TestDeploy(t *testing.T) {
+	newConfig := func(apiID string, originObjectID string) *config.Config {
+		return &config.Config{
+			Coordinate:     coordinate.Coordinate{Project: "proj", Type: apiID, ConfigId: "cfg"},
+			Type:           config.ClusterType{Api: apiID},
+			OriginObjectId: originObjectID,
+		}
+	}
+
+	t.Run("creates objects that do not exist", func(t *testing.T) {
+		c := &testClient{existing: []clusterClient.Response{{ID: "1", Name: "other"}}}
+
+		got, err := cluster.Deploy(context.TODO(), c, parameter.Properties{config.NameParameter: "group"}, `{"name": "group"}`, newConfig(api.ClusterUserGroups, ""))
+		require.NoError(t, err)
+		assert.Equal(t, []string{`{"name": "group"}`}, c.created)
+		assert.Empty(t, c.updated)
+		assert.Equal(t, "new-id", got.Properties[config.IdParameter])
+		assert.Equal(t, "group", got.EntityName)
+	})
+
+	t.Run("updates objects with the same name", func(t *testing.T) {
+		c := &testClient{existing: []clusterClient.Response{{ID: "1", Name: "other"}, {ID: "2", Name: "group"}}}
+
+		got, err := cluster.Deploy(context.TODO(), c, parameter.Properties{config.NameParameter: "group"}, `{"name": "group"}`, newConfig(api.ClusterUserGroups, ""))
+		require.NoError(t, err)
+		assert.Empty(t, c.created)
+		assert.Equal(t, map[string]string{"2": `{"name": "group"}`}, c.updated)
+		assert.Equal(t, "2", got.Properties[config.IdParameter])
+	})
+
+	t.Run("prefers objects with the origin object ID", func(t *testing.T) {
+		c := &testClient{existing: []clusterClient.Response{{ID: "1", Name: "group"}, {ID: "2", Name: "renamed"}}}
+
+		got, err := cluster.Deploy(context.TODO(), c, parameter.Properties{config.NameParameter: "group"}, `{"name": "group"}`, newConfig(api.ClusterUserGroups, "2"))
+		require.NoError(t, err)
+		assert.Contains(t, c.updated, "2")
+		assert.Equal(t, "2", got.Properties[config.IdParameter])
+	})
+
+	t.Run("updates single configurations in place", func(t *testing.T) {
+		c := &testClient{}
+
+		got, err := cluster.Deploy(context.TODO(), c, parameter.Properties{}, `{"scheme": "https"}`, newConfig(api.ClusterProxy, ""))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"": `{"scheme": "https"}`}, c.updated)
+		assert.Equal(t, api.ClusterProxy, got.Properties[config.IdParameter])
+	})
+
+	t.Run("fails without name", func(t *testing.T) {
+		_, err := cluster.Deploy(context.TODO(), &testClient{}, parameter.Properties{}, `{}`, newConfig(api.ClusterUserGroups, ""))
+		assert.ErrorContains(t, err, "missing name parameter")
+	})
+
+	t.Run("fails for unknown APIs", func(t *testing.T) {
+		_, err := cluster.Deploy(context.TODO(), &testClient{}, parameter.Properties{config.NameParameter: "n"}, `{}`, newConfig("unknown", ""))
+		assert.ErrorContains(t, err, "unknown Cluster API")
+	})
+}
@@ -34,6 +34,11 @@ import (
 	"github.com/go-logr/logr"
 )
 
+// maxConflictRetries is the number of times an update is retried if the document was modified concurrently. Updates of
+// documents use optimistic locking: the client reads the current version of the document and sends it with the update,
+// which is rejected with HTTP 409 if another update created a new version in between. Retrying reads the new version.
+const maxConflictRetries = 3
+
 //go:generate mockgen -source=document.go -destination=document_mock_test.go -package=document_test documentClient
 type Client interface {
 	Get(ctx context.Context, id string) (documents.Response, error)
@@ -82,7 +87,7 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 
 	// strategy 1: if an origin id is available, try to update that document
 	if c.OriginObjectId != "" {
-		updateResponse, err := client.Update(ctx, c.OriginObjectId, documentName, []byte(renderedConfig), documentType)
+		updateResponse, err := update(ctx, client, c.OriginObjectId, documentName, []byte(renderedConfig), documentType)
 		if err == nil {
 			return createResolvedEntity(documentName, updateResponse.ID, c.Coordinate, properties), nil
 		}
@@ -104,9 +109,9 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 	}
 
 	if id != "" {
-		updateResponse, err := client.Update(ctx, id, documentName, []byte(renderedConfig), documentType)
+		updateResponse, err := update(ctx, client, id, documentName, []byte(renderedConfig), documentType)
 		if err != nil {
-			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to update document '%s'", c.OriginObjectId)).WithError(err)
+			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to update document '%s'", id)).WithError(err)
 		}
 
 		return createResolvedEntity(documentName, updateResponse.ID, c.Coordinate, properties), nil
@@ -121,13 +126,28 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 	return createResolvedEntity(documentName, createResponse.ID, c.Coordinate, properties), nil
 }
 
+// update updates the document, retrying if the update is rejected due to a concurrent modification
+func update(ctx context.Context, client Client, id string, name string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Update(ctx, id, name, data, documentType)
+		if err == nil || attempt >= maxConflictRetries || !isAPIErrorStatus(err, http.StatusConflict) {
+			return resp, err
+		}
+		log.WithCtxFields(ctx).Debug("Document %q was modified concurrently, retrying update (%d/%d)", id, attempt+1, maxConflictRetries)
+	}
+}
+
 func isAPIErrorStatusNotFound(err error) bool {
+	return isAPIErrorStatus(err, http.StatusNotFound)
+}
+
+func isAPIErrorStatus(err error, status int) bool {
 	var apiErr api.APIError
 	if !errors.As(err, &apiErr) {
 		return false
 	}
 
-	return apiErr.StatusCode == http.StatusNotFound
+	return apiErr.StatusCode == status
 }
 
 func tryGetDocumentIDByExternalID(ctx context.Context, client Client, externalId string) (string, error) {
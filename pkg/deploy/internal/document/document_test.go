@@ -93,6 +93,26 @@ func TestDeploy_ConfigWithOriginObjectId(t *testing.T) {
 		assert.Equal(t, originObjectId, result.Properties[config.IdParameter])
 	})
 
+	t.Run("Update by originObjectId is retried on version conflicts", func(t *testing.T) {
+		client := NewMockClient(gomock.NewController(t))
+		gomock.InOrder(
+			client.EXPECT().Update(gomock.Any(), gomock.Eq(originObjectId), gomock.Eq(documentName), gomock.Any(), gomock.Any()).Times(2).Return(documents.Response{}, api.APIError{StatusCode: http.StatusConflict}),
+			client.EXPECT().Update(gomock.Any(), gomock.Eq(originObjectId), gomock.Eq(documentName), gomock.Any(), gomock.Any()).Times(1).Return(documents.Response{ID: originObjectId}, nil),
+		)
+
+		result, err := runDeployTest(t, client, documentConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, originObjectId, result.Properties[config.IdParameter])
+	})
+
+	t.Run("Update by originObjectId fails after too many version conflicts", func(t *testing.T) {
+		client := NewMockClient(gomock.NewController(t))
+		client.EXPECT().Update(gomock.Any(), gomock.Eq(originObjectId), gomock.Eq(documentName), gomock.Any(), gomock.Any()).Times(4).Return(documents.Response{}, api.APIError{StatusCode: http.StatusConflict})
+
+		_, err := runDeployTest(t, client, documentConfig)
+		assert.Error(t, err)
+	})
+
 	t.Run("Update by originObjectId fails", func(t *testing.T) {
 		client := NewMockClient(gomock.NewController(t))
 		client.EXPECT().Update(gomock.Any(), gomock.Eq(originObjectId), gomock.Eq(documentName), gomock.Any(), gomock.Any()).Times(1).Return(documents.Response{}, errors.New("connection error"))
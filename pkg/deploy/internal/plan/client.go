@@ -0,0 +1,417 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	coreapi "github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	automationAPI "github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/automation"
+	coreAutomation "github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	clusterClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	sloClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/document"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/slo"
+)
+
+var (
+	_ client.ConfigClient   = (*DynatraceClient)(nil)
+	_ client.SettingsClient = (*DynatraceClient)(nil)
+	_ automation.Client     = (*AutomationClient)(nil)
+	_ bucket.Client         = (*BucketClient)(nil)
+	_ document.Client       = (*DocumentClient)(nil)
+	_ slo.Client            = (*SLOClient)(nil)
+	_ cluster.Client        = (*ClusterClient)(nil)
+)
+
+// DynatraceClient is used in place of the classic and settings clients when planning a deployment. Instead of creating
+// or updating objects, the existing object is read from the environment and compared to the payload. Existing objects
+// are returned with their actual ID, while objects that would be created are returned as fake entities of a
+// dtclient.DummyClient.
+type DynatraceClient struct {
+	*dtclient.DummyClient
+	remote client.DynatraceClient
+	plan   *Plan
+}
+
+// NewDynatraceClient creates a DynatraceClient reading the current state using remote and recording entries in the given Plan
+func NewDynatraceClient(remote client.DynatraceClient, p *Plan) *DynatraceClient {
+	return &DynatraceClient{
+		DummyClient: &dtclient.DummyClient{},
+		remote:      remote,
+		plan:        p,
+	}
+}
+
+func (c *DynatraceClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (dtclient.DynatraceEntity, error) {
+	exists, id, err := c.remote.ConfigExistsByName(ctx, a, name)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to check if %q config %q exists: %w", a.ID, name, err)
+	}
+
+	entity, err := c.DummyClient.UpsertConfigByName(ctx, a, name, payload)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, err
+	}
+
+	if !exists {
+		c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+		return entity, nil
+	}
+
+	if err := c.diffClassicConfig(ctx, a, id, payload); err != nil {
+		return dtclient.DynatraceEntity{}, err
+	}
+	if id != "" {
+		entity.Id = id
+	}
+	return entity, nil
+}
+
+func (c *DynatraceClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityID string, name string, payload []byte, duplicate bool) (dtclient.DynatraceEntity, error) {
+	values, err := c.remote.ListConfigs(ctx, a)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to list existing %q configs: %w", a.ID, err)
+	}
+
+	// the same rules as for the actual upsert apply: a config is updated if it either exists with the known ID, or if
+	// it is the only config of that name
+	var id string
+	var sameName []dtclient.Value
+	for _, v := range values {
+		if v.Id == entityID {
+			id = v.Id
+			break
+		}
+		if v.Name == name {
+			sameName = append(sameName, v)
+		}
+	}
+	if id == "" && len(sameName) == 1 && !duplicate {
+		id = sameName[0].Id
+	}
+
+	if id == "" {
+		c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+		return dtclient.DynatraceEntity{Id: entityID, Name: name}, nil
+	}
+
+	if err := c.diffClassicConfig(ctx, a, id, payload); err != nil {
+		return dtclient.DynatraceEntity{}, err
+	}
+	return dtclient.DynatraceEntity{Id: id, Name: name}, nil
+}
+
+func (c *DynatraceClient) diffClassicConfig(ctx context.Context, a api.API, id string, payload []byte) error {
+	actual, err := c.remote.ReadConfigById(a, id)
+	if err != nil {
+		return fmt.Errorf("failed to read existing %q config %q: %w", a.ID, id, err)
+	}
+	return addDiff(c.plan, coordinateFromContext(ctx), payload, actual)
+}
+
+// UpsertSettings compares the settings object to the existing object with the same externalId. Besides the value, the
+// scope of the object and, if opts.InsertAfter is set, its position in the ordered list of objects are compared.
+func (c *DynatraceClient) UpsertSettings(ctx context.Context, obj dtclient.SettingsObject, opts dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+	externalID, err := idutils.GenerateExternalIDForSettingsObject(obj.Coordinate)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("unable to generate external id: %w", err)
+	}
+
+	existing, err := c.remote.ListSettings(ctx, obj.SchemaId, dtclient.ListSettingsOptions{
+		Filter: func(o dtclient.DownloadSettingsObject) bool {
+			return o.ExternalId == externalID || (obj.OriginObjectId != "" && o.ObjectId == obj.OriginObjectId)
+		},
+	})
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to list existing settings of schema %q: %w", obj.SchemaId, err)
+	}
+
+	if len(existing) == 0 {
+		c.plan.Add(Entry{Coordinate: obj.Coordinate, Action: Create})
+		return c.DummyClient.UpsertSettings(ctx, obj, opts)
+	}
+
+	changes, err := Diff(obj.Content, existing[0].Value)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to compare %q to its existing state: %w", obj.Coordinate, err)
+	}
+	if obj.Scope != existing[0].Scope {
+		changes = append(changes, "scope")
+	}
+	if opts.InsertAfter != "" {
+		predecessor, err := c.predecessorOf(ctx, existing[0])
+		if err != nil {
+			return dtclient.DynatraceEntity{}, err
+		}
+		if predecessor != opts.InsertAfter {
+			changes = append(changes, "insertAfter")
+		}
+	}
+	slices.Sort(changes)
+	c.plan.Add(newEntry(obj.Coordinate, changes))
+
+	return dtclient.DynatraceEntity{Id: existing[0].ObjectId, Name: existing[0].ObjectId}, nil
+}
+
+// predecessorOf returns the object ID of the settings object placed before the given object in its scope, or
+// dtclient.InsertAfterFront if it is the first one. The objects of ordered schemas are listed in their order.
+func (c *DynatraceClient) predecessorOf(ctx context.Context, obj dtclient.DownloadSettingsObject) (string, error) {
+	objects, err := c.remote.ListSettings(ctx, obj.SchemaId, dtclient.ListSettingsOptions{
+		DiscardValue: true,
+		Filter: func(o dtclient.DownloadSettingsObject) bool {
+			return o.Scope == obj.Scope
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list existing settings of schema %q: %w", obj.SchemaId, err)
+	}
+
+	for i, o := range objects {
+		if o.ObjectId != obj.ObjectId {
+			continue
+		}
+		if i == 0 {
+			return dtclient.InsertAfterFront, nil
+		}
+		return objects[i-1].ObjectId, nil
+	}
+	return "", nil
+}
+
+// AutomationClient is used in place of the automation client when planning a deployment
+type AutomationClient struct {
+	automation.DummyClient
+	remote client.AutomationClient
+	plan   *Plan
+}
+
+// NewAutomationClient creates an AutomationClient reading the current state using remote and recording entries in the given Plan
+func NewAutomationClient(remote client.AutomationClient, p *Plan) *AutomationClient {
+	return &AutomationClient{remote: remote, plan: p}
+}
+
+func (c *AutomationClient) Upsert(ctx context.Context, resourceType automationAPI.ResourceType, id string, data []byte) (coreAutomation.Response, error) {
+	resp, err := c.remote.Get(ctx, resourceType, id)
+	if err != nil && !isNotFound(err) {
+		return coreAutomation.Response{}, fmt.Errorf("failed to read existing automation object with id %q: %w", id, err)
+	}
+
+	if err != nil {
+		c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+	} else if err := addDiff(c.plan, coordinateFromContext(ctx), data, resp.Data); err != nil {
+		return coreAutomation.Response{}, err
+	}
+	return c.DummyClient.Upsert(ctx, resourceType, id, data)
+}
+
+// BucketClient is used in place of the bucket client when planning a deployment
+type BucketClient struct {
+	bucket.DummyClient
+	remote client.BucketClient
+	plan   *Plan
+}
+
+// NewBucketClient creates a BucketClient reading the current state using remote and recording entries in the given Plan
+func NewBucketClient(remote client.BucketClient, p *Plan) *BucketClient {
+	return &BucketClient{remote: remote, plan: p}
+}
+
+func (c *BucketClient) Upsert(ctx context.Context, bucketName string, data []byte) (buckets.Response, error) {
+	resp, err := c.remote.Get(ctx, bucketName)
+	if err != nil && !isNotFound(err) {
+		return buckets.Response{}, fmt.Errorf("failed to read existing bucket %q: %w", bucketName, err)
+	}
+
+	if err != nil {
+		c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+	} else if err := addDiff(c.plan, coordinateFromContext(ctx), data, resp.Data); err != nil {
+		return buckets.Response{}, err
+	}
+	return c.DummyClient.Upsert(ctx, bucketName, data)
+}
+
+// DocumentClient is used in place of the document client when planning a deployment. Reading documents is passed on to
+// the environment, while creates and updates are only recorded in the Plan.
+type DocumentClient struct {
+	remote client.DocumentClient
+	plan   *Plan
+}
+
+// NewDocumentClient creates a DocumentClient reading the current state using remote and recording entries in the given Plan
+func NewDocumentClient(remote client.DocumentClient, p *Plan) *DocumentClient {
+	return &DocumentClient{remote: remote, plan: p}
+}
+
+func (c *DocumentClient) Get(ctx context.Context, id string) (documents.Response, error) {
+	return c.remote.Get(ctx, id)
+}
+
+func (c *DocumentClient) List(ctx context.Context, filter string) (documents.ListResponse, error) {
+	return c.remote.List(ctx, filter)
+}
+
+func (c *DocumentClient) Create(ctx context.Context, name string, externalId string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+	return documents.Response{ID: externalId, Name: name, ExternalID: externalId}, nil
+}
+
+func (c *DocumentClient) Update(ctx context.Context, id string, name string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	existing, err := c.remote.Get(ctx, id)
+	if err != nil {
+		// errors are returned as is, as a document that is not found is handled by the caller
+		return documents.Response{}, err
+	}
+
+	coord := coordinateFromContext(ctx)
+	changes, err := Diff(data, existing.Data)
+	if err != nil {
+		return documents.Response{}, fmt.Errorf("failed to compare document %q: %w", id, err)
+	}
+	if existing.Name != name {
+		changes = append([]string{"name"}, changes...)
+	}
+	c.plan.Add(newEntry(coord, changes))
+
+	return documents.Response{ID: id, Name: name, ExternalID: existing.ExternalID}, nil
+}
+
+// SLOClient is used in place of the SLO client when planning a deployment. Listing SLOs is passed on to the environment,
+// while creates and updates are only recorded in the Plan.
+type SLOClient struct {
+	slo.DummyClient
+	remote client.SLOClient
+	plan   *Plan
+}
+
+// NewSLOClient creates an SLOClient reading the current state using remote and recording entries in the given Plan
+func NewSLOClient(remote client.SLOClient, p *Plan) *SLOClient {
+	return &SLOClient{remote: remote, plan: p}
+}
+
+func (c *SLOClient) List(ctx context.Context) ([]sloClient.Response, error) {
+	return c.remote.List(ctx)
+}
+
+func (c *SLOClient) Create(ctx context.Context, data []byte) (sloClient.Response, error) {
+	c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+	return c.DummyClient.Create(ctx, data)
+}
+
+func (c *SLOClient) Update(ctx context.Context, id string, data []byte) (sloClient.Response, error) {
+	existing, err := c.remote.Get(ctx, id)
+	if err != nil {
+		return sloClient.Response{}, fmt.Errorf("failed to read existing SLO %q: %w", id, err)
+	}
+	if err := addDiff(c.plan, coordinateFromContext(ctx), data, existing.Data); err != nil {
+		return sloClient.Response{}, err
+	}
+	return c.DummyClient.Update(ctx, id, data)
+}
+
+// ClusterClient is used in place of the cluster client when planning a deployment. Listing objects is passed on to the
+// cluster, while creates and updates are only recorded in the Plan.
+type ClusterClient struct {
+	cluster.DummyClient
+	remote client.ClusterClient
+	plan   *Plan
+}
+
+// NewClusterClient creates a ClusterClient reading the current state using remote and recording entries in the given Plan
+func NewClusterClient(remote client.ClusterClient, p *Plan) *ClusterClient {
+	return &ClusterClient{remote: remote, plan: p}
+}
+
+func (c *ClusterClient) List(ctx context.Context, a api.ClusterAPI) ([]clusterClient.Response, error) {
+	return c.remote.List(ctx, a)
+}
+
+func (c *ClusterClient) Create(ctx context.Context, a api.ClusterAPI, data []byte) (clusterClient.Response, error) {
+	c.plan.Add(Entry{Coordinate: coordinateFromContext(ctx), Action: Create})
+	return c.DummyClient.Create(ctx, a, data)
+}
+
+func (c *ClusterClient) Update(ctx context.Context, a api.ClusterAPI, id string, data []byte) (clusterClient.Response, error) {
+	coord := coordinateFromContext(ctx)
+
+	// endpoints holding a single configuration can't be listed, thus their current state is not compared
+	if a.SingleConfiguration {
+		log.WithCtxFields(ctx).Warn("Current state of %s is not compared, planning it as updated", a.ID)
+		c.plan.Add(Entry{Coordinate: coord, Action: Update})
+		return c.DummyClient.Update(ctx, a, id, data)
+	}
+
+	existing, err := c.remote.List(ctx, a)
+	if err != nil {
+		return clusterClient.Response{}, fmt.Errorf("failed to read existing %s object %q: %w", a.ID, id, err)
+	}
+	for _, o := range existing {
+		if o.ID != id {
+			continue
+		}
+		if err := addDiff(c.plan, coord, data, o.Data); err != nil {
+			return clusterClient.Response{}, err
+		}
+		return c.DummyClient.Update(ctx, a, id, data)
+	}
+	return clusterClient.Response{}, fmt.Errorf("failed to read existing %s object %q: not found", a.ID, id)
+}
+
+func addDiff(p *Plan, coord coordinate.Coordinate, desired, actual []byte) error {
+	changes, err := Diff(desired, actual)
+	if err != nil {
+		return fmt.Errorf("failed to compare %q to its existing state: %w", coord, err)
+	}
+	p.Add(newEntry(coord, changes))
+	return nil
+}
+
+func newEntry(coord coordinate.Coordinate, changes []string) Entry {
+	if len(changes) == 0 {
+		return Entry{Coordinate: coord, Action: NoOp}
+	}
+	return Entry{Coordinate: coord, Action: Update, Changes: changes}
+}
+
+func isNotFound(err error) bool {
+	var apiErr coreapi.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// coordinateFromContext returns the coordinate of the config currently being deployed, which is added to the context
+// for logging purposes
+func coordinateFromContext(ctx context.Context) coordinate.Coordinate {
+	if c, ok := ctx.Value(log.CtxKeyCoord{}).(coordinate.Coordinate); ok {
+		return c
+	}
+	return coordinate.Coordinate{}
+}
@@ -0,0 +1,245 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plan_test
+
+import (
+	"context"
+	"testing"
+
+	coreapi "github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDynatraceClient_UpsertConfigByName(t *testing.T) {
+	theAPI := api.API{ID: "alerting-profile", URLPath: "/api/config/v1/alertingProfiles"}
+	coord := coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+	ctx := context.WithValue(context.TODO(), log.CtxKeyCoord{}, coord)
+
+	t.Run("config that does not exist is created", func(t *testing.T) {
+		remote := client.NewMockDynatraceClient(gomock.NewController(t))
+		remote.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(false, "", nil)
+
+		p := plan.New()
+		_, err := plan.NewDynatraceClient(remote, p).UpsertConfigByName(ctx, theAPI, "profile", []byte(`{"name": "profile"}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Create}}, p.Entries())
+	})
+
+	t.Run("existing config with changes is updated", func(t *testing.T) {
+		remote := client.NewMockDynatraceClient(gomock.NewController(t))
+		remote.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(true, "1234", nil)
+		remote.EXPECT().ReadConfigById(theAPI, "1234").Return([]byte(`{"id": "1234", "name": "profile", "rules": []}`), nil)
+
+		p := plan.New()
+		entity, err := plan.NewDynatraceClient(remote, p).UpsertConfigByName(ctx, theAPI, "profile", []byte(`{"name": "profile", "rules": [{"severity": "ERROR"}]}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "1234", entity.Id)
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Update, Changes: []string{"rules"}}}, p.Entries())
+	})
+
+	t.Run("existing config without changes is a no-op", func(t *testing.T) {
+		remote := client.NewMockDynatraceClient(gomock.NewController(t))
+		remote.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(true, "1234", nil)
+		remote.EXPECT().ReadConfigById(theAPI, "1234").Return([]byte(`{"id": "1234", "name": "profile"}`), nil)
+
+		p := plan.New()
+		_, err := plan.NewDynatraceClient(remote, p).UpsertConfigByName(ctx, theAPI, "profile", []byte(`{"name": "profile"}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.NoOp}}, p.Entries())
+		assert.Equal(t, 1, p.Count(plan.NoOp))
+	})
+}
+
+func TestDynatraceClient_UpsertSettings(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "builtin:tags.auto-tagging", ConfigId: "tag"}
+
+	remote := client.NewMockDynatraceClient(gomock.NewController(t))
+	remote.EXPECT().ListSettings(gomock.Any(), "builtin:tags.auto-tagging", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+		{ObjectId: "obj-id", Scope: "environment", Value: []byte(`{"name": "tag", "rules": [{"enabled": false}]}`)},
+	}, nil)
+
+	p := plan.New()
+	entity, err := plan.NewDynatraceClient(remote, p).UpsertSettings(context.TODO(), dtclient.SettingsObject{
+		Coordinate: coord,
+		SchemaId:   "builtin:tags.auto-tagging",
+		Scope:      "environment",
+		Content:    []byte(`{"name": "tag", "rules": [{"enabled": true}]}`),
+	}, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "obj-id", entity.Id)
+	assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Update, Changes: []string{"rules[0].enabled"}}}, p.Entries())
+}
+
+func TestDynatraceClient_UpsertSettings_ComparesScopeAndInsertAfter(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	existing := dtclient.DownloadSettingsObject{ObjectId: "obj-id", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: []byte(`{"name": "profile"}`)}
+
+	tests := []struct {
+		name        string
+		scope       string
+		insertAfter string
+		ordered     []dtclient.DownloadSettingsObject
+		want        plan.Entry
+	}{
+		{
+			name:  "unchanged scope",
+			scope: "environment",
+			want:  plan.Entry{Coordinate: coord, Action: plan.NoOp},
+		},
+		{
+			name:  "changed scope",
+			scope: "HOST-1234",
+			want:  plan.Entry{Coordinate: coord, Action: plan.Update, Changes: []string{"scope"}},
+		},
+		{
+			name:        "unchanged position",
+			scope:       "environment",
+			insertAfter: "other-id",
+			ordered:     []dtclient.DownloadSettingsObject{{ObjectId: "other-id"}, existing},
+			want:        plan.Entry{Coordinate: coord, Action: plan.NoOp},
+		},
+		{
+			name:        "unchanged position at the front",
+			scope:       "environment",
+			insertAfter: dtclient.InsertAfterFront,
+			ordered:     []dtclient.DownloadSettingsObject{existing, {ObjectId: "other-id"}},
+			want:        plan.Entry{Coordinate: coord, Action: plan.NoOp},
+		},
+		{
+			name:        "changed position",
+			scope:       "environment",
+			insertAfter: dtclient.InsertAfterFront,
+			ordered:     []dtclient.DownloadSettingsObject{{ObjectId: "other-id"}, existing},
+			want:        plan.Entry{Coordinate: coord, Action: plan.Update, Changes: []string{"insertAfter"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			remote := client.NewMockDynatraceClient(gomock.NewController(t))
+			remote.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return([]dtclient.DownloadSettingsObject{existing}, nil)
+			if tc.insertAfter != "" {
+				remote.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return(tc.ordered, nil)
+			}
+
+			p := plan.New()
+			_, err := plan.NewDynatraceClient(remote, p).UpsertSettings(context.TODO(), dtclient.SettingsObject{
+				Coordinate: coord,
+				SchemaId:   "builtin:alerting.profile",
+				Scope:      tc.scope,
+				Content:    []byte(`{"name": "profile"}`),
+			}, dtclient.UpsertSettingsOptions{InsertAfter: tc.insertAfter})
+			require.NoError(t, err)
+
+			assert.Equal(t, []plan.Entry{tc.want}, p.Entries())
+		})
+	}
+}
+
+func TestBucketClient_Upsert(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "bucket", ConfigId: "bucket"}
+	ctx := context.WithValue(context.TODO(), log.CtxKeyCoord{}, coord)
+
+	remote := client.NewMockBucketClient(gomock.NewController(t))
+	remote.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Response{}, coreapi.APIError{StatusCode: 404})
+
+	p := plan.New()
+	_, err := plan.NewBucketClient(remote, p).Upsert(ctx, "bucket", []byte(`{"displayName": "bucket"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Create}}, p.Entries())
+}
+
+// fakeClusterClient lists the given objects and fails on any modification
+type fakeClusterClient struct {
+	objects []cluster.Response
+}
+
+func (c fakeClusterClient) List(context.Context, api.ClusterAPI) ([]cluster.Response, error) {
+	return c.objects, nil
+}
+
+func (c fakeClusterClient) Create(context.Context, api.ClusterAPI, []byte) (cluster.Response, error) {
+	panic("must not be called")
+}
+
+func (c fakeClusterClient) Update(context.Context, api.ClusterAPI, string, []byte) (cluster.Response, error) {
+	panic("must not be called")
+}
+
+func (c fakeClusterClient) Delete(context.Context, api.ClusterAPI, string) error {
+	panic("must not be called")
+}
+
+func TestClusterClient(t *testing.T) {
+	coord := coordinate.Coordinate{Project: "project", Type: "cluster", ConfigId: "group"}
+	ctx := context.WithValue(context.TODO(), log.CtxKeyCoord{}, coord)
+	groups := api.NewClusterAPIs()[api.ClusterUserGroups]
+	remote := fakeClusterClient{objects: []cluster.Response{{ID: "group-id", Name: "group", Data: []byte(`{"id": "group-id", "name": "group", "isClusterAdminGroup": false}`)}}}
+
+	t.Run("create", func(t *testing.T) {
+		p := plan.New()
+		_, err := plan.NewClusterClient(remote, p).Create(ctx, groups, []byte(`{"name": "new"}`))
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Create}}, p.Entries())
+	})
+
+	t.Run("update compares to the existing object", func(t *testing.T) {
+		p := plan.New()
+		_, err := plan.NewClusterClient(remote, p).Update(ctx, groups, "group-id", []byte(`{"name": "group", "isClusterAdminGroup": true}`))
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Update, Changes: []string{"isClusterAdminGroup"}}}, p.Entries())
+	})
+
+	t.Run("unchanged object", func(t *testing.T) {
+		p := plan.New()
+		_, err := plan.NewClusterClient(remote, p).Update(ctx, groups, "group-id", []byte(`{"name": "group"}`))
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.NoOp}}, p.Entries())
+	})
+
+	t.Run("update of a single configuration is planned as update", func(t *testing.T) {
+		p := plan.New()
+		_, err := plan.NewClusterClient(remote, p).Update(ctx, api.NewClusterAPIs()[api.ClusterProxy], "", []byte(`{"host": "proxy"}`))
+		require.NoError(t, err)
+		assert.Equal(t, []plan.Entry{{Coordinate: coord, Action: plan.Update}}, p.Entries())
+	})
+}
+
+func TestPlan_String(t *testing.T) {
+	p := plan.New()
+	p.Add(plan.Entry{Coordinate: coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "b"}, Action: plan.Update, Changes: []string{"name", "value"}})
+	p.Add(plan.Entry{Coordinate: coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}, Action: plan.Create})
+	p.Add(plan.Entry{Coordinate: coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "c"}, Action: plan.NoOp})
+
+	assert.Equal(t, "+ p:t:a (create)\n~ p:t:b (update: name, value)\n= p:t:c (no-op)\n", p.String())
+}
@@ -0,0 +1,115 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+)
+
+// Diff compares the desired JSON payload to the actual JSON returned by the Dynatrace API and returns the paths of
+// all properties that differ.
+//
+// Only properties present in the desired payload are compared, as the API usually returns additional
+// properties like IDs or metadata, which are not part of a configuration. Deployment time tags stamped by
+// monaco are ignored, as they change with every deployment.
+func Diff(desired, actual []byte) ([]string, error) {
+	var d, a any
+	if err := json.Unmarshal(desired, &d); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rendered payload: %w", err)
+	}
+	if err := json.Unmarshal(actual, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remote payload: %w", err)
+	}
+
+	var changes []string
+	diffValues("", withoutDeployedAtTags(d), withoutDeployedAtTags(a), &changes)
+	slices.Sort(changes)
+	return changes, nil
+}
+
+func diffValues(path string, desired, actual any, changes *[]string) {
+	switch d := desired.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			*changes = append(*changes, rootIfEmpty(path))
+			return
+		}
+		for k, v := range d {
+			av, found := a[k]
+			if !found {
+				*changes = append(*changes, joinPath(path, k))
+				continue
+			}
+			diffValues(joinPath(path, k), v, av, changes)
+		}
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(d) {
+			*changes = append(*changes, rootIfEmpty(path))
+			return
+		}
+		for i := range d {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), d[i], a[i], changes)
+		}
+	default:
+		if !reflect.DeepEqual(desired, actual) {
+			*changes = append(*changes, rootIfEmpty(path))
+		}
+	}
+}
+
+// withoutDeployedAtTags returns the given JSON value with all deployment time tags removed from its arrays
+func withoutDeployedAtTags(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = withoutDeployedAtTags(child)
+		}
+		return v
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok && idutils.IsDeployedAtTag(s) {
+				continue
+			}
+			result = append(result, withoutDeployedAtTags(e))
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func rootIfEmpty(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
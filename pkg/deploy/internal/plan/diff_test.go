@@ -0,0 +1,99 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/plan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		actual  string
+		want    []string
+	}{
+		{
+			name:    "identical payloads",
+			desired: `{"name": "a", "enabled": true}`,
+			actual:  `{"name": "a", "enabled": true}`,
+			want:    nil,
+		},
+		{
+			name:    "additional remote properties are ignored",
+			desired: `{"name": "a"}`,
+			actual:  `{"id": "1234", "name": "a", "metadata": {"version": 1}}`,
+			want:    nil,
+		},
+		{
+			name:    "changed and missing properties",
+			desired: `{"name": "b", "enabled": true, "nested": {"value": 1}}`,
+			actual:  `{"name": "a", "nested": {"value": 2}}`,
+			want:    []string{"enabled", "name", "nested.value"},
+		},
+		{
+			name:    "arrays are compared element wise",
+			desired: `{"rules": [{"enabled": true}, {"enabled": false}]}`,
+			actual:  `{"rules": [{"id": "x", "enabled": true}, {"id": "y", "enabled": true}]}`,
+			want:    []string{"rules[1].enabled"},
+		},
+		{
+			name:    "arrays of different length",
+			desired: `{"tags": ["a", "b"]}`,
+			actual:  `{"tags": ["a"]}`,
+			want:    []string{"tags"},
+		},
+		{
+			name:    "deployment time tags are ignored",
+			desired: `{"tags": ["team-a", "managed-by:monaco", "monaco-deployed:2024-05-10T14:12:00Z"]}`,
+			actual:  `{"tags": ["team-a", "managed-by:monaco", "monaco-deployed:2024-01-01T00:00:00Z"]}`,
+			want:    nil,
+		},
+		{
+			name:    "other tags next to deployment time tags are compared",
+			desired: `{"tags": ["team-b", "monaco-deployed:2024-05-10T14:12:00Z"]}`,
+			actual:  `{"tags": ["team-a", "monaco-deployed:2024-01-01T00:00:00Z"]}`,
+			want:    []string{"tags[0]"},
+		},
+		{
+			name:    "different types",
+			desired: `{"value": {"a": 1}}`,
+			actual:  `{"value": "a"}`,
+			want:    []string{"value"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := plan.Diff([]byte(tc.desired), []byte(tc.actual))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDiff_InvalidJSON(t *testing.T) {
+	_, err := plan.Diff([]byte(`{`), []byte(`{}`))
+	assert.Error(t, err)
+
+	_, err = plan.Diff([]byte(`{}`), []byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package plan implements the 'plan' mode of a deployment. Instead of creating or updating configurations, the current
+// state of every configuration is fetched from the environment and compared to the rendered payload. The outcome is
+// collected in a Plan, listing which configurations would be created, updated, or left unchanged.
+package plan
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+)
+
+// Action describes what a deployment would do with a single configuration
+type Action string
+
+const (
+	// Create means that the configuration does not exist yet and would be created
+	Create Action = "create"
+	// Update means that the configuration exists, but differs from the rendered payload
+	Update Action = "update"
+	// NoOp means that the configuration exists and already matches the rendered payload
+	NoOp Action = "no-op"
+)
+
+func (a Action) symbol() string {
+	switch a {
+	case Create:
+		return "+"
+	case Update:
+		return "~"
+	default:
+		return "="
+	}
+}
+
+// Entry is the planned Action for one configuration
+type Entry struct {
+	Coordinate coordinate.Coordinate
+	Action     Action
+	// Changes holds the JSON paths of all properties that would be changed by an Update
+	Changes []string
+}
+
+// Plan collects the planned Entry of every configuration of an environment. It is safe for concurrent use.
+type Plan struct {
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+// New creates an empty Plan
+func New() *Plan {
+	return &Plan{}
+}
+
+// Add records the given Entry
+func (p *Plan) Add(e Entry) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.entries = append(p.entries, e)
+}
+
+// Entries returns all recorded entries, sorted by their coordinate
+func (p *Plan) Entries() []Entry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	entries := slices.Clone(p.entries)
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+	return entries
+}
+
+// Count returns how many entries with the given Action have been recorded
+func (p *Plan) Count(a Action) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	count := 0
+	for _, e := range p.entries {
+		if e.Action == a {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the plan in a human-readable form, with one line per configuration. Configurations that would be
+// created are prefixed with '+', updated ones with '~' followed by the changed properties, and unchanged ones with '='.
+func (p *Plan) String() string {
+	sb := strings.Builder{}
+	for _, e := range p.Entries() {
+		sb.WriteString(fmt.Sprintf("%s %s (%s", e.Action.symbol(), e.Coordinate, e.Action))
+		if len(e.Changes) > 0 {
+			sb.WriteString(": " + strings.Join(e.Changes, ", "))
+		}
+		sb.WriteString(")\n")
+	}
+	return sb.String()
+}
+
+// Log writes the plan and a summary of it to the log
+func (p *Plan) Log(ctx context.Context) {
+	logger := log.WithCtxFields(ctx)
+	for _, l := range strings.Split(strings.TrimSuffix(p.String(), "\n"), "\n") {
+		if l != "" {
+			logger.Info("  %s", l)
+		}
+	}
+	logger.Info("Plan: %d to create, %d to update, %d unchanged", p.Count(Create), p.Count(Update), p.Count(NoOp))
+}
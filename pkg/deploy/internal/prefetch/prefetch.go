@@ -0,0 +1,103 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package prefetch warms the caches of the client before deploying to an environment. Deploying classic configs and
+// settings looks up existing objects by listing all objects of their API or schema. The client caches these lists,
+// but configurations deployed in parallel would still list the same API concurrently before the first result is
+// cached. Prefetching lists every API and schema used by the deployment exactly once upfront, so that all lookups
+// during the deployment are served from the cache.
+package prefetch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+)
+
+// Client lists classic configs and settings objects, caching the results
+type Client interface {
+	ListConfigs(ctx context.Context, a api.API) ([]dtclient.Value, error)
+	ListSettings(ctx context.Context, schemaId string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error)
+}
+
+// Prefetch lists the objects of all classic APIs and settings schemas of the given configs in parallel. Skipped configs
+// are ignored. Errors are only logged, as the lookups are repeated during the deployment anyway.
+func Prefetch(ctx context.Context, c Client, apis api.APIs, configs []*config.Config) {
+	classicAPIs, schemas := collect(apis, configs)
+	if len(classicAPIs) == 0 && len(schemas) == 0 {
+		return
+	}
+
+	log.WithCtxFields(ctx).Info("Prefetching existing objects of %d APIs and %d settings schemas...", len(classicAPIs), len(schemas))
+
+	wg := sync.WaitGroup{}
+	for _, a := range classicAPIs {
+		wg.Add(1)
+		go func(a api.API) {
+			defer wg.Done()
+			if _, err := c.ListConfigs(ctx, a); err != nil {
+				log.WithCtxFields(ctx).WithFields(field.Error(err)).Warn("Failed to prefetch configs of API %q: %v", a.ID, err)
+			}
+		}(a)
+	}
+	for _, s := range schemas {
+		wg.Add(1)
+		go func(schemaID string) {
+			defer wg.Done()
+			if _, err := c.ListSettings(ctx, schemaID, dtclient.ListSettingsOptions{}); err != nil {
+				log.WithCtxFields(ctx).WithFields(field.Error(err)).Warn("Failed to prefetch settings of schema %q: %v", schemaID, err)
+			}
+		}(s)
+	}
+	wg.Wait()
+}
+
+// collect returns the distinct cacheable classic APIs and settings schemas of the given configs
+func collect(apis api.APIs, configs []*config.Config) ([]api.API, []string) {
+	var classicAPIs []api.API
+	var schemas []string
+	seen := make(map[string]struct{})
+
+	for _, c := range configs {
+		if c.Skip {
+			continue
+		}
+
+		switch t := c.Type.(type) {
+		case config.ClassicApiType:
+			a, ok := apis[t.Api]
+			// configs of single configuration APIs are not looked up, and configs of non-cacheable APIs are listed again anyway
+			if !ok || a.SingleConfiguration || !dtclient.CachesConfigsOf(a) {
+				continue
+			}
+			if _, dup := seen["api:"+a.ID]; !dup {
+				seen["api:"+a.ID] = struct{}{}
+				classicAPIs = append(classicAPIs, a)
+			}
+		case config.SettingsType:
+			if _, dup := seen["schema:"+t.SchemaId]; !dup {
+				seen["schema:"+t.SchemaId] = struct{}{}
+				schemas = append(schemas, t.SchemaId)
+			}
+		}
+	}
+	return classicAPIs, schemas
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prefetch_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/prefetch"
+)
+
+type recordingClient struct {
+	mu       sync.Mutex
+	apis     []string
+	schemas  []string
+	failWith error
+}
+
+func (c *recordingClient) ListConfigs(_ context.Context, a api.API) ([]dtclient.Value, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apis = append(c.apis, a.ID)
+	return nil, c.failWith
+}
+
+func (c *recordingClient) ListSettings(_ context.Context, schemaId string, _ dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemas = append(c.schemas, schemaId)
+	return nil, c.failWith
+}
+
+func TestPrefetch(t *testing.T) {
+	apis := api.APIs{
+		"alerting-profile":         {ID: "alerting-profile"},
+		"dashboard":                {ID: "dashboard", NonUniqueName: true},
+		"frequent-issue-detection": {ID: "frequent-issue-detection", SingleConfiguration: true},
+	}
+	configs := []*config.Config{
+		{Type: config.ClassicApiType{Api: "alerting-profile"}},
+		{Type: config.ClassicApiType{Api: "alerting-profile"}},
+		{Type: config.ClassicApiType{Api: "dashboard"}},
+		{Type: config.ClassicApiType{Api: "frequent-issue-detection"}},
+		{Type: config.ClassicApiType{Api: "unknown-api"}},
+		{Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}},
+		{Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}},
+		{Type: config.SettingsType{SchemaId: "builtin:skipped"}, Skip: true},
+		{Type: config.AutomationType{Resource: config.Workflow}},
+	}
+
+	t.Run("lists every cacheable API and schema once", func(t *testing.T) {
+		c := &recordingClient{}
+		prefetch.Prefetch(context.TODO(), c, apis, configs)
+
+		assert.Equal(t, []string{"alerting-profile"}, c.apis)
+		assert.Equal(t, []string{"builtin:tags.auto-tagging"}, c.schemas)
+	})
+
+	t.Run("errors are ignored", func(t *testing.T) {
+		c := &recordingClient{failWith: errors.New("failed")}
+		prefetch.Prefetch(context.TODO(), c, apis, configs)
+
+		assert.Len(t, c.apis, 1)
+		assert.Len(t, c.schemas, 1)
+	})
+
+	t.Run("nothing is listed without classic configs or settings", func(t *testing.T) {
+		c := &recordingClient{}
+		prefetch.Prefetch(context.TODO(), c, apis, []*config.Config{{Type: config.BucketType{}}})
+
+		assert.Empty(t, c.apis)
+		assert.Empty(t, c.schemas)
+	})
+}
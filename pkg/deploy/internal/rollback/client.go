@@ -0,0 +1,458 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	coreapi "github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	automationAPI "github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/automation"
+	coreAutomation "github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	clusterClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	sloClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/document"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/slo"
+)
+
+var (
+	_ client.ConfigClient   = (*DynatraceClient)(nil)
+	_ client.SettingsClient = (*DynatraceClient)(nil)
+	_ automation.Client     = (*AutomationClient)(nil)
+	_ bucket.Client         = (*BucketClient)(nil)
+	_ document.Client       = (*DocumentClient)(nil)
+	_ slo.Client            = (*SLOClient)(nil)
+	_ cluster.Client        = (*ClusterClient)(nil)
+)
+
+// DynatraceClient wraps the classic and settings client of an environment. Before an object is modified, its current
+// state is captured and recorded in the Journal, together with how to restore it.
+type DynatraceClient struct {
+	client.DynatraceClient
+	journal *Journal
+}
+
+// NewDynatraceClient creates a DynatraceClient deploying using the given client, and recording modifications in the given Journal
+func NewDynatraceClient(c client.DynatraceClient, j *Journal) *DynatraceClient {
+	return &DynatraceClient{DynatraceClient: c, journal: j}
+}
+
+func (c *DynatraceClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (dtclient.DynatraceEntity, error) {
+	exists, id, err := c.DynatraceClient.ConfigExistsByName(ctx, a, name)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to check if %q config %q exists: %w", a.ID, name, err)
+	}
+
+	var snapshot []byte
+	if exists {
+		if snapshot, err = c.snapshotClassicConfig(a, id); err != nil {
+			return dtclient.DynatraceEntity{}, err
+		}
+	}
+
+	entity, err := c.DynatraceClient.UpsertConfigByName(ctx, a, name, payload)
+	if err != nil {
+		return entity, err
+	}
+
+	c.recordClassicConfig(ctx, a, entity, exists, snapshot)
+	return entity, nil
+}
+
+func (c *DynatraceClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityID string, name string, payload []byte, duplicate bool) (dtclient.DynatraceEntity, error) {
+	values, err := c.DynatraceClient.ListConfigs(ctx, a)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to list existing %q configs: %w", a.ID, err)
+	}
+
+	// as the upsert may update a config of the same name instead of the one with the given ID, all potential targets
+	// of the upsert are captured
+	snapshots := make(map[string][]byte)
+	for _, v := range values {
+		if v.Id != entityID && v.Name != name {
+			continue
+		}
+		if snapshots[v.Id], err = c.snapshotClassicConfig(a, v.Id); err != nil {
+			return dtclient.DynatraceEntity{}, err
+		}
+	}
+
+	entity, err := c.DynatraceClient.UpsertConfigByNonUniqueNameAndId(ctx, a, entityID, name, payload, duplicate)
+	if err != nil {
+		return entity, err
+	}
+
+	snapshot, exists := snapshots[entity.Id]
+	c.recordClassicConfig(ctx, a, entity, exists, snapshot)
+	return entity, nil
+}
+
+func (c *DynatraceClient) snapshotClassicConfig(a api.API, id string) ([]byte, error) {
+	snapshot, err := c.DynatraceClient.ReadConfigById(a, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture state of %q config %q before deployment: %w", a.ID, id, err)
+	}
+	return removeProperties(snapshot, "id", "metadata")
+}
+
+func (c *DynatraceClient) recordClassicConfig(ctx context.Context, a api.API, entity dtclient.DynatraceEntity, existed bool, snapshot []byte) {
+	coord := coordinateFromContext(ctx)
+	if !existed {
+		c.journal.record(coord, fmt.Sprintf("deleting created %q config %q", a.ID, entity.Id), func(context.Context) error {
+			return c.DynatraceClient.DeleteConfigById(a, entity.Id)
+		})
+		return
+	}
+
+	c.journal.record(coord, fmt.Sprintf("restoring %q config %q", a.ID, entity.Id), func(ctx context.Context) error {
+		if a.SingleConfiguration {
+			_, err := c.DynatraceClient.UpsertConfigByName(ctx, a, entity.Name, snapshot)
+			return err
+		}
+		_, err := c.DynatraceClient.UpsertConfigByNonUniqueNameAndId(ctx, a, entity.Id, entity.Name, snapshot, false)
+		return err
+	})
+}
+
+func (c *DynatraceClient) UpsertSettings(ctx context.Context, obj dtclient.SettingsObject, opts dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+	externalID, err := idutils.GenerateExternalIDForSettingsObject(obj.Coordinate)
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("unable to generate external id: %w", err)
+	}
+
+	existing, err := c.DynatraceClient.ListSettings(ctx, obj.SchemaId, dtclient.ListSettingsOptions{
+		Filter: func(o dtclient.DownloadSettingsObject) bool {
+			return o.ExternalId == externalID || (obj.OriginObjectId != "" && o.ObjectId == obj.OriginObjectId)
+		},
+	})
+	if err != nil {
+		return dtclient.DynatraceEntity{}, fmt.Errorf("failed to capture state of settings object before deployment: %w", err)
+	}
+
+	entity, err := c.DynatraceClient.UpsertSettings(ctx, obj, opts)
+	if err != nil {
+		return entity, err
+	}
+
+	for _, e := range existing {
+		if e.ObjectId != entity.Id {
+			continue
+		}
+
+		restored := obj
+		restored.Content = e.Value
+		restored.Scope = e.Scope
+		restored.OriginObjectId = e.ObjectId
+		c.journal.record(obj.Coordinate, fmt.Sprintf("restoring settings object %q", e.ObjectId), func(ctx context.Context) error {
+			_, err := c.DynatraceClient.UpsertSettings(ctx, restored, dtclient.UpsertSettingsOptions{})
+			return err
+		})
+		return entity, nil
+	}
+
+	c.journal.record(obj.Coordinate, fmt.Sprintf("deleting created settings object %q", entity.Id), func(context.Context) error {
+		return c.DynatraceClient.DeleteSettings(entity.Id)
+	})
+	return entity, nil
+}
+
+// AutomationClient wraps the automation client of an environment, recording modifications in a Journal
+type AutomationClient struct {
+	client  client.AutomationClient
+	journal *Journal
+}
+
+// NewAutomationClient creates an AutomationClient deploying using the given client, and recording modifications in the given Journal
+func NewAutomationClient(c client.AutomationClient, j *Journal) *AutomationClient {
+	return &AutomationClient{client: c, journal: j}
+}
+
+func (c *AutomationClient) Upsert(ctx context.Context, resourceType automationAPI.ResourceType, id string, data []byte) (coreAutomation.Response, error) {
+	existing, err := c.client.Get(ctx, resourceType, id)
+	existed := err == nil
+	if err != nil && !isNotFound(err) {
+		return coreAutomation.Response{}, fmt.Errorf("failed to capture state of automation object %q before deployment: %w", id, err)
+	}
+
+	var snapshot []byte
+	if existed {
+		if snapshot, err = removeProperties(existing.Data, "id", "modificationInfo", "lastExecution"); err != nil {
+			return coreAutomation.Response{}, err
+		}
+	}
+
+	resp, err := c.client.Upsert(ctx, resourceType, id, data)
+	if err != nil {
+		return resp, err
+	}
+
+	coord := coordinateFromContext(ctx)
+	if existed {
+		c.journal.record(coord, fmt.Sprintf("restoring automation object %q", id), func(ctx context.Context) error {
+			_, err := c.client.Update(ctx, resourceType, id, snapshot)
+			return err
+		})
+	} else {
+		c.journal.record(coord, fmt.Sprintf("deleting created automation object %q", id), func(ctx context.Context) error {
+			_, err := c.client.Delete(ctx, resourceType, id)
+			return err
+		})
+	}
+	return resp, nil
+}
+
+// BucketClient wraps the bucket client of an environment, recording modifications in a Journal
+type BucketClient struct {
+	client  client.BucketClient
+	journal *Journal
+}
+
+// NewBucketClient creates a BucketClient deploying using the given client, and recording modifications in the given Journal
+func NewBucketClient(c client.BucketClient, j *Journal) *BucketClient {
+	return &BucketClient{client: c, journal: j}
+}
+
+func (c *BucketClient) Upsert(ctx context.Context, bucketName string, data []byte) (buckets.Response, error) {
+	existing, err := c.client.Get(ctx, bucketName)
+	existed := err == nil
+	if err != nil && !isNotFound(err) {
+		return buckets.Response{}, fmt.Errorf("failed to capture state of bucket %q before deployment: %w", bucketName, err)
+	}
+
+	resp, err := c.client.Upsert(ctx, bucketName, data)
+	if err != nil {
+		return resp, err
+	}
+
+	coord := coordinateFromContext(ctx)
+	if existed {
+		c.journal.record(coord, fmt.Sprintf("restoring bucket %q", bucketName), func(ctx context.Context) error {
+			_, err := c.client.Update(ctx, bucketName, existing.Data)
+			return err
+		})
+	} else {
+		c.journal.record(coord, fmt.Sprintf("deleting created bucket %q", bucketName), func(ctx context.Context) error {
+			_, err := c.client.Delete(ctx, bucketName)
+			return err
+		})
+	}
+	return resp, nil
+}
+
+// DocumentClient wraps the document client of an environment, recording modifications in a Journal
+type DocumentClient struct {
+	client  client.DocumentClient
+	journal *Journal
+}
+
+// NewDocumentClient creates a DocumentClient deploying using the given client, and recording modifications in the given Journal
+func NewDocumentClient(c client.DocumentClient, j *Journal) *DocumentClient {
+	return &DocumentClient{client: c, journal: j}
+}
+
+func (c *DocumentClient) Get(ctx context.Context, id string) (documents.Response, error) {
+	return c.client.Get(ctx, id)
+}
+
+func (c *DocumentClient) List(ctx context.Context, filter string) (documents.ListResponse, error) {
+	return c.client.List(ctx, filter)
+}
+
+func (c *DocumentClient) Create(ctx context.Context, name string, externalId string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	resp, err := c.client.Create(ctx, name, externalId, data, documentType)
+	if err != nil {
+		return resp, err
+	}
+
+	c.journal.record(coordinateFromContext(ctx), fmt.Sprintf("deleting created document %q", resp.ID), func(ctx context.Context) error {
+		_, err := c.client.Delete(ctx, resp.ID)
+		return err
+	})
+	return resp, nil
+}
+
+func (c *DocumentClient) Update(ctx context.Context, id string, name string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	existing, err := c.client.Get(ctx, id)
+	if err != nil {
+		// errors are returned as is, as a document that is not found is handled by the caller
+		return documents.Response{}, err
+	}
+
+	resp, err := c.client.Update(ctx, id, name, data, documentType)
+	if err != nil {
+		return resp, err
+	}
+
+	c.journal.record(coordinateFromContext(ctx), fmt.Sprintf("restoring document %q", id), func(ctx context.Context) error {
+		_, err := c.client.Update(ctx, id, existing.Name, existing.Data, documents.DocumentType(existing.Type))
+		return err
+	})
+	return resp, nil
+}
+
+// SLOClient wraps the SLO client of an environment, recording modifications in a Journal
+type SLOClient struct {
+	client  client.SLOClient
+	journal *Journal
+}
+
+// NewSLOClient creates an SLOClient deploying using the given client, and recording modifications in the given Journal
+func NewSLOClient(c client.SLOClient, j *Journal) *SLOClient {
+	return &SLOClient{client: c, journal: j}
+}
+
+func (c *SLOClient) List(ctx context.Context) ([]sloClient.Response, error) {
+	return c.client.List(ctx)
+}
+
+func (c *SLOClient) Create(ctx context.Context, data []byte) (sloClient.Response, error) {
+	resp, err := c.client.Create(ctx, data)
+	if err != nil {
+		return resp, err
+	}
+
+	c.journal.record(coordinateFromContext(ctx), fmt.Sprintf("deleting created SLO %q", resp.ID), func(ctx context.Context) error {
+		return c.client.Delete(ctx, resp.ID)
+	})
+	return resp, nil
+}
+
+func (c *SLOClient) Update(ctx context.Context, id string, data []byte) (sloClient.Response, error) {
+	existing, err := c.client.Get(ctx, id)
+	if err != nil {
+		return sloClient.Response{}, fmt.Errorf("failed to capture state of SLO %q before deployment: %w", id, err)
+	}
+	snapshot, err := removeProperties(existing.Data, "id", "version")
+	if err != nil {
+		return sloClient.Response{}, err
+	}
+
+	resp, err := c.client.Update(ctx, id, data)
+	if err != nil {
+		return resp, err
+	}
+
+	c.journal.record(coordinateFromContext(ctx), fmt.Sprintf("restoring SLO %q", id), func(ctx context.Context) error {
+		_, err := c.client.Update(ctx, id, snapshot)
+		return err
+	})
+	return resp, nil
+}
+
+// ClusterClient wraps the client of a Managed cluster, recording modifications in a Journal
+type ClusterClient struct {
+	client  client.ClusterClient
+	journal *Journal
+}
+
+// NewClusterClient creates a ClusterClient deploying using the given client, and recording modifications in the given Journal
+func NewClusterClient(c client.ClusterClient, j *Journal) *ClusterClient {
+	return &ClusterClient{client: c, journal: j}
+}
+
+func (c *ClusterClient) List(ctx context.Context, a api.ClusterAPI) ([]clusterClient.Response, error) {
+	return c.client.List(ctx, a)
+}
+
+func (c *ClusterClient) Create(ctx context.Context, a api.ClusterAPI, data []byte) (clusterClient.Response, error) {
+	resp, err := c.client.Create(ctx, a, data)
+	if err != nil {
+		return resp, err
+	}
+
+	c.journal.record(coordinateFromContext(ctx), fmt.Sprintf("deleting created %s %q", a.ID, resp.ID), func(ctx context.Context) error {
+		return c.client.Delete(ctx, a, resp.ID)
+	})
+	return resp, nil
+}
+
+func (c *ClusterClient) Update(ctx context.Context, a api.ClusterAPI, id string, data []byte) (clusterClient.Response, error) {
+	// endpoints holding a single configuration can't be listed, thus their state can't be captured to be restored
+	if a.SingleConfiguration {
+		return clusterClient.Response{}, fmt.Errorf("failed to capture state of %s before deployment: single configurations of the cluster can't be rolled back", a.ID)
+	}
+
+	snapshot, err := c.snapshot(ctx, a, id)
+	if err != nil {
+		return clusterClient.Response{}, err
+	}
+
+	resp, err := c.client.Update(ctx, a, id, data)
+	if err != nil {
+		return resp, err
+	}
+
+	c.journal.record(coordinateFromContext(ctx), fmt.Sprintf("restoring %s %q", a.ID, id), func(ctx context.Context) error {
+		_, err := c.client.Update(ctx, a, id, snapshot)
+		return err
+	})
+	return resp, nil
+}
+
+func (c *ClusterClient) snapshot(ctx context.Context, a api.ClusterAPI, id string) ([]byte, error) {
+	existing, err := c.client.List(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture state of %s %q before deployment: %w", a.ID, id, err)
+	}
+	for _, o := range existing {
+		if o.ID == id {
+			return removeProperties(o.Data, "id")
+		}
+	}
+	return nil, fmt.Errorf("failed to capture state of %s %q before deployment: not found", a.ID, id)
+}
+
+// removeProperties removes the given top-level properties from a JSON object. This is used to strip read-only
+// properties returned by the Dynatrace APIs, which are not accepted when the object is restored.
+func removeProperties(data []byte, properties ...string) ([]byte, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal captured state: %w", err)
+	}
+	for _, p := range properties {
+		delete(obj, p)
+	}
+	return json.Marshal(obj)
+}
+
+func isNotFound(err error) bool {
+	var apiErr coreapi.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// coordinateFromContext returns the coordinate of the config currently being deployed, which is added to the context
+// for logging purposes
+func coordinateFromContext(ctx context.Context) coordinate.Coordinate {
+	if c, ok := ctx.Value(log.CtxKeyCoord{}).(coordinate.Coordinate); ok {
+		return c
+	}
+	return coordinate.Coordinate{}
+}
@@ -0,0 +1,84 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rollback implements the transactional mode of a deployment. While deploying, the state of every object is
+// captured before it is modified. If the deployment fails, all modifications are reverted: updated objects are restored
+// to their captured state, and newly created objects are deleted.
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+)
+
+// step is a single modification of the environment, and how to revert it
+type step struct {
+	coordinate  coordinate.Coordinate
+	description string
+	undo        func(ctx context.Context) error
+}
+
+// Journal records all modifications done during the deployment to one environment. It is safe for concurrent use.
+type Journal struct {
+	mutex sync.Mutex
+	steps []step
+}
+
+// NewJournal creates an empty Journal
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+func (j *Journal) record(coord coordinate.Coordinate, description string, undo func(ctx context.Context) error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.steps = append(j.steps, step{coordinate: coord, description: description, undo: undo})
+}
+
+// Len returns the number of recorded modifications
+func (j *Journal) Len() int {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return len(j.steps)
+}
+
+// Rollback reverts all recorded modifications in the reverse order of their deployment, so that objects referencing
+// others are reverted before the objects they reference. Failing to revert one modification does not stop the rollback
+// of the remaining ones, all errors are returned joined together.
+func (j *Journal) Rollback(ctx context.Context) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var errs []error
+	for i := len(j.steps) - 1; i >= 0; i-- {
+		s := j.steps[i]
+		logger := log.WithCtxFields(ctx).WithFields(field.Coordinate(s.coordinate))
+		logger.Info("Rolling back %s: %s", s.coordinate, s.description)
+		if err := s.undo(ctx); err != nil {
+			logger.WithFields(field.Error(err)).Error("Failed to roll back %s: %v", s.coordinate, err)
+			errs = append(errs, fmt.Errorf("failed to roll back %s: %w", s.coordinate, err))
+		}
+	}
+	j.steps = nil
+
+	return errors.Join(errs...)
+}
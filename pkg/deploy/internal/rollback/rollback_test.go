@@ -0,0 +1,154 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollback_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	coreapi "github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/rollback"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRollback_DeletesCreatedConfig(t *testing.T) {
+	theAPI := api.API{ID: "alerting-profile", URLPath: "/api/config/v1/alertingProfiles"}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(false, "", nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), theAPI, "profile", gomock.Any()).Return(dtclient.DynatraceEntity{Id: "new-id", Name: "profile"}, nil)
+
+	j := rollback.NewJournal()
+	_, err := rollback.NewDynatraceClient(c, j).UpsertConfigByName(context.TODO(), theAPI, "profile", []byte(`{"name": "profile"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, j.Len())
+
+	c.EXPECT().DeleteConfigById(theAPI, "new-id").Return(nil)
+	assert.NoError(t, j.Rollback(context.TODO()))
+	assert.Equal(t, 0, j.Len())
+}
+
+func TestRollback_RestoresUpdatedConfig(t *testing.T) {
+	theAPI := api.API{ID: "alerting-profile", URLPath: "/api/config/v1/alertingProfiles"}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(true, "1234", nil)
+	c.EXPECT().ReadConfigById(theAPI, "1234").Return([]byte(`{"id": "1234", "metadata": {}, "name": "profile", "rules": []}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), theAPI, "profile", gomock.Any()).Return(dtclient.DynatraceEntity{Id: "1234", Name: "profile"}, nil)
+
+	j := rollback.NewJournal()
+	_, err := rollback.NewDynatraceClient(c, j).UpsertConfigByName(context.TODO(), theAPI, "profile", []byte(`{"name": "profile", "rules": [{}]}`))
+	require.NoError(t, err)
+
+	c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), theAPI, "1234", "profile", []byte(`{"name":"profile","rules":[]}`), false).Return(dtclient.DynatraceEntity{}, nil)
+	assert.NoError(t, j.Rollback(context.TODO()))
+}
+
+func TestRollback_RestoresUpdatedSettings(t *testing.T) {
+	obj := dtclient.SettingsObject{Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:tags.auto-tagging", ConfigId: "tag"}, SchemaId: "builtin:tags.auto-tagging", Scope: "environment", Content: []byte(`{"name": "new"}`)}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:tags.auto-tagging", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+		{ObjectId: "obj-id", Scope: "environment", Value: []byte(`{"name": "old"}`)},
+	}, nil)
+	c.EXPECT().UpsertSettings(gomock.Any(), obj, gomock.Any()).Return(dtclient.DynatraceEntity{Id: "obj-id"}, nil)
+
+	j := rollback.NewJournal()
+	_, err := rollback.NewDynatraceClient(c, j).UpsertSettings(context.TODO(), obj, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+
+	restored := obj
+	restored.Content = []byte(`{"name": "old"}`)
+	restored.OriginObjectId = "obj-id"
+	c.EXPECT().UpsertSettings(gomock.Any(), restored, gomock.Any()).Return(dtclient.DynatraceEntity{Id: "obj-id"}, nil)
+	assert.NoError(t, j.Rollback(context.TODO()))
+}
+
+func TestRollback_RevertsInReverseOrderAndCollectsErrors(t *testing.T) {
+	c := client.NewMockBucketClient(gomock.NewController(t))
+	c.EXPECT().Get(gomock.Any(), gomock.Any()).Return(buckets.Response{}, coreapi.APIError{StatusCode: 404}).Times(2)
+	c.EXPECT().Upsert(gomock.Any(), gomock.Any(), gomock.Any()).Return(buckets.Response{}, nil).Times(2)
+
+	j := rollback.NewJournal()
+	bucketClient := rollback.NewBucketClient(c, j)
+	_, err := bucketClient.Upsert(context.TODO(), "first", []byte(`{}`))
+	require.NoError(t, err)
+	_, err = bucketClient.Upsert(context.TODO(), "second", []byte(`{}`))
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		c.EXPECT().Delete(gomock.Any(), "second").Return(buckets.Response{}, errors.New("failed")),
+		c.EXPECT().Delete(gomock.Any(), "first").Return(buckets.Response{}, nil),
+	)
+	assert.Error(t, j.Rollback(context.TODO()))
+}
+
+func TestRollback_DeletesCreatedClusterObject(t *testing.T) {
+	theAPI := api.NewClusterAPIs()[api.ClusterUserGroups]
+
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().Create(gomock.Any(), theAPI, gomock.Any()).Return(cluster.Response{ID: "new-id", Name: "group"}, nil)
+
+	j := rollback.NewJournal()
+	_, err := rollback.NewClusterClient(c, j).Create(context.TODO(), theAPI, []byte(`{"name": "group"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, j.Len())
+
+	c.EXPECT().Delete(gomock.Any(), theAPI, "new-id").Return(nil)
+	assert.NoError(t, j.Rollback(context.TODO()))
+}
+
+func TestRollback_RestoresUpdatedClusterObject(t *testing.T) {
+	theAPI := api.NewClusterAPIs()[api.ClusterUserGroups]
+
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().List(gomock.Any(), theAPI).Return([]cluster.Response{
+		{ID: "other-id", Name: "other", Data: []byte(`{"id": "other-id", "name": "other"}`)},
+		{ID: "1234", Name: "group", Data: []byte(`{"id": "1234", "name": "group", "ldapGroupNames": []}`)},
+	}, nil)
+	c.EXPECT().Update(gomock.Any(), theAPI, "1234", []byte(`{"name": "group", "ldapGroupNames": ["admins"]}`)).Return(cluster.Response{ID: "1234"}, nil)
+
+	j := rollback.NewJournal()
+	_, err := rollback.NewClusterClient(c, j).Update(context.TODO(), theAPI, "1234", []byte(`{"name": "group", "ldapGroupNames": ["admins"]}`))
+	require.NoError(t, err)
+
+	c.EXPECT().Update(gomock.Any(), theAPI, "1234", []byte(`{"ldapGroupNames":[],"name":"group"}`)).Return(cluster.Response{ID: "1234"}, nil)
+	assert.NoError(t, j.Rollback(context.TODO()))
+}
+
+func TestRollback_FailsUpdateOfSingleClusterConfiguration(t *testing.T) {
+	theAPI := api.NewClusterAPIs()[api.ClusterProxy]
+
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	j := rollback.NewJournal()
+	_, err := rollback.NewClusterClient(c, j).Update(context.TODO(), theAPI, "", []byte(`{"scheme": "http"}`))
+	assert.ErrorContains(t, err, "can't be rolled back")
+	assert.Equal(t, 0, j.Len())
+}
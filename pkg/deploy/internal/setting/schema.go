@@ -0,0 +1,99 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package setting
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+)
+
+// SchemaVersionError is returned if the schema version declared by a config can not be deployed to an environment, as
+// the environment provides a different version of the schema
+type SchemaVersionError struct {
+	SchemaID string
+	// Declared is the schema version declared by the config
+	Declared string
+	// Remote is the version of the schema on the environment
+	Remote string
+}
+
+func (e SchemaVersionError) Error() string {
+	declared, _ := version.ParseVersion(e.Declared)
+	remote, _ := version.ParseVersion(e.Remote)
+	if declared.GreaterThan(remote) {
+		return fmt.Sprintf("config declares version %s of schema %q, but the environment only provides version %s - update the environment or declare version %s", e.Declared, e.SchemaID, e.Remote, e.Remote)
+	}
+	return fmt.Sprintf("config declares version %s of schema %q, but the environment provides the incompatible version %s - update the config to version %s, or use --accept-newer-schema to deploy it against version %s", e.Declared, e.SchemaID, e.Remote, e.Remote, e.Remote)
+}
+
+// CheckSchemaVersion compares the schema version declared by the given settings config with the version of the schema on
+// the environment. Newer versions with the same major version are compatible and the config is deployed as is. If
+// acceptNewer is set, the config is upgraded to any newer version of the schema instead. A SchemaVersionError is
+// returned for an incompatible newer version or if the environment only provides an older version of the schema.
+//
+// The returned config is the config to deploy - a copy declaring the newer schema version if it was upgraded, or the
+// given config otherwise. The check is skipped if the config declares no version or the remote version is unknown, e.g.
+// as the schema can't be read with the token used for the deployment.
+func CheckSchemaVersion(ctx context.Context, c client.SettingsClient, conf *config.Config, acceptNewer bool) (*config.Config, error) {
+	t, ok := conf.Type.(config.SettingsType)
+	if !ok || t.SchemaVersion == "" {
+		return conf, nil
+	}
+
+	schema, err := c.GetSchemaById(t.SchemaId)
+	if err != nil {
+		log.WithCtxFields(ctx).Warn("Failed to get schema %q to check its version: %v", t.SchemaId, err)
+		return conf, nil
+	}
+	if schema.Version == "" || schema.Version == t.SchemaVersion {
+		return conf, nil
+	}
+
+	declared, err := version.ParseVersion(t.SchemaVersion)
+	if err != nil {
+		log.WithCtxFields(ctx).Debug("Not checking schema version %q of schema %q: %v", t.SchemaVersion, t.SchemaId, err)
+		return conf, nil
+	}
+	remote, err := version.ParseVersion(schema.Version)
+	if err != nil {
+		log.WithCtxFields(ctx).Debug("Not checking schema version %q of schema %q: %v", schema.Version, t.SchemaId, err)
+		return conf, nil
+	}
+
+	versionErr := SchemaVersionError{SchemaID: t.SchemaId, Declared: t.SchemaVersion, Remote: schema.Version}
+	switch {
+	case declared == remote:
+		return conf, nil
+	case declared.GreaterThan(remote):
+		return nil, versionErr
+	case acceptNewer:
+		log.WithCtxFields(ctx).Warn("Config declares version %s of schema %q, deploying it against the newer version %s of the environment", t.SchemaVersion, t.SchemaId, schema.Version)
+		upgraded := *conf
+		t.SchemaVersion = schema.Version
+		upgraded.Type = t
+		return &upgraded, nil
+	case declared.Major != remote.Major:
+		return nil, versionErr
+	default:
+		log.WithCtxFields(ctx).Debug("Config declares version %s of schema %q, the environment provides the compatible version %s", t.SchemaVersion, t.SchemaId, schema.Version)
+		return conf, nil
+	}
+}
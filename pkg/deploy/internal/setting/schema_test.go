@@ -0,0 +1,127 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package setting
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestCheckSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		declared      string
+		remote        string
+		acceptNewer   bool
+		wantVersion   string
+		wantErrSubstr string
+	}{
+		{
+			name:        "same version",
+			declared:    "1.2",
+			remote:      "1.2",
+			wantVersion: "1.2",
+		},
+		{
+			name:        "unknown remote version",
+			declared:    "1.2",
+			remote:      "",
+			wantVersion: "1.2",
+		},
+		{
+			name:        "newer compatible version",
+			declared:    "1.2",
+			remote:      "1.5",
+			wantVersion: "1.2",
+		},
+		{
+			name:        "newer compatible version is accepted",
+			declared:    "1.2",
+			remote:      "1.5",
+			acceptNewer: true,
+			wantVersion: "1.5",
+		},
+		{
+			name:          "newer incompatible version",
+			declared:      "1.2",
+			remote:        "2.0.1",
+			wantErrSubstr: "incompatible version 2.0.1 - update the config to version 2.0.1, or use --accept-newer-schema",
+		},
+		{
+			name:        "newer incompatible version is accepted",
+			declared:    "1.2",
+			remote:      "2.0.1",
+			acceptNewer: true,
+			wantVersion: "2.0.1",
+		},
+		{
+			name:          "older version",
+			declared:      "1.5",
+			remote:        "1.2",
+			acceptNewer:   true,
+			wantErrSubstr: "the environment only provides version 1.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := client.NewMockSettingsClient(gomock.NewController(t))
+			c.EXPECT().GetSchemaById("builtin:alerting.profile").Return(dtclient.Schema{SchemaId: "builtin:alerting.profile", Version: tt.remote}, nil)
+
+			conf := &config.Config{Type: config.SettingsType{SchemaId: "builtin:alerting.profile", SchemaVersion: tt.declared}}
+			got, err := CheckSchemaVersion(context.TODO(), c, conf, tt.acceptNewer)
+
+			if tt.wantErrSubstr != "" {
+				var versionErr SchemaVersionError
+				assert.ErrorAs(t, err, &versionErr)
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, got.Type.(config.SettingsType).SchemaVersion)
+			assert.Equal(t, tt.declared, conf.Type.(config.SettingsType).SchemaVersion, "given config must not be modified")
+		})
+	}
+
+	t.Run("no declared version is not checked", func(t *testing.T) {
+		c := client.NewMockSettingsClient(gomock.NewController(t))
+
+		conf := &config.Config{Type: config.SettingsType{SchemaId: "builtin:alerting.profile"}}
+		got, err := CheckSchemaVersion(context.TODO(), c, conf, false)
+		assert.NoError(t, err)
+		assert.Same(t, conf, got)
+	})
+
+	t.Run("failing to get the schema skips the check", func(t *testing.T) {
+		c := client.NewMockSettingsClient(gomock.NewController(t))
+		c.EXPECT().GetSchemaById("builtin:alerting.profile").Return(dtclient.Schema{}, errors.New("boom"))
+
+		conf := &config.Config{Type: config.SettingsType{SchemaId: "builtin:alerting.profile", SchemaVersion: "1.2"}}
+		got, err := CheckSchemaVersion(context.TODO(), c, conf, false)
+		assert.NoError(t, err)
+		assert.Same(t, conf, got)
+	})
+}
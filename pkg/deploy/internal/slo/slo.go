@@ -0,0 +1,107 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+)
+
+type Client interface {
+	List(ctx context.Context) ([]slo.Response, error)
+	Create(ctx context.Context, data []byte) (slo.Response, error)
+	Update(ctx context.Context, id string, data []byte) (slo.Response, error)
+}
+
+var _ Client = (*DummyClient)(nil)
+
+type DummyClient struct{}
+
+func (c *DummyClient) List(context.Context) ([]slo.Response, error) {
+	return nil, nil
+}
+
+func (c *DummyClient) Create(_ context.Context, data []byte) (slo.Response, error) {
+	return slo.Response{ID: uuid.NewString(), Data: data}, nil
+}
+
+func (c *DummyClient) Update(_ context.Context, id string, data []byte) (slo.Response, error) {
+	return slo.Response{ID: id, Data: data}, nil
+}
+
+// Deploy creates or updates the SLO of the given config. SLO names are unique within an environment, so an existing
+// SLO is identified by the origin object ID of the config, or by its name.
+func Deploy(ctx context.Context, client Client, properties parameter.Properties, renderedConfig string, c *config.Config) (entities.ResolvedEntity, error) {
+	// create new context to carry logger
+	ctx = logr.NewContext(ctx, log.WithCtxFields(ctx).GetLogr())
+
+	sloName, ok := properties[config.NameParameter].(string)
+	if !ok {
+		return entities.ResolvedEntity{}, errors.New("missing name parameter")
+	}
+
+	existing, err := client.List(ctx)
+	if err != nil {
+		return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, "failed to list existing SLOs").WithError(err)
+	}
+
+	id := findExisting(existing, c.OriginObjectId, sloName)
+	if id != "" {
+		if _, err := client.Update(ctx, id, []byte(renderedConfig)); err != nil {
+			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to update SLO %q", id)).WithError(err)
+		}
+	} else {
+		resp, err := client.Create(ctx, []byte(renderedConfig))
+		if err != nil {
+			return entities.ResolvedEntity{}, deployErrors.NewConfigDeployErr(c, fmt.Sprintf("failed to create SLO named %q", sloName)).WithError(err)
+		}
+		id = resp.ID
+	}
+
+	properties[config.IdParameter] = id
+
+	return entities.ResolvedEntity{
+		EntityName: sloName,
+		Coordinate: c.Coordinate,
+		Properties: properties,
+	}, nil
+}
+
+// findExisting returns the ID of the SLO with the given origin object ID if it exists, or else the ID of the SLO with
+// the given name. If neither exists, an empty string is returned.
+func findExisting(slos []slo.Response, originObjectId string, name string) string {
+	var byName string
+	for _, s := range slos {
+		if originObjectId != "" && s.ID == originObjectId {
+			return s.ID
+		}
+		if byName == "" && s.Name == name {
+			byName = s.ID
+		}
+	}
+	return byName
+}
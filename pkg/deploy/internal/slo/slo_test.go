@@ -0,0 +1,111 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slo_test
+
+import (
+	"context"
+	"testing"
+
+	sloClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/slo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	existing []sloClient.Response
+	created  []string
+	updated  []string
+}
+
+func (c *fakeClient) List(context.Context) ([]sloClient.Response, error) {
+	return c.existing, nil
+}
+
+func (c *fakeClient) Create(_ context.Context, data []byte) (sloClient.Response, error) {
+	c.created = append(c.created, string(data))
+	return sloClient.Response{ID: "new-id", Data: data}, nil
+}
+
+func (c *fakeClient) Update(_ context.Context, id string, data []byte) (sloClient.Response, error) {
+	c.updated = append(c.updated, id)
+	return sloClient.Response{ID: id, Data: data}, nil
+}
+
+func TestDeploy(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       []sloClient.Response
+		originObjectId string
+		wantID         string
+		wantCreated    bool
+	}{
+		{
+			name:        "creates SLO that does not exist",
+			existing:    []sloClient.Response{{ID: "other-id", Name: "other"}},
+			wantID:      "new-id",
+			wantCreated: true,
+		},
+		{
+			name:     "updates SLO of the same name",
+			existing: []sloClient.Response{{ID: "other-id", Name: "other"}, {ID: "existing-id", Name: "my-slo"}},
+			wantID:   "existing-id",
+		},
+		{
+			name:           "prefers SLO with the origin object ID",
+			existing:       []sloClient.Response{{ID: "existing-id", Name: "my-slo"}, {ID: "origin-id", Name: "renamed"}},
+			originObjectId: "origin-id",
+			wantID:         "origin-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{existing: tt.existing}
+			c := &config.Config{
+				Coordinate:     coordinate.Coordinate{Project: "p", Type: "slo-v2", ConfigId: "my-slo"},
+				Type:           config.SLOType{},
+				OriginObjectId: tt.originObjectId,
+			}
+
+			got, err := slo.Deploy(context.TODO(), client, parameter.Properties{config.NameParameter: "my-slo"}, `{"name": "my-slo"}`, c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantID, got.Properties[config.IdParameter])
+			assert.Equal(t, "my-slo", got.EntityName)
+			if tt.wantCreated {
+				assert.Equal(t, []string{`{"name": "my-slo"}`}, client.created)
+				assert.Empty(t, client.updated)
+			} else {
+				assert.Equal(t, []string{tt.wantID}, client.updated)
+				assert.Empty(t, client.created)
+			}
+		})
+	}
+}
+
+func TestDeploy_FailsWithoutName(t *testing.T) {
+	c := &config.Config{Coordinate: coordinate.Coordinate{Project: "p", Type: "slo-v2", ConfigId: "my-slo"}, Type: config.SLOType{}}
+
+	_, err := slo.Deploy(context.TODO(), &fakeClient{}, parameter.Properties{}, `{}`, c)
+	assert.ErrorContains(t, err, "missing name parameter")
+}
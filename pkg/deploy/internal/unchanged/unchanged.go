@@ -0,0 +1,222 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package unchanged implements skipping the deployment of configurations that are identical to their current state.
+// Before an object is created or updated, the existing object is read from the environment and compared to the
+// rendered payload. If all properties defined in the payload already have the same value, and settings objects are
+// in the same scope and position, the write is skipped and the existing object is returned instead.
+package unchanged
+
+import (
+	"context"
+
+	automationAPI "github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/automation"
+	coreAutomation "github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	clusterClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	sloClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/slo"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/document"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/plan"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/slo"
+)
+
+var (
+	_ client.ConfigClient   = (*DynatraceClient)(nil)
+	_ client.SettingsClient = (*DynatraceClient)(nil)
+	_ automation.Client     = (*AutomationClient)(nil)
+	_ bucket.Client         = (*BucketClient)(nil)
+	_ document.Client       = (*DocumentClient)(nil)
+	_ slo.Client            = (*SLOClient)(nil)
+	_ cluster.Client        = (*ClusterClient)(nil)
+)
+
+// ConfigAndSettingsClient is the client used to deploy classic configs and settings
+type ConfigAndSettingsClient interface {
+	client.ConfigClient
+	client.SettingsClient
+}
+
+// DynatraceClient wraps the client deploying classic configs and settings, skipping upserts of unchanged objects.
+// The comparison is done by planning the upsert, see plan.DynatraceClient.
+type DynatraceClient struct {
+	ConfigAndSettingsClient
+	remote client.DynatraceClient
+}
+
+// NewDynatraceClient creates a DynatraceClient reading the current state using remote, and deploying changed objects using c
+func NewDynatraceClient(remote client.DynatraceClient, c ConfigAndSettingsClient) *DynatraceClient {
+	return &DynatraceClient{ConfigAndSettingsClient: c, remote: remote}
+}
+
+func (c *DynatraceClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (dtclient.DynatraceEntity, error) {
+	p := plan.New()
+	entity, err := plan.NewDynatraceClient(c.remote, p).UpsertConfigByName(ctx, a, name, payload)
+	if isUnchanged(ctx, p, err) {
+		return entity, nil
+	}
+	return c.ConfigAndSettingsClient.UpsertConfigByName(ctx, a, name, payload)
+}
+
+func (c *DynatraceClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityID string, name string, payload []byte, duplicate bool) (dtclient.DynatraceEntity, error) {
+	p := plan.New()
+	entity, err := plan.NewDynatraceClient(c.remote, p).UpsertConfigByNonUniqueNameAndId(ctx, a, entityID, name, payload, duplicate)
+	if isUnchanged(ctx, p, err) {
+		return entity, nil
+	}
+	return c.ConfigAndSettingsClient.UpsertConfigByNonUniqueNameAndId(ctx, a, entityID, name, payload, duplicate)
+}
+
+func (c *DynatraceClient) UpsertSettings(ctx context.Context, obj dtclient.SettingsObject, opts dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+	p := plan.New()
+	entity, err := plan.NewDynatraceClient(c.remote, p).UpsertSettings(ctx, obj, opts)
+	if isUnchanged(ctx, p, err) {
+		return entity, nil
+	}
+	return c.ConfigAndSettingsClient.UpsertSettings(ctx, obj, opts)
+}
+
+// AutomationClient wraps the client deploying automation objects, skipping upserts of unchanged objects
+type AutomationClient struct {
+	automation.Client
+	remote client.AutomationClient
+}
+
+// NewAutomationClient creates an AutomationClient reading the current state using remote, and deploying changed objects using c
+func NewAutomationClient(remote client.AutomationClient, c automation.Client) *AutomationClient {
+	return &AutomationClient{Client: c, remote: remote}
+}
+
+func (c *AutomationClient) Upsert(ctx context.Context, resourceType automationAPI.ResourceType, id string, data []byte) (coreAutomation.Response, error) {
+	if existing, err := c.remote.Get(ctx, resourceType, id); err == nil && isIdentical(ctx, data, existing.Data) {
+		return existing, nil
+	}
+	return c.Client.Upsert(ctx, resourceType, id, data)
+}
+
+// BucketClient wraps the client deploying Grail buckets, skipping upserts of unchanged buckets
+type BucketClient struct {
+	bucket.Client
+	remote client.BucketClient
+}
+
+// NewBucketClient creates a BucketClient reading the current state using remote, and deploying changed buckets using c
+func NewBucketClient(remote client.BucketClient, c bucket.Client) *BucketClient {
+	return &BucketClient{Client: c, remote: remote}
+}
+
+func (c *BucketClient) Upsert(ctx context.Context, bucketName string, data []byte) (buckets.Response, error) {
+	if existing, err := c.remote.Get(ctx, bucketName); err == nil && isIdentical(ctx, data, existing.Data) {
+		return existing, nil
+	}
+	return c.Client.Upsert(ctx, bucketName, data)
+}
+
+// DocumentClient wraps the client deploying documents, skipping updates of unchanged documents
+type DocumentClient struct {
+	document.Client
+}
+
+// NewDocumentClient creates a DocumentClient deploying changed documents using c
+func NewDocumentClient(c document.Client) *DocumentClient {
+	return &DocumentClient{Client: c}
+}
+
+func (c *DocumentClient) Update(ctx context.Context, id string, name string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	if existing, err := c.Client.Get(ctx, id); err == nil && existing.Name == name && isIdentical(ctx, data, existing.Data) {
+		return existing, nil
+	}
+	return c.Client.Update(ctx, id, name, data, documentType)
+}
+
+// SLOClient wraps the client deploying SLOs, skipping updates of unchanged SLOs
+type SLOClient struct {
+	slo.Client
+	remote client.SLOClient
+}
+
+// NewSLOClient creates an SLOClient reading the current state using remote, and deploying changed SLOs using c
+func NewSLOClient(remote client.SLOClient, c slo.Client) *SLOClient {
+	return &SLOClient{Client: c, remote: remote}
+}
+
+func (c *SLOClient) Update(ctx context.Context, id string, data []byte) (sloClient.Response, error) {
+	if existing, err := c.remote.Get(ctx, id); err == nil && isIdentical(ctx, data, existing.Data) {
+		return existing, nil
+	}
+	return c.Client.Update(ctx, id, data)
+}
+
+// ClusterClient wraps the client deploying cluster-wide configurations, skipping updates of unchanged objects.
+// The comparison is done by planning the update, see plan.ClusterClient.
+type ClusterClient struct {
+	cluster.Client
+	remote client.ClusterClient
+}
+
+// NewClusterClient creates a ClusterClient reading the current state using remote, and deploying changed objects using c
+func NewClusterClient(remote client.ClusterClient, c cluster.Client) *ClusterClient {
+	return &ClusterClient{Client: c, remote: remote}
+}
+
+func (c *ClusterClient) Update(ctx context.Context, a api.ClusterAPI, id string, data []byte) (clusterClient.Response, error) {
+	p := plan.New()
+	resp, err := plan.NewClusterClient(c.remote, p).Update(ctx, a, id, data)
+	if isUnchanged(ctx, p, err) {
+		return resp, nil
+	}
+	return c.Client.Update(ctx, a, id, data)
+}
+
+// isUnchanged returns whether planning an upsert resulted in no changes. If planning failed, the object is treated as
+// changed, so that it is deployed as usual.
+func isUnchanged(ctx context.Context, p *plan.Plan, err error) bool {
+	if err != nil {
+		log.WithCtxFields(ctx).WithFields(field.Error(err)).Debug("Failed to compare config to its current state, deploying it: %v", err)
+		return false
+	}
+	if p.Count(plan.NoOp) == 0 {
+		return false
+	}
+	logSkipped(ctx)
+	return true
+}
+
+// isIdentical returns whether the existing object already matches the desired payload
+func isIdentical(ctx context.Context, desired, actual []byte) bool {
+	changes, err := plan.Diff(desired, actual)
+	if err != nil {
+		log.WithCtxFields(ctx).WithFields(field.Error(err)).Debug("Failed to compare config to its current state, deploying it: %v", err)
+		return false
+	}
+	if len(changes) > 0 {
+		return false
+	}
+	logSkipped(ctx)
+	return true
+}
+
+func logSkipped(ctx context.Context) {
+	log.WithCtxFields(ctx).Info("Config is unchanged, skipping update")
+}
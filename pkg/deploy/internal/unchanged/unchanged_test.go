@@ -0,0 +1,160 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unchanged_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/cluster"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/unchanged"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDynatraceClient_SkipsUnchangedClassicConfig(t *testing.T) {
+	theAPI := api.API{ID: "alerting-profile", URLPath: "/api/config/v1/alertingProfiles"}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(true, "1234", nil)
+	c.EXPECT().ReadConfigById(theAPI, "1234").Return([]byte(`{"id": "1234", "name": "profile", "rules": []}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	entity, err := unchanged.NewDynatraceClient(c, c).UpsertConfigByName(context.TODO(), theAPI, "profile", []byte(`{"name": "profile", "rules": []}`))
+	require.NoError(t, err)
+	assert.Equal(t, "1234", entity.Id)
+}
+
+func TestDynatraceClient_SkipsClassicConfigOnlyDifferingInDeploymentTime(t *testing.T) {
+	theAPI := api.API{ID: "dashboard", URLPath: "/api/config/v1/dashboards"}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "dash").Return(true, "1234", nil)
+	c.EXPECT().ReadConfigById(theAPI, "1234").Return([]byte(`{"id": "1234", "dashboardMetadata": {"name": "dash", "tags": ["managed-by:monaco", "monaco-deployed:2024-01-01T00:00:00Z"]}}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := unchanged.NewDynatraceClient(c, c).UpsertConfigByName(context.TODO(), theAPI, "dash", []byte(`{"dashboardMetadata": {"name": "dash", "tags": ["managed-by:monaco", "monaco-deployed:2024-05-10T14:12:00Z"]}}`))
+	require.NoError(t, err)
+}
+
+func TestDynatraceClient_DeploysChangedClassicConfig(t *testing.T) {
+	theAPI := api.API{ID: "alerting-profile", URLPath: "/api/config/v1/alertingProfiles"}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), theAPI, "profile").Return(true, "1234", nil)
+	c.EXPECT().ReadConfigById(theAPI, "1234").Return([]byte(`{"id": "1234", "name": "profile", "rules": []}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), theAPI, "profile", gomock.Any()).Return(dtclient.DynatraceEntity{Id: "1234", Name: "profile"}, nil)
+
+	_, err := unchanged.NewDynatraceClient(c, c).UpsertConfigByName(context.TODO(), theAPI, "profile", []byte(`{"name": "profile", "rules": [{"a": 1}]}`))
+	require.NoError(t, err)
+}
+
+func TestDynatraceClient_DeploysNewSettings(t *testing.T) {
+	obj := dtclient.SettingsObject{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:test", ConfigId: "c"},
+		SchemaId:   "builtin:test",
+		Content:    []byte(`{"enabled": true}`),
+	}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:test", gomock.Any()).Return(nil, nil)
+	c.EXPECT().UpsertSettings(gomock.Any(), obj, gomock.Any()).Return(dtclient.DynatraceEntity{Id: "new"}, nil)
+
+	entity, err := unchanged.NewDynatraceClient(c, c).UpsertSettings(context.TODO(), obj, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", entity.Id)
+}
+
+func TestDynatraceClient_DeploysSettingsWithChangedScope(t *testing.T) {
+	obj := dtclient.SettingsObject{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:test", ConfigId: "c"},
+		SchemaId:   "builtin:test",
+		Scope:      "HOST-1234",
+		Content:    []byte(`{"enabled": true}`),
+	}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:test", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+		{ObjectId: "obj-id", SchemaId: "builtin:test", Scope: "environment", Value: []byte(`{"enabled": true}`)},
+	}, nil)
+	c.EXPECT().UpsertSettings(gomock.Any(), obj, gomock.Any()).Return(dtclient.DynatraceEntity{Id: "obj-id"}, nil)
+
+	_, err := unchanged.NewDynatraceClient(c, c).UpsertSettings(context.TODO(), obj, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+}
+
+func TestBucketClient_SkipsUnchangedBucket(t *testing.T) {
+	c := client.NewMockBucketClient(gomock.NewController(t))
+	c.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Response{StatusCode: 200, Data: []byte(`{"bucketName": "bucket", "retentionDays": 35, "version": 3}`)}, nil)
+	c.EXPECT().Upsert(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := unchanged.NewBucketClient(c, c).Upsert(context.TODO(), "bucket", []byte(`{"bucketName": "bucket", "retentionDays": 35}`))
+	require.NoError(t, err)
+}
+
+func TestBucketClient_DeploysChangedBucket(t *testing.T) {
+	c := client.NewMockBucketClient(gomock.NewController(t))
+	c.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Response{StatusCode: 200, Data: []byte(`{"bucketName": "bucket", "retentionDays": 35}`)}, nil)
+	c.EXPECT().Upsert(gomock.Any(), "bucket", gomock.Any()).Return(buckets.Response{}, nil)
+
+	_, err := unchanged.NewBucketClient(c, c).Upsert(context.TODO(), "bucket", []byte(`{"bucketName": "bucket", "retentionDays": 60}`))
+	require.NoError(t, err)
+}
+
+func TestClusterClient_SkipsUnchangedObject(t *testing.T) {
+	theAPI := api.NewClusterAPIs()[api.ClusterUserGroups]
+
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().List(gomock.Any(), theAPI).Return([]cluster.Response{
+		{ID: "1234", Name: "group", Data: []byte(`{"id": "1234", "name": "group", "ldapGroupNames": ["admins"]}`)},
+	}, nil)
+	c.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := unchanged.NewClusterClient(c, c).Update(context.TODO(), theAPI, "1234", []byte(`{"name": "group", "ldapGroupNames": ["admins"]}`))
+	require.NoError(t, err)
+}
+
+func TestClusterClient_DeploysChangedObject(t *testing.T) {
+	theAPI := api.NewClusterAPIs()[api.ClusterUserGroups]
+
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().List(gomock.Any(), theAPI).Return([]cluster.Response{
+		{ID: "1234", Name: "group", Data: []byte(`{"id": "1234", "name": "group", "ldapGroupNames": []}`)},
+	}, nil)
+	c.EXPECT().Update(gomock.Any(), theAPI, "1234", gomock.Any()).Return(cluster.Response{ID: "1234"}, nil)
+
+	_, err := unchanged.NewClusterClient(c, c).Update(context.TODO(), theAPI, "1234", []byte(`{"name": "group", "ldapGroupNames": ["admins"]}`))
+	require.NoError(t, err)
+}
+
+func TestClusterClient_DeploysSingleConfiguration(t *testing.T) {
+	theAPI := api.NewClusterAPIs()[api.ClusterProxy]
+
+	c := client.NewMockClusterClient(gomock.NewController(t))
+	c.EXPECT().Update(gomock.Any(), theAPI, "", gomock.Any()).Return(cluster.Response{}, nil)
+
+	_, err := unchanged.NewClusterClient(c, c).Update(context.TODO(), theAPI, "", []byte(`{"scheme": "http"}`))
+	require.NoError(t, err)
+}
@@ -0,0 +1,55 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+)
+
+// environmentVariableValidator checks that all environment variables required by a config are set, so that all
+// missing variables are reported before the deployment starts, instead of failing one config after the other.
+type environmentVariableValidator struct{}
+
+// Validate returns an error listing all required environment variables of the config that are not set
+func (environmentVariableValidator) Validate(c config.Config) error {
+	if c.Skip {
+		return nil
+	}
+
+	var missing []string
+	for _, p := range c.Parameters {
+		envParam, ok := p.(*environment.EnvironmentVariableParameter)
+		if !ok || !envParam.IsRequired() {
+			continue
+		}
+		if _, found := os.LookupEnv(envParam.Name); !found && !slices.Contains(missing, envParam.Name) {
+			missing = append(missing, envParam.Name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	slices.Sort(missing)
+	return fmt.Errorf("config %s requires environment variables which are not set: %s", c.Coordinate, strings.Join(missing, ", "))
+}
@@ -0,0 +1,76 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentVariableValidator(t *testing.T) {
+	t.Setenv("__SET_VAR", "value")
+
+	newConfig := func(id string, skip bool, params config.Parameters) config.Config {
+		return config.Config{
+			Type:        config.ClassicApiType{Api: "alerting-profile"},
+			Environment: "env1",
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: id},
+			Parameters:  params,
+			Skip:        skip,
+		}
+	}
+
+	projects := []project.Project{
+		{
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env1": project.ConfigsPerType{
+					"alerting-profile": {
+						newConfig("config1", false, config.Parameters{
+							config.NameParameter: &value.ValueParameter{Value: "config1"},
+							"b":                  environment.New("__MISSING_B"),
+							"a":                  environment.New("__MISSING_A"),
+							"set":                environment.New("__SET_VAR"),
+							"default":            environment.NewWithDefault("__MISSING_DEFAULT", "x"),
+							"optional":           environment.NewOptional("__MISSING_OPTIONAL"),
+						}),
+						newConfig("config2", false, config.Parameters{
+							config.NameParameter: environment.New("__MISSING_C"),
+						}),
+						newConfig("skipped", true, config.Parameters{
+							config.NameParameter: environment.New("__MISSING_SKIPPED"),
+						}),
+					},
+				},
+			},
+		},
+	}
+
+	err := Validate(projects)
+	assert.ErrorContains(t, err, "config project:alerting-profile:config1 requires environment variables which are not set: __MISSING_A, __MISSING_B")
+	assert.ErrorContains(t, err, "config project:alerting-profile:config2 requires environment variables which are not set: __MISSING_C")
+	assert.NotContains(t, err.Error(), "__MISSING_DEFAULT")
+	assert.NotContains(t, err.Error(), "__MISSING_OPTIONAL")
+	assert.NotContains(t, err.Error(), "__MISSING_SKIPPED")
+}
@@ -0,0 +1,67 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"context"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+)
+
+var (
+	_ client.ConfigClient   = (*RemoteValidationClient)(nil)
+	_ client.SettingsClient = (*RemoteValidationClient)(nil)
+)
+
+// RemoteValidationClient is used in place of the classic and settings clients during a dry-run with server-side validation.
+// Instead of creating or updating objects, every payload is sent to the validation endpoints of the environment. As
+// nothing is created, the returned entities are the fake entities of a dtclient.DummyClient.
+type RemoteValidationClient struct {
+	*dtclient.DummyClient
+	validator client.ValidationClient
+}
+
+// NewRemoteValidationClient creates a RemoteValidationClient validating payloads using the given client.ValidationClient
+func NewRemoteValidationClient(validator client.ValidationClient) *RemoteValidationClient {
+	return &RemoteValidationClient{
+		DummyClient: &dtclient.DummyClient{},
+		validator:   validator,
+	}
+}
+
+func (c *RemoteValidationClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (dtclient.DynatraceEntity, error) {
+	if err := c.validator.ValidateConfig(ctx, a, payload); err != nil {
+		return dtclient.DynatraceEntity{}, err
+	}
+	return c.DummyClient.UpsertConfigByName(ctx, a, name, payload)
+}
+
+func (c *RemoteValidationClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityID string, name string, payload []byte, duplicate bool) (dtclient.DynatraceEntity, error) {
+	if err := c.validator.ValidateConfig(ctx, a, payload); err != nil {
+		return dtclient.DynatraceEntity{}, err
+	}
+	return c.DummyClient.UpsertConfigByNonUniqueNameAndId(ctx, a, entityID, name, payload, duplicate)
+}
+
+func (c *RemoteValidationClient) UpsertSettings(ctx context.Context, obj dtclient.SettingsObject, opts dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+	if err := c.validator.ValidateSettings(ctx, obj); err != nil {
+		return dtclient.DynatraceEntity{}, err
+	}
+	return c.DummyClient.UpsertSettings(ctx, obj, opts)
+}
@@ -0,0 +1,56 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+)
+
+// missingKeysValidator checks that all template variables of strict configs are defined as parameters, so that all
+// missing keys of all configs are reported before the deployment starts, instead of failing one config after the other.
+type missingKeysValidator struct{}
+
+// Validate returns an error listing all template variables of the config that are not defined as parameters
+func (missingKeysValidator) Validate(c config.Config) error {
+	if c.Skip || c.Template == nil || c.MissingKeys == config.MissingKeysLenient {
+		return nil
+	}
+
+	keys, err := template.ReferencedKeys(c.Template)
+	if err != nil {
+		// invalid templates are reported when they are rendered
+		return nil
+	}
+
+	var missing []string
+	for _, k := range keys {
+		if _, found := c.Parameters[k]; !found {
+			missing = append(missing, k)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	slices.Sort(missing)
+	return fmt.Errorf("template of config %s references keys which are not defined as parameters: %s", c.Coordinate, strings.Join(missing, ", "))
+}
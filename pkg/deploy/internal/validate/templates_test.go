@@ -0,0 +1,64 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingKeysValidator(t *testing.T) {
+	newConfig := func(id string, content string, mode config.MissingKeyMode) config.Config {
+		return config.Config{
+			Template:    template.NewInMemoryTemplate(id, content),
+			Type:        config.ClassicApiType{Api: "alerting-profile"},
+			Environment: "env1",
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: id},
+			Parameters:  config.Parameters{config.NameParameter: &value.ValueParameter{Value: id}},
+			MissingKeys: mode,
+		}
+	}
+
+	projects := []project.Project{
+		{
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env1": project.ConfigsPerType{
+					"alerting-profile": {
+						newConfig("config1", `{"name": "{{ .name }}", "b": "{{ .b }}", "a": {{ .a.value }}}`, ""),
+						newConfig("config2", `{"name": "{{ .name }}", {{ range .items }}"{{ .x }}"{{ end }} "c": "{{ $.c }}"}`, config.MissingKeysStrict),
+						newConfig("lenient", `{"name": "{{ .name }}", "d": "{{ .d }}"}`, config.MissingKeysLenient),
+						newConfig("valid", `{"name": "{{ .name }}"}`, ""),
+					},
+				},
+			},
+		},
+	}
+
+	err := validate(projects, []Validator{missingKeysValidator{}})
+	assert.ErrorContains(t, err, "template of config project:alerting-profile:config1 references keys which are not defined as parameters: a, b")
+	assert.ErrorContains(t, err, "template of config project:alerting-profile:config2 references keys which are not defined as parameters: c, items")
+	assert.NotContains(t, err.Error(), "lenient")
+	assert.NotContains(t, err.Error(), "valid")
+}
@@ -32,6 +32,8 @@ func Validate(projects []project.Project) error {
 	defaultValidators := []Validator{
 		classic.NewValidator(),
 		&setting.Validator{},
+		environmentVariableValidator{},
+		missingKeysValidator{},
 	}
 	return validate(projects, defaultValidators)
 }
@@ -0,0 +1,138 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
+)
+
+const (
+	// documentExternalID is the well-known external ID of the document storing the lease of an environment
+	documentExternalID = "monaco-deployment-lock"
+	// documentType is the type of the document storing the lease of an environment
+	documentType documents.DocumentType = "monaco-deployment-lock"
+)
+
+// DocumentClient is the subset of the Documents API client needed to store leases
+type DocumentClient interface {
+	Get(ctx context.Context, id string) (documents.Response, error)
+	List(ctx context.Context, filter string) (documents.ListResponse, error)
+	Create(ctx context.Context, name string, externalId string, data []byte, documentType documents.DocumentType) (documents.Response, error)
+	Update(ctx context.Context, id string, name string, data []byte, documentType documents.DocumentType) (documents.Response, error)
+	Delete(ctx context.Context, id string) (documents.Response, error)
+}
+
+// DocumentStore is a Store persisting the lease as a document with a well-known external ID via the Documents API.
+// The revision of a lease is the ID of its document. As the Documents API rejects creating a second document with the
+// same external ID, only one lease can be created at a time.
+type DocumentStore struct {
+	client DocumentClient
+	name   string
+}
+
+var _ Store = (*DocumentStore)(nil)
+
+// NewDocumentStore returns a DocumentStore using the given client. The name is used as the document's name.
+func NewDocumentStore(client DocumentClient, name string) *DocumentStore {
+	return &DocumentStore{client: client, name: name}
+}
+
+// Get implements Store
+func (s *DocumentStore) Get(ctx context.Context) (Lease, string, bool, error) {
+	id, err := s.find(ctx)
+	if err != nil || id == "" {
+		return Lease{}, "", false, err
+	}
+
+	resp, err := s.client.Get(ctx, id)
+	if isAPIErrorStatus(err, http.StatusNotFound) {
+		return Lease{}, "", false, nil
+	}
+	if err != nil {
+		return Lease{}, "", false, err
+	}
+
+	var lease Lease
+	if err := json.Unmarshal(resp.Data, &lease); err != nil {
+		return Lease{}, "", false, fmt.Errorf("failed to unmarshal lease of document %q: %w", id, err)
+	}
+	return lease, id, true, nil
+}
+
+// Create implements Store
+func (s *DocumentStore) Create(ctx context.Context, lease Lease) (string, error) {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.Create(ctx, s.name, documentExternalID, data, documentType)
+	if isAPIErrorStatus(err, http.StatusConflict) {
+		return "", fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// Update implements Store
+func (s *DocumentStore) Update(ctx context.Context, revision string, lease Lease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Update(ctx, revision, s.name, data, documentType)
+	if isAPIErrorStatus(err, http.StatusNotFound) || isAPIErrorStatus(err, http.StatusConflict) {
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	return err
+}
+
+// Delete implements Store
+func (s *DocumentStore) Delete(ctx context.Context, revision string) error {
+	_, err := s.client.Delete(ctx, revision)
+	if isAPIErrorStatus(err, http.StatusNotFound) {
+		return nil
+	}
+	return err
+}
+
+// find returns the ID of the lease document, or an empty string if there is none
+func (s *DocumentStore) find(ctx context.Context) (string, error) {
+	resp, err := s.client.List(ctx, fmt.Sprintf("externalId=='%s'", documentExternalID))
+	if err != nil {
+		return "", fmt.Errorf("failed to list lock documents: %w", err)
+	}
+	if len(resp.Responses) == 0 {
+		return "", nil
+	}
+	return resp.Responses[0].ID, nil
+}
+
+func isAPIErrorStatus(err error, status int) bool {
+	var apiErr api.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
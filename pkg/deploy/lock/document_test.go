@@ -0,0 +1,146 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lock_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/documents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/lock"
+)
+
+// fakeDocumentClient keeps documents in memory. Like the Documents API, it rejects creating a document with an external
+// ID already in use. Only the external ID filter used by lock.DocumentStore is supported.
+type fakeDocumentClient struct {
+	docs   map[string]documents.Response
+	nextID int
+}
+
+func (c *fakeDocumentClient) Get(_ context.Context, id string) (documents.Response, error) {
+	d, ok := c.docs[id]
+	if !ok {
+		return documents.Response{}, api.APIError{StatusCode: 404}
+	}
+	return d, nil
+}
+
+func (c *fakeDocumentClient) List(_ context.Context, filter string) (documents.ListResponse, error) {
+	var resp documents.ListResponse
+	for _, d := range c.docs {
+		if filter == fmt.Sprintf("externalId=='%s'", d.ExternalID) {
+			resp.Responses = append(resp.Responses, d)
+		}
+	}
+	return resp, nil
+}
+
+func (c *fakeDocumentClient) Create(_ context.Context, name string, externalId string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	for _, d := range c.docs {
+		if d.ExternalID == externalId {
+			return documents.Response{}, api.APIError{StatusCode: 409}
+		}
+	}
+	c.nextID++
+	id := fmt.Sprint(c.nextID)
+	c.docs[id] = documents.Response{Response: api.Response{Data: data}, ID: id, ExternalID: externalId, Name: name, Type: string(documentType)}
+	return c.docs[id], nil
+}
+
+func (c *fakeDocumentClient) Update(_ context.Context, id string, name string, data []byte, documentType documents.DocumentType) (documents.Response, error) {
+	d, ok := c.docs[id]
+	if !ok {
+		return documents.Response{}, api.APIError{StatusCode: 404}
+	}
+	d.Name, d.Data, d.Type = name, data, string(documentType)
+	c.docs[id] = d
+	return d, nil
+}
+
+func (c *fakeDocumentClient) Delete(_ context.Context, id string) (documents.Response, error) {
+	if _, ok := c.docs[id]; !ok {
+		return documents.Response{}, api.APIError{StatusCode: 404}
+	}
+	delete(c.docs, id)
+	return documents.Response{}, nil
+}
+
+func TestDocumentStore(t *testing.T) {
+	client := &fakeDocumentClient{docs: map[string]documents.Response{}}
+	store := lock.NewDocumentStore(client, "env lock")
+
+	_, _, found, err := store.Get(context.TODO())
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	first := lock.Lease{Holder: "first", Acquired: time.Unix(0, 0).UTC(), Expires: time.Unix(60, 0).UTC()}
+	revision, err := store.Create(context.TODO(), first)
+	require.NoError(t, err)
+	got, gotRevision, found, err := store.Get(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, first, got)
+	assert.Equal(t, revision, gotRevision)
+	assert.Equal(t, "env lock", client.docs[revision].Name)
+
+	renewed := first
+	renewed.Expires = time.Unix(120, 0).UTC()
+	require.NoError(t, store.Update(context.TODO(), revision, renewed))
+	got, _, _, err = store.Get(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, renewed, got)
+	assert.Len(t, client.docs, 1, "the lease document must be updated, not created again")
+
+	require.NoError(t, store.Delete(context.TODO(), revision))
+	_, _, found, err = store.Get(context.TODO())
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, store.Delete(context.TODO(), revision), "deleting a non-existing lease must not fail")
+}
+
+func TestDocumentStore_WritesAreConditional(t *testing.T) {
+	client := &fakeDocumentClient{docs: map[string]documents.Response{}}
+	store := lock.NewDocumentStore(client, "env lock")
+
+	first := lock.Lease{Holder: "first", Acquired: time.Unix(0, 0).UTC(), Expires: time.Unix(60, 0).UTC()}
+	firstRevision, err := store.Create(context.TODO(), first)
+	require.NoError(t, err)
+
+	second := lock.Lease{Holder: "second", Acquired: time.Unix(120, 0).UTC(), Expires: time.Unix(180, 0).UTC()}
+	_, err = store.Create(context.TODO(), second)
+	assert.ErrorIs(t, err, lock.ErrConflict, "a second lease must not be created")
+
+	require.NoError(t, store.Delete(context.TODO(), firstRevision))
+	secondRevision, err := store.Create(context.TODO(), second)
+	require.NoError(t, err)
+	assert.NotEqual(t, firstRevision, secondRevision)
+
+	assert.ErrorIs(t, store.Update(context.TODO(), firstRevision, first), lock.ErrConflict, "a lease taken over must not be updated")
+	require.NoError(t, store.Delete(context.TODO(), firstRevision))
+	got, _, found, err := store.Get(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, found, "a lease taken over must not be deleted")
+	assert.Equal(t, second, got)
+}
@@ -0,0 +1,295 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lock implements environment locks that prevent concurrent deployments to the same environment.
+// A lock is a lease stored in the environment itself, see Store. A lease expires if it is not renewed, so that a
+// crashed deployment does not block an environment forever.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
+)
+
+// maxAcquireAttempts is the number of times acquiring a lock is attempted if its lease is changed concurrently
+const maxAcquireAttempts = 3
+
+const (
+	// DefaultTTL is the duration after which a lease expires if it is not renewed
+	DefaultTTL = 10 * time.Minute
+	// DefaultPollInterval is the interval in which a held lock is checked while waiting for it
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Lease is the state of a lock persisted in a Store
+type Lease struct {
+	// Holder identifies the deployment holding the lock
+	Holder string `json:"holder"`
+	// Acquired is the time the lock was acquired
+	Acquired time.Time `json:"acquired"`
+	// Expires is the time after which the lock is considered released, unless it is renewed
+	Expires time.Time `json:"expires"`
+}
+
+// ErrConflict is returned by a Store if a lease was not written as the stored lease was changed concurrently
+var ErrConflict = errors.New("lease was changed concurrently")
+
+// Store persists the lease of a single environment. Every lease created in a store has a revision identifying it until
+// it is deleted. Writes are conditional on the revision, so that concurrent deployments can not overwrite each other's
+// leases.
+type Store interface {
+	// Get returns the current lease and its revision, and false if there is none
+	Get(ctx context.Context) (Lease, string, bool, error)
+	// Create writes a new lease and returns its revision. If a lease exists already, ErrConflict is returned.
+	Create(ctx context.Context, lease Lease) (string, error)
+	// Update replaces the lease of the given revision. If it does not exist anymore, ErrConflict is returned.
+	Update(ctx context.Context, revision string, lease Lease) error
+	// Delete removes the lease of the given revision. Deleting a lease that does not exist anymore is not an error.
+	Delete(ctx context.Context, revision string) error
+}
+
+// HeldError is returned if a lock could not be acquired as it is held by another deployment
+type HeldError struct {
+	Lease Lease
+}
+
+func (e HeldError) Error() string {
+	return fmt.Sprintf("locked by %q since %s (lease expires %s)", e.Lease.Holder, e.Lease.Acquired.Format(time.RFC3339), e.Lease.Expires.Format(time.RFC3339))
+}
+
+// Locker acquires locks of a Store on behalf of a holder
+type Locker struct {
+	store        Store
+	holder       string
+	ttl          time.Duration
+	wait         time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+// Option configures a Locker
+type Option func(*Locker)
+
+// WithTTL sets the duration after which a lease expires if it is not renewed. Leases are renewed after half of it.
+func WithTTL(ttl time.Duration) Option {
+	return func(l *Locker) {
+		l.ttl = ttl
+	}
+}
+
+// WithWait sets the maximum duration to wait for a lock held by another deployment. By default, acquiring a held lock
+// fails immediately.
+func WithWait(wait time.Duration) Option {
+	return func(l *Locker) {
+		l.wait = wait
+	}
+}
+
+// WithPollInterval sets the interval in which a held lock is checked while waiting for it
+func WithPollInterval(interval time.Duration) Option {
+	return func(l *Locker) {
+		l.pollInterval = interval
+	}
+}
+
+// New returns a Locker acquiring the lock of the given store for the given holder. The holder must be unique for every
+// deployment, as it is used to detect whether a lock is owned.
+func New(store Store, holder string, opts ...Option) *Locker {
+	l := &Locker{
+		store:        store,
+		holder:       holder,
+		ttl:          DefaultTTL,
+		pollInterval: DefaultPollInterval,
+		now:          time.Now,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Acquire acquires the lock. If it is held by another deployment, Acquire waits up to the configured wait duration
+// for it to be released or to expire, and returns a HeldError otherwise. The returned Lock is renewed in the background
+// until it is released.
+func (l *Locker) Acquire(ctx context.Context) (*Lock, error) {
+	deadline := l.now().Add(l.wait)
+	for {
+		lease, revision, held, err := l.tryAcquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !held {
+			return l.newLock(lease, revision), nil
+		}
+
+		if !l.now().Add(l.pollInterval).Before(deadline) {
+			return nil, HeldError{Lease: lease}
+		}
+
+		log.WithCtxFields(ctx).Info("Waiting for lock held by %q", lease.Holder)
+		if err := timeutils.SleepWithContext(ctx, l.pollInterval); err != nil {
+			return nil, fmt.Errorf("cancelled while waiting for lock: %w", err)
+		}
+	}
+}
+
+// tryAcquire creates a new lease, unless a valid lease of another holder exists. It returns the lease created and its
+// revision, or the lease of the other holder and true.
+func (l *Locker) tryAcquire(ctx context.Context) (Lease, string, bool, error) {
+	for attempt := 1; ; attempt++ {
+		current, revision, found, err := l.store.Get(ctx)
+		if err != nil {
+			return Lease{}, "", false, fmt.Errorf("failed to read lock: %w", err)
+		}
+		if found && current.Holder != l.holder && l.now().Before(current.Expires) {
+			return current, "", true, nil
+		}
+
+		// an expired lease is removed by its revision, so that a lease created concurrently by another deployment
+		// taking it over as well is kept
+		if found {
+			if err := l.store.Delete(ctx, revision); err != nil {
+				return Lease{}, "", false, fmt.Errorf("failed to remove expired lock of %q: %w", current.Holder, err)
+			}
+		}
+
+		now := l.now()
+		lease := Lease{Holder: l.holder, Acquired: now, Expires: now.Add(l.ttl)}
+		revision, err = l.store.Create(ctx, lease)
+		if err == nil {
+			return lease, revision, false, nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			return Lease{}, "", false, fmt.Errorf("failed to write lock: %w", err)
+		}
+
+		// another deployment created its lease first, thus the lock is read again to check whether it is still held
+		if attempt == maxAcquireAttempts {
+			return Lease{}, "", false, fmt.Errorf("failed to write lock: %w", err)
+		}
+	}
+}
+
+func (l *Locker) newLock(lease Lease, revision string) *Lock {
+	lock := &Lock{locker: l, lease: lease, revision: revision, stop: make(chan struct{}), done: make(chan struct{}), lost: make(chan struct{})}
+	go lock.renew()
+	return lock
+}
+
+// Lock is an acquired lock
+type Lock struct {
+	locker   *Locker
+	revision string
+	stop     chan struct{}
+	done     chan struct{}
+	lost     chan struct{}
+	once     sync.Once
+
+	mu    sync.Mutex
+	lease Lease
+}
+
+// Lease returns the current lease of the lock
+func (lock *Lock) Lease() Lease {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	return lock.lease
+}
+
+// Lost returns a channel that is closed if the lock was taken over by another holder, e.g. because it could not be
+// renewed in time. The lock is not renewed anymore after it was lost.
+func (lock *Lock) Lost() <-chan struct{} {
+	return lock.lost
+}
+
+// renew extends the lease after half of its TTL until the lock is released or lost
+func (lock *Lock) renew() {
+	defer close(lock.done)
+	interval := lock.locker.ttl / 2
+	for {
+		select {
+		case <-lock.stop:
+			return
+		case <-time.After(interval):
+		}
+
+		lease := lock.Lease()
+		err := lock.renewLease(context.Background(), lease)
+		if errors.Is(err, ErrConflict) {
+			log.WithFields(field.Error(err)).Error("Lost lock held by %q: %v", lease.Holder, err)
+			close(lock.lost)
+			return
+		}
+		if err != nil {
+			log.WithFields(field.Error(err)).Warn("Failed to renew lock held by %q: %v", lease.Holder, err)
+		}
+	}
+}
+
+// renewLease extends the given lease, unless it was taken over by another holder, in which case ErrConflict is returned
+func (lock *Lock) renewLease(ctx context.Context, lease Lease) error {
+	current, revision, found, err := lock.locker.store.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read lock: %w", err)
+	}
+	if !found || revision != lock.revision || current.Holder != lock.locker.holder {
+		return fmt.Errorf("%w: lock was taken over by another deployment", ErrConflict)
+	}
+
+	lease.Expires = lock.locker.now().Add(lock.locker.ttl)
+	if err := lock.locker.store.Update(ctx, lock.revision, lease); err != nil {
+		return err
+	}
+
+	lock.mu.Lock()
+	lock.lease = lease
+	lock.mu.Unlock()
+	return nil
+}
+
+// Release stops renewing the lock and removes its lease, unless it was taken over by another holder after it
+// expired. Release may be called multiple times.
+func (lock *Lock) Release(ctx context.Context) error {
+	var err error
+	lock.once.Do(func() {
+		close(lock.stop)
+		<-lock.done
+
+		current, revision, found, getErr := lock.locker.store.Get(ctx)
+		if getErr != nil {
+			err = fmt.Errorf("failed to read lock: %w", getErr)
+			return
+		}
+		if !found {
+			return
+		}
+		if revision != lock.revision || current.Holder != lock.locker.holder {
+			log.WithCtxFields(ctx).Warn("Lock was taken over by %q before it was released", current.Holder)
+			return
+		}
+		if deleteErr := lock.locker.store.Delete(ctx, lock.revision); deleteErr != nil {
+			err = fmt.Errorf("failed to release lock: %w", deleteErr)
+		}
+	})
+	return err
+}
@@ -0,0 +1,265 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/lock"
+)
+
+// memoryStore is a lock.Store keeping the lease in memory. If beforeCreate or beforeDelete are set, they are called
+// once before the next Create or Delete, respectively, to simulate concurrent modifications.
+type memoryStore struct {
+	mu           sync.Mutex
+	lease        *lock.Lease
+	revision     int
+	beforeCreate func(s *memoryStore)
+	beforeDelete func(s *memoryStore)
+}
+
+func (s *memoryStore) Get(_ context.Context) (lock.Lease, string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lease == nil {
+		return lock.Lease{}, "", false, nil
+	}
+	return *s.lease, fmt.Sprint(s.revision), true, nil
+}
+
+func (s *memoryStore) Create(_ context.Context, lease lock.Lease) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.beforeCreate != nil {
+		s.beforeCreate(s)
+		s.beforeCreate = nil
+	}
+	if s.lease != nil {
+		return "", lock.ErrConflict
+	}
+	s.lease = &lease
+	s.revision++
+	return fmt.Sprint(s.revision), nil
+}
+
+func (s *memoryStore) Update(_ context.Context, revision string, lease lock.Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lease == nil || revision != fmt.Sprint(s.revision) {
+		return lock.ErrConflict
+	}
+	s.lease = &lease
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, revision string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.beforeDelete != nil {
+		s.beforeDelete(s)
+		s.beforeDelete = nil
+	}
+	if revision == fmt.Sprint(s.revision) {
+		s.lease = nil
+	}
+	return nil
+}
+
+// set replaces the lease with a new one, as if it was taken over by another holder
+func (s *memoryStore) set(lease *lock.Lease) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setLocked(lease)
+}
+
+func (s *memoryStore) setLocked(lease *lock.Lease) {
+	s.lease = lease
+	s.revision++
+}
+
+func heldByOther(expires time.Time) *lock.Lease {
+	return &lock.Lease{Holder: "other", Acquired: time.Now(), Expires: expires}
+}
+
+func TestAcquire_AcquiresAndReleasesFreeLock(t *testing.T) {
+	store := &memoryStore{}
+
+	l, err := lock.New(store, "me").Acquire(context.TODO())
+	require.NoError(t, err)
+
+	lease, _, found, _ := store.Get(context.TODO())
+	assert.True(t, found)
+	assert.Equal(t, "me", lease.Holder)
+	assert.Equal(t, lease, l.Lease())
+
+	assert.NoError(t, l.Release(context.TODO()))
+	assert.NoError(t, l.Release(context.TODO()), "releasing twice must not fail")
+
+	_, _, found, _ = store.Get(context.TODO())
+	assert.False(t, found)
+}
+
+func TestAcquire_FailsFastIfHeldByOther(t *testing.T) {
+	store := &memoryStore{lease: heldByOther(time.Now().Add(time.Hour))}
+
+	_, err := lock.New(store, "me").Acquire(context.TODO())
+
+	var heldErr lock.HeldError
+	require.ErrorAs(t, err, &heldErr)
+	assert.Equal(t, "other", heldErr.Lease.Holder)
+}
+
+func TestAcquire_TakesOverExpiredLease(t *testing.T) {
+	store := &memoryStore{lease: heldByOther(time.Now().Add(-time.Second))}
+
+	l, err := lock.New(store, "me").Acquire(context.TODO())
+	require.NoError(t, err)
+	defer l.Release(context.TODO())
+
+	assert.Equal(t, "me", l.Lease().Holder)
+}
+
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	store := &memoryStore{lease: heldByOther(time.Now().Add(time.Hour))}
+	time.AfterFunc(20*time.Millisecond, func() { store.set(nil) })
+
+	l, err := lock.New(store, "me", lock.WithWait(time.Second), lock.WithPollInterval(5*time.Millisecond)).Acquire(context.TODO())
+	require.NoError(t, err)
+	defer l.Release(context.TODO())
+
+	assert.Equal(t, "me", l.Lease().Holder)
+}
+
+func TestAcquire_GivesUpWaitingAfterWaitDuration(t *testing.T) {
+	store := &memoryStore{lease: heldByOther(time.Now().Add(time.Hour))}
+
+	_, err := lock.New(store, "me", lock.WithWait(20*time.Millisecond), lock.WithPollInterval(5*time.Millisecond)).Acquire(context.TODO())
+
+	assert.ErrorAs(t, err, &lock.HeldError{})
+}
+
+func TestAcquire_StopsWaitingIfContextIsCancelled(t *testing.T) {
+	store := &memoryStore{lease: heldByOther(time.Now().Add(time.Hour))}
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	_, err := lock.New(store, "me", lock.WithWait(time.Hour), lock.WithPollInterval(time.Minute)).Acquire(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAcquire_DetectsConcurrentlyCreatedLease(t *testing.T) {
+	store := &memoryStore{}
+	store.beforeCreate = func(s *memoryStore) {
+		s.setLocked(heldByOther(time.Now().Add(time.Hour)))
+	}
+
+	_, err := lock.New(store, "me").Acquire(context.TODO())
+
+	var heldErr lock.HeldError
+	require.ErrorAs(t, err, &heldErr)
+	assert.Equal(t, "other", heldErr.Lease.Holder)
+}
+
+func TestAcquire_KeepsLeaseOfConcurrentTakeOver(t *testing.T) {
+	store := &memoryStore{lease: heldByOther(time.Now().Add(-time.Second))}
+	store.beforeDelete = func(s *memoryStore) {
+		taken := heldByOther(time.Now().Add(time.Hour))
+		taken.Holder = "taker"
+		s.setLocked(taken)
+	}
+
+	_, err := lock.New(store, "me").Acquire(context.TODO())
+
+	var heldErr lock.HeldError
+	require.ErrorAs(t, err, &heldErr)
+	assert.Equal(t, "taker", heldErr.Lease.Holder)
+	lease, _, _, _ := store.Get(context.TODO())
+	assert.Equal(t, "taker", lease.Holder)
+}
+
+func TestLock_RenewsLease(t *testing.T) {
+	store := &memoryStore{}
+
+	l, err := lock.New(store, "me", lock.WithTTL(20*time.Millisecond)).Acquire(context.TODO())
+	require.NoError(t, err)
+	defer l.Release(context.TODO())
+	initial := l.Lease()
+
+	assert.Eventually(t, func() bool {
+		return l.Lease().Expires.After(initial.Expires)
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, initial.Acquired, l.Lease().Acquired)
+}
+
+func TestLock_StopsRenewingLeaseTakenOverByOther(t *testing.T) {
+	store := &memoryStore{}
+
+	l, err := lock.New(store, "me", lock.WithTTL(20*time.Millisecond)).Acquire(context.TODO())
+	require.NoError(t, err)
+	defer l.Release(context.TODO())
+	store.set(heldByOther(time.Now().Add(time.Hour)))
+
+	select {
+	case <-l.Lost():
+	case <-time.After(time.Second):
+		assert.Fail(t, "lock was not lost")
+	}
+	lease, _, _, _ := store.Get(context.TODO())
+	assert.Equal(t, "other", lease.Holder)
+}
+
+func TestRelease_KeepsLeaseTakenOverByOther(t *testing.T) {
+	store := &memoryStore{}
+
+	l, err := lock.New(store, "me").Acquire(context.TODO())
+	require.NoError(t, err)
+	store.set(heldByOther(time.Now().Add(time.Hour)))
+
+	assert.NoError(t, l.Release(context.TODO()))
+
+	lease, _, found, _ := store.Get(context.TODO())
+	assert.True(t, found)
+	assert.Equal(t, "other", lease.Holder)
+}
+
+func TestRelease_ReturnsStoreErrors(t *testing.T) {
+	store := &failingDeleteStore{}
+
+	l, err := lock.New(store, "me").Acquire(context.TODO())
+	require.NoError(t, err)
+
+	assert.Error(t, l.Release(context.TODO()))
+}
+
+type failingDeleteStore struct {
+	memoryStore
+}
+
+func (s *failingDeleteStore) Delete(_ context.Context, _ string) error {
+	return errors.New("delete failed")
+}
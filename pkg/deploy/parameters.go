@@ -0,0 +1,125 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	secretParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
+)
+
+// ResolvedParameters collects the resolved parameter values of every deployed config, so that they can be inspected
+// after a dry-run. Values of secret parameters and all registered sensitive values are masked.
+type ResolvedParameters struct {
+	mutex sync.Mutex
+	// values maps environment names to config coordinates to the masked parameter values of the config
+	values map[string]map[string]map[string]any
+}
+
+// NewResolvedParameters returns an empty [ResolvedParameters] collection
+func NewResolvedParameters() *ResolvedParameters {
+	return &ResolvedParameters{values: make(map[string]map[string]map[string]any)}
+}
+
+// add stores the masked parameter values of the given config
+func (r *ResolvedParameters) add(c *config.Config, masked map[string]any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.values[c.Environment] == nil {
+		r.values[c.Environment] = make(map[string]map[string]any)
+	}
+	r.values[c.Environment][c.Coordinate.String()] = masked
+}
+
+// Get returns the masked parameter values of the config with the given coordinate in the given environment
+func (r *ResolvedParameters) Get(environment string, coordinate string) (map[string]any, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	v, ok := r.values[environment][coordinate]
+	return v, ok
+}
+
+// MarshalJSON returns the collected parameter values as JSON object of environments, holding an object of config
+// coordinates and their parameter values each
+func (r *ResolvedParameters) MarshalJSON() ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return json.Marshal(r.values)
+}
+
+// recordResolvedParameters masks the resolved parameter values of the given config, adds them to the given collection
+// if set, and logs them at debug level during a dry-run
+func recordResolvedParameters(ctx context.Context, c *config.Config, properties parameter.Properties, opts configDeployOptions) {
+	if opts.resolvedParameters == nil && !opts.dryRun {
+		return
+	}
+
+	masked := maskParameters(c, properties)
+	if opts.resolvedParameters != nil {
+		opts.resolvedParameters.add(c, masked)
+	}
+	if opts.dryRun {
+		b, err := json.Marshal(masked)
+		if err != nil {
+			log.WithCtxFields(ctx).WithFields(field.Error(err)).Debug("Failed to marshal resolved parameters: %v", err)
+			return
+		}
+		log.WithCtxFields(ctx).Debug("Resolved parameters: %s", b)
+	}
+}
+
+// maskParameters returns a copy of the given parameter values. Values of secret parameters are replaced entirely,
+// while all other string values only have the sensitive values registered with [secret.RegisterMaskedValue] masked.
+func maskParameters(c *config.Config, properties parameter.Properties) map[string]any {
+	masked := make(map[string]any, len(properties))
+	for name, value := range properties {
+		if p, ok := c.Parameters[name]; ok && p.GetType() == secretParam.SecretParameterType {
+			masked[name] = secret.MaskedString("").String()
+			continue
+		}
+		masked[name] = maskValue(value)
+	}
+	return masked
+}
+
+func maskValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return secret.Mask(v)
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = maskValue(e)
+		}
+		return m
+	case []any:
+		l := make([]any, len(v))
+		for i, e := range v {
+			l[i] = maskValue(e)
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/policy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
+)
+
+// unresolvedPlaceholder replaces the values of references that are only known once the referenced config is deployed
+const unresolvedPlaceholder = "<resolved during deployment>"
+
+// policyViolations holds the policy violation errors of configs, by environment and coordinate
+type policyViolations map[string]map[coordinate.Coordinate]error
+
+func (v policyViolations) add(environment string, c coordinate.Coordinate, err error) {
+	if v[environment] == nil {
+		v[environment] = make(map[coordinate.Coordinate]error)
+	}
+	v[environment][c] = err
+}
+
+// get returns the policy violation error of the given config, if it violates any policy
+func (v policyViolations) get(c *config.Config) error {
+	return v[c.Environment][c.Coordinate]
+}
+
+// checkPolicies renders all configs of the given graphs and checks them against the evaluator, so that policy
+// violations are reported before any config is deployed. The IDs of referenced objects, and any other properties
+// which are only known once the referenced config is deployed, are rendered as placeholder.
+// Configs which fail to resolve or render are not checked, as their deployment fails anyway.
+func checkPolicies(ctx context.Context, g graph.ConfigGraphPerEnvironment, environments []string, valueCache *parameter.ValueCache, evaluator policy.Evaluator) (policyViolations, error) {
+	violations := make(policyViolations)
+	for _, env := range environments {
+		sortedConfigs, err := g.SortConfigs(env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sort configs of environment %q: %w", env, err)
+		}
+
+		lookup := placeholderLookup{EntityMap: entities.New(), valueCache: valueCache}
+		for i := range sortedConfigs {
+			c := &sortedConfigs[i]
+			if c.Skip {
+				lookup.Put(entities.ResolvedEntity{Coordinate: c.Coordinate, Skip: true})
+				continue
+			}
+
+			properties, errs := c.ResolveParameterValues(lookup)
+			if len(errs) > 0 {
+				continue
+			}
+			rendered, err := c.Render(properties)
+			if err != nil {
+				continue
+			}
+
+			if _, found := properties[config.IdParameter]; !found {
+				properties[config.IdParameter] = unresolvedPlaceholder
+			}
+			lookup.Put(entities.ResolvedEntity{Coordinate: c.Coordinate, Properties: properties})
+
+			if err := policy.Check(ctx, evaluator, c.Coordinate, []byte(rendered)); err != nil {
+				log.WithFields(field.Environment(env, c.Group), field.Coordinate(c.Coordinate), field.Error(err)).Error("Invalid configuration - policy check failed: %v", err)
+				violations.add(env, c.Coordinate, err)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// placeholderLookup resolves references to configs it holds to their resolved properties, and references to any other
+// config, including configs of other environments, to a placeholder
+type placeholderLookup struct {
+	*entities.EntityMap
+	valueCache *parameter.ValueCache
+}
+
+var (
+	_ config.EntityLookup                   = placeholderLookup{}
+	_ parameter.EnvironmentPropertyResolver = placeholderLookup{}
+	_ parameter.ValueCacheProvider          = placeholderLookup{}
+)
+
+func (l placeholderLookup) GetResolvedEntity(c coordinate.Coordinate) (entities.ResolvedEntity, bool) {
+	if e, found := l.EntityMap.GetResolvedEntity(c); found {
+		return e, true
+	}
+	return entities.ResolvedEntity{Coordinate: c}, true
+}
+
+func (l placeholderLookup) GetResolvedProperty(c coordinate.Coordinate, propertyName string) (any, bool) {
+	if v, found := l.EntityMap.GetResolvedProperty(c, propertyName); found {
+		return v, true
+	}
+	return unresolvedPlaceholder, true
+}
+
+func (l placeholderLookup) GetResolvedPropertyOfEnvironment(string, coordinate.Coordinate, string) (any, error) {
+	return unresolvedPlaceholder, nil
+}
+
+func (l placeholderLookup) ParameterValueCache() *parameter.ValueCache {
+	return l.valueCache
+}
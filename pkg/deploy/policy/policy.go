@@ -0,0 +1,73 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package policy checks rendered configuration payloads against policies before they are deployed.
+// Policies are evaluated by an Evaluator. Monaco ships a built-in rule format, see RuleSet; other policy engines, like
+// OPA, can be integrated by implementing Evaluator.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+)
+
+// Evaluator checks the rendered payload of a configuration against policies.
+type Evaluator interface {
+	// Evaluate returns all policy violations of the given payload of the config with the given coordinate. An error is
+	// only returned if the policies could not be evaluated.
+	Evaluate(ctx context.Context, c coordinate.Coordinate, payload []byte) ([]Violation, error)
+}
+
+// Violation describes a single policy a payload does not comply with
+type Violation struct {
+	// Policy is the name of the violated policy
+	Policy string
+	// Message describes why the payload violates the policy
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Policy, v.Message)
+}
+
+// ViolationError is returned if the payload of a config violates at least one policy
+type ViolationError struct {
+	Coordinate coordinate.Coordinate
+	Violations []Violation
+}
+
+func (e ViolationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		violations[i] = v.String()
+	}
+	return fmt.Sprintf("config %s violates %d policies: %s", e.Coordinate, len(e.Violations), strings.Join(violations, "; "))
+}
+
+// Check evaluates the given payload using the Evaluator and returns a ViolationError if any policy is violated.
+func Check(ctx context.Context, e Evaluator, c coordinate.Coordinate, payload []byte) error {
+	violations, err := e.Evaluate(ctx, c, payload)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policies for config %s: %w", c, err)
+	}
+	if len(violations) > 0 {
+		return ViolationError{Coordinate: c, Violations: violations}
+	}
+	return nil
+}
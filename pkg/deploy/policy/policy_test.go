@@ -0,0 +1,166 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/policy"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRules = `
+rules:
+  - name: no-public-dashboards
+    description: dashboards must not be shared publicly
+    types: [dashboard]
+    path: dashboardMetadata.shared
+    forbidden: [true]
+  - name: owner-tag
+    types: ["builtin:alerting.*"]
+    path: tags
+    required: true
+    pattern: "^owner:"
+  - name: known-severity
+    path: rules.severity
+    allowed: [ERROR, WARNING]
+`
+
+func loadTestRules(t *testing.T, content string) (*policy.RuleSet, error) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "policy.yaml", []byte(content), 0644))
+	return policy.LoadRules(fs, "policy.yaml")
+}
+
+func TestRuleSet_Evaluate(t *testing.T) {
+	rules, err := loadTestRules(t, testRules)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		configType string
+		payload    string
+		want       []policy.Violation
+	}{
+		{
+			name:       "private dashboard",
+			configType: "dashboard",
+			payload:    `{"dashboardMetadata": {"shared": false}}`,
+		},
+		{
+			name:       "public dashboard",
+			configType: "dashboard",
+			payload:    `{"dashboardMetadata": {"shared": true}}`,
+			want:       []policy.Violation{{Policy: "no-public-dashboards", Message: "dashboards must not be shared publicly"}},
+		},
+		{
+			name:       "rule of other type is ignored",
+			configType: "notification",
+			payload:    `{"dashboardMetadata": {"shared": true}}`,
+		},
+		{
+			name:       "owner tag present",
+			configType: "builtin:alerting.profile",
+			payload:    `{"tags": ["team:a", "owner:jane"]}`,
+		},
+		{
+			name:       "owner tag missing",
+			configType: "builtin:alerting.profile",
+			payload:    `{"tags": ["team:a"]}`,
+			want:       []policy.Violation{{Policy: "owner-tag", Message: `no value of "tags" matches "^owner:"`}},
+		},
+		{
+			name:       "tags missing",
+			configType: "builtin:alerting.profile",
+			payload:    `{}`,
+			want:       []policy.Violation{{Policy: "owner-tag", Message: `"tags" is required`}},
+		},
+		{
+			name:       "values in arrays are checked",
+			configType: "notification",
+			payload:    `{"rules": [{"severity": "ERROR"}, {"severity": "INFO"}]}`,
+			want:       []policy.Violation{{Policy: "known-severity", Message: `"rules.severity" must be one of [ERROR WARNING], but is INFO`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rules.Evaluate(context.TODO(), coordinate.Coordinate{Project: "p", Type: tt.configType, ConfigId: "c"}, []byte(tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRuleSet_Evaluate_DoesNotParsePayloadsWithoutApplyingRules(t *testing.T) {
+	rules, err := loadTestRules(t, `
+rules:
+  - name: no-public-dashboards
+    types: [dashboard]
+    path: dashboardMetadata.shared
+    forbidden: [true]
+`)
+	require.NoError(t, err)
+
+	got, err := rules.Evaluate(context.TODO(), coordinate.Coordinate{Project: "p", Type: "notification", ConfigId: "c"}, []byte("not JSON"))
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = rules.Evaluate(context.TODO(), coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "c"}, []byte("not JSON"))
+	assert.Error(t, err)
+}
+
+func TestLoadRules_FailsOnInvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing name", "rules: [{path: a, required: true}]", "missing name"},
+		{"missing path", "rules: [{name: r, required: true}]", "missing path"},
+		{"missing condition", "rules: [{name: r, path: a}]", "at least one of"},
+		{"invalid pattern", "rules: [{name: r, path: a, pattern: '('}]", "invalid pattern"},
+		{"non-scalar value", "rules: [{name: r, path: a, forbidden: [{a: b}]}]", "only scalar values"},
+		{"unknown property", "rules: [{name: r, path: a, required: true, unknown: 1}]", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestRules(t, tt.content)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules, err := loadTestRules(t, testRules)
+	require.NoError(t, err)
+
+	coord := coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "c"}
+	assert.NoError(t, policy.Check(context.TODO(), rules, coord, []byte(`{}`)))
+
+	err = policy.Check(context.TODO(), rules, coord, []byte(`{"dashboardMetadata": {"shared": true}}`))
+	var violationErr policy.ViolationError
+	require.ErrorAs(t, err, &violationErr)
+	assert.Equal(t, coord, violationErr.Coordinate)
+	assert.ErrorContains(t, err, "config p:dashboard:c violates 1 policies: no-public-dashboards")
+}
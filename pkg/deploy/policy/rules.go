@@ -0,0 +1,242 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule is a built-in policy checking the value(s) at one path of a payload.
+//
+// The path is a dot-separated list of object keys, e.g. "dashboardMetadata.shared". If the path traverses an array, the
+// values of all its elements are checked, e.g. "tags" checks every tag of a list of tags.
+type Rule struct {
+	// Name identifies the rule in violations
+	Name string `yaml:"name"`
+	// Description is reported instead of the default message if the rule is violated
+	Description string `yaml:"description,omitempty"`
+	// Types restricts the rule to the given config types, e.g. "dashboard" or "builtin:alerting.profile". Supports
+	// wildcards. If empty, the rule applies to all configs.
+	Types []string `yaml:"types,omitempty"`
+	// Path of the checked value(s) in the payload
+	Path string `yaml:"path"`
+	// Required states that the path must exist in the payload
+	Required bool `yaml:"required,omitempty"`
+	// Allowed lists the only values that are allowed at the path
+	Allowed []any `yaml:"allowed,omitempty"`
+	// Forbidden lists values that are not allowed at the path
+	Forbidden []any `yaml:"forbidden,omitempty"`
+	// Pattern is a regular expression that at least one value at the path must match
+	Pattern string `yaml:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// RuleSet is an Evaluator checking payloads against built-in Rules
+type RuleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+var _ Evaluator = (*RuleSet)(nil)
+
+// LoadRules reads a RuleSet from the given YAML file, e.g.:
+//
+//	rules:
+//	  - name: no-public-dashboards
+//	    types: [dashboard]
+//	    path: dashboardMetadata.shared
+//	    forbidden: [true]
+//	  - name: alerting-profile-owner
+//	    types: [builtin:alerting.profile]
+//	    path: tags
+//	    required: true
+//	    pattern: "^owner:"
+func LoadRules(fs afero.Fs, file string) (*RuleSet, error) {
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file %q: %w", file, err)
+	}
+
+	var rs RuleSet
+	if err := yaml.UnmarshalStrict(data, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %q: %w", file, err)
+	}
+
+	var errs []error
+	for i := range rs.Rules {
+		if err := rs.Rules[i].init(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid rule %d of policy file %q: %w", i, file, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &rs, nil
+}
+
+func (r *Rule) init() error {
+	if r.Name == "" {
+		return errors.New("missing name")
+	}
+	if r.Path == "" {
+		return fmt.Errorf("rule %q: missing path", r.Name)
+	}
+	for _, t := range r.Types {
+		if _, err := path.Match(t, ""); err != nil {
+			return fmt.Errorf("rule %q: invalid type pattern %q: %w", r.Name, t, err)
+		}
+	}
+	if !r.Required && len(r.Allowed) == 0 && len(r.Forbidden) == 0 && r.Pattern == "" {
+		return fmt.Errorf("rule %q: at least one of 'required', 'allowed', 'forbidden', or 'pattern' must be set", r.Name)
+	}
+
+	var err error
+	if r.Allowed, err = normalizeValues(r.Allowed); err != nil {
+		return fmt.Errorf("rule %q: invalid allowed value: %w", r.Name, err)
+	}
+	if r.Forbidden, err = normalizeValues(r.Forbidden); err != nil {
+		return fmt.Errorf("rule %q: invalid forbidden value: %w", r.Name, err)
+	}
+	if r.Pattern != "" {
+		if r.pattern, err = regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("rule %q: invalid pattern: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate checks the payload against all rules applying to the type of the given config. Payloads of configs no rule
+// applies to are not parsed.
+func (rs *RuleSet) Evaluate(_ context.Context, c coordinate.Coordinate, payload []byte) ([]Violation, error) {
+	var rules []Rule
+	for _, r := range rs.Rules {
+		if r.appliesTo(c.Type) {
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	var obj any
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	var violations []Violation
+	for _, r := range rules {
+		if msg, ok := r.check(obj); !ok {
+			if r.Description != "" {
+				msg = r.Description
+			}
+			violations = append(violations, Violation{Policy: r.Name, Message: msg})
+		}
+	}
+	return violations, nil
+}
+
+func (r *Rule) appliesTo(configType string) bool {
+	if len(r.Types) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(r.Types, func(t string) bool {
+		matched, _ := path.Match(t, configType)
+		return matched
+	})
+}
+
+// check returns whether the payload complies with the rule, and if it does not, why
+func (r *Rule) check(obj any) (string, bool) {
+	values := lookup(obj, strings.Split(r.Path, "."))
+	if len(values) == 0 {
+		if r.Required {
+			return fmt.Sprintf("%q is required", r.Path), false
+		}
+		return "", true
+	}
+
+	for _, v := range values {
+		if len(r.Allowed) > 0 && !slices.Contains(r.Allowed, v) {
+			return fmt.Sprintf("%q must be one of %v, but is %v", r.Path, r.Allowed, v), false
+		}
+		if slices.Contains(r.Forbidden, v) {
+			return fmt.Sprintf("%q must not be %v", r.Path, v), false
+		}
+	}
+
+	if r.pattern != nil && !slices.ContainsFunc(values, func(v any) bool {
+		s, ok := v.(string)
+		return ok && r.pattern.MatchString(s)
+	}) {
+		return fmt.Sprintf("no value of %q matches %q", r.Path, r.Pattern), false
+	}
+	return "", true
+}
+
+// lookup returns all scalar values found at the given path. Arrays are traversed by continuing the lookup for each of
+// their elements.
+func lookup(v any, keys []string) []any {
+	if arr, ok := v.([]any); ok {
+		var result []any
+		for _, e := range arr {
+			result = append(result, lookup(e, keys)...)
+		}
+		return result
+	}
+
+	if len(keys) == 0 {
+		return []any{v}
+	}
+
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	child, found := obj[keys[0]]
+	if !found {
+		return nil
+	}
+	return lookup(child, keys[1:])
+}
+
+// normalizeValues converts the scalar values parsed from YAML to the types values parsed from JSON have, so that they
+// can be compared
+func normalizeValues(values []any) ([]any, error) {
+	result := make([]any, len(values))
+	for i, v := range values {
+		switch t := v.(type) {
+		case int:
+			result[i] = float64(t)
+		case float64, string, bool, nil:
+			result[i] = t
+		default:
+			return nil, fmt.Errorf("only scalar values are supported, but got %v", v)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// marshalJUnit renders the summary as JUnit XML report. Every environment is a test suite, every configuration a test
+// case named after its coordinate, with its project and type as class name.
+func (s *Summary) marshalJUnit() ([]byte, error) {
+	suites := junitTestSuites{Name: "monaco deploy"}
+	var total time.Duration
+
+	var suite *junitTestSuite
+	var suiteTime time.Duration
+	for _, r := range s.Records() {
+		if suite == nil || suite.Name != r.Environment {
+			if suite != nil {
+				suite.Time = junitTime(suiteTime)
+			}
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: r.Environment})
+			suite = &suites.Suites[len(suites.Suites)-1]
+			suiteTime = 0
+		}
+
+		tc := junitTestCase{
+			Name:      r.Config.String(),
+			ClassName: fmt.Sprintf("%s.%s", r.Config.Project, r.Config.Type),
+			Time:      junitTime(r.Duration),
+		}
+		switch r.State {
+		case StateFailed:
+			tc.Failure = &junitMessage{Message: "deployment failed", Text: r.Error}
+			suite.Failures++
+		case StateSkipped:
+			tc.Skipped = &junitMessage{Message: r.Error}
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, tc)
+		suiteTime += r.Duration
+		total += r.Duration
+	}
+	if suite != nil {
+		suite.Time = junitTime(suiteTime)
+	}
+
+	for _, ts := range suites.Suites {
+		suites.Tests += ts.Tests
+		suites.Failures += ts.Failures
+		suites.Skipped += ts.Skipped
+	}
+	suites.Time = junitTime(total)
+
+	content, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,217 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"golang.org/x/exp/maps"
+)
+
+// DefaultProgressInterval is the default minimum time between two progress log messages
+const DefaultProgressInterval = 10 * time.Second
+
+// maxProgressTypes is the maximum number of config types whose throughput is logged
+const maxProgressTypes = 3
+
+// Event is written to the event stream of a Progress, one JSON object per line
+type Event struct {
+	Time time.Time `json:"time"`
+	// Event is the kind of event: "planned" before the deployment (of each stage), "config" for every configuration,
+	// and "finished" once after the deployment
+	Event string `json:"event"`
+
+	Environment     string                 `json:"environment,omitempty"`
+	Config          *coordinate.Coordinate `json:"config,omitempty"`
+	State           State                  `json:"state,omitempty"`
+	Error           string                 `json:"error,omitempty"`
+	DurationSeconds float64                `json:"durationSeconds,omitempty"`
+
+	// Completed is the number of configurations whose deployment finished so far, regardless of its outcome
+	Completed int `json:"completed"`
+	// Total is the number of configurations going to be deployed, including those of all stages planned so far
+	Total int `json:"total"`
+	// ETASeconds is the estimated time until the deployment of all configurations finished
+	ETASeconds float64 `json:"etaSeconds,omitempty"`
+	// ElapsedSeconds is the time since the deployment started
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// ProgressOption configures a Progress
+type ProgressOption func(*Progress)
+
+// WithProgressInterval sets the minimum time between two progress log messages
+func WithProgressInterval(d time.Duration) ProgressOption {
+	return func(p *Progress) {
+		p.interval = d
+	}
+}
+
+// WithEvents writes an Event for every reported deployment to the given writer, as newline-delimited JSON
+func WithEvents(w io.Writer) ProgressOption {
+	return func(p *Progress) {
+		p.events = w
+	}
+}
+
+// withClock replaces the clock of a Progress, for testing
+func withClock(now func() time.Time) ProgressOption {
+	return func(p *Progress) {
+		p.now = now
+	}
+}
+
+// Progress is a Reporter tracking how many of all planned configurations were deployed. It regularly logs the
+// progress, the throughput per config type, and the estimated remaining time, and optionally writes every reported
+// deployment to an event stream. It is safe for concurrent use.
+type Progress struct {
+	interval time.Duration
+	events   io.Writer
+	now      func() time.Time
+
+	mutex     sync.Mutex
+	start     time.Time
+	lastLog   time.Time
+	total     int
+	completed int
+	perType   map[string]int
+	eventErr  error
+}
+
+var _ Reporter = (*Progress)(nil)
+var _ Planner = (*Progress)(nil)
+
+// NewProgress creates a Progress starting now
+func NewProgress(opts ...ProgressOption) *Progress {
+	p := &Progress{
+		interval: DefaultProgressInterval,
+		now:      time.Now,
+		perType:  map[string]int{},
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	p.start = p.now()
+	p.lastLog = p.start
+	return p
+}
+
+func (p *Progress) ReportPlanned(total int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.total += total
+	p.writeEvent(Event{Event: "planned"})
+}
+
+func (p *Progress) ReportDeployment(ctx context.Context, c coordinate.Coordinate, state State, duration time.Duration, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.completed++
+	p.perType[c.Type]++
+
+	e := Event{Event: "config", Config: &c, State: state, DurationSeconds: duration.Seconds()}
+	if env, ok := ctx.Value(log.CtxKeyEnv{}).(log.CtxValEnv); ok {
+		e.Environment = env.Name
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	p.writeEvent(e)
+
+	if now := p.now(); now.Sub(p.lastLog) >= p.interval {
+		p.lastLog = now
+		p.logProgress(now)
+	}
+}
+
+// Finish writes the final event to the event stream. It returns the first error writing any event, if any.
+func (p *Progress) Finish() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.writeEvent(Event{Event: "finished"})
+	return p.eventErr
+}
+
+// writeEvent completes the given event with the current progress and writes it to the event stream, if any. Writing
+// stops at the first error.
+func (p *Progress) writeEvent(e Event) {
+	if p.events == nil || p.eventErr != nil {
+		return
+	}
+
+	now := p.now()
+	e.Time = now
+	e.Completed = p.completed
+	e.Total = p.total
+	e.ElapsedSeconds = now.Sub(p.start).Seconds()
+	e.ETASeconds = p.eta(now).Seconds()
+
+	line, err := json.Marshal(e)
+	if err == nil {
+		_, err = p.events.Write(append(line, '\n'))
+	}
+	if err != nil {
+		p.eventErr = fmt.Errorf("failed to write deployment event: %w", err)
+		log.WithFields(field.Error(err)).Warn("Failed to write deployment event, no further events are written: %v", err)
+	}
+}
+
+// eta estimates the time remaining until all planned configurations are deployed, based on the throughput so far
+func (p *Progress) eta(now time.Time) time.Duration {
+	if p.completed == 0 || p.completed >= p.total {
+		return 0
+	}
+	perConfig := now.Sub(p.start) / time.Duration(p.completed)
+	return perConfig * time.Duration(p.total-p.completed)
+}
+
+func (p *Progress) logProgress(now time.Time) {
+	elapsed := now.Sub(p.start)
+	if elapsed <= 0 {
+		return
+	}
+
+	// only the most frequent types are logged, as deployments may consist of hundreds of types
+	types := maps.Keys(p.perType)
+	slices.SortFunc(types, func(a, b string) int {
+		return cmp.Or(cmp.Compare(p.perType[b], p.perType[a]), cmp.Compare(a, b))
+	})
+	throughput := make([]string, 0, maxProgressTypes)
+	for _, t := range types[:min(len(types), maxProgressTypes)] {
+		throughput = append(throughput, fmt.Sprintf("%s: %.1f/s", t, float64(p.perType[t])/elapsed.Seconds()))
+	}
+
+	msg := fmt.Sprintf("Progress: %d/%d configurations (%.1f/s)", p.completed, p.total, float64(p.completed)/elapsed.Seconds())
+	if eta := p.eta(now); eta > 0 {
+		msg += fmt.Sprintf(", about %s remaining", eta.Round(time.Second))
+	}
+	log.Info("%s - %s", msg, strings.Join(throughput, ", "))
+}
@@ -0,0 +1,115 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestProgress_WritesEvents(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	var events bytes.Buffer
+	p := NewProgress(WithEvents(&events), withClock(clock.Now))
+	ctx := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: "env"})
+
+	p.ReportPlanned(4)
+	clock.now = clock.now.Add(10 * time.Second)
+	p.ReportDeployment(ctx, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}, StateDeployed, time.Second, nil)
+	clock.now = clock.now.Add(10 * time.Second)
+	p.ReportDeployment(ctx, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "b"}, StateFailed, 2*time.Second, errors.New("HTTP 400"))
+	require.NoError(t, p.Finish())
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	require.Len(t, lines, 4)
+
+	var parsed []Event
+	for _, l := range lines {
+		var e Event
+		require.NoError(t, json.Unmarshal([]byte(l), &e))
+		parsed = append(parsed, e)
+	}
+
+	assert.Equal(t, "planned", parsed[0].Event)
+	assert.Equal(t, 4, parsed[0].Total)
+
+	assert.Equal(t, "config", parsed[1].Event)
+	assert.Equal(t, "env", parsed[1].Environment)
+	assert.Equal(t, &coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}, parsed[1].Config)
+	assert.Equal(t, StateDeployed, parsed[1].State)
+	assert.Equal(t, 1, parsed[1].Completed)
+	assert.Equal(t, 30.0, parsed[1].ETASeconds, "3 remaining configs at 10s per config")
+
+	assert.Equal(t, StateFailed, parsed[2].State)
+	assert.Equal(t, "HTTP 400", parsed[2].Error)
+	assert.Equal(t, 2, parsed[2].Completed)
+	assert.Equal(t, 20.0, parsed[2].ETASeconds)
+	assert.Equal(t, 20.0, parsed[2].ElapsedSeconds)
+
+	assert.Equal(t, "finished", parsed[3].Event)
+	assert.Equal(t, 2, parsed[3].Completed)
+}
+
+func TestProgress_PlannedTotalsOfStagesAddUp(t *testing.T) {
+	var events bytes.Buffer
+	p := NewProgress(WithEvents(&events))
+
+	Multi(NewSummary(), p).(Planner).ReportPlanned(2)
+	ReportPlanned(p, 3)
+
+	assert.Equal(t, 5, p.total)
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestProgress_StopsWritingEventsAfterError(t *testing.T) {
+	w := &failingWriter{}
+	p := NewProgress(WithEvents(w))
+
+	p.ReportPlanned(1)
+	p.ReportDeployment(context.TODO(), coordinate.Coordinate{}, StateDeployed, 0, nil)
+
+	assert.ErrorContains(t, p.Finish(), "disk full")
+	assert.Equal(t, 1, w.writes)
+}
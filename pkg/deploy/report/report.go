@@ -0,0 +1,232 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package report records the outcome of the deployment of every single configuration, so that it can be presented in
+// machine-readable form after a deployment.
+package report
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+)
+
+// State is the outcome of the deployment of a single configuration
+type State string
+
+const (
+	// StateDeployed means the configuration was deployed successfully
+	StateDeployed State = "deployed"
+	// StateFailed means the deployment of the configuration failed
+	StateFailed State = "failed"
+	// StateSkipped means the configuration was not deployed, either because it is marked as skipped, or because one
+	// of its dependencies was not deployed
+	StateSkipped State = "skipped"
+)
+
+// Reporter is notified about the outcome of the deployment of every configuration
+type Reporter interface {
+	// ReportDeployment records the outcome of the deployment of the configuration with the given coordinate, and how
+	// long the deployment took. The environment is taken from the context.
+	ReportDeployment(ctx context.Context, c coordinate.Coordinate, state State, duration time.Duration, err error)
+}
+
+// Planner is implemented by Reporters that need to know how many configurations are going to be deployed in total,
+// e.g. to show the progress of the deployment
+type Planner interface {
+	// ReportPlanned records that the given number of configurations is going to be deployed to all environments. It
+	// is called before the deployment starts, and again before every further stage of a staged deployment.
+	ReportPlanned(total int)
+}
+
+// ReportPlanned notifies the given Reporter about the number of configurations going to be deployed, if it is a Planner
+func ReportPlanned(r Reporter, total int) {
+	if p, ok := r.(Planner); ok {
+		p.ReportPlanned(total)
+	}
+}
+
+// Multi returns a Reporter forwarding everything to all given Reporters
+func Multi(reporters ...Reporter) Reporter {
+	return multi(reporters)
+}
+
+type multi []Reporter
+
+var _ Planner = multi{}
+
+func (m multi) ReportDeployment(ctx context.Context, c coordinate.Coordinate, state State, duration time.Duration, err error) {
+	for _, r := range m {
+		r.ReportDeployment(ctx, c, state, duration, err)
+	}
+}
+
+func (m multi) ReportPlanned(total int) {
+	for _, r := range m {
+		ReportPlanned(r, total)
+	}
+}
+
+type ctxKeyReporter struct{}
+
+// NewContextWithReporter returns a copy of ctx carrying the given Reporter
+func NewContextWithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, ctxKeyReporter{}, r)
+}
+
+// GetReporterFromContextOrDiscard returns the Reporter carried by ctx, or a Reporter discarding everything if there is none
+func GetReporterFromContextOrDiscard(ctx context.Context) Reporter {
+	if r, ok := ctx.Value(ctxKeyReporter{}).(Reporter); ok && r != nil {
+		return r
+	}
+	return discard{}
+}
+
+type discard struct{}
+
+func (discard) ReportDeployment(context.Context, coordinate.Coordinate, State, time.Duration, error) {
+}
+
+// Record is the outcome of the deployment of one configuration to one environment
+type Record struct {
+	Environment string                `json:"environment"`
+	Config      coordinate.Coordinate `json:"config"`
+	State       State                 `json:"state"`
+	Error       string                `json:"error,omitempty"`
+	// Duration is how long the deployment took. It is written to JSON as fractional 'durationSeconds'.
+	Duration time.Duration `json:"-"`
+}
+
+func (r Record) MarshalJSON() ([]byte, error) {
+	type record Record
+	return json.Marshal(struct {
+		record
+		DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	}{record(r), r.Duration.Seconds()})
+}
+
+// Summary is a Reporter collecting a Record for every reported deployment. It is safe for concurrent use.
+type Summary struct {
+	mutex   sync.Mutex
+	records []Record
+}
+
+var _ Reporter = (*Summary)(nil)
+
+// NewSummary creates an empty Summary
+func NewSummary() *Summary {
+	return &Summary{}
+}
+
+func (s *Summary) ReportDeployment(ctx context.Context, c coordinate.Coordinate, state State, duration time.Duration, err error) {
+	r := Record{Config: c, State: state, Duration: duration}
+	if env, ok := ctx.Value(log.CtxKeyEnv{}).(log.CtxValEnv); ok {
+		r.Environment = env.Name
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = append(s.records, r)
+}
+
+// Records returns all collected records, sorted by environment and configuration
+func (s *Summary) Records() []Record {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records := slices.Clone(s.records)
+	slices.SortFunc(records, func(a, b Record) int {
+		return cmp.Or(cmp.Compare(a.Environment, b.Environment), cmp.Compare(a.Config.String(), b.Config.String()))
+	})
+	return records
+}
+
+// Count returns the number of records with the given State
+func (s *Summary) Count(state State) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for _, r := range s.records {
+		if r.State == state {
+			count++
+		}
+	}
+	return count
+}
+
+// summaryFile is the JSON representation of a Summary
+type summaryFile struct {
+	Deployed int      `json:"deployed"`
+	Failed   int      `json:"failed"`
+	Skipped  int      `json:"skipped"`
+	Configs  []Record `json:"configs"`
+}
+
+// Format is the file format a Summary can be written in
+type Format string
+
+const (
+	// FormatJSON writes the summary as JSON object listing the counts of all states and all records
+	FormatJSON Format = "json"
+	// FormatJUnit writes the summary as JUnit XML report, with one test suite per environment and one test case per configuration
+	FormatJUnit Format = "junit"
+)
+
+// Formats lists all supported formats
+var Formats = []Format{FormatJSON, FormatJUnit}
+
+// Write writes the summary in the given format to the given path
+func (s *Summary) Write(fs afero.Fs, path string, format Format) error {
+	var content []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		content, err = s.marshalJSON()
+	case FormatJUnit:
+		content, err = s.marshalJUnit()
+	default:
+		return fmt.Errorf("unknown deployment report format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal deployment report: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write deployment report to %q: %w", path, err)
+	}
+	return nil
+}
+
+func (s *Summary) marshalJSON() ([]byte, error) {
+	return json.MarshalIndent(summaryFile{
+		Deployed: s.Count(StateDeployed),
+		Failed:   s.Count(StateFailed),
+		Skipped:  s.Count(StateSkipped),
+		Configs:  s.Records(),
+	}, "", "  ")
+}
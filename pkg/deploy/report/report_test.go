@@ -0,0 +1,101 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReporterFromContextOrDiscard(t *testing.T) {
+	summary := report.NewSummary()
+	ctx := report.NewContextWithReporter(context.TODO(), summary)
+	assert.Same(t, summary, report.GetReporterFromContextOrDiscard(ctx))
+
+	assert.NotPanics(t, func() {
+		report.GetReporterFromContextOrDiscard(context.TODO()).ReportDeployment(context.TODO(), coordinate.Coordinate{}, report.StateDeployed, 0, nil)
+	})
+}
+
+func TestSummary_Write_JSON(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: "env", Group: "group"})
+
+	summary := report.NewSummary()
+	summary.ReportDeployment(ctx, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "b"}, report.StateSkipped, 0, nil)
+	summary.ReportDeployment(ctx, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}, report.StateFailed, 1500*time.Millisecond, errors.New("HTTP 400"))
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, summary.Write(fs, "summary.json", report.FormatJSON))
+
+	content, err := afero.ReadFile(fs, "summary.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "deployed": 0,
+  "failed": 1,
+  "skipped": 1,
+  "configs": [
+    {"environment": "env", "config": {"project": "p", "type": "t", "configId": "a"}, "state": "failed", "error": "HTTP 400", "durationSeconds": 1.5},
+    {"environment": "env", "config": {"project": "p", "type": "t", "configId": "b"}, "state": "skipped"}
+  ]
+}`, string(content))
+}
+
+func TestSummary_Write_JUnit(t *testing.T) {
+	envA := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: "a"})
+	envB := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: "b"})
+
+	summary := report.NewSummary()
+	summary.ReportDeployment(envA, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "c1"}, report.StateDeployed, time.Second, nil)
+	summary.ReportDeployment(envA, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "c2"}, report.StateFailed, 500*time.Millisecond, errors.New("HTTP 400"))
+	summary.ReportDeployment(envB, coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "c1"}, report.StateSkipped, 0, errors.New("depends on p:t:c0"))
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, summary.Write(fs, "report.xml", report.FormatJUnit))
+
+	content, err := afero.ReadFile(fs, "report.xml")
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="monaco deploy" tests="3" failures="1" skipped="1" time="1.500">
+  <testsuite name="a" tests="2" failures="1" skipped="0" time="1.500">
+    <testcase name="p:t:c1" classname="p.t" time="1.000"></testcase>
+    <testcase name="p:t:c2" classname="p.t" time="0.500">
+      <failure message="deployment failed">HTTP 400</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b" tests="1" failures="0" skipped="1" time="0.000">
+    <testcase name="p:t:c1" classname="p.t" time="0.000">
+      <skipped message="depends on p:t:c0"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`, string(content))
+}
+
+func TestSummary_Write_FailsOnUnknownFormat(t *testing.T) {
+	err := report.NewSummary().Write(afero.NewMemMapFs(), "report", "yaml")
+	assert.ErrorContains(t, err, "unknown deployment report format")
+}
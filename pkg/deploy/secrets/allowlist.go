@@ -0,0 +1,101 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// AllowEntry allows values of configs that look like secrets, but are none, or are intentionally deployed.
+type AllowEntry struct {
+	// Config is the coordinate of the configs the entry applies to, in the format "project:type:config-id". Supports
+	// wildcards, e.g. "my-project:dashboard:*".
+	Config string `yaml:"config"`
+	// Paths of the allowed values in the payload. A path is a dot-separated list of object keys, e.g.
+	// "tiles.markdown"; arrays do not add to the path. Supports wildcards. If empty, all values of the configs are
+	// allowed.
+	Paths []string `yaml:"paths,omitempty"`
+}
+
+// AllowList lists values of configs that are not reported as secrets
+type AllowList struct {
+	Allow []AllowEntry `yaml:"allow"`
+}
+
+// LoadAllowList reads an AllowList from the given YAML file, e.g.:
+//
+//	allow:
+//	  - config: "my-project:dashboard:*"
+//	    paths: [tiles.markdown]
+//	  - config: "my-project:builtin:alerting.profile:default-profile"
+func LoadAllowList(fs afero.Fs, file string) (AllowList, error) {
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return AllowList{}, fmt.Errorf("failed to read secret scan allow-list %q: %w", file, err)
+	}
+
+	var l AllowList
+	if err := yaml.UnmarshalStrict(data, &l); err != nil {
+		return AllowList{}, fmt.Errorf("failed to parse secret scan allow-list %q: %w", file, err)
+	}
+
+	var errs []error
+	for i, e := range l.Allow {
+		if err := e.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid entry %d of secret scan allow-list %q: %w", i, file, err))
+		}
+	}
+	if len(errs) > 0 {
+		return AllowList{}, errors.Join(errs...)
+	}
+	return l, nil
+}
+
+func (e AllowEntry) validate() error {
+	if e.Config == "" {
+		return errors.New("missing config")
+	}
+	for _, p := range append([]string{e.Config}, e.Paths...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// Allows returns whether the value at the given path of the payload of the config with the given coordinate is allowed
+func (l AllowList) Allows(c coordinate.Coordinate, valuePath string) bool {
+	return slices.ContainsFunc(l.Allow, func(e AllowEntry) bool {
+		if matched, _ := path.Match(e.Config, c.String()); !matched {
+			return false
+		}
+		if len(e.Paths) == 0 {
+			return true
+		}
+		return slices.ContainsFunc(e.Paths, func(p string) bool {
+			matched, _ := path.Match(p, valuePath)
+			return matched
+		})
+	})
+}
@@ -0,0 +1,200 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package secrets scans rendered configuration payloads for values that look like secrets, such as API tokens, keys,
+// or passwords, so that they are not accidentally deployed in plain text.
+package secrets
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+)
+
+// Level defines how a deployment reacts to secrets found in payloads
+type Level string
+
+const (
+	// LevelOff disables scanning payloads for secrets
+	LevelOff Level = "off"
+	// LevelWarn logs a warning for each secret found, but deploys the payload anyway
+	LevelWarn Level = "warn"
+	// LevelFail fails the deployment of payloads containing secrets
+	LevelFail Level = "fail"
+)
+
+// Levels lists all supported levels
+var Levels = []Level{LevelOff, LevelWarn, LevelFail}
+
+const (
+	// minEntropyLength is the minimum length of a value to be checked for high entropy
+	minEntropyLength = 20
+	// minEntropy is the Shannon entropy in bits per character above which a value is considered random, and thus a
+	// secret. Random base64 strings reach it from about 30 characters on; words, URLs, and hex IDs stay below.
+	minEntropy = 4.5
+	// settingsObjectIDPrefix starts the base64 encoded IDs of all Settings 2.0 objects. Such IDs are high-entropy
+	// values, but no secrets, and are commonly referenced in payloads.
+	settingsObjectIDPrefix = "vu9U3hXa3q0AAAAB"
+)
+
+// knownSecrets are patterns of secrets with a well-known format
+var knownSecrets = []struct {
+	rule    string
+	pattern *regexp.Regexp
+}{
+	{"dynatrace-token", regexp.MustCompile(`dt0[a-z][0-9]{2}\.[A-Za-z0-9]+\.[A-Za-z0-9]+`)},
+	{"aws-access-key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"private-key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+}
+
+// Finding is a value of a payload that looks like a secret
+type Finding struct {
+	// Path of the value in the payload, in the format of AllowEntry.Paths
+	Path string
+	// Rule names the reason the value looks like a secret, e.g. "dynatrace-token" or "high-entropy"
+	Rule string
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return f.Rule
+	}
+	return fmt.Sprintf("%s at %q", f.Rule, f.Path)
+}
+
+// FoundError is returned if the payload of a config contains values that look like secrets
+type FoundError struct {
+	Coordinate coordinate.Coordinate
+	Findings   []Finding
+}
+
+func (e FoundError) Error() string {
+	findings := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		findings[i] = f.String()
+	}
+	return fmt.Sprintf("config %s contains %d values that look like secrets: %s", e.Coordinate, len(e.Findings), strings.Join(findings, "; "))
+}
+
+// Scanner finds values that look like secrets in payloads: values matching the format of well-known tokens and keys,
+// and random, high-entropy values. Values allowed by its AllowList are ignored.
+type Scanner struct {
+	allow AllowList
+}
+
+// NewScanner returns a Scanner ignoring the values allowed by the given AllowList
+func NewScanner(allow AllowList) *Scanner {
+	return &Scanner{allow: allow}
+}
+
+// Scan returns a FoundError if the payload of the config with the given coordinate contains values that look like
+// secrets and are not allowed. An error of another type is returned if the payload is no valid JSON.
+func (s *Scanner) Scan(c coordinate.Coordinate, payload []byte) error {
+	var obj any
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return fmt.Errorf("failed to parse payload of config %s: %w", c, err)
+	}
+
+	var findings []Finding
+	walk(obj, nil, func(p []string, v string) {
+		// values containing secrets fetched from a secret store by a 'secret' parameter are deployed intentionally
+		if secret.Mask(v) != v {
+			return
+		}
+		if rule, found := check(v); found {
+			f := Finding{Path: strings.Join(p, "."), Rule: rule}
+			if !s.allow.Allows(c, f.Path) && !slices.Contains(findings, f) {
+				findings = append(findings, f)
+			}
+		}
+	})
+
+	if len(findings) > 0 {
+		slices.SortFunc(findings, func(a, b Finding) int {
+			return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Rule, b.Rule))
+		})
+		return FoundError{Coordinate: c, Findings: findings}
+	}
+	return nil
+}
+
+// walk calls fn for all string values of the given JSON value with their path. Arrays do not add to the path, thus the
+// values of all elements of an array share the same path.
+func walk(v any, p []string, fn func(p []string, v string)) {
+	switch t := v.(type) {
+	case string:
+		fn(p, t)
+	case []any:
+		for _, e := range t {
+			walk(e, p, fn)
+		}
+	case map[string]any:
+		for k, e := range t {
+			walk(e, append(slices.Clip(p), k), fn)
+		}
+	}
+}
+
+// check returns whether the given value looks like a secret, and why
+func check(v string) (string, bool) {
+	for _, k := range knownSecrets {
+		if k.pattern.MatchString(v) {
+			return k.rule, true
+		}
+	}
+
+	// values may embed secrets, e.g. in markdown or in a header like "Authorization: Api-Token ...", thus each word is
+	// checked separately
+	words := strings.FieldsFunc(v, func(r rune) bool {
+		return strings.ContainsRune(" \t\r\n\"'`=,;()<>[]{}", r)
+	})
+	for _, w := range words {
+		if len(w) < minEntropyLength || strings.Contains(w, "://") || strings.HasPrefix(w, settingsObjectIDPrefix) {
+			continue
+		}
+		if entropy(w) >= minEntropy {
+			return "high-entropy", true
+		}
+	}
+	return "", false
+}
+
+// entropy returns the Shannon entropy of the given string in bits per character
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+
+	var e float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
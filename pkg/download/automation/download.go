@@ -144,6 +144,8 @@ func createTemplateFromRawJSON(obj automationutils.Response, configType, project
 	delete(data, "id")
 	delete(data, "modificationInfo")
 	delete(data, "lastExecution")
+	// 'usages' lists where an object is used, it is computed by the environment and can't be deployed
+	delete(data, "usages")
 
 	// extract 'title' as name
 	configName := configId
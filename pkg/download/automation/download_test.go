@@ -167,6 +167,18 @@ func Test_createTemplateFromRawJSON(t *testing.T) {
 				t: template.NewInMemoryTemplate("42", `{
   "important": "data",
   "workflow_name": "My Workflow"
+}`),
+			},
+		},
+		{
+			"removes properties computed by the environment",
+			automationutils.Response{
+				ID:   "42",
+				Data: []byte(`{ "id": "42", "important": "data", "usages": [], "lastExecution": null, "modificationInfo": {} }`),
+			},
+			want{
+				t: template.NewInMemoryTemplate("42", `{
+  "important": "data"
 }`),
 			},
 		},
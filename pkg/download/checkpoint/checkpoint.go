@@ -0,0 +1,202 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package checkpoint records the responses of a running download, so that a download that failed midway can be
+// resumed without fetching the already completed APIs and settings schemas again.
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/spf13/afero"
+	iofs "io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName is the name of the file, stored in the download output folder, that holds the checkpoint of a download.
+const FileName = ".download-checkpoint.json"
+
+// checkpointVersion is increased whenever the format of the checkpoint file changes in an incompatible way. Checkpoint
+// files of a different version are ignored, resulting in a download from scratch.
+const checkpointVersion = 1
+
+// saveInterval is the minimum time between two automatic saves of a Checkpoint while responses are recorded.
+const saveInterval = 10 * time.Second
+
+// Checkpoint holds the responses of all completed requests of a download. It is saved periodically while responses are
+// recorded, so it survives a download being aborted.
+type Checkpoint struct {
+	Version int `json:"version"`
+	// Settings holds the settings objects per completely listed schema ID
+	Settings map[string][]dtclient.DownloadSettingsObject `json:"settings"`
+	// ClassicLists holds the listed values per classic API, see classicKey
+	ClassicLists map[string][]dtclient.Value `json:"classicLists"`
+	// ClassicConfigs holds the payloads of downloaded classic configs, see classicKey
+	ClassicConfigs map[string]string `json:"classicConfigs"`
+
+	fs        afero.Fs
+	file      string
+	lastSave  time.Time
+	mutex     sync.Mutex
+	saveMutex sync.Mutex
+}
+
+// New returns an empty Checkpoint which is saved to the given file.
+func New(fs afero.Fs, file string) *Checkpoint {
+	return &Checkpoint{
+		Version:        checkpointVersion,
+		Settings:       make(map[string][]dtclient.DownloadSettingsObject),
+		ClassicLists:   make(map[string][]dtclient.Value),
+		ClassicConfigs: make(map[string]string),
+		fs:             fs,
+		file:           file,
+		lastSave:       time.Now(),
+	}
+}
+
+// Load reads the Checkpoint stored in the given file. If the file does not exist, or was written by an incompatible
+// version, an empty Checkpoint is returned.
+func Load(fs afero.Fs, file string) (*Checkpoint, error) {
+	data, err := afero.ReadFile(fs, file)
+	if errors.Is(err, iofs.ErrNotExist) {
+		log.Warn("No download checkpoint found at %q, downloading everything", file)
+		return New(fs, file), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download checkpoint %q: %w", file, err)
+	}
+
+	c := New(fs, file)
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse download checkpoint %q: %w", file, err)
+	}
+
+	if c.Version != checkpointVersion {
+		log.Warn("Download checkpoint %q has unsupported version %d, downloading everything", file, c.Version)
+		return New(fs, file), nil
+	}
+
+	if c.Settings == nil {
+		c.Settings = make(map[string][]dtclient.DownloadSettingsObject)
+	}
+	if c.ClassicLists == nil {
+		c.ClassicLists = make(map[string][]dtclient.Value)
+	}
+	if c.ClassicConfigs == nil {
+		c.ClassicConfigs = make(map[string]string)
+	}
+
+	log.Info("Resuming download from checkpoint %q: %d settings schemas and %d classic configs were already downloaded", file, len(c.Settings), len(c.ClassicConfigs))
+	return c, nil
+}
+
+// Save stores the Checkpoint in its file.
+func (c *Checkpoint) Save() error {
+	c.saveMutex.Lock()
+	defer c.saveMutex.Unlock()
+
+	c.mutex.Lock()
+	data, err := json.Marshal(c)
+	c.lastSave = time.Now()
+	c.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to serialize download checkpoint: %w", err)
+	}
+
+	if err := c.fs.MkdirAll(filepath.Dir(c.file), 0777); err != nil {
+		return fmt.Errorf("failed to write download checkpoint %q: %w", c.file, err)
+	}
+	if err := afero.WriteFile(c.fs, c.file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write download checkpoint %q: %w", c.file, err)
+	}
+	return nil
+}
+
+// Remove deletes the file of the Checkpoint, if it exists. It is called once a download completed successfully.
+func (c *Checkpoint) Remove() error {
+	if err := c.fs.Remove(c.file); err != nil && !errors.Is(err, iofs.ErrNotExist) {
+		return fmt.Errorf("failed to remove download checkpoint %q: %w", c.file, err)
+	}
+	return nil
+}
+
+// saveIfDue saves the Checkpoint if the last save is longer ago than saveInterval. Failures are only logged, as the
+// download itself is not affected by them.
+func (c *Checkpoint) saveIfDue() {
+	c.mutex.Lock()
+	due := time.Since(c.lastSave) >= saveInterval
+	c.mutex.Unlock()
+
+	if !due {
+		return
+	}
+	if err := c.Save(); err != nil {
+		log.Warn("Failed to save download checkpoint: %v", err)
+	}
+}
+
+func (c *Checkpoint) settings(schemaID string) ([]dtclient.DownloadSettingsObject, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	objects, found := c.Settings[schemaID]
+	return objects, found
+}
+
+func (c *Checkpoint) setSettings(schemaID string, objects []dtclient.DownloadSettingsObject) {
+	c.mutex.Lock()
+	c.Settings[schemaID] = objects
+	c.mutex.Unlock()
+
+	c.saveIfDue()
+}
+
+func (c *Checkpoint) classicList(key string) ([]dtclient.Value, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	values, found := c.ClassicLists[key]
+	return values, found
+}
+
+func (c *Checkpoint) setClassicList(key string, values []dtclient.Value) {
+	c.mutex.Lock()
+	c.ClassicLists[key] = values
+	c.mutex.Unlock()
+
+	c.saveIfDue()
+}
+
+func (c *Checkpoint) classicConfig(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	payload, found := c.ClassicConfigs[key]
+	return []byte(payload), found
+}
+
+func (c *Checkpoint) setClassicConfig(key string, payload []byte) {
+	c.mutex.Lock()
+	c.ClassicConfigs[key] = string(payload)
+	c.mutex.Unlock()
+
+	c.saveIfDue()
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkpoint
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLoad_MissingFileReturnsEmptyCheckpoint(t *testing.T) {
+	c, err := Load(afero.NewMemMapFs(), FileName)
+	require.NoError(t, err)
+	assert.Equal(t, checkpointVersion, c.Version)
+	assert.Empty(t, c.Settings)
+	assert.Empty(t, c.ClassicLists)
+	assert.Empty(t, c.ClassicConfigs)
+}
+
+func TestLoad_UnsupportedVersionReturnsEmptyCheckpoint(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, FileName, []byte(`{"version": 999, "classicConfigs": {"a/b": "{}"}}`), 0644))
+
+	c, err := Load(fs, FileName)
+	require.NoError(t, err)
+	assert.Empty(t, c.ClassicConfigs)
+}
+
+func TestLoad_InvalidFileFails(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, FileName, []byte(`{`), 0644))
+
+	_, err := Load(fs, FileName)
+	assert.Error(t, err)
+}
+
+func TestCheckpoint_SaveLoadAndRemove(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	file := "out/" + FileName
+	c := New(fs, file)
+	c.setSettings("builtin:alerting.profile", []dtclient.DownloadSettingsObject{{ObjectId: "o1", Value: []byte(`{"name":"a"}`)}})
+	c.setClassicList("alerting-profile", []dtclient.Value{{Id: "id", Name: "name"}})
+	c.setClassicConfig("alerting-profile/id", []byte(`{"name": "name"}`))
+
+	require.NoError(t, c.Save())
+
+	loaded, err := Load(fs, file)
+	require.NoError(t, err)
+	assert.Equal(t, c.Settings, loaded.Settings)
+	assert.Equal(t, c.ClassicLists, loaded.ClassicLists)
+	assert.Equal(t, c.ClassicConfigs, loaded.ClassicConfigs)
+
+	require.NoError(t, loaded.Remove())
+	exists, err := afero.Exists(fs, file)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, loaded.Remove(), "removing a missing checkpoint is not an error")
+}
@@ -0,0 +1,112 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkpoint
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/filter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+)
+
+// SettingsClient wraps a client.SettingsClient and serves the settings objects of schemas listed by a previous run
+// from the Checkpoint. Newly listed schemas are recorded in the Checkpoint.
+type SettingsClient struct {
+	client.SettingsClient
+	checkpoint *Checkpoint
+}
+
+// NewSettingsClient returns a SettingsClient listing settings objects using c and recording them in checkpoint.
+func NewSettingsClient(c client.SettingsClient, checkpoint *Checkpoint) *SettingsClient {
+	return &SettingsClient{
+		SettingsClient: c,
+		checkpoint:     checkpoint,
+	}
+}
+
+// ListSettings returns all settings objects of the given schema. Listings discarding the values of the objects are
+// not recorded and always passed to the wrapped client.
+func (c *SettingsClient) ListSettings(ctx context.Context, schemaID string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+	if opts.DiscardValue {
+		return c.SettingsClient.ListSettings(ctx, schemaID, opts)
+	}
+
+	if objects, found := c.checkpoint.settings(schemaID); found {
+		return filter.FilterSlice(objects, opts.Filter), nil
+	}
+
+	objects, err := c.SettingsClient.ListSettings(ctx, schemaID, dtclient.ListSettingsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	c.checkpoint.setSettings(schemaID, objects)
+	return filter.FilterSlice(objects, opts.Filter), nil
+}
+
+// ConfigClient wraps a client.ConfigClient and serves classic configs listed or downloaded by a previous run from the
+// Checkpoint. New responses are recorded in the Checkpoint.
+type ConfigClient struct {
+	client.ConfigClient
+	checkpoint *Checkpoint
+}
+
+// NewConfigClient returns a ConfigClient downloading classic configs using c and recording them in checkpoint.
+func NewConfigClient(c client.ConfigClient, checkpoint *Checkpoint) *ConfigClient {
+	return &ConfigClient{
+		ConfigClient: c,
+		checkpoint:   checkpoint,
+	}
+}
+
+// ListConfigs lists the configs of the given API.
+func (c *ConfigClient) ListConfigs(ctx context.Context, a api.API) ([]dtclient.Value, error) {
+	key := classicKey(a)
+	if values, found := c.checkpoint.classicList(key); found {
+		return values, nil
+	}
+
+	values, err := c.ConfigClient.ListConfigs(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	c.checkpoint.setClassicList(key, values)
+	return values, nil
+}
+
+// ReadConfigById reads the config with the given ID of the given API.
+func (c *ConfigClient) ReadConfigById(a api.API, id string) ([]byte, error) {
+	key := classicKey(a) + "/" + id
+	if payload, found := c.checkpoint.classicConfig(key); found {
+		return payload, nil
+	}
+
+	payload, err := c.ConfigClient.ReadConfigById(a, id)
+	if err != nil {
+		return nil, err
+	}
+	c.checkpoint.setClassicConfig(key, payload)
+	return payload, nil
+}
+
+// classicKey identifies an API in the Checkpoint. APIs with a parent are identified per parent object.
+func classicKey(a api.API) string {
+	if a.AppliedParentObjectID != "" {
+		return a.ID + "/" + a.AppliedParentObjectID
+	}
+	return a.ID
+}
@@ -0,0 +1,92 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkpoint
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestSettingsClient_ListSettings(t *testing.T) {
+	objects := []dtclient.DownloadSettingsObject{{ObjectId: "o1"}, {ObjectId: "o2"}}
+	c := client.NewMockSettingsClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", dtclient.ListSettingsOptions{}).Return(objects, nil).Times(1)
+
+	cp := New(afero.NewMemMapFs(), FileName)
+	sc := NewSettingsClient(c, cp)
+
+	got, err := sc.ListSettings(context.TODO(), "builtin:alerting.profile", dtclient.ListSettingsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, objects, got)
+
+	// second listing is served from the checkpoint, filters are still applied
+	got, err = sc.ListSettings(context.TODO(), "builtin:alerting.profile", dtclient.ListSettingsOptions{
+		Filter: func(o dtclient.DownloadSettingsObject) bool { return o.ObjectId == "o2" },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []dtclient.DownloadSettingsObject{{ObjectId: "o2"}}, got)
+}
+
+func TestSettingsClient_FailedListingIsNotRecorded(t *testing.T) {
+	c := client.NewMockSettingsClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return(nil, assert.AnError)
+
+	cp := New(afero.NewMemMapFs(), FileName)
+	_, err := NewSettingsClient(c, cp).ListSettings(context.TODO(), "builtin:alerting.profile", dtclient.ListSettingsOptions{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, cp.Settings)
+}
+
+func TestConfigClient_ServesRecordedResponses(t *testing.T) {
+	apis := api.NewAPIs()
+	profile := apis[api.AlertingProfile]
+	keyUserActions := apis[api.KeyUserActionsMobile].ApplyParentObjectID("MOBILE_APPLICATION-1")
+
+	c := client.NewMockConfigClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), profile).Return([]dtclient.Value{{Id: "id", Name: "name"}}, nil).Times(1)
+	c.EXPECT().ListConfigs(gomock.Any(), keyUserActions).Return([]dtclient.Value{{Id: "abc", Name: "abc"}}, nil).Times(1)
+	c.EXPECT().ReadConfigById(profile, "id").Return([]byte(`{"name":"name"}`), nil).Times(1)
+
+	cp := New(afero.NewMemMapFs(), FileName)
+	cc := NewConfigClient(c, cp)
+
+	for i := 0; i < 2; i++ {
+		values, err := cc.ListConfigs(context.TODO(), profile)
+		require.NoError(t, err)
+		assert.Equal(t, []dtclient.Value{{Id: "id", Name: "name"}}, values)
+
+		values, err = cc.ListConfigs(context.TODO(), keyUserActions)
+		require.NoError(t, err)
+		assert.Equal(t, []dtclient.Value{{Id: "abc", Name: "abc"}}, values)
+
+		payload, err := cc.ReadConfigById(profile, "id")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"name"}`, string(payload))
+	}
+
+	assert.Contains(t, cp.ClassicLists, "key-user-actions-mobile/MOBILE_APPLICATION-1")
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/concurrency"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
@@ -32,9 +34,11 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
 	projectv2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/mitchellh/mapstructure"
 	"golang.org/x/exp/maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -45,35 +49,46 @@ type downloadedConfig struct {
 	value dtclient.Value
 }
 
-func Download(client client.ConfigClient, projectName string, apisToDownload api.APIs, filters ContentFilters) (projectv2.ConfigsPerType, error) {
+// Download downloads the configs of the given APIs. Configs not matching the scopeFilter are not downloaded. Configs of
+// APIs with a parent are scoped to their parent entity, all other configs are scoped to the environment.
+func Download(client client.ConfigClient, projectName string, apisToDownload api.APIs, filters ContentFilters, scopeFilter scope.Filter) (projectv2.ConfigsPerType, error) {
 	log.Debug("APIs to download: \n - %v", strings.Join(maps.Keys(apisToDownload), "\n - "))
 	results := make(projectv2.ConfigsPerType, len(apisToDownload))
 	mutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
 	wg.Add(len(apisToDownload))
 
+	perAPILimit := environment.GetEnvValueIntLog(environment.ConcurrentDownloadsPerAPIEnvKey)
+
 	log.Debug("Fetching configs to download")
 	startTime := time.Now()
 	for _, currentApi := range apisToDownload {
 		go func() {
 			defer wg.Done()
 			lg := log.WithFields(field.Type(currentApi.ID))
-			downloadedConfigs := downloadConfigs(client, currentApi, projectName, filters)
+			if !currentApi.HasParent() && !scopeFilter.MatchesScope(scope.Environment) {
+				lg.Debug("\tSkipping download of API %v, as its configs are not of a scope matching the filter", currentApi.ID)
+				return
+			}
+			downloadedConfigs := downloadConfigs(client, currentApi, projectName, filters, scopeFilter, perAPILimit)
 			var configsToPersist []downloadedConfig
 			for _, c := range downloadedConfigs {
 				content, err := c.Template.Content()
 				if err != nil {
 					return
 				}
-				if shouldPersist(currentApi, content, filters) {
+				if shouldPersist(currentApi, content, filters) && scopeFilter.MatchesManagementZone(c.value.Id, []byte(content)) {
 					configsToPersist = append(configsToPersist, c)
 				} else {
 					lg.Debug("\tSkipping persisting config %v (%v) in API %v", c.value.Id, c.value.Name, currentApi.ID)
 				}
 			}
 			if len(configsToPersist) > 0 {
+				configs := getConfigsFromCustomConfigs(configsToPersist)
+				// configs are downloaded in parallel, sort them to get the same result on every download
+				slices.SortFunc(configs, func(a, b config.Config) int { return strings.Compare(a.Coordinate.ConfigId, b.Coordinate.ConfigId) })
 				mutex.Lock()
-				results[currentApi.ID] = getConfigsFromCustomConfigs(configsToPersist)
+				results[currentApi.ID] = configs
 				mutex.Unlock()
 			}
 		}()
@@ -95,10 +110,12 @@ func getConfigsFromCustomConfigs(customConfigs []downloadedConfig) []config.Conf
 	return finalConfigs
 }
 
-func downloadConfigs(client client.ConfigClient, api api.API, projectName string, filters ContentFilters) []downloadedConfig {
+// downloadConfigs downloads all configs of the given API in parallel. At most limit configs are downloaded at once, a
+// limit <= 0 means no limit besides the one of the client.
+func downloadConfigs(client client.ConfigClient, api api.API, projectName string, filters ContentFilters, scopeFilter scope.Filter, limit int) []downloadedConfig {
 	var results []downloadedConfig
 	logger := log.WithFields(field.Type(api.ID))
-	foundValues, err := findConfigsToDownload(client, api, filters)
+	foundValues, err := findConfigsToDownload(client, api, filters, scopeFilter)
 	if err != nil {
 		logger.WithFields(field.Error(err)).Error("Failed to fetch configs of type '%v', skipping download of this type. Reason: %v", api.ID, err)
 		return results
@@ -115,8 +132,9 @@ func downloadConfigs(client client.ConfigClient, api api.API, projectName string
 	mutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
 	wg.Add(len(foundValues))
+	limiter := concurrency.NewLimiter(limit)
 	for _, v := range foundValues {
-		go func() {
+		limiter.Execute(func() {
 			defer wg.Done()
 
 			downloadedJsons, err := downloadAndUnmarshalConfig(client, api, v)
@@ -145,7 +163,7 @@ func downloadConfigs(client client.ConfigClient, api api.API, projectName string
 				results = append(results, c1)
 				mutex.Unlock()
 			}
-		}()
+		})
 	}
 	wg.Wait()
 	return results
@@ -173,7 +191,7 @@ func (v value) id() string {
 
 // findConfigsToDownload tries to identify all values that should be downloaded from a Dynatrace environment for
 // the given API
-func findConfigsToDownload(client client.ConfigClient, apiToDownload api.API, filters ContentFilters) (values, error) {
+func findConfigsToDownload(client client.ConfigClient, apiToDownload api.API, filters ContentFilters, scopeFilter scope.Filter) (values, error) {
 	if apiToDownload.SingleConfiguration && !apiToDownload.HasParent() {
 		log.WithFields(field.Type(apiToDownload.ID)).Debug("\tFetching singleton-configuration '%v'", apiToDownload.ID)
 
@@ -191,7 +209,7 @@ func findConfigsToDownload(client client.ConfigClient, apiToDownload api.API, fi
 		}
 		for _, parentAPIValue := range parentAPIValues {
 
-			if skipDownload(*apiToDownload.Parent, parentAPIValue, filters) {
+			if skipDownload(*apiToDownload.Parent, parentAPIValue, filters) || !scopeFilter.MatchesScope(parentAPIValue.Id) {
 				continue
 			}
 
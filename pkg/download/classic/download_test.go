@@ -32,6 +32,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -51,7 +52,7 @@ func TestDownload_KeyUserActionMobile(t *testing.T) {
 	c.EXPECT().ReadConfigById(apiMap[api.ApplicationMobile], applicationId).Return([]byte(`{"keyUserActions": [{"name": "abc"}]}`), nil).Times(1)
 	c.EXPECT().ReadConfigById(apiMap[api.KeyUserActionsMobile].ApplyParentObjectID(applicationId), "").Return([]byte(`{}`), nil).Times(1)
 
-	configurations, err := classic.Download(c, "project", apiMap, classic.ApiContentFilters)
+	configurations, err := classic.Download(c, "project", apiMap, classic.ApiContentFilters, scope.Filter{})
 	require.NoError(t, err)
 	assert.Len(t, configurations, 2, "Expected two configurations downloaded")
 
@@ -66,6 +67,44 @@ func TestDownload_KeyUserActionMobile(t *testing.T) {
 	assert.False(t, gotKeyUserActionsMobileConfig.Skip)
 }
 
+func TestDownload_ScopeFilter(t *testing.T) {
+	standardAPIs := api.NewAPIs()
+	apiMap := api.APIs{api.KeyUserActionsMobile: standardAPIs[api.KeyUserActionsMobile],
+		api.ApplicationMobile: standardAPIs[api.ApplicationMobile],
+	}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(context.TODO(), apiMap[api.ApplicationMobile]).Return([]dtclient.Value{{Id: "MOBILE_APPLICATION-1", Name: "one"}, {Id: "MOBILE_APPLICATION-2", Name: "two"}}, nil).Times(1)
+	c.EXPECT().ListConfigs(context.TODO(), apiMap[api.KeyUserActionsMobile].ApplyParentObjectID("MOBILE_APPLICATION-2")).Return([]dtclient.Value{{Id: "abc", Name: "abc"}}, nil).Times(1)
+	c.EXPECT().ReadConfigById(apiMap[api.KeyUserActionsMobile].ApplyParentObjectID("MOBILE_APPLICATION-2"), "").Return([]byte(`{}`), nil).Times(1)
+
+	configurations, err := classic.Download(c, "project", apiMap, classic.ApiContentFilters, scope.Filter{Scopes: []string{"MOBILE_APPLICATION-2"}})
+	require.NoError(t, err)
+	assert.Len(t, configurations, 1, "Expected only configs of the matching scope to be downloaded")
+	require.Len(t, configurations[api.KeyUserActionsMobile], 1)
+	assert.Equal(t, reference.New("project", api.ApplicationMobile, "MOBILE_APPLICATION-2", "id"), configurations[api.KeyUserActionsMobile][0].Parameters[config.ScopeParameter])
+}
+
+func TestDownload_ConfigsAreSortedAndLimitedPerAPI(t *testing.T) {
+	t.Setenv("MONACO_CONCURRENT_DOWNLOADS_PER_API", "1")
+
+	a := api.API{ID: "some-api", URLPath: "/some-api"}
+	values := []dtclient.Value{{Id: "c", Name: "c"}, {Id: "a", Name: "a"}, {Id: "b", Name: "b"}}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), a).Return(values, nil)
+	for _, v := range values {
+		c.EXPECT().ReadConfigById(a, v.Id).Return([]byte(fmt.Sprintf(`{"name": %q}`, v.Name)), nil)
+	}
+
+	configurations, err := classic.Download(c, "project", toAPIs(a), classic.ApiContentFilters, scope.Filter{})
+	require.NoError(t, err)
+	require.Len(t, configurations["some-api"], 3)
+	for i, id := range []string{"a", "b", "c"} {
+		assert.Equal(t, id, configurations["some-api"][i].Coordinate.ConfigId)
+	}
+}
+
 func apiGet(a string) api.API {
 	return api.NewAPIs()[a]
 }
@@ -87,7 +126,7 @@ func TestDownload_KeyUserActionWeb(t *testing.T) {
 
 	apiMap := api.NewAPIs().Filter(api.RetainByName([]string{api.KeyUserActionsWeb}))
 
-	configurations, err := classic.Download(c, "project", apiMap, map[string]classic.ContentFilter{})
+	configurations, err := classic.Download(c, "project", apiMap, map[string]classic.ContentFilter{}, scope.Filter{})
 	assert.NoError(t, err)
 	assert.Len(t, configurations, 1)
 	gotConfig := configurations[api.KeyUserActionsWeb][0]
@@ -114,7 +153,7 @@ func TestDownload_KeyUserActionWeb_Uniqnes(t *testing.T) {
 
 	apiMap := api.NewAPIs().Filter(api.RetainByName([]string{api.KeyUserActionsWeb}))
 
-	configurations, err := classic.Download(c, "project", apiMap, map[string]classic.ContentFilter{})
+	configurations, err := classic.Download(c, "project", apiMap, map[string]classic.ContentFilter{}, scope.Filter{})
 	assert.NoError(t, err)
 	assert.Len(t, configurations, 1)
 	assert.Len(t, configurations[api.KeyUserActionsWeb], 3)
@@ -135,7 +174,7 @@ func TestDownload_SkipConfigThatShouldNotBePersisted(t *testing.T) {
 		},
 	}}
 
-	configurations, err := classic.Download(c, "project", toAPIs(api1, api2), filters)
+	configurations, err := classic.Download(c, "project", toAPIs(api1, api2), filters, scope.Filter{})
 	assert.NoError(t, err)
 	assert.Len(t, configurations, 1)
 }
@@ -184,7 +223,7 @@ func TestDownload_SkipConfigBeforeDownload(t *testing.T) {
 			t.Setenv(featureflags.DownloadFilterClassicConfigs().EnvName(), strconv.FormatBool(tt.withFiltering))
 			t.Setenv(featureflags.DownloadFilter().EnvName(), strconv.FormatBool(tt.withFiltering))
 
-			configurations, err := classic.Download(c, "project", toAPIs(api1, api2), filters)
+			configurations, err := classic.Download(c, "project", toAPIs(api1, api2), filters, scope.Filter{})
 			assert.NoError(t, err)
 			assert.Len(t, configurations, tt.wantDownloadedConfigs)
 		})
@@ -206,7 +245,7 @@ func TestDownload_FilteringCanBeTurnedOffViaFeatureFlags(t *testing.T) {
 		},
 	}}
 
-	configurations, err := classic.Download(c, "project", toAPIs(api1, api2), filters)
+	configurations, err := classic.Download(c, "project", toAPIs(api1, api2), filters, scope.Filter{})
 	assert.NoError(t, err)
 	assert.Len(t, configurations, 1)
 }
@@ -300,7 +339,7 @@ func Test_generalCases(t *testing.T) {
 				c.EXPECT().ReadConfigById(gomock.Any(), m.id).Return([]byte(m.response), m.err)
 			}
 
-			actual, err := classic.Download(c, "project", toAPIs(api1, api2), classic.ApiContentFilters)
+			actual, err := classic.Download(c, "project", toAPIs(api1, api2), classic.ApiContentFilters, scope.Filter{})
 
 			require.NoError(t, err)
 			require.Len(t, actual, len(tc.expectedKeys))
@@ -345,7 +384,7 @@ func TestDownload_SkippedParentsSkipChildren(t *testing.T) {
 	c := client.NewMockDynatraceClient(gomock.NewController(t))
 	c.EXPECT().ListConfigs(gomock.Any(), matcher.EqAPI(parentAPI)).Return([]dtclient.Value{{Id: "PARENT_ID_1", Name: "PARENT_NAME_1"}}, nil).Times(2)
 
-	configurations, err := classic.Download(c, "project", apiMap, contentFilters)
+	configurations, err := classic.Download(c, "project", apiMap, contentFilters, scope.Filter{})
 	require.NoError(t, err)
 	assert.Len(t, configurations, 0, "Expected no configurations as everything is skipped")
 }
@@ -370,7 +409,7 @@ func TestDownload_SingleConfigurationChild(t *testing.T) {
 	c.EXPECT().ListConfigs(gomock.Any(), matcher.EqAPI(parentAPI)).Return([]dtclient.Value{{Id: "PARENT_ID_1", Name: "PARENT_NAME_1"}}, nil).Times(2)
 	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil).AnyTimes()
 
-	configurations, err := classic.Download(c, "project", apiMap, contentFilters)
+	configurations, err := classic.Download(c, "project", apiMap, contentFilters, scope.Filter{})
 	require.NoError(t, err)
 	require.Len(t, configurations, 2, "Expected two configurations")
 	require.Len(t, configurations["PARENT_API_ID"], 1)
@@ -0,0 +1,59 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classic
+
+import (
+	"encoding/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/estimate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
+)
+
+// Estimate lists the configs of the given APIs that would be downloaded, without downloading them. As the list
+// endpoints only return the IDs and names of configs, content filters are not applied and the size of an entry is the
+// size of the listed values, which is a lower bound of the size of the downloaded configs.
+func Estimate(client client.ConfigClient, apisToEstimate api.APIs, filters ContentFilters, scopeFilter scope.Filter) []estimate.Entry {
+	var entries []estimate.Entry
+	for _, a := range apisToEstimate {
+		lg := log.WithFields(field.Type(a.ID))
+		if !a.HasParent() && !scopeFilter.MatchesScope(scope.Environment) {
+			continue
+		}
+
+		vals, err := findConfigsToDownload(client, a, filters, scopeFilter)
+		if err != nil {
+			lg.WithFields(field.Error(err)).Error("Failed to list configs of type '%v', skipping estimation of this type. Reason: %v", a.ID, err)
+			continue
+		}
+		vals = filterConfigsToSkip(a, vals, filters)
+		if len(vals) == 0 {
+			continue
+		}
+
+		e := estimate.Entry{Type: a.ID, Count: len(vals)}
+		for _, v := range vals {
+			if b, err := json.Marshal(v.value); err == nil {
+				e.Size += len(b)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
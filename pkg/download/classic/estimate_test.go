@@ -0,0 +1,61 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classic_test
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/estimate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	apiMap := api.NewAPIs()
+	apis := api.APIs{
+		api.AlertingProfile: apiMap[api.AlertingProfile],
+		api.Dashboard:       apiMap[api.Dashboard],
+	}
+
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(context.TODO(), apiMap[api.AlertingProfile]).Return([]dtclient.Value{{Id: "a", Name: "a"}, {Id: "b", Name: "b"}}, nil)
+	c.EXPECT().ListConfigs(context.TODO(), apiMap[api.Dashboard]).Return(nil, nil)
+
+	entries := classic.Estimate(c, apis, classic.ContentFilters{}, scope.Filter{})
+
+	assert.Len(t, entries, 1, "APIs without configs are not listed")
+	assert.Equal(t, api.AlertingProfile, entries[0].Type)
+	assert.Equal(t, 2, entries[0].Count)
+	assert.Positive(t, entries[0].Size)
+	assert.Equal(t, 2, estimate.Total(entries).Count)
+}
+
+func TestEstimate_SkipsEnvironmentScopedAPIsNotMatchingScopeFilter(t *testing.T) {
+	apiMap := api.NewAPIs()
+	c := client.NewMockDynatraceClient(gomock.NewController(t))
+
+	entries := classic.Estimate(c, api.APIs{api.AlertingProfile: apiMap[api.AlertingProfile]}, classic.ContentFilters{}, scope.Filter{Scopes: []string{"HOST-*"}})
+
+	assert.Empty(t, entries)
+}
@@ -87,9 +87,10 @@ func convertDocumentResponse(client client.DocumentClient, projectName string, r
 			Type:     string(documentType),
 			ConfigId: documentResponse.ID,
 		},
-		Type:           documentType,
-		Parameters:     params,
-		OriginObjectId: documentResponse.ID,
+		Type:             documentType,
+		Parameters:       params,
+		OriginObjectId:   documentResponse.ID,
+		OriginExternalId: documentResponse.ExternalID,
 	}, nil
 }
 
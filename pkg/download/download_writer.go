@@ -24,6 +24,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	configwriter "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/writer"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/writer"
 	"github.com/spf13/afero"
@@ -31,11 +32,13 @@ import (
 )
 
 type WriterContext struct {
-	EnvironmentUrl  string
-	ProjectToWrite  project.Project
-	Auth            manifest.Auth
-	OutputFolder    string
-	ForceOverwrite  bool
+	EnvironmentUrl string
+	ProjectToWrite project.Project
+	Auth           manifest.Auth
+	OutputFolder   string
+	ForceOverwrite bool
+	// Layout decides the folders configs are written to within the project. If nil, the flat layout is used.
+	Layout          configwriter.Layout
 	timestampString string
 }
 
@@ -89,6 +92,7 @@ func writeToDisk(fs afero.Fs, writerContext WriterContext) error {
 		OutputDir:       outputFolder,
 		ManifestName:    manifestFileName,
 		ParametersSerde: config.DefaultParameterParsers,
+		Layout:          writerContext.Layout,
 	}, manifest, []project.Project{writerContext.ProjectToWrite})
 
 	if len(errs) > 0 {
@@ -135,3 +139,30 @@ func getProjectFolderName(fs afero.Fs, writerContext WriterContext) string {
 	log.WithFields(field.F("outputFolder", outputFolder), field.F("projectFolder", projectFolderName)).Warn("A project folder named %q already exists in %q, creating %q instead.", writerContext.ProjectToWrite.Id, outputFolder, projectFolderName)
 	return projectFolderName
 }
+
+// WriteMergedProject writes the configs merged by Merge back into the existing project folder using the given layout.
+// Other than WriteToDisk, no manifest is written.
+func WriteMergedProject(fs afero.Fs, projectFolder string, configs project.ConfigsPerType, layout configwriter.Layout) error {
+	projectFolder = filepath.Clean(projectFolder)
+
+	var all []config.Config
+	for _, c := range configs {
+		all = append(all, c...)
+	}
+
+	log.Debug("Persisting merged configurations")
+	errs := configwriter.WriteConfigs(&configwriter.WriterContext{
+		Fs:              fs,
+		OutputFolder:    filepath.Dir(projectFolder),
+		ProjectFolder:   filepath.Base(projectFolder),
+		ParametersSerde: config.DefaultParameterParsers,
+		Layout:          layout,
+	}, all)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to persist merged configurations")
+	}
+
+	log.WithFields(field.F("projectFolder", projectFolder)).Info("Merged configurations written to '%s'", projectFolder)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package estimate holds the result of a download dry-run: the number of objects, and their estimated size, per API or
+// settings schema that would be downloaded.
+package estimate
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"slices"
+	"strings"
+)
+
+// Entry holds the number of objects of one API or settings schema that would be downloaded.
+type Entry struct {
+	// Type is the ID of the API or settings schema
+	Type string
+	// Count is the number of objects that would be downloaded
+	Count int
+	// Size is the estimated size of the objects in bytes
+	Size int
+}
+
+// FromConfigs creates an Entry per type of the given configs. The size of an Entry is the size of the templates of its
+// configs.
+func FromConfigs(configs project.ConfigsPerType) ([]Entry, error) {
+	entries := make([]Entry, 0, len(configs))
+	for t, cs := range configs {
+		e := Entry{Type: t, Count: len(cs)}
+		for _, c := range cs {
+			content, err := c.Template.Content()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get content of config %s: %w", c.Coordinate, err)
+			}
+			e.Size += len(content)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Total returns the summed up count and size of all entries.
+func Total(entries []Entry) Entry {
+	total := Entry{Type: "total"}
+	for _, e := range entries {
+		total.Count += e.Count
+		total.Size += e.Size
+	}
+	return total
+}
+
+// Log logs the entries sorted by type, followed by their total.
+func Log(entries []Entry) {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Type, b.Type) })
+
+	for _, e := range entries {
+		log.WithFields(field.Type(e.Type), field.F("count", e.Count), field.F("size", e.Size)).Info("\t%s: %d objects, ~%s", e.Type, e.Count, FormatSize(e.Size))
+	}
+	total := Total(entries)
+	log.WithFields(field.F("count", total.Count), field.F("size", total.Size)).Info("Would download %d objects of %d types, ~%s in total", total.Count, len(entries), FormatSize(total.Size))
+}
+
+// FormatSize formats the given number of bytes in a human-readable way, e.g. "1.5 KiB".
+func FormatSize(bytes int) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := unit, 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,59 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package estimate
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFromConfigs(t *testing.T) {
+	configs := project.ConfigsPerType{
+		"builtin:alerting.profile": {
+			{Template: template.NewInMemoryTemplate("a", "1234")},
+			{Template: template.NewInMemoryTemplate("b", "12")},
+		},
+		"builtin:tags": {
+			{Template: template.NewInMemoryTemplate("c", "1")},
+		},
+		"builtin:empty": []config.Config{},
+	}
+
+	entries, err := FromConfigs(configs)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []Entry{
+		{Type: "builtin:alerting.profile", Count: 2, Size: 6},
+		{Type: "builtin:tags", Count: 1, Size: 1},
+		{Type: "builtin:empty"},
+	}, entries)
+	assert.Equal(t, Entry{Type: "total", Count: 3, Size: 7}, Total(entries))
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "0 B", FormatSize(0))
+	assert.Equal(t, "1023 B", FormatSize(1023))
+	assert.Equal(t, "1.5 KiB", FormatSize(1536))
+	assert.Equal(t, "2.0 MiB", FormatSize(2*1024*1024))
+	assert.Equal(t, "1.0 GiB", FormatSize(1024*1024*1024))
+}
@@ -0,0 +1,185 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extraction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ApplyRules extracts the values of the fields matched by the given rules from each config's JSON template into
+// parameters. Rules are applied in order, values that are already parameterized are not extracted again.
+// It modifies the given configsPerType map.
+func ApplyRules(configsPerType project.ConfigsPerType, rules []Rule) (project.ConfigsPerType, error) {
+	if len(rules) == 0 {
+		return configsPerType, nil
+	}
+
+	configsByID := map[string]config.Config{}
+	for _, cfgs := range configsPerType {
+		for _, c := range cfgs {
+			if c.OriginObjectId != "" {
+				configsByID[c.OriginObjectId] = c
+			}
+		}
+	}
+
+	for _, cfgs := range configsPerType {
+		for _, c := range cfgs {
+			if err := applyRules(c, rules, configsByID); err != nil {
+				return nil, fmt.Errorf("failed to apply extraction rules to %s: %w", c.Coordinate, err)
+			}
+		}
+	}
+	return configsPerType, nil
+}
+
+func applyRules(c config.Config, rules []Rule, configsByID map[string]config.Config) error {
+	content, err := c.Template.Content()
+	if err != nil {
+		return err
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+		log.WithFields(field.Coordinate(c.Coordinate), field.Error(err)).Debug("Unable to parse template of %s, skipping extraction rules: %v", c.Coordinate, err)
+		return nil
+	}
+
+	extracted := map[string]bool{}
+	for _, r := range rules {
+		if len(r.Types) > 0 && !slices.Contains(r.Types, c.Coordinate.Type) {
+			continue
+		}
+
+		for _, v := range findFieldValues(parsed, r.Field) {
+			paramName := uniqueParameterName(extracted, r.parameterName())
+
+			replaced := false
+			for _, encoded := range encodings(v) {
+				pattern := regexp.MustCompile(`("` + regexp.QuoteMeta(r.Field) + `"\s*:\s*)` + regexp.QuoteMeta(encoded))
+				if pattern.MatchString(content) {
+					content = pattern.ReplaceAllString(content, fmt.Sprintf(`${1}"{{ .%s }}"`, paramName))
+					replaced = true
+				}
+			}
+			if !replaced {
+				log.WithFields(field.Coordinate(c.Coordinate)).Debug("Unable to extract field %q of %s", r.Field, c.Coordinate)
+				continue
+			}
+
+			c.Parameters[paramName] = newParameter(c, r.As, v, configsByID)
+			extracted[paramName] = true
+		}
+	}
+
+	if len(extracted) == 0 {
+		return nil
+	}
+	return c.Template.UpdateContent(content)
+}
+
+// newParameter creates the parameter of the given kind for an extracted value
+func newParameter(c config.Config, kind Kind, v string, configsByID map[string]config.Config) parameter.Parameter {
+	if kind == ReferenceKind {
+		if target, found := configsByID[v]; found && target.Coordinate != c.Coordinate {
+			return reference.NewWithCoordinate(target.Coordinate, "id")
+		}
+		log.WithFields(field.Coordinate(c.Coordinate)).Debug("No downloaded config has the ID %q referenced by %s, extracting it as value", v, c.Coordinate)
+	}
+	return value.New(v)
+}
+
+func (r Rule) parameterName() string {
+	name := r.Parameter
+	if name == "" {
+		name = r.Field
+	}
+	return strings.ReplaceAll(name, "-", "_") // golang template keys must not contain hyphens
+}
+
+// findFieldValues returns the distinct string values of all fields with the given name in the given JSON value, in
+// a stable order. Values that already are template parameters are skipped.
+func findFieldValues(v any, fieldName string) []string {
+	var result []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				if s, isString := v[k].(string); isString && k == fieldName {
+					if s != "" && !strings.Contains(s, "{{") && !slices.Contains(result, s) {
+						result = append(result, s)
+					}
+					continue
+				}
+				walk(v[k])
+			}
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+	return result
+}
+
+// uniqueParameterName returns the given name, or the name with a numeric suffix if it was already used
+func uniqueParameterName(used map[string]bool, name string) string {
+	if !used[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// encodings returns the possible JSON encodings of a string value as it may occur in a template
+func encodings(value string) []string {
+	escaped, _ := json.Marshal(value)
+
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	unescaped := strings.TrimSuffix(buf.String(), "\n")
+
+	if unescaped == string(escaped) {
+		return []string{unescaped}
+	}
+	return []string{string(escaped), unescaped}
+}
@@ -0,0 +1,155 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extraction
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestApplyRules(t *testing.T) {
+	mzCoordinate := coordinate.Coordinate{Project: "p", Type: "management-zone", ConfigId: "mz"}
+
+	tests := []struct {
+		name        string
+		rules       []Rule
+		given       string
+		wantContent string
+		wantParams  config.Parameters
+	}{
+		{
+			name:        "field is extracted into named value parameter",
+			rules:       []Rule{{Field: "displayName", Parameter: "name"}},
+			given:       `{"displayName": "My profile", "enabled": true}`,
+			wantContent: `{"displayName": "{{ .name }}", "enabled": true}`,
+			wantParams:  config.Parameters{"name": value.New("My profile")},
+		},
+		{
+			name:        "parameter name defaults to field name",
+			rules:       []Rule{{Field: "host-name"}},
+			given:       `{"host-name": "example.com"}`,
+			wantContent: `{"host-name": "{{ .host_name }}"}`,
+			wantParams:  config.Parameters{"host_name": value.New("example.com")},
+		},
+		{
+			name:        "field is extracted at any depth and distinct values get distinct parameters",
+			rules:       []Rule{{Field: "managementZoneId", As: ReferenceKind}},
+			given:       `{"rules": [{"managementZoneId": "123"}, {"managementZoneId": "456"}, {"managementZoneId": "123"}]}`,
+			wantContent: `{"rules": [{"managementZoneId": "{{ .managementZoneId }}"}, {"managementZoneId": "{{ .managementZoneId_2 }}"}, {"managementZoneId": "{{ .managementZoneId }}"}]}`,
+			wantParams: config.Parameters{
+				"managementZoneId":   reference.NewWithCoordinate(mzCoordinate, "id"),
+				"managementZoneId_2": value.New("456"),
+			},
+		},
+		{
+			name:        "other fields with the same value are kept",
+			rules:       []Rule{{Field: "displayName"}},
+			given:       `{"displayName": "x", "description": "x"}`,
+			wantContent: `{"displayName": "{{ .displayName }}", "description": "x"}`,
+			wantParams:  config.Parameters{"displayName": value.New("x")},
+		},
+		{
+			name:        "parameterized values and rules for other types are skipped",
+			rules:       []Rule{{Field: "name"}, {Field: "displayName", Types: []string{"builtin:other"}}},
+			given:       `{"name": "{{.name}}", "displayName": "x"}`,
+			wantContent: `{"name": "{{.name}}", "displayName": "x"}`,
+			wantParams:  config.Parameters{},
+		},
+		{
+			name:        "templates that are no valid JSON are skipped",
+			rules:       []Rule{{Field: "displayName"}},
+			given:       `{"displayName": {{.x}}}`,
+			wantContent: `{"displayName": {{.x}}}`,
+			wantParams:  config.Parameters{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config.Config{
+				Coordinate: coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "id"},
+				Template:   template.NewInMemoryTemplate("id", tt.given),
+				Parameters: config.Parameters{},
+			}
+			mz := config.Config{
+				Coordinate:     mzCoordinate,
+				Template:       template.NewInMemoryTemplate("mz", `{}`),
+				Parameters:     config.Parameters{},
+				OriginObjectId: "123",
+			}
+
+			got, err := ApplyRules(project.ConfigsPerType{"alerting-profile": {c}, "management-zone": {mz}}, tt.rules)
+			require.NoError(t, err)
+
+			content, err := got["alerting-profile"][0].Template.Content()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContent, content)
+			assert.Equal(t, tt.wantParams, got["alerting-profile"][0].Parameters)
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "rules.yaml", []byte(`
+rules:
+  - field: displayName
+    parameter: name
+  - field: managementZoneId
+    as: reference
+    types: [ builtin:alerting.profile ]
+`), 0644))
+
+	rules, err := LoadRules(fs, "rules.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Field: "displayName", Parameter: "name"},
+		{Field: "managementZoneId", As: ReferenceKind, Types: []string{"builtin:alerting.profile"}},
+	}, rules)
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing field", "rules:\n  - parameter: name\n"},
+		{"unknown kind", "rules:\n  - field: x\n    as: magic\n"},
+		{"unknown property", "rules:\n  - field: x\n    foo: bar\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "rules.yaml", []byte(tt.content), 0644))
+
+			_, err := LoadRules(fs, "rules.yaml")
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadRules(afero.NewMemMapFs(), "missing.yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extraction
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// Kind defines how the value of an extracted field is stored as parameter
+type Kind string
+
+const (
+	// ValueKind extracts the value of a field into a value parameter
+	ValueKind Kind = "value"
+	// ReferenceKind extracts the value of a field into a reference to the downloaded config with this ID. If no
+	// downloaded config has this ID, the value is extracted into a value parameter instead.
+	ReferenceKind Kind = "reference"
+)
+
+// Rules are the user defined extraction rules, as defined in a rules file:
+//
+//	rules:
+//	  - field: displayName
+//	    parameter: name
+//	  - field: managementZoneId
+//	    as: reference
+//	    types: [ builtin:alerting.profile ]
+type Rules struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule extracts all string values of a JSON field, at any depth of a template, into parameters
+type Rule struct {
+	// Field is the name of the JSON field whose values are extracted
+	Field string `yaml:"field"`
+	// Parameter is the name of the parameter to extract the value into. It defaults to the name of the field.
+	// Existing parameters of the same name, e.g. 'name', are replaced.
+	Parameter string `yaml:"parameter,omitempty"`
+	// Kind of the parameter, defaults to ValueKind
+	As Kind `yaml:"as,omitempty"`
+	// Types restricts the rule to configs of the given APIs or settings schemas. If empty, the rule applies to all configs.
+	Types []string `yaml:"types,omitempty"`
+}
+
+// LoadRules reads and validates the extraction rules of the given file
+func LoadRules(fs afero.Fs, file string) ([]Rule, error) {
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extraction rules %q: %w", file, err)
+	}
+
+	var r Rules
+	if err := yaml.UnmarshalStrict(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse extraction rules %q: %w", file, err)
+	}
+
+	var errs []error
+	for i, rule := range r.Rules {
+		if err := rule.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid extraction rule %d in %q: %w", i+1, file, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return r.Rules, nil
+}
+
+func (r Rule) validate() error {
+	if r.Field == "" {
+		return errors.New("'field' must be set")
+	}
+	if r.As != "" && r.As != ValueKind && r.As != ReferenceKind {
+		return fmt.Errorf("unknown kind %q, must be %q or %q", r.As, ValueKind, ReferenceKind)
+	}
+	return nil
+}
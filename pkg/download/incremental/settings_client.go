@@ -0,0 +1,95 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/filter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+)
+
+// SettingsClient wraps a client.SettingsClient and only fetches the values of settings objects which changed since
+// the State was recorded. Unchanged objects are taken from the State, which is updated with every listing.
+type SettingsClient struct {
+	client.SettingsClient
+	state *State
+}
+
+// NewSettingsClient returns a SettingsClient fetching changed settings objects using c and recording them in state.
+func NewSettingsClient(c client.SettingsClient, state *State) *SettingsClient {
+	return &SettingsClient{
+		SettingsClient: c,
+		state:          state,
+	}
+}
+
+// ListSettings returns all settings objects of the given schema. If the schema was downloaded before, only the
+// metadata of the objects is listed and the values of new or modified objects are fetched one by one.
+func (c *SettingsClient) ListSettings(ctx context.Context, schemaID string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+	previous, found := c.state.settings(schemaID)
+	if !found {
+		objects, err := c.SettingsClient.ListSettings(ctx, schemaID, dtclient.ListSettingsOptions{})
+		if err != nil {
+			return nil, err
+		}
+		c.state.setSettings(schemaID, objects)
+		return filter.FilterSlice(objects, opts.Filter), nil
+	}
+
+	current, err := c.SettingsClient.ListSettings(ctx, schemaID, dtclient.ListSettingsOptions{DiscardValue: true})
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]dtclient.DownloadSettingsObject, len(previous))
+	for _, o := range previous {
+		known[o.ObjectId] = o
+	}
+
+	result := make([]dtclient.DownloadSettingsObject, 0, len(current))
+	changed := 0
+	for _, o := range current {
+		if k, exists := known[o.ObjectId]; exists && unchanged(k, o) {
+			result = append(result, k)
+			continue
+		}
+
+		fetched, err := c.SettingsClient.GetSettingById(o.ObjectId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch changed settings object %q of schema %q: %w", o.ObjectId, schemaID, err)
+		}
+		o.Value = fetched.Value
+		result = append(result, o)
+		changed++
+	}
+
+	log.WithCtxFields(ctx).Debug("Fetched %d new or changed of %d settings objects for schema %q", changed, len(current), schemaID)
+
+	c.state.setSettings(schemaID, result)
+	return filter.FilterSlice(result, opts.Filter), nil
+}
+
+// unchanged reports whether the current object is the same as the previously downloaded one. Objects without a
+// modification timestamp are always considered changed.
+func unchanged(previous, current dtclient.DownloadSettingsObject) bool {
+	return current.Modified != 0 &&
+		previous.Modified == current.Modified &&
+		previous.SchemaVersion == current.SchemaVersion
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+const schemaID = "builtin:alerting.profile"
+
+func TestSettingsClient_ListSettings_FullDownloadForUnknownSchema(t *testing.T) {
+	objects := []dtclient.DownloadSettingsObject{
+		{ObjectId: "o1", SchemaId: schemaID, Modified: 1, Value: []byte(`{}`)},
+	}
+	c := client.NewMockSettingsClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), schemaID, dtclient.ListSettingsOptions{}).Return(objects, nil)
+
+	state := NewState()
+	got, err := NewSettingsClient(c, state).ListSettings(context.TODO(), schemaID, dtclient.ListSettingsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, objects, got)
+	assert.Equal(t, objects, state.Settings[schemaID])
+}
+
+func TestSettingsClient_ListSettings_OnlyFetchesChangedObjects(t *testing.T) {
+	state := NewState()
+	state.setSettings(schemaID, []dtclient.DownloadSettingsObject{
+		{ObjectId: "unchanged", SchemaId: schemaID, SchemaVersion: "1", Modified: 1, Value: []byte(`{"v":"unchanged"}`)},
+		{ObjectId: "modified", SchemaId: schemaID, SchemaVersion: "1", Modified: 1, Value: []byte(`{"v":"old"}`)},
+		{ObjectId: "upgraded", SchemaId: schemaID, SchemaVersion: "1", Modified: 1, Value: []byte(`{"v":"old"}`)},
+		{ObjectId: "deleted", SchemaId: schemaID, SchemaVersion: "1", Modified: 1, Value: []byte(`{"v":"deleted"}`)},
+	})
+
+	c := client.NewMockSettingsClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), schemaID, dtclient.ListSettingsOptions{DiscardValue: true}).Return([]dtclient.DownloadSettingsObject{
+		{ObjectId: "unchanged", SchemaId: schemaID, SchemaVersion: "1", Modified: 1},
+		{ObjectId: "modified", SchemaId: schemaID, SchemaVersion: "1", Modified: 2},
+		{ObjectId: "upgraded", SchemaId: schemaID, SchemaVersion: "2", Modified: 1},
+		{ObjectId: "new", SchemaId: schemaID, SchemaVersion: "1", Modified: 3},
+	}, nil)
+	c.EXPECT().GetSettingById("modified").Return(&dtclient.DownloadSettingsObject{ObjectId: "modified", Value: []byte(`{"v":"modified"}`)}, nil)
+	c.EXPECT().GetSettingById("upgraded").Return(&dtclient.DownloadSettingsObject{ObjectId: "upgraded", Value: []byte(`{"v":"upgraded"}`)}, nil)
+	c.EXPECT().GetSettingById("new").Return(&dtclient.DownloadSettingsObject{ObjectId: "new", Value: []byte(`{"v":"new"}`)}, nil)
+
+	got, err := NewSettingsClient(c, state).ListSettings(context.TODO(), schemaID, dtclient.ListSettingsOptions{})
+	require.NoError(t, err)
+
+	want := []dtclient.DownloadSettingsObject{
+		{ObjectId: "unchanged", SchemaId: schemaID, SchemaVersion: "1", Modified: 1, Value: []byte(`{"v":"unchanged"}`)},
+		{ObjectId: "modified", SchemaId: schemaID, SchemaVersion: "1", Modified: 2, Value: []byte(`{"v":"modified"}`)},
+		{ObjectId: "upgraded", SchemaId: schemaID, SchemaVersion: "2", Modified: 1, Value: []byte(`{"v":"upgraded"}`)},
+		{ObjectId: "new", SchemaId: schemaID, SchemaVersion: "1", Modified: 3, Value: []byte(`{"v":"new"}`)},
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, want, state.Settings[schemaID])
+}
+
+func TestSettingsClient_ListSettings_ObjectsWithoutTimestampAreRefetched(t *testing.T) {
+	state := NewState()
+	state.setSettings(schemaID, []dtclient.DownloadSettingsObject{{ObjectId: "o1", Value: []byte(`{"v":"old"}`)}})
+
+	c := client.NewMockSettingsClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), schemaID, dtclient.ListSettingsOptions{DiscardValue: true}).Return([]dtclient.DownloadSettingsObject{{ObjectId: "o1"}}, nil)
+	c.EXPECT().GetSettingById("o1").Return(&dtclient.DownloadSettingsObject{ObjectId: "o1", Value: []byte(`{"v":"new"}`)}, nil)
+
+	got, err := NewSettingsClient(c, state).ListSettings(context.TODO(), schemaID, dtclient.ListSettingsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []dtclient.DownloadSettingsObject{{ObjectId: "o1", Value: []byte(`{"v":"new"}`)}}, got)
+}
+
+func TestSettingsClient_ListSettings_FetchErrorIsReturned(t *testing.T) {
+	state := NewState()
+	state.setSettings(schemaID, []dtclient.DownloadSettingsObject{})
+
+	c := client.NewMockSettingsClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), schemaID, gomock.Any()).Return([]dtclient.DownloadSettingsObject{{ObjectId: "o1", Modified: 1}}, nil)
+	c.EXPECT().GetSettingById("o1").Return(nil, assert.AnError)
+
+	_, err := NewSettingsClient(c, state).ListSettings(context.TODO(), schemaID, dtclient.ListSettingsOptions{})
+	assert.ErrorIs(t, err, assert.AnError)
+}
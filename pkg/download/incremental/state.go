@@ -0,0 +1,111 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/spf13/afero"
+	iofs "io/fs"
+	"sync"
+)
+
+// StateFileName is the name of the file, stored in the downloaded project folder, that holds the state of an
+// incremental download.
+const StateFileName = ".download-state.json"
+
+// stateVersion is increased whenever the format of the state file changes in an incompatible way. State files of a
+// different version are ignored, resulting in a full download.
+const stateVersion = 1
+
+// State holds the settings objects downloaded by a previous run, per schema ID.
+type State struct {
+	Version  int                                          `json:"version"`
+	Settings map[string][]dtclient.DownloadSettingsObject `json:"settings"`
+
+	mutex sync.Mutex
+}
+
+// NewState returns an empty State.
+func NewState() *State {
+	return &State{
+		Version:  stateVersion,
+		Settings: make(map[string][]dtclient.DownloadSettingsObject),
+	}
+}
+
+// LoadState reads the State stored in the given file. If the file does not exist, or was written by an incompatible
+// version, an empty State is returned.
+func LoadState(fs afero.Fs, file string) (*State, error) {
+	data, err := afero.ReadFile(fs, file)
+	if errors.Is(err, iofs.ErrNotExist) {
+		log.Debug("No download state found at %q, downloading everything", file)
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download state %q: %w", file, err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse download state %q: %w", file, err)
+	}
+
+	if s.Version != stateVersion {
+		log.Warn("Download state %q has unsupported version %d, downloading everything", file, s.Version)
+		return NewState(), nil
+	}
+
+	if s.Settings == nil {
+		s.Settings = make(map[string][]dtclient.DownloadSettingsObject)
+	}
+	return &s, nil
+}
+
+// Write stores the State in the given file.
+func (s *State) Write(fs afero.Fs, file string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to serialize download state: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write download state %q: %w", file, err)
+	}
+	return nil
+}
+
+func (s *State) settings(schemaID string) ([]dtclient.DownloadSettingsObject, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	objects, found := s.Settings[schemaID]
+	return objects, found
+}
+
+func (s *State) setSettings(schemaID string, objects []dtclient.DownloadSettingsObject) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Settings[schemaID] = objects
+}
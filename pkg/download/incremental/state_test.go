@@ -0,0 +1,65 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLoadState_MissingFileReturnsEmptyState(t *testing.T) {
+	s, err := LoadState(afero.NewMemMapFs(), StateFileName)
+	require.NoError(t, err)
+	assert.Equal(t, stateVersion, s.Version)
+	assert.Empty(t, s.Settings)
+}
+
+func TestLoadState_UnsupportedVersionReturnsEmptyState(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, StateFileName, []byte(`{"version": 999, "settings": {"a": [{"objectId": "o"}]}}`), 0644))
+
+	s, err := LoadState(fs, StateFileName)
+	require.NoError(t, err)
+	assert.Empty(t, s.Settings)
+}
+
+func TestLoadState_InvalidFileFails(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, StateFileName, []byte(`{`), 0644))
+
+	_, err := LoadState(fs, StateFileName)
+	assert.Error(t, err)
+}
+
+func TestState_WriteAndLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := NewState()
+	s.setSettings("builtin:alerting.profile", []dtclient.DownloadSettingsObject{
+		{ObjectId: "o1", SchemaId: "builtin:alerting.profile", SchemaVersion: "1.0", Scope: "environment", Modified: 42, Value: []byte(`{"name":"a"}`)},
+	})
+
+	require.NoError(t, s.Write(fs, StateFileName))
+
+	loaded, err := LoadState(fs, StateFileName)
+	require.NoError(t, err)
+	assert.Equal(t, s.Settings, loaded.Settings)
+}
@@ -0,0 +1,108 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package managed
+
+import (
+	"encoding/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"slices"
+)
+
+// Filter returns only the downloaded configs which were deployed by monaco. See IsManaged for how this is detected.
+func Filter(configs project.ConfigsPerType, apis api.APIs) project.ConfigsPerType {
+	result := make(project.ConfigsPerType, len(configs))
+	for t, cs := range configs {
+		var managed []config.Config
+		for _, c := range cs {
+			if IsManaged(c, apis) {
+				managed = append(managed, c)
+			} else {
+				log.WithFields(field.Coordinate(c.Coordinate)).Debug("Skipping config %s as it is not managed by monaco", c.Coordinate)
+			}
+		}
+		if len(managed) > 0 {
+			result[t] = managed
+		}
+	}
+	return result
+}
+
+// IsManaged returns whether the given downloaded config was deployed by monaco:
+//   - Settings objects and documents are managed if their externalId matches the pattern generated by monaco.
+//   - Classic configs are managed if their API supports ownership tags and the payload holds the managed-by tag.
+//   - SLOs are managed if their tags hold the managed-by tag.
+//
+// Configs of all other types carry no ownership information and are never considered managed.
+func IsManaged(c config.Config, apis api.APIs) bool {
+	switch t := c.Type.(type) {
+	case config.SettingsType:
+		_, err := idutils.DecodeExternalIDForSettingsObject(c.OriginExternalId)
+		return err == nil
+	case config.DocumentType:
+		return idutils.IsExternalIDForDocument(c.OriginExternalId)
+	case config.ClassicApiType:
+		a, ok := apis[t.Api]
+		if !ok || len(a.OwnershipTagsPath) == 0 || c.Template == nil {
+			return false
+		}
+		content, err := c.Template.Content()
+		if err != nil {
+			return false
+		}
+		return slices.Contains(ownershipTags(a.OwnershipTagsPath, content), idutils.ManagedByTag)
+	case config.SLOType:
+		if c.Template == nil {
+			return false
+		}
+		content, err := c.Template.Content()
+		if err != nil {
+			return false
+		}
+		return slices.Contains(ownershipTags(idutils.SLOOwnershipTagsPath, content), idutils.ManagedByTag)
+	default:
+		return false
+	}
+}
+
+// ownershipTags returns the string values of the array at the given path of the JSON payload
+func ownershipTags(path []string, payload string) []string {
+	var obj any
+	if err := json.Unmarshal([]byte(payload), &obj); err != nil {
+		return nil
+	}
+	for _, key := range path {
+		m, ok := obj.(map[string]any)
+		if !ok {
+			return nil
+		}
+		obj = m[key]
+	}
+
+	values, _ := obj.([]any)
+	tags := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	return tags
+}
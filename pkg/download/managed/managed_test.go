@@ -0,0 +1,145 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package managed_test
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/managed"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestIsManaged(t *testing.T) {
+	apis := api.NewAPIs()
+
+	settingsExternalID, err := idutils.GenerateExternalIDForSettingsObject(coordinate.Coordinate{Project: "p", Type: "builtin:tags.auto-tagging", ConfigId: "c"})
+	require.NoError(t, err)
+	documentExternalID, err := idutils.GenerateExternalIDForDocument(coordinate.Coordinate{Project: "p", Type: "dashboard-document", ConfigId: "c"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		config config.Config
+		want   bool
+	}{
+		{
+			name:   "settings with monaco external ID",
+			config: config.Config{Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}, OriginExternalId: settingsExternalID},
+			want:   true,
+		},
+		{
+			name:   "settings with foreign external ID",
+			config: config.Config{Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}, OriginExternalId: "terraform:1234"},
+			want:   false,
+		},
+		{
+			name:   "settings without external ID",
+			config: config.Config{Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}},
+			want:   false,
+		},
+		{
+			name:   "document with monaco external ID",
+			config: config.Config{Type: config.DashboardType, OriginExternalId: documentExternalID},
+			want:   true,
+		},
+		{
+			name:   "document with foreign external ID",
+			config: config.Config{Type: config.DashboardType, OriginExternalId: "my-dashboard"},
+			want:   false,
+		},
+		{
+			name: "classic API without template content",
+			config: config.Config{
+				Type: config.ClassicApiType{Api: api.Dashboard},
+			},
+			want: false,
+		},
+		{
+			name: "dashboard with ownership tags",
+			config: config.Config{
+				Type:     config.ClassicApiType{Api: api.Dashboard},
+				Template: template.NewInMemoryTemplate("id", `{"dashboardMetadata": {"tags": ["team-a", "managed-by:monaco"]}}`),
+			},
+			want: true,
+		},
+		{
+			name: "dashboard without ownership tags",
+			config: config.Config{
+				Type:     config.ClassicApiType{Api: api.Dashboard},
+				Template: template.NewInMemoryTemplate("id", `{"dashboardMetadata": {"tags": ["team-a"]}}`),
+			},
+			want: false,
+		},
+		{
+			name: "classic API without ownership tag support",
+			config: config.Config{
+				Type:     config.ClassicApiType{Api: "alerting-profile"},
+				Template: template.NewInMemoryTemplate("id", `{"tags": ["managed-by:monaco"]}`),
+			},
+			want: false,
+		},
+		{
+			name: "SLO with ownership tags",
+			config: config.Config{
+				Type:     config.SLOType{},
+				Template: template.NewInMemoryTemplate("id", `{"name": "slo", "tags": ["managed-by:monaco"]}`),
+			},
+			want: true,
+		},
+		{
+			name: "SLO without ownership tags",
+			config: config.Config{
+				Type:     config.SLOType{},
+				Template: template.NewInMemoryTemplate("id", `{"name": "slo"}`),
+			},
+			want: false,
+		},
+		{
+			name:   "other types are never managed",
+			config: config.Config{Type: config.BucketType{}},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, managed.IsManaged(tt.config, apis))
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	settingsExternalID, err := idutils.GenerateExternalIDForSettingsObject(coordinate.Coordinate{Project: "p", Type: "builtin:tags.auto-tagging", ConfigId: "c"})
+	require.NoError(t, err)
+
+	managedConfig := config.Config{Coordinate: coordinate.Coordinate{ConfigId: "managed"}, Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}, OriginExternalId: settingsExternalID}
+	unmanagedConfig := config.Config{Coordinate: coordinate.Coordinate{ConfigId: "unmanaged"}, Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}}
+
+	got := managed.Filter(project.ConfigsPerType{
+		"builtin:tags.auto-tagging": {managedConfig, unmanagedConfig},
+		"builtin:alerting.profile":  {unmanagedConfig},
+	}, api.NewAPIs())
+
+	assert.Equal(t, project.ConfigsPerType{"builtin:tags.auto-tagging": {managedConfig}}, got)
+}
@@ -0,0 +1,357 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	mystrings "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/spf13/afero"
+	iofs "io/fs"
+	"path/filepath"
+	"reflect"
+)
+
+// mergeEnvironment is the name of the environment an existing project is loaded for when merging downloaded configs
+const mergeEnvironment = "merge"
+
+// LoadProjectToMerge loads the configs of the existing project in projectFolder, so that downloaded configs can be
+// merged into it. The project is loaded on its own, environment and group overrides are not taken into account.
+func LoadProjectToMerge(fs afero.Fs, projectFolder string) ([]config.Config, error) {
+	projectFolder = filepath.Clean(projectFolder)
+	projectName := filepath.Base(projectFolder)
+
+	m := manifest.Manifest{
+		Projects: manifest.ProjectDefinitionByProjectID{
+			projectName: {Name: projectName, Path: projectName},
+		},
+		Environments: manifest.Environments{
+			mergeEnvironment: {Name: mergeEnvironment, Group: "default"},
+		},
+	}
+
+	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
+		KnownApis:       api.NewAPIs().GetApiNameLookup(),
+		WorkingDir:      filepath.Dir(projectFolder),
+		Manifest:        m,
+		ParametersSerde: config.DefaultParameterParsers,
+	}, nil)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load project %q to merge into: %w", projectFolder, errors.Join(errs...))
+	}
+
+	var result []config.Config
+	for _, p := range projects {
+		for _, configs := range p.Configs[mergeEnvironment] {
+			result = append(result, configs...)
+		}
+	}
+
+	if err := verifyDownloadLayout(fs, projectFolder, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// verifyDownloadLayout ensures that all config files of the project are stored where they would be written, i.e.
+// in one 'config.yaml' per config type. Otherwise, merging would write configs a second time.
+func verifyDownloadLayout(fs afero.Fs, projectFolder string, configs []config.Config) error {
+	expected := make(map[string]struct{})
+	for _, c := range configs {
+		expected[filepath.Join(projectFolder, mystrings.Sanitize(c.Coordinate.Type), "config.yaml")] = struct{}{}
+	}
+
+	return afero.Walk(fs, projectFolder, func(path string, info iofs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !files.IsYamlFileExtension(path) {
+			return nil
+		}
+		if _, found := expected[filepath.Clean(path)]; !found {
+			return fmt.Errorf("unable to merge into project %q: config file %q is not stored in the folder structure created by download", projectFolder, path)
+		}
+		return nil
+	})
+}
+
+// Merge merges the downloaded configs into the configs of an existing project.
+//
+// Downloaded configs are matched with existing configs of the same type by their origin object ID, the external ID
+// generated for settings objects, or the config ID of classic configs. If a matched existing config still renders to
+// the same JSON as the downloaded config, the existing config is kept with all its parameters. Otherwise, the
+// downloaded config replaces it, keeping the coordinate of the existing config so that references to it stay valid.
+// Downloaded configs without a match are added, existing configs without a match are kept.
+func Merge(downloaded project.ConfigsPerType, existing []config.Config) project.ConfigsPerType {
+	matches := matchExisting(downloaded, existing)
+	lookup := newMergeLookup(downloaded, existing, matches)
+	templateUsages := countTemplateUsages(existing)
+
+	// downloaded coordinates are replaced by the coordinates of the existing configs they were matched with
+	renamed := make(map[coordinate.Coordinate]coordinate.Coordinate, len(matches))
+	for d, e := range matches {
+		renamed[d] = e.Coordinate
+	}
+
+	result := make(project.ConfigsPerType)
+	matchedExisting := make(map[coordinate.Coordinate]struct{}, len(matches))
+	for t, configs := range downloaded {
+		for _, d := range configs {
+			e, found := matches[d.Coordinate]
+			if !found {
+				result[t] = append(result[t], renameReferences(d, renamed))
+				continue
+			}
+			matchedExisting[e.Coordinate] = struct{}{}
+
+			lg := log.WithFields(field.Coordinate(e.Coordinate))
+			drifted, err := hasDrifted(lookup, e, d)
+			if err != nil {
+				lg.WithFields(field.Error(err)).Warn("Unable to compare config %s with downloaded object, keeping existing config: %v", e.Coordinate, err)
+				result[t] = append(result[t], asWritable(e))
+				continue
+			}
+			if !drifted {
+				lg.Debug("Config %s is unchanged, keeping existing config", e.Coordinate)
+				result[t] = append(result[t], asWritable(e))
+				continue
+			}
+
+			lg.Info("Config %s drifted, replacing it with downloaded object", e.Coordinate)
+			result[t] = append(result[t], replaceExisting(d, e, renamed, templateUsages))
+		}
+	}
+
+	for _, e := range existing {
+		if _, found := matchedExisting[e.Coordinate]; !found {
+			log.WithFields(field.Coordinate(e.Coordinate)).Debug("Config %s was not downloaded, keeping existing config", e.Coordinate)
+			result[e.Coordinate.Type] = append(result[e.Coordinate.Type], asWritable(e))
+		}
+	}
+
+	return result
+}
+
+// matchExisting returns the existing config matched with each downloaded config, by coordinate of the downloaded config
+func matchExisting(downloaded project.ConfigsPerType, existing []config.Config) map[coordinate.Coordinate]config.Config {
+	byKey := make(map[string]config.Config)
+	for _, e := range existing {
+		for _, k := range existingMatchKeys(e) {
+			byKey[k] = e
+		}
+	}
+
+	matches := make(map[coordinate.Coordinate]config.Config)
+	claimed := make(map[coordinate.Coordinate]struct{})
+	for _, configs := range downloaded {
+		for _, d := range configs {
+			for _, k := range downloadedMatchKeys(d) {
+				e, found := byKey[k]
+				if !found {
+					continue
+				}
+				if _, taken := claimed[e.Coordinate]; taken {
+					continue
+				}
+				matches[d.Coordinate] = e
+				claimed[e.Coordinate] = struct{}{}
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func existingMatchKeys(c config.Config) []string {
+	keys := []string{matchKey(c.Coordinate.Type, "id", c.Coordinate.ConfigId)}
+	if c.OriginObjectId != "" {
+		keys = append(keys, matchKey(c.Coordinate.Type, "object", c.OriginObjectId))
+	}
+	if c.Type.ID() == config.SettingsTypeId {
+		for _, coord := range []coordinate.Coordinate{c.Coordinate, {Type: c.Coordinate.Type, ConfigId: c.Coordinate.ConfigId}} {
+			if externalID, err := idutils.GenerateExternalIDForSettingsObject(coord); err == nil {
+				keys = append(keys, matchKey(c.Coordinate.Type, "external", externalID))
+			}
+		}
+	}
+	return keys
+}
+
+func downloadedMatchKeys(c config.Config) []string {
+	var keys []string
+	if c.OriginObjectId != "" {
+		keys = append(keys, matchKey(c.Coordinate.Type, "object", c.OriginObjectId))
+	}
+	if c.OriginExternalId != "" {
+		keys = append(keys, matchKey(c.Coordinate.Type, "external", c.OriginExternalId))
+	}
+	return append(keys, matchKey(c.Coordinate.Type, "id", c.Coordinate.ConfigId))
+}
+
+func matchKey(configType, kind, id string) string {
+	return configType + "|" + kind + "|" + id
+}
+
+// newMergeLookup creates an EntityLookup to resolve the references of both downloaded and existing configs. Matched
+// configs resolve to the same properties, so that they render to the same JSON if nothing else changed.
+func newMergeLookup(downloaded project.ConfigsPerType, existing []config.Config, matches map[coordinate.Coordinate]config.Config) *entities.EntityMap {
+	lookup := entities.New()
+	for _, e := range existing {
+		// unmatched existing configs resolve to IDs that never match a downloaded object
+		lookup.Put(entities.ResolvedEntity{
+			Coordinate: e.Coordinate,
+			Properties: parameter.Properties{config.IdParameter: "unknown:" + e.Coordinate.String()},
+		})
+	}
+
+	for _, configs := range downloaded {
+		for _, d := range configs {
+			props := parameter.Properties{config.IdParameter: remoteID(d)}
+			if name, err := config.GetNameForConfig(d); err == nil {
+				if _, isString := name.(string); isString {
+					props[config.NameParameter] = name
+				}
+			}
+			lookup.Put(entities.ResolvedEntity{Coordinate: d.Coordinate, Properties: props})
+			if e, found := matches[d.Coordinate]; found {
+				lookup.Put(entities.ResolvedEntity{Coordinate: e.Coordinate, Properties: props})
+			}
+		}
+	}
+	return lookup
+}
+
+func remoteID(c config.Config) string {
+	if c.OriginObjectId != "" {
+		return c.OriginObjectId
+	}
+	return c.Coordinate.ConfigId
+}
+
+// hasDrifted reports whether the existing config renders to a different JSON or scope than the downloaded config
+func hasDrifted(lookup config.EntityLookup, existing, downloaded config.Config) (bool, error) {
+	existingJSON, existingProps, err := render(lookup, existing)
+	if err != nil {
+		return false, err
+	}
+	downloadedJSON, downloadedProps, err := render(lookup, downloaded)
+	if err != nil {
+		return false, err
+	}
+
+	if !reflect.DeepEqual(existingJSON, downloadedJSON) {
+		return true, nil
+	}
+	return existingProps[config.ScopeParameter] != downloadedProps[config.ScopeParameter], nil
+}
+
+func render(lookup config.EntityLookup, c config.Config) (any, parameter.Properties, error) {
+	props, errs := c.ResolveParameterValues(lookup)
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("failed to resolve parameters of %s: %w", c.Coordinate, errors.Join(errs...))
+	}
+
+	rendered, err := template.Render(c.Template, props)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(rendered), &parsed); err != nil {
+		return nil, nil, fmt.Errorf("rendered template of %s is not valid JSON: %w", c.Coordinate, err)
+	}
+	return parsed, props, nil
+}
+
+// replaceExisting returns the downloaded config with the coordinate of the existing config. The template of the
+// existing config is overwritten, unless it is shared with other configs.
+func replaceExisting(downloaded, existing config.Config, renamed map[coordinate.Coordinate]coordinate.Coordinate, templateUsages map[string]int) config.Config {
+	c := renameReferences(downloaded, renamed)
+	c.Coordinate = existing.Coordinate
+	c.Skip = existing.Skip
+
+	if path, ok := templatePath(existing.Template); ok && templateUsages[path] == 1 {
+		if content, err := downloaded.Template.Content(); err == nil {
+			c.Template = template.NewInMemoryTemplateWithPath(path, content)
+		}
+	}
+	return c
+}
+
+// renameReferences returns a copy of the config with all references to renamed coordinates replaced
+func renameReferences(c config.Config, renamed map[coordinate.Coordinate]coordinate.Coordinate) config.Config {
+	params := make(config.Parameters, len(c.Parameters))
+	for name, p := range c.Parameters {
+		if ref, isRef := p.(*reference.ReferenceParameter); isRef {
+			if to, found := renamed[ref.Config]; found {
+				p = reference.NewWithCoordinate(to, ref.Property)
+			}
+		}
+		params[name] = p
+	}
+	c.Parameters = params
+	return c
+}
+
+// asWritable prepares a loaded config to be written again. Loaded templates are converted to in-memory templates
+// keeping their original path and partials.
+func asWritable(c config.Config) config.Config {
+	c.Environment = ""
+	c.Group = ""
+	if path, ok := templatePath(c.Template); ok {
+		if content, err := c.Template.Content(); err == nil {
+			c.Template = template.NewInMemoryTemplateWithPartials(path, content, template.Partials(c.Template))
+		}
+	}
+	return c
+}
+
+func templatePath(t template.Template) (string, bool) {
+	switch t := t.(type) {
+	case *template.FileBasedTemplate:
+		return t.FilePath(), true
+	case *template.InMemoryTemplate:
+		if p := t.FilePath(); p != nil {
+			return *p, true
+		}
+	}
+	return "", false
+}
+
+func countTemplateUsages(configs []config.Config) map[string]int {
+	usages := make(map[string]int)
+	for _, c := range configs {
+		if path, ok := templatePath(c.Template); ok {
+			usages[path]++
+		}
+	}
+	return usages
+}
@@ -0,0 +1,198 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const mergeSchema = "builtin:alerting.profile"
+
+const existingConfigYaml = `configs:
+- id: unchanged
+  config:
+    name: Unchanged
+    template: unchanged.json
+    originObjectId: obj-unchanged
+    parameters:
+      severity: HIGH
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+- id: drifted
+  config:
+    name: Drifted
+    template: drifted.json
+    originObjectId: obj-drifted
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+- id: by-external-id
+  config:
+    name: External
+    template: external.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+- id: not-downloaded
+  config:
+    name: Gone
+    template: gone.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+`
+
+func newMergeTestFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"out/proj/builtinalerting.profile/config.yaml":    existingConfigYaml,
+		"out/proj/builtinalerting.profile/unchanged.json": `{"name": "{{.name}}", "severity": "{{.severity}}"}`,
+		"out/proj/builtinalerting.profile/drifted.json":   `{"name": "{{.name}}", "severity": "LOW"}`,
+		"out/proj/builtinalerting.profile/external.json":  `{"name": "{{.name}}"}`,
+		"out/proj/builtinalerting.profile/gone.json":      `{"name": "{{.name}}"}`,
+	}
+	for path, content := range files {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+	}
+	return fs
+}
+
+func downloadedSetting(t *testing.T, configID, objectID, externalID, content string, params config.Parameters) config.Config {
+	t.Helper()
+	if params == nil {
+		params = config.Parameters{}
+	}
+	params[config.ScopeParameter] = valueParam.New("environment")
+	return config.Config{
+		Template:         template.NewInMemoryTemplate(configID, content),
+		Coordinate:       coordinate.Coordinate{Project: "proj", Type: mergeSchema, ConfigId: configID},
+		Type:             config.SettingsType{SchemaId: mergeSchema},
+		Parameters:       params,
+		OriginObjectId:   objectID,
+		OriginExternalId: externalID,
+	}
+}
+
+func TestMerge(t *testing.T) {
+	fs := newMergeTestFs(t)
+
+	existing, err := LoadProjectToMerge(fs, "out/proj")
+	require.NoError(t, err)
+	require.Len(t, existing, 4)
+
+	externalID, err := idutils.GenerateExternalIDForSettingsObject(coordinate.Coordinate{Project: "proj", Type: mergeSchema, ConfigId: "by-external-id"})
+	require.NoError(t, err)
+
+	downloaded := project.ConfigsPerType{
+		mergeSchema: {
+			downloadedSetting(t, "uuid-unchanged", "obj-unchanged", "", `{"name": "Unchanged", "severity": "HIGH"}`, nil),
+			downloadedSetting(t, "uuid-drifted", "obj-drifted", "", `{"name": "{{.name}}", "severity": "HIGH"}`, config.Parameters{config.NameParameter: valueParam.New("Drifted")}),
+			downloadedSetting(t, "uuid-external", "obj-external", externalID, `{"name": "External"}`, nil),
+			downloadedSetting(t, "uuid-new", "obj-new", "", `{"name": "New"}`, config.Parameters{
+				"ref": reference.New("proj", mergeSchema, "uuid-drifted", "id"),
+			}),
+		},
+	}
+
+	merged := Merge(downloaded, existing)
+	byID := make(map[string]config.Config)
+	for _, c := range merged[mergeSchema] {
+		byID[c.Coordinate.ConfigId] = c
+	}
+	assert.Len(t, byID, 5)
+
+	t.Run("unchanged config keeps its parameters", func(t *testing.T) {
+		c := byID["unchanged"]
+		assert.Equal(t, valueParam.New("HIGH"), c.Parameters["severity"])
+		content, err := c.Template.Content()
+		require.NoError(t, err)
+		assert.Equal(t, `{"name": "{{.name}}", "severity": "{{.severity}}"}`, content)
+	})
+
+	t.Run("drifted config is replaced by the downloaded one at the existing coordinate", func(t *testing.T) {
+		c := byID["drifted"]
+		content, err := c.Template.Content()
+		require.NoError(t, err)
+		assert.Equal(t, `{"name": "{{.name}}", "severity": "HIGH"}`, content)
+		assert.Equal(t, "obj-drifted", c.OriginObjectId)
+	})
+
+	t.Run("configs are matched by external ID", func(t *testing.T) {
+		assert.Contains(t, byID, "by-external-id")
+		assert.NotContains(t, byID, "uuid-external")
+	})
+
+	t.Run("new configs reference the existing coordinates", func(t *testing.T) {
+		c := byID["uuid-new"]
+		assert.Equal(t, reference.New("proj", mergeSchema, "drifted", "id"), c.Parameters["ref"])
+	})
+
+	t.Run("configs that were not downloaded are kept", func(t *testing.T) {
+		assert.Contains(t, byID, "not-downloaded")
+	})
+
+	t.Run("merged project can be written and loaded again", func(t *testing.T) {
+		require.NoError(t, WriteMergedProject(fs, "out/proj", merged, nil))
+
+		reloaded, err := LoadProjectToMerge(fs, "out/proj")
+		require.NoError(t, err)
+		assert.Len(t, reloaded, 5)
+
+		drifted, err := afero.ReadFile(fs, "out/proj/builtinalerting.profile/drifted.json")
+		require.NoError(t, err)
+		assert.Equal(t, `{"name": "{{.name}}", "severity": "HIGH"}`, string(drifted))
+
+		exists, err := afero.Exists(fs, "out/manifest.yaml")
+		require.NoError(t, err)
+		assert.False(t, exists, "no manifest must be written")
+	})
+}
+
+func TestLoadProjectToMerge_FailsForCustomFolderStructure(t *testing.T) {
+	fs := newMergeTestFs(t)
+	custom := `configs:
+- id: custom
+  config:
+    name: Custom
+    template: ../builtinalerting.profile/gone.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+`
+	require.NoError(t, afero.WriteFile(fs, "out/proj/custom/alerting.yaml", []byte(custom), 0644))
+
+	_, err := LoadProjectToMerge(fs, "out/proj")
+	assert.ErrorContains(t, err, "folder structure created by download")
+}
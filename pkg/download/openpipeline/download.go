@@ -0,0 +1,90 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openpipeline
+
+import (
+	"context"
+	"fmt"
+	jsonutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	openPipelineClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/openpipeline"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/internal/templatetools"
+	v2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+// fieldsToRemove are set by the environment and must not be part of a template
+var fieldsToRemove = []string{"id", "version", "updateToken"}
+
+// Download downloads the OpenPipeline configurations of all kinds of data of the environment.
+func Download(client client.OpenPipelineClient, projectName string) (v2.ConfigsPerType, error) {
+	lg := log.WithFields(field.Type(config.OpenPipelineTypeId))
+
+	pipelines, err := client.List(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch all OpenPipeline configurations: %w", err)
+	}
+
+	configs := make([]config.Config, 0, len(pipelines))
+	for _, p := range pipelines {
+		c, err := convertObject(p, projectName)
+		if err != nil {
+			lg.WithFields(field.Error(err)).Error("Failed to convert OpenPipeline configuration %q: %v", p.ID, err)
+			continue
+		}
+		configs = append(configs, c)
+	}
+
+	lg.WithFields(field.F("configsDownloaded", len(configs))).Info("Downloaded %d OpenPipeline configurations.", len(configs))
+	return v2.ConfigsPerType{string(config.OpenPipelineTypeId): configs}, nil
+}
+
+func convertObject(p openPipelineClient.Response, projectName string) (config.Config, error) {
+	if p.ID == "" {
+		return config.Config{}, fmt.Errorf("id is not set")
+	}
+
+	o, err := templatetools.NewJSONObject(p.Data)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("failed to unmarshal OpenPipeline configuration: %w", err)
+	}
+	for _, f := range fieldsToRemove {
+		o.Delete(f)
+	}
+
+	t, err := o.ToJSON()
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	return config.Config{
+		Coordinate: coordinate.Coordinate{
+			Project:  projectName,
+			Type:     string(config.OpenPipelineTypeId),
+			ConfigId: p.ID,
+		},
+		OriginObjectId: p.ID,
+		Type:           config.OpenPipelineType{Kind: p.ID},
+		Template:       template.NewInMemoryTemplate(p.ID, string(jsonutils.MarshalIndent(t))),
+		Parameters:     map[string]parameter.Parameter{},
+	}, nil
+}
@@ -0,0 +1,67 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openpipeline
+
+import (
+	"context"
+	openPipelineClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/openpipeline"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakeClient struct {
+	pipelines []openPipelineClient.Response
+	err       error
+}
+
+func (f fakeClient) Get(context.Context, string) (openPipelineClient.Response, error) {
+	return openPipelineClient.Response{}, nil
+}
+
+func (f fakeClient) List(context.Context) ([]openPipelineClient.Response, error) {
+	return f.pipelines, f.err
+}
+
+func TestDownload(t *testing.T) {
+	c := fakeClient{pipelines: []openPipelineClient.Response{
+		{ID: "logs", Data: []byte(`{"id": "logs", "version": "1", "updateToken": "token", "editable": true, "pipelines": []}`)},
+		{ID: "events", Data: []byte(`{"id": "events", "pipelines": []}`)},
+	}}
+
+	result, err := Download(c, "project")
+	require.NoError(t, err)
+	configs := result[string(config.OpenPipelineTypeId)]
+	require.Len(t, configs, 2)
+
+	assert.Equal(t, "logs", configs[0].Coordinate.ConfigId)
+	assert.Equal(t, config.OpenPipelineType{Kind: "logs"}, configs[0].Type)
+	content, err := configs[0].Template.Content()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"editable": true, "pipelines": []}`, content)
+
+	assert.Equal(t, config.OpenPipelineType{Kind: "events"}, configs[1].Type)
+}
+
+func TestDownload_ReturnsListFailure(t *testing.T) {
+	result, err := Download(fakeClient{err: assert.AnError}, "project")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, result)
+}
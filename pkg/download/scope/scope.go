@@ -0,0 +1,184 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package scope restricts downloads to the objects of certain scopes or management zones. Settings objects are
+// scoped to the environment or an entity. Classic configs of APIs with a parent, e.g. key user actions, are scoped to
+// their parent entity, all other classic configs are scoped to the environment.
+package scope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Environment is the scope of objects that are not scoped to an entity
+const Environment = "environment"
+
+const managementZoneSchema = "builtin:management-zones"
+
+// Filter restricts a download to the objects of certain scopes or management zones. If both are set, objects must
+// match both. The zero value does not filter any objects.
+type Filter struct {
+	// Scopes holds patterns of the scopes to download objects of, e.g. 'environment' or 'HOST-*'. Patterns use the
+	// syntax of path.Match. If empty, objects of all scopes are downloaded.
+	Scopes []string
+	// ManagementZones holds the management zones to download objects of. If empty, objects of all management zones
+	// are downloaded.
+	ManagementZones []ManagementZone
+}
+
+// ManagementZone identifies a management zone by all the ways objects reference it
+type ManagementZone struct {
+	Name string
+	// ObjectID is the ID of the management zone's settings object
+	ObjectID string
+	// NumericID is the ID used by classic APIs and entity selectors
+	NumericID string
+}
+
+// IsSet returns whether the filter restricts the download at all
+func (f Filter) IsSet() bool {
+	return len(f.Scopes) > 0 || len(f.ManagementZones) > 0
+}
+
+// Matches returns whether the object with the given ID, scope, and JSON payload is to be downloaded
+func (f Filter) Matches(id, scope string, payload []byte) bool {
+	return f.MatchesScope(scope) && f.MatchesManagementZone(id, payload)
+}
+
+// MatchesScope returns whether objects of the given scope are to be downloaded
+func (f Filter) MatchesScope(scope string) bool {
+	if len(f.Scopes) == 0 {
+		return true
+	}
+	for _, p := range f.Scopes {
+		if ok, _ := path.Match(p, scope); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesManagementZone returns whether the object with the given ID and JSON payload belongs to one of the management
+// zones of the filter. An object belongs to a management zone if it is the management zone itself, or if its payload
+// references the management zone by ID, or by an entity selector like 'mzId(123)' or 'mzName("name")'.
+func (f Filter) MatchesManagementZone(id string, payload []byte) bool {
+	if len(f.ManagementZones) == 0 {
+		return true
+	}
+	for _, mz := range f.ManagementZones {
+		if id == mz.ObjectID || id == mz.NumericID {
+			return true
+		}
+	}
+
+	d := json.NewDecoder(bytes.NewReader(payload))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return false
+	}
+	return f.references(v)
+}
+
+// references returns whether the given decoded JSON value references any management zone of the filter
+func (f Filter) references(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, e := range v {
+			if f.references(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if f.references(e) {
+				return true
+			}
+		}
+	case json.Number:
+		return f.referencedBy(v.String())
+	case string:
+		return f.referencedBy(v)
+	}
+	return false
+}
+
+func (f Filter) referencedBy(s string) bool {
+	for _, mz := range f.ManagementZones {
+		if s == mz.ObjectID || s == mz.NumericID ||
+			strings.Contains(s, fmt.Sprintf("mzId(%s)", mz.NumericID)) ||
+			strings.Contains(s, fmt.Sprintf("mzName(%q)", mz.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingsClient lists settings objects
+type SettingsClient interface {
+	ListSettings(ctx context.Context, schemaId string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error)
+}
+
+// ResolveManagementZones looks up the management zones with the given names or numeric IDs on the environment.
+// An error is returned if any of them does not exist.
+func ResolveManagementZones(ctx context.Context, c SettingsClient, namesOrIDs []string) ([]ManagementZone, error) {
+	if len(namesOrIDs) == 0 {
+		return nil, nil
+	}
+
+	objects, err := c.ListSettings(ctx, managementZoneSchema, dtclient.ListSettingsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list management zones: %w", err)
+	}
+
+	var all []ManagementZone
+	for _, o := range objects {
+		var v struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(o.Value, &v); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal management zone %q: %w", o.ObjectId, err)
+		}
+		numericID, err := idutils.GetNumericIDForObjectID(o.ObjectId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get numeric ID of management zone %q: %w", v.Name, err)
+		}
+		all = append(all, ManagementZone{Name: v.Name, ObjectID: o.ObjectId, NumericID: strconv.Itoa(numericID)})
+	}
+
+	var result []ManagementZone
+	for _, s := range namesOrIDs {
+		found := false
+		for _, mz := range all {
+			if s == mz.Name || s == mz.NumericID {
+				result = append(result, mz)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("management zone %q does not exist", s)
+		}
+	}
+	return result, nil
+}
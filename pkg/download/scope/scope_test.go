@@ -0,0 +1,106 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scope_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// mzObjectID is the object ID of a management zone with the numeric ID -4292415658385853785
+const mzObjectID = "vu9U3hXa3q0AAAABABhidWlsdGluOm1hbmFnZW1lbnQtem9uZXMABnRlbmFudAAGdGVuYW50ACRjNDZlNDZiMy02ZDk2LTMyYTctOGI1Yi1mNjExNzcyZDAxNjW-71TeFdrerQ"
+
+func TestFilter_MatchesScope(t *testing.T) {
+	assert.True(t, scope.Filter{}.MatchesScope("HOST-1234"))
+	assert.True(t, scope.Filter{Scopes: []string{"environment", "HOST-*"}}.MatchesScope("HOST-1234"))
+	assert.True(t, scope.Filter{Scopes: []string{"environment", "HOST-*"}}.MatchesScope(scope.Environment))
+	assert.False(t, scope.Filter{Scopes: []string{"HOST-*"}}.MatchesScope("PROCESS_GROUP-1234"))
+}
+
+func TestFilter_MatchesManagementZone(t *testing.T) {
+	f := scope.Filter{ManagementZones: []scope.ManagementZone{{Name: "my zone", ObjectID: "mz-object-id", NumericID: "-4292415658385853785"}}}
+
+	tests := []struct {
+		name    string
+		id      string
+		payload string
+		want    bool
+	}{
+		{"management zone itself by object ID", "mz-object-id", `{}`, true},
+		{"management zone itself by numeric ID", "-4292415658385853785", `{}`, true},
+		{"reference by object ID", "id", `{"managementZone": "mz-object-id"}`, true},
+		{"reference by numeric ID string", "id", `{"filter": {"managementZone": {"id": "-4292415658385853785"}}}`, true},
+		{"reference by numeric ID number", "id", `{"mzId": -4292415658385853785}`, true},
+		{"reference by mzId selector", "id", `{"selector": "type(HOST),mzId(-4292415658385853785)"}`, true},
+		{"reference by mzName selector", "id", `{"rules": [{"selector": "mzName(\"my zone\")"}]}`, true},
+		{"no reference", "id", `{"name": "my zone", "mzId": 42}`, false},
+		{"invalid payload", "id", `{`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, f.MatchesManagementZone(tt.id, []byte(tt.payload)))
+		})
+	}
+
+	assert.True(t, scope.Filter{}.MatchesManagementZone("id", []byte(`{}`)), "empty filter must match everything")
+}
+
+func TestResolveManagementZones(t *testing.T) {
+	objects := []dtclient.DownloadSettingsObject{{ObjectId: mzObjectID, Value: json.RawMessage(`{"name": "my zone"}`)}}
+
+	t.Run("by name and numeric ID", func(t *testing.T) {
+		c := client.NewMockSettingsClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:management-zones", gomock.Any()).Return(objects, nil)
+
+		got, err := scope.ResolveManagementZones(context.TODO(), c, []string{"my zone", "-4292415658385853785"})
+		require.NoError(t, err)
+		want := scope.ManagementZone{Name: "my zone", ObjectID: mzObjectID, NumericID: "-4292415658385853785"}
+		assert.Equal(t, []scope.ManagementZone{want, want}, got)
+	})
+
+	t.Run("unknown management zone", func(t *testing.T) {
+		c := client.NewMockSettingsClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:management-zones", gomock.Any()).Return(objects, nil)
+
+		_, err := scope.ResolveManagementZones(context.TODO(), c, []string{"other zone"})
+		assert.ErrorContains(t, err, `management zone "other zone" does not exist`)
+	})
+
+	t.Run("listing fails", func(t *testing.T) {
+		c := client.NewMockSettingsClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:management-zones", gomock.Any()).Return(nil, errors.New("boom"))
+
+		_, err := scope.ResolveManagementZones(context.TODO(), c, []string{"my zone"})
+		assert.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("nothing to resolve", func(t *testing.T) {
+		got, err := scope.ResolveManagementZones(context.TODO(), client.NewMockSettingsClient(gomock.NewController(t)), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
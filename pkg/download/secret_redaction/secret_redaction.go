@@ -0,0 +1,198 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret_redaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// secretFields are the normalized names of JSON fields known to hold secrets, e.g. passwords of synthetic monitors or
+// tokens of notification integrations. Names are compared in lower case and without '_' and '-'.
+var secretFields = []string{
+	"password",
+	"passwd",
+	"passphrase",
+	"secret",
+	"clientsecret",
+	"sharedsecret",
+	"token",
+	"apitoken",
+	"accesstoken",
+	"authtoken",
+	"bearertoken",
+	"apikey",
+	"privatekey",
+}
+
+const envVarPrefix = "MONACO_SECRET_"
+
+var nonEnvVarChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// Secret is a secret value that was replaced by an environment variable parameter
+type Secret struct {
+	// Coordinate of the config the secret was found in
+	Coordinate coordinate.Coordinate
+	// Field is the name of the JSON field holding the secret
+	Field string
+	// EnvVar is the environment variable that needs to hold the secret when deploying
+	EnvVar string
+}
+
+// RedactSecretsIntoEnvParameters searches each given config's JSON template for fields known to hold secrets and
+// replaces their values with environment variable parameters. It modifies the given configsPerType map and returns
+// all redacted secrets, whose environment variables need to be supplied to deploy the configs.
+func RedactSecretsIntoEnvParameters(configsPerType project.ConfigsPerType) (project.ConfigsPerType, []Secret, error) {
+	var secrets []Secret
+	for _, cfgs := range configsPerType {
+		for _, c := range cfgs {
+			content, err := c.Template.Content()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to redact secrets of %s: %w", c.Coordinate, err)
+			}
+
+			var parsed any
+			if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+				log.WithFields(field.Coordinate(c.Coordinate), field.Error(err)).Debug("Unable to parse template of %s, skipping secret redaction: %v", c.Coordinate, err)
+				continue
+			}
+
+			found := findSecrets(parsed)
+			if len(found) == 0 {
+				continue
+			}
+
+			paramNames := make(map[string]string, len(found))
+			for _, f := range found {
+				if _, done := paramNames[f.value]; done {
+					continue // the same secret is replaced once
+				}
+
+				replaced := false
+				paramName := uniqueParameterName(c.Parameters, "secret_"+f.field)
+				for _, encoded := range encodings(f.value) {
+					if strings.Contains(content, encoded) {
+						content = strings.ReplaceAll(content, encoded, fmt.Sprintf(`"{{ .%s }}"`, paramName))
+						replaced = true
+					}
+				}
+				if !replaced {
+					log.WithFields(field.Coordinate(c.Coordinate)).Warn("Unable to redact secret field %q of %s, please check the template before sharing it", f.field, c.Coordinate)
+					continue
+				}
+
+				envVar := envVarName(c.Coordinate, f.field)
+				c.Parameters[paramName] = envParam.New(envVar)
+				paramNames[f.value] = paramName
+				secrets = append(secrets, Secret{Coordinate: c.Coordinate, Field: f.field, EnvVar: envVar})
+			}
+
+			if err := c.Template.UpdateContent(content); err != nil {
+				return nil, nil, fmt.Errorf("failed to redact secrets of %s: %w", c.Coordinate, err)
+			}
+		}
+	}
+	return configsPerType, secrets, nil
+}
+
+type secretValue struct {
+	field string
+	value string
+}
+
+// findSecrets returns all non-empty string values of secret fields in the given JSON value, in a stable order
+func findSecrets(v any) []secretValue {
+	var result []secretValue
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if s, isString := v[k].(string); isString && isSecretField(k) && !isRedacted(s) {
+				result = append(result, secretValue{field: k, value: s})
+				continue
+			}
+			result = append(result, findSecrets(v[k])...)
+		}
+	case []any:
+		for _, e := range v {
+			result = append(result, findSecrets(e)...)
+		}
+	}
+	return result
+}
+
+func isSecretField(name string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	return slices.Contains(secretFields, normalized)
+}
+
+// isRedacted reports whether a value does not hold a secret in plain text, as it is empty, masked by Dynatrace, or
+// already a template parameter
+func isRedacted(value string) bool {
+	return strings.Trim(value, "*") == "" || strings.Contains(value, "{{")
+}
+
+// encodings returns the possible JSON encodings of a string value as it may occur in a template
+func encodings(value string) []string {
+	escaped, _ := json.Marshal(value)
+
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	unescaped := strings.TrimSuffix(buf.String(), "\n")
+
+	if unescaped == string(escaped) {
+		return []string{unescaped}
+	}
+	return []string{string(escaped), unescaped}
+}
+
+func uniqueParameterName(params config.Parameters, name string) string {
+	name = strings.ReplaceAll(name, "-", "_") // golang template keys must not contain hyphens
+	if _, exists := params[name]; !exists {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if _, exists := params[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+func envVarName(c coordinate.Coordinate, fieldName string) string {
+	parts := []string{c.Type, c.ConfigId, fieldName}
+	for i, p := range parts {
+		parts[i] = strings.Trim(nonEnvVarChars.ReplaceAllString(strings.ToUpper(p), "_"), "_")
+	}
+	return envVarPrefix + strings.Join(parts, "_")
+}
@@ -0,0 +1,110 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret_redaction
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRedactSecretsIntoEnvParameters(t *testing.T) {
+	tests := []struct {
+		name            string
+		given           string
+		givenParams     config.Parameters
+		wantContent     string
+		wantParams      config.Parameters
+		wantSecretCount int
+	}{
+		{
+			name:        "secret fields are replaced",
+			given:       `{"name": "{{.name}}", "auth": {"user": "admin", "password": "s3cr3t"}, "apiToken": "dt0c01.abc"}`,
+			wantContent: `{"name": "{{.name}}", "auth": {"user": "admin", "password": "{{ .secret_password }}"}, "apiToken": "{{ .secret_apiToken }}"}`,
+			wantParams: config.Parameters{
+				"secret_password": envParam.New("MONACO_SECRET_SOME_API_SOME_ID_PASSWORD"),
+				"secret_apiToken": envParam.New("MONACO_SECRET_SOME_API_SOME_ID_APITOKEN"),
+			},
+			wantSecretCount: 2,
+		},
+		{
+			name:            "masked, empty and parameterized values are kept",
+			given:           `{"password": "******", "token": "", "secret": "{{.secret}}"}`,
+			wantContent:     `{"password": "******", "token": "", "secret": "{{.secret}}"}`,
+			wantParams:      config.Parameters{},
+			wantSecretCount: 0,
+		},
+		{
+			name:        "secrets in arrays and with special characters are replaced",
+			given:       `{"headers": [{"name": "X", "client_secret": "a\"b<c"}]}`,
+			wantContent: `{"headers": [{"name": "X", "client_secret": "{{ .secret_client_secret }}"}]}`,
+			wantParams: config.Parameters{
+				"secret_client_secret": envParam.New("MONACO_SECRET_SOME_API_SOME_ID_CLIENT_SECRET"),
+			},
+			wantSecretCount: 1,
+		},
+		{
+			name:        "existing parameter names are not overwritten",
+			given:       `{"password": "s3cr3t"}`,
+			givenParams: config.Parameters{"secret_password": value.New("other")},
+			wantContent: `{"password": "{{ .secret_password_2 }}"}`,
+			wantParams: config.Parameters{
+				"secret_password":   value.New("other"),
+				"secret_password_2": envParam.New("MONACO_SECRET_SOME_API_SOME_ID_PASSWORD"),
+			},
+			wantSecretCount: 1,
+		},
+		{
+			name:            "templates that are no valid JSON are skipped",
+			given:           `{"password": {{.password}}}`,
+			wantContent:     `{"password": {{.password}}}`,
+			wantParams:      config.Parameters{},
+			wantSecretCount: 0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params := tc.givenParams
+			if params == nil {
+				params = config.Parameters{}
+			}
+			c := config.Config{
+				Template:   template.NewInMemoryTemplate("id", tc.given),
+				Coordinate: coordinate.Coordinate{Project: "p", Type: "some-api", ConfigId: "some-id"},
+				Parameters: params,
+			}
+
+			got, secrets, err := RedactSecretsIntoEnvParameters(project.ConfigsPerType{"some-api": {c}})
+			require.NoError(t, err)
+			assert.Len(t, secrets, tc.wantSecretCount)
+
+			gotConfig := got["some-api"][0]
+			content, err := gotConfig.Template.Content()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantContent, content)
+			assert.Equal(t, tc.wantParams, gotConfig.Parameters)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segment
+
+import (
+	"context"
+	"fmt"
+	jsonutils "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	segmentClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/segment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/internal/templatetools"
+	v2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+// fieldsToRemove are set by the environment and must not be part of a template
+var fieldsToRemove = []string{"uid", "owner", "version", "allowedOperations"}
+
+// Download downloads all Grail filter segments of the environment.
+func Download(client client.SegmentClient, projectName string) (v2.ConfigsPerType, error) {
+	lg := log.WithFields(field.Type(config.SegmentTypeId))
+
+	segments, err := client.List(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch all segments: %w", err)
+	}
+
+	configs := make([]config.Config, 0, len(segments))
+	for _, s := range segments {
+		c, err := convertObject(s, projectName)
+		if err != nil {
+			lg.WithFields(field.Error(err)).Error("Failed to convert segment %q: %v", s.UID, err)
+			continue
+		}
+		configs = append(configs, c)
+	}
+
+	lg.WithFields(field.F("configsDownloaded", len(configs))).Info("Downloaded %d segments.", len(configs))
+	return v2.ConfigsPerType{string(config.SegmentTypeId): configs}, nil
+}
+
+func convertObject(s segmentClient.Response, projectName string) (config.Config, error) {
+	if s.UID == "" {
+		return config.Config{}, fmt.Errorf("uid is not set")
+	}
+
+	o, err := templatetools.NewJSONObject(s.Data)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("failed to unmarshal segment: %w", err)
+	}
+	for _, f := range fieldsToRemove {
+		o.Delete(f)
+	}
+
+	parameters := map[string]parameter.Parameter{}
+	if p := o.Parameterize(config.NameParameter); p != nil {
+		parameters[config.NameParameter] = p
+	}
+
+	t, err := o.ToJSON()
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	return config.Config{
+		Coordinate: coordinate.Coordinate{
+			Project:  projectName,
+			Type:     string(config.SegmentTypeId),
+			ConfigId: s.UID,
+		},
+		OriginObjectId: s.UID,
+		Type:           config.SegmentType{},
+		Template:       template.NewInMemoryTemplate(s.UID, string(jsonutils.MarshalIndent(t))),
+		Parameters:     parameters,
+	}, nil
+}
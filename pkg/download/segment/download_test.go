@@ -0,0 +1,69 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segment
+
+import (
+	"context"
+	segmentClient "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/segment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakeClient struct {
+	segments []segmentClient.Response
+	err      error
+}
+
+func (f fakeClient) Get(context.Context, string) (segmentClient.Response, error) {
+	return segmentClient.Response{}, nil
+}
+
+func (f fakeClient) List(context.Context) ([]segmentClient.Response, error) {
+	return f.segments, f.err
+}
+
+func TestDownload(t *testing.T) {
+	c := fakeClient{segments: []segmentClient.Response{
+		{UID: "uid-1", Name: "My segment", Data: []byte(`{"uid": "uid-1", "name": "My segment", "version": 3, "owner": "someone", "allowedOperations": ["READ"], "isPublic": true, "includes": []}`)},
+		{UID: "", Data: []byte(`{}`)},
+	}}
+
+	result, err := Download(c, "project")
+	require.NoError(t, err)
+	require.Len(t, result[string(config.SegmentTypeId)], 1, "segments without uid are skipped")
+
+	got := result[string(config.SegmentTypeId)][0]
+	assert.Equal(t, "uid-1", got.Coordinate.ConfigId)
+	assert.Equal(t, "uid-1", got.OriginObjectId)
+	assert.Equal(t, config.SegmentType{}, got.Type)
+	assert.Equal(t, &value.ValueParameter{Value: "My segment"}, got.Parameters[config.NameParameter])
+
+	content, err := got.Template.Content()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "{{.name}}", "isPublic": true, "includes": []}`, content)
+}
+
+func TestDownload_ReturnsListFailure(t *testing.T) {
+	result, err := Download(fakeClient{err: assert.AnError}, "project")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, result)
+}
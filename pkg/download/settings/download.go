@@ -26,6 +26,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
 	clientErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"golang.org/x/sync/errgroup"
 	"strings"
@@ -47,18 +48,20 @@ type schema struct {
 	ordered bool
 }
 
-func Download(client client.SettingsClient, projectName string, filters Filters, schemaIDs ...config.SettingsType) (v2.ConfigsPerType, error) {
+// Download downloads the settings objects of all schemas, or of the given schemas only. Objects not matching the
+// scopeFilter are not downloaded.
+func Download(client client.SettingsClient, projectName string, filters Filters, scopeFilter scope.Filter, schemaIDs ...config.SettingsType) (v2.ConfigsPerType, error) {
 	if len(schemaIDs) == 0 {
-		return downloadAll(client, projectName, filters)
+		return downloadAll(client, projectName, filters, scopeFilter)
 	}
 	var schemas []string
 	for _, s := range schemaIDs {
 		schemas = append(schemas, s.SchemaId)
 	}
-	return downloadSpecific(client, projectName, schemas, filters)
+	return downloadSpecific(client, projectName, schemas, filters, scopeFilter)
 }
 
-func downloadAll(client client.SettingsClient, projectName string, filters Filters) (v2.ConfigsPerType, error) {
+func downloadAll(client client.SettingsClient, projectName string, filters Filters, scopeFilter scope.Filter) (v2.ConfigsPerType, error) {
 	log.Debug("Fetching all schemas to download")
 	schemaList, err := client.ListSchemas()
 	if err != nil {
@@ -76,11 +79,11 @@ func downloadAll(client client.SettingsClient, projectName string, filters Filte
 		return v2.ConfigsPerType{}, err
 	}
 
-	result := download(client, schemas, projectName, filters)
+	result := download(client, schemas, projectName, filters, scopeFilter)
 	return result, nil
 }
 
-func downloadSpecific(client client.SettingsClient, projectName string, schemaIDs []string, filters Filters) (v2.ConfigsPerType, error) {
+func downloadSpecific(client client.SettingsClient, projectName string, schemaIDs []string, filters Filters, scopeFilter scope.Filter) (v2.ConfigsPerType, error) {
 	if ok, unknownSchemas := validateSpecificSchemas(client, schemaIDs); !ok {
 		err := fmt.Errorf("requested settings-schema(s) '%v' are not known", strings.Join(unknownSchemas, ","))
 		log.WithFields(field.F("unknownSchemas", unknownSchemas), field.Error(err)).Error("%v. Please consult the documentation for available schemas and verify they are available in your environment.", err)
@@ -93,7 +96,7 @@ func downloadSpecific(client client.SettingsClient, projectName string, schemaID
 	}
 
 	log.Debug("Settings to download: \n - %v", strings.Join(schemaIDs, "\n - "))
-	result := download(client, schemas, projectName, filters)
+	result := download(client, schemas, projectName, filters, scopeFilter)
 	return result, nil
 }
 
@@ -122,7 +125,7 @@ func fetchSchemas(cl client.SettingsClient, schemaIds []string) ([]schema, error
 	return schemas, nil
 }
 
-func download(client client.SettingsClient, schemas []schema, projectName string, filters Filters) v2.ConfigsPerType {
+func download(client client.SettingsClient, schemas []schema, projectName string, filters Filters, scopeFilter scope.Filter) v2.ConfigsPerType {
 	results := make(v2.ConfigsPerType, len(schemas))
 	downloadMutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
@@ -138,16 +141,20 @@ func download(client client.SettingsClient, schemas []schema, projectName string
 			if err != nil {
 				var errMsg string
 				var respErr clientErrors.RespError
-				if errors.As(err, &respErr) {
+				var missingScope clientErrors.MissingScopeError
+				switch {
+				case errors.As(err, &missingScope):
+					errMsg = fmt.Sprintf("%v\n    Hint: %s", err, missingScope.Hint())
+				case errors.As(err, &respErr):
 					errMsg = respErr.ConcurrentError()
-				} else {
+				default:
 					errMsg = err.Error()
 				}
 				lg.WithFields(field.Error(err)).Error("Failed to fetch all settings for schema '%s': %v", s.id, errMsg)
 				return
 			}
 
-			cfgs := convertAllObjects(objects, projectName, sc.ordered, filters)
+			cfgs := convertAllObjects(objects, projectName, sc.ordered, filters, scopeFilter)
 			downloadMutex.Lock()
 			results[s.id] = cfgs
 			downloadMutex.Unlock()
@@ -168,7 +175,7 @@ func download(client client.SettingsClient, schemas []schema, projectName string
 	return results
 }
 
-func convertAllObjects(objects []dtclient.DownloadSettingsObject, projectName string, ordered bool, filters Filters) []config.Config {
+func convertAllObjects(objects []dtclient.DownloadSettingsObject, projectName string, ordered bool, filters Filters, scopeFilter scope.Filter) []config.Config {
 	result := make([]config.Config, 0, len(objects))
 	var previousConfig *config.Config = nil
 	for _, o := range objects {
@@ -178,6 +185,11 @@ func convertAllObjects(objects []dtclient.DownloadSettingsObject, projectName st
 			continue
 		}
 
+		if !scopeFilter.Matches(o.ObjectId, o.Scope, o.Value) {
+			log.WithFields(field.Type(o.SchemaId), field.F("object", o)).Debug("Discarded settings object %q (%s). Reason: Scope %q or management zone does not match filter.", o.ObjectId, o.SchemaId, o.Scope)
+			continue
+		}
+
 		// try to unmarshall settings value
 		var contentUnmarshalled map[string]interface{}
 		if err := json.Unmarshal(o.Value, &contentUnmarshalled); err != nil {
@@ -207,12 +219,17 @@ func convertAllObjects(objects []dtclient.DownloadSettingsObject, projectName st
 			Parameters: map[string]parameter.Parameter{
 				config.ScopeParameter: &value.ValueParameter{Value: o.Scope},
 			},
-			Skip:           false,
-			OriginObjectId: o.ObjectId,
+			Skip:             false,
+			OriginObjectId:   o.ObjectId,
+			OriginExternalId: o.ExternalId,
 		}
 
-		if ordered && (previousConfig != nil) {
-			c.Parameters[config.InsertAfterParameter] = reference.NewWithCoordinate(previousConfig.Coordinate, "id")
+		if ordered {
+			if previousConfig != nil {
+				c.Parameters[config.InsertAfterParameter] = reference.NewWithCoordinate(previousConfig.Coordinate, "id")
+			} else {
+				c.Parameters[config.InsertAfterParameter] = &value.ValueParameter{Value: config.InsertAfterFront}
+			}
 		}
 		result = append(result, c)
 		previousConfig = &c
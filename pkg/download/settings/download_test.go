@@ -31,6 +31,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/scope"
 	v2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"github.com/stretchr/testify/assert"
@@ -155,8 +156,9 @@ func TestDownloadAll(t *testing.T) {
 					Parameters: map[string]parameter.Parameter{
 						config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
 					},
-					Skip:           false,
-					OriginObjectId: "oid1",
+					Skip:             false,
+					OriginObjectId:   "oid1",
+					OriginExternalId: "ex1",
 				},
 			}},
 		},
@@ -240,8 +242,9 @@ func TestDownloadAll(t *testing.T) {
 					Parameters: map[string]parameter.Parameter{
 						config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
 					},
-					Skip:           false,
-					OriginObjectId: "oid1",
+					Skip:             false,
+					OriginObjectId:   "oid1",
+					OriginExternalId: "ex1",
 				},
 			}},
 		},
@@ -289,8 +292,9 @@ func TestDownloadAll(t *testing.T) {
 					Parameters: map[string]parameter.Parameter{
 						config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
 					},
-					Skip:           false,
-					OriginObjectId: "oid1",
+					Skip:             false,
+					OriginObjectId:   "oid1",
+					OriginExternalId: "ex1",
 				},
 			}},
 		},
@@ -351,10 +355,12 @@ func TestDownloadAll(t *testing.T) {
 						SchemaVersion: "sv1",
 					},
 					Parameters: map[string]parameter.Parameter{
-						config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
+						config.ScopeParameter:       &value.ValueParameter{Value: "tenant"},
+						config.InsertAfterParameter: &value.ValueParameter{Value: config.InsertAfterFront},
 					},
-					Skip:           false,
-					OriginObjectId: "oid1",
+					Skip:             false,
+					OriginObjectId:   "oid1",
+					OriginExternalId: "ex1",
 				},
 				{
 					Template: template.NewInMemoryTemplate(uuid3, "{}"),
@@ -380,8 +386,9 @@ func TestDownloadAll(t *testing.T) {
 							},
 						},
 					},
-					Skip:           false,
-					OriginObjectId: "oid3",
+					Skip:             false,
+					OriginObjectId:   "oid3",
+					OriginExternalId: "ex3",
 				},
 			}},
 		},
@@ -394,7 +401,7 @@ func TestDownloadAll(t *testing.T) {
 			c.EXPECT().GetSchemaById(gomock.Any()).Times(tt.mockValues.GetSchemaCalls).Return(tt.mockValues.GetSchema(""))
 			settings, err := tt.mockValues.Settings()
 			c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(tt.mockValues.ListSettingsCalls).Return(settings, err)
-			res, _ := Download(c, "projectName", tt.filters)
+			res, _ := Download(c, "projectName", tt.filters, scope.Filter{})
 			assert.Equal(t, tt.want, res)
 		})
 	}
@@ -474,8 +481,9 @@ func TestDownload(t *testing.T) {
 					Parameters: map[string]parameter.Parameter{
 						config.ScopeParameter: &value.ValueParameter{Value: "tenant"},
 					},
-					Skip:           false,
-					OriginObjectId: "oid1",
+					Skip:             false,
+					OriginObjectId:   "oid1",
+					OriginExternalId: "ex1",
 				},
 			}},
 		},
@@ -488,7 +496,7 @@ func TestDownload(t *testing.T) {
 			c.EXPECT().ListSchemas().Times(tt.mockValues.ListSchemasCalls).Return(schemas, err1)
 			c.EXPECT().GetSchemaById(gomock.Any()).Times(tt.mockValues.GetSchemaCalls).Return(tt.mockValues.FetchedSchemas(""))
 			c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(tt.mockValues.ListSettingsCalls).Return(settings, err2)
-			res, _ := Download(c, "projectName", DefaultSettingsFilters, tt.Schemas...)
+			res, _ := Download(c, "projectName", DefaultSettingsFilters, scope.Filter{}, tt.Schemas...)
 			assert.Equal(t, tt.want, res)
 		})
 	}
@@ -695,3 +703,26 @@ func Test_shouldFilterSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertAllObjects_ScopeFilter(t *testing.T) {
+	objects := []dtclient.DownloadSettingsObject{
+		{ObjectId: "oid1", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: json.RawMessage(`{"managementZone": "mz-object-id"}`)},
+		{ObjectId: "oid2", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: json.RawMessage(`{}`)},
+		{ObjectId: "oid3", SchemaId: "builtin:alerting.profile", Scope: "HOST-1234", Value: json.RawMessage(`{"managementZone": "mz-object-id"}`)},
+	}
+
+	t.Run("by scope", func(t *testing.T) {
+		got := convertAllObjects(objects, "project", false, Filters{}, scope.Filter{Scopes: []string{"HOST-*"}})
+		assert.Len(t, got, 1)
+		assert.Equal(t, "oid3", got[0].OriginObjectId)
+	})
+
+	t.Run("by scope and management zone", func(t *testing.T) {
+		got := convertAllObjects(objects, "project", false, Filters{}, scope.Filter{
+			Scopes:          []string{"environment"},
+			ManagementZones: []scope.ManagementZone{{Name: "mz", ObjectID: "mz-object-id", NumericID: "42"}},
+		})
+		assert.Len(t, got, 1)
+		assert.Equal(t, "oid1", got[0].OriginObjectId)
+	})
+}
@@ -144,6 +144,58 @@ func (graphs ConfigGraphPerEnvironment) GetIndependentlySortedConfigs(environmen
 	return sortedComponents, nil
 }
 
+// RetainWithDependencies reduces the dependency graph of the given environment to the configurations matched by keep,
+// plus all configurations they transitively depend on. All other configurations are removed from the graph.
+// It returns the number of configurations matched by keep.
+func (graphs ConfigGraphPerEnvironment) RetainWithDependencies(environment string, keep func(coordinate.Coordinate) bool) (int, error) {
+	g, ok := graphs[environment]
+	if !ok {
+		return 0, fmt.Errorf("no dependency graph exists for envrionment %s", environment)
+	}
+
+	retained := make(map[int64]struct{})
+	var queue []graph.Node
+	matched := 0
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n := nodes.Node()
+		if keep(n.(ConfigNode).Config.Coordinate) {
+			matched++
+			retained[n.ID()] = struct{}{}
+			queue = append(queue, n)
+		}
+	}
+
+	// edges point from a dependency to the config depending on it, so all dependencies are found by walking the edges backwards
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		dependencies := g.To(n.ID())
+		for dependencies.Next() {
+			d := dependencies.Node()
+			if _, found := retained[d.ID()]; !found {
+				retained[d.ID()] = struct{}{}
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	var toRemove []int64
+	nodes = g.Nodes()
+	for nodes.Next() {
+		if _, found := retained[nodes.Node().ID()]; !found {
+			toRemove = append(toRemove, nodes.Node().ID())
+		}
+	}
+	for _, id := range toRemove {
+		g.RemoveNode(id)
+	}
+
+	return matched, nil
+}
+
 func findConnectedComponents(d *simple.DirectedGraph) []*simple.DirectedGraph {
 	u := buildUndirectedGraph(d)
 
@@ -533,3 +533,48 @@ func TestRoots(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigGraphPerEnvironment_RetainWithDependencies(t *testing.T) {
+	environmentName := "dev"
+
+	zone := coordinate.Coordinate{Project: "project", Type: "management-zone", ConfigId: "zone"}
+	tag := coordinate.Coordinate{Project: "project", Type: "auto-tag", ConfigId: "tag"}
+	dashboard := coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"}
+	unrelated := coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "unrelated"}
+
+	referencing := func(c coordinate.Coordinate, refs ...coordinate.Coordinate) config.Config {
+		p := &parameter.DummyParameter{Value: "value"}
+		for _, r := range refs {
+			p.References = append(p.References, parameter.ParameterReference{Config: r, Property: "id"})
+		}
+		return config.Config{Coordinate: c, Environment: environmentName, Parameters: map[string]parameter.Parameter{"ref": p}}
+	}
+
+	projects := []project.Project{
+		{
+			Id: "project",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				environmentName: {
+					"management-zone": []config.Config{referencing(zone)},
+					"auto-tag":        []config.Config{referencing(tag, zone)},
+					"dashboard":       []config.Config{referencing(dashboard, tag), referencing(unrelated)},
+				},
+			},
+		},
+	}
+
+	graphs := graph.New(projects, []string{environmentName})
+	matched, err := graphs.RetainWithDependencies(environmentName, func(c coordinate.Coordinate) bool { return c == dashboard })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, matched)
+
+	sorted, err := graphs.SortConfigs(environmentName)
+	assert.NoError(t, err)
+	assert.Len(t, sorted, 3)
+	assert.Equal(t, zone, sorted[0].Coordinate)
+	assert.Equal(t, tag, sorted[1].Coordinate)
+	assert.Equal(t, dashboard, sorted[2].Coordinate)
+
+	_, err = graphs.RetainWithDependencies("unknown", func(coordinate.Coordinate) bool { return true })
+	assert.Error(t, err)
+}
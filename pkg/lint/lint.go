@@ -0,0 +1,241 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lint statically analyses the templates and parameters of loaded projects, without contacting any environment.
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	listParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Rule identifies the check that produced a Finding.
+type Rule string
+
+const (
+	// RuleParseError reports templates that are no valid Go templates.
+	RuleParseError Rule = "parse-error"
+	// RuleUndefinedVariable reports template variables that are not declared as parameters of the config.
+	RuleUndefinedVariable Rule = "undefined-variable"
+	// RuleUnusedParameter reports parameters that are neither used by the template nor by other parameters of the config.
+	RuleUnusedParameter Rule = "unused-parameter"
+	// RuleInvalidJSON reports templates that are no valid JSON once rendered with placeholder values.
+	RuleInvalidJSON Rule = "invalid-json"
+)
+
+// placeholderValue is used for all parameters whose value can not be known without access to an environment.
+const placeholderValue = "0"
+
+// placeholderList is used for list parameters that can not be resolved statically. It contains a single element, so
+// that the body of loops over the list is rendered as well.
+const placeholderList = `[ "0" ]`
+
+// Finding is a single problem found in a template or config definition.
+type Finding struct {
+	// File is the template file the finding relates to.
+	File string
+	// Line is the line within File, starting at 1. It is 0 if the finding is not related to a specific line.
+	Line int
+	// Coordinate of the config the finding was reported for.
+	Coordinate coordinate.Coordinate
+	Rule       Rule
+	Message    string
+}
+
+func (f Finding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("%s: [%s] %s: %s", location, f.Rule, f.Coordinate, f.Message)
+}
+
+// Lint analyses all configs of the given projects and returns the findings ordered by file and line.
+// Configs that are defined for several environments are reported only once per finding.
+func Lint(projects []project.Project) []Finding {
+	var findings []Finding
+	seen := make(map[Finding]struct{})
+
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			for _, f := range lintConfig(c) {
+				if _, found := seen[f]; found {
+					continue
+				}
+				seen[f] = struct{}{}
+				findings = append(findings, f)
+			}
+		})
+	}
+
+	slices.SortFunc(findings, func(a, b Finding) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		if c := strings.Compare(a.Coordinate.String(), b.Coordinate.String()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Message, b.Message)
+	})
+	return findings
+}
+
+func lintConfig(c config.Config) []Finding {
+	if c.Template == nil {
+		return nil
+	}
+
+	file := c.Template.ID()
+	newFinding := func(line int, rule Rule, format string, args ...any) Finding {
+		return Finding{File: file, Line: line, Coordinate: c.Coordinate, Rule: rule, Message: fmt.Sprintf(format, args...)}
+	}
+
+	parsed, err := template.Parse(c.Template)
+	if err != nil {
+		return []Finding{newFinding(parseErrorLine(err), RuleParseError, "%v", err)}
+	}
+
+	// references within partials are reported for the partial's file
+	files := map[string]string{file: file}
+	for _, p := range template.Partials(c.Template) {
+		files[p.Name] = p.Path
+	}
+
+	var findings []Finding
+
+	refs := template.References(parsed)
+	used := make(map[string]struct{}, len(refs))
+	for _, r := range refs {
+		used[r.Name] = struct{}{}
+		if _, found := c.Parameters[r.Name]; !found && c.MissingKeys != config.MissingKeysLenient {
+			f := newFinding(r.Line, RuleUndefinedVariable, "template references undefined parameter %q", r.Name)
+			if path, found := files[r.Template]; found {
+				f.File = path
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	for _, p := range c.Parameters {
+		for _, r := range p.GetReferences() {
+			if r.Config == c.Coordinate {
+				used[r.Property] = struct{}{}
+			}
+		}
+	}
+
+	for name := range c.Parameters {
+		if _, found := used[name]; found || isSpecialParameter(name) {
+			continue
+		}
+		findings = append(findings, newFinding(0, RuleUnusedParameter, "parameter %q is not used by the template", name))
+	}
+
+	if len(findings) > 0 {
+		// undefined variables make rendering fail, so there is no point in checking the rendered JSON
+		return findings
+	}
+
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, placeholderProperties(c.Parameters)); err != nil {
+		// rendering depends on values that are only known at deployment time
+		return findings
+	}
+
+	var js any
+	if err := json.Unmarshal(rendered.Bytes(), &js); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return append(findings, newFinding(0, RuleInvalidJSON, "rendering the template with placeholder values results in invalid JSON: %v (line %d of the rendered template)", err, lineOf(rendered.String(), int(syntaxErr.Offset))))
+		}
+		return append(findings, newFinding(0, RuleInvalidJSON, "rendering the template with placeholder values results in invalid JSON: %v", err))
+	}
+
+	return findings
+}
+
+// isSpecialParameter returns whether the parameter is used by monaco itself, and does not need to be used in the template.
+func isSpecialParameter(name string) bool {
+	return slices.Contains(config.ReservedParameterNames, name) || name == config.InsertAfterParameter || name == config.NonUniqueNameConfigDuplicationParameter
+}
+
+// placeholderProperties returns the properties used to render a template without resolving parameters. Plain values
+// are used as they are, all other parameters are replaced by placeholders.
+func placeholderProperties(params config.Parameters) map[string]any {
+	properties := make(map[string]any, len(params))
+	for name, p := range params {
+		properties[name] = placeholderValue
+
+		switch p := p.(type) {
+		case *valueParam.ValueParameter:
+			if v, err := p.ResolveValue(parameter.ResolveContext{}); err == nil {
+				properties[name] = v
+			}
+		case *listParam.ListParameter:
+			properties[name] = placeholderList
+			if isStatic(p) {
+				if v, err := p.ResolveValue(parameter.ResolveContext{}); err == nil {
+					properties[name] = v
+				}
+			}
+		}
+	}
+	return properties
+}
+
+// isStatic returns whether all values of the list are plain values that can be resolved without further context.
+func isStatic(p *listParam.ListParameter) bool {
+	for _, v := range p.Values {
+		if _, ok := v.(*valueParam.ValueParameter); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// lineOf returns the line number of the given byte offset within s, starting at 1.
+func lineOf(s string, offset int) int {
+	if offset > len(s) {
+		offset = len(s)
+	}
+	return strings.Count(s[:offset], "\n") + 1
+}
+
+// parseErrorLine extracts the line number from an error returned when parsing a template.
+// Such errors have the form 'template: <name>:<line>: <message>'. Returns 0 if no line number is found.
+func parseErrorLine(err error) int {
+	for _, part := range strings.Split(err.Error(), ":") {
+		if line, convErr := strconv.Atoi(part); convErr == nil {
+			return line
+		}
+	}
+	return 0
+}
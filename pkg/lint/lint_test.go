@@ -0,0 +1,141 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	listParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var coord = coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "c"}
+
+func lintSingle(content string, params config.Parameters) []Finding {
+	c := config.Config{
+		Template:    template.NewInMemoryTemplate("p/alerting-profile/c.json", content),
+		Coordinate:  coord,
+		Environment: "env",
+		Parameters:  params,
+	}
+	p := project.Project{
+		Id: "p",
+		Configs: project.ConfigsPerTypePerEnvironments{
+			"env":   {"alerting-profile": {c}},
+			"other": {"alerting-profile": {c}},
+		},
+	}
+	return Lint([]project.Project{p})
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		params  config.Parameters
+		want    []Finding
+	}{
+		{
+			name:    "valid template",
+			content: `{"name": "{{ .name }}", "tags": {{ .tags }}, "threshold": {{ .threshold }}}`,
+			params: config.Parameters{
+				"name":      valueParam.New("n"),
+				"tags":      listParam.New([]parameter.Parameter{valueParam.New("a")}),
+				"threshold": refParam.New("p", "alerting-profile", "other", "id"),
+			},
+		},
+		{
+			name:    "template variables within range and with blocks",
+			content: "[{{ range $i, $e := items .list }}{{ if $i }},{{ end }}{{ with .Value }}\"{{ . }}{{ $.suffix }}\"{{ end }}{{ end }}]",
+			params: config.Parameters{
+				"name":   valueParam.New("n"),
+				"list":   listParam.New([]parameter.Parameter{valueParam.New("a"), valueParam.New("b")}),
+				"suffix": valueParam.New("-x"),
+			},
+		},
+		{
+			name:    "parse error",
+			content: "{\n\"name\": \"{{ .name }\"\n}",
+			params:  config.Parameters{"name": valueParam.New("n")},
+			want:    []Finding{{Line: 2, Rule: RuleParseError, Message: `template: p/alerting-profile/c.json:2: unexpected "}" in operand`}},
+		},
+		{
+			name:    "undefined variable",
+			content: "{\n\"name\": \"{{ .name }}\",\n\"enabled\": {{ .enabled }}\n}",
+			params:  config.Parameters{"name": valueParam.New("n")},
+			want:    []Finding{{Line: 3, Rule: RuleUndefinedVariable, Message: `template references undefined parameter "enabled"`}},
+		},
+		{
+			name:    "unused parameter",
+			content: `{"name": "{{ .name }}"}`,
+			params: config.Parameters{
+				"name":        valueParam.New("n"),
+				"skip":        valueParam.New(false),
+				"unused":      valueParam.New("u"),
+				"referenced":  valueParam.New("r"),
+				"referencing": refParam.New("p", "alerting-profile", "c", "referenced"),
+			},
+			want: []Finding{{Rule: RuleUnusedParameter, Message: `parameter "referencing" is not used by the template`}, {Rule: RuleUnusedParameter, Message: `parameter "unused" is not used by the template`}},
+		},
+		{
+			name:    "invalid JSON",
+			content: "{\n\"name\": \"{{ .name }}\",\n}",
+			params:  config.Parameters{"name": valueParam.New("n")},
+			want:    []Finding{{Rule: RuleInvalidJSON, Message: "rendering the template with placeholder values results in invalid JSON: invalid character '}' looking for beginning of object key string (line 3 of the rendered template)"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.want {
+				tt.want[i].File = "p/alerting-profile/c.json"
+				tt.want[i].Coordinate = coord
+			}
+
+			got := lintSingle(tt.content, tt.params)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLint_Partials(t *testing.T) {
+	partials := []template.Partial{{Name: "partials/tile.json", Path: "p/partials/tile.json", Content: "{\n\"name\": \"{{ .tileName }}\"\n}"}}
+	c := config.Config{
+		Template:    template.NewInMemoryTemplateWithPartials("p/dashboard/c.json", `{"tiles": [{{ template "partials/tile.json" . }}]}`, partials),
+		Coordinate:  coord,
+		Environment: "env",
+		Parameters:  config.Parameters{"name": valueParam.New("n")},
+	}
+	got := Lint([]project.Project{{Id: "p", Configs: project.ConfigsPerTypePerEnvironments{"env": {"dashboard": {c}}}}})
+	assert.Equal(t, []Finding{{File: "p/partials/tile.json", Line: 2, Coordinate: coord, Rule: RuleUndefinedVariable, Message: `template references undefined parameter "tileName"`}}, got)
+}
+
+func TestFinding_String(t *testing.T) {
+	f := Finding{File: "p/t/c.json", Line: 3, Coordinate: coord, Rule: RuleUndefinedVariable, Message: "some message"}
+	assert.Equal(t, "p/t/c.json:3: [undefined-variable] p:alerting-profile:c: some message", f.String())
+
+	f.Line = 0
+	assert.Equal(t, "p/t/c.json: [undefined-variable] p:alerting-profile:c: some message", f.String())
+}
@@ -0,0 +1,205 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gitsource supports loading manifests and projects from remote Git repositories.
+//
+// A Git source is given as 'git::<repository>[//<subdir>][?ref=<ref>]', e.g.
+// 'git::https://github.com/example/monaco-projects.git//projects/infra?ref=v1.2.0'.
+// Repositories are fetched into a local cache folder once per repository and ref, and loaded from there.
+package gitsource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Prefix marks a path as Git source
+const Prefix = "git::"
+
+// Source is a location within a remote Git repository
+type Source struct {
+	// Repository is the URL of the Git repository, e.g. 'https://github.com/example/monaco-projects.git'
+	Repository string
+	// Subdir is the slash-separated path within the repository. It is empty for the root of the repository.
+	Subdir string
+	// Ref is the branch, tag, or commit to fetch. If it is empty, the default branch of the repository is fetched.
+	Ref string
+}
+
+func (s Source) String() string {
+	str := Prefix + s.Repository
+	if s.Subdir != "" {
+		str += "//" + s.Subdir
+	}
+	if s.Ref != "" {
+		str += "?ref=" + s.Ref
+	}
+	return str
+}
+
+// cacheKey identifies the fetched version of the repository, independent of the subdir
+func (s Source) cacheKey() string {
+	h := sha256.Sum256([]byte(s.Repository + "\n" + s.Ref))
+	return hex.EncodeToString(h[:])[:16]
+}
+
+// IsSource returns whether p is a Git source, i.e. starts with [Prefix]
+func IsSource(p string) bool {
+	return strings.HasPrefix(p, Prefix)
+}
+
+// Parse parses a Git source of the form 'git::<repository>[//<subdir>][?ref=<ref>]'
+func Parse(p string) (Source, error) {
+	if !IsSource(p) {
+		return Source{}, fmt.Errorf("%q is not a Git source, it must start with %q", p, Prefix)
+	}
+	repo := strings.TrimPrefix(p, Prefix)
+
+	var ref string
+	if i := strings.Index(repo, "?"); i >= 0 {
+		query, err := url.ParseQuery(repo[i+1:])
+		if err != nil {
+			return Source{}, fmt.Errorf("invalid query of Git source %q: %w", p, err)
+		}
+		for k := range query {
+			if k != "ref" {
+				return Source{}, fmt.Errorf("invalid query parameter %q of Git source %q, only 'ref' is supported", k, p)
+			}
+		}
+		ref = query.Get("ref")
+		repo = repo[:i]
+	}
+
+	// the subdir is separated by '//', which must not be confused with the '//' following the scheme of the URL
+	schemeEnd := 0
+	if i := strings.Index(repo, "://"); i >= 0 {
+		schemeEnd = i + len("://")
+	}
+
+	var subdir string
+	if i := strings.Index(repo[schemeEnd:], "//"); i >= 0 {
+		subdir = repo[schemeEnd+i+len("//"):]
+		repo = repo[:schemeEnd+i]
+	}
+
+	if repo == "" {
+		return Source{}, fmt.Errorf("Git source %q does not define a repository", p)
+	}
+
+	// cleaning the subdir as absolute path ensures that it does not leave the repository
+	subdir = strings.Trim(path.Clean("/"+subdir), "/")
+
+	return Source{Repository: repo, Subdir: subdir, Ref: ref}, nil
+}
+
+// Fetcher fetches the content of a Git repository
+type Fetcher interface {
+	// Fetch fetches the content of the repository of src in the version of its ref into dir. dir does not exist
+	// before and must only be created if fetching succeeds.
+	Fetch(src Source, dir string) error
+}
+
+// Resolve returns the local path of src within cacheDir on fs. If the repository has not been fetched in the version
+// of the ref before, it is fetched using fetcher.
+func Resolve(fs afero.Fs, fetcher Fetcher, cacheDir string, src Source) (string, error) {
+	dir := filepath.Join(cacheDir, src.cacheKey())
+
+	exists, err := afero.DirExists(fs, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to access Git cache of %q: %w", src, err)
+	}
+	if !exists {
+		if err := fs.MkdirAll(cacheDir, 0777); err != nil {
+			return "", fmt.Errorf("failed to create Git cache folder %q: %w", cacheDir, err)
+		}
+		if err := fetcher.Fetch(src, dir); err != nil {
+			return "", fmt.Errorf("failed to fetch %q: %w", src, err)
+		}
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(src.Subdir)), nil
+}
+
+// DefaultCacheDir returns the folder Git sources referenced outside of a manifest (e.g. a manifest given on the
+// command line) are cached in
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache folder: %w", err)
+	}
+	return filepath.Join(dir, "monaco", "git"), nil
+}
+
+// CLIFetcher is a [Fetcher] using the git command line tool, which needs to be installed. It only supports
+// fetching onto the OS file system.
+type CLIFetcher struct{}
+
+var _ Fetcher = CLIFetcher{}
+
+// Fetch fetches only the commit of src's ref without history. The repository is fetched into a temporary folder
+// first, which is renamed to dir once fetching succeeded.
+func (CLIFetcher) Fetch(src Source, dir string) error {
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", src.Repository, ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		if err := runGit(tmp, args...); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(tmp, ".git")); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+func runGit(dir string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return nil
+}
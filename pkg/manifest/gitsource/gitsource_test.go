@@ -0,0 +1,177 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gitsource
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		given   string
+		want    Source
+		wantErr string
+	}{
+		{
+			name:  "repository only",
+			given: "git::https://github.com/example/repo.git",
+			want:  Source{Repository: "https://github.com/example/repo.git"},
+		},
+		{
+			name:  "repository with subdir and ref",
+			given: "git::https://github.com/example/repo.git//projects/infra?ref=v1.2.0",
+			want:  Source{Repository: "https://github.com/example/repo.git", Subdir: "projects/infra", Ref: "v1.2.0"},
+		},
+		{
+			name:  "repository with ref",
+			given: "git::https://github.com/example/repo.git?ref=main",
+			want:  Source{Repository: "https://github.com/example/repo.git", Ref: "main"},
+		},
+		{
+			name:  "scp-like repository with subdir",
+			given: "git::git@github.com:example/repo.git//manifest.yaml",
+			want:  Source{Repository: "git@github.com:example/repo.git", Subdir: "manifest.yaml"},
+		},
+		{
+			name:  "subdir is cleaned",
+			given: "git::https://github.com/example/repo.git//projects/./infra/",
+			want:  Source{Repository: "https://github.com/example/repo.git", Subdir: "projects/infra"},
+		},
+		{
+			name:    "missing prefix",
+			given:   "https://github.com/example/repo.git",
+			wantErr: "is not a Git source",
+		},
+		{
+			name:    "missing repository",
+			given:   "git::?ref=main",
+			wantErr: "does not define a repository",
+		},
+		{
+			name:    "unknown query parameter",
+			given:   "git::https://github.com/example/repo.git?depth=1",
+			wantErr: `invalid query parameter "depth"`,
+		},
+		{
+			name:  "subdir does not leave the repository",
+			given: "git::https://github.com/example/repo.git//../other",
+			want:  Source{Repository: "https://github.com/example/repo.git", Subdir: "other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.given)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type fetcherFunc func(src Source, dir string) error
+
+func (f fetcherFunc) Fetch(src Source, dir string) error {
+	return f(src, dir)
+}
+
+func TestResolve_FetchesOncePerRepositoryAndRef(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	fetches := 0
+	fetcher := fetcherFunc(func(src Source, dir string) error {
+		fetches++
+		return afero.WriteFile(fs, filepath.Join(dir, "projects", "infra", "config.yaml"), []byte("configs: []"), 0644)
+	})
+
+	src := Source{Repository: "https://github.com/example/repo.git", Subdir: "projects/infra", Ref: "v1.2.0"}
+
+	p, err := Resolve(fs, fetcher, "cache", src)
+	require.NoError(t, err)
+	exists, err := afero.Exists(fs, filepath.Join(p, "config.yaml"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	other, err := Resolve(fs, fetcher, "cache", Source{Repository: src.Repository, Ref: src.Ref})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Dir(filepath.Dir(p)), other)
+	assert.Equal(t, 1, fetches, "repository should be fetched only once for the same ref")
+
+	_, err = Resolve(fs, fetcher, "cache", Source{Repository: src.Repository, Ref: "v2.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetches, "repository should be fetched again for another ref")
+}
+
+func TestResolve_ReturnsFetchErrors(t *testing.T) {
+	fetcher := fetcherFunc(func(src Source, dir string) error {
+		return assert.AnError
+	})
+
+	_, err := Resolve(afero.NewMemMapFs(), fetcher, "cache", Source{Repository: "https://github.com/example/repo.git"})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, `failed to fetch "git::https://github.com/example/repo.git"`)
+}
+
+func TestCLIFetcher(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	fs := afero.NewOsFs()
+	git("init", "-q")
+	require.NoError(t, fs.MkdirAll(filepath.Join(repo, "project"), 0777))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(repo, "project", "config.yaml"), []byte("v1"), 0644))
+	git("add", "-A")
+	git("commit", "-q", "-m", "v1")
+	git("tag", "v1")
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(repo, "project", "config.yaml"), []byte("v2"), 0644))
+	git("commit", "-q", "-am", "v2")
+
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	for ref, want := range map[string]string{"v1": "v1", "": "v2"} {
+		p, err := Resolve(fs, CLIFetcher{}, cacheDir, Source{Repository: repo, Subdir: "project", Ref: ref})
+		require.NoError(t, err)
+
+		content, err := afero.ReadFile(fs, filepath.Join(p, "config.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content))
+	}
+
+	_, err := Resolve(fs, CLIFetcher{}, cacheDir, Source{Repository: repo, Ref: "does-not-exist"})
+	assert.ErrorContains(t, err, "git fetch failed")
+
+	entries, err := afero.ReadDir(fs, cacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "failed fetches must not leave folders in the cache")
+}
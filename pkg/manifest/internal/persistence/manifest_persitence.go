@@ -28,6 +28,29 @@ type Project struct {
 	Name string `yaml:"name" json:"name" jsonschema:"required,description=The name of the project - if 'path' is not set the name will be used as path, otherwise this can be freely defined."`
 	Type string `yaml:"type,omitempty" json:"type" jsonschema:"enum=simple,enum=grouping,description=The type of project - either a 'simple' project folder containing configs, or a 'grouping' of projects in sub-folders."`
 	Path string `yaml:"path,omitempty" json:"path" jsonschema:"description=The file path to the project folder, relative to the manifest's location."`
+
+	Hooks *Hooks `yaml:"hooks,omitempty" json:"hooks" jsonschema:"description=Commands or HTTP calls to execute before and after deploying this project."`
+
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn" jsonschema:"description=The names of the projects or grouping projects whose configurations this project may reference. If defined, references to configurations of any other project are rejected when loading the project. If not defined, configurations of any project may be referenced."`
+}
+
+const (
+	HookFailureAbort = "abort"
+	HookFailureWarn  = "warn"
+)
+
+// Hooks defines the hooks executed before and after deploying a project
+type Hooks struct {
+	Pre  []Hook `yaml:"pre,omitempty" json:"pre" jsonschema:"description=Hooks executed before the project is deployed."`
+	Post []Hook `yaml:"post,omitempty" json:"post" jsonschema:"description=Hooks executed after the project was deployed."`
+}
+
+// Hook defines a single shell command or HTTP call. Exactly one of Command and URL must be set.
+type Hook struct {
+	Command    string `yaml:"command,omitempty" json:"command" jsonschema:"description=A shell command to execute."`
+	URL        string `yaml:"url,omitempty" json:"url" jsonschema:"description=A URL to send a POST request describing the deployment to."`
+	ConfigType string `yaml:"configType,omitempty" json:"configType" jsonschema:"description=If set, the hook is executed before or after deploying configurations of this type to each environment, instead of once around the whole deployment."`
+	OnFailure  string `yaml:"onFailure,omitempty" json:"onFailure" jsonschema:"enum=abort,enum=warn,default=abort,description=Whether a failing hook aborts the deployment, or only logs a warning."`
 }
 
 type Type string
@@ -35,6 +58,7 @@ type Type string
 const (
 	TypeEnvironment Type = "environment"
 	TypeValue       Type = "value"
+	TypeFile        Type = "file"
 )
 
 // TypedValue represents a value with a Type - currently these are variables that can be either:
@@ -65,17 +89,20 @@ func (c *TypedValue) UnmarshalYAML(unmarshal func(any) error) error {
 	return nil
 }
 
-// AuthSecret represents a user-defined client id or client secret. It has a [Type] which is [TypeEnvironment] (default).
-// Secrets must never be provided as plain text, but always loaded from somewhere else. Currently, loading is only allowed from environment variables.
+// AuthSecret represents a user-defined client id or client secret. It has a [Type] which is [TypeEnvironment] (default) or [TypeFile].
+// Secrets must never be provided as plain text, but always loaded from somewhere else - either from environment variables,
+// or from files provided by a secret store (e.g. a Vault agent or a Kubernetes secrets store CSI driver).
 //
-// [Name] contains the environment-variable to resolve the authSecret.
+// [Name] contains the environment-variable to resolve the authSecret, [Path] the file to read it from for [TypeFile].
 //
 // This struct is meant to be reused for fields that require the same behavior.
 type AuthSecret struct {
-	// Type exists for future compatibility - AuthSecrets are always read from 'environment' variables
-	Type Type `yaml:"type" json:"type,omitempty"`
-	//Name of the environment variable to read the secret from.
-	Name string `yaml:"name" json:"name" jsonschema:"required"`
+	// Type defines where the secret is read from - either from an 'environment' variable (default) or a 'file'
+	Type Type `yaml:"type" json:"type,omitempty" jsonschema:"enum=environment,enum=file"`
+	//Name of the environment variable to read the secret from. Required for secrets of type 'environment'.
+	Name string `yaml:"name,omitempty" json:"name"`
+	// Path of the file to read the secret from. Required for secrets of type 'file'.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
 }
 
 // OAuth defines the required information to request oAuth bearer tokens for authenticated API calls
@@ -90,8 +117,8 @@ type OAuth struct {
 
 // Auth defines all required information for authenticated API calls
 type Auth struct {
-	// Token defines an API access tokens used for Dynatrace Config API calls
-	Token AuthSecret `yaml:"token" json:"token" jsonschema:"required,description=An API access tokens used for Dynatrace Config API calls."`
+	// Token defines an API access tokens used for Dynatrace Config API calls. It may only be omitted if OAuth is defined.
+	Token AuthSecret `yaml:"token,omitempty" json:"token,omitempty" jsonschema:"description=An API access tokens used for Dynatrace Config API calls - it may only be omitted if oAuth credentials are defined, in which case configurations of classic Config APIs can't be deployed to or downloaded from the environment."`
 	// OAuth defines client credentials used for Dynatrace Platform API calls
 	OAuth *OAuth `yaml:"oAuth,omitempty" json:"oAuth" jsonschema:"description=OAuth client credentials used for Dynatrace Platform API calls - for platform environments this is required."`
 }
@@ -100,14 +127,56 @@ type Auth struct {
 type Environment struct {
 	Name string     `yaml:"name"  json:"name" jsonschema:"required,description=The name of the environment - this can be freely defined and will be used in logs, etc."`
 	URL  TypedValue `yaml:"url" json:"url" jsonschema:"required,oneof_type=string;object,description=The URL of the environment."`
+	Type string     `yaml:"type,omitempty" json:"type" jsonschema:"enum=environment,enum=cluster,description=The kind of API of the environment. 'cluster' targets the Cluster API of a Dynatrace Managed cluster, authenticated using a Cluster API token - only configurations of type 'cluster' can be deployed to it. Defaults to 'environment'."`
 
 	Auth Auth `yaml:"auth,omitempty" json:"auth" jsonschema:"required,description=This defines all information required for authenticated access to the environment's API."`
+
+	RetryPolicy *RetryPolicy `yaml:"retryPolicy,omitempty" json:"retryPolicy" jsonschema:"description=Optionally overrides how failed API calls to this environment are retried."`
+
+	HTTPSettings *HTTPSettings `yaml:"httpSettings,omitempty" json:"httpSettings" jsonschema:"description=Optionally customizes the HTTP connections to this environment - e.g. for environments only reachable through a proxy."`
+
+	ProtectedTypes []string `yaml:"protectedTypes,omitempty" json:"protectedTypes" jsonschema:"description=Config types that are not deployed to this environment unless '--allow-protected' is set. Supports wildcards - e.g. 'builtin:tags.*'."`
+
+	Parameters map[string]any `yaml:"parameters,omitempty" json:"parameters" jsonschema:"description=Values of environment parameters for this environment, overriding the defaults of its group. Configurations use them with parameters of type 'environmentParameter'."`
+}
+
+// RetryPolicy defines how failed API calls to an environment are retried
+type RetryPolicy struct {
+	MaxRetries           *int    `yaml:"maxRetries,omitempty" json:"maxRetries" jsonschema:"minimum=0,description=The maximum number of retries of a single API call. API calls expected to take longer are retried proportionally more often. 0 disables retries."`
+	WaitTime             string  `yaml:"waitTime,omitempty" json:"waitTime" jsonschema:"description=The time to wait before the first retry - e.g. '1s' or '500ms'."`
+	BackoffFactor        float64 `yaml:"backoffFactor,omitempty" json:"backoffFactor" jsonschema:"minimum=1,description=The factor the wait time is multiplied with after every retry."`
+	MaxWaitTime          string  `yaml:"maxWaitTime,omitempty" json:"maxWaitTime" jsonschema:"description=The maximum time to wait between two retries - e.g. '30s'."`
+	RetriableStatusCodes []int   `yaml:"retriableStatusCodes,omitempty" json:"retriableStatusCodes" jsonschema:"description=The HTTP status codes API calls are retried for. If not defined, all failed API calls are retried."`
+}
+
+// HTTPSettings customize the HTTP connections to an environment
+type HTTPSettings struct {
+	Proxy         string `yaml:"proxy,omitempty" json:"proxy" jsonschema:"description=The URL of a proxy all requests to this environment are sent through - e.g. 'http://proxy.example.com:8080'. If not defined, the proxy is taken from the HTTPS_PROXY and HTTP_PROXY environment variables."`
+	CACertificate string `yaml:"caCertificate,omitempty" json:"caCertificate" jsonschema:"description=The path of a PEM file holding certificates of CAs that are trusted in addition to the system's CAs - e.g. the CA that issued the certificate of a Managed cluster."`
+	Timeout       string `yaml:"timeout,omitempty" json:"timeout" jsonschema:"description=Limits the duration of a single request to this environment - e.g. '2m'."`
+
+	MaxIdleConnsPerHost    int    `yaml:"maxIdleConnsPerHost,omitempty" json:"maxIdleConnsPerHost" jsonschema:"minimum=0,description=The number of idle connections to this environment that are kept open for reuse. Defaults to 32."`
+	MaxConnsPerHost        int    `yaml:"maxConnsPerHost,omitempty" json:"maxConnsPerHost" jsonschema:"minimum=0,description=Limits the number of connections to this environment. If not defined, the number of connections is not limited."`
+	IdleConnTimeout        string `yaml:"idleConnTimeout,omitempty" json:"idleConnTimeout" jsonschema:"description=How long idle connections to this environment are kept open - e.g. '2m'. Defaults to 90s."`
+	DisableTLSSessionReuse bool   `yaml:"disableTLSSessionReuse,omitempty" json:"disableTLSSessionReuse" jsonschema:"description=Disables resuming TLS sessions when opening new connections to this environment."`
+	DisableHTTP2           bool   `yaml:"disableHTTP2,omitempty" json:"disableHTTP2" jsonschema:"description=Disables HTTP/2, so that all requests to this environment are sent using HTTP/1.1."`
 }
 
 // Group defines a group of Environment
 type Group struct {
 	Name         string        `yaml:"name" json:"name" jsonschema:"required,description=The name of the group - this can be freely defined and will be used in logs, etc."`
-	Environments []Environment `yaml:"environments" json:"environments" jsonschema:"required,minItems=1,description=The environments that are part of this group."`
+	Environments []Environment `yaml:"environments,omitempty" json:"environments" jsonschema:"description=The environments that are part of this group. Required unless the environments are discovered from an account."`
+
+	Parameters map[string]any `yaml:"parameters,omitempty" json:"parameters" jsonschema:"description=Default values of environment parameters for all environments of this group - e.g. an 'alertingEmail'. Configurations use them with parameters of type 'environmentParameter'."`
+
+	Discover *EnvironmentDiscovery `yaml:"discover,omitempty" json:"discover" jsonschema:"description=Discovers the environments of a Dynatrace account and adds them to this group when the manifest is loaded."`
+}
+
+// EnvironmentDiscovery defines how environments of a group are discovered from a Dynatrace account
+type EnvironmentDiscovery struct {
+	Account string `yaml:"account" json:"account" jsonschema:"required,description=The name of the account defined in 'accounts' to discover the environments of."`
+	Filter  string `yaml:"filter,omitempty" json:"filter" jsonschema:"description=Only environments with a name matching this pattern are added - e.g. 'prod-*'. By default, all active environments of the account are added."`
+	Auth    Auth   `yaml:"auth" json:"auth" jsonschema:"required,description=This defines all information required for authenticated access to the API of each discovered environment."`
 }
 
 type Manifest struct {
@@ -118,6 +187,19 @@ type Manifest struct {
 	EnvironmentGroups []Group `yaml:"environmentGroups" json:"environmentGroups" jsonschema:"minItems=1,description=A list of environment groups that configs in the defined 'projects' will be deployed to. Required when deploying environment configurations."`
 	// Accounts is a list of accounts that account resources in Projects will be deployed to
 	Accounts []Account `yaml:"accounts,omitempty" json:"accounts" jsonschema:"minItems=1,description=A list of of accounts that account resources defined in 'projects' will be deployed to. Required when deploying account resources."`
+	// Variables can be referenced in URLs, group names and project paths
+	Variables map[string]TypedValue `yaml:"variables,omitempty" json:"variables" jsonschema:"description=Variables that can be referenced in URLs, group names and project paths - e.g. 'https://{{ .tenant }}.live.dynatrace.com'. Each variable is either a 'value' or read from an 'environment' variable, and can be overridden with '--var name=value'."`
+	// TargetingRules restrict the environments configurations of certain types are deployed to
+	TargetingRules []TargetingRule `yaml:"targetingRules,omitempty" json:"targetingRules" jsonschema:"description=Rules restricting the environments configurations of certain types are deployed to. Configurations are skipped for environments a rule applying to their type does not target."`
+	// Includes are other manifests merged into this one
+	Includes []string `yaml:"includes,omitempty" json:"includes" jsonschema:"description=Other manifests that are merged into this one - given as path relative to this manifest or as Git source, e.g. 'git::https://github.com/example/repo.git//manifest.yaml?ref=v1.2.0'. Projects with the same name must be identical, while environments, accounts, and variables of this manifest override the ones of included manifests with the same name."`
+}
+
+// TargetingRule restricts the environments configurations of certain types are deployed to
+type TargetingRule struct {
+	ConfigTypes  []string `yaml:"configTypes" json:"configTypes" jsonschema:"required,minItems=1,description=The config types this rule applies to. Supports wildcards - e.g. 'builtin:synthetic.*'."`
+	Groups       []string `yaml:"groups,omitempty" json:"groups" jsonschema:"description=The environment groups configurations of the matching types are deployed to."`
+	Environments []string `yaml:"environments,omitempty" json:"environments" jsonschema:"description=The environments configurations of the matching types are deployed to."`
 }
 
 type Account struct {
@@ -0,0 +1,99 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"path"
+	"slices"
+	"strings"
+)
+
+// EnvironmentDiscoverer discovers the environments of a Dynatrace account
+type EnvironmentDiscoverer interface {
+	DiscoverEnvironments(ctx context.Context, account manifest.Account) ([]DiscoveredEnvironment, error)
+}
+
+// DiscoveredEnvironment is an environment of a Dynatrace account
+type DiscoveredEnvironment struct {
+	ID     string
+	Name   string
+	URL    string
+	Active bool
+}
+
+// discoverEnvironments returns the environments discovered for the group, converted to persistence environments so that
+// they can be loaded like the ones defined in the manifest. Inactive environments and ones not matching the filter are
+// not returned.
+func discoverEnvironments(c *Context, group persistence.Group, accounts map[string]manifest.Account) ([]persistence.Environment, error) {
+	d := group.Discover
+
+	if d.Account == "" {
+		return nil, fmt.Errorf("failed to discover environments: %w", errNameMissing)
+	}
+	if d.Filter != "" {
+		if _, err := path.Match(d.Filter, ""); err != nil {
+			return nil, fmt.Errorf("failed to discover environments: invalid filter %q: %w", d.Filter, err)
+		}
+	}
+
+	acc, found := accounts[d.Account]
+	if !found {
+		return nil, fmt.Errorf("failed to discover environments: account %q is not defined", d.Account)
+	}
+
+	logger := log.WithFields(field.F("manifestPath", c.ManifestPath), field.F("group", group.Name), field.F("account", acc.Name))
+	if c.Opts.DoNotResolveEnvVars || c.EnvironmentDiscoverer == nil {
+		logger.Debug("Skipped discovering environments of account %q based on loader options", acc.Name)
+		return nil, nil
+	}
+
+	discovered, err := c.EnvironmentDiscoverer.DiscoverEnvironments(context.Background(), acc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover environments of account %q: %w", acc.Name, err)
+	}
+
+	var result []persistence.Environment
+	for _, e := range discovered {
+		if !e.Active {
+			continue
+		}
+		if matches, _ := path.Match(d.Filter, e.Name); d.Filter != "" && !matches {
+			continue
+		}
+
+		result = append(result, persistence.Environment{
+			Name: e.Name,
+			URL:  persistence.TypedValue{Type: persistence.TypeValue, Value: e.URL},
+			Auth: d.Auth,
+		})
+	}
+	slices.SortFunc(result, func(a, b persistence.Environment) int { return strings.Compare(a.Name, b.Name) })
+
+	names := make([]string, len(result))
+	for i, e := range result {
+		names[i] = e.Name
+	}
+	logger.Info("Discovered %d environments of account %q for group %q: %q", len(result), acc.Name, group.Name, names)
+
+	return result, nil
+}
@@ -0,0 +1,156 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type discovererMock struct {
+	environments []DiscoveredEnvironment
+	err          error
+	calls        int
+}
+
+func (d *discovererMock) DiscoverEnvironments(_ context.Context, _ manifest.Account) ([]DiscoveredEnvironment, error) {
+	d.calls++
+	return d.environments, d.err
+}
+
+func TestLoadManifest_DiscoversEnvironments(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+	t.Setenv("CLIENT_ID", "client-id")
+	t.Setenv("CLIENT_SECRET", "client-secret")
+
+	manifestContent := func(discover string) string {
+		return `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+accounts:
+  - name: acc
+    accountUUID: 4fa7a9ce-8b1a-4a57-9a1c-0a2e4a1d3c5e
+    oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}
+environmentGroups:
+  - name: manual
+    environments: [{name: manual-env, url: {value: "https://manual.dynatrace.com"}, auth: {token: {name: TOKEN}}}]
+  - name: discovered
+    discover: ` + discover + `
+`
+	}
+
+	discovered := []DiscoveredEnvironment{
+		{ID: "ghi", Name: "prod-b", URL: "https://ghi.live.dynatrace.com", Active: true},
+		{ID: "abc", Name: "prod-a", URL: "https://abc.live.dynatrace.com", Active: true},
+		{ID: "def", Name: "prod-inactive", URL: "https://def.live.dynatrace.com", Active: false},
+		{ID: "xyz", Name: "dev", URL: "https://xyz.live.dynatrace.com", Active: true},
+	}
+
+	tests := []struct {
+		name             string
+		discover         string
+		discoverer       *discovererMock
+		opts             Options
+		wantEnvironments []string
+		wantErrorPart    string
+	}{
+		{
+			name:             "active environments matching the filter are added",
+			discover:         `{account: acc, filter: "prod-*", auth: {token: {name: TOKEN}}}`,
+			discoverer:       &discovererMock{environments: discovered},
+			wantEnvironments: []string{"manual-env", "prod-a", "prod-b"},
+		},
+		{
+			name:             "all active environments are added without filter",
+			discover:         `{account: acc, auth: {token: {name: TOKEN}}}`,
+			discoverer:       &discovererMock{environments: discovered},
+			wantEnvironments: []string{"dev", "manual-env", "prod-a", "prod-b"},
+		},
+		{
+			name:             "nothing is discovered if env vars are not resolved",
+			discover:         `{account: acc, auth: {token: {name: TOKEN}}}`,
+			discoverer:       &discovererMock{environments: discovered},
+			opts:             Options{DoNotResolveEnvVars: true},
+			wantEnvironments: []string{"manual-env"},
+		},
+		{
+			name:          "unknown account",
+			discover:      `{account: unknown, auth: {token: {name: TOKEN}}}`,
+			discoverer:    &discovererMock{environments: discovered},
+			wantErrorPart: `group "discovered": failed to discover environments: account "unknown" is not defined`,
+		},
+		{
+			name:          "invalid filter",
+			discover:      `{account: acc, filter: "[", auth: {token: {name: TOKEN}}}`,
+			discoverer:    &discovererMock{environments: discovered},
+			wantErrorPart: `invalid filter "["`,
+		},
+		{
+			name:          "discovery fails",
+			discover:      `{account: acc, auth: {token: {name: TOKEN}}}`,
+			discoverer:    &discovererMock{err: errors.New("unauthorized")},
+			wantErrorPart: `failed to discover environments of account "acc": unauthorized`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent(tt.discover)), 0400))
+
+			mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", Opts: tt.opts, EnvironmentDiscoverer: tt.discoverer})
+
+			if tt.wantErrorPart != "" {
+				require.Len(t, errs, 1)
+				assert.ErrorContains(t, errs[0], tt.wantErrorPart)
+				return
+			}
+			require.Empty(t, errs)
+			assert.ElementsMatch(t, tt.wantEnvironments, mani.Environments.Names())
+		})
+	}
+
+	t.Run("discovered environments use the url and auth of the discovery", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent(`{account: acc, filter: "prod-a", auth: {token: {name: TOKEN}}}`)), 0400))
+
+		mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", EnvironmentDiscoverer: &discovererMock{environments: discovered}})
+		require.Empty(t, errs)
+
+		env := mani.Environments["prod-a"]
+		assert.Equal(t, "discovered", env.Group)
+		assert.Equal(t, "https://abc.live.dynatrace.com", env.URL.Value)
+		assert.Equal(t, "token", env.Auth.Token.Value.Value())
+	})
+
+	t.Run("environments are only discovered once per group", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent(`{account: acc, auth: {token: {name: TOKEN}}}`)), 0400))
+
+		discoverer := &discovererMock{environments: discovered}
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", EnvironmentDiscoverer: discoverer})
+		require.Empty(t, errs)
+		assert.Equal(t, 1, discoverer.calls)
+	})
+}
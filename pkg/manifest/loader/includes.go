@@ -0,0 +1,216 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/gitsource"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+)
+
+// resolveIncludes merges the manifests included by m - and the ones included by them - into m. Included manifests are
+// given as path relative to the including manifest, or as Git source.
+//
+// Conflicts are resolved as follows:
+//   - Projects with the same name must be defined identically, otherwise loading fails.
+//   - Environment groups with the same name are merged. Environments, accounts, and variables with the same name are
+//     taken from the including manifest, overriding the ones of included manifests.
+//   - Targeting rules of all manifests apply.
+//
+// The projects of included manifests need to be located within the folder of the root manifest, as projects are loaded
+// relative to it. Repositories of included Git sources are fetched into the root manifest's Git cache.
+func resolveIncludes(context *Context, m persistence.Manifest) (persistence.Manifest, error) {
+	rootPath := filepath.Clean(context.ManifestPath)
+	return resolveIncludesOf(context, m, rootPath, []string{rootPath})
+}
+
+func resolveIncludesOf(context *Context, m persistence.Manifest, manifestPath string, includeChain []string) (persistence.Manifest, error) {
+	includes := m.Includes
+	m.Includes = nil
+
+	for _, include := range includes {
+		includedPath, err := includedManifestPath(context, manifestPath, include)
+		if err != nil {
+			return persistence.Manifest{}, newManifestLoaderError(manifestPath, fmt.Sprintf("failed to include %q: %s", include, err))
+		}
+
+		if slices.Contains(includeChain, includedPath) {
+			return persistence.Manifest{}, newManifestLoaderError(manifestPath, fmt.Sprintf("cyclic include of %q: %s", include, strings.Join(append(includeChain, includedPath), " -> ")))
+		}
+
+		included, err := readManifestFile(context.Fs, includedPath)
+		if err != nil {
+			return persistence.Manifest{}, err
+		}
+		if err := validateVersion(included); err != nil {
+			return persistence.Manifest{}, newManifestLoaderError(includedPath, fmt.Sprintf("invalid manifest definition: %s", err))
+		}
+
+		// the projects of manifests included by the included manifest are relocated when resolving them
+		if included.Projects, err = relocateProjects(filepath.Dir(includeChain[0]), filepath.Dir(includedPath), included.Projects); err != nil {
+			return persistence.Manifest{}, newManifestLoaderError(includedPath, err.Error())
+		}
+
+		included, err = resolveIncludesOf(context, included, includedPath, append(slices.Clone(includeChain), includedPath))
+		if err != nil {
+			return persistence.Manifest{}, err
+		}
+
+		if m, err = mergeManifests(m, included); err != nil {
+			return persistence.Manifest{}, newManifestLoaderError(manifestPath, fmt.Sprintf("failed to include %q: %s", include, err))
+		}
+	}
+
+	return m, nil
+}
+
+// includedManifestPath returns the local path of an included manifest. Git sources are fetched into the Git cache of
+// the root manifest.
+func includedManifestPath(context *Context, manifestPath string, include string) (string, error) {
+	if !gitsource.IsSource(include) {
+		return filepath.Clean(filepath.Join(filepath.Dir(manifestPath), filepath.FromSlash(include))), nil
+	}
+
+	src, err := gitsource.Parse(include)
+	if err != nil {
+		return "", err
+	}
+
+	fetcher := context.GitFetcher
+	if fetcher == nil {
+		fetcher = gitsource.CLIFetcher{}
+	}
+
+	rootDir := filepath.Dir(filepath.Clean(context.ManifestPath))
+	p, err := gitsource.Resolve(context.Fs, fetcher, filepath.Join(rootDir, filepath.FromSlash(GitCacheDir)), src)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(p), nil
+}
+
+// relocateProjects changes the paths of the projects of an included manifest to be relative to the root manifest
+func relocateProjects(rootDir string, includedDir string, projects []persistence.Project) ([]persistence.Project, error) {
+	result := slices.Clone(projects)
+	for i := range result {
+		p := &result[i]
+		if gitsource.IsSource(p.Path) {
+			continue
+		}
+
+		projectPath := p.Path
+		if projectPath == "" {
+			projectPath = p.Name
+		}
+
+		rel, err := filepath.Rel(rootDir, filepath.Join(includedDir, filepath.FromSlash(projectPath)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("project %q is not located within the folder of the root manifest %q", p.Name, rootDir)
+		}
+		p.Path = filepath.ToSlash(rel)
+	}
+	return result, nil
+}
+
+// withEffectivePath returns p with its path set to the one it is loaded from, which is its name if no path is defined
+func withEffectivePath(p persistence.Project) persistence.Project {
+	if p.Path == "" {
+		p.Path = p.Name
+	}
+	if !gitsource.IsSource(p.Path) {
+		p.Path = path.Clean(filepath.ToSlash(p.Path))
+	}
+	return p
+}
+
+// mergeManifests merges an included manifest into the including one, see [resolveIncludes] for how conflicts are resolved
+func mergeManifests(including, included persistence.Manifest) (persistence.Manifest, error) {
+	result := including
+
+	result.Projects = slices.Clone(including.Projects)
+	for _, p := range included.Projects {
+		i := slices.IndexFunc(result.Projects, func(existing persistence.Project) bool { return existing.Name == p.Name })
+		if i < 0 {
+			result.Projects = append(result.Projects, p)
+			continue
+		}
+		if !reflect.DeepEqual(withEffectivePath(result.Projects[i]), withEffectivePath(p)) {
+			return persistence.Manifest{}, fmt.Errorf("project %q is defined differently in the including and the included manifest", p.Name)
+		}
+	}
+
+	definedEnvironments := make(map[string]struct{})
+	for _, g := range including.EnvironmentGroups {
+		for _, e := range g.Environments {
+			definedEnvironments[e.Name] = struct{}{}
+		}
+	}
+
+	result.EnvironmentGroups = slices.Clone(including.EnvironmentGroups)
+	for _, g := range included.EnvironmentGroups {
+		var environments []persistence.Environment
+		for _, e := range g.Environments {
+			if _, found := definedEnvironments[e.Name]; found {
+				log.Debug("Environment %q of an included manifest is overridden by the including manifest", e.Name)
+				continue
+			}
+			environments = append(environments, e)
+		}
+
+		i := slices.IndexFunc(result.EnvironmentGroups, func(existing persistence.Group) bool { return existing.Name == g.Name })
+		if i < 0 {
+			g.Environments = environments
+			result.EnvironmentGroups = append(result.EnvironmentGroups, g)
+			continue
+		}
+
+		existing := &result.EnvironmentGroups[i]
+		existing.Environments = append(slices.Clone(existing.Environments), environments...)
+		existing.Parameters = mergeParameters(g.Parameters, existing.Parameters)
+		if existing.Discover == nil {
+			existing.Discover = g.Discover
+		}
+	}
+
+	result.Accounts = slices.Clone(including.Accounts)
+	for _, a := range included.Accounts {
+		if !slices.ContainsFunc(result.Accounts, func(existing persistence.Account) bool { return existing.Name == a.Name }) {
+			result.Accounts = append(result.Accounts, a)
+		}
+	}
+
+	if len(included.Variables) > 0 {
+		result.Variables = make(map[string]persistence.TypedValue, len(including.Variables)+len(included.Variables))
+		for k, v := range included.Variables {
+			result.Variables[k] = v
+		}
+		for k, v := range including.Variables {
+			result.Variables[k] = v
+		}
+	}
+
+	result.TargetingRules = append(slices.Clone(including.TargetingRules), included.TargetingRules...)
+
+	return result, nil
+}
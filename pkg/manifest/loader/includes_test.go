@@ -0,0 +1,207 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/gitsource"
+)
+
+func TestLoadManifest_Includes(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+
+	writeFiles := func(t *testing.T, files map[string]string) afero.Fs {
+		fs := afero.NewMemMapFs()
+		for p, content := range files {
+			require.NoError(t, fs.MkdirAll(filepath.Dir(p), 0777))
+			require.NoError(t, afero.WriteFile(fs, p, []byte(content), 0400))
+		}
+		return fs
+	}
+
+	t.Run("included manifests are merged", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"manifest.yaml": `
+manifestVersion: 1.0
+includes: [teams/a/manifest.yaml]
+projects: [{name: root}]
+environmentGroups:
+  - {name: prod, environments: [{name: prod-eu, url: {value: "https://override.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}
+`,
+			"root/.keep": "",
+			"teams/a/manifest.yaml": `
+manifestVersion: 1.0
+includes: [../shared/manifest.yaml]
+projects: [{name: team-a}, {name: team-a-alerting, path: alerting}]
+environmentGroups:
+  - {name: prod, environments: [{name: prod-eu, url: {value: "https://eu.dynatrace.com"}, auth: {token: {name: TOKEN}}}, {name: prod-us, url: {value: "https://us.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}
+targetingRules: [{configTypes: [synthetic-monitor], groups: [prod]}]
+`,
+			"teams/a/team-a/.keep":   "",
+			"teams/a/alerting/.keep": "",
+			"teams/shared/manifest.yaml": `
+manifestVersion: 1.0
+projects: [{name: shared}]
+environmentGroups:
+  - {name: dev, environments: [{name: dev, url: {value: "https://dev.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}
+`,
+			"teams/shared/shared/.keep": "",
+		})
+
+		mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Empty(t, errs)
+
+		assert.Equal(t, manifest.ProjectDefinitionByProjectID{
+			"root":            {Name: "root", Path: "root"},
+			"team-a":          {Name: "team-a", Path: "teams/a/team-a"},
+			"team-a-alerting": {Name: "team-a-alerting", Path: "teams/a/alerting"},
+			"shared":          {Name: "shared", Path: "teams/shared/shared"},
+		}, mani.Projects)
+
+		require.Len(t, mani.Environments, 3)
+		assert.Equal(t, "https://override.dynatrace.com", mani.Environments["prod-eu"].URL.Value, "environment of including manifest should take precedence")
+		assert.Equal(t, "prod", mani.Environments["prod-us"].Group)
+		assert.Equal(t, "dev", mani.Environments["dev"].Group)
+
+		assert.Equal(t, []manifest.TargetingRule{{ConfigTypes: []string{"synthetic-monitor"}, Groups: []string{"prod"}}}, mani.TargetingRules)
+	})
+
+	t.Run("identical projects can be defined by several manifests", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"manifest.yaml": `
+manifestVersion: 1.0
+includes: [other.yaml]
+projects: [{name: p}]
+environmentGroups: [{name: g, environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}]
+`,
+			"other.yaml": `
+manifestVersion: 1.0
+projects: [{name: p}]
+`,
+			"p/.keep": "",
+		})
+
+		mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Empty(t, errs)
+		assert.Len(t, mani.Projects, 1)
+	})
+
+	t.Run("conflicting projects are rejected", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"manifest.yaml": `
+manifestVersion: 1.0
+includes: [other.yaml]
+projects: [{name: p}]
+environmentGroups: [{name: g, environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}]
+`,
+			"other.yaml": `
+manifestVersion: 1.0
+projects: [{name: p, path: somewhere-else}]
+`,
+		})
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], `project "p" is defined differently in the including and the included manifest`)
+	})
+
+	t.Run("cyclic includes are rejected", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"manifest.yaml": `
+manifestVersion: 1.0
+includes: [a/manifest.yaml]
+projects: [{name: p}]
+`,
+			"a/manifest.yaml": `
+manifestVersion: 1.0
+includes: [../manifest.yaml]
+projects: []
+`,
+		})
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "cyclic include of \"../manifest.yaml\": manifest.yaml -> "+filepath.Join("a", "manifest.yaml")+" -> manifest.yaml")
+	})
+
+	t.Run("projects outside of the root manifest's folder are rejected", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"root/manifest.yaml": `
+manifestVersion: 1.0
+includes: [../other/manifest.yaml]
+projects: []
+`,
+			"other/manifest.yaml": `
+manifestVersion: 1.0
+projects: [{name: p}]
+`,
+		})
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "root/manifest.yaml"})
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], `project "p" is not located within the folder of the root manifest`)
+	})
+
+	t.Run("missing included manifest", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"manifest.yaml": `
+manifestVersion: 1.0
+includes: [missing.yaml]
+projects: []
+`,
+		})
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "missing.yaml: manifest file does not exist")
+	})
+
+	t.Run("manifests are included from Git sources", func(t *testing.T) {
+		fs := writeFiles(t, map[string]string{
+			"manifest.yaml": `
+manifestVersion: 1.0
+includes: ["git::https://github.com/example/repo.git//monaco/manifest.yaml?ref=v1"]
+projects: []
+environmentGroups: [{name: g, environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}]
+`,
+		})
+
+		fetcher := gitFetcherFunc(func(src gitsource.Source, dir string) error {
+			if err := fs.MkdirAll(filepath.Join(dir, "monaco", "remote"), 0777); err != nil {
+				return err
+			}
+			return afero.WriteFile(fs, filepath.Join(dir, "monaco", "manifest.yaml"), []byte("manifestVersion: 1.0\nprojects: [{name: remote}]\n"), 0400)
+		})
+
+		mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", GitFetcher: fetcher})
+		require.Empty(t, errs)
+		require.Contains(t, mani.Projects, "remote")
+
+		exists, err := afero.DirExists(fs, mani.Projects["remote"].Path)
+		require.NoError(t, err)
+		assert.True(t, exists, "project should be located in the Git cache")
+	})
+}
@@ -17,6 +17,7 @@
 package loader
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
@@ -25,14 +26,18 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	version2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/gitsource"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v2"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 )
 
 // Context holds all information for [Load]
@@ -59,11 +64,30 @@ type Context struct {
 
 	// Opts are Options holding optional configuration for Load
 	Opts Options
+
+	// Variables override the values of variables defined in the manifest, e.g. given as '--var name=value'
+	Variables map[string]string
+
+	// EnvironmentDiscoverer discovers the environments of groups that define an account to discover them from.
+	// If it is nil, no environments are discovered.
+	EnvironmentDiscoverer EnvironmentDiscoverer
+
+	// GitFetcher fetches the repositories of projects whose path is a Git source (see [gitsource]).
+	// If it is nil, the git command line tool is used.
+	GitFetcher gitsource.Fetcher
 }
 
+// GitCacheDir is the folder, relative to the manifest, repositories of projects loaded from Git sources are cached in
+const GitCacheDir = ".monaco/git"
+
 type projectLoaderContext struct {
 	fs           afero.Fs
 	manifestPath string
+
+	// rootFs and workingDir are needed to fetch projects from Git sources into the cache within the working dir
+	rootFs     afero.Fs
+	workingDir string
+	gitFetcher gitsource.Fetcher
 }
 
 // Options are optional configuration for Load
@@ -145,6 +169,16 @@ func Load(context *Context) (manifest.Manifest, []error) {
 	if err := validateVersion(manifestYAML); err != nil {
 		return manifest.Manifest{}, []error{newManifestLoaderError(context.ManifestPath, fmt.Sprintf("invalid manifest definition: %s", err))}
 	}
+	warnAboutOutdatedVersion(context.ManifestPath, manifestYAML)
+
+	variables, err := parseVariables(context, manifestYAML.Variables)
+	if err != nil {
+		return manifest.Manifest{}, []error{newManifestLoaderError(context.ManifestPath, fmt.Sprintf("invalid variables: %s", err))}
+	}
+
+	if manifestYAML, err = interpolateVariables(manifestYAML, variables); err != nil {
+		return manifest.Manifest{}, []error{newManifestLoaderError(context.ManifestPath, err.Error())}
+	}
 
 	if context.Opts.RequireEnvironmentGroups && len(manifestYAML.EnvironmentGroups) == 0 {
 		return manifest.Manifest{}, []error{newManifestLoaderError(context.ManifestPath, "'environmentGroups' are required, but not defined")}
@@ -169,30 +203,41 @@ func Load(context *Context) (manifest.Manifest, []error) {
 	var errs []error
 
 	// projects
+	gitFetcher := context.GitFetcher
+	if gitFetcher == nil {
+		gitFetcher = gitsource.CLIFetcher{}
+	}
+
 	projectDefinitions, projectErrors := parseProjects(&projectLoaderContext{
 		fs:           workingDirFs,
 		manifestPath: relativeManifestPath,
+		rootFs:       context.Fs,
+		workingDir:   workingDir,
+		gitFetcher:   gitFetcher,
 	}, manifestYAML.Projects)
 	if projectErrors != nil {
 		errs = append(errs, projectErrors...)
 	}
 
+	// accounts
+	accounts, accErr := parseAccounts(context, manifestYAML.Accounts)
+	if accErr != nil {
+		errs = append(errs, newManifestLoaderError(context.ManifestPath, accErr.Error()))
+	}
+
 	// environments
 	var environmentDefinitions map[string]manifest.EnvironmentDefinition
 	if len(manifestYAML.EnvironmentGroups) > 0 {
 		var manifestErrors []error
-		if environmentDefinitions, manifestErrors = parseEnvironments(context, manifestYAML.EnvironmentGroups); manifestErrors != nil {
+		if environmentDefinitions, manifestErrors = parseEnvironments(context, manifestYAML.EnvironmentGroups, accounts); manifestErrors != nil {
 			errs = append(errs, manifestErrors...)
 		} else if len(environmentDefinitions) == 0 {
 			errs = append(errs, newManifestLoaderError(context.ManifestPath, "no environments defined in manifest"))
 		}
 	}
 
-	// accounts
-	accounts, accErr := parseAccounts(context, manifestYAML.Accounts)
-	if accErr != nil {
-		errs = append(errs, newManifestLoaderError(context.ManifestPath, accErr.Error()))
-	}
+	targetingRules, targetingErrs := parseTargetingRules(context, manifestYAML)
+	errs = append(errs, targetingErrs...)
 
 	// if any errors occurred up to now, return them
 	if errs != nil {
@@ -200,13 +245,24 @@ func Load(context *Context) (manifest.Manifest, []error) {
 	}
 
 	return manifest.Manifest{
-		Projects:     projectDefinitions,
-		Environments: environmentDefinitions,
-		Accounts:     accounts,
+		Projects:       projectDefinitions,
+		Environments:   environmentDefinitions,
+		Accounts:       accounts,
+		Variables:      variables,
+		TargetingRules: targetingRules,
 	}, nil
 }
 
 func parseAuth(context *Context, a persistence.Auth) (manifest.Auth, error) {
+	if a.Token == (persistence.AuthSecret{}) && a.OAuth != nil {
+		// environments may authenticate with OAuth credentials only - calls to the classic Config APIs are not possible then
+		o, err := parseOAuth(context, *a.OAuth)
+		if err != nil {
+			return manifest.Auth{}, fmt.Errorf("failed to parse OAuth credentials: %w", err)
+		}
+		return manifest.Auth{OAuth: &o}, nil
+	}
+
 	token, err := parseAuthSecret(context, a.Token)
 	if err != nil {
 		return manifest.Auth{}, fmt.Errorf("error parsing token: %w", err)
@@ -232,8 +288,12 @@ func parseAuth(context *Context, a persistence.Auth) (manifest.Auth, error) {
 
 func parseAuthSecret(context *Context, s persistence.AuthSecret) (manifest.AuthSecret, error) {
 
+	if s.Type == persistence.TypeFile {
+		return parseFileAuthSecret(context, s)
+	}
+
 	if !(s.Type == persistence.TypeEnvironment || s.Type == "") {
-		return manifest.AuthSecret{}, errors.New("type must be 'environment'")
+		return manifest.AuthSecret{}, errors.New("type must be 'environment' or 'file'")
 	}
 
 	if s.Name == "" {
@@ -260,6 +320,35 @@ func parseAuthSecret(context *Context, s persistence.AuthSecret) (manifest.AuthS
 	return manifest.AuthSecret{Name: s.Name, Value: secret.MaskedString(v)}, nil
 }
 
+// parseFileAuthSecret reads a secret of type 'file' from its path. Surrounding whitespace - like the trailing newline many
+// secret stores write - is removed.
+func parseFileAuthSecret(context *Context, s persistence.AuthSecret) (manifest.AuthSecret, error) {
+	if s.Path == "" {
+		return manifest.AuthSecret{}, errors.New("no path given or empty")
+	}
+
+	if context.Opts.DoNotResolveEnvVars {
+		log.Debug("Skipped reading secret file %s based on loader options", s.Path)
+		return manifest.AuthSecret{
+			Name:  s.Name,
+			Path:  s.Path,
+			Value: secret.MaskedString(fmt.Sprintf("SKIPPED RESOLUTION OF SECRET FILE: %s", s.Path)),
+		}, nil
+	}
+
+	content, err := afero.ReadFile(context.Fs, s.Path)
+	if err != nil {
+		return manifest.AuthSecret{}, fmt.Errorf("failed to read secret file %q: %w", s.Path, err)
+	}
+
+	v := strings.TrimSpace(string(content))
+	if v == "" {
+		return manifest.AuthSecret{}, fmt.Errorf("secret file %q found, but it is empty", s.Path)
+	}
+
+	return manifest.AuthSecret{Name: s.Name, Path: s.Path, Value: secret.MaskedString(v)}, nil
+}
+
 func parseOAuth(context *Context, a persistence.OAuth) (manifest.OAuth, error) {
 	clientID, err := parseAuthSecret(context, a.ClientID)
 	if err != nil {
@@ -291,29 +380,38 @@ func parseOAuth(context *Context, a persistence.OAuth) (manifest.OAuth, error) {
 	}, nil
 }
 
+// readManifestYAML reads the manifest of the context, including the manifests it includes
 func readManifestYAML(context *Context) (persistence.Manifest, error) {
-	manifestPath := filepath.Clean(context.ManifestPath)
+	m, err := readManifestFile(context.Fs, context.ManifestPath)
+	if err != nil {
+		return persistence.Manifest{}, err
+	}
+	return resolveIncludes(context, m)
+}
+
+func readManifestFile(fs afero.Fs, path string) (persistence.Manifest, error) {
+	manifestPath := filepath.Clean(path)
 
 	if !files.IsYamlFileExtension(manifestPath) {
-		return persistence.Manifest{}, newManifestLoaderError(context.ManifestPath, "manifest file is not a yaml")
+		return persistence.Manifest{}, newManifestLoaderError(path, "manifest file is not a yaml")
 	}
 
-	if exists, err := files.DoesFileExist(context.Fs, manifestPath); err != nil {
+	if exists, err := files.DoesFileExist(fs, manifestPath); err != nil {
 		return persistence.Manifest{}, err
 	} else if !exists {
-		return persistence.Manifest{}, newManifestLoaderError(context.ManifestPath, "manifest file does not exist")
+		return persistence.Manifest{}, newManifestLoaderError(path, "manifest file does not exist")
 	}
 
-	rawData, err := afero.ReadFile(context.Fs, manifestPath)
+	rawData, err := afero.ReadFile(fs, manifestPath)
 	if err != nil {
-		return persistence.Manifest{}, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("error while reading the manifest: %s", err))
+		return persistence.Manifest{}, newManifestLoaderError(path, fmt.Sprintf("error while reading the manifest: %s", err))
 	}
 
 	var m persistence.Manifest
 
 	err = yaml.UnmarshalStrict(rawData, &m)
 	if err != nil {
-		return persistence.Manifest{}, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("error during parsing the manifest: %s", err))
+		return persistence.Manifest{}, newManifestLoaderError(path, fmt.Sprintf("error during parsing the manifest: %s", err))
 	}
 	return m, nil
 }
@@ -343,7 +441,7 @@ func validateVersion(m persistence.Manifest) error {
 	return nil
 }
 
-func parseEnvironments(context *Context, groups []persistence.Group) (map[string]manifest.EnvironmentDefinition, []error) { // nolint:gocognit
+func parseEnvironments(context *Context, groups []persistence.Group, accounts map[string]manifest.Account) (map[string]manifest.EnvironmentDefinition, []error) { // nolint:gocognit
 	var errors []error
 	environments := make(map[string]manifest.EnvironmentDefinition)
 
@@ -361,6 +459,15 @@ func parseEnvironments(context *Context, groups []persistence.Group) (map[string
 
 		groupNames[group.Name] = true
 
+		if group.Discover != nil {
+			discovered, err := discoverEnvironments(context, group, accounts)
+			if err != nil {
+				errors = append(errors, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("group %q: %s", group.Name, err)))
+				continue
+			}
+			group.Environments = append(slices.Clone(group.Environments), discovered...)
+		}
+
 		for j, env := range group.Environments {
 
 			if env.Name == "" {
@@ -380,7 +487,7 @@ func parseEnvironments(context *Context, groups []persistence.Group) (map[string
 				continue
 			}
 
-			parsedEnv, configErrors := parseSingleEnvironment(context, env, group.Name)
+			parsedEnv, configErrors := parseSingleEnvironment(context, env, group.Name, group.Parameters)
 
 			if configErrors != nil {
 				errors = append(errors, configErrors...)
@@ -428,7 +535,7 @@ func shouldSkipEnv(context *Context, group persistence.Group, env persistence.En
 	return true
 }
 
-func parseSingleEnvironment(context *Context, config persistence.Environment, group string) (manifest.EnvironmentDefinition, []error) {
+func parseSingleEnvironment(context *Context, config persistence.Environment, group string, groupParameters map[string]any) (manifest.EnvironmentDefinition, []error) {
 	var errs []error
 
 	a, err := parseAuth(context, config.Auth)
@@ -441,18 +548,186 @@ func parseSingleEnvironment(context *Context, config persistence.Environment, gr
 		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, err.Error()))
 	}
 
+	retryPolicy, err := parseRetryPolicy(config.RetryPolicy)
+	if err != nil {
+		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, fmt.Sprintf("failed to parse retry policy: %s", err)))
+	}
+
+	httpSettings, err := parseHTTPSettings(context, config.HTTPSettings)
+	if err != nil {
+		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, fmt.Sprintf("failed to parse http settings: %s", err)))
+	}
+
+	envType, err := parseEnvironmentType(config.Type, a)
+	if err != nil {
+		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, err.Error()))
+	}
+
+	for _, p := range config.ProtectedTypes {
+		if _, err := path.Match(p, ""); err != nil {
+			errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, fmt.Sprintf("invalid protected type %q: %s", p, err)))
+		}
+	}
+
 	if len(errs) > 0 {
 		return manifest.EnvironmentDefinition{}, errs
 	}
 
 	return manifest.EnvironmentDefinition{
-		Name:  config.Name,
-		URL:   urlDef,
-		Auth:  a,
-		Group: group,
+		Name:           config.Name,
+		URL:            urlDef,
+		Auth:           a,
+		Type:           envType,
+		Group:          group,
+		RetryPolicy:    retryPolicy,
+		HTTPSettings:   httpSettings,
+		ProtectedTypes: slices.Clone(config.ProtectedTypes),
+		Parameters:     mergeParameters(groupParameters, config.Parameters),
 	}, nil
 }
 
+// parseEnvironmentType returns the type of an environment. Tenants are of the empty type, clusters must be
+// authenticated using a Cluster API token.
+func parseEnvironmentType(t string, a manifest.Auth) (manifest.EnvironmentType, error) {
+	switch manifest.EnvironmentType(t) {
+	case "", manifest.TenantEnvironmentType:
+		return "", nil
+	case manifest.ClusterEnvironmentType:
+		if !a.HasToken() || a.HasOAuth() {
+			return "", errors.New("cluster environments must be authenticated using a Cluster API token ('auth.token') and must not define OAuth credentials")
+		}
+		return manifest.ClusterEnvironmentType, nil
+	default:
+		return "", fmt.Errorf("unknown environment type %q, must be one of %q", t, []manifest.EnvironmentType{manifest.TenantEnvironmentType, manifest.ClusterEnvironmentType})
+	}
+}
+
+// mergeParameters returns the default parameters of a group, overridden by the parameters of an environment
+func mergeParameters(groupParameters, environmentParameters map[string]any) map[string]any {
+	if len(groupParameters) == 0 && len(environmentParameters) == 0 {
+		return nil
+	}
+
+	result := make(map[string]any, len(groupParameters)+len(environmentParameters))
+	for k, v := range groupParameters {
+		result[k] = v
+	}
+	for k, v := range environmentParameters {
+		result[k] = v
+	}
+	return result
+}
+
+func parseRetryPolicy(p *persistence.RetryPolicy) (*manifest.RetryPolicy, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	if p.MaxRetries != nil && *p.MaxRetries < 0 {
+		return nil, fmt.Errorf("maxRetries must not be negative, but is %d", *p.MaxRetries)
+	}
+	if p.BackoffFactor != 0 && p.BackoffFactor < 1 {
+		return nil, fmt.Errorf("backoffFactor must be at least 1, but is %v", p.BackoffFactor)
+	}
+
+	waitTime, err := parseOptionalDuration("waitTime", p.WaitTime)
+	if err != nil {
+		return nil, err
+	}
+	maxWaitTime, err := parseOptionalDuration("maxWaitTime", p.MaxWaitTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range p.RetriableStatusCodes {
+		if c < 100 || c > 599 {
+			return nil, fmt.Errorf("%d is not a valid HTTP status code", c)
+		}
+	}
+
+	return &manifest.RetryPolicy{
+		MaxRetries:           p.MaxRetries,
+		WaitTime:             waitTime,
+		BackoffFactor:        p.BackoffFactor,
+		MaxWaitTime:          maxWaitTime,
+		RetriableStatusCodes: slices.Clone(p.RetriableStatusCodes),
+	}, nil
+}
+
+func parseHTTPSettings(context *Context, s *persistence.HTTPSettings) (*manifest.HTTPSettings, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	result := manifest.HTTPSettings{
+		CACertificatePath:      s.CACertificate,
+		MaxIdleConnsPerHost:    s.MaxIdleConnsPerHost,
+		MaxConnsPerHost:        s.MaxConnsPerHost,
+		DisableTLSSessionReuse: s.DisableTLSSessionReuse,
+		DisableHTTP2:           s.DisableHTTP2,
+	}
+
+	if s.Proxy != "" {
+		proxy, err := url.Parse(s.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %q is not a valid URL: %w", s.Proxy, err)
+		}
+		if !slices.Contains([]string{"http", "https", "socks5"}, proxy.Scheme) || proxy.Host == "" {
+			return nil, fmt.Errorf("proxy %q is not a valid URL: it must define a host and one of the schemes 'http', 'https', or 'socks5'", s.Proxy)
+		}
+		result.Proxy = proxy
+	}
+
+	if s.CACertificate != "" && !context.Opts.DoNotResolveEnvVars {
+		content, err := afero.ReadFile(context.Fs, s.CACertificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate file %q: %w", s.CACertificate, err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("CA certificate file %q does not contain any PEM encoded certificate", s.CACertificate)
+		}
+		result.CACertificates = content
+	}
+
+	timeout, err := parseOptionalDuration("timeout", s.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("timeout must not be negative, but is %s", s.Timeout)
+	}
+	result.Timeout = timeout
+
+	if s.MaxIdleConnsPerHost < 0 {
+		return nil, fmt.Errorf("maxIdleConnsPerHost must not be negative, but is %d", s.MaxIdleConnsPerHost)
+	}
+	if s.MaxConnsPerHost < 0 {
+		return nil, fmt.Errorf("maxConnsPerHost must not be negative, but is %d", s.MaxConnsPerHost)
+	}
+
+	idleConnTimeout, err := parseOptionalDuration("idleConnTimeout", s.IdleConnTimeout)
+	if err != nil {
+		return nil, err
+	}
+	result.IdleConnTimeout = idleConnTimeout
+
+	return &result, nil
+}
+
+func parseOptionalDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a valid duration: %w", name, value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative, but is %q", name, value)
+	}
+	return d, nil
+}
+
 func parseURLDefinition(context *Context, u persistence.TypedValue) (manifest.URLDefinition, error) {
 
 	// Depending on the type, the url.value either contains the env var name or the direct value of the url
@@ -529,6 +804,8 @@ func parseProjects(context *projectLoaderContext, definitions []persistence.Proj
 		}
 	}
 
+	errors = append(errors, validateDependsOn(context, result)...)
+
 	if errors != nil {
 		return nil, errors
 	}
@@ -536,6 +813,27 @@ func parseProjects(context *projectLoaderContext, definitions []persistence.Proj
 	return result, nil
 }
 
+// validateDependsOn returns an error for every project depending on a project or grouping project that is not defined
+func validateDependsOn(context *projectLoaderContext, projects map[string]manifest.ProjectDefinition) []error {
+	known := make(map[string]struct{}, len(projects))
+	for _, p := range projects {
+		known[p.Name] = struct{}{}
+		if p.Group != "" {
+			known[p.Group] = struct{}{}
+		}
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(projects) {
+		for _, d := range projects[name].DependsOn {
+			if _, found := known[d]; !found {
+				errs = append(errs, newManifestProjectLoaderError(context.manifestPath, name, fmt.Sprintf("depends on unknown project `%s`", d)))
+			}
+		}
+	}
+	return errs
+}
+
 func checkForDuplicateDefinitions(context *projectLoaderContext, definitions []persistence.Project) (errors []error) {
 	definedIds := map[string]struct{}{}
 	for _, project := range definitions {
@@ -560,15 +858,96 @@ func parseProjectDefinition(context *projectLoaderContext, project persistence.P
 		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name, "project name is required")}
 	}
 
+	hooks, err := parseHooks(project.Hooks)
+	if err != nil {
+		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name, fmt.Sprintf("invalid hooks: %s", err))}
+	}
+
+	if gitsource.IsSource(project.Path) {
+		localPath, err := fetchProject(context, project.Path)
+		if err != nil {
+			return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name, err.Error())}
+		}
+		project.Path = localPath
+	}
+
+	var definitions []manifest.ProjectDefinition
+	var errs []error
 	switch projectType {
 	case persistence.SimpleProjectType:
-		return parseSimpleProjectDefinition(context, project)
+		definitions, errs = parseSimpleProjectDefinition(context, project)
 	case persistence.GroupProjectType:
-		return parseGroupingProjectDefinition(context, project)
+		definitions, errs = parseGroupingProjectDefinition(context, project)
 	default:
 		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name,
 			fmt.Sprintf("invalid project type `%s`", projectType))}
 	}
+
+	for i := range definitions {
+		definitions[i].Hooks = hooks
+		definitions[i].DependsOn = project.DependsOn
+	}
+	return definitions, errs
+}
+
+// fetchProject fetches the Git source p into the Git cache of the working dir, and returns the local path of the
+// project relative to the working dir
+func fetchProject(context *projectLoaderContext, p string) (string, error) {
+	src, err := gitsource.Parse(p)
+	if err != nil {
+		return "", err
+	}
+
+	localPath, err := gitsource.Resolve(context.rootFs, context.gitFetcher, filepath.Join(context.workingDir, filepath.FromSlash(GitCacheDir)), src)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Rel(context.workingDir, localPath)
+}
+
+func parseHooks(h *persistence.Hooks) ([]manifest.Hook, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	var result []manifest.Hook
+	for _, stage := range []struct {
+		stage manifest.HookStage
+		hooks []persistence.Hook
+	}{{manifest.HookStagePre, h.Pre}, {manifest.HookStagePost, h.Post}} {
+		for i, hook := range stage.hooks {
+			parsed, err := parseHook(stage.stage, hook)
+			if err != nil {
+				return nil, fmt.Errorf("%s hook %d: %w", stage.stage, i, err)
+			}
+			result = append(result, parsed)
+		}
+	}
+	return result, nil
+}
+
+func parseHook(stage manifest.HookStage, h persistence.Hook) (manifest.Hook, error) {
+	if (h.Command == "") == (h.URL == "") {
+		return manifest.Hook{}, errors.New("exactly one of `command` and `url` must be defined")
+	}
+
+	onFailure := manifest.HookFailureAbort
+	switch h.OnFailure {
+	case "", persistence.HookFailureAbort:
+	case persistence.HookFailureWarn:
+		onFailure = manifest.HookFailureWarn
+	default:
+		return manifest.Hook{}, fmt.Errorf("invalid `onFailure` value %q, must be one of %q or %q", h.OnFailure, persistence.HookFailureAbort, persistence.HookFailureWarn)
+	}
+
+	return manifest.Hook{
+		Stage:      stage,
+		Command:    h.Command,
+		URL:        h.URL,
+		ConfigType: h.ConfigType,
+		OnFailure:  onFailure,
+	}, nil
 }
 
 func parseSimpleProjectDefinition(context *projectLoaderContext, project persistence.Project) ([]manifest.ProjectDefinition, []error) {
@@ -19,18 +19,27 @@
 package loader
 
 import (
+	"encoding/pem"
+	"errors"
 	"fmt"
 	monacoVersion "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/gitsource"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_extractUrlType(t *testing.T) {
@@ -258,6 +267,63 @@ func Test_parseProjectDefinition_FailsOnUnknownType(t *testing.T) {
 	assert.IsType(t, ProjectLoaderError{}, gotErrs[0])
 }
 
+func Test_parseProjectDefinition_ParsesHooks(t *testing.T) {
+	context := projectLoaderContext{
+		fs:           nil,
+		manifestPath: ".",
+	}
+	project := persistence.Project{
+		Name: "PROJ_NAME",
+		Hooks: &persistence.Hooks{
+			Pre:  []persistence.Hook{{Command: "./smoke-test.sh", ConfigType: "dashboard"}},
+			Post: []persistence.Hook{{URL: "https://example.com/notify", OnFailure: persistence.HookFailureWarn}},
+		},
+	}
+
+	got, gotErrs := parseProjectDefinition(&context, project)
+
+	assert.Empty(t, gotErrs)
+	assert.Equal(t, []manifest.ProjectDefinition{
+		{
+			Name: "PROJ_NAME",
+			Path: "PROJ_NAME",
+			Hooks: []manifest.Hook{
+				{Stage: manifest.HookStagePre, Command: "./smoke-test.sh", ConfigType: "dashboard", OnFailure: manifest.HookFailureAbort},
+				{Stage: manifest.HookStagePost, URL: "https://example.com/notify", OnFailure: manifest.HookFailureWarn},
+			},
+		},
+	}, got)
+}
+
+func Test_parseProjectDefinition_FailsOnInvalidHooks(t *testing.T) {
+	context := projectLoaderContext{
+		fs:           nil,
+		manifestPath: ".",
+	}
+
+	tests := []struct {
+		name string
+		hook persistence.Hook
+	}{
+		{"neither command nor url", persistence.Hook{}},
+		{"both command and url", persistence.Hook{Command: "true", URL: "https://example.com"}},
+		{"unknown failure mode", persistence.Hook{Command: "true", OnFailure: "ignore"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := persistence.Project{
+				Name:  "PROJ_NAME",
+				Hooks: &persistence.Hooks{Pre: []persistence.Hook{tt.hook}},
+			}
+
+			_, gotErrs := parseProjectDefinition(&context, project)
+
+			assert.Len(t, gotErrs, 1)
+			assert.IsType(t, ProjectLoaderError{}, gotErrs[0])
+		})
+	}
+}
+
 func Test_parseProjectDefinition_FailsOnInvalidProjectDefinitions(t *testing.T) {
 	context := projectLoaderContext{
 		fs:           afero.NewMemMapFs(),
@@ -321,6 +387,21 @@ func Test_parseProjectDefinition_FailsOnInvalidProjectDefinitions(t *testing.T)
 
 }
 
+func Test_parseRetryPolicy_MaxRetries(t *testing.T) {
+	zero, negative := 0, -1
+
+	got, err := parseRetryPolicy(&persistence.RetryPolicy{MaxRetries: &zero})
+	assert.NoError(t, err)
+	assert.Equal(t, &zero, got.MaxRetries, "zero max retries must be kept to disable retries")
+
+	got, err = parseRetryPolicy(&persistence.RetryPolicy{WaitTime: "1s"})
+	assert.NoError(t, err)
+	assert.Nil(t, got.MaxRetries, "undefined max retries keep the default")
+
+	_, err = parseRetryPolicy(&persistence.RetryPolicy{MaxRetries: &negative})
+	assert.ErrorContains(t, err, "maxRetries must not be negative")
+}
+
 func Test_toProjectDefinitions(t *testing.T) {
 
 	testFs := afero.NewMemMapFs()
@@ -483,7 +564,7 @@ func Test_toProjectDefinitions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			context := &projectLoaderContext{testFs, "path/to/a/manifest.yaml"}
+			context := &projectLoaderContext{fs: testFs, manifestPath: "path/to/a/manifest.yaml"}
 
 			got, gotErrs := parseProjects(context, tt.projectDefinitions)
 
@@ -724,6 +805,8 @@ func TestLoadManifest(t *testing.T) {
 	t.Setenv("client-secret", "resolved-client-secret")
 	t.Setenv("ENV_OAUTH_ENDPOINT", "resolved-oauth-endpoint")
 
+	maxRetries := 5
+
 	tests := []struct {
 		name            string
 		manifestContent string
@@ -771,6 +854,159 @@ environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {t
 				Accounts: map[string]manifest.Account{},
 			},
 		},
+		{
+			name: "Retry policy is loaded",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, retryPolicy: {maxRetries: 5, waitTime: 500ms, backoffFactor: 2, maxWaitTime: 10s, retriableStatusCodes: [429, 503]}}]}]
+`,
+			expectedManifest: manifest.Manifest{
+				Projects: map[string]manifest.ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]manifest.EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						URL: manifest.URLDefinition{
+							Type:  manifest.ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: manifest.Auth{
+							Token: manifest.AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+						},
+						RetryPolicy: &manifest.RetryPolicy{
+							MaxRetries:           &maxRetries,
+							WaitTime:             500 * time.Millisecond,
+							BackoffFactor:        2,
+							MaxWaitTime:          10 * time.Second,
+							RetriableStatusCodes: []int{429, 503},
+						},
+					},
+				},
+				Accounts: map[string]manifest.Account{},
+			},
+		},
+		{
+			name: "Retry policy with invalid wait time fails",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, retryPolicy: {waitTime: soon}}]}]
+`,
+			errsContain: []string{`waitTime "soon" is not a valid duration`},
+		},
+		{
+			name: "Retry policy with backoff factor below 1 fails",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, retryPolicy: {backoffFactor: 0.5}}]}]
+`,
+			errsContain: []string{"backoffFactor must be at least 1"},
+		},
+		{
+			name: "Protected types are loaded",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, protectedTypes: [auto-tag, "builtin:tags.*"]}]}]
+`,
+			expectedManifest: manifest.Manifest{
+				Projects: map[string]manifest.ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]manifest.EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						URL: manifest.URLDefinition{
+							Type:  manifest.ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: manifest.Auth{
+							Token: manifest.AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+						},
+						ProtectedTypes: []string{"auto-tag", "builtin:tags.*"},
+					},
+				},
+				Accounts: map[string]manifest.Account{},
+			},
+		},
+		{
+			name: "Invalid protected type pattern fails",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, protectedTypes: ["builtin:["]}]}]
+`,
+			errsContain: []string{`invalid protected type "builtin:["`},
+		},
+		{
+			name: "Cluster environments are loaded",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, type: cluster, url: {value: d}, auth: {token: {name: e}}}]}]
+`,
+			expectedManifest: manifest.Manifest{
+				Projects: map[string]manifest.ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]manifest.EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						Type: manifest.ClusterEnvironmentType,
+						URL: manifest.URLDefinition{
+							Type:  manifest.ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: manifest.Auth{
+							Token: manifest.AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+						},
+					},
+				},
+				Accounts: map[string]manifest.Account{},
+			},
+		},
+		{
+			name: "Cluster environments with OAuth credentials fail",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, type: cluster, url: {value: d}, auth: {token: {name: e}, oAuth: {clientId: {name: e}, clientSecret: {name: e}}}}]}]
+`,
+			errsContain: []string{"cluster environments must be authenticated using a Cluster API token"},
+		},
+		{
+			name: "Unknown environment type fails",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, type: foo, url: {value: d}, auth: {token: {name: e}}}]}]
+`,
+			errsContain: []string{`unknown environment type "foo"`},
+		},
 		{
 			name: "Everything good with multiple environments in multiple groups",
 			manifestContent: `
@@ -1767,6 +2003,63 @@ func TestEnvVarResolutionCanBeDeactivated(t *testing.T) {
 	})
 }
 
+func TestLoadManifest_FileSecrets(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		wantToken     manifest.AuthSecret
+		wantErrorPart string
+	}{
+		{
+			name:      "secret is read from file",
+			token:     `{type: file, name: TOKEN, path: secrets/token}`,
+			wantToken: manifest.AuthSecret{Name: "TOKEN", Path: "secrets/token", Value: "file token"},
+		},
+		{
+			name:          "missing path",
+			token:         `{type: file, name: TOKEN}`,
+			wantErrorPart: "no path given or empty",
+		},
+		{
+			name:          "missing file",
+			token:         `{type: file, path: secrets/missing}`,
+			wantErrorPart: `failed to read secret file "secrets/missing"`,
+		},
+		{
+			name:          "empty file",
+			token:         `{type: file, path: secrets/empty}`,
+			wantErrorPart: `secret file "secrets/empty" found, but it is empty`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "secrets/token", []byte("file token\n"), 0400))
+			require.NoError(t, afero.WriteFile(fs, "secrets/empty", []byte("\n"), 0400))
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: `+tt.token+`}}]}]
+`), 0400))
+
+			mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+
+			if tt.wantErrorPart != "" {
+				require.Len(t, errs, 1)
+				assert.ErrorContains(t, errs[0], tt.wantErrorPart)
+				return
+			}
+			require.Empty(t, errs)
+			assert.Equal(t, tt.wantToken, mani.Environments["c"].Auth.Token)
+		})
+	}
+
+	t.Run("file is not read if 'DoNotResolveEnvVars' option is set", func(t *testing.T) {
+		_, err := parseAuthSecret(&Context{Fs: afero.NewMemMapFs(), Opts: Options{DoNotResolveEnvVars: true}}, persistence.AuthSecret{Type: persistence.TypeFile, Path: "secrets/missing"})
+		assert.NoError(t, err)
+	})
+}
+
 func TestEnvironmentsAndAccountsAreOptionalUnlessDefined(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -1822,3 +2115,364 @@ projects: [{name: a, path: p}]
 		})
 	}
 }
+
+func TestLoadManifest_AuthMethods(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+	t.Setenv("CLIENT_ID", "client-id")
+	t.Setenv("CLIENT_SECRET", "client-secret")
+
+	tests := []struct {
+		name          string
+		auth          string
+		wantToken     bool
+		wantOAuth     bool
+		wantErrorPart string
+	}{
+		{
+			name:      "token only",
+			auth:      `{token: {name: TOKEN}}`,
+			wantToken: true,
+		},
+		{
+			name:      "OAuth credentials only",
+			auth:      `{oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}}`,
+			wantOAuth: true,
+		},
+		{
+			name:      "token and OAuth credentials",
+			auth:      `{token: {name: TOKEN}, oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}}`,
+			wantToken: true,
+			wantOAuth: true,
+		},
+		{
+			name:          "neither token nor OAuth credentials",
+			auth:          `{}`,
+			wantErrorPart: "error parsing token: no name given or empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			content := `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: "https://c.dynatrace.com"}, auth: ` + tt.auth + `}]}]
+`
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(content), 0400))
+
+			mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+			if tt.wantErrorPart != "" {
+				require.Len(t, errs, 1)
+				assert.ErrorContains(t, errs[0], tt.wantErrorPart)
+				return
+			}
+			require.Empty(t, errs)
+
+			a := mani.Environments["c"].Auth
+			assert.Equal(t, tt.wantToken, a.HasToken())
+			assert.Equal(t, tt.wantOAuth, a.HasOAuth())
+		})
+	}
+}
+
+func TestLoadManifest_DependsOn(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+
+	manifestContent := func(dependsOn string) string {
+		return `
+manifestVersion: 1.0
+projects:
+  - {name: a}
+  - {name: team, type: grouping}
+  - {name: b, dependsOn: ` + dependsOn + `}
+environmentGroups: [{name: g, environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}]
+`
+	}
+
+	t.Run("dependencies on projects and grouping projects", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll("team/x", 0777))
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent("[a, team]")), 0400))
+
+		mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Empty(t, errs)
+		assert.Equal(t, []string{"a", "team"}, mani.Projects["b"].DependsOn)
+		assert.Nil(t, mani.Projects["a"].DependsOn)
+	})
+
+	t.Run("dependencies on unknown projects are rejected", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll("team/x", 0777))
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent("[a, unknown]")), 0400))
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "depends on unknown project `unknown`")
+	})
+}
+
+func TestLoadManifest_Parameters(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+
+	manifestContent := `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: prod
+    parameters:
+      alertingEmail: prod@example.com
+      owner: team-a
+    environments:
+      - name: prod-eu
+        url: {value: "https://eu.dynatrace.com"}
+        auth: {token: {name: TOKEN}}
+      - name: prod-us
+        url: {value: "https://us.dynatrace.com"}
+        auth: {token: {name: TOKEN}}
+        parameters:
+          owner: team-b
+  - name: dev
+    environments:
+      - name: dev
+        url: {value: "https://dev.dynatrace.com"}
+        auth: {token: {name: TOKEN}}
+`
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("a", 0777))
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent), 0400))
+
+	mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string]any{"alertingEmail": "prod@example.com", "owner": "team-a"}, mani.Environments["prod-eu"].Parameters)
+	assert.Equal(t, map[string]any{"alertingEmail": "prod@example.com", "owner": "team-b"}, mani.Environments["prod-us"].Parameters)
+	assert.Empty(t, mani.Environments["dev"].Parameters)
+}
+
+type gitFetcherFunc func(src gitsource.Source, dir string) error
+
+func (f gitFetcherFunc) Fetch(src gitsource.Source, dir string) error {
+	return f(src, dir)
+}
+
+func TestLoadManifest_GitProjects(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+
+	manifestContent := `
+manifestVersion: 1.0
+projects:
+  - name: remote
+    path: git::https://github.com/example/repo.git//projects/infra?ref=v1.2.0
+  - name: remote-group
+    type: grouping
+    path: git::https://github.com/example/repo.git//teams?ref=v1.2.0
+environmentGroups: [{name: g, environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}]
+`
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "monaco/manifest.yaml", []byte(manifestContent), 0400))
+
+	var fetched []gitsource.Source
+	fetcher := gitFetcherFunc(func(src gitsource.Source, dir string) error {
+		fetched = append(fetched, src)
+		for _, p := range []string{"projects/infra", "teams/a", "teams/b"} {
+			if err := fs.MkdirAll(filepath.Join(dir, p), 0777); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	mani, errs := Load(&Context{Fs: fs, ManifestPath: "monaco/manifest.yaml", GitFetcher: fetcher})
+	require.Empty(t, errs)
+
+	assert.Equal(t, []gitsource.Source{{Repository: "https://github.com/example/repo.git", Subdir: "projects/infra", Ref: "v1.2.0"}}, fetched, "repository should be fetched once")
+
+	remote := mani.Projects["remote"].Path
+	assert.True(t, strings.HasPrefix(remote, filepath.FromSlash(GitCacheDir)), "project should be loaded from the Git cache, but path is %q", remote)
+	exists, err := afero.DirExists(fs, filepath.Join("monaco", remote))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.Equal(t, filepath.Join(filepath.Dir(filepath.Dir(remote)), "teams", "a"), mani.Projects["remote-group.a"].Path)
+	assert.Equal(t, filepath.Join(filepath.Dir(filepath.Dir(remote)), "teams", "b"), mani.Projects["remote-group.b"].Path)
+
+	t.Run("fetch errors are reported", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent), 0400))
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", GitFetcher: gitFetcherFunc(func(gitsource.Source, string) error {
+			return errors.New("repository not found")
+		})})
+		require.Len(t, errs, 2)
+		assert.ErrorContains(t, errs[0], "repository not found")
+	})
+}
+
+func TestLoadManifest_HTTPSettings(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	manifestContent := func(httpSettings string) string {
+		return `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups: [{name: g, environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}, httpSettings: ` + httpSettings + `}]}]
+`
+	}
+
+	tests := []struct {
+		name         string
+		httpSettings string
+		want         *manifest.HTTPSettings
+		wantErr      string
+	}{
+		{
+			name:         "all settings",
+			httpSettings: `{proxy: "http://proxy.example.com:8080", caCertificate: ca.pem, timeout: 2m}`,
+			want: &manifest.HTTPSettings{
+				Proxy:             &url.URL{Scheme: "http", Host: "proxy.example.com:8080"},
+				CACertificatePath: "ca.pem",
+				CACertificates:    ca,
+				Timeout:           2 * time.Minute,
+			},
+		},
+		{
+			name:         "only timeout",
+			httpSettings: `{timeout: 30s}`,
+			want:         &manifest.HTTPSettings{Timeout: 30 * time.Second},
+		},
+		{
+			name:         "connection pooling",
+			httpSettings: `{maxIdleConnsPerHost: 64, maxConnsPerHost: 128, idleConnTimeout: 2m, disableTLSSessionReuse: true, disableHTTP2: true}`,
+			want: &manifest.HTTPSettings{
+				MaxIdleConnsPerHost:    64,
+				MaxConnsPerHost:        128,
+				IdleConnTimeout:        2 * time.Minute,
+				DisableTLSSessionReuse: true,
+				DisableHTTP2:           true,
+			},
+		},
+		{
+			name:         "negative max connections",
+			httpSettings: `{maxConnsPerHost: -1}`,
+			wantErr:      `maxConnsPerHost must not be negative`,
+		},
+		{
+			name:         "invalid idle connection timeout",
+			httpSettings: `{idleConnTimeout: never}`,
+			wantErr:      `idleConnTimeout "never" is not a valid duration`,
+		},
+		{
+			name:         "proxy without scheme",
+			httpSettings: `{proxy: "proxy.example.com:8080"}`,
+			wantErr:      `proxy "proxy.example.com:8080" is not a valid URL`,
+		},
+		{
+			name:         "missing CA certificate file",
+			httpSettings: `{caCertificate: missing.pem}`,
+			wantErr:      `failed to read CA certificate file "missing.pem"`,
+		},
+		{
+			name:         "CA certificate file without certificates",
+			httpSettings: `{caCertificate: invalid.pem}`,
+			wantErr:      `CA certificate file "invalid.pem" does not contain any PEM encoded certificate`,
+		},
+		{
+			name:         "invalid timeout",
+			httpSettings: `{timeout: forever}`,
+			wantErr:      `timeout "forever" is not a valid duration`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("a", 0777))
+			require.NoError(t, afero.WriteFile(fs, "ca.pem", ca, 0400))
+			require.NoError(t, afero.WriteFile(fs, "invalid.pem", []byte("not a certificate"), 0400))
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent(tt.httpSettings)), 0400))
+
+			mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+			if tt.wantErr != "" {
+				require.Len(t, errs, 1)
+				assert.ErrorContains(t, errs[0], tt.wantErr)
+				return
+			}
+
+			require.Empty(t, errs)
+			assert.Equal(t, tt.want, mani.Environments["e"].HTTPSettings)
+		})
+	}
+}
+
+func TestLoadManifest_TargetingRules(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+
+	manifestContent := func(rules string) string {
+		return `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - {name: public, environments: [{name: prod, url: {value: "https://prod.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}
+  - {name: internal, environments: [{name: dev, url: {value: "https://dev.dynatrace.com"}, auth: {token: {name: TOKEN}}}]}
+targetingRules: ` + rules + `
+`
+	}
+
+	tests := []struct {
+		name    string
+		rules   string
+		want    []manifest.TargetingRule
+		wantErr []string
+	}{
+		{
+			name:  "rules are loaded",
+			rules: `[{configTypes: [synthetic-monitor, "builtin:synthetic.*"], groups: [public]}, {configTypes: [workflow], environments: [dev]}]`,
+			want: []manifest.TargetingRule{
+				{ConfigTypes: []string{"synthetic-monitor", "builtin:synthetic.*"}, Groups: []string{"public"}},
+				{ConfigTypes: []string{"workflow"}, Environments: []string{"dev"}},
+			},
+		},
+		{
+			name:    "config types are required",
+			rules:   `[{groups: [public]}]`,
+			wantErr: []string{"targeting rule 0: 'configTypes' are required, but not defined"},
+		},
+		{
+			name:    "targets are required",
+			rules:   `[{configTypes: [workflow]}]`,
+			wantErr: []string{"targeting rule 0: neither 'groups' nor 'environments' are defined"},
+		},
+		{
+			name:    "unknown groups and environments",
+			rules:   `[{configTypes: ["[invalid"], groups: [unknown], environments: [other]}]`,
+			wantErr: []string{`invalid config type "[invalid"`, `unknown group "unknown"`, `unknown environment "other"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("a", 0777))
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent(tt.rules)), 0400))
+
+			mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+			if len(tt.wantErr) > 0 {
+				require.Len(t, errs, len(tt.wantErr))
+				for i, e := range tt.wantErr {
+					assert.ErrorContains(t, errs[i], e)
+				}
+				return
+			}
+
+			require.Empty(t, errs)
+			assert.Equal(t, tt.want, mani.TargetingRules)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+)
+
+// parseTargetingRules parses the targeting rules of the manifest. Their groups must be defined in the manifest, and so
+// must their environments - unless a group discovers its environments, as their names are not known up front.
+func parseTargetingRules(context *Context, m persistence.Manifest) ([]manifest.TargetingRule, []error) {
+	if len(m.TargetingRules) == 0 {
+		return nil, nil
+	}
+
+	groups := make(map[string]struct{})
+	environments := make(map[string]struct{})
+	discovers := false
+	for _, g := range m.EnvironmentGroups {
+		groups[g.Name] = struct{}{}
+		for _, e := range g.Environments {
+			environments[e.Name] = struct{}{}
+		}
+		discovers = discovers || g.Discover != nil
+	}
+
+	var errs []error
+	newErr := func(i int, reason string) {
+		errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("targeting rule %d: %s", i, reason)))
+	}
+
+	result := make([]manifest.TargetingRule, 0, len(m.TargetingRules))
+	for i, r := range m.TargetingRules {
+		if len(r.ConfigTypes) == 0 {
+			newErr(i, "'configTypes' are required, but not defined")
+		}
+		for _, t := range r.ConfigTypes {
+			if _, err := path.Match(t, ""); err != nil {
+				newErr(i, fmt.Sprintf("invalid config type %q: %s", t, err))
+			}
+		}
+
+		if len(r.Groups) == 0 && len(r.Environments) == 0 {
+			newErr(i, "neither 'groups' nor 'environments' are defined")
+		}
+		for _, g := range r.Groups {
+			if _, ok := groups[g]; !ok {
+				newErr(i, fmt.Sprintf("unknown group %q", g))
+			}
+		}
+		for _, e := range r.Environments {
+			if _, ok := environments[e]; !ok && !discovers {
+				newErr(i, fmt.Sprintf("unknown environment %q", e))
+			}
+		}
+
+		result = append(result, manifest.TargetingRule{
+			ConfigTypes:  slices.Clone(r.ConfigTypes),
+			Groups:       slices.Clone(r.Groups),
+			Environments: slices.Clone(r.Environments),
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
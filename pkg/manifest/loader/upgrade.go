@@ -0,0 +1,162 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	version2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
+	"gopkg.in/yaml.v2"
+	"slices"
+	"strings"
+)
+
+// migration upgrades the raw YAML of a manifest from one manifestVersion to the next.
+type migration struct {
+	from, to string
+	// migrate rewrites the given manifest in place. The manifestVersion itself is updated by [Upgrade].
+	migrate func(m yaml.MapSlice) (yaml.MapSlice, error)
+}
+
+// migrations holds all migrations between manifest versions, ordered by version. Whenever the manifest format changes,
+// [version.ManifestVersion] is increased and a migration from the previous version is added here.
+var migrations = []migration{
+	{
+		// 1.1 added variables, includes, targetingRules, per-environment httpSettings and parameters, and project
+		// dependencies - all of them optional, so no existing content needs to be changed.
+		from:    "1.0",
+		to:      "1.1",
+		migrate: func(m yaml.MapSlice) (yaml.MapSlice, error) { return m, nil },
+	},
+}
+
+// UpgradeResult describes a manifest upgraded by [Upgrade].
+type UpgradeResult struct {
+	// FromVersion is the manifestVersion of the original manifest.
+	FromVersion string
+	// ToVersion is the manifestVersion of the upgraded manifest.
+	ToVersion string
+	// Content is the upgraded manifest.
+	Content []byte
+}
+
+// Upgraded returns whether the manifest was changed by the upgrade.
+func (r UpgradeResult) Upgraded() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// Upgrade rewrites the given manifest content to the newest manifestVersion supported by this version of monaco, by
+// applying all migrations between its current version and the newest one in order. The order of keys is kept, but
+// comments are lost. If the manifest already has the newest version, its content is returned unchanged.
+func Upgrade(content []byte) (UpgradeResult, error) {
+	var m persistence.Manifest
+	if err := yaml.Unmarshal(content, &m); err != nil {
+		return UpgradeResult{}, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := validateVersion(m); err != nil {
+		return UpgradeResult{}, err
+	}
+
+	current, _ := version2.ParseVersion(m.ManifestVersion)
+	if current == maxSupportedManifestVersion {
+		return UpgradeResult{FromVersion: m.ManifestVersion, ToVersion: m.ManifestVersion, Content: content}, nil
+	}
+
+	var raw yaml.MapSlice
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return UpgradeResult{}, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	for _, mig := range migrations {
+		from, _ := version2.ParseVersion(mig.from)
+		if current.GreaterThan(from) {
+			continue
+		}
+		if current != from {
+			return UpgradeResult{}, fmt.Errorf("no migration from `manifestVersion` %s to %s exists", current, mig.from)
+		}
+
+		var err error
+		if raw, err = mig.migrate(raw); err != nil {
+			return UpgradeResult{}, fmt.Errorf("failed to upgrade manifest from version %s to %s: %w", mig.from, mig.to, err)
+		}
+		current, _ = version2.ParseVersion(mig.to)
+	}
+
+	if current != maxSupportedManifestVersion {
+		return UpgradeResult{}, errors.New("no migration to `manifestVersion` " + version.ManifestVersion + " exists")
+	}
+
+	raw = setManifestVersion(raw, version.ManifestVersion)
+	upgraded, err := yaml.Marshal(raw)
+	if err != nil {
+		return UpgradeResult{}, fmt.Errorf("failed to write upgraded manifest: %w", err)
+	}
+	return UpgradeResult{FromVersion: m.ManifestVersion, ToVersion: version.ManifestVersion, Content: upgraded}, nil
+}
+
+func setManifestVersion(m yaml.MapSlice, v string) yaml.MapSlice {
+	for i := range m {
+		if m[i].Key == "manifestVersion" {
+			m[i].Value = v
+			return m
+		}
+	}
+	return append(yaml.MapSlice{{Key: "manifestVersion", Value: v}}, m...)
+}
+
+// featuresOfVersion1_1 returns the fields of the given manifest that were introduced with manifestVersion 1.1.
+func featuresOfVersion1_1(m persistence.Manifest) []string {
+	var features []string
+	if len(m.Variables) > 0 {
+		features = append(features, "variables")
+	}
+	if len(m.Includes) > 0 {
+		features = append(features, "includes")
+	}
+	if len(m.TargetingRules) > 0 {
+		features = append(features, "targetingRules")
+	}
+	if slices.ContainsFunc(m.Projects, func(p persistence.Project) bool { return len(p.DependsOn) > 0 }) {
+		features = append(features, "dependsOn")
+	}
+	if slices.ContainsFunc(m.EnvironmentGroups, func(g persistence.Group) bool {
+		return len(g.Parameters) > 0 || slices.ContainsFunc(g.Environments, func(e persistence.Environment) bool { return len(e.Parameters) > 0 })
+	}) {
+		features = append(features, "parameters")
+	}
+	if slices.ContainsFunc(m.EnvironmentGroups, func(g persistence.Group) bool {
+		return slices.ContainsFunc(g.Environments, func(e persistence.Environment) bool { return e.HTTPSettings != nil })
+	}) {
+		features = append(features, "httpSettings")
+	}
+	return features
+}
+
+// warnAboutOutdatedVersion logs a warning if the manifest uses fields introduced after its manifestVersion.
+func warnAboutOutdatedVersion(manifestPath string, m persistence.Manifest) {
+	v, err := version2.ParseVersion(m.ManifestVersion)
+	if err != nil || !v.SmallerThan(version2.Version{Major: 1, Minor: 1}) {
+		return
+	}
+	if features := featuresOfVersion1_1(m); len(features) > 0 {
+		log.Warn("Manifest %q of `manifestVersion` %s uses %s, which were introduced with `manifestVersion` 1.1. Run 'monaco manifest upgrade %s' to upgrade it.", manifestPath, m.ManifestVersion, strings.Join(features, ", "), manifestPath)
+	}
+}
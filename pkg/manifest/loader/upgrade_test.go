@@ -0,0 +1,112 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpgrade(t *testing.T) {
+	t.Run("upgrades version 1.0 and keeps the order of fields", func(t *testing.T) {
+		result, err := Upgrade([]byte(`
+projects:
+- name: project
+manifestVersion: 1.0
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url: https://abc.live.dynatrace.com
+    auth:
+      token:
+        name: TOKEN
+`))
+		require.NoError(t, err)
+		assert.True(t, result.Upgraded())
+		assert.Equal(t, "1.0", result.FromVersion)
+		assert.Equal(t, version.ManifestVersion, result.ToVersion)
+		assert.Equal(t, fmt.Sprintf(`projects:
+- name: project
+manifestVersion: "%s"
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url: https://abc.live.dynatrace.com
+    auth:
+      token:
+        name: TOKEN
+`, version.ManifestVersion), string(result.Content))
+	})
+
+	t.Run("manifests of the newest version are returned unchanged", func(t *testing.T) {
+		content := []byte("manifestVersion: " + version.ManifestVersion + "\n# a comment\n")
+		result, err := Upgrade(content)
+		require.NoError(t, err)
+		assert.False(t, result.Upgraded())
+		assert.Equal(t, content, result.Content)
+	})
+
+	t.Run("unsupported versions are rejected", func(t *testing.T) {
+		_, err := Upgrade([]byte("manifestVersion: 0.9"))
+		assert.ErrorContains(t, err, "no longer supported")
+
+		_, err = Upgrade([]byte("manifestVersion: 99.0"))
+		assert.ErrorContains(t, err, "is not supported by monaco")
+
+		_, err = Upgrade([]byte("projects: []"))
+		assert.ErrorContains(t, err, "`manifestVersion` missing")
+	})
+
+	t.Run("invalid YAML is rejected", func(t *testing.T) {
+		_, err := Upgrade([]byte("manifestVersion: [1.0"))
+		assert.ErrorContains(t, err, "failed to parse manifest")
+	})
+}
+
+func TestMigrationsCoverAllSupportedVersions(t *testing.T) {
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, version.MinManifestVersion, migrations[0].from)
+	assert.Equal(t, version.ManifestVersion, migrations[len(migrations)-1].to)
+	for i := 1; i < len(migrations); i++ {
+		assert.Equal(t, migrations[i-1].to, migrations[i].from, "migrations must form a chain")
+	}
+}
+
+func Test_featuresOfVersion1_1(t *testing.T) {
+	assert.Empty(t, featuresOfVersion1_1(persistence.Manifest{
+		Projects:          []persistence.Project{{Name: "p"}},
+		EnvironmentGroups: []persistence.Group{{Name: "g", Environments: []persistence.Environment{{Name: "e"}}}},
+	}))
+
+	assert.Equal(t, []string{"variables", "includes", "targetingRules", "dependsOn", "parameters", "httpSettings"}, featuresOfVersion1_1(persistence.Manifest{
+		Variables:      map[string]persistence.TypedValue{"v": {Value: "x"}},
+		Includes:       []string{"other.yaml"},
+		TargetingRules: []persistence.TargetingRule{{ConfigTypes: []string{"*"}}},
+		Projects:       []persistence.Project{{Name: "p", DependsOn: []string{"q"}}},
+		EnvironmentGroups: []persistence.Group{{Name: "g", Environments: []persistence.Environment{
+			{Name: "e", Parameters: map[string]any{"a": "b"}, HTTPSettings: &persistence.HTTPSettings{Timeout: "1m"}},
+		}}},
+	}))
+}
@@ -0,0 +1,187 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"github.com/spf13/afero"
+	"golang.org/x/exp/maps"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Validate loads the manifest of the given context without resolving environment variables and checks it beyond what is
+// required to load it: the folders of all projects must exist, all URLs must be valid, and every group must define or
+// discover environments.
+// If documentedEnvVars is not nil, every environment variable referenced by the manifest must be part of it.
+//
+// Returns the sorted names of all environment variables referenced by the manifest, and all found problems.
+func Validate(c *Context, documentedEnvVars []string) ([]string, []error) {
+	validationContext := *c
+	validationContext.Opts.DoNotResolveEnvVars = true
+
+	m, errs := Load(&validationContext)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	manifestYAML, err := readManifestYAML(&validationContext)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	errs = append(errs, validateProjectPaths(&validationContext, m.Projects)...)
+	errs = append(errs, validateURLs(&validationContext, m)...)
+	errs = append(errs, validateGroups(&validationContext, manifestYAML.EnvironmentGroups)...)
+
+	envVars := referencedEnvVars(manifestYAML)
+	if documentedEnvVars != nil {
+		for _, v := range envVars {
+			if !slices.Contains(documentedEnvVars, v) {
+				errs = append(errs, newManifestLoaderError(c.ManifestPath, fmt.Sprintf("environment variable %q is referenced, but not documented", v)))
+			}
+		}
+	}
+
+	return envVars, errs
+}
+
+func validateProjectPaths(c *Context, projects manifest.ProjectDefinitionByProjectID) []error {
+	workingDir := filepath.Dir(filepath.Clean(c.ManifestPath))
+
+	var errs []error
+	for _, name := range sortedKeys(projects) {
+		p := projects[name]
+		if exists, err := afero.DirExists(c.Fs, filepath.Join(workingDir, p.Path)); err != nil || !exists {
+			errs = append(errs, newManifestProjectLoaderError(c.ManifestPath, p.Name, fmt.Sprintf("project folder %q does not exist", p.Path)))
+		}
+	}
+	return errs
+}
+
+func validateURLs(c *Context, m manifest.Manifest) []error {
+	var errs []error
+	check := func(location string, u *manifest.URLDefinition) {
+		// URLs read from environment variables, or referencing variables that are read from them, can't be validated
+		if u == nil || u.Type != manifest.ValueURLType || strings.Contains(u.Value, "{{") {
+			return
+		}
+		if err := validateURL(u.Value); err != nil {
+			errs = append(errs, newManifestLoaderError(c.ManifestPath, fmt.Sprintf("invalid URL %q of %s: %s", u.Value, location, err)))
+		}
+	}
+
+	for _, name := range sortedKeys(m.Environments) {
+		env := m.Environments[name]
+		check(fmt.Sprintf("environment %q", name), &env.URL)
+		if env.Auth.OAuth != nil {
+			check(fmt.Sprintf("the token endpoint of environment %q", name), env.Auth.OAuth.TokenEndpoint)
+		}
+	}
+
+	for _, name := range sortedKeys(m.Accounts) {
+		acc := m.Accounts[name]
+		check(fmt.Sprintf("account %q", name), acc.ApiUrl)
+		check(fmt.Sprintf("the token endpoint of account %q", name), acc.OAuth.TokenEndpoint)
+	}
+
+	return errs
+}
+
+func validateURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.New("scheme must be 'http' or 'https'")
+	}
+	if parsed.Host == "" {
+		return errors.New("no host given")
+	}
+	return nil
+}
+
+func validateGroups(c *Context, groups []persistence.Group) []error {
+	var errs []error
+	for _, g := range groups {
+		if len(g.Environments) == 0 && g.Discover == nil {
+			errs = append(errs, newManifestLoaderError(c.ManifestPath, fmt.Sprintf("group %q neither defines nor discovers environments", g.Name)))
+		}
+	}
+	return errs
+}
+
+// referencedEnvVars returns the sorted names of all environment variables referenced by the manifest
+func referencedEnvVars(m persistence.Manifest) []string {
+	vars := make(map[string]struct{})
+
+	addValue := func(v *persistence.TypedValue) {
+		if v != nil && v.Type == persistence.TypeEnvironment && v.Value != "" {
+			vars[v.Value] = struct{}{}
+		}
+	}
+	addSecret := func(s persistence.AuthSecret) {
+		if s.Type != persistence.TypeFile && s.Name != "" {
+			vars[s.Name] = struct{}{}
+		}
+	}
+	addOAuth := func(o *persistence.OAuth) {
+		if o != nil {
+			addSecret(o.ClientID)
+			addSecret(o.ClientSecret)
+			addValue(o.TokenEndpoint)
+		}
+	}
+	addAuth := func(a persistence.Auth) {
+		addSecret(a.Token)
+		addOAuth(a.OAuth)
+	}
+
+	for _, v := range m.Variables {
+		addValue(&v)
+	}
+
+	for _, g := range m.EnvironmentGroups {
+		for _, env := range g.Environments {
+			addValue(&env.URL)
+			addAuth(env.Auth)
+		}
+		if g.Discover != nil {
+			addAuth(g.Discover.Auth)
+		}
+	}
+
+	for _, acc := range m.Accounts {
+		addValue(&acc.AccountUUID)
+		addValue(acc.ApiUrl)
+		addOAuth(&acc.OAuth)
+	}
+
+	return sortedKeys(vars)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	const validManifest = `
+manifestVersion: 1.0
+variables:
+  tenant: {type: environment, value: TENANT}
+projects: [{name: a}]
+accounts:
+  - name: acc
+    accountUUID: 4fa7a9ce-8b1a-4a57-9a1c-0a2e4a1d3c5e
+    oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {type: file, path: secret.txt}}
+environmentGroups:
+  - name: g
+    environments:
+      - {name: e1, url: {value: "https://{{ .tenant }}.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+      - {name: e2, url: {type: environment, value: E2_URL}, auth: {token: {name: TOKEN}, oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}}}
+`
+
+	tests := []struct {
+		name              string
+		manifest          string
+		createProject     bool
+		documentedEnvVars []string
+		wantEnvVars       []string
+		wantErrors        []string
+	}{
+		{
+			name:          "valid manifest",
+			manifest:      validManifest,
+			createProject: true,
+			wantEnvVars:   []string{"CLIENT_ID", "CLIENT_SECRET", "E2_URL", "TENANT", "TOKEN"},
+		},
+		{
+			name:              "all environment variables are documented",
+			manifest:          validManifest,
+			createProject:     true,
+			documentedEnvVars: []string{"CLIENT_ID", "CLIENT_SECRET", "E2_URL", "TENANT", "TOKEN", "UNUSED"},
+			wantEnvVars:       []string{"CLIENT_ID", "CLIENT_SECRET", "E2_URL", "TENANT", "TOKEN"},
+		},
+		{
+			name:              "undocumented environment variables",
+			manifest:          validManifest,
+			createProject:     true,
+			documentedEnvVars: []string{"CLIENT_ID", "TENANT", "TOKEN"},
+			wantEnvVars:       []string{"CLIENT_ID", "CLIENT_SECRET", "E2_URL", "TENANT", "TOKEN"},
+			wantErrors: []string{
+				`environment variable "CLIENT_SECRET" is referenced, but not documented`,
+				`environment variable "E2_URL" is referenced, but not documented`,
+			},
+		},
+		{
+			name:       "missing project folder",
+			manifest:   validManifest,
+			wantErrors: []string{`project folder "a" does not exist`},
+		},
+		{
+			name: "invalid URLs",
+			manifest: `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: g
+    environments:
+      - {name: e1, url: {value: "abc.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+      - {name: e2, url: {value: "https:///path"}, auth: {token: {name: TOKEN}, oAuth: {clientId: {name: ID}, clientSecret: {name: SECRET}, tokenEndpoint: "ftp://sso"}}}
+`,
+			createProject: true,
+			wantErrors: []string{
+				`invalid URL "abc.live.dynatrace.com" of environment "e1": scheme must be 'http' or 'https'`,
+				`invalid URL "https:///path" of environment "e2": no host given`,
+				`invalid URL "ftp://sso" of the token endpoint of environment "e2": scheme must be 'http' or 'https'`,
+			},
+		},
+		{
+			name: "group without environments",
+			manifest: `
+manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - name: g
+    environments: [{name: e, url: {value: "https://e.dynatrace.com"}, auth: {token: {name: TOKEN}}}]
+  - name: empty
+`,
+			createProject: true,
+			wantErrors:    []string{`group "empty" neither defines nor discovers environments`},
+		},
+		{
+			name:       "loading errors are returned",
+			manifest:   `projects: [{name: a}]`,
+			wantErrors: []string{"`manifestVersion` missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(tt.manifest), 0400))
+			if tt.createProject {
+				require.NoError(t, fs.MkdirAll("a", 0777))
+			}
+
+			envVars, errs := Validate(&Context{Fs: fs, ManifestPath: "manifest.yaml"}, tt.documentedEnvVars)
+
+			require.Len(t, errs, len(tt.wantErrors), "got errors: %v", errs)
+			for i, want := range tt.wantErrors {
+				assert.ErrorContains(t, errs[i], want)
+			}
+			if len(tt.wantErrors) == 0 {
+				assert.Equal(t, tt.wantEnvVars, envVars)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+var variableNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// parseVariables resolves the variables defined in the manifest. The variables given in the context override the
+// defined values, but every overridden variable must be defined in the manifest.
+func parseVariables(c *Context, definitions map[string]persistence.TypedValue) (map[string]manifest.Variable, error) {
+	var errs []error
+	result := make(map[string]manifest.Variable, len(definitions))
+
+	for name, d := range definitions {
+		if !variableNameRegex.MatchString(name) {
+			errs = append(errs, fmt.Errorf("invalid variable name %q: names must start with a letter or underscore and only contain letters, digits and underscores", name))
+			continue
+		}
+
+		v, err := parseVariable(c, name, d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("variable %q: %w", name, err))
+			continue
+		}
+		result[name] = v
+	}
+
+	for name, value := range c.Variables {
+		if _, defined := definitions[name]; !defined {
+			errs = append(errs, fmt.Errorf("variable %q is overridden, but not defined in the manifest", name))
+			continue
+		}
+		result[name] = manifest.Variable{Value: value}
+	}
+
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+		return nil, errors.Join(errs...)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func parseVariable(c *Context, name string, d persistence.TypedValue) (manifest.Variable, error) {
+	switch d.Type {
+	case "", persistence.TypeValue:
+		return manifest.Variable{Value: d.Value}, nil
+
+	case persistence.TypeEnvironment:
+		if d.Value == "" {
+			return manifest.Variable{}, errors.New("no environment variable name given")
+		}
+		if c.Opts.DoNotResolveEnvVars {
+			log.Debug("Skipped resolving environment variable %s based on loader options", d.Value)
+			// keep references to the variable as they are
+			return manifest.Variable{EnvironmentVariable: d.Value, Value: "{{ ." + name + " }}"}, nil
+		}
+		val, found := os.LookupEnv(d.Value)
+		if !found {
+			return manifest.Variable{}, fmt.Errorf("environment variable %q could not be found", d.Value)
+		}
+		return manifest.Variable{EnvironmentVariable: d.Value, Value: val}, nil
+	}
+
+	return manifest.Variable{}, fmt.Errorf("unexpected type %q (expected one of %q, %q)", d.Type, persistence.TypeValue, persistence.TypeEnvironment)
+}
+
+// interpolateVariables replaces references to variables in the URLs, group names and project paths of the manifest
+func interpolateVariables(m persistence.Manifest, variables map[string]manifest.Variable) (persistence.Manifest, error) {
+	values := make(map[string]string, len(variables))
+	for name, v := range variables {
+		values[name] = v.Value
+	}
+
+	var errs []error
+	interpolate := func(location string, s *string) {
+		if !strings.Contains(*s, "{{") {
+			return
+		}
+		result, err := interpolateString(*s, values)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", location, err))
+			return
+		}
+		*s = result
+	}
+
+	m.Projects = slices.Clone(m.Projects)
+	for i := range m.Projects {
+		interpolate(fmt.Sprintf("path of project %q", m.Projects[i].Name), &m.Projects[i].Path)
+	}
+
+	m.EnvironmentGroups = slices.Clone(m.EnvironmentGroups)
+	for i := range m.EnvironmentGroups {
+		g := &m.EnvironmentGroups[i]
+		interpolate(fmt.Sprintf("name of group %q", g.Name), &g.Name)
+
+		g.Environments = slices.Clone(g.Environments)
+		for j := range g.Environments {
+			interpolate(fmt.Sprintf("url of environment %q", g.Environments[j].Name), &g.Environments[j].URL.Value)
+		}
+	}
+
+	m.TargetingRules = slices.Clone(m.TargetingRules)
+	for i := range m.TargetingRules {
+		r := &m.TargetingRules[i]
+		r.Groups = slices.Clone(r.Groups)
+		for j := range r.Groups {
+			interpolate(fmt.Sprintf("group of targeting rule %d", i), &r.Groups[j])
+		}
+	}
+
+	m.Accounts = slices.Clone(m.Accounts)
+	for i := range m.Accounts {
+		if m.Accounts[i].ApiUrl != nil {
+			apiUrl := *m.Accounts[i].ApiUrl
+			interpolate(fmt.Sprintf("apiUrl of account %q", m.Accounts[i].Name), &apiUrl.Value)
+			m.Accounts[i].ApiUrl = &apiUrl
+		}
+	}
+
+	return m, errors.Join(errs...)
+}
+
+func interpolateString(s string, values map[string]string) (string, error) {
+	t, err := template.New("").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid variable reference in %q: %w", s, err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, values); err != nil {
+		return "", fmt.Errorf("failed to resolve variables in %q: %w", s, err)
+	}
+	return b.String(), nil
+}
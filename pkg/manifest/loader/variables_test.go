@@ -0,0 +1,147 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLoadManifest_Variables(t *testing.T) {
+	t.Setenv("TOKEN", "token")
+	t.Setenv("TENANT_VAR", "from-env")
+
+	manifestContent := func(variables string) string {
+		return `
+manifestVersion: 1.0
+variables:
+` + variables + `
+projects: [{name: a, path: "projects/{{ .stage }}"}]
+environmentGroups:
+  - name: "group-{{ .stage }}"
+    environments: [{name: env, url: {value: "https://{{ .tenant }}.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}]
+`
+	}
+
+	tests := []struct {
+		name          string
+		variables     string
+		overrides     map[string]string
+		opts          Options
+		wantURL       string
+		wantGroup     string
+		wantPath      string
+		wantVariables map[string]manifest.Variable
+		wantErrorPart string
+	}{
+		{
+			name:      "variables are interpolated",
+			variables: `  tenant: abc123` + "\n" + `  stage: dev`,
+			wantURL:   "https://abc123.live.dynatrace.com",
+			wantGroup: "group-dev",
+			wantPath:  "projects/dev",
+			wantVariables: map[string]manifest.Variable{
+				"tenant": {Value: "abc123"},
+				"stage":  {Value: "dev"},
+			},
+		},
+		{
+			name:      "variables are read from environment variables",
+			variables: `  tenant: {type: environment, value: TENANT_VAR}` + "\n" + `  stage: {type: value, value: dev}`,
+			wantURL:   "https://from-env.live.dynatrace.com",
+			wantGroup: "group-dev",
+			wantPath:  "projects/dev",
+			wantVariables: map[string]manifest.Variable{
+				"tenant": {EnvironmentVariable: "TENANT_VAR", Value: "from-env"},
+				"stage":  {Value: "dev"},
+			},
+		},
+		{
+			name:      "overrides take precedence",
+			variables: `  tenant: {type: environment, value: TENANT_VAR}` + "\n" + `  stage: dev`,
+			overrides: map[string]string{"tenant": "xyz", "stage": "prod"},
+			wantURL:   "https://xyz.live.dynatrace.com",
+			wantGroup: "group-prod",
+			wantPath:  "projects/prod",
+			wantVariables: map[string]manifest.Variable{
+				"tenant": {Value: "xyz"},
+				"stage":  {Value: "prod"},
+			},
+		},
+		{
+			name:      "environment variables are kept as references if not resolved",
+			variables: `  tenant: {type: environment, value: TENANT_VAR}` + "\n" + `  stage: dev`,
+			opts:      Options{DoNotResolveEnvVars: true},
+			wantURL:   "https://{{ .tenant }}.live.dynatrace.com",
+			wantGroup: "group-dev",
+			wantPath:  "projects/dev",
+			wantVariables: map[string]manifest.Variable{
+				"tenant": {EnvironmentVariable: "TENANT_VAR", Value: "{{ .tenant }}"},
+				"stage":  {Value: "dev"},
+			},
+		},
+		{
+			name:          "overriding an undefined variable fails",
+			variables:     `  tenant: abc123` + "\n" + `  stage: dev`,
+			overrides:     map[string]string{"unknown": "value"},
+			wantErrorPart: `variable "unknown" is overridden, but not defined in the manifest`,
+		},
+		{
+			name:          "referencing an undefined variable fails",
+			variables:     `  tenant: abc123`,
+			wantErrorPart: `path of project "a": failed to resolve variables in "projects/{{ .stage }}"`,
+		},
+		{
+			name:          "missing environment variable fails",
+			variables:     `  tenant: {type: environment, value: UNDEFINED_TENANT_VAR}` + "\n" + `  stage: dev`,
+			wantErrorPart: `variable "tenant": environment variable "UNDEFINED_TENANT_VAR" could not be found`,
+		},
+		{
+			name:          "invalid variable name fails",
+			variables:     `  tenant: abc123` + "\n" + `  stage: dev` + "\n" + `  "in-valid": x`,
+			wantErrorPart: `invalid variable name "in-valid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestContent(tt.variables)), 0400))
+
+			mani, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", Variables: tt.overrides, Opts: tt.opts})
+
+			if tt.wantErrorPart != "" {
+				require.NotEmpty(t, errs)
+				assert.ErrorContains(t, errs[0], tt.wantErrorPart)
+				return
+			}
+			require.Empty(t, errs)
+
+			env, found := mani.Environments["env"]
+			require.True(t, found)
+			assert.Equal(t, tt.wantURL, env.URL.Value)
+			assert.Equal(t, tt.wantGroup, env.Group)
+			assert.Equal(t, tt.wantPath, mani.Projects["a"].Path)
+			assert.Equal(t, tt.wantVariables, mani.Variables)
+		})
+	}
+}
@@ -23,12 +23,64 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/oauth2/endpoints"
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"net/url"
+	"path"
+	"slices"
+	"time"
 )
 
 type ProjectDefinition struct {
 	Name  string
 	Group string
 	Path  string
+
+	// Hooks are executed before and after the project is deployed
+	Hooks []Hook
+
+	// DependsOn are the names of the projects - or grouping projects - whose configurations this project may reference.
+	// If it is nil, the project may reference configurations of any project.
+	DependsOn []string
+}
+
+// HookStage defines when a Hook is executed
+type HookStage string
+
+const (
+	// HookStagePre hooks are executed before a project is deployed
+	HookStagePre HookStage = "pre"
+	// HookStagePost hooks are executed after a project was deployed, regardless of whether the deployment succeeded
+	HookStagePost HookStage = "post"
+)
+
+// HookFailureMode defines how a failing Hook is handled
+type HookFailureMode string
+
+const (
+	// HookFailureAbort makes a failing hook fail the deployment. Failing pre-deployment hooks prevent the deployment.
+	HookFailureAbort HookFailureMode = "abort"
+	// HookFailureWarn only logs a warning if the hook fails
+	HookFailureWarn HookFailureMode = "warn"
+)
+
+// Hook is a shell command or HTTP call executed before or after deploying a project. Exactly one of Command and URL is set.
+type Hook struct {
+	Stage HookStage
+	// Command is a shell command to execute
+	Command string
+	// URL is called with a POST request describing the deployment
+	URL string
+	// ConfigType restricts the hook to the deployment of configurations of this type. Instead of once around the whole
+	// deployment, the hook is executed before and after deploying to each environment that configurations of this
+	// type are deployed to. If empty, the hook is executed for the project.
+	ConfigType string
+	OnFailure  HookFailureMode
+}
+
+func (h Hook) String() string {
+	if h.Command != "" {
+		return fmt.Sprintf("%s-deployment command %q", h.Stage, h.Command)
+	}
+	return fmt.Sprintf("%s-deployment call to %q", h.Stage, h.URL)
 }
 
 func (p ProjectDefinition) String() string {
@@ -52,17 +104,109 @@ func (o OAuth) GetTokenEndpointValue() string {
 	return o.TokenEndpoint.Value
 }
 
+// Auth holds the credentials of an environment. An environment may define an access token, OAuth client credentials or
+// both of them - the access token is used for the classic Config APIs, the OAuth credentials for the Dynatrace Platform APIs.
 type Auth struct {
 	Token AuthSecret
 	OAuth *OAuth
 }
 
+// HasToken returns whether an access token is defined. It is required for calls to the classic Config APIs.
+func (a Auth) HasToken() bool {
+	return a.Token != (AuthSecret{})
+}
+
+// HasOAuth returns whether OAuth client credentials are defined. They are required for calls to the Dynatrace Platform APIs.
+func (a Auth) HasOAuth() bool {
+	return a.OAuth != nil
+}
+
+// EnvironmentType defines which kind of Dynatrace API an environment is
+type EnvironmentType string
+
+const (
+	// TenantEnvironmentType is a Dynatrace environment (tenant), it is the default type
+	TenantEnvironmentType EnvironmentType = "environment"
+	// ClusterEnvironmentType is a Dynatrace Managed cluster, whose cluster-wide configurations are managed using its Cluster API
+	ClusterEnvironmentType EnvironmentType = "cluster"
+)
+
 // EnvironmentDefinition holds all information about a Dynatrace environment
 type EnvironmentDefinition struct {
 	Name  string
 	Group string
 	URL   URLDefinition
 	Auth  Auth
+
+	// Type is the kind of API the environment is. If empty, it is a TenantEnvironmentType.
+	Type EnvironmentType
+
+	// RetryPolicy optionally overrides how failed API calls to the environment are retried
+	RetryPolicy *RetryPolicy
+
+	// HTTPSettings optionally customize the HTTP connections to the environment
+	HTTPSettings *HTTPSettings
+
+	// ProtectedTypes are patterns of config types (see [path.Match]) that must not be deployed to the environment,
+	// unless deploying protected types is explicitly allowed
+	ProtectedTypes []string
+
+	// Parameters are the values of environment parameters, which configurations use with parameters of type
+	// 'environmentParameter'. They hold the defaults of the environment's group, overridden by the environment's own values.
+	Parameters map[string]any
+}
+
+// IsCluster returns whether the environment is a Managed cluster, which only supports configs of cluster-wide settings
+func (e EnvironmentDefinition) IsCluster() bool {
+	return e.Type == ClusterEnvironmentType
+}
+
+// IsProtectedType returns whether the given config type matches one of the environment's ProtectedTypes
+func (e EnvironmentDefinition) IsProtectedType(configType string) bool {
+	for _, p := range e.ProtectedTypes {
+		if ok, _ := path.Match(p, configType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryPolicy defines how failed API calls to an environment are retried. Fields left at their zero value keep the
+// default retry behavior.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries of a single API call. Zero disables retries, nil keeps the default.
+	MaxRetries *int
+	// WaitTime is the time to wait before the first retry
+	WaitTime time.Duration
+	// BackoffFactor is multiplied with the wait time after every retry
+	BackoffFactor float64
+	// MaxWaitTime is the maximum time to wait between two retries
+	MaxWaitTime time.Duration
+	// RetriableStatusCodes are the HTTP status codes API calls are retried for. If empty, all failed calls are retried.
+	RetriableStatusCodes []int
+}
+
+// HTTPSettings customize the HTTP connections to an environment, e.g. for Managed environments only reachable through a
+// proxy. Fields left at their zero value keep the default behavior.
+type HTTPSettings struct {
+	// Proxy is the URL of the proxy all requests to the environment are sent through
+	Proxy *url.URL
+	// CACertificatePath is the path of the PEM file CACertificates are read from
+	CACertificatePath string
+	// CACertificates are PEM encoded certificates of CAs that are trusted in addition to the system's CAs
+	CACertificates []byte
+	// Timeout limits the duration of a single request
+	Timeout time.Duration
+	// MaxIdleConnsPerHost is the number of idle connections kept open for reuse
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the number of connections to the environment
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long idle connections are kept open
+	IdleConnTimeout time.Duration
+	// DisableTLSSessionReuse disables resuming TLS sessions when opening new connections
+	DisableTLSSessionReuse bool
+	// DisableHTTP2 disables HTTP/2, so that all requests are sent using HTTP/1.1
+	DisableHTTP2 bool
 }
 
 // URLType describes from where the url is loaded.
@@ -97,6 +241,9 @@ type AuthSecret struct {
 	// where the value is not resolved, but the env-name has to be kept.
 	Name string
 
+	// Path is the file the secret is read from. If set, the secret is read from that file instead of the environment-variable [Name].
+	Path string
+
 	// Value holds the actual token value for the given [Name]. It is empty when converting vom monaco-v1 to monaco-v2
 	Value secret.MaskedString
 }
@@ -137,4 +284,59 @@ type Manifest struct {
 
 	// Accounts holds all accounts defined in the manifest. Key is the user-defined account name.
 	Accounts map[string]Account
+
+	// Variables defined in the manifest, by name. They are already interpolated into the manifest's URLs, group names
+	// and project paths when it is loaded.
+	Variables map[string]Variable
+
+	// TargetingRules restrict the environments configurations of certain types are deployed to
+	TargetingRules []TargetingRule
+}
+
+// TargetingRule restricts the environments configurations of certain types are deployed to, e.g. to only deploy
+// synthetic monitors to the environments of a 'public' group.
+type TargetingRule struct {
+	// ConfigTypes are patterns of config types (see [path.Match]) the rule applies to
+	ConfigTypes []string
+
+	// Groups and Environments are the names of the environment groups and environments configurations of matching types
+	// are deployed to. Such configurations are skipped for all other environments.
+	Groups       []string
+	Environments []string
+}
+
+// AppliesTo returns whether the config type matches one of the rule's ConfigTypes
+func (r TargetingRule) AppliesTo(configType string) bool {
+	for _, p := range r.ConfigTypes {
+		if ok, _ := path.Match(p, configType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Targets returns whether the environment is one of the rule's Environments, or part of one of its Groups
+func (r TargetingRule) Targets(env EnvironmentDefinition) bool {
+	return slices.Contains(r.Groups, env.Group) || slices.Contains(r.Environments, env.Name)
+}
+
+// IsTargeted returns whether configurations of the given type are deployed to env, which is the case if every
+// targeting rule applying to the type targets env.
+func (m Manifest) IsTargeted(configType string, env EnvironmentDefinition) bool {
+	for _, r := range m.TargetingRules {
+		if r.AppliesTo(configType) && !r.Targets(env) {
+			return false
+		}
+	}
+	return true
+}
+
+// Variable is a value defined in the manifest that can be referenced in URLs, group names and project paths -
+// e.g. 'https://{{ .tenant }}.live.dynatrace.com'.
+type Variable struct {
+	// EnvironmentVariable is the name of the environment variable the value is read from, if it is not defined directly
+	EnvironmentVariable string
+
+	// Value is the resolved value of the variable, including overrides given when loading the manifest
+	Value string
 }
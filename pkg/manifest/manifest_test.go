@@ -55,6 +55,15 @@ func TestDefaultTokenEndpoint(t *testing.T) {
 	})
 }
 
+func TestEnvironmentDefinition_IsProtectedType(t *testing.T) {
+	env := manifest.EnvironmentDefinition{ProtectedTypes: []string{"auto-tag", "builtin:tags.*"}}
+
+	assert.True(t, env.IsProtectedType("auto-tag"))
+	assert.True(t, env.IsProtectedType("builtin:tags.auto-tagging"))
+	assert.False(t, env.IsProtectedType("management-zone"))
+	assert.False(t, manifest.EnvironmentDefinition{}.IsProtectedType("auto-tag"))
+}
+
 func TestManifestLoading(t *testing.T) {
 	fs := afero.NewCopyOnWriteFs(afero.NewOsFs(), afero.NewMemMapFs())
 	fs.Mkdir("./testdata/grouping", 0644)
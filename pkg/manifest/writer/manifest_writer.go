@@ -78,6 +78,8 @@ func Write(context *Context, manifestToWrite manifest.Manifest) error {
 		ManifestVersion:   version.ManifestVersion,
 		Projects:          projects,
 		EnvironmentGroups: groups,
+		Variables:         toWriteableVariables(manifestToWrite.Variables),
+		TargetingRules:    toWriteableTargetingRules(manifestToWrite.TargetingRules),
 	}
 
 	if featureflags.AccountManagement().Enabled() {
@@ -110,14 +112,16 @@ func toWriteableProjects(projects map[string]manifest.ProjectDefinition) (result
 			groupName, groupPath := extractGroupedProjectDetails(projectDefinition)
 
 			groups[groupName] = persistence.Project{
-				Name: groupName,
-				Path: groupPath,
-				Type: persistence.GroupProjectType,
+				Name:      groupName,
+				Path:      groupPath,
+				Type:      persistence.GroupProjectType,
+				Hooks:     toWriteableHooks(projectDefinition.Hooks),
+				DependsOn: projectDefinition.DependsOn,
 			}
 			continue
 		}
 
-		p := persistence.Project{Name: projectDefinition.Name}
+		p := persistence.Project{Name: projectDefinition.Name, Hooks: toWriteableHooks(projectDefinition.Hooks), DependsOn: projectDefinition.DependsOn}
 
 		if projectDefinition.Name != projectDefinition.Path {
 			p.Path = projectDefinition.Path
@@ -133,6 +137,31 @@ func toWriteableProjects(projects map[string]manifest.ProjectDefinition) (result
 	return result
 }
 
+func toWriteableHooks(hooks []manifest.Hook) *persistence.Hooks {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	var result persistence.Hooks
+	for _, h := range hooks {
+		p := persistence.Hook{
+			Command:    h.Command,
+			URL:        h.URL,
+			ConfigType: h.ConfigType,
+		}
+		if h.OnFailure == manifest.HookFailureWarn {
+			p.OnFailure = persistence.HookFailureWarn
+		}
+
+		if h.Stage == manifest.HookStagePost {
+			result.Post = append(result.Post, p)
+		} else {
+			result.Pre = append(result.Pre, p)
+		}
+	}
+	return &result
+}
+
 func isGroupingProject(projectDefinition manifest.ProjectDefinition) bool {
 	return strings.Contains(projectDefinition.Name, ".") &&
 		strings.ReplaceAll(projectDefinition.Name, ".", "/") == projectDefinition.Path
@@ -152,9 +181,14 @@ func toWriteableEnvironmentGroups(environments map[string]manifest.EnvironmentDe
 
 	for name, env := range environments {
 		e := persistence.Environment{
-			Name: name,
-			URL:  toWriteableURL(env.URL),
-			Auth: getAuth(env),
+			Name:           name,
+			URL:            toWriteableURL(env.URL),
+			Type:           string(env.Type),
+			Auth:           getAuth(env),
+			RetryPolicy:    toWriteableRetryPolicy(env.RetryPolicy),
+			HTTPSettings:   toWriteableHTTPSettings(env.HTTPSettings),
+			ProtectedTypes: env.ProtectedTypes,
+			Parameters:     env.Parameters,
 		}
 
 		environmentPerGroup[env.Group] = append(environmentPerGroup[env.Group], e)
@@ -168,10 +202,73 @@ func toWriteableEnvironmentGroups(environments map[string]manifest.EnvironmentDe
 }
 
 func getAuth(env manifest.EnvironmentDefinition) persistence.Auth {
-	return persistence.Auth{
-		Token: getTokenSecret(env.Auth, env.Name),
+	a := persistence.Auth{
 		OAuth: getOAuthCredentials(env.Auth.OAuth),
 	}
+	// environments authenticating with OAuth credentials only don't define a token
+	if env.Auth.HasToken() || !env.Auth.HasOAuth() {
+		a.Token = getTokenSecret(env.Auth, env.Name)
+	}
+	return a
+}
+
+func toWriteableTargetingRules(rules []manifest.TargetingRule) []persistence.TargetingRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	result := make([]persistence.TargetingRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, persistence.TargetingRule{
+			ConfigTypes:  r.ConfigTypes,
+			Groups:       r.Groups,
+			Environments: r.Environments,
+		})
+	}
+	return result
+}
+
+func toWriteableHTTPSettings(s *manifest.HTTPSettings) *persistence.HTTPSettings {
+	if s == nil {
+		return nil
+	}
+
+	r := persistence.HTTPSettings{
+		CACertificate:          s.CACertificatePath,
+		MaxIdleConnsPerHost:    s.MaxIdleConnsPerHost,
+		MaxConnsPerHost:        s.MaxConnsPerHost,
+		DisableTLSSessionReuse: s.DisableTLSSessionReuse,
+		DisableHTTP2:           s.DisableHTTP2,
+	}
+	if s.Proxy != nil {
+		r.Proxy = s.Proxy.String()
+	}
+	if s.Timeout != 0 {
+		r.Timeout = s.Timeout.String()
+	}
+	if s.IdleConnTimeout != 0 {
+		r.IdleConnTimeout = s.IdleConnTimeout.String()
+	}
+	return &r
+}
+
+func toWriteableRetryPolicy(p *manifest.RetryPolicy) *persistence.RetryPolicy {
+	if p == nil {
+		return nil
+	}
+
+	r := persistence.RetryPolicy{
+		MaxRetries:           p.MaxRetries,
+		BackoffFactor:        p.BackoffFactor,
+		RetriableStatusCodes: p.RetriableStatusCodes,
+	}
+	if p.WaitTime > 0 {
+		r.WaitTime = p.WaitTime.String()
+	}
+	if p.MaxWaitTime > 0 {
+		r.MaxWaitTime = p.MaxWaitTime.String()
+	}
+	return &r
 }
 
 func toWriteableURL(url manifest.URLDefinition) persistence.TypedValue {
@@ -187,6 +284,27 @@ func toWriteableURL(url manifest.URLDefinition) persistence.TypedValue {
 	}
 }
 
+func toWriteableVariables(variables map[string]manifest.Variable) map[string]persistence.TypedValue {
+	if len(variables) == 0 {
+		return nil
+	}
+
+	result := make(map[string]persistence.TypedValue, len(variables))
+	for name, v := range variables {
+		if v.EnvironmentVariable != "" {
+			result[name] = persistence.TypedValue{
+				Type:  persistence.TypeEnvironment,
+				Value: v.EnvironmentVariable,
+			}
+			continue
+		}
+		result[name] = persistence.TypedValue{
+			Value: v.Value,
+		}
+	}
+	return result
+}
+
 // getTokenSecret returns the tokenConfig with some legacy magic string append that still might be used (?)
 func getTokenSecret(a manifest.Auth, envName string) persistence.AuthSecret {
 	var envVarName string
@@ -196,9 +314,22 @@ func getTokenSecret(a manifest.Auth, envName string) persistence.AuthSecret {
 		envVarName = envName + "_TOKEN"
 	}
 
+	return toWriteableSecret(manifest.AuthSecret{Name: envVarName, Path: a.Token.Path})
+}
+
+// toWriteableSecret returns the secret as 'file' secret if it has a path, or as 'environment' secret otherwise
+func toWriteableSecret(s manifest.AuthSecret) persistence.AuthSecret {
+	if s.Path != "" {
+		return persistence.AuthSecret{
+			Type: persistence.TypeFile,
+			Name: s.Name,
+			Path: s.Path,
+		}
+	}
+
 	return persistence.AuthSecret{
 		Type: persistence.TypeEnvironment,
-		Name: envVarName,
+		Name: s.Name,
 	}
 }
 
@@ -214,14 +345,8 @@ func getOAuthCredentials(a *manifest.OAuth) *persistence.OAuth {
 	}
 
 	return &persistence.OAuth{
-		ClientID: persistence.AuthSecret{
-			Type: persistence.TypeEnvironment,
-			Name: a.ClientID.Name,
-		},
-		ClientSecret: persistence.AuthSecret{
-			Type: persistence.TypeEnvironment,
-			Name: a.ClientSecret.Name,
-		},
+		ClientID:      toWriteableSecret(a.ClientID),
+		ClientSecret:  toWriteableSecret(a.ClientSecret),
 		TokenEndpoint: te,
 	}
 }
@@ -237,14 +362,8 @@ func toWriteableAccounts(accounts map[string]manifest.Account) []persistence.Acc
 		}
 
 		oauth := persistence.OAuth{
-			ClientID: persistence.AuthSecret{
-				Type: persistence.TypeEnvironment,
-				Name: account.OAuth.ClientID.Name,
-			},
-			ClientSecret: persistence.AuthSecret{
-				Type: persistence.TypeEnvironment,
-				Name: account.OAuth.ClientSecret.Name,
-			},
+			ClientID:     toWriteableSecret(account.OAuth.ClientID),
+			ClientSecret: toWriteableSecret(account.OAuth.ClientSecret),
 		}
 		if account.OAuth.TokenEndpoint != nil {
 			url := toWriteableURL(*account.OAuth.TokenEndpoint)
@@ -26,10 +26,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"net/url"
 	"reflect"
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func Test_toWriteableProjects(t *testing.T) {
@@ -266,10 +268,6 @@ func Test_toWriteableEnvironmentGroups(t *testing.T) {
 							Name: "env2",
 							URL:  persistence.TypedValue{Value: "www.an.Url"},
 							Auth: persistence.Auth{
-								Token: persistence.AuthSecret{
-									Name: "env2_TOKEN",
-									Type: "environment",
-								},
 								OAuth: &persistence.OAuth{
 									ClientID: persistence.AuthSecret{
 										Type: persistence.TypeEnvironment,
@@ -290,10 +288,6 @@ func Test_toWriteableEnvironmentGroups(t *testing.T) {
 							Name: "env2a",
 							URL:  persistence.TypedValue{Value: "www.an.Url"},
 							Auth: persistence.Auth{
-								Token: persistence.AuthSecret{
-									Name: "env2_TOKEN",
-									Type: "environment",
-								},
 								OAuth: &persistence.OAuth{
 									ClientID: persistence.AuthSecret{
 										Type: persistence.TypeEnvironment,
@@ -310,10 +304,6 @@ func Test_toWriteableEnvironmentGroups(t *testing.T) {
 							Name: "env2b",
 							URL:  persistence.TypedValue{Value: "www.an.Url"},
 							Auth: persistence.Auth{
-								Token: persistence.AuthSecret{
-									Name: "env2_TOKEN",
-									Type: "environment",
-								},
 								OAuth: &persistence.OAuth{
 									ClientID: persistence.AuthSecret{
 										Type: persistence.TypeEnvironment,
@@ -459,6 +449,22 @@ func Test_toWritableToken(t *testing.T) {
 				Type: "environment",
 			},
 		},
+		{
+			"correctly transforms file token",
+			manifest.EnvironmentDefinition{
+				Name:  "NAME",
+				URL:   manifest.URLDefinition{},
+				Group: "GROUP",
+				Auth: manifest.Auth{
+					Token: manifest.AuthSecret{Name: "VARIABLE", Path: "/run/secrets/VARIABLE"},
+				},
+			},
+			persistence.AuthSecret{
+				Name: "VARIABLE",
+				Type: "file",
+				Path: "/run/secrets/VARIABLE",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -575,6 +581,55 @@ func Test_toWriteableAccounts(t *testing.T) {
 	}
 }
 
+func Test_toWriteableVariables(t *testing.T) {
+	t.Run("no variables", func(t *testing.T) {
+		assert.Nil(t, toWriteableVariables(nil))
+	})
+
+	t.Run("values and environment variables", func(t *testing.T) {
+		got := toWriteableVariables(map[string]manifest.Variable{
+			"tenant": {Value: "abc123"},
+			"stage":  {EnvironmentVariable: "STAGE", Value: "dev"},
+		})
+		assert.Equal(t, map[string]persistence.TypedValue{
+			"tenant": {Value: "abc123"},
+			"stage":  {Type: persistence.TypeEnvironment, Value: "STAGE"},
+		}, got)
+	})
+}
+
+func Test_toWriteableHTTPSettings(t *testing.T) {
+	t.Run("no http settings", func(t *testing.T) {
+		assert.Nil(t, toWriteableHTTPSettings(nil))
+	})
+
+	t.Run("all settings", func(t *testing.T) {
+		got := toWriteableHTTPSettings(&manifest.HTTPSettings{
+			Proxy:             &url.URL{Scheme: "http", Host: "proxy.example.com:8080"},
+			CACertificatePath: "ca.pem",
+			CACertificates:    []byte("certificate"),
+			Timeout:           2 * time.Minute,
+
+			MaxIdleConnsPerHost:    64,
+			MaxConnsPerHost:        128,
+			IdleConnTimeout:        time.Minute,
+			DisableTLSSessionReuse: true,
+			DisableHTTP2:           true,
+		})
+		assert.Equal(t, &persistence.HTTPSettings{
+			Proxy:         "http://proxy.example.com:8080",
+			CACertificate: "ca.pem",
+			Timeout:       "2m0s",
+
+			MaxIdleConnsPerHost:    64,
+			MaxConnsPerHost:        128,
+			IdleConnTimeout:        "1m0s",
+			DisableTLSSessionReuse: true,
+			DisableHTTP2:           true,
+		}, got)
+	})
+}
+
 func TestWrite(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -607,7 +662,7 @@ func TestWrite(t *testing.T) {
 					},
 				},
 			},
-			`manifestVersion: "1.0"
+			`manifestVersion: "1.1"
 projects:
 - name: p1
   path: projects/p1
@@ -664,7 +719,7 @@ environmentGroups:
 					},
 				},
 			},
-			`manifestVersion: "1.0"
+			`manifestVersion: "1.1"
 projects:
 - name: p1
   path: projects/p1
@@ -732,7 +787,7 @@ accounts:
 					},
 				},
 			},
-			`manifestVersion: "1.0"
+			`manifestVersion: "1.1"
 projects:
 - name: p1
   path: projects/p1
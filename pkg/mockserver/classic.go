@@ -0,0 +1,248 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+)
+
+// classicObject is a config stored for a classic config API
+type classicObject struct {
+	id      string
+	name    string
+	payload map[string]any
+}
+
+// AddConfig stores the given payload as config of the classic config API with the given ID, as if it was created by a
+// request. It returns the ID of the config.
+func (s *Server) AddConfig(apiID string, payload []byte) (string, error) {
+	var obj map[string]any
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	a, ok := s.apis[apiID]
+	if !ok {
+		return "", fmt.Errorf("unknown API %q", apiID)
+	}
+	return s.createConfig(a, newID(), obj).id, nil
+}
+
+// Configs returns the payloads of all configs of the classic config API with the given ID, in order of their creation
+func (s *Server) Configs(apiID string) []json.RawMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []json.RawMessage
+	for _, o := range s.configs[apiID] {
+		data, _ := json.Marshal(o.payload)
+		result = append(result, data)
+	}
+	return result
+}
+
+// findAPI returns the classic config API serving the given path, and the remainder of the path after the path of the
+// API. If multiple APIs match, the one with the longest path is returned.
+func (s *Server) findAPI(p string) (api.API, string, bool) {
+	var found api.API
+	var rest string
+	for _, a := range s.apis {
+		if len(a.URLPath) <= len(found.URLPath) {
+			continue
+		}
+		if p == a.URLPath {
+			found, rest = a, ""
+		} else if strings.HasPrefix(p, a.URLPath+"/") {
+			found, rest = a, strings.TrimPrefix(p, a.URLPath+"/")
+		}
+	}
+	return found, rest, found.ID != ""
+}
+
+func (s *Server) serveClassic(rw http.ResponseWriter, req *http.Request, p string) {
+	a, rest, ok := s.findAPI(p)
+	if !ok {
+		writeError(rw, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if rest == "validator" || strings.HasSuffix(rest, "/validator") {
+		if req.Method != http.MethodPost {
+			writeError(rw, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	id, err := url.PathUnescape(rest)
+	if err != nil || strings.Contains(rest, "/") {
+		writeError(rw, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch {
+	case a.SingleConfiguration:
+		s.serveSingleConfiguration(rw, req, a)
+	case id == "":
+		s.serveConfigCollection(rw, req, a)
+	default:
+		s.serveConfig(rw, req, a, id)
+	}
+}
+
+func (s *Server) serveSingleConfiguration(rw http.ResponseWriter, req *http.Request, a api.API) {
+	switch req.Method {
+	case http.MethodGet:
+		if objects := s.configs[a.ID]; len(objects) > 0 {
+			writeJSON(rw, http.StatusOK, objects[0].payload)
+			return
+		}
+		writeJSON(rw, http.StatusOK, map[string]any{})
+	case http.MethodPut:
+		obj, ok := readObject(rw, req)
+		if !ok {
+			return
+		}
+		s.configs[a.ID] = []classicObject{{id: a.ID, payload: obj}}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(rw, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) serveConfigCollection(rw http.ResponseWriter, req *http.Request, a api.API) {
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(rw, http.StatusOK, s.listResponse(a))
+	case http.MethodPost:
+		obj, ok := readObject(rw, req)
+		if !ok {
+			return
+		}
+		created := s.createConfig(a, newID(), obj)
+		writeJSON(rw, http.StatusCreated, createdResponse(a, created))
+	default:
+		writeError(rw, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) serveConfig(rw http.ResponseWriter, req *http.Request, a api.API, id string) {
+	i := slices.IndexFunc(s.configs[a.ID], func(o classicObject) bool { return o.id == id })
+
+	switch req.Method {
+	case http.MethodGet:
+		if i < 0 {
+			writeError(rw, http.StatusNotFound, "Not found")
+			return
+		}
+		writeJSON(rw, http.StatusOK, s.configs[a.ID][i].payload)
+	case http.MethodPut:
+		obj, ok := readObject(rw, req)
+		if !ok {
+			return
+		}
+		// like most classic config APIs, PUT creates the config with the given ID if it does not exist yet
+		if i < 0 {
+			created := s.createConfig(a, id, obj)
+			writeJSON(rw, http.StatusCreated, createdResponse(a, created))
+			return
+		}
+		obj[idProperty(a)] = id
+		s.configs[a.ID][i].name, _ = obj["name"].(string)
+		s.configs[a.ID][i].payload = obj
+		rw.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if i < 0 {
+			writeError(rw, http.StatusNotFound, "Not found")
+			return
+		}
+		s.configs[a.ID] = slices.Delete(s.configs[a.ID], i, i+1)
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(rw, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) createConfig(a api.API, id string, obj map[string]any) classicObject {
+	obj[idProperty(a)] = id
+	name, _ := obj["name"].(string)
+	created := classicObject{id: id, name: name, payload: obj}
+	s.configs[a.ID] = append(s.configs[a.ID], created)
+	return created
+}
+
+// listResponse returns the response listing all configs of an API, in the format the respective API uses
+func (s *Server) listResponse(a api.API) any {
+	values := make([]map[string]any, 0, len(s.configs[a.ID]))
+	for _, o := range s.configs[a.ID] {
+		values = append(values, map[string]any{idProperty(a): o.id, "name": o.name})
+	}
+
+	switch a.ID {
+	case api.AwsCredentials:
+		return values
+	case api.SyntheticMonitor:
+		return map[string]any{"monitors": values}
+	case api.SyntheticLocation:
+		return map[string]any{"locations": values}
+	}
+
+	property := a.PropertyNameOfGetAllResponse
+	if property == "" {
+		property = api.StandardApiPropertyNameOfGetAllResponse
+	}
+	return map[string]any{property: values, "totalCount": len(values)}
+}
+
+func createdResponse(a api.API, o classicObject) map[string]any {
+	return map[string]any{idProperty(a): o.id, "name": o.name}
+}
+
+// idProperty returns the property holding the ID of the configs of an API
+func idProperty(a api.API) string {
+	if a.ID == api.SyntheticMonitor || a.ID == api.SyntheticLocation {
+		return "entityId"
+	}
+	return "id"
+}
+
+func readObject(rw http.ResponseWriter, req *http.Request) (map[string]any, bool) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, "Failed to read request body")
+		return nil, false
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		writeError(rw, http.StatusBadRequest, "Request body is not a JSON object")
+		return nil, false
+	}
+	return obj, true
+}
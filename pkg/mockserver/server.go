@@ -0,0 +1,133 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mockserver provides an in-process HTTP server emulating the classic config APIs and the Settings 2.0 API of
+// a Dynatrace environment with in-memory state. It allows deploying projects in tests without a live environment.
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/google/uuid"
+)
+
+// DefaultVersion is the Dynatrace version reported by a Server
+const DefaultVersion = "1.300.0.20240101-000000"
+
+const (
+	versionPath         = "/api/v1/config/clusterversion"
+	settingsObjectsPath = "/api/v2/settings/objects"
+	settingsSchemasPath = "/api/v2/settings/schemas"
+)
+
+// Server is an http.Handler emulating the classic config APIs and the Settings 2.0 API of a Dynatrace environment.
+// All objects are kept in memory. Requests without an Authorization header are rejected, but any token is accepted.
+//
+// Classic config APIs with a parent API, such as key user actions, are not emulated. Settings schemas that were not
+// added using AddSchema are assumed to exist, to have no unique properties, and to not be ordered.
+type Server struct {
+	mutex sync.Mutex
+
+	apis     api.APIs
+	configs  map[string][]classicObject
+	settings []settingsObject
+	schemas  map[string]Schema
+}
+
+// Schema describes a Settings 2.0 schema known to a Server
+type Schema struct {
+	// ID of the schema, e.g. "builtin:alerting.profile"
+	ID string
+	// Version of the schema. Defaults to "1.0.0".
+	Version string
+	// Ordered states whether the objects of the schema are ordered
+	Ordered bool
+	// UniqueProperties lists the sets of properties whose values must be unique among the objects of a scope
+	UniqueProperties [][]string
+}
+
+// New creates a Server without any objects
+func New() *Server {
+	apis := api.NewAPIs().Filter(func(a api.API) bool { return a.HasParent() })
+	return &Server{
+		apis:    apis,
+		configs: make(map[string][]classicObject, len(apis)),
+		schemas: make(map[string]Schema),
+	}
+}
+
+// AddSchema makes the given schema known to the Server
+func (s *Server) AddSchema(schema Schema) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.schemas[schema.ID] = schema
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Authorization") == "" {
+		writeError(rw, http.StatusUnauthorized, "Missing authorization parameter.")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	p := strings.TrimSuffix(req.URL.Path, "/")
+	switch {
+	case p == versionPath && req.Method == http.MethodGet:
+		writeJSON(rw, http.StatusOK, map[string]string{"version": DefaultVersion})
+	case p == settingsObjectsPath || strings.HasPrefix(p, settingsObjectsPath+"/"):
+		s.serveSettingsObjects(rw, req, strings.TrimPrefix(strings.TrimPrefix(p, settingsObjectsPath), "/"))
+	case p == settingsSchemasPath || strings.HasPrefix(p, settingsSchemasPath+"/"):
+		s.serveSettingsSchemas(rw, req, strings.TrimPrefix(strings.TrimPrefix(p, settingsSchemasPath), "/"))
+	default:
+		s.serveClassic(rw, req, p)
+	}
+}
+
+type errorResponse struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Code                 int                   `json:"code"`
+	Message              string                `json:"message"`
+	ConstraintViolations []constraintViolation `json:"constraintViolations,omitempty"`
+}
+
+type constraintViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func writeError(rw http.ResponseWriter, status int, message string, violations ...constraintViolation) {
+	writeJSON(rw, status, errorResponse{Error: errorDetails{Code: status, Message: message, ConstraintViolations: violations}})
+}
+
+func newID() string {
+	return uuid.NewString()
+}
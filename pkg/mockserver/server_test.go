@@ -0,0 +1,202 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mockserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/auth"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/mockserver"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+)
+
+func newClient(t *testing.T, server *mockserver.Server) *dtclient.DynatraceClient {
+	s := httptest.NewServer(server)
+	t.Cleanup(s.Close)
+
+	restClient := rest.NewRestClient(auth.NewTokenAuthClient("token"), nil, rest.CreateRateLimitStrategy())
+	c, err := dtclient.NewClassicClient(s.URL, restClient, dtclient.WithAutoServerVersion())
+	require.NoError(t, err)
+	return c
+}
+
+func TestServer_RejectsRequestsWithoutAuthorization(t *testing.T) {
+	s := httptest.NewServer(mockserver.New())
+	defer s.Close()
+
+	resp, err := http.Get(s.URL + "/api/config/v1/alertingProfiles")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServer_ClassicConfigs(t *testing.T) {
+	server := mockserver.New()
+	c := newClient(t, server)
+	a := api.NewAPIs()[api.AlertingProfile]
+
+	created, err := c.UpsertConfigByName(context.TODO(), a, "profile", []byte(`{"name": "profile", "rules": []}`))
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Id)
+
+	updated, err := c.UpsertConfigByName(context.TODO(), a, "profile", []byte(`{"name": "profile", "rules": [{}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, updated.Id)
+
+	values, err := c.ListConfigs(context.TODO(), a)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	assert.Equal(t, "profile", values[0].Name)
+
+	payload, err := c.ReadConfigById(a, created.Id)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "`+created.Id+`", "name": "profile", "rules": [{}]}`, string(payload))
+
+	require.NoError(t, c.DeleteConfigById(a, created.Id))
+	assert.Empty(t, server.Configs(api.AlertingProfile))
+}
+
+func TestServer_NonUniqueNameConfigs(t *testing.T) {
+	server := mockserver.New()
+	c := newClient(t, server)
+	a := api.NewAPIs()[api.Dashboard]
+	require.True(t, a.NonUniqueName)
+
+	entity, err := c.UpsertConfigByNonUniqueNameAndId(context.TODO(), a, "fixed-id", "dashboard", []byte(`{"name": "dashboard"}`), false)
+	require.NoError(t, err)
+	assert.Equal(t, "fixed-id", entity.Id)
+	assert.Len(t, server.Configs(a.ID), 1)
+}
+
+func TestServer_SingleConfiguration(t *testing.T) {
+	server := mockserver.New()
+	c := newClient(t, server)
+
+	var a api.API
+	for _, candidate := range api.NewAPIs() {
+		if candidate.SingleConfiguration && !candidate.HasParent() {
+			a = candidate
+			break
+		}
+	}
+	require.NotEmpty(t, a.ID)
+
+	_, err := c.UpsertConfigByName(context.TODO(), a, a.ID, []byte(`{"enabled": true}`))
+	require.NoError(t, err)
+	require.Len(t, server.Configs(a.ID), 1)
+	assert.JSONEq(t, `{"enabled": true}`, string(server.Configs(a.ID)[0]))
+}
+
+func TestServer_Settings(t *testing.T) {
+	server := mockserver.New()
+	c := newClient(t, server)
+
+	obj := dtclient.SettingsObject{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:test", ConfigId: "c"},
+		SchemaId:   "builtin:test",
+		Scope:      "environment",
+		Content:    []byte(`{"name": "a"}`),
+	}
+
+	created, err := c.UpsertSettings(context.TODO(), obj, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+
+	obj.Content = []byte(`{"name": "b"}`)
+	updated, err := c.UpsertSettings(context.TODO(), obj, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, updated.Id, "objects with the same external ID are updated")
+
+	// a new client is used, as clients cache listed objects
+	objects, err := newClient(t, server).ListSettings(context.TODO(), "builtin:test", dtclient.ListSettingsOptions{})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, created.Id, objects[0].ObjectId)
+	assert.JSONEq(t, `{"name": "b"}`, string(objects[0].Value))
+	assert.NotEmpty(t, objects[0].ExternalId)
+
+	got, err := c.GetSettingById(created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "environment", got.Scope)
+
+	require.NoError(t, c.DeleteSettings(created.Id))
+	assert.Empty(t, server.Settings("builtin:test"))
+}
+
+func TestServer_SettingsUniqueProperties(t *testing.T) {
+	server := mockserver.New()
+	server.AddSchema(mockserver.Schema{ID: "builtin:test", Version: "1.2.3", UniqueProperties: [][]string{{"key"}}})
+	existingID := server.AddSetting("builtin:test", "environment", "", []byte(`{"key": "k", "value": 1}`))
+	c := newClient(t, server)
+
+	entity, err := c.UpsertSettings(context.TODO(), dtclient.SettingsObject{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:test", ConfigId: "c"},
+		SchemaId:   "builtin:test",
+		Scope:      "environment",
+		Content:    []byte(`{"key": "k", "value": 2}`),
+	}, dtclient.UpsertSettingsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, existingID, entity.Id)
+	assert.JSONEq(t, `{"key": "k", "value": 2}`, string(server.Settings("builtin:test")[0]))
+
+	schema, err := c.GetSchemaById("builtin:test")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", schema.Version)
+}
+
+func TestServer_RejectsInvalidSettings(t *testing.T) {
+	c := newClient(t, mockserver.New())
+
+	_, err := c.UpsertSettings(context.TODO(), dtclient.SettingsObject{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:test", ConfigId: "c"},
+		SchemaId:   "builtin:test",
+		Content:    []byte(`{}`),
+	}, dtclient.UpsertSettingsOptions{OverrideRetry: &rest.RetrySetting{MaxRetries: 1}})
+
+	var schemaValidation rest.SchemaValidationError
+	require.ErrorAs(t, err, &schemaValidation)
+	assert.Equal(t, []string{"scope: must not be empty"}, schemaValidation.Violations)
+}
+
+func TestServer_ReportsVersion(t *testing.T) {
+	s := httptest.NewServer(mockserver.New())
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL+"/api/v1/config/clusterversion", nil)
+	req.Header.Set("Authorization", "Api-Token token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Version string `json:"version"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, mockserver.DefaultVersion, body.Version)
+}
@@ -0,0 +1,270 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mockserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// settingsObject is a stored Settings 2.0 object
+type settingsObject struct {
+	ObjectID      string          `json:"objectId"`
+	SchemaID      string          `json:"schemaId"`
+	SchemaVersion string          `json:"schemaVersion"`
+	Scope         string          `json:"scope"`
+	ExternalID    string          `json:"externalId,omitempty"`
+	Value         json.RawMessage `json:"value"`
+}
+
+// settingsRequest is a single object of the payload of a request creating or updating settings objects
+type settingsRequest struct {
+	SchemaID      string          `json:"schemaId"`
+	ExternalID    string          `json:"externalId"`
+	Scope         string          `json:"scope"`
+	Value         json.RawMessage `json:"value"`
+	SchemaVersion string          `json:"schemaVersion"`
+	ObjectID      string          `json:"objectId"`
+	InsertAfter   *string         `json:"insertAfter"`
+}
+
+type settingsResponse struct {
+	Code     int           `json:"code"`
+	ObjectID string        `json:"objectId,omitempty"`
+	Error    *errorDetails `json:"error,omitempty"`
+}
+
+// AddSetting stores a Settings 2.0 object, as if it was created by a request. It returns the object ID.
+func (s *Server) AddSetting(schemaID, scope, externalID string, value []byte) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.upsertSetting(settingsRequest{SchemaID: schemaID, Scope: scope, ExternalID: externalID, Value: value})
+}
+
+// Settings returns the values of all Settings 2.0 objects of the given schema, in their order
+func (s *Server) Settings(schemaID string) []json.RawMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []json.RawMessage
+	for _, o := range s.settings {
+		if o.SchemaID == schemaID {
+			result = append(result, o.Value)
+		}
+	}
+	return result
+}
+
+func (s *Server) serveSettingsObjects(rw http.ResponseWriter, req *http.Request, objectID string) {
+	switch {
+	case objectID == "" && req.Method == http.MethodGet:
+		s.listSettings(rw, req)
+	case objectID == "" && req.Method == http.MethodPost:
+		s.postSettings(rw, req)
+	case objectID != "" && req.Method == http.MethodGet:
+		if i := s.settingIndex(objectID); i >= 0 {
+			writeJSON(rw, http.StatusOK, s.settings[i])
+			return
+		}
+		writeError(rw, http.StatusNotFound, "Settings not found")
+	case objectID != "" && req.Method == http.MethodDelete:
+		if i := s.settingIndex(objectID); i >= 0 {
+			s.settings = slices.Delete(s.settings, i, i+1)
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeError(rw, http.StatusNotFound, "Settings not found")
+	default:
+		writeError(rw, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// listSettings lists the objects matching the schemaIds, scopes and externalIds query parameters. All objects are
+// returned in a single page.
+func (s *Server) listSettings(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	matches := func(param, value string) bool {
+		v := query.Get(param)
+		return v == "" || slices.Contains(strings.Split(v, ","), value)
+	}
+
+	items := make([]settingsObject, 0)
+	for _, o := range s.settings {
+		if matches("schemaIds", o.SchemaID) && matches("scopes", o.Scope) && matches("externalIds", o.ExternalID) {
+			items = append(items, o)
+		}
+	}
+	writeJSON(rw, http.StatusOK, map[string]any{"items": items, "totalCount": len(items), "pageSize": len(items)})
+}
+
+// postSettings creates or updates all valid objects of the request. Objects are updated if an object with their object
+// ID or external ID exists, and created otherwise. The result of each object is returned in the order of the request,
+// and the request fails if any object is invalid.
+func (s *Server) postSettings(rw http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+
+	var requests []settingsRequest
+	if err := json.Unmarshal(body, &requests); err != nil {
+		writeError(rw, http.StatusBadRequest, "Request body is not a JSON array of settings objects")
+		return
+	}
+
+	validateOnly := req.URL.Query().Get("validateOnly") == "true"
+	status := http.StatusOK
+	results := make([]settingsResponse, len(requests))
+	for i, r := range requests {
+		if violations := validateSettingsRequest(r); len(violations) > 0 {
+			status = http.StatusBadRequest
+			results[i] = settingsResponse{Code: http.StatusBadRequest, Error: &errorDetails{Code: http.StatusBadRequest, Message: "Constraints violated.", ConstraintViolations: violations}}
+			continue
+		}
+		if validateOnly {
+			results[i] = settingsResponse{Code: http.StatusOK}
+			continue
+		}
+		results[i] = settingsResponse{Code: http.StatusOK, ObjectID: s.upsertSetting(r)}
+	}
+	writeJSON(rw, status, results)
+}
+
+func validateSettingsRequest(r settingsRequest) []constraintViolation {
+	var violations []constraintViolation
+	if r.SchemaID == "" {
+		violations = append(violations, constraintViolation{Path: "schemaId", Message: "must not be empty"})
+	}
+	if r.Scope == "" {
+		violations = append(violations, constraintViolation{Path: "scope", Message: "must not be empty"})
+	}
+	var value map[string]any
+	if err := json.Unmarshal(r.Value, &value); err != nil || value == nil {
+		violations = append(violations, constraintViolation{Path: "value", Message: "must be a JSON object"})
+	}
+	return violations
+}
+
+func (s *Server) upsertSetting(r settingsRequest) string {
+	i := -1
+	if r.ObjectID != "" {
+		i = s.settingIndex(r.ObjectID)
+	}
+	if i < 0 && r.ExternalID != "" {
+		i = slices.IndexFunc(s.settings, func(o settingsObject) bool { return o.ExternalID == r.ExternalID })
+	}
+
+	if i >= 0 {
+		s.settings[i].Value = r.Value
+		s.settings[i].Scope = r.Scope
+		if r.ExternalID != "" {
+			s.settings[i].ExternalID = r.ExternalID
+		}
+		return s.settings[i].ObjectID
+	}
+
+	o := settingsObject{
+		ObjectID:      newID(),
+		SchemaID:      r.SchemaID,
+		SchemaVersion: s.schema(r.SchemaID).Version,
+		Scope:         r.Scope,
+		ExternalID:    r.ExternalID,
+		Value:         r.Value,
+	}
+
+	// objects are appended, unless they are inserted at the front (empty insertAfter) or after a given object
+	switch {
+	case r.InsertAfter == nil:
+		s.settings = append(s.settings, o)
+	case *r.InsertAfter == "":
+		s.settings = slices.Insert(s.settings, 0, o)
+	default:
+		if j := s.settingIndex(*r.InsertAfter); j >= 0 {
+			s.settings = slices.Insert(s.settings, j+1, o)
+		} else {
+			s.settings = append(s.settings, o)
+		}
+	}
+	return o.ObjectID
+}
+
+func (s *Server) settingIndex(objectID string) int {
+	return slices.IndexFunc(s.settings, func(o settingsObject) bool { return o.ObjectID == objectID })
+}
+
+// schema returns the schema with the given ID, or a default one if it was not added to the Server
+func (s *Server) schema(schemaID string) Schema {
+	sc, ok := s.schemas[schemaID]
+	if !ok {
+		sc = Schema{ID: schemaID}
+	}
+	if sc.Version == "" {
+		sc.Version = "1.0.0"
+	}
+	return sc
+}
+
+type schemaConstraint struct {
+	Type             string   `json:"type"`
+	UniqueProperties []string `json:"uniqueProperties"`
+}
+
+type schemaResponse struct {
+	SchemaID          string             `json:"schemaId"`
+	Version           string             `json:"version"`
+	Ordered           bool               `json:"ordered"`
+	SchemaConstraints []schemaConstraint `json:"schemaConstraints"`
+}
+
+func (s *Server) serveSettingsSchemas(rw http.ResponseWriter, req *http.Request, schemaID string) {
+	if req.Method != http.MethodGet {
+		writeError(rw, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if schemaID != "" {
+		sc := s.schema(schemaID)
+		constraints := make([]schemaConstraint, 0, len(sc.UniqueProperties))
+		for _, u := range sc.UniqueProperties {
+			constraints = append(constraints, schemaConstraint{Type: "UNIQUE", UniqueProperties: u})
+		}
+		writeJSON(rw, http.StatusOK, schemaResponse{SchemaID: sc.ID, Version: sc.Version, Ordered: sc.Ordered, SchemaConstraints: constraints})
+		return
+	}
+
+	// all schemas that were added or are used by any object are listed
+	ids := make([]string, 0, len(s.schemas))
+	for id := range s.schemas {
+		ids = append(ids, id)
+	}
+	for _, o := range s.settings {
+		ids = append(ids, o.SchemaID)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	items := make([]map[string]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, map[string]string{"schemaId": id, "latestSchemaVersion": s.schema(id).Version})
+	}
+	writeJSON(rw, http.StatusOK, map[string]any{"items": items, "totalCount": len(items)})
+}
@@ -36,6 +36,8 @@ type ConfigDefinition struct {
 	Template       string                     `yaml:"template,omitempty" json:"template,omitempty" jsonschema:"required,description=The filepath to the JSON template used for this configuration"`
 	Skip           ConfigParameter            `yaml:"skip,omitempty" json:"skip,omitempty" jsonschema:"description=Defines whether this config should be skipped when deploying."`
 	OriginObjectId string                     `yaml:"originObjectId,omitempty" json:"originObjectId,omitempty" jsonschema:"description=description=The identifier of the Dynatrace object this config originated from - this is filled when downloading, but can also be set to tie a config to a specific object."`
+	MatchStrategy  string                     `yaml:"matchStrategy,omitempty" json:"matchStrategy,omitempty" jsonschema:"enum=by-name-first-match,enum=by-generated-id,enum=always-create,description=Defines how a config of a Config API with non-unique names is matched with existing objects of the same name: 'by-name-first-match' updates the first object of the same name, 'by-generated-id' creates or updates the object with the ID generated by monaco, 'always-create' creates a new object on every deployment. By default, a single existing object of the same name is updated, otherwise the object with the generated ID."`
+	MissingKeys    string                     `yaml:"missingKeys,omitempty" json:"missingKeys,omitempty" jsonschema:"enum=strict,enum=lenient,description=Defines how template variables are handled that are not defined as parameters: 'strict' fails the deployment of the config, 'lenient' renders them as empty strings. Defaults to 'strict'."`
 }
 
 type TopLevelConfigDefinition struct {
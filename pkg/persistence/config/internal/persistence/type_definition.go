@@ -20,12 +20,17 @@ import (
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/mitchellh/mapstructure"
 	"golang.org/x/exp/maps"
 )
 
-const BucketType = "bucket"
+const (
+	BucketType  = "bucket"
+	SLOType     = "slo-v2"
+	SegmentType = "segment"
+)
 
 type TypeDefinition struct {
 	Type        config.Type
@@ -49,6 +54,10 @@ type AutomationDefinition struct {
 	Resource config.AutomationResource `yaml:"resource" json:"resource" jsonschema:"required,enum=workflow,enum=business-calendar,enum=scheduling-rule,description=This defines which automation resource this config is for."`
 }
 
+type OpenPipelineDefinition struct {
+	Kind string `yaml:"kind" json:"kind" jsonschema:"required,description=This defines which kind of data this OpenPipeline configuration is for, e.g. 'logs' or 'events'." mapstructure:"kind"`
+}
+
 type DocumentDefinition struct {
 	Type config.DocumentType `yaml:"type" json:"type" jsonschema:"required,enum=dashboard-document,enum=notebook-document,description=This defines which document type this config is for." mapstructure:"type"`
 }
@@ -62,9 +71,14 @@ func (c *TypeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error
 	// To catch that, let's try to unmarshal directly into a string. If it works, we know the shorthand is used.
 	str := ""
 	if err := unmarshal(&str); err == nil {
-		if str == BucketType {
+		switch str {
+		case BucketType:
 			c.Type = config.BucketType{}
-		} else {
+		case SLOType:
+			c.Type = config.SLOType{}
+		case SegmentType:
+			c.Type = config.SegmentType{}
+		default:
 			c.Type = config.ClassicApiType{Api: str}
 		}
 
@@ -91,9 +105,11 @@ func (c *TypeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error
 	// Now we know the one type and can call the unmarshalers.
 	// The unmarshalers write to the type directly to update it, which is a design choice, not a requirement.
 	unmarshalers := map[string]func(data any) error{
-		"api":        c.parseApiType,
-		"settings":   c.parseSettingsType,
-		"automation": c.parseAutomation,
+		"api":          c.parseApiType,
+		"settings":     c.parseSettingsType,
+		"automation":   c.parseAutomation,
+		"openpipeline": c.parseOpenPipelineType,
+		"cluster":      c.parseClusterType,
 	}
 
 	if featureflags.Documents().Enabled() {
@@ -154,6 +170,29 @@ func (c *TypeDefinition) parseAutomation(a any) error {
 	return nil
 }
 
+func (c *TypeDefinition) parseOpenPipelineType(a any) error {
+	var r OpenPipelineDefinition
+	err := mapstructure.Decode(a, &r)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal openpipeline-type: %w", err)
+	}
+
+	c.Type = config.OpenPipelineType{Kind: r.Kind}
+
+	return nil
+}
+
+func (c *TypeDefinition) parseClusterType(a any) error {
+	str, ok := a.(string)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal cluster-type: expected the name of a Cluster API, but got %T", a)
+	}
+
+	c.Type = config.ClusterType{Api: str}
+
+	return nil
+}
+
 func (c *TypeDefinition) parseDocumentType(a any) error {
 	var r DocumentDefinition
 	err := mapstructure.Decode(a, &r)
@@ -195,6 +234,16 @@ func (c *TypeDefinition) Validate(apis map[string]struct{}) error {
 			return fmt.Errorf("unknown automation resource %q", t.Resource)
 		}
 
+	case config.OpenPipelineType:
+		if t.Kind == "" {
+			return errors.New("missing openpipeline kind property")
+		}
+
+	case config.ClusterType:
+		if _, f := api.NewClusterAPIs()[t.Api]; !f {
+			return fmt.Errorf("unknown Cluster API: %s", t.Api)
+		}
+
 	case config.DocumentType:
 		switch t {
 		case "":
@@ -223,6 +272,10 @@ func (c *TypeDefinition) GetApiType() string {
 		return string(t.ID())
 	case config.DocumentType:
 		return string(t)
+	case config.SLOType, config.SegmentType, config.OpenPipelineType:
+		return string(t.ID())
+	case config.ClusterType:
+		return t.Api
 	}
 
 	return ""
@@ -271,6 +324,24 @@ func (c TypeDefinition) MarshalYAML() (interface{}, error) {
 	case config.BucketType:
 		return BucketType, nil
 
+	case config.SLOType:
+		return SLOType, nil
+
+	case config.SegmentType:
+		return SegmentType, nil
+
+	case config.OpenPipelineType:
+		return map[string]any{
+			"openpipeline": OpenPipelineDefinition{
+				Kind: t.Kind,
+			},
+		}, nil
+
+	case config.ClusterType:
+		return map[string]string{
+			"cluster": t.Api,
+		}, nil
+
 	case config.DocumentType:
 		if featureflags.Documents().Enabled() {
 			return map[string]any{
@@ -23,7 +23,9 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	entitySelectorParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entityselector"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
@@ -111,6 +113,8 @@ func parseDefinitionForEnvironment(
 	configDefinition := persistence.ConfigDefinition{
 		Parameters:     make(map[string]persistence.ConfigParameter),
 		OriginObjectId: definition.Config.OriginObjectId,
+		MatchStrategy:  definition.Config.MatchStrategy,
+		MissingKeys:    definition.Config.MissingKeys,
 	}
 
 	applyOverrides(&configDefinition, definition.Config)
@@ -145,6 +149,14 @@ func applyOverrides(base *persistence.ConfigDefinition, override persistence.Con
 		base.OriginObjectId = override.OriginObjectId
 	}
 
+	if override.MatchStrategy != "" {
+		base.MatchStrategy = override.MatchStrategy
+	}
+
+	if override.MissingKeys != "" {
+		base.MissingKeys = override.MissingKeys
+	}
+
 	for name, param := range override.Parameters {
 		base.Parameters[name] = param
 	}
@@ -166,7 +178,7 @@ func getConfigFromDefinition(
 		}
 	}
 
-	tmpl, err := template.NewFileTemplate(fs, filepath.Join(context.Folder, definition.Template))
+	tmpl, err := template.NewFileTemplateWithPartials(fs, filepath.Join(context.Folder, definition.Template), context.LoaderContext.Path)
 
 	var errs []error
 
@@ -174,7 +186,7 @@ func getConfigFromDefinition(
 		errs = append(errs, newDetailedDefinitionParserError(configId, context, environment, fmt.Sprintf("error while loading template: `%s`", err)))
 	}
 
-	parameters, parameterErrors := parseParametersAndReferences(fs, context, environment, configId,
+	parameters, constraints, parameterErrors := parseParametersAndReferences(fs, context, environment, configId,
 		definition.Parameters)
 
 	if parameterErrors != nil {
@@ -209,6 +221,16 @@ func getConfigFromDefinition(
 		errs = append(errs, newDetailedDefinitionParserError(configId, context, environment, "missing parameter `name`"))
 	}
 
+	if definition.MatchStrategy != "" {
+		if err := validateMatchStrategy(definition.MatchStrategy, configType); err != nil {
+			errs = append(errs, newDetailedDefinitionParserError(configId, context, environment, err.Error()))
+		}
+	}
+
+	if definition.MissingKeys != "" && !slices.Contains(config.MissingKeyModes, config.MissingKeyMode(definition.MissingKeys)) {
+		errs = append(errs, newDetailedDefinitionParserError(configId, context, environment, fmt.Sprintf("unknown `missingKeys` %q, must be one of %q", definition.MissingKeys, config.MissingKeyModes)))
+	}
+
 	if errs != nil {
 		return config.Config{}, errs
 	}
@@ -224,6 +246,11 @@ func getConfigFromDefinition(
 			return config.Config{}, []error{fmt.Errorf("failed to parse scope: cannot use parameter-type %q. Allowed types: %v", scopeParam.GetType(), allowedScopeParameterTypes)}
 		}
 
+		// a scope is a single entity, thus entity selectors have to match exactly one entity
+		if selector, ok := scopeParam.(*entitySelectorParam.EntitySelectorParameter); ok && selector.Cardinality != entitySelectorParam.One {
+			return config.Config{}, []error{fmt.Errorf("failed to parse scope: entity selector must match exactly one entity, but has cardinality %q", selector.Cardinality)}
+		}
+
 		parameters[config.ScopeParameter] = scopeParam
 	}
 
@@ -234,13 +261,21 @@ func getConfigFromDefinition(
 			return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: %w", err)}
 		}
 
-		r, isRef := insertAfterParam.(*reference.ReferenceParameter)
-		if !isRef {
-			return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: cannot use parameter-type %q. Allowed types: %v", insertAfterParam.GetType(), reference.ReferenceParameterType)}
-		}
-
-		if r.Property != "id" {
-			return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: property field of reference parameter %q must be %q", insertAfterParam, "id")}
+		switch p := insertAfterParam.(type) {
+		case *reference.ReferenceParameter:
+			if p.Property != "id" {
+				return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: property field of reference parameter %q must be %q", insertAfterParam, "id")}
+			}
+		case *reference.NameReferenceParameter:
+			if p.Property != "id" {
+				return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: property field of reference parameter %q must be %q", insertAfterParam, "id")}
+			}
+		case *valueParam.ValueParameter:
+			if p.Value != config.InsertAfterFront {
+				return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: value must be %q, got %q", config.InsertAfterFront, p.Value)}
+			}
+		default:
+			return config.Config{}, []error{fmt.Errorf("failed to parse insertAfter: cannot use parameter-type %q. Allowed types: %v, %q value", insertAfterParam.GetType(), reference.ReferenceParameterType, config.InsertAfterFront)}
 		}
 
 		parameters[config.InsertAfterParameter] = insertAfterParam
@@ -253,15 +288,30 @@ func getConfigFromDefinition(
 			Type:     context.Type,
 			ConfigId: configId,
 		},
-		Type:           configType.Type,
-		Group:          environment.Group,
-		Environment:    environment.Name,
-		Parameters:     parameters,
-		Skip:           skipConfig,
-		OriginObjectId: definition.OriginObjectId,
+		Type:                 configType.Type,
+		Group:                environment.Group,
+		Environment:          environment.Name,
+		Parameters:           parameters,
+		ParameterConstraints: constraints,
+		Skip:                 skipConfig,
+		OriginObjectId:       definition.OriginObjectId,
+		MatchStrategy:        config.MatchStrategy(definition.MatchStrategy),
+		MissingKeys:          config.MissingKeyMode(definition.MissingKeys),
 	}, nil
 }
 
+// validateMatchStrategy checks that the match strategy is known, and that the config is of a classic API with
+// non-unique names, as only such configs are matched with existing objects by name
+func validateMatchStrategy(strategy string, configType persistence.TypeDefinition) error {
+	if !slices.Contains(config.MatchStrategies, config.MatchStrategy(strategy)) {
+		return fmt.Errorf("unknown `matchStrategy` %q, must be one of %q", strategy, config.MatchStrategies)
+	}
+	if configType.Type.ID() != config.ClassicApiTypeId || !api.NewAPIs()[configType.GetApiType()].NonUniqueName {
+		return errors.New("`matchStrategy` is only supported for configs of Config APIs with non-unique names")
+	}
+	return nil
+}
+
 func parseSkip(fs afero.Fs,
 	context *singleConfigEntryLoadContext,
 	environmentDefinition manifest.EnvironmentDefinition,
@@ -26,8 +26,11 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/compound"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/constraint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entityselector"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/managementzone"
 	ref "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
@@ -35,6 +38,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"strings"
 	"testing"
 )
@@ -46,7 +50,7 @@ func Test_parseConfigs(t *testing.T) {
 	testLoaderContext := &LoaderContext{
 		ProjectId: "project",
 		Path:      "some-dir/",
-		KnownApis: map[string]struct{}{"some-api": {}, api.DashboardShareSettings: {}},
+		KnownApis: map[string]struct{}{"some-api": {}, api.Dashboard: {}, api.DashboardShareSettings: {}},
 		Environments: []manifest.EnvironmentDefinition{
 			{
 				Name:  "env name",
@@ -362,6 +366,121 @@ configs:
 			filePathOnDisk:    "test-file.yaml",
 			wantErrorsContain: []string{"no configurations found in file"},
 		},
+		{
+			name:             "loads config with matchStrategy",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: dashboard-id
+  config:
+    name: Star Trek Dashboard
+    template: profile.json
+    matchStrategy: by-name-first-match
+  type:
+    api: dashboard`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "dashboard",
+						ConfigId: "dashboard-id",
+					},
+					Type: config.ClassicApiType{
+						Api: "dashboard",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Dashboard"},
+					},
+					Environment:   "env name",
+					Group:         "default",
+					MatchStrategy: config.MatchByNameFirstMatch,
+				},
+			},
+		},
+		{
+			name:             "reports error for unknown matchStrategy",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: dashboard-id
+  config:
+    name: Star Trek Dashboard
+    template: profile.json
+    matchStrategy: by-luck
+  type:
+    api: dashboard`,
+			wantErrorsContain: []string{"unknown `matchStrategy` \"by-luck\""},
+		},
+		{
+			name:             "loads config with lenient missingKeys overridden per environment",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: Star Trek Service
+    template: profile.json
+    missingKeys: strict
+  type:
+    api: some-api
+  environmentOverrides:
+  - environment: env name
+    override:
+      missingKeys: lenient`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile-id",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Service"},
+					},
+					Environment: "env name",
+					Group:       "default",
+					MissingKeys: config.MissingKeysLenient,
+				},
+			},
+		},
+		{
+			name:             "reports error for unknown missingKeys",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: Star Trek Service
+    template: profile.json
+    missingKeys: ignore
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{"unknown `missingKeys` \"ignore\""},
+		},
+		{
+			name:             "reports error for matchStrategy on config of API with unique names",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: Star Trek Service
+    template: profile.json
+    matchStrategy: always-create
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{"`matchStrategy` is only supported for configs of Config APIs with non-unique names"},
+		},
 		{
 			name:             "loads settings 2.0 config with all properties",
 			filePathArgument: "test-file.yaml",
@@ -553,6 +672,63 @@ configs:
         configType: something`,
 			wantErrorsContain: []string{`failed to parse insertAfter: property field of reference parameter "project:something:configId:name" must be "id"`},
 		},
+		{
+			name:             "loads settings 2.0 config with front as insertAfter",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'environment'
+      insertAfter: front`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: config.SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						config.NameParameter:        &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						config.ScopeParameter:       &value.ValueParameter{Value: "environment"},
+						config.InsertAfterParameter: &value.ValueParameter{Value: "front"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "loads settings 2.0 config with an unknown value as insertAfter",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'environment'
+      insertAfter: back`,
+			wantErrorsContain: []string{`failed to parse insertAfter: value must be "front", got "back"`},
+		},
 		{
 			name:             "loads settings 2.0 config with a shorthand reference as scope",
 			filePathArgument: "test-file.yaml",
@@ -1153,6 +1329,149 @@ configs:
 				},
 			},
 		},
+		{
+			name:             "SLO-v2 config",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    template: 'profile.json'
+  type: slo-v2
+`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "slo-v2",
+						ConfigId: "profile-id",
+					},
+					Type:        config.SLOType{},
+					Template:    template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters:  config.Parameters{},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "Segment config",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: segment-id
+  config:
+    template: 'profile.json'
+  type: segment
+`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "segment",
+						ConfigId: "segment-id",
+					},
+					Type:        config.SegmentType{},
+					Template:    template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters:  config.Parameters{},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "OpenPipeline config",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: logs
+  config:
+    template: 'profile.json'
+  type:
+    openpipeline:
+      kind: logs
+`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "openpipeline",
+						ConfigId: "logs",
+					},
+					Type:        config.OpenPipelineType{Kind: "logs"},
+					Template:    template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters:  config.Parameters{},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "OpenPipeline config without kind",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: logs
+  config:
+    template: 'profile.json'
+  type:
+    openpipeline:
+      kind: ""
+`,
+			wantErrorsContain: []string{"missing openpipeline kind property"},
+		},
+		{
+			name:             "Cluster config",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: group
+  config:
+    name: 'group'
+    template: 'profile.json'
+  type:
+    cluster: cluster-user-group
+`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "cluster-user-group",
+						ConfigId: "group",
+					},
+					Type:     config.ClusterType{Api: "cluster-user-group"},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						config.NameParameter: &value.ValueParameter{Value: "group"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "Unknown cluster API",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: group
+  config:
+    template: 'profile.json'
+  type:
+    cluster: cluster-unknown
+`,
+			wantErrorsContain: []string{"unknown Cluster API: cluster-unknown"},
+		},
 		{
 			name:             "Bucket written as api config",
 			filePathArgument: "test-file.yaml",
@@ -1499,10 +1818,147 @@ configs:
   type:
     document:
       type: dashboard-document`,
+			envVars: map[string]string{
+				featureflags.Documents().EnvName(): "false",
+			},
 			wantErrorsContain: []string{
 				"unknown config-type \"document\"",
 			},
 		},
+		{
+			name:             "Parameter constraints are loaded",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      threshold:
+        type: environment
+        name: __UNSET_THRESHOLD
+        min: 0
+        max: 100
+      severity:
+        type: value
+        value: ERROR
+        enum: [ERROR, WARNING]
+  type:
+    api: some-api`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":      &value.ValueParameter{Value: "Star Trek Service"},
+						"threshold": environment.New("__UNSET_THRESHOLD"),
+						"severity":  &value.ValueParameter{Value: "ERROR"},
+					},
+					ParameterConstraints: map[string]constraint.Constraints{
+						"threshold": {Min: ptr(0.0), Max: ptr(100.0)},
+						"severity":  {Enum: []string{"ERROR", "WARNING"}},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "Parameter constraints are checked for environment variables",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      threshold:
+        type: environment
+        name: THRESHOLD
+        max: 100
+  type:
+    api: some-api`,
+			envVars: map[string]string{
+				"THRESHOLD": "150",
+			},
+			wantErrorsContain: []string{
+				"value 150 is greater than the maximum 100",
+			},
+		},
+		{
+			name:             "Parameter constraints are checked for values",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      severity:
+        type: value
+        value: INFO
+        enum: [ERROR, WARNING]
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{
+				`value "INFO" is not one of the allowed values ["ERROR" "WARNING"]`,
+			},
+		},
+		{
+			name:             "Invalid parameter constraints",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      threshold:
+        type: value
+        value: 1
+        pattern: "[0-9"
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{
+				"invalid `pattern`",
+			},
+		},
+		{
+			name:             "Entity selector scope matching several entities",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      scope:
+        type: entitySelector
+        selector: type(HOST)
+        cardinality: any`,
+			wantErrorsContain: []string{
+				`failed to parse scope: entity selector must match exactly one entity, but has cardinality "any"`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1660,3 +2116,60 @@ func makeCompoundParam(t *testing.T, refs []parameter.ParameterReference) *compo
 	assert.NoError(t, err)
 	return compoundParam
 }
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func Test_parseConfigs_ResolvedScopes(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId: "project",
+		Path:      "some-dir/",
+		Environments: []manifest.EnvironmentDefinition{
+			{
+				Name:  "env name",
+				URL:   manifest.URLDefinition{Type: manifest.ValueURLType, Value: "env url"},
+				Group: "default",
+			},
+		},
+		ParametersSerDe: config.DefaultParameterParsers,
+	}
+
+	tests := []struct {
+		name      string
+		scope     string
+		wantScope parameter.Parameter
+	}{
+		{
+			name:      "entity selector",
+			scope:     "{type: entitySelector, selector: 'type(HOST),entityName(web)'}",
+			wantScope: &entityselector.EntitySelectorParameter{},
+		},
+		{
+			name:      "management zone",
+			scope:     "{type: managementZone, name: 'Team Checkout'}",
+			wantScope: &managementzone.ManagementZoneParameter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(testFs, "test-file.yaml", []byte(`
+configs:
+- id: profile-id
+  config:
+    name: Star Trek Service
+    template: profile.json
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      scope: `+tt.scope), 0644)
+			_ = afero.WriteFile(testFs, "profile.json", []byte("{}"), 0644)
+
+			gotConfigs, gotErrors := LoadConfigFile(testFs, loaderContext, "test-file.yaml")
+			require.Empty(t, gotErrors)
+			require.Len(t, gotConfigs, 1)
+			assert.IsType(t, tt.wantScope, gotConfigs[0].Parameters[config.ScopeParameter])
+		})
+	}
+}
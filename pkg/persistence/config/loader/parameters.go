@@ -23,7 +23,11 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/constraint"
+	entitySelectorParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entityselector"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	envParameterParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environmentparameter"
+	managementZoneParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/managementzone"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
@@ -35,6 +39,9 @@ var allowedScopeParameterTypes = []string{
 	refParam.ReferenceParameterType,
 	valueParam.ValueParameterType,
 	envParam.EnvironmentVariableParameterType,
+	envParameterParam.EnvironmentParameterType,
+	entitySelectorParam.EntitySelectorParameterType,
+	managementZoneParam.ManagementZoneParameterType,
 }
 
 // isSupportedParamTypeForSkip check is 'skip' section of configuration supports specified param type
@@ -44,6 +51,8 @@ func isSupportedParamTypeForSkip(p parameter.Parameter) bool {
 		return true
 	case envParam.EnvironmentVariableParameterType:
 		return true
+	case envParameterParam.EnvironmentParameterType:
+		return true
 	default:
 		return false
 	}
@@ -53,9 +62,10 @@ func isSupportedParamTypeForSkip(p parameter.Parameter) bool {
 type References map[string]coordinate.Coordinate
 
 func parseParametersAndReferences(fs afero.Fs, context *singleConfigEntryLoadContext, environment manifest.EnvironmentDefinition,
-	configId string, parameterMap map[string]persistence.ConfigParameter) (config.Parameters, []error) {
+	configId string, parameterMap map[string]persistence.ConfigParameter) (config.Parameters, map[string]constraint.Constraints, []error) {
 
 	parameters := make(map[string]parameter.Parameter)
+	var constraints map[string]constraint.Constraints
 	var errs []error
 
 	for name, param := range parameterMap {
@@ -82,14 +92,56 @@ func parseParametersAndReferences(fs afero.Fs, context *singleConfigEntryLoadCon
 			continue
 		}
 
+		if c, found, err := parseConstraints(context, environment, configId, name, param, result); err != nil {
+			errs = append(errs, err)
+			continue
+		} else if found {
+			if constraints == nil {
+				constraints = make(map[string]constraint.Constraints)
+			}
+			constraints[name] = c
+		}
+
 		parameters[name] = result
 	}
 
 	if errs != nil {
-		return nil, errs
+		return nil, nil, errs
+	}
+
+	return parameters, constraints, nil
+}
+
+// parseConstraints parses the constraints declared in the definition of a parameter. Parameters whose value is already
+// known when loading - values and environment variables - are checked right away, so that invalid values are reported
+// before anything is deployed. All other parameters are checked when they are resolved.
+func parseConstraints(context *singleConfigEntryLoadContext, environment manifest.EnvironmentDefinition,
+	configId string, name string, definition interface{}, param parameter.Parameter) (constraint.Constraints, bool, error) {
+
+	m, ok := definition.(map[interface{}]interface{})
+	if !ok {
+		return constraint.Constraints{}, false, nil
+	}
+
+	c, found, err := constraint.Parse(maps.ToStringMap(m))
+	if err != nil {
+		return constraint.Constraints{}, false, newParameterDefinitionParserError(name, configId, context, environment, err.Error())
+	}
+	if !found {
+		return constraint.Constraints{}, false, nil
+	}
+
+	switch param.GetType() {
+	case valueParam.ValueParameterType, envParam.EnvironmentVariableParameterType:
+		// a missing environment variable is reported when the parameter is resolved
+		if val, err := param.ResolveValue(parameter.ResolveContext{ParameterName: name}); err == nil {
+			if err := c.Check(val); err != nil {
+				return constraint.Constraints{}, false, newParameterDefinitionParserError(name, configId, context, environment, err.Error())
+			}
+		}
 	}
 
-	return parameters, nil
+	return c, true, nil
 }
 
 func validateParameterName(context *singleConfigEntryLoadContext, environment manifest.EnvironmentDefinition, configId string, name string) error {
@@ -130,9 +182,14 @@ func parseParameter(fs afero.Fs, context *singleConfigEntryLoadContext, environm
 				Type:     context.Type,
 				ConfigId: configId,
 			},
-			Fs:            afero.NewBasePathFs(fs, context.Folder),
-			ParameterName: name,
-			Value:         maps.ToStringMap(val),
+			Group:                 environment.Group,
+			Environment:           environment.Name,
+			Fs:                    afero.NewBasePathFs(fs, context.Folder),
+			ParameterName:         name,
+			Value:                 maps.ToStringMap(val),
+			EnvironmentParameters: environment.Parameters,
+			EnvironmentURL:        environment.URL.Value,
+			EnvironmentToken:      environment.Auth.Token.Value.Value(),
 		})
 	}
 
@@ -0,0 +1,180 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package writer
+
+import (
+	"bytes"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
+	"gopkg.in/yaml.v3"
+)
+
+// collectAnnotations merges the annotations of all configs per coordinate. Configs without annotations are omitted.
+func collectAnnotations(configs []config.Config) map[coordinate.Coordinate]config.ConversionAnnotations {
+	result := make(map[coordinate.Coordinate]config.ConversionAnnotations)
+	for _, c := range configs {
+		if c.AnnotationsForConversion.IsEmpty() {
+			continue
+		}
+
+		merged := result[c.Coordinate]
+		merged.Config = merged.Config.Merge(c.AnnotationsForConversion.Config)
+		for name, a := range c.AnnotationsForConversion.Parameters {
+			if merged.Parameters == nil {
+				merged.Parameters = make(map[string]config.YamlAnnotation)
+			}
+			merged.Parameters[name] = merged.Parameters[name].Merge(a)
+		}
+		result[c.Coordinate] = merged
+	}
+	return result
+}
+
+// hasAnnotations returns true if any config of the definition is annotated
+func hasAnnotations(apiCoord apiCoordinate, definition persistence.TopLevelDefinition, annotations map[coordinate.Coordinate]config.ConversionAnnotations) bool {
+	for _, c := range definition.Configs {
+		if _, found := annotations[coordinate.Coordinate{Project: apiCoord.project, Type: apiCoord.api, ConfigId: c.Id}]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateYaml adds the comments and anchors of the annotated configs to the marshalled config file. As yaml.v2 does
+// not support comments, the yaml is round-tripped through yaml.v3 nodes. Within a file, the first parameter value
+// with an anchor defines it, and equal values of the same anchor are written as aliases.
+func annotateYaml(definitionYaml []byte, apiCoord apiCoordinate, annotations map[coordinate.Coordinate]config.ConversionAnnotations) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(definitionYaml, &doc); err != nil {
+		return nil, err
+	}
+
+	anchors := make(map[string]*yaml.Node)
+	for _, c := range mappingValue(doc.Content[0], "configs").Content {
+		id, idFound := mappingEntry(c, "id")
+		if !idFound {
+			continue
+		}
+
+		a, found := annotations[coordinate.Coordinate{Project: apiCoord.project, Type: apiCoord.api, ConfigId: c.Content[id+1].Value}]
+		if !found {
+			continue
+		}
+
+		c.HeadComment = a.Config.Comment
+
+		commented := make(map[string]bool)
+		for _, definition := range configDefinitions(c) {
+			annotateConfigDefinition(definition, a.Parameters, commented, anchors)
+		}
+	}
+
+	var b bytes.Buffer
+	e := yaml.NewEncoder(&b)
+	e.SetIndent(2)
+	if err := e.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// configDefinitions returns the base config definition and all override definitions of a config
+func configDefinitions(c *yaml.Node) []*yaml.Node {
+	result := []*yaml.Node{mappingValue(c, "config")}
+	for _, key := range []string{"groupOverrides", "environmentOverrides"} {
+		for _, o := range mappingValue(c, key).Content {
+			result = append(result, mappingValue(o, "override"))
+		}
+	}
+	return result
+}
+
+// annotateConfigDefinition annotates the name, skip and parameters of the definition. Each parameter is commented only
+// once per config.
+func annotateConfigDefinition(definition *yaml.Node, parameters map[string]config.YamlAnnotation, commented map[string]bool, anchors map[string]*yaml.Node) {
+	annotate := func(mapping *yaml.Node, i int) {
+		name := mapping.Content[i].Value
+		a, found := parameters[name]
+		if !found {
+			return
+		}
+
+		if a.Comment != "" && !commented[name] {
+			mapping.Content[i].HeadComment = a.Comment
+			commented[name] = true
+		}
+
+		if a.Anchor == "" {
+			return
+		}
+		value := mapping.Content[i+1]
+		if anchored, defined := anchors[a.Anchor]; !defined {
+			value.Anchor = a.Anchor
+			anchors[a.Anchor] = value
+		} else if equalNodes(anchored, value) {
+			mapping.Content[i+1] = &yaml.Node{Kind: yaml.AliasNode, Value: a.Anchor, Alias: anchored}
+		}
+	}
+
+	for _, key := range []string{config.NameParameter, config.SkipParameter} {
+		if i, found := mappingEntry(definition, key); found {
+			annotate(definition, i)
+		}
+	}
+
+	params := mappingValue(definition, "parameters")
+	for i := 0; i+1 < len(params.Content); i += 2 {
+		annotate(params, i)
+	}
+}
+
+// mappingEntry returns the index of the key node of the given key in the mapping node
+func mappingEntry(mapping *yaml.Node, key string) (int, bool) {
+	if mapping.Kind != yaml.MappingNode {
+		return 0, false
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// mappingValue returns the value node of the given key in the mapping node, or an empty node if there is none
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if i, found := mappingEntry(mapping, key); found {
+		return mapping.Content[i+1]
+	}
+	return &yaml.Node{}
+}
+
+func equalNodes(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Tag != b.Tag || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !equalNodes(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
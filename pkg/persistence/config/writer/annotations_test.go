@@ -0,0 +1,133 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package writer
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/testutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWriteConfigs_KeepsAnnotations(t *testing.T) {
+	newConfig := func(id string, env string, name string, annotations config.ConversionAnnotations) config.Config {
+		return config.Config{
+			Template:    template.NewInMemoryTemplateWithPath("project/alerting-profile/a.json", ""),
+			Coordinate:  coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: id},
+			Type:        config.ClassicApiType{Api: "alerting-profile"},
+			Environment: env,
+			Group:       "default",
+			Parameters: map[string]parameter.Parameter{
+				config.NameParameter: &value.ValueParameter{Value: name},
+			},
+			AnnotationsForConversion: annotations,
+		}
+	}
+
+	configs := []config.Config{
+		newConfig("a", "env1", "Services", config.ConversionAnnotations{
+			Config:     config.YamlAnnotation{Comment: "alerting for all services"},
+			Parameters: map[string]config.YamlAnnotation{config.NameParameter: {Comment: "shown in the UI", Anchor: "profileName"}},
+		}),
+		newConfig("a", "env2", "Services", config.ConversionAnnotations{
+			Config:     config.YamlAnnotation{Comment: "alerting for all services\nalso on env2"},
+			Parameters: map[string]config.YamlAnnotation{config.NameParameter: {Comment: "shown in the UI", Anchor: "profileName"}},
+		}),
+		newConfig("b", "env1", "Services", config.ConversionAnnotations{
+			Parameters: map[string]config.YamlAnnotation{config.NameParameter: {Anchor: "profileName"}},
+		}),
+		newConfig("c", "env1", "Other", config.ConversionAnnotations{
+			Parameters: map[string]config.YamlAnnotation{config.NameParameter: {Anchor: "profileName"}},
+		}),
+	}
+
+	fs := testutils.TempFs(t)
+	errs := WriteConfigs(&WriterContext{
+		Fs:              fs,
+		OutputFolder:    "test",
+		ProjectFolder:   "project",
+		ParametersSerde: config.DefaultParameterParsers,
+	}, configs)
+	require.Empty(t, errs)
+
+	content, err := afero.ReadFile(fs, "test/project/alerting-profile/config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `configs:
+  # alerting for all services
+  # also on env2
+  - id: a
+    config:
+      # shown in the UI
+      name: &profileName Services
+      template: a.json
+      skip: false
+    type:
+      api: alerting-profile
+  - id: b
+    config:
+      name: *profileName
+      template: a.json
+      skip: false
+    type:
+      api: alerting-profile
+  - id: c
+    config:
+      name: Other
+      template: a.json
+      skip: false
+    type:
+      api: alerting-profile
+`, string(content))
+}
+
+func TestWriteConfigs_WithoutAnnotationsKeepsFormat(t *testing.T) {
+	fs := testutils.TempFs(t)
+	errs := WriteConfigs(&WriterContext{
+		Fs:              fs,
+		OutputFolder:    "test",
+		ProjectFolder:   "project",
+		ParametersSerde: config.DefaultParameterParsers,
+	}, []config.Config{
+		{
+			Template:   template.NewInMemoryTemplateWithPath("project/alerting-profile/a.json", ""),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "a"},
+			Type:       config.ClassicApiType{Api: "alerting-profile"},
+			Parameters: map[string]parameter.Parameter{config.NameParameter: &value.ValueParameter{Value: "Services"}},
+		},
+	})
+	require.Empty(t, errs)
+
+	content, err := afero.ReadFile(fs, "test/project/alerting-profile/config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `configs:
+- id: a
+  config:
+    name: Services
+    template: a.json
+    skip: false
+  type:
+    api: alerting-profile
+`, string(content))
+}
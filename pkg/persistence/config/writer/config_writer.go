@@ -23,6 +23,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	configError "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	assetParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/asset"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
@@ -39,6 +40,8 @@ type WriterContext struct {
 	OutputFolder    string
 	ProjectFolder   string
 	ParametersSerde map[string]parameter.ParameterSerDe
+	// Layout decides the folders configs are written to. If nil, FlatLayout is used.
+	Layout Layout
 }
 
 type serializerContext struct {
@@ -60,6 +63,8 @@ type detailedSerializerContext struct {
 type apiCoordinate struct {
 	project string
 	api     string
+	// folder relative to the project folder the api's configs are written to
+	folder string
 }
 
 type configTemplate struct {
@@ -77,10 +82,12 @@ func WriteConfigs(context *WriterContext, configs []config.Config) []error {
 		return errs
 	}
 
+	annotations := collectAnnotations(configs)
+
 	var writeErrors []error
 
 	for apiCoord, definition := range definitions {
-		err := writeTopLevelDefinitionToDisk(context, apiCoord, definition)
+		err := writeTopLevelDefinitionToDisk(context, apiCoord, definition, annotations)
 
 		if err != nil {
 			writeErrors = append(writeErrors, err)
@@ -132,11 +139,16 @@ func toTopLevelDefinitions(context *WriterContext, configs []config.Config) (map
 	knownTemplates := map[string]struct{}{}
 	var configTemplates []configTemplate
 
+	layout := context.Layout
+	if layout == nil {
+		layout = FlatLayout
+	}
+
 	for coord, confs := range configsPerCoordinate {
-		sanitizedType := mystrings.Sanitize(coord.Type)
+		folder := layout(confs[0])
 		configContext := &serializerContext{
 			WriterContext: context,
-			configFolder:  filepath.Join(context.ProjectFolder, sanitizedType),
+			configFolder:  filepath.Join(context.ProjectFolder, folder),
 			config:        coord,
 		}
 
@@ -150,6 +162,7 @@ func toTopLevelDefinitions(context *WriterContext, configs []config.Config) (map
 		apiCoord := apiCoordinate{
 			project: coord.Project,
 			api:     coord.Type,
+			folder:  folder,
 		}
 
 		configsPerApi[apiCoord] = append(configsPerApi[apiCoord], definition)
@@ -181,7 +194,7 @@ func byConfigId(a, b persistence.TopLevelConfigDefinition) int {
 	return strings.Compare(a.Id, b.Id)
 }
 
-func writeTopLevelDefinitionToDisk(context *WriterContext, apiCoord apiCoordinate, definition persistence.TopLevelDefinition) error {
+func writeTopLevelDefinitionToDisk(context *WriterContext, apiCoord apiCoordinate, definition persistence.TopLevelDefinition, annotations map[coordinate.Coordinate]config.ConversionAnnotations) error {
 	// sort configs so that they are stable within a config file
 	slices.SortFunc(definition.Configs, byConfigId)
 	definitionYaml, err := yaml.Marshal(definition)
@@ -190,8 +203,15 @@ func writeTopLevelDefinitionToDisk(context *WriterContext, apiCoord apiCoordinat
 		return newConfigWriterError(context, err)
 	}
 
-	sanitizedApi := mystrings.Sanitize(apiCoord.api)
-	targetConfigFile := filepath.Join(context.OutputFolder, context.ProjectFolder, sanitizedApi, "config.yaml")
+	if hasAnnotations(apiCoord, definition, annotations) {
+		definitionYaml, err = annotateYaml(definitionYaml, apiCoord, annotations)
+
+		if err != nil {
+			return newConfigWriterError(context, err)
+		}
+	}
+
+	targetConfigFile := filepath.Join(context.OutputFolder, context.ProjectFolder, apiCoord.folder, "config.yaml")
 
 	err = context.Fs.MkdirAll(filepath.Dir(targetConfigFile), 0777)
 
@@ -347,7 +367,9 @@ func extractCommonBase(configs []extendedConfigDefinition) (*persistence.ConfigD
 	// TODO refactor this monstrosity
 	if len(sharedParam) == 0 && (!checkResult.foundName || !checkResult.shareName) &&
 		(!checkResult.foundTemplate || !checkResult.shareTemplate) &&
-		(!checkResult.foundSkip || !checkResult.shareSkip) {
+		(!checkResult.foundSkip || !checkResult.shareSkip) &&
+		(!checkResult.foundMatchStrategy || !checkResult.shareMatchStrategy) &&
+		(!checkResult.foundMissingKeys || !checkResult.shareMissingKeys) {
 		return nil, configs
 	}
 
@@ -382,7 +404,7 @@ func createConfigDefinitionWithoutSharedValues(toReduce extendedConfigDefinition
 	}
 
 	if allParametersShared && checkResult.shareName &&
-		checkResult.shareSkip && checkResult.shareTemplate {
+		checkResult.shareSkip && checkResult.shareTemplate && checkResult.shareMatchStrategy && checkResult.shareMissingKeys {
 		return nil
 	}
 
@@ -402,6 +424,14 @@ func createConfigDefinitionWithoutSharedValues(toReduce extendedConfigDefinition
 		result.Skip = toReduce.Skip
 	}
 
+	if !checkResult.shareMatchStrategy {
+		result.MatchStrategy = toReduce.MatchStrategy
+	}
+
+	if !checkResult.shareMissingKeys {
+		result.MissingKeys = toReduce.MissingKeys
+	}
+
 	return result
 }
 
@@ -420,6 +450,14 @@ func createCommonConfigDefinition(checkResult propertyCheckResult, sharedParamet
 		result.Skip = checkResult.skip
 	}
 
+	if checkResult.foundMatchStrategy || checkResult.shareMatchStrategy {
+		result.MatchStrategy = checkResult.matchStrategy
+	}
+
+	if checkResult.foundMissingKeys || checkResult.shareMissingKeys {
+		result.MissingKeys = checkResult.missingKeys
+	}
+
 	if len(sharedParameters) > 0 {
 		result.Parameters = sharedParameters
 	}
@@ -462,17 +500,29 @@ type propertyCheckResult struct {
 	shareSkip bool
 	foundSkip bool
 	skip      interface{}
+
+	shareMatchStrategy bool
+	foundMatchStrategy bool
+	matchStrategy      string
+
+	shareMissingKeys bool
+	foundMissingKeys bool
+	missingKeys      string
 }
 
 func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResult {
 	name := configs[0].Name
 	templ := configs[0].Template
 	skip := configs[0].Skip
+	matchStrategy := configs[0].MatchStrategy
+	missingKeys := configs[0].MissingKeys
 
 	var (
 		sameName,
 		sameTemplate,
-		sameSkip = true, true, true
+		sameSkip,
+		sameMatchStrategy,
+		sameMissingKeys = true, true, true, true, true
 	)
 
 	for _, c := range configs {
@@ -481,6 +531,8 @@ func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResu
 		sameSkip = sameSkip && (reflect.DeepEqual(skip, c.Skip) ||
 			(skip == nil && c.Skip == false) ||
 			(skip == false && c.Skip == nil))
+		sameMatchStrategy = sameMatchStrategy && matchStrategy == c.MatchStrategy
+		sameMissingKeys = sameMissingKeys && missingKeys == c.MissingKeys
 	}
 
 	if !sameName {
@@ -495,6 +547,14 @@ func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResu
 		skip = nil
 	}
 
+	if !sameMatchStrategy {
+		matchStrategy = ""
+	}
+
+	if !sameMissingKeys {
+		missingKeys = ""
+	}
+
 	return propertyCheckResult{
 		shareName: sameName,
 		foundName: name != nil || !sameName,
@@ -507,6 +567,14 @@ func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResu
 		shareSkip: sameSkip,
 		foundSkip: skip != nil || !sameSkip,
 		skip:      skip,
+
+		shareMatchStrategy: sameMatchStrategy,
+		foundMatchStrategy: matchStrategy != "" || !sameMatchStrategy,
+		matchStrategy:      matchStrategy,
+
+		shareMissingKeys: sameMissingKeys,
+		foundMissingKeys: missingKeys != "" || !sameMissingKeys,
+		missingKeys:      missingKeys,
 	}
 }
 
@@ -530,7 +598,18 @@ func toConfigDefinitions(context *serializerContext, configs []config.Config) ([
 			continue
 		}
 
+		assets, err := extractAssets(context, c)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		templates = append(templates, assets...)
+
 		templates = append(templates, templ)
+		// partials keep their path, so that the template can still include them by the same name
+		for _, p := range template.Partials(c.Template) {
+			templates = append(templates, configTemplate{templatePath: p.Path, content: p.Content})
+		}
 
 		result = append(result, extendedConfigDefinition{
 			ConfigDefinition: definition,
@@ -585,6 +664,8 @@ func toConfigDefinition(context *serializerContext, cfg config.Config) (persiste
 		Template:       filepath.ToSlash(configTemplatePath),
 		Skip:           skipParam,
 		OriginObjectId: cfg.OriginObjectId,
+		MatchStrategy:  string(cfg.MatchStrategy),
+		MissingKeys:    string(cfg.MissingKeys),
 	}, templ, nil
 }
 
@@ -630,6 +711,28 @@ func extractTemplate(context *detailedSerializerContext, cfg config.Config) (str
 	}, nil
 }
 
+// extractAssets returns the contents of all asset parameters of the config, so that they are stored as separate files
+// relative to the config's folder.
+func extractAssets(context *serializerContext, cfg config.Config) ([]configTemplate, error) {
+	var assets []configTemplate
+	for name, p := range cfg.Parameters {
+		a, ok := p.(*assetParam.AssetParameter)
+		if !ok {
+			continue
+		}
+
+		content, err := a.Content()
+		if err != nil {
+			return nil, newDetailedConfigWriterError(context, fmt.Errorf("failed to store asset of parameter %q: %w", name, err))
+		}
+		assets = append(assets, configTemplate{
+			templatePath: filepath.Join(context.configFolder, filepath.FromSlash(a.Path)),
+			content:      string(content),
+		})
+	}
+	return assets, nil
+}
+
 func convertParameters(context *detailedSerializerContext, parameters config.Parameters) (map[string]persistence.ConfigParameter, []error) {
 	var errs []error
 	result := make(map[string]persistence.ConfigParameter)
@@ -28,6 +28,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/testutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/asset"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
@@ -1022,6 +1023,41 @@ func TestWriteConfigs(t *testing.T) {
 				"project/bucket/mybucket.json",
 			},
 		},
+		{
+			name: "OpenPipeline configurations",
+			configs: []config.Config{
+				{
+					Template: template.NewInMemoryTemplateWithPath("project/openpipeline/logs.json", "{}"),
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "openpipeline",
+						ConfigId: "logs",
+					},
+					Type:       config.OpenPipelineType{Kind: "logs"},
+					Parameters: map[string]parameter.Parameter{},
+					Skip:       false,
+				},
+			},
+			expectedConfigs: map[string]persistence.TopLevelDefinition{
+				"openpipeline": {
+					Configs: []persistence.TopLevelConfigDefinition{
+						{
+							Id: "logs",
+							Config: persistence.ConfigDefinition{
+								Template: "logs.json",
+								Skip:     false,
+							},
+							Type: persistence.TypeDefinition{
+								Type: config.OpenPipelineType{Kind: "logs"},
+							},
+						},
+					},
+				},
+			},
+			expectedTemplatePaths: []string{
+				"project/openpipeline/logs.json",
+			},
+		},
 		{
 			name: "Reference scope",
 			configs: []config.Config{
@@ -1339,3 +1375,58 @@ func TestOrderedConfigs(t *testing.T) {
 	}
 
 }
+
+func TestWriteConfigs_WritesPartials(t *testing.T) {
+	fs := testutils.TempFs(t)
+	partials := []template.Partial{{Name: "partials/tile.json", Path: "project/partials/tile.json", Content: `{"name": "{{ .name }}"}`}}
+
+	errs := WriteConfigs(&WriterContext{
+		Fs:              fs,
+		OutputFolder:    "out",
+		ProjectFolder:   "project",
+		ParametersSerde: config.DefaultParameterParsers,
+	}, []config.Config{
+		{
+			Template:   template.NewInMemoryTemplateWithPartials("project/dashboard/a.json", `[{{ template "partials/tile.json" . }}]`, partials),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "a"},
+			Type:       config.ClassicApiType{Api: "dashboard"},
+			Parameters: map[string]parameter.Parameter{config.NameParameter: &value.ValueParameter{Value: "a"}},
+		},
+	})
+	assert.Empty(t, errs)
+
+	content, err := afero.ReadFile(fs, "out/project/partials/tile.json")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name": "{{ .name }}"}`, string(content))
+}
+
+func TestWriteConfigs_WritesAssets(t *testing.T) {
+	fs := testutils.TempFs(t)
+	content := []byte{0x50, 0x4b, 0x03, 0x04, 0x00, 0xff}
+
+	errs := WriteConfigs(&WriterContext{
+		Fs:              fs,
+		OutputFolder:    "out",
+		ProjectFolder:   "project",
+		ParametersSerde: config.DefaultParameterParsers,
+	}, []config.Config{
+		{
+			Template:   template.NewInMemoryTemplate("a", `{"archive": "{{ .archive }}"}`),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "a"},
+			Type:       config.ClassicApiType{Api: "dashboard"},
+			Parameters: map[string]parameter.Parameter{
+				config.NameParameter: &value.ValueParameter{Value: "a"},
+				"archive":            asset.New("assets/a.zip", content),
+			},
+		},
+	})
+	assert.Empty(t, errs)
+
+	written, err := afero.ReadFile(fs, "out/project/dashboard/assets/a.zip")
+	assert.NoError(t, err)
+	assert.Equal(t, content, written)
+
+	definition, err := afero.ReadFile(fs, "out/project/dashboard/config.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(definition), "path: assets/a.zip")
+}
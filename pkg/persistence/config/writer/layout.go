@@ -0,0 +1,76 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package writer
+
+import (
+	mystrings "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"path/filepath"
+	"strings"
+)
+
+// Layout decides the folder, relative to the project folder, that a config is written to. All configs of the same
+// coordinate type must be mapped to the same folder, as they share one config.yaml.
+type Layout func(c config.Config) string
+
+const (
+	// FlatLayoutName is the name of FlatLayout
+	FlatLayoutName = "flat"
+	// HierarchicalLayoutName is the name of HierarchicalLayout
+	HierarchicalLayoutName = "hierarchical"
+)
+
+// Layouts holds all available layouts by their name.
+var Layouts = map[string]Layout{
+	FlatLayoutName:         FlatLayout,
+	HierarchicalLayoutName: HierarchicalLayout,
+}
+
+// FlatLayout writes the configs of every type into its own folder directly in the project folder, e.g.
+// 'builtinalerting.profile' or 'alerting-profile'.
+func FlatLayout(c config.Config) string {
+	return mystrings.Sanitize(c.Coordinate.Type)
+}
+
+// HierarchicalLayout groups the type folders by the kind of config, and settings additionally by the category of their
+// schema, e.g. 'settings/alerting/builtinalerting.profile' or 'classic/alerting-profile'.
+func HierarchicalLayout(c config.Config) string {
+	typeFolder := mystrings.Sanitize(c.Coordinate.Type)
+	if c.Type == nil {
+		return typeFolder
+	}
+
+	kind := string(c.Type.ID())
+	if s, ok := c.Type.(config.SettingsType); ok {
+		if category := schemaCategory(s.SchemaId); category != "" {
+			return filepath.Join(kind, mystrings.Sanitize(category), typeFolder)
+		}
+	}
+	return filepath.Join(kind, typeFolder)
+}
+
+// schemaCategory returns the first segment of a schema ID after its namespace, e.g. 'alerting' for
+// 'builtin:alerting.profile'.
+func schemaCategory(schemaID string) string {
+	_, name, found := strings.Cut(schemaID, ":")
+	if !found || !strings.Contains(name, ".") {
+		return ""
+	}
+	category, _, _ := strings.Cut(name, ".")
+	category, _, _ = strings.Cut(category, ":")
+	return category
+}
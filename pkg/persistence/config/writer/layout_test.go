@@ -0,0 +1,136 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package writer
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayouts(t *testing.T) {
+	tests := []struct {
+		name                 string
+		config               config.Config
+		expectedFlat         string
+		expectedHierarchical string
+	}{
+		{
+			name: "settings with category",
+			config: config.Config{
+				Coordinate: coordinate.Coordinate{Type: "builtin:alerting.profile"},
+				Type:       config.SettingsType{SchemaId: "builtin:alerting.profile"},
+			},
+			expectedFlat:         "builtinalerting.profile",
+			expectedHierarchical: filepath.Join("settings", "alerting", "builtinalerting.profile"),
+		},
+		{
+			name: "settings without category",
+			config: config.Config{
+				Coordinate: coordinate.Coordinate{Type: "builtin:tags"},
+				Type:       config.SettingsType{SchemaId: "builtin:tags"},
+			},
+			expectedFlat:         "builtintags",
+			expectedHierarchical: filepath.Join("settings", "builtintags"),
+		},
+		{
+			name: "app settings",
+			config: config.Config{
+				Coordinate: coordinate.Coordinate{Type: "app:my.app:schema"},
+				Type:       config.SettingsType{SchemaId: "app:my.app:schema"},
+			},
+			expectedFlat:         "appmy.appschema",
+			expectedHierarchical: filepath.Join("settings", "my", "appmy.appschema"),
+		},
+		{
+			name: "classic API",
+			config: config.Config{
+				Coordinate: coordinate.Coordinate{Type: "alerting-profile"},
+				Type:       config.ClassicApiType{Api: "alerting-profile"},
+			},
+			expectedFlat:         "alerting-profile",
+			expectedHierarchical: filepath.Join("classic", "alerting-profile"),
+		},
+		{
+			name: "automation",
+			config: config.Config{
+				Coordinate: coordinate.Coordinate{Type: "workflow"},
+				Type:       config.AutomationType{Resource: config.Workflow},
+			},
+			expectedFlat:         "workflow",
+			expectedHierarchical: filepath.Join("automation", "workflow"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedFlat, FlatLayout(tt.config))
+			assert.Equal(t, tt.expectedHierarchical, HierarchicalLayout(tt.config))
+		})
+	}
+}
+
+func TestWriteConfigs_HierarchicalLayout(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configs := []config.Config{
+		{
+			Template:   template.NewInMemoryTemplate("a", "{}"),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "a"},
+			Type:       config.SettingsType{SchemaId: "builtin:alerting.profile"},
+			Parameters: map[string]parameter.Parameter{
+				config.NameParameter:  &value.ValueParameter{Value: "a"},
+				config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			},
+		},
+		{
+			Template:   template.NewInMemoryTemplate("b", "{}"),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "b"},
+			Type:       config.ClassicApiType{Api: "alerting-profile"},
+			Parameters: map[string]parameter.Parameter{
+				config.NameParameter: &value.ValueParameter{Value: "b"},
+			},
+		},
+	}
+
+	errs := WriteConfigs(&WriterContext{
+		Fs:              fs,
+		OutputFolder:    "out",
+		ProjectFolder:   "project",
+		ParametersSerde: config.DefaultParameterParsers,
+		Layout:          HierarchicalLayout,
+	}, configs)
+	assert.Empty(t, errs)
+
+	for _, f := range []string{
+		"out/project/settings/alerting/builtinalerting.profile/config.yaml",
+		"out/project/settings/alerting/builtinalerting.profile/a.json",
+		"out/project/classic/alerting-profile/config.yaml",
+		"out/project/classic/alerting-profile/b.json",
+	} {
+		exists, err := afero.Exists(fs, f)
+		assert.NoError(t, err)
+		assert.True(t, exists, "expected file %q to exist", f)
+	}
+}
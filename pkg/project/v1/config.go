@@ -39,6 +39,8 @@ type Config struct {
 	template   template.Template
 	api        api.API
 	fileName   string
+	// annotations holds the comments and anchors of the config's yaml entries, by top-level key and property
+	annotations template.Annotations
 }
 
 type configProvider func(fs afero.Fs, id string, project string, fileName string, properties map[string]map[string]string, api api.API) (*Config, error)
@@ -64,9 +66,9 @@ func newConfigWithTemplate(id string, project string, template template.Template
 	}
 }
 
-func filterProperties(id string, properties map[string]map[string]string) map[string]map[string]string {
+func filterProperties[V any](id string, properties map[string]V) map[string]V {
 
-	result := make(map[string]map[string]string)
+	result := make(map[string]V)
 	configNameInID := strings.Split(id, ".")[0]
 	for key, value := range properties {
 		configNameInKey := strings.Split(key, ".")[0]
@@ -122,6 +124,12 @@ func (c *Config) GetProperties() map[string]map[string]string {
 	return c.properties
 }
 
+// GetAnnotations returns the comments and anchors of the config's yaml entries, by top-level key and property.
+// The annotations of the config itself are stored with the config's id and an empty property.
+func (c *Config) GetAnnotations() template.Annotations {
+	return c.annotations
+}
+
 // HasDependencyOn checks if one config depends on the given parameter config
 // Having a dependency means, that the config having the dependency needs to be applied AFTER the config it depends on
 func (c *Config) HasDependencyOn(config *Config) bool {
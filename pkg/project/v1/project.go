@@ -135,12 +135,17 @@ func (p *projectBuilder) processYaml(filename string, unmarshalYaml template.Unm
 		return err
 	}
 
-	err = p.processConfigSection(properties, folderPath)
+	annotations, err := template.UnmarshalYamlAnnotations(string(bytes))
+	if err != nil {
+		log.Debug("Comments and anchors of %s can not be kept: %s", filename, err)
+	}
+
+	err = p.processConfigSection(properties, annotations, folderPath)
 
 	return err
 }
 
-func (p *projectBuilder) processConfigSection(properties map[string]map[string]string, folderPath string) error {
+func (p *projectBuilder) processConfigSection(properties map[string]map[string]string, annotations template.Annotations, folderPath string) error {
 
 	templates, ok := properties["config"]
 	if !ok {
@@ -165,11 +170,32 @@ func (p *projectBuilder) processConfigSection(properties map[string]map[string]s
 			return err
 		}
 
+		c.annotations = configAnnotations(configName, annotations)
 		p.configs = append(p.configs, c)
 	}
 	return nil
 }
 
+// configAnnotations returns the annotations of the config with the given id. The annotation of its entry in the
+// 'config' section is merged into the annotation of the config itself.
+func configAnnotations(id string, annotations template.Annotations) template.Annotations {
+	result := filterProperties(id, annotations)
+
+	if a, found := annotations["config"][id]; found {
+		if result[id] == nil {
+			result[id] = make(map[string]template.Annotation)
+		}
+		own := result[id][""]
+		own.Comment = strings.TrimSpace(a.Comment + "\n" + own.Comment)
+		result[id][""] = own
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
 // standardizeLocation aims to standardize the location of the passed json file
 // When it is called with an absolute path (starting with /), we simply strip the "/" away
 // Otherwise we assume that the location is relative to the given yaml - so it needs to pe prepended with the folder
@@ -39,7 +39,7 @@ func testCreateProjectBuilder(projectsRoot string) projectBuilder {
 				project,
 				properties,
 				nil,
-				api, fileName,
+				api, fileName, nil,
 			}, nil
 		},
 		configs: make([]*Config, 10),
@@ -156,7 +156,7 @@ func TestProcessConfigSection(t *testing.T) {
 
 	fs := testutils.CreateTestFileSystem()
 	builder := testCreateProjectBuilderWithMock(func(fs afero.Fs, id string, project string, fileName string, properties map[string]map[string]string, api api.API) (*Config, error) {
-		return &Config{id, project, properties, nil, api, fileName}, nil
+		return &Config{id, project, properties, nil, api, fileName, nil}, nil
 	}, fs, "testProject", "")
 
 	m := make(map[string]map[string]string)
@@ -167,7 +167,7 @@ func TestProcessConfigSection(t *testing.T) {
 	m["config"]["test2"] = files.ReplacePathSeparators("/test/alerting-profile/profile.json")
 
 	folderPath := files.ReplacePathSeparators("test/management-zone")
-	err := builder.processConfigSection(m, folderPath)
+	err := builder.processConfigSection(m, nil, folderPath)
 	assert.NoError(t, err)
 }
 
@@ -181,6 +181,7 @@ func TestProcessConfigSectionWithProjectRootParameter(t *testing.T) {
 			nil,
 			api,
 			fileName,
+			nil,
 		}, nil
 	}, fileReaderMock, "test", "testProjectsRoot")
 
@@ -192,7 +193,7 @@ func TestProcessConfigSectionWithProjectRootParameter(t *testing.T) {
 	m["config"]["testconfig2"] = files.ReplacePathSeparators("/test/alerting-profile/profile.json")
 
 	folderPath := files.ReplacePathSeparators("test/management-zone")
-	err := builder.processConfigSection(m, folderPath)
+	err := builder.processConfigSection(m, nil, folderPath)
 	assert.NoError(t, err)
 }
 
@@ -250,6 +251,7 @@ func TestProcessYaml(t *testing.T) {
 			nil,
 			api,
 			fileName,
+			nil,
 		}, nil
 	}, fs, "testproject", "")
 
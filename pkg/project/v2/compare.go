@@ -0,0 +1,184 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+
+	"github.com/spf13/afero"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
+)
+
+// ChangeKind describes how a config differs between two versions of projects
+type ChangeKind string
+
+const (
+	// ConfigAdded configs only exist in the current version
+	ConfigAdded ChangeKind = "added"
+	// ConfigRemoved configs only exist in the base version
+	ConfigRemoved ChangeKind = "removed"
+	// ConfigChanged configs exist in both versions, but differ in their type, template, parameters, or skip state
+	ConfigChanged ChangeKind = "changed"
+)
+
+// ConfigChange is a config that differs between two versions of projects in one environment
+type ConfigChange struct {
+	Environment string
+	Coordinate  coordinate.Coordinate
+	Kind        ChangeKind
+}
+
+// Compare returns all configs that were added, removed, or changed in the current version of projects compared to the
+// base version, per environment. Changes are sorted by environment and coordinate.
+func Compare(base, current []Project) []ConfigChange {
+	baseConfigs := configsByCoordinatePerEnvironment(base)
+	currentConfigs := configsByCoordinatePerEnvironment(current)
+
+	var changes []ConfigChange
+	for env, configs := range currentConfigs {
+		for coord, c := range configs {
+			b, found := baseConfigs[env][coord]
+			if !found {
+				changes = append(changes, ConfigChange{Environment: env, Coordinate: coord, Kind: ConfigAdded})
+			} else if !configsEqual(b, c) {
+				changes = append(changes, ConfigChange{Environment: env, Coordinate: coord, Kind: ConfigChanged})
+			}
+		}
+	}
+	for env, configs := range baseConfigs {
+		for coord := range configs {
+			if _, found := currentConfigs[env][coord]; !found {
+				changes = append(changes, ConfigChange{Environment: env, Coordinate: coord, Kind: ConfigRemoved})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b ConfigChange) int {
+		if c := cmp.Compare(a.Environment, b.Environment); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+	return changes
+}
+
+// Dependents returns the given coordinates, plus the coordinates of all configs of the environment that directly or
+// transitively reference one of them. The result is sorted.
+func Dependents(projects []Project, environment string, coordinates []coordinate.Coordinate) []coordinate.Coordinate {
+	referencedBy := make(map[coordinate.Coordinate][]coordinate.Coordinate)
+	for _, p := range projects {
+		p.ForEveryConfigInEnvironmentDo(environment, func(c config.Config) {
+			for _, ref := range c.References() {
+				referencedBy[ref] = append(referencedBy[ref], c.Coordinate)
+			}
+		})
+	}
+
+	seen := make(map[coordinate.Coordinate]struct{}, len(coordinates))
+	queue := slices.Clone(coordinates)
+	for len(queue) > 0 {
+		coord := queue[0]
+		queue = queue[1:]
+		if _, found := seen[coord]; found {
+			continue
+		}
+		seen[coord] = struct{}{}
+		queue = append(queue, referencedBy[coord]...)
+	}
+
+	result := make([]coordinate.Coordinate, 0, len(seen))
+	for coord := range seen {
+		result = append(result, coord)
+	}
+	slices.SortFunc(result, func(a, b coordinate.Coordinate) int { return cmp.Compare(a.String(), b.String()) })
+	return result
+}
+
+func configsByCoordinatePerEnvironment(projects []Project) map[EnvironmentName]map[coordinate.Coordinate]config.Config {
+	result := make(map[EnvironmentName]map[coordinate.Coordinate]config.Config)
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			if result[env] == nil {
+				result[env] = make(map[coordinate.Coordinate]config.Config)
+			}
+			for _, configs := range configsPerType {
+				for _, c := range configs {
+					result[env][c.Coordinate] = c
+				}
+			}
+		}
+	}
+	return result
+}
+
+// configsEqual returns whether two versions of a config would be deployed the same way. Templates are compared by
+// content, and parameters by their serialized definition, plus the content of referenced files.
+func configsEqual(a, b config.Config) bool {
+	if a.Skip != b.Skip || a.OriginObjectId != b.OriginObjectId || a.MatchStrategy != b.MatchStrategy || a.MissingKeys != b.MissingKeys || !reflect.DeepEqual(a.Type, b.Type) {
+		return false
+	}
+
+	aContent, aErr := a.Template.Content()
+	bContent, bErr := b.Template.Content()
+	if aErr != nil || bErr != nil || aContent != bContent {
+		return false
+	}
+
+	if len(a.Parameters) != len(b.Parameters) {
+		return false
+	}
+	for name, aParam := range a.Parameters {
+		bParam, found := b.Parameters[name]
+		if !found || !parametersEqual(a.Coordinate, name, aParam, bParam) {
+			return false
+		}
+	}
+	return true
+}
+
+// parametersEqual compares parameters by their serialized definition, as parameters may hold state, like parsed
+// templates or file systems, that differs between loads of equal definitions
+func parametersEqual(c coordinate.Coordinate, name string, a, b parameter.Parameter) bool {
+	if a.GetType() != b.GetType() {
+		return false
+	}
+
+	serDe, found := config.DefaultParameterParsers[a.GetType()]
+	if !found {
+		return reflect.DeepEqual(a, b)
+	}
+
+	aSerialized, aErr := serDe.Serializer(parameter.ParameterWriterContext{Coordinate: c, ParameterName: name, Parameter: a})
+	bSerialized, bErr := serDe.Serializer(parameter.ParameterWriterContext{Coordinate: c, ParameterName: name, Parameter: b})
+	if aErr != nil || bErr != nil || !reflect.DeepEqual(aSerialized, bSerialized) {
+		return false
+	}
+
+	if aFile, ok := a.(*file.FileParameter); ok {
+		bFile := b.(*file.FileParameter)
+		aContent, aErr := afero.ReadFile(aFile.Fs, aFile.Path)
+		bContent, bErr := afero.ReadFile(bFile.Fs, bFile.Path)
+		return aErr == nil && bErr == nil && string(aContent) == string(bContent)
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/compound"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+)
+
+func testConfig(id string, content string, params config.Parameters) config.Config {
+	return config.Config{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: id},
+		Type:       config.ClassicApiType{Api: "alerting-profile"},
+		Template:   template.NewInMemoryTemplate(id, content),
+		Parameters: params,
+	}
+}
+
+func testProjects(configs ...config.Config) []project.Project {
+	return []project.Project{{
+		Id:      "p",
+		Configs: project.ConfigsPerTypePerEnvironments{"env": {"alerting-profile": configs}},
+	}}
+}
+
+func newCompound(t *testing.T, format string) parameter.Parameter {
+	p, err := compound.New("name", format, []parameter.ParameterReference{{Config: coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: "a"}, Property: "name"}})
+	require.NoError(t, err)
+	return p
+}
+
+func TestCompare(t *testing.T) {
+	base := testProjects(
+		testConfig("unchanged", "{}", config.Parameters{"name": newCompound(t, "{{ .name }}")}),
+		testConfig("template-changed", "{}", nil),
+		testConfig("parameter-changed", "{}", config.Parameters{"name": value.New("old")}),
+		testConfig("removed", "{}", nil),
+	)
+	current := testProjects(
+		testConfig("unchanged", "{}", config.Parameters{"name": newCompound(t, "{{ .name }}")}),
+		testConfig("template-changed", `{"enabled": true}`, nil),
+		testConfig("parameter-changed", "{}", config.Parameters{"name": value.New("new")}),
+		testConfig("added", "{}", nil),
+	)
+
+	changes := project.Compare(base, current)
+
+	coord := func(id string) coordinate.Coordinate {
+		return coordinate.Coordinate{Project: "p", Type: "alerting-profile", ConfigId: id}
+	}
+	assert.Equal(t, []project.ConfigChange{
+		{Environment: "env", Coordinate: coord("added"), Kind: project.ConfigAdded},
+		{Environment: "env", Coordinate: coord("parameter-changed"), Kind: project.ConfigChanged},
+		{Environment: "env", Coordinate: coord("removed"), Kind: project.ConfigRemoved},
+		{Environment: "env", Coordinate: coord("template-changed"), Kind: project.ConfigChanged},
+	}, changes)
+}
+
+func TestCompare_DetectsChangedFileContent(t *testing.T) {
+	baseFs, currentFs := afero.NewMemMapFs(), afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(baseFs, "file.txt", []byte("old"), 0644))
+	require.NoError(t, afero.WriteFile(currentFs, "file.txt", []byte("new"), 0644))
+
+	base := testProjects(testConfig("c", "{}", config.Parameters{"content": &file.FileParameter{Fs: baseFs, Path: "file.txt"}}))
+	current := testProjects(testConfig("c", "{}", config.Parameters{"content": &file.FileParameter{Fs: currentFs, Path: "file.txt"}}))
+
+	changes := project.Compare(base, current)
+	require.Len(t, changes, 1)
+	assert.Equal(t, project.ConfigChanged, changes[0].Kind)
+
+	assert.Empty(t, project.Compare(current, current))
+}
+
+func TestDependents(t *testing.T) {
+	projects := testProjects(
+		testConfig("a", "{}", nil),
+		testConfig("b", "{}", config.Parameters{"ref": reference.New("p", "alerting-profile", "a", "id")}),
+		testConfig("c", "{}", config.Parameters{"ref": reference.New("p", "alerting-profile", "b", "id")}),
+		testConfig("unrelated", "{}", nil),
+	)
+
+	got := project.Dependents(projects, "env", []coordinate.Coordinate{{Project: "p", Type: "alerting-profile", ConfigId: "a"}})
+
+	assert.Equal(t, []coordinate.Coordinate{
+		{Project: "p", Type: "alerting-profile", ConfigId: "a"},
+		{Project: "p", Type: "alerting-profile", ConfigId: "b"},
+		{Project: "p", Type: "alerting-profile", ConfigId: "c"},
+	}, got)
+}
@@ -184,6 +184,8 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 	log.Debug("Loading project `%s` (%s)...", projectDefinition.Name, projectDefinition.Path)
 
 	configs, errors := loadConfigsOfProject(fs, context, projectDefinition, environments)
+	errors = append(errors, resolveNameReferences(configs)...)
+	skipUntargetedConfigs(context.Manifest, configs)
 
 	if d := findDuplicatedConfigIdentifiers(configs); d != nil {
 		for _, c := range d {
@@ -203,6 +205,8 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 		}
 	}
 
+	errors = append(errors, checkDependencyBoundaries(context.Manifest.Projects, projectDefinition, configs)...)
+
 	if errors != nil {
 		return Project{}, errors
 	}
@@ -285,6 +289,60 @@ func loadConfigsOfProject(fs afero.Fs, loadingContext ProjectLoaderContext, proj
 	return configs, errs
 }
 
+// resolveNameReferences replaces all references identifying a config by its type and name with references to the
+// coordinate of that config. Names are resolved per environment, as configs can be named differently per environment.
+func resolveNameReferences(configs []config.Config) []error {
+	type key struct{ environment, configType, name string }
+	coordinatesByName := make(map[key][]coordinate.Coordinate)
+	for _, c := range configs {
+		name, err := config.GetNameForConfig(c)
+		if err != nil {
+			continue
+		}
+		if nameStr, ok := name.(string); ok {
+			k := key{c.Environment, c.Coordinate.Type, nameStr}
+			coordinatesByName[k] = append(coordinatesByName[k], c.Coordinate)
+		}
+	}
+
+	var errs []error
+	for _, c := range configs {
+		for paramName, p := range c.Parameters {
+			nameRef, ok := p.(*ref.NameReferenceParameter)
+			if !ok {
+				continue
+			}
+
+			switch coordinates := coordinatesByName[key{c.Environment, nameRef.ConfigType, nameRef.Name}]; len(coordinates) {
+			case 0:
+				errs = append(errs, fmt.Errorf("parameter %q of config '%s' references the %s config named %q, but there is no such config in environment %q", paramName, c.Coordinate, nameRef.ConfigType, nameRef.Name, c.Environment))
+			case 1:
+				c.Parameters[paramName] = ref.NewWithCoordinate(coordinates[0], nameRef.Property)
+			default:
+				errs = append(errs, fmt.Errorf("parameter %q of config '%s' references the %s config named %q, but there are several configs of that name in environment %q: %v - please reference the config by its 'configId'", paramName, c.Coordinate, nameRef.ConfigType, nameRef.Name, c.Environment, coordinates))
+			}
+		}
+	}
+	return errs
+}
+
+// skipUntargetedConfigs skips configs for the environments the targeting rules of the manifest exclude them from
+func skipUntargetedConfigs(m manifest.Manifest, configs []config.Config) {
+	if len(m.TargetingRules) == 0 {
+		return
+	}
+
+	for i, c := range configs {
+		env, found := m.Environments[c.Environment]
+		if c.Skip || !found || m.IsTargeted(c.Coordinate.Type, env) {
+			continue
+		}
+
+		log.WithFields(field.Coordinate(c.Coordinate), field.Environment(c.Environment, c.Group)).Debug("Skipping config %s for environment %q, as no targeting rule of type %q targets it", c.Coordinate, c.Environment, c.Coordinate.Type)
+		configs[i].Skip = true
+	}
+}
+
 func findDuplicatedConfigIdentifiers(configs []config.Config) []config.Config {
 
 	coordinates := make(map[string]struct{})
@@ -306,6 +364,49 @@ func toFullyQualifiedConfigIdentifier(config config.Config) string {
 	return fmt.Sprintf("%s:%s:%s", config.Group, config.Environment, config.Coordinate)
 }
 
+// checkDependencyBoundaries returns an error for every reference to a configuration of another project, if the project
+// declares the projects it depends on and the referenced project is not one of them
+func checkDependencyBoundaries(projects manifest.ProjectDefinitionByProjectID, definition manifest.ProjectDefinition, configs []config.Config) []error {
+	if definition.DependsOn == nil {
+		return nil
+	}
+
+	allowed := make(map[string]struct{})
+	for _, d := range definition.DependsOn {
+		allowed[d] = struct{}{}
+		// grouping projects allow references to all of their projects
+		for _, p := range projects {
+			if p.Group == d {
+				allowed[p.Name] = struct{}{}
+			}
+		}
+	}
+
+	var errs []error
+	reported := make(map[string]struct{})
+	for _, c := range configs {
+		if c.Skip {
+			continue
+		}
+
+		for _, ref := range c.References() {
+			if _, ok := allowed[ref.Project]; ok || ref.Project == definition.Name {
+				continue
+			}
+
+			// configurations are loaded per environment, but each crossed boundary is only reported once
+			key := c.Coordinate.String() + "->" + ref.String()
+			if _, found := reported[key]; found {
+				continue
+			}
+			reported[key] = struct{}{}
+
+			errs = append(errs, fmt.Errorf("config '%s' references config '%s', but project '%s' does not declare a dependency on project '%s' in 'dependsOn'", c.Coordinate, ref, definition.Name, ref.Project))
+		}
+	}
+	return errs
+}
+
 func toDependenciesMap(projectId string, configs []config.Config) DependenciesPerEnvironment {
 	result := make(DependenciesPerEnvironment)
 
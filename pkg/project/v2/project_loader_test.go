@@ -22,12 +22,14 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/testutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	ref "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"io/fs"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -1360,3 +1362,306 @@ func TestLoadProjects_CircularDependencies(t *testing.T) {
 	require.Len(t, gotErrs, 0, "Expected no errors loading dependent projects")
 	requireProjectsWithNames(t, gotProjects, "b", "a")
 }
+
+func TestLoadProjects_DependencyBoundaries(t *testing.T) {
+	configWithReferenceTo := func(project string) []byte {
+		return []byte(`configs:
+- id: mz
+  config:
+    template: mz.json
+    parameters:
+      mzId:
+        type: reference
+        project: ` + project + `
+        configType: builtin:management-zones
+        configId: mz
+        property: id
+  type:
+    settings:
+      schema: builtin:management-zones
+      schemaVersion: 1.0.9
+      scope: environment`)
+	}
+	managementZoneConfig := []byte(`configs:
+- id: mz
+  config:
+    template: mz.json
+  type:
+    settings:
+      schema: builtin:management-zones
+      schemaVersion: 1.0.9
+      scope: environment`)
+	managementZoneJSON := []byte(`{ "name": "", "rules": [] }`)
+
+	testFs := testutils.TempFs(t)
+	for path, content := range map[string][]byte{
+		"team/a/builtinmanagement-zones/config.yaml": managementZoneConfig,
+		"b/builtinmanagement-zones/config.yaml":      configWithReferenceTo("team.a"),
+	} {
+		require.NoError(t, testFs.MkdirAll(filepath.Dir(path), testDirectoryFileMode))
+		require.NoError(t, afero.WriteFile(testFs, path, content, testFileFileMode))
+		require.NoError(t, afero.WriteFile(testFs, filepath.Join(filepath.Dir(path), "mz.json"), managementZoneJSON, testFileFileMode))
+	}
+
+	tests := []struct {
+		name          string
+		dependsOn     []string
+		wantErrorPart string
+	}{
+		{
+			name: "no declared dependencies allow any reference",
+		},
+		{
+			name:      "dependency on the referenced project",
+			dependsOn: []string{"team.a"},
+		},
+		{
+			name:      "dependency on the grouping project of the referenced project",
+			dependsOn: []string{"team"},
+		},
+		{
+			name:          "dependency on other projects",
+			dependsOn:     []string{"c"},
+			wantErrorPart: "config 'b:builtin:management-zones:mz' references config 'team.a:builtin:management-zones:mz', but project 'b' does not declare a dependency on project 'team.a' in 'dependsOn'",
+		},
+		{
+			name:          "no dependencies",
+			dependsOn:     []string{},
+			wantErrorPart: "project 'b' does not declare a dependency on project 'team.a'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testContext := ProjectLoaderContext{
+				KnownApis:  map[string]struct{}{"builtin:management-zones": {}},
+				WorkingDir: ".",
+				Manifest: manifest.Manifest{
+					Projects: manifest.ProjectDefinitionByProjectID{
+						"team.a": {Name: "team.a", Group: "team", Path: "team/a"},
+						"b":      {Name: "b", Path: "b", DependsOn: tt.dependsOn},
+						"c":      {Name: "c", Path: "b"},
+					},
+					Environments: manifest.Environments{
+						"dev":  {Name: "dev", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+						"prod": {Name: "prod", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+					},
+				},
+				ParametersSerde: config.DefaultParameterParsers,
+			}
+
+			gotProjects, gotErrs := LoadProjects(testFs, testContext, []string{"b"})
+			if tt.wantErrorPart != "" {
+				require.Len(t, gotErrs, 1, "crossed boundaries are reported once for all environments")
+				assert.ErrorContains(t, gotErrs[0], tt.wantErrorPart)
+				return
+			}
+			require.Empty(t, gotErrs)
+			requireProjectsWithNames(t, gotProjects, "b", "team.a")
+		})
+	}
+}
+
+func TestLoadProjects_ResolvesReferencesByName(t *testing.T) {
+	profiles := []byte(`configs:
+- id: profile-a
+  config:
+    name: Production
+    template: t.json
+  type:
+    api: alerting-profile
+- id: profile-c
+  config:
+    name: Test
+    template: t.json
+  type:
+    api: alerting-profile
+- id: profile-b
+  config:
+    name: Staging
+    template: t.json
+  type:
+    api: alerting-profile
+  environmentOverrides:
+  - environment: prod
+    override:
+      name: Production
+`)
+	dashboardReferencing := func(name string) []byte {
+		return []byte(`configs:
+- id: dashboard
+  config:
+    name: Dashboard
+    template: t.json
+    parameters:
+      profileId:
+        type: reference
+        configType: alerting-profile
+        name: ` + name + `
+        property: id
+  type:
+    api: dashboard
+`)
+	}
+
+	tests := []struct {
+		name           string
+		referencedName string
+		wantErrorParts []string
+	}{
+		{
+			name:           "reference is replaced by reference to the config ID",
+			referencedName: "Test",
+		},
+		{
+			name:           "names are resolved per environment",
+			referencedName: "Staging",
+			wantErrorParts: []string{
+				// profile-b is named Production in environment prod
+				`parameter "profileId" of config 'project:dashboard:dashboard' references the alerting-profile config named "Staging", but there is no such config in environment "prod"`,
+			},
+		},
+		{
+			name:           "ambiguous names are reported",
+			referencedName: "Production",
+			wantErrorParts: []string{
+				`parameter "profileId" of config 'project:dashboard:dashboard' references the alerting-profile config named "Production", but there are several configs of that name in environment "prod"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFs := afero.NewMemMapFs()
+			for path, content := range map[string][]byte{
+				"project/alerting-profile/config.yaml": profiles,
+				"project/dashboard/config.yaml":        dashboardReferencing(tt.referencedName),
+			} {
+				require.NoError(t, afero.WriteFile(testFs, path, content, testFileFileMode))
+				require.NoError(t, afero.WriteFile(testFs, filepath.Join(filepath.Dir(path), "t.json"), []byte("{}"), testFileFileMode))
+			}
+
+			testContext := ProjectLoaderContext{
+				KnownApis:  map[string]struct{}{"alerting-profile": {}, "dashboard": {}},
+				WorkingDir: ".",
+				Manifest: manifest.Manifest{
+					Projects: manifest.ProjectDefinitionByProjectID{
+						"project": {Name: "project", Path: "project"},
+					},
+					Environments: manifest.Environments{
+						"dev":  {Name: "dev", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+						"prod": {Name: "prod", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+					},
+				},
+				ParametersSerde: config.DefaultParameterParsers,
+			}
+
+			gotProjects, gotErrs := LoadProjects(testFs, testContext, nil)
+			require.Len(t, gotErrs, len(tt.wantErrorParts))
+			for i, part := range tt.wantErrorParts {
+				assert.ErrorContains(t, gotErrs[i], part)
+			}
+			if len(tt.wantErrorParts) > 0 {
+				return
+			}
+
+			for _, env := range []string{"dev", "prod"} {
+				dashboard := gotProjects[0].Configs[env]["dashboard"][0]
+				assert.Equal(t, ref.New("project", "alerting-profile", "profile-c", "id"), dashboard.Parameters["profileId"])
+			}
+		})
+	}
+}
+
+func Test_resolveNameReferences(t *testing.T) {
+	profile := config.Config{
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile-a"},
+		Environment: "dev",
+		Parameters:  config.Parameters{config.NameParameter: value.New("Production")},
+	}
+	dashboard := config.Config{
+		Coordinate:  coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"},
+		Environment: "dev",
+		Parameters: config.Parameters{
+			config.NameParameter: value.New("Dashboard"),
+			"profileId":          ref.NewByName("alerting-profile", "Production", "id"),
+		},
+	}
+
+	errs := resolveNameReferences([]config.Config{profile, dashboard})
+	require.Empty(t, errs)
+	assert.Equal(t, ref.NewWithCoordinate(profile.Coordinate, "id"), dashboard.Parameters["profileId"])
+	assert.Equal(t, []coordinate.Coordinate{profile.Coordinate}, dashboard.References())
+}
+
+func TestLoadProjects_TargetingRules(t *testing.T) {
+	managementZoneConfig := []byte(`configs:
+- id: mz
+  config:
+    template: mz.json
+  type:
+    settings:
+      schema: builtin:management-zones
+      schemaVersion: 1.0.9
+      scope: environment`)
+
+	testFs := testutils.TempFs(t)
+	require.NoError(t, testFs.MkdirAll("a/builtinmanagement-zones", testDirectoryFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "a/builtinmanagement-zones/config.yaml", managementZoneConfig, testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "a/builtinmanagement-zones/mz.json", []byte(`{ "name": "", "rules": [] }`), testFileFileMode))
+
+	loadSkipped := func(t *testing.T, rules []manifest.TargetingRule) map[string]bool {
+		gotProjects, gotErrs := LoadProjects(testFs, ProjectLoaderContext{
+			KnownApis:  map[string]struct{}{"builtin:management-zones": {}},
+			WorkingDir: ".",
+			Manifest: manifest.Manifest{
+				Projects: manifest.ProjectDefinitionByProjectID{
+					"a": {Name: "a", Path: "a"},
+				},
+				Environments: manifest.Environments{
+					"dev":    {Name: "dev", Group: "internal", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+					"prod":   {Name: "prod", Group: "public", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+					"public": {Name: "public", Group: "public", Auth: manifest.Auth{Token: manifest.AuthSecret{Name: "ENV_VAR"}}},
+				},
+				TargetingRules: rules,
+			},
+			ParametersSerde: config.DefaultParameterParsers,
+		}, nil)
+		require.Empty(t, gotErrs)
+		require.Len(t, gotProjects, 1)
+
+		skipped := make(map[string]bool)
+		for env, configsPerType := range gotProjects[0].Configs {
+			for _, c := range configsPerType["builtin:management-zones"] {
+				skipped[env] = c.Skip
+			}
+		}
+		return skipped
+	}
+
+	t.Run("configs are deployed everywhere without rules", func(t *testing.T) {
+		assert.Equal(t, map[string]bool{"dev": false, "prod": false, "public": false}, loadSkipped(t, nil))
+	})
+
+	t.Run("configs are skipped for environments that are not targeted", func(t *testing.T) {
+		got := loadSkipped(t, []manifest.TargetingRule{
+			{ConfigTypes: []string{"builtin:management-*"}, Groups: []string{"public"}},
+		})
+		assert.Equal(t, map[string]bool{"dev": true, "prod": false, "public": false}, got)
+	})
+
+	t.Run("all rules applying to a type must target the environment", func(t *testing.T) {
+		got := loadSkipped(t, []manifest.TargetingRule{
+			{ConfigTypes: []string{"builtin:management-zones"}, Groups: []string{"public"}},
+			{ConfigTypes: []string{"builtin:*"}, Environments: []string{"dev", "prod"}},
+		})
+		assert.Equal(t, map[string]bool{"dev": true, "prod": false, "public": true}, got)
+	})
+
+	t.Run("rules of other types don't affect configs", func(t *testing.T) {
+		got := loadSkipped(t, []manifest.TargetingRule{
+			{ConfigTypes: []string{"synthetic-monitor"}, Groups: []string{"public"}},
+		})
+		assert.Equal(t, map[string]bool{"dev": false, "prod": false, "public": false}, got)
+	})
+}
@@ -0,0 +1,131 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/throttle"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
+)
+
+// adaptiveRateLimitStrategy proactively throttles requests based on the 'X-RateLimit-Remaining' and 'X-RateLimit-Reset'
+// headers returned by Dynatrace APIs. It acts as a token bucket refilled by every response: once no requests remain,
+// all requests using the strategy wait until the reported reset time. As the strategy is safe for concurrent use, a
+// single instance should be shared by all clients targeting the same environment.
+// Should a request still be rate limited, it is retried using the simpleSleepRateLimitStrategy.
+type adaptiveRateLimitStrategy struct {
+	mutex sync.Mutex
+	// known is true, if a previous response reported the rate limit state
+	known bool
+	// remaining is the number of requests which may still be sent until reset
+	remaining int
+	// reset is the time at which the server resets the rate limit
+	reset time.Time
+
+	onTooManyRequests simpleSleepRateLimitStrategy
+}
+
+func (s *adaptiveRateLimitStrategy) ExecuteRequest(ctx context.Context, timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error) {
+	return s.onTooManyRequests.ExecuteRequest(ctx, timelineProvider, func() (Response, error) {
+		if err := s.acquire(ctx, timelineProvider); err != nil {
+			return Response{}, err
+		}
+
+		response, err := callback()
+		if err == nil {
+			s.update(response)
+		}
+		return response, err
+	})
+}
+
+// acquire takes a token from the bucket, waiting for the rate limit to be reset if none remain. If ctx is done while
+// waiting, its error is returned.
+func (s *adaptiveRateLimitStrategy) acquire(ctx context.Context, timelineProvider timeutils.TimelineProvider) error {
+	for {
+		s.mutex.Lock()
+		if !s.known || s.remaining > 0 {
+			s.remaining--
+			s.mutex.Unlock()
+			return nil
+		}
+
+		reset := s.reset
+		sleepDuration := reset.Sub(timelineProvider.Now())
+		if sleepDuration <= 0 {
+			// the limit was reset, until the next response reports the new state requests are not throttled
+			s.known = false
+			s.mutex.Unlock()
+			return nil
+		}
+		s.mutex.Unlock()
+
+		// Attention: the reset time is server time, so ensure plausible wait times in case of clock skew
+		sleepDuration = throttle.ApplyMinMaxDefaults(sleepDuration)
+		log.Debug("No requests remaining until rate limit is reset. Sleeping until %s (%s)", reset.Format(time.RFC3339), sleepDuration)
+		if err := timelineProvider.Sleep(ctx, sleepDuration); err != nil {
+			return fmt.Errorf("cancelled while waiting for the rate limit to be reset: %w", err)
+		}
+	}
+}
+
+// update refills the bucket from the rate limit headers of the response. Headers of responses for earlier rate limit
+// periods can't refill the bucket, as concurrent requests may return out of order.
+func (s *adaptiveRateLimitStrategy) update(response Response) {
+	remaining, reset, ok := extractRemainingRequests(response)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.known || reset.After(s.reset) {
+		s.known = true
+		s.remaining = remaining
+		s.reset = reset
+		return
+	}
+	if reset.Equal(s.reset) {
+		s.remaining = min(s.remaining, remaining)
+	}
+}
+
+func extractRemainingRequests(response Response) (remaining int, reset time.Time, ok bool) {
+	remainingHeader := http.Header(response.Headers).Get("X-RateLimit-Remaining")
+	resetHeader := http.Header(response.Headers).Get("X-RateLimit-Reset")
+	if remainingHeader == "" || resetHeader == "" {
+		return 0, time.Time{}, false
+	}
+
+	remaining, err := strconv.Atoi(remainingHeader)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	resetInMicroseconds, err := strconv.ParseInt(resetHeader, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return remaining, timeutils.ConvertMicrosecondsToUnixTime(resetInMicroseconds), true
+}
@@ -0,0 +1,154 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeTimelineProvider advances its time when sleeping, unless the context is done
+type fakeTimelineProvider struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (f *fakeTimelineProvider) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeTimelineProvider) Sleep(ctx context.Context, duration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.sleeps = append(f.sleeps, duration)
+	f.now = f.now.Add(duration)
+	return nil
+}
+
+func rateLimitedResponse(remaining int, reset time.Time) Response {
+	return Response{
+		StatusCode: http.StatusOK,
+		Headers: map[string][]string{
+			"X-Ratelimit-Remaining": {strconv.Itoa(remaining)},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(reset.UnixMicro(), 10)},
+		},
+	}
+}
+
+func TestAdaptiveRateLimitStrategy_DoesNotThrottleWithoutHeaders(t *testing.T) {
+	strategy := adaptiveRateLimitStrategy{}
+	timeline := &fakeTimelineProvider{now: time.Unix(0, 0)}
+
+	for i := 0; i < 10; i++ {
+		_, err := strategy.ExecuteRequest(context.TODO(), timeline, func() (Response, error) {
+			return Response{StatusCode: http.StatusOK}, nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Empty(t, timeline.sleeps)
+}
+
+func TestAdaptiveRateLimitStrategy_ThrottlesOnceNoRequestsRemain(t *testing.T) {
+	strategy := adaptiveRateLimitStrategy{}
+	timeline := &fakeTimelineProvider{now: time.Unix(0, 0)}
+	reset := timeline.now.Add(10 * time.Second)
+
+	calls := 0
+	send := func() {
+		_, err := strategy.ExecuteRequest(context.TODO(), timeline, func() (Response, error) {
+			calls++
+			if timeline.now.Before(reset) {
+				return rateLimitedResponse(2, reset), nil
+			}
+			return Response{StatusCode: http.StatusOK}, nil
+		})
+		require.NoError(t, err)
+	}
+
+	send() // learns that 2 requests remain
+	send()
+	send()
+	assert.Empty(t, timeline.sleeps)
+
+	send()
+	assert.Equal(t, []time.Duration{10 * time.Second}, timeline.sleeps)
+	assert.Equal(t, 4, calls)
+
+	send()
+	assert.Len(t, timeline.sleeps, 1, "limit was reset and must not throttle anymore")
+}
+
+func TestAdaptiveRateLimitStrategy_StopsWaitingWhenContextIsDone(t *testing.T) {
+	strategy := adaptiveRateLimitStrategy{}
+	timeline := &fakeTimelineProvider{now: time.Unix(0, 0)}
+	strategy.update(rateLimitedResponse(0, timeline.now.Add(10*time.Second)))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	called := false
+	_, err := strategy.ExecuteRequest(ctx, timeline, func() (Response, error) {
+		called = true
+		return Response{StatusCode: http.StatusOK}, nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, called, "request must not be sent after the context is done")
+	assert.Empty(t, timeline.sleeps)
+}
+
+func TestAdaptiveRateLimitStrategy_IgnoresHeadersOfPreviousPeriods(t *testing.T) {
+	strategy := adaptiveRateLimitStrategy{}
+	now := time.Unix(0, 0)
+
+	strategy.update(rateLimitedResponse(5, now.Add(time.Minute)))
+	strategy.update(rateLimitedResponse(50, now))
+	assert.Equal(t, 5, strategy.remaining)
+
+	strategy.update(rateLimitedResponse(3, now.Add(time.Minute)))
+	strategy.update(rateLimitedResponse(4, now.Add(time.Minute)))
+	assert.Equal(t, 3, strategy.remaining)
+
+	strategy.update(rateLimitedResponse(100, now.Add(2*time.Minute)))
+	assert.Equal(t, 100, strategy.remaining)
+}
+
+func TestAdaptiveRateLimitStrategy_RetriesTooManyRequests(t *testing.T) {
+	strategy := adaptiveRateLimitStrategy{}
+	timeline := &fakeTimelineProvider{now: time.Unix(0, 0)}
+
+	calls := 0
+	resp, err := strategy.ExecuteRequest(context.TODO(), timeline, func() (Response, error) {
+		calls++
+		if calls == 1 {
+			return Response{StatusCode: http.StatusTooManyRequests, Headers: createTestHeaders(5 * time.Second.Microseconds())}, nil
+		}
+		return Response{StatusCode: http.StatusOK}, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []time.Duration{5 * time.Second}, timeline.sleeps)
+}
@@ -28,20 +28,23 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"time"
 )
 
 type Client struct {
 	client            *http.Client
 	rateLimitStrategy RateLimitStrategy
 	trafficLogger     *trafficlogs.FileBasedLogger
+	middlewares       []Middleware
 }
 
-func NewRestClient(client *http.Client, trafficLogger *trafficlogs.FileBasedLogger, strategy RateLimitStrategy) *Client {
+// NewRestClient creates a new Client. The given middlewares intercept every request in the order they are passed,
+// their OnResponse hooks are called in reverse order.
+func NewRestClient(client *http.Client, trafficLogger *trafficlogs.FileBasedLogger, strategy RateLimitStrategy, middlewares ...Middleware) *Client {
 	return &Client{
 		client:            client,
 		rateLimitStrategy: strategy,
 		trafficLogger:     trafficLogger,
+		middlewares:       middlewares,
 	}
 }
 func (c Client) Client() *http.Client {
@@ -65,13 +68,15 @@ func (c Client) GetWithRetry(ctx context.Context, url string, settings RetrySett
 		return resp, nil
 	}
 
-	for i := 0; i < settings.MaxRetries; i++ {
+	for i := 0; i < settings.MaxRetries && settings.isRetriable(resp, err); i++ {
 		if err != nil {
 			log.WithCtxFields(ctx).WithFields(field.Error(err)).Warn("Retrying failed GET request %s with error: %v", url, err)
 		} else {
 			log.WithCtxFields(ctx).Warn("Retrying failed GET request %s (HTTP %d)", url, resp.StatusCode)
 		}
-		time.Sleep(settings.WaitTime)
+		if sleepErr := timeutils.SleepWithContext(ctx, settings.waitTimeBeforeRetry(i)); sleepErr != nil {
+			return resp, fmt.Errorf("GET request %s cancelled while waiting to retry: %w", url, sleepErr)
+		}
 		resp, err = c.Get(ctx, url)
 		if err == nil && resp.IsSuccess() {
 			return resp, err
@@ -150,6 +155,27 @@ func (c Client) executeRequest(request *http.Request) (Response, error) {
 
 	request.Header.Set("User-Agent", "Dynatrace-config-as-code-http-client")
 
+	for i, m := range c.middlewares {
+		if err := m.OnRequest(request); err != nil {
+			// middlewares which already saw the request are notified about its failure
+			c.notifyMiddlewares(c.middlewares[:i], request, Response{}, err)
+			return Response{}, fmt.Errorf("request %s %s aborted by middleware: %w", request.Method, request.URL, err)
+		}
+	}
+
+	response, err := c.sendRequest(request)
+	c.notifyMiddlewares(c.middlewares, request, response, err)
+	return response, err
+}
+
+func (c Client) notifyMiddlewares(middlewares []Middleware, request *http.Request, response Response, err error) {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middlewares[i].OnResponse(request, response, err)
+	}
+}
+
+func (c Client) sendRequest(request *http.Request) (Response, error) {
+
 	// extract request body for logging before executing the request drains it
 	var reqBody string
 	if c.trafficLogger != nil && request.Body != nil {
@@ -161,7 +187,7 @@ func (c Client) executeRequest(request *http.Request) (Response, error) {
 		}
 	}
 
-	response, err := c.rateLimitStrategy.ExecuteRequest(timeutils.NewTimelineProvider(), func() (Response, error) {
+	response, err := c.rateLimitStrategy.ExecuteRequest(request.Context(), timeutils.NewTimelineProvider(), func() (Response, error) {
 		resp, err := c.client.Do(request)
 		if err != nil {
 			if isConnectionResetErr(err) {
@@ -0,0 +1,254 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/trafficlogs"
+	"github.com/spf13/afero"
+)
+
+// DumpFormat defines the file format HTTP traffic is dumped in by an HTTPDump
+type DumpFormat string
+
+const (
+	// DumpFormatNDJSON writes one JSON object per request/response pair and line, as soon as the request is done
+	DumpFormatNDJSON DumpFormat = "ndjson"
+	// DumpFormatHAR writes an HTTP Archive (HAR 1.2) once the HTTPDump is closed
+	DumpFormatHAR DumpFormat = "har"
+)
+
+// DumpFormats contains all supported DumpFormat values
+var DumpFormats = []DumpFormat{DumpFormatNDJSON, DumpFormatHAR}
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are never written in plain text
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization", "X-Api-Key"}
+
+// HTTPDump is a Middleware writing all request/response pairs to a file, to diagnose API failures. Authentication
+// headers are redacted and secret values masked.
+type HTTPDump struct {
+	fs     afero.Fs
+	path   string
+	format DumpFormat
+
+	mutex   sync.Mutex
+	started map[*http.Request]time.Time
+	file    afero.File
+	entries []dumpEntry
+}
+
+// this forces the compiler to check if HTTPDump is of type Middleware
+var _ Middleware = (*HTTPDump)(nil)
+
+// NewHTTPDump creates an HTTPDump writing to a file named after the current execution time in the given directory
+func NewHTTPDump(fs afero.Fs, dir string, format DumpFormat) (*HTTPDump, error) {
+	if !slices.Contains(DumpFormats, format) {
+		return nil, fmt.Errorf("unknown HTTP dump format %q, must be one of %q", format, DumpFormats)
+	}
+	if err := fs.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("failed to create HTTP dump directory %q: %w", dir, err)
+	}
+
+	return &HTTPDump{
+		fs:      fs,
+		path:    filepath.Join(dir, timeutils.TimeAnchor().Format(trafficlogs.TrafficLogFilePrefixFormat)+"-http."+string(format)),
+		format:  format,
+		started: make(map[*http.Request]time.Time),
+	}, nil
+}
+
+// Path returns the path of the file the traffic is dumped to
+func (d *HTTPDump) Path() string {
+	return d.path
+}
+
+func (d *HTTPDump) OnRequest(req *http.Request) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.started[req] = time.Now()
+	return nil
+}
+
+func (d *HTTPDump) OnResponse(req *http.Request, resp Response, err error) {
+	d.mutex.Lock()
+	started, ok := d.started[req]
+	delete(d.started, req)
+	d.mutex.Unlock()
+	if !ok {
+		started = time.Now()
+	}
+
+	entry := newDumpEntry(req, started, resp, err)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.format == DumpFormatHAR {
+		d.entries = append(d.entries, entry)
+		return
+	}
+	if writeErr := d.writeLine(entry); writeErr != nil {
+		log.WithFields(field.Error(writeErr)).Warn("Failed to dump HTTP traffic to %q: %v", d.path, writeErr)
+	}
+}
+
+func (d *HTTPDump) writeLine(entry dumpEntry) error {
+	if d.file == nil {
+		f, err := d.fs.Create(d.path)
+		if err != nil {
+			return err
+		}
+		d.file = f
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = d.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close finishes the dump. For the HAR format, this writes all recorded entries.
+func (d *HTTPDump) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.format == DumpFormatHAR {
+		return d.writeHAR()
+	}
+	if d.file == nil {
+		return nil
+	}
+	return d.file.Close()
+}
+
+func (d *HTTPDump) writeHAR() error {
+	har := map[string]any{
+		"log": map[string]any{
+			"version": "1.2",
+			"creator": map[string]string{"name": "monaco", "version": ""},
+			"entries": d.entries,
+		},
+	}
+	content, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(d.fs, d.path, content, 0644)
+}
+
+// dumpEntry is a request/response pair, structured like the entries of a HAR file
+type dumpEntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         dumpRequest  `json:"request"`
+	Response        dumpResponse `json:"response"`
+	// Comment holds the error a request failed with, if any
+	Comment string `json:"comment,omitempty"`
+}
+
+type dumpRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []dumpHeader `json:"headers"`
+	PostData    *dumpContent `json:"postData,omitempty"`
+}
+
+type dumpResponse struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []dumpHeader `json:"headers"`
+	Content     dumpContent  `json:"content"`
+}
+
+type dumpHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type dumpContent struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+func newDumpEntry(req *http.Request, started time.Time, resp Response, err error) dumpEntry {
+	e := dumpEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            float64(time.Since(started).Microseconds()) / 1000,
+		Request: dumpRequest{
+			Method:      req.Method,
+			URL:         secret.Mask(req.URL.String()),
+			HTTPVersion: req.Proto,
+			Headers:     toDumpHeaders(req.Header),
+		},
+		Response: dumpResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: req.Proto,
+			Headers:     toDumpHeaders(resp.Headers),
+			Content: dumpContent{
+				MimeType: http.Header(resp.Headers).Get("Content-Type"),
+				Text:     secret.Mask(string(resp.Body)),
+			},
+		},
+	}
+
+	// the body of sent requests is drained, so read it from a copy if possible
+	if req.GetBody != nil {
+		if body, bodyErr := req.GetBody(); bodyErr == nil {
+			if b, readErr := io.ReadAll(body); readErr == nil && len(b) > 0 {
+				e.Request.PostData = &dumpContent{MimeType: req.Header.Get("Content-Type"), Text: secret.Mask(string(b))}
+			}
+		}
+	}
+
+	if err != nil {
+		e.Comment = secret.Mask(err.Error())
+	}
+	return e
+}
+
+func toDumpHeaders(h map[string][]string) []dumpHeader {
+	headers := make([]dumpHeader, 0, len(h))
+	for name, values := range h {
+		for _, v := range values {
+			if slices.Contains(sensitiveHeaders, http.CanonicalHeaderKey(name)) {
+				v = redacted
+			}
+			headers = append(headers, dumpHeader{Name: name, Value: secret.Mask(v)})
+		}
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	return headers
+}
@@ -0,0 +1,137 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newDumpTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Set-Cookie", "session=abc")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(`{"id": "42"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPDump_NDJSON(t *testing.T) {
+	server := newDumpTestServer(t)
+	fs := afero.NewMemMapFs()
+	dump, err := NewHTTPDump(fs, "dump", DumpFormatNDJSON)
+	require.NoError(t, err)
+
+	secret.RegisterMaskedValue("my-super-secret")
+	restClient := NewRestClient(server.Client(), nil, CreateRateLimitStrategy(), RequestInterceptor(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Api-Token dt0c01.secret")
+		return nil
+	}), dump)
+
+	_, err = restClient.Post(context.Background(), server.URL+"/api/config", []byte(`{"password": "my-super-secret"}`))
+	require.NoError(t, err)
+	_, err = restClient.Get(context.Background(), server.URL+"/api/config/42")
+	require.NoError(t, err)
+	require.NoError(t, dump.Close())
+
+	content, err := afero.ReadFile(fs, dump.Path())
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var entry dumpEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, http.MethodPost, entry.Request.Method)
+	assert.Equal(t, server.URL+"/api/config", entry.Request.URL)
+	assert.Contains(t, entry.Request.Headers, dumpHeader{Name: "Authorization", Value: redacted})
+	require.NotNil(t, entry.Request.PostData)
+	assert.NotContains(t, entry.Request.PostData.Text, "my-super-secret")
+	assert.Equal(t, http.StatusCreated, entry.Response.Status)
+	assert.Contains(t, entry.Response.Headers, dumpHeader{Name: "Set-Cookie", Value: redacted})
+	assert.Equal(t, `{"id": "42"}`, entry.Response.Content.Text)
+	assert.NotContains(t, string(content), "dt0c01.secret")
+
+	var getEntry dumpEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &getEntry))
+	assert.Equal(t, http.MethodGet, getEntry.Request.Method)
+	assert.Nil(t, getEntry.Request.PostData)
+}
+
+func TestHTTPDump_HAR(t *testing.T) {
+	server := newDumpTestServer(t)
+	fs := afero.NewMemMapFs()
+	dump, err := NewHTTPDump(fs, "dump", DumpFormatHAR)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(dump.Path(), ".har"))
+
+	restClient := NewRestClient(server.Client(), nil, CreateRateLimitStrategy(), dump)
+	_, err = restClient.Delete(context.Background(), server.URL+"/api/config/42")
+	require.NoError(t, err)
+
+	exists, err := afero.Exists(fs, dump.Path())
+	require.NoError(t, err)
+	assert.False(t, exists, "HAR must only be written once the dump is closed")
+
+	require.NoError(t, dump.Close())
+
+	content, err := afero.ReadFile(fs, dump.Path())
+	require.NoError(t, err)
+	var har struct {
+		Log struct {
+			Version string      `json:"version"`
+			Entries []dumpEntry `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(content, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 1)
+	assert.Equal(t, http.MethodDelete, har.Log.Entries[0].Request.Method)
+}
+
+func TestHTTPDump_RecordsFailedRequests(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	dump, err := NewHTTPDump(fs, "dump", DumpFormatNDJSON)
+	require.NoError(t, err)
+
+	restClient := NewRestClient(http.DefaultClient, nil, CreateRateLimitStrategy(), dump)
+	_, err = restClient.Get(context.Background(), "http://localhost:0/unreachable")
+	require.Error(t, err)
+	require.NoError(t, dump.Close())
+
+	content, err := afero.ReadFile(fs, dump.Path())
+	require.NoError(t, err)
+	var entry dumpEntry
+	require.NoError(t, json.Unmarshal(content, &entry))
+	assert.Contains(t, entry.Comment, "HTTP request failed")
+}
+
+func TestNewHTTPDump_UnknownFormat(t *testing.T) {
+	_, err := NewHTTPDump(afero.NewMemMapFs(), "dump", "xml")
+	assert.ErrorContains(t, err, `unknown HTTP dump format "xml"`)
+}
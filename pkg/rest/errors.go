@@ -17,7 +17,15 @@
 package rest
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/environment"
 )
 
@@ -33,6 +41,9 @@ type RespError struct {
 	Err error `json:"error"`
 	// Request contains information about the HTTP request that caused the RespError
 	Request *RequestInfo `json:"request"`
+
+	// retryAfter is the time to wait before retrying a rate limited request, as stated by the response headers
+	retryAfter time.Duration
 }
 
 type RequestInfo struct {
@@ -43,11 +54,15 @@ type RequestInfo struct {
 }
 
 func NewRespErr(reason string, resp Response) RespError {
-	return RespError{
+	e := RespError{
 		Reason:     reason,
 		StatusCode: resp.StatusCode,
 		Body:       string(resp.Body),
 	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		e.retryAfter = retryAfter(resp.Headers, time.Now())
+	}
+	return e
 }
 
 func (e RespError) WithErr(err error) RespError {
@@ -78,8 +93,219 @@ func (e RespError) ConcurrentError() string {
 	if e.StatusCode == 403 {
 		concurrentDownloadLimit := environment.GetEnvValueInt(environment.ConcurrentRequestsEnvKey)
 		additionalMessage := fmt.Sprintf("\n\n    A 403 error code probably means too many requests.\n    Reduce the number of concurrent requests by setting the %q environment variable (current value: %d). \n    Then wait a few minutes and retry ", environment.ConcurrentRequestsEnvKey, concurrentDownloadLimit)
-		return fmt.Sprintf("%s\n%s", e.Err.Error(), additionalMessage)
+		return fmt.Sprintf("%s\n%s", e.Error(), additionalMessage)
 	}
 
 	return e.Error()
 }
+
+// As converts the RespError to the typed error matching its status code, so that callers can use errors.As to find out
+// why a request failed, e.g.
+//
+//	var notFound rest.NotFoundError
+//	if errors.As(err, &notFound) { ... }
+func (e RespError) As(target any) bool {
+	switch t := target.(type) {
+	case *RateLimitedError:
+		if e.StatusCode != http.StatusTooManyRequests {
+			return false
+		}
+		*t = RateLimitedError{RespError: e, RetryAfter: e.retryAfter}
+		return true
+	case *SchemaValidationError:
+		if e.StatusCode != http.StatusBadRequest {
+			return false
+		}
+		*t = SchemaValidationError{RespError: e, Violations: constraintViolations(e.Body)}
+		return true
+	case *MissingScopeError:
+		if e.StatusCode != http.StatusForbidden || !strings.Contains(strings.ToLower(e.Body), "scope") {
+			return false
+		}
+		*t = MissingScopeError{RespError: e, RequiredScope: requiredScope(e.Body)}
+		return true
+	case *NotFoundError:
+		if e.StatusCode != http.StatusNotFound {
+			return false
+		}
+		*t = NotFoundError{RespError: e}
+		return true
+	case *ConflictError:
+		if e.StatusCode != http.StatusConflict {
+			return false
+		}
+		*t = ConflictError{RespError: e}
+		return true
+	}
+	return false
+}
+
+// RateLimitedError is a RespError of a request rejected as too many requests were sent (HTTP 429).
+type RateLimitedError struct {
+	RespError
+	// RetryAfter is the time to wait before retrying the request. Zero if the API did not state it.
+	RetryAfter time.Duration
+}
+
+func (e RateLimitedError) Unwrap() error {
+	return e.RespError
+}
+
+// Hint returns a remediation hint for the error
+func (e RateLimitedError) Hint() string {
+	return fmt.Sprintf("The API rate limit was exceeded. Reduce the number of concurrent requests by setting the %q environment variable, then wait a few minutes and retry.", environment.ConcurrentRequestsEnvKey)
+}
+
+// SchemaValidationError is a RespError of a request whose payload was rejected by the API (HTTP 400).
+type SchemaValidationError struct {
+	RespError
+	// Violations lists the constraint violations reported by the API, if any
+	Violations []string
+}
+
+func (e SchemaValidationError) Unwrap() error {
+	return e.RespError
+}
+
+// Hint returns a remediation hint for the error
+func (e SchemaValidationError) Hint() string {
+	if len(e.Violations) == 0 {
+		return "The API rejected the payload. Check the rendered template of the configuration against the schema of its type."
+	}
+	return fmt.Sprintf("The API rejected the payload. Fix the template or parameters of the configuration to resolve these violations: %s", strings.Join(e.Violations, "; "))
+}
+
+// MissingScopeError is a RespError of a request rejected as the token lacks a required scope (HTTP 403).
+type MissingScopeError struct {
+	RespError
+	// RequiredScope is the scope the API reported as missing. Empty if it could not be detected.
+	RequiredScope string
+}
+
+func (e MissingScopeError) Unwrap() error {
+	return e.RespError
+}
+
+// Hint returns a remediation hint for the error
+func (e MissingScopeError) Hint() string {
+	if e.RequiredScope == "" {
+		return "Grant the scopes required by this type to the access token or OAuth client used for the environment."
+	}
+	return fmt.Sprintf("Grant the %q scope to the access token or OAuth client used for the environment.", e.RequiredScope)
+}
+
+// NotFoundError is a RespError of a request for an object or endpoint that does not exist (HTTP 404).
+type NotFoundError struct {
+	RespError
+}
+
+func (e NotFoundError) Unwrap() error {
+	return e.RespError
+}
+
+// Hint returns a remediation hint for the error
+func (e NotFoundError) Hint() string {
+	return "Verify the URL of the environment and that the API and the referenced object exist on it."
+}
+
+// ConflictError is a RespError of a request conflicting with the current state of an object (HTTP 409).
+type ConflictError struct {
+	RespError
+}
+
+func (e ConflictError) Unwrap() error {
+	return e.RespError
+}
+
+// Hint returns a remediation hint for the error
+func (e ConflictError) Hint() string {
+	return "The object was modified concurrently or conflicts with an existing one. Check for duplicate names or IDs and retry."
+}
+
+// Hint returns the remediation hint of the typed error wrapped by err, or an empty string if there is none.
+func Hint(err error) string {
+	var rateLimited RateLimitedError
+	var schemaValidation SchemaValidationError
+	var missingScope MissingScopeError
+	var notFound NotFoundError
+	var conflict ConflictError
+	switch {
+	case errors.As(err, &rateLimited):
+		return rateLimited.Hint()
+	case errors.As(err, &schemaValidation):
+		return schemaValidation.Hint()
+	case errors.As(err, &missingScope):
+		return missingScope.Hint()
+	case errors.As(err, &notFound):
+		return notFound.Hint()
+	case errors.As(err, &conflict):
+		return conflict.Hint()
+	}
+	return ""
+}
+
+// retryAfter returns the time to wait before retrying as stated by the Retry-After or X-RateLimit-Reset headers
+func retryAfter(headers map[string][]string, now time.Time) time.Duration {
+	h := http.Header(headers)
+	if v := h.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0)
+		}
+	}
+	if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if resetInMicroseconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return max(time.UnixMicro(resetInMicroseconds).Sub(now), 0)
+		}
+	}
+	return 0
+}
+
+type constraintViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type errorBody struct {
+	Error struct {
+		ConstraintViolations []constraintViolation `json:"constraintViolations"`
+	} `json:"error"`
+}
+
+// constraintViolations extracts the constraint violations of an error response of the Dynatrace API. Both single
+// error objects and lists of them, as returned by the Settings API, are supported.
+func constraintViolations(body string) []string {
+	var bodies []errorBody
+	var single errorBody
+	if err := json.Unmarshal([]byte(body), &single); err == nil {
+		bodies = append(bodies, single)
+	} else if err := json.Unmarshal([]byte(body), &bodies); err != nil {
+		return nil
+	}
+
+	var violations []string
+	for _, b := range bodies {
+		for _, v := range b.Error.ConstraintViolations {
+			if v.Path == "" {
+				violations = append(violations, v.Message)
+			} else {
+				violations = append(violations, fmt.Sprintf("%s: %s", v.Path, v.Message))
+			}
+		}
+	}
+	return violations
+}
+
+// scopePattern matches the scope named in responses of the Dynatrace API, e.g. "Token is missing required scope. Use
+// one of: ReadConfig (Read configuration)" or "missing scope: storage:buckets:read"
+var scopePattern = regexp.MustCompile(`(?i)missing (?:required )?scopes?\b[^:]*:\s*([\w.:-]+)`)
+
+// requiredScope returns the scope a 403 response body reports as missing, or an empty string if there is none
+func requiredScope(body string) string {
+	if m := scopePattern.FindStringSubmatch(body); m != nil {
+		return strings.TrimRight(m[1], ".:")
+	}
+	return ""
+}
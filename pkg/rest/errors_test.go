@@ -0,0 +1,129 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespError_As(t *testing.T) {
+	t.Run("rate limited", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", NewRespErr("failed", Response{StatusCode: http.StatusTooManyRequests, Headers: map[string][]string{"Retry-After": {"7"}}}))
+
+		var rateLimited RateLimitedError
+		assert.True(t, errors.As(err, &rateLimited))
+		assert.Equal(t, 7*time.Second, rateLimited.RetryAfter)
+		assert.Contains(t, rateLimited.Hint(), "MONACO_CONCURRENT_REQUESTS")
+
+		var notFound NotFoundError
+		assert.False(t, errors.As(err, &notFound))
+	})
+
+	t.Run("schema validation", func(t *testing.T) {
+		body := `{"error": {"code": 400, "message": "Constraints violated.", "constraintViolations": [{"path": "name", "message": "must not be null"}]}}`
+		err := NewRespErr("failed", Response{StatusCode: http.StatusBadRequest, Body: []byte(body)})
+
+		var schemaValidation SchemaValidationError
+		assert.True(t, errors.As(err, &schemaValidation))
+		assert.Equal(t, []string{"name: must not be null"}, schemaValidation.Violations)
+		assert.Equal(t, body, schemaValidation.Body)
+		assert.Contains(t, schemaValidation.Hint(), "name: must not be null")
+	})
+
+	t.Run("schema validation of settings", func(t *testing.T) {
+		body := `[{"code": 400, "error": {"constraintViolations": [{"path": "enabled", "message": "must be a boolean"}]}}]`
+		err := NewRespErr("failed", Response{StatusCode: http.StatusBadRequest, Body: []byte(body)})
+
+		var schemaValidation SchemaValidationError
+		assert.True(t, errors.As(err, &schemaValidation))
+		assert.Equal(t, []string{"enabled: must be a boolean"}, schemaValidation.Violations)
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		body := `{"error": {"code": 403, "message": "Token is missing required scope. Use one of: ReadConfig (Read configuration)"}}`
+		err := NewRespErr("failed", Response{StatusCode: http.StatusForbidden, Body: []byte(body)})
+
+		var missingScope MissingScopeError
+		assert.True(t, errors.As(err, &missingScope))
+		assert.Equal(t, "ReadConfig", missingScope.RequiredScope)
+		assert.Contains(t, missingScope.Hint(), `"ReadConfig"`)
+	})
+
+	t.Run("forbidden without scope is no missing scope", func(t *testing.T) {
+		err := NewRespErr("failed", Response{StatusCode: http.StatusForbidden, Body: []byte("too many requests")})
+
+		var missingScope MissingScopeError
+		assert.False(t, errors.As(err, &missingScope))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := NewRespErr("failed", Response{StatusCode: http.StatusNotFound})
+
+		var notFound NotFoundError
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		err := NewRespErr("failed", Response{StatusCode: http.StatusConflict})
+
+		var conflict ConflictError
+		assert.True(t, errors.As(err, &conflict))
+	})
+
+	t.Run("typed errors still are RespErrors", func(t *testing.T) {
+		var err error = ConflictError{RespError: NewRespErr("failed", Response{StatusCode: http.StatusConflict})}
+
+		var respErr RespError
+		assert.True(t, errors.As(err, &respErr))
+		assert.Equal(t, http.StatusConflict, respErr.StatusCode)
+	})
+}
+
+func TestHint(t *testing.T) {
+	assert.NotEmpty(t, Hint(NewRespErr("failed", Response{StatusCode: http.StatusNotFound})))
+	assert.Empty(t, Hint(NewRespErr("failed", Response{StatusCode: http.StatusInternalServerError})))
+	assert.Empty(t, Hint(errors.New("no response error")))
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		want    time.Duration
+	}{
+		{"no headers", nil, 0},
+		{"Retry-After seconds", map[string][]string{"Retry-After": {"3"}}, 3 * time.Second},
+		{"Retry-After date", map[string][]string{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute},
+		{"X-RateLimit-Reset", map[string][]string{"X-Ratelimit-Reset": {fmt.Sprint(now.Add(2 * time.Second).UnixMicro())}}, 2 * time.Second},
+		{"reset in the past", map[string][]string{"X-Ratelimit-Reset": {fmt.Sprint(now.Add(-time.Second).UnixMicro())}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryAfter(tt.headers, now))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"net/http"
+)
+
+// Middleware intercepts the requests sent by a Client. It can mutate requests before they are sent - e.g. to add custom
+// headers - and inspect the responses they resulted in - e.g. for tracing or audit logging.
+type Middleware interface {
+	// OnRequest is called before a request is sent. Returning an error aborts the request.
+	OnRequest(req *http.Request) error
+	// OnResponse is called once a request is done, with either the received response or the error the request failed with.
+	OnResponse(req *http.Request, resp Response, err error)
+}
+
+// RequestInterceptor is a Middleware only acting on requests before they are sent
+type RequestInterceptor func(req *http.Request) error
+
+func (f RequestInterceptor) OnRequest(req *http.Request) error {
+	return f(req)
+}
+
+func (f RequestInterceptor) OnResponse(*http.Request, Response, error) {}
+
+// ResponseInterceptor is a Middleware only inspecting the results of requests
+type ResponseInterceptor func(req *http.Request, resp Response, err error)
+
+func (f ResponseInterceptor) OnRequest(*http.Request) error {
+	return nil
+}
+
+func (f ResponseInterceptor) OnResponse(req *http.Request, resp Response, err error) {
+	f(req, resp, err)
+}
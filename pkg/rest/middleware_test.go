@@ -0,0 +1,104 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingMiddleware struct {
+	name  string
+	calls *[]string
+}
+
+func (m recordingMiddleware) OnRequest(req *http.Request) error {
+	*m.calls = append(*m.calls, m.name+" request")
+	return nil
+}
+
+func (m recordingMiddleware) OnResponse(*http.Request, Response, error) {
+	*m.calls = append(*m.calls, m.name+" response")
+}
+
+func TestClient_MiddlewaresMutateRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "trace-id", req.Header.Get("X-Trace"))
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	var status int
+	restClient := NewRestClient(server.Client(), nil, CreateRateLimitStrategy(),
+		RequestInterceptor(func(req *http.Request) error {
+			req.Header.Set("X-Trace", "trace-id")
+			return nil
+		}),
+		ResponseInterceptor(func(req *http.Request, resp Response, err error) {
+			assert.NoError(t, err)
+			status = resp.StatusCode
+		}),
+	)
+
+	_, err := restClient.Get(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTeapot, status)
+}
+
+func TestClient_MiddlewaresAreCalledInOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+
+	var calls []string
+	restClient := NewRestClient(server.Client(), nil, CreateRateLimitStrategy(),
+		recordingMiddleware{name: "first", calls: &calls},
+		recordingMiddleware{name: "second", calls: &calls},
+	)
+
+	_, err := restClient.Post(context.Background(), server.URL, []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first request", "second request", "second response", "first response"}, calls)
+}
+
+func TestClient_MiddlewareAbortsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Error("request should not have been sent")
+	}))
+	defer server.Close()
+
+	var calls []string
+	var responseErr error
+	abort := errors.New("not allowed")
+	restClient := NewRestClient(server.Client(), nil, CreateRateLimitStrategy(),
+		recordingMiddleware{name: "first", calls: &calls},
+		ResponseInterceptor(func(_ *http.Request, _ Response, err error) { responseErr = err }),
+		RequestInterceptor(func(*http.Request) error { return abort }),
+		recordingMiddleware{name: "last", calls: &calls},
+	)
+
+	_, err := restClient.Delete(context.Background(), server.URL)
+	assert.ErrorIs(t, err, abort)
+	assert.ErrorIs(t, responseErr, abort)
+	assert.Equal(t, []string{"first request", "first response"}, calls)
+}
@@ -17,6 +17,7 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -30,24 +31,26 @@ import (
 // RateLimitStrategy ensures that the concrete implementation of the rate limiting strategy can be hidden
 // behind this interface
 type RateLimitStrategy interface {
-	ExecuteRequest(timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error)
+	ExecuteRequest(ctx context.Context, timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error)
 }
 
 // CreateRateLimitStrategy creates a RateLimitStrategy. In the future this can be extended to instantiate
 // different rate limiting strategies based on e.g. environment variables. The current implementation
-// always returns the strategy simpleSleepRateLimitStrategy, which suspends the current goroutine until
-// the time in the rate limiting header 'X-RateLimit-Reset' is up.
+// always returns the strategy adaptiveRateLimitStrategy, which throttles requests once the rate limiting header
+// 'X-RateLimit-Remaining' reports no remaining requests, and suspends the current goroutine until the time in the
+// rate limiting header 'X-RateLimit-Reset' is up.
+// The returned strategy is safe for concurrent use and should be shared by all clients of an environment.
 func CreateRateLimitStrategy() RateLimitStrategy {
-	return &simpleSleepRateLimitStrategy{}
+	return &adaptiveRateLimitStrategy{}
 }
 
 // simpleSleepRateLimitStrategy, is a rate limiting strategy which suspends the current goroutine until
 // the time in the rate limiting header 'X-RateLimit-Reset' is up.
 // It has a min sleep duration of 5 seconds and a max sleep duration of one minute and performs maximal 5
-// polling iterations before giving up.
+// polling iterations before giving up. Waiting stops early if the context is done.
 type simpleSleepRateLimitStrategy struct{}
 
-func (s *simpleSleepRateLimitStrategy) ExecuteRequest(timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error) {
+func (s *simpleSleepRateLimitStrategy) ExecuteRequest(ctx context.Context, timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error) {
 
 	response, err := callback()
 	if err != nil {
@@ -73,7 +76,9 @@ func (s *simpleSleepRateLimitStrategy) ExecuteRequest(timelineProvider timeutils
 
 		log.Debug("Rate limit reached (iteration: %d/%d). Sleeping until %s (%s)", currentIteration+1, maxIterationCount, humanReadableTimestamp, sleepDuration)
 
-		timelineProvider.Sleep(sleepDuration)
+		if err := timelineProvider.Sleep(ctx, sleepDuration); err != nil {
+			return Response{}, fmt.Errorf("cancelled while waiting for the rate limit to be reset: %w", err)
+		}
 
 		// Checking again:
 		currentIteration++
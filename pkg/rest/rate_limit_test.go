@@ -19,9 +19,11 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/throttle"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"net/http"
@@ -117,9 +119,9 @@ func TestSimpleRateLimitStrategySleepsFor42Seconds(t *testing.T) {
 	}
 
 	timelineProvider.EXPECT().Now().Times(1).Return(time.Unix(0, 0)) // time travel to the 70s
-	timelineProvider.EXPECT().Sleep(42 * time.Second).Times(1)
+	timelineProvider.EXPECT().Sleep(gomock.Any(), 42*time.Second).Times(1).Return(nil)
 
-	response, err := rateLimitStrategy.ExecuteRequest(timelineProvider, callback)
+	response, err := rateLimitStrategy.ExecuteRequest(context.TODO(), timelineProvider, callback)
 
 	require.NoError(t, err)
 	require.Equal(t, 200, response.StatusCode)
@@ -144,11 +146,12 @@ func TestSimpleRateLimitStrategySleepsGeneratedTimeout_IfHeaderIsMissingLimit(t
 	}
 
 	timelineProvider.EXPECT().Now().Times(1).Return(time.Unix(0, 0)) // time travel to the 70s
-	timelineProvider.EXPECT().Sleep(gomock.Any()).Times(1).Do(func(duration time.Duration) {
+	timelineProvider.EXPECT().Sleep(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, duration time.Duration) error {
 		require.GreaterOrEqual(t, duration, throttle.MinWaitDuration)
+		return nil
 	})
 
-	response, err := rateLimitStrategy.ExecuteRequest(timelineProvider, callback)
+	response, err := rateLimitStrategy.ExecuteRequest(context.TODO(), timelineProvider, callback)
 
 	require.NoError(t, err)
 	require.Equal(t, 200, response.StatusCode)
@@ -176,9 +179,9 @@ func TestSimpleRateLimitStrategy2Iterations(t *testing.T) {
 	}
 
 	timelineProvider.EXPECT().Now().Times(2).Return(time.Unix(0, 0)) // time travel to the 70s
-	timelineProvider.EXPECT().Sleep(42 * time.Second).Times(2)
+	timelineProvider.EXPECT().Sleep(gomock.Any(), 42*time.Second).Times(2).Return(nil)
 
-	response, err := rateLimitStrategy.ExecuteRequest(timelineProvider, callback)
+	response, err := rateLimitStrategy.ExecuteRequest(context.TODO(), timelineProvider, callback)
 
 	require.NoError(t, err)
 	require.Equal(t, 200, response.StatusCode)
@@ -192,6 +195,24 @@ func TestHandleEmptyResponse(t *testing.T) {
 		return Response{}, errors.New("foo Error")
 	}
 
-	_, err := rateLimitStrategy.ExecuteRequest(timelineProvider, callback)
+	_, err := rateLimitStrategy.ExecuteRequest(context.TODO(), timelineProvider, callback)
 	require.ErrorContains(t, err, "foo Error")
 }
+
+func TestSimpleRateLimitStrategy_StopsWaitingWhenContextIsDone(t *testing.T) {
+	rateLimitStrategy := simpleSleepRateLimitStrategy{}
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	calls := 0
+	callback := func() (Response, error) {
+		calls++
+		return Response{StatusCode: 429, Headers: createTestHeaders(time.Hour.Microseconds())}, nil
+	}
+
+	start := time.Now()
+	_, err := rateLimitStrategy.ExecuteRequest(ctx, timeutils.NewTimelineProvider(), callback)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls, "the request must not be retried once the context is done")
+	assert.Less(t, time.Since(start), time.Minute)
+}
@@ -19,14 +19,88 @@ package rest
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
 	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
 )
 
 type RetrySetting struct {
 	WaitTime   time.Duration
 	MaxRetries int
+
+	// BackoffFactor multiplies the WaitTime after every retry. Values of 1 or less result in a constant WaitTime.
+	BackoffFactor float64
+	// MaxWaitTime caps the WaitTime growing due to the BackoffFactor. Zero means no limit.
+	MaxWaitTime time.Duration
+	// RetriableStatusCodes restricts retries to responses with one of these status codes. Requests failing without a
+	// response are always retried. If empty, every unsuccessful response is retried.
+	RetriableStatusCodes []int
+}
+
+// waitTimeBeforeRetry returns the time to wait before the given retry, starting at 0
+func (s RetrySetting) waitTimeBeforeRetry(retry int) time.Duration {
+	if s.BackoffFactor <= 1 {
+		return s.WaitTime
+	}
+
+	wait := time.Duration(float64(s.WaitTime) * math.Pow(s.BackoffFactor, float64(retry)))
+	if s.MaxWaitTime > 0 && (wait > s.MaxWaitTime || wait < 0) {
+		return s.MaxWaitTime
+	}
+	return wait
+}
+
+// isRetriable returns whether a request resulting in the given response and error should be retried
+func (s RetrySetting) isRetriable(resp Response, err error) bool {
+	if err != nil || len(s.RetriableStatusCodes) == 0 {
+		return true
+	}
+	return slices.Contains(s.RetriableStatusCodes, resp.StatusCode)
+}
+
+// RetryPolicy overrides the default retry behavior of a client. Fields left at their zero value keep the default of
+// the respective RetrySetting.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries of a single request using the Normal RetrySetting. Requests using
+	// the Long and VeryLong settings are retried proportionally more often, as they are expected to take longer.
+	// Zero disables retries, nil keeps the defaults.
+	MaxRetries *int
+	// WaitTime is the time to wait before the first retry
+	WaitTime time.Duration
+	// BackoffFactor multiplies the wait time after every retry
+	BackoffFactor float64
+	// MaxWaitTime caps the wait time between two retries
+	MaxWaitTime time.Duration
+	// RetriableStatusCodes are the HTTP status codes to retry requests for
+	RetriableStatusCodes []int
+}
+
+// apply returns a copy of s with all fields set in p overridden. The MaxRetries of s are scaled by the ratio between
+// the MaxRetries of p and the given normalRetries, so that settings retrying longer than the Normal one keep doing so.
+func (p RetryPolicy) apply(s RetrySetting, normalRetries int) RetrySetting {
+	if p.MaxRetries != nil {
+		if normalRetries > 0 {
+			s.MaxRetries = *p.MaxRetries * s.MaxRetries / normalRetries
+		} else {
+			s.MaxRetries = *p.MaxRetries
+		}
+	}
+	if p.WaitTime > 0 {
+		s.WaitTime = p.WaitTime
+	}
+	if p.BackoffFactor > 0 {
+		s.BackoffFactor = p.BackoffFactor
+	}
+	if p.MaxWaitTime > 0 {
+		s.MaxWaitTime = p.MaxWaitTime
+	}
+	if len(p.RetriableStatusCodes) > 0 {
+		s.RetriableStatusCodes = slices.Clone(p.RetriableStatusCodes)
+	}
+	return s
 }
 
 type RetrySettings struct {
@@ -35,6 +109,16 @@ type RetrySettings struct {
 	VeryLong RetrySetting
 }
 
+// WithPolicy returns a copy of the RetrySettings with the given RetryPolicy applied to all of them. The MaxRetries of
+// the policy apply to the Normal setting, the Long and VeryLong settings keep their ratio to it.
+func (s RetrySettings) WithPolicy(p RetryPolicy) RetrySettings {
+	return RetrySettings{
+		Normal:   p.apply(s.Normal, s.Normal.MaxRetries),
+		Long:     p.apply(s.Long, s.Normal.MaxRetries),
+		VeryLong: p.apply(s.VeryLong, s.Normal.MaxRetries),
+	}
+}
+
 var DefaultRetrySettings = RetrySettings{
 	Normal: RetrySetting{
 		WaitTime:   time.Second,
@@ -50,16 +134,23 @@ var DefaultRetrySettings = RetrySettings{
 	},
 }
 
-// SendWithRetry will retry to call sendWithBody for a given number of times, waiting a give duration between calls
+// SendWithRetry will retry to call sendWithBody for a given number of times, waiting a give duration between calls.
+// Retrying stops early if a response is received that is not retriable according to the setting.
 func SendWithRetry(ctx context.Context, sendWithBody SendRequestWithBody, path string, body []byte, setting RetrySetting) (resp Response, err error) {
 
 	for i := 0; i < setting.MaxRetries; i++ {
-		log.WithCtxFields(ctx).Warn("Failed to send HTTP request. Waiting for %s before retrying...", setting.WaitTime)
-		time.Sleep(setting.WaitTime)
+		wait := setting.waitTimeBeforeRetry(i)
+		log.WithCtxFields(ctx).Warn("Failed to send HTTP request. Waiting for %s before retrying...", wait)
+		if sleepErr := timeutils.SleepWithContext(ctx, wait); sleepErr != nil {
+			return Response{}, fmt.Errorf("HTTP send request %s cancelled while waiting to retry: %w", path, sleepErr)
+		}
 		resp, err = sendWithBody(ctx, path, body)
 		if err == nil && resp.IsSuccess() {
 			return resp, err
 		}
+		if !setting.isRetriable(resp, err) {
+			return Response{}, NewRespErr(fmt.Sprintf("HTTP send request %s failed with non-retriable response (HTTP %d)", path, resp.StatusCode), resp)
+		}
 	}
 
 	if err != nil {
@@ -74,6 +165,9 @@ func SendWithRetryWithInitialTry(ctx context.Context, sendWithBody SendRequestWi
 	if err == nil && resp.IsSuccess() {
 		return resp, err
 	}
+	if !setting.isRetriable(resp, err) {
+		return Response{}, NewRespErr(fmt.Sprintf("HTTP send request %s failed with non-retriable response (HTTP %d)", path, resp.StatusCode), resp)
+	}
 
 	return SendWithRetry(ctx, sendWithBody, path, body, setting)
 }
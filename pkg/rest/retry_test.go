@@ -0,0 +1,136 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrySetting_waitTimeBeforeRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		setting  RetrySetting
+		expected []time.Duration
+	}{
+		{
+			name:     "constant wait time without backoff",
+			setting:  RetrySetting{WaitTime: time.Second},
+			expected: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:     "exponential backoff",
+			setting:  RetrySetting{WaitTime: time.Second, BackoffFactor: 2},
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:     "exponential backoff is capped",
+			setting:  RetrySetting{WaitTime: time.Second, BackoffFactor: 3, MaxWaitTime: 5 * time.Second},
+			expected: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.expected {
+				assert.Equal(t, want, tt.setting.waitTimeBeforeRetry(i))
+			}
+		})
+	}
+}
+
+func TestRetrySettings_WithPolicy(t *testing.T) {
+	maxRetries := 3
+	got := DefaultRetrySettings.WithPolicy(RetryPolicy{MaxRetries: &maxRetries, BackoffFactor: 1.5, RetriableStatusCodes: []int{http.StatusServiceUnavailable}})
+
+	assert.Equal(t, 3, got.Normal.MaxRetries)
+	assert.Equal(t, 6, got.Long.MaxRetries, "long settings keep their ratio to the normal setting")
+	assert.Equal(t, 12, got.VeryLong.MaxRetries, "very long settings keep their ratio to the normal setting")
+	for _, s := range []RetrySetting{got.Normal, got.Long, got.VeryLong} {
+		assert.Equal(t, DefaultRetrySettings.Normal.WaitTime, s.WaitTime, "unset fields keep their default")
+		assert.Equal(t, 1.5, s.BackoffFactor)
+		assert.Equal(t, []int{http.StatusServiceUnavailable}, s.RetriableStatusCodes)
+	}
+
+	t.Run("zero max retries disables retries", func(t *testing.T) {
+		noRetries := 0
+		got := DefaultRetrySettings.WithPolicy(RetryPolicy{MaxRetries: &noRetries})
+		for _, s := range []RetrySetting{got.Normal, got.Long, got.VeryLong} {
+			assert.Zero(t, s.MaxRetries)
+		}
+	})
+
+	t.Run("unset max retries keep the defaults", func(t *testing.T) {
+		assert.Equal(t, DefaultRetrySettings, DefaultRetrySettings.WithPolicy(RetryPolicy{}))
+	})
+}
+
+func TestSendWithRetry_StopsOnNonRetriableStatusCode(t *testing.T) {
+	calls := 0
+	send := func(ctx context.Context, url string, data []byte) (Response, error) {
+		calls++
+		if calls == 1 {
+			return Response{StatusCode: http.StatusServiceUnavailable}, nil
+		}
+		return Response{StatusCode: http.StatusBadRequest}, nil
+	}
+
+	_, err := SendWithRetryWithInitialTry(context.TODO(), send, "/path", nil, RetrySetting{MaxRetries: 5, RetriableStatusCodes: []int{http.StatusServiceUnavailable}})
+
+	var respErr RespError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusBadRequest, respErr.StatusCode)
+	assert.Equal(t, 2, calls)
+}
+
+func TestSendWithRetryWithInitialTry_DoesNotRetryNonRetriableStatusCode(t *testing.T) {
+	calls := 0
+	send := func(ctx context.Context, url string, data []byte) (Response, error) {
+		calls++
+		return Response{StatusCode: http.StatusNotFound}, nil
+	}
+
+	_, err := SendWithRetryWithInitialTry(context.TODO(), send, "/path", nil, RetrySetting{MaxRetries: 5, RetriableStatusCodes: []int{http.StatusServiceUnavailable}})
+
+	var respErr RespError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSendWithRetry_StopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	calls := 0
+	send := func(ctx context.Context, url string, data []byte) (Response, error) {
+		calls++
+		return Response{StatusCode: http.StatusServiceUnavailable}, nil
+	}
+
+	start := time.Now()
+	_, err := SendWithRetry(ctx, send, "url", nil, RetrySetting{WaitTime: time.Minute, MaxRetries: 5})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, calls, "no request is retried once the context is cancelled")
+	assert.Less(t, time.Since(start), time.Minute)
+}
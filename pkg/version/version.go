@@ -20,7 +20,7 @@ import "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 
 var MonitoringAsCode = "2.x"
 
-const ManifestVersion = "1.0"
+const ManifestVersion = "1.1"
 const MinManifestVersion = "1.0"
 
 func LogVersionAsInfo() {
@@ -33,6 +33,8 @@ type WriterContext struct {
 	OutputDir          string
 	ManifestName       string
 	ParametersSerde    map[string]parameter.ParameterSerDe
+	// Layout decides the folders configs are written to within their project. If nil, the flat layout is used.
+	Layout configwriter.Layout
 }
 
 func WriteToDisk(context *WriterContext, manifestToWrite manifest.Manifest, projects []project.Project) []error {
@@ -81,6 +83,7 @@ func writeProjects(context *WriterContext, projectDefinitions manifest.ProjectDe
 			OutputFolder:    context.OutputDir,
 			ProjectFolder:   definition.Path,
 			ParametersSerde: context.ParametersSerde,
+			Layout:          context.Layout,
 		}, configs)
 
 		errors = append(errors, errs...)